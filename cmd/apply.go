@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/gitops"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f FILE",
+	Short: "Reconcile Coolify with a declarative spec",
+	Long: `Read a spec of projects, environments, applications, databases, env vars
+and domains, and make the Coolify instance match it: missing resources are
+created and drifted settings updated. With --prune, applications, databases
+and env vars missing from the spec are deleted from the environments it
+declares. The plan is shown and confirmed before anything changes.
+
+Settings left out of the spec are not managed. Env values can reference
+local environment variables as ${NAME}, so secrets stay out of the file;
+write $${NAME} for a literal ${NAME}. 'export' writes a spec from live
+configuration.
+Values of shown-once variables can't be read back and are only set when
+missing.
+
+Example spec:
+  version: 1
+  projects:
+    - name: shop
+      environments:
+        - name: production
+          applications:
+            - name: web
+              repository: acme/shop
+              github_app: acme
+              branch: main
+              port: "3000"
+              domains: [https://shop.example.com]
+              env:
+                STRIPE_KEY: ${STRIPE_KEY}
+          databases:
+            - name: db
+              engine: postgresql
+
+Available Commands:
+  apply diff  - Show the plan without applying it
+
+Examples:
+  cool-kit apply diff -f coolify.yaml
+  cool-kit apply -f coolify.yaml
+  cool-kit apply -f coolify.yaml --prune --deploy -y`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+var applyDiffCmd = &cobra.Command{
+	Use:   "diff -f FILE",
+	Short: "Show what apply would change",
+	Args:  cobra.NoArgs,
+	RunE:  runApplyDiff,
+}
+
+func init() {
+	applyCmd.PersistentFlags().StringP("file", "f", "", "Spec file (- for stdin)")
+	applyCmd.PersistentFlags().Bool("prune", false, "Delete resources and env vars missing from the spec")
+	_ = applyCmd.MarkPersistentFlagRequired("file")
+
+	applyCmd.Flags().Bool("deploy", false, "Deploy the applications that were created or updated")
+
+	applyCmd.AddCommand(applyDiffCmd)
+}
+
+// loadApplyPlan reads the spec and plans it against the live state
+func loadApplyPlan(cmd *cobra.Command) (*api.Client, *gitops.State, []gitops.Change, error) {
+	file, _ := cmd.Flags().GetString("file")
+	prune, _ := cmd.Flags().GetBool("prune")
+
+	spec, err := gitops.Load(file)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := checkLogin(); err != nil {
+		return nil, nil, nil, err
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	var state *gitops.State
+	var changes []gitops.Change
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-state",
+			ActiveName:   "Loading live resources...",
+			CompleteName: "✓ Loaded live resources",
+			Action: func() error {
+				var err error
+				state, err = gitops.FetchState(client)
+				return err
+			},
+		},
+		{
+			Name:         "plan",
+			ActiveName:   "Comparing with the spec...",
+			CompleteName: "✓ Compared with the spec",
+			Action: func() error {
+				var err error
+				changes, err = gitops.Plan(spec, state, gitops.PlanOptions{Prune: prune})
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to plan changes")
+		return nil, nil, nil, err
+	}
+	return client, state, changes, nil
+}
+
+func runApplyDiff(cmd *cobra.Command, args []string) error {
+	ui.Section("Plan")
+
+	_, _, changes, err := loadApplyPlan(cmd)
+	if err != nil {
+		return err
+	}
+	printPlan(changes)
+	return nil
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	yes, _ := cmd.Flags().GetBool("yes")
+	deploy, _ := cmd.Flags().GetBool("deploy")
+
+	ui.Section("Apply")
+
+	client, state, changes, err := loadApplyPlan(cmd)
+	if err != nil {
+		return err
+	}
+	printPlan(changes)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if !yes {
+		ui.Spacer()
+		confirmed, err := ui.Confirm(fmt.Sprintf("Apply %d change(s)?", len(changes)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Dim("Cancelled")
+			return nil
+		}
+	}
+
+	ui.Spacer()
+	result, err := gitops.Apply(client, state, changes, func(c gitops.Change) {
+		fmt.Printf("  %s %s %s\n", changeSymbol(c.Action), c.Kind, c.Path)
+	})
+	if err != nil {
+		ui.Error("Apply stopped")
+		return err
+	}
+	ui.Spacer()
+	ui.Success(fmt.Sprintf("Applied %d change(s)", len(changes)))
+
+	if len(result.Applications) == 0 {
+		return nil
+	}
+	if !deploy {
+		ui.NextSteps([]string{
+			fmt.Sprintf("Redeploy the %d changed application(s) for the changes to take effect, or rerun with --deploy", len(result.Applications)),
+		})
+		return nil
+	}
+
+	for _, uuid := range result.Applications {
+		if err := client.UnpinCommit(uuid); err != nil {
+			return err
+		}
+		if _, err := client.Deploy(uuid, false, 0); err != nil {
+			return fmt.Errorf("failed to trigger deployment of %s: %w", uuid, err)
+		}
+	}
+	ui.Success(fmt.Sprintf("Triggered %d deployment(s)", len(result.Applications)))
+	return nil
+}
+
+// printPlan lists the changes of a plan with their field differences
+func printPlan(changes []gitops.Change) {
+	ui.Spacer()
+	if len(changes) == 0 {
+		ui.Success("Everything matches the spec")
+		return
+	}
+
+	counts := map[gitops.Action]int{}
+	for _, c := range changes {
+		counts[c.Action]++
+		line := fmt.Sprintf("%s %s %s", changeSymbol(c.Action), c.Kind, c.Path)
+		switch c.Action {
+		case gitops.ActionCreate:
+			fmt.Println(ui.SuccessStyle.Render(line))
+		case gitops.ActionUpdate:
+			fmt.Println(ui.WarningStyle.Render(line))
+		default:
+			fmt.Println(ui.ErrorStyle.Render(line))
+		}
+		for _, f := range c.Fields {
+			switch {
+			case f.Old == "":
+				fmt.Printf("    %s: %s\n", f.Field, f.New)
+			case f.New == "":
+				fmt.Printf("    %s: %s\n", f.Field, ui.DimStyle.Render("removed"))
+			default:
+				fmt.Printf("    %s: %s → %s\n", f.Field, f.Old, f.New)
+			}
+		}
+	}
+
+	ui.Spacer()
+	ui.Dim(fmt.Sprintf("%d to create, %d to update, %d to delete",
+		counts[gitops.ActionCreate], counts[gitops.ActionUpdate], counts[gitops.ActionDelete]))
+}
+
+func changeSymbol(action gitops.Action) string {
+	switch action {
+	case gitops.ActionCreate:
+		return "+"
+	case gitops.ActionUpdate:
+		return "~"
+	default:
+		return "-"
+	}
+}
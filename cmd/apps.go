@@ -0,0 +1,393 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+var appsCmd = &cobra.Command{
+	Use:     "apps",
+	Aliases: []string{"app", "applications"},
+	Short:   "List applications and run operations on many at once",
+	Long: `List applications, or restart, redeploy or stop several concurrently.
+
+Select applications with --all, --project or --uuid. Operations run with
+bounded parallelism and finish with a summary of successes and failures.
+
+Available Commands:
+  apps ls        - List applications
+  apps restart   - Restart applications
+  apps redeploy  - Trigger a new deployment of applications
+  apps stop      - Stop applications
+
+Examples:
+  cool-kit apps ls --project web -o json
+  cool-kit apps restart --project web
+  cool-kit apps redeploy --uuid abc123,def456 --force
+  cool-kit apps stop --all --parallel 8 --yes`,
+}
+
+var appsLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List applications",
+	Args:    cobra.NoArgs,
+	RunE:    runAppsLs,
+}
+
+var appsRestartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart applications",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAppsBulk(cmd, bulkAppOperation{
+			title:    "Restart",
+			verb:     "restart",
+			doneVerb: "restarted",
+			run: func(ctx context.Context, client *api.Client, uuid string) error {
+				_, err := client.RestartApplication(ctx, uuid)
+				return err
+			},
+		})
+	},
+}
+
+var appsRedeployCmd = &cobra.Command{
+	Use:   "redeploy",
+	Short: "Trigger a new deployment of applications",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		return runAppsBulk(cmd, bulkAppOperation{
+			title:    "Redeploy",
+			verb:     "redeploy",
+			doneVerb: "queued",
+			run: func(ctx context.Context, client *api.Client, uuid string) error {
+				if err := client.UnpinCommit(uuid); err != nil {
+					return err
+				}
+				_, err := client.Deploy(uuid, force, 0)
+				return err
+			},
+		})
+	},
+}
+
+var appsStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop applications",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAppsBulk(cmd, bulkAppOperation{
+			title:    "Stop",
+			verb:     "stop",
+			doneVerb: "stopped",
+			run: func(ctx context.Context, client *api.Client, uuid string) error {
+				_, err := client.StopApplication(ctx, uuid)
+				return err
+			},
+		})
+	},
+}
+
+func init() {
+	appsCmd.AddCommand(appsLsCmd)
+	appsCmd.AddCommand(appsRestartCmd)
+	appsCmd.AddCommand(appsRedeployCmd)
+	appsCmd.AddCommand(appsStopCmd)
+
+	for _, c := range []*cobra.Command{appsRestartCmd, appsRedeployCmd, appsStopCmd} {
+		c.Flags().Bool("all", false, "Select every application")
+		c.Flags().String("project", "", "Select applications in this project (name or UUID)")
+		c.Flags().StringSlice("uuid", nil, "Select applications by UUID (comma-separated)")
+		c.Flags().IntP("parallel", "p", 4, "Maximum number of concurrent operations")
+		c.MarkFlagsMutuallyExclusive("all", "project", "uuid")
+	}
+	appsRedeployCmd.Flags().Bool("force", false, "Force rebuild without cache")
+	appsLsCmd.Flags().String("project", "", "Only list applications in this project (name or UUID)")
+	addTableFlags(appsLsCmd)
+}
+
+func runAppsLs(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetString("project")
+	format, err := structuredFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	if format == "" {
+		ui.Section("Applications")
+	}
+
+	var apps []api.Application
+	err = loadTask(format, ui.Task{
+		Name:         "load-applications",
+		ActiveName:   "Loading applications...",
+		CompleteName: "✓ Loaded applications",
+		Action: func() error {
+			var err error
+			apps, err = selectApplications(client, project == "", project, nil)
+			return err
+		},
+	})
+	if err != nil {
+		if format == "" {
+			ui.Error("Failed to load applications")
+		}
+		return fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	if format != "" {
+		records := make([]appRecord, 0, len(apps))
+		for i := range apps {
+			records = append(records, newAppRecord(&apps[i]))
+		}
+		return formatOutput(format, records)
+	}
+
+	if len(apps) == 0 {
+		ui.Dim("No applications found")
+		return nil
+	}
+
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "Name"},
+		{Name: "Status", Status: true},
+		{Name: "URL"},
+		{Name: "Repository", Wide: true},
+		{Name: "UUID"},
+	}}
+	for _, app := range apps {
+		table.Rows = append(table.Rows, []string{
+			app.Name,
+			orDash(app.Status),
+			orDash(app.PrimaryURL()),
+			orDash(app.GitRepository),
+			app.UUID,
+		})
+	}
+	ui.Spacer()
+	return printTable(cmd, table)
+}
+
+// bulkAppOperation describes an action applied to each selected application
+type bulkAppOperation struct {
+	title    string
+	verb     string
+	doneVerb string
+	run      func(ctx context.Context, client *api.Client, uuid string) error
+}
+
+// bulkAppResult is the outcome of a bulk operation on one application
+type bulkAppResult struct {
+	app api.Application
+	err error
+}
+
+func runAppsBulk(cmd *cobra.Command, op bulkAppOperation) error {
+	all, _ := cmd.Flags().GetBool("all")
+	project, _ := cmd.Flags().GetString("project")
+	uuids, _ := cmd.Flags().GetStringSlice("uuid")
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	if !all && project == "" && len(uuids) == 0 {
+		return fmt.Errorf("select applications with --all, --project or --uuid")
+	}
+	if parallel < 1 {
+		return fmt.Errorf("invalid --parallel value: %d", parallel)
+	}
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	var apps []api.Application
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-applications",
+			ActiveName:   "Loading applications...",
+			CompleteName: "✓ Loaded applications",
+			Action: func() error {
+				var err error
+				apps, err = selectApplications(client, all, project, uuids)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load applications")
+		return fmt.Errorf("failed to select applications: %w", err)
+	}
+
+	if len(apps) == 0 {
+		ui.Dim("No matching applications")
+		return nil
+	}
+
+	if !skipConfirm {
+		confirmed, err := ui.ConfirmAction(op.verb, fmt.Sprintf("%d application(s)", len(apps)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Dim("Cancelled")
+			return nil
+		}
+	}
+
+	ui.Section(fmt.Sprintf("%s: %d application(s)", op.title, len(apps)))
+
+	results := runBulkOperation(client, apps, parallel, op)
+
+	rows := make([][]string, 0, len(results))
+	failed := 0
+	for _, r := range results {
+		result, detail := "✓ "+op.doneVerb, ""
+		if r.err != nil {
+			failed++
+			result, detail = "✗ failed", r.err.Error()
+		}
+		rows = append(rows, []string{r.app.Name, shortUUID(r.app.UUID), result, detail})
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Application", "UUID", "Result", "Error"}, rows)
+	ui.Spacer()
+
+	if failed > 0 {
+		ui.Error(fmt.Sprintf("%d of %d application(s) failed to %s", failed, len(results), op.verb))
+		return fmt.Errorf("%d application(s) failed to %s", failed, op.verb)
+	}
+
+	ui.Success(fmt.Sprintf("All %d application(s) %s", len(results), op.doneVerb))
+	return nil
+}
+
+// runBulkOperation applies op to apps with at most parallel operations in flight,
+// printing progress as each one finishes. Results keep the order of apps.
+func runBulkOperation(client *api.Client, apps []api.Application, parallel int, op bulkAppOperation) []bulkAppResult {
+	results := make([]bulkAppResult, len(apps))
+
+	var (
+		mu       sync.Mutex
+		finished int
+	)
+
+	// Failures are recorded per application instead of cancelling the rest
+	g := new(errgroup.Group)
+	g.SetLimit(parallel)
+	for i, app := range apps {
+		g.Go(func() error {
+			err := op.run(context.Background(), client, app.UUID)
+			results[i] = bulkAppResult{app: app, err: err}
+
+			mu.Lock()
+			defer mu.Unlock()
+			finished++
+			if err != nil {
+				ui.StepProgress(finished, len(apps), ui.ErrorStyle.Render(fmt.Sprintf("✗ %s: %v", app.Name, err)))
+			} else {
+				ui.StepProgress(finished, len(apps), fmt.Sprintf("✓ %s %s", app.Name, op.doneVerb))
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results
+}
+
+// selectApplications resolves the --all, --project and --uuid selectors to applications
+func selectApplications(client *api.Client, all bool, project string, uuids []string) ([]api.Application, error) {
+	apps, err := client.ListApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case all:
+		return apps, nil
+
+	case project != "":
+		environmentIDs, err := projectEnvironmentIDs(client, project)
+		if err != nil {
+			return nil, err
+		}
+
+		var selected []api.Application
+		for _, app := range apps {
+			if environmentIDs[app.EnvironmentID] {
+				selected = append(selected, app)
+			}
+		}
+		return selected, nil
+
+	default:
+		byUUID := make(map[string]api.Application, len(apps))
+		for _, app := range apps {
+			byUUID[app.UUID] = app
+		}
+
+		selected := make([]api.Application, 0, len(uuids))
+		seen := make(map[string]bool, len(uuids))
+		for _, uuid := range uuids {
+			uuid = strings.TrimSpace(uuid)
+			if uuid == "" || seen[uuid] {
+				continue
+			}
+			seen[uuid] = true
+
+			app, ok := byUUID[uuid]
+			if !ok {
+				return nil, fmt.Errorf("application not found: %s", uuid)
+			}
+			selected = append(selected, app)
+		}
+		return selected, nil
+	}
+}
+
+// projectEnvironmentIDs returns the IDs of all environments in a project given by name or UUID
+func projectEnvironmentIDs(client *api.Client, nameOrUUID string) (map[int]bool, error) {
+	projects, err := client.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	uuid := ""
+	for _, p := range projects {
+		if p.UUID == nameOrUUID || strings.EqualFold(p.Name, nameOrUUID) {
+			uuid = p.UUID
+			break
+		}
+	}
+	if uuid == "" {
+		return nil, fmt.Errorf("project not found: %s", nameOrUUID)
+	}
+
+	// The project list omits environments, so fetch the project itself
+	project, err := client.GetProject(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int]bool, len(project.Environments))
+	for _, env := range project.Environments {
+		ids[env.ID] = true
+	}
+	return ids, nil
+}
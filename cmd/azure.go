@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"os"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/providers/azure"
 	"github.com/spf13/cobra"
 )
@@ -27,9 +30,18 @@ This command will:
 - Setup initial admin credentials
 - Provide access URLs
 
-The deployment process is interactive and will guide you through all steps.`,
+The deployment process is interactive and will guide you through all steps.
+In CI, pass --resource-group, --vm-name and --admin-email (or rely on the
+azure config), set the admin password in COOLKIT_ADMIN_PASSWORD, and confirm
+with --yes.`,
+	Example: `  cool-kit azure deploy
+  COOLKIT_ADMIN_PASSWORD=... cool-kit azure deploy --non-interactive --yes --resource-group coolify-rg`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return azure.Deploy()
+		opts := azure.DeployOptions{AdminPassword: os.Getenv(config.EnvAdminPassword)}
+		opts.ResourceGroup, _ = cmd.Flags().GetString("resource-group")
+		opts.VMName, _ = cmd.Flags().GetString("vm-name")
+		opts.AdminEmail, _ = cmd.Flags().GetString("admin-email")
+		return azure.Deploy(opts)
 	},
 }
 
@@ -135,6 +147,10 @@ func init() {
 	azureCmd.AddCommand(azureConfigCmd)
 	azureCmd.AddCommand(azureSSHCmd)
 
+	azureDeployCmd.Flags().String("resource-group", "", "Resource group to create (default from the azure config)")
+	azureDeployCmd.Flags().String("vm-name", "", "Name of the VM (default from the azure config)")
+	azureDeployCmd.Flags().String("admin-email", "", "Email of the Coolify admin (default from the azure config)")
+
 	// Add azure command to root
 	rootCmd.AddCommand(azureCmd)
 }
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/entro314-labs/cool-kit/internal/ci"
+	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -12,44 +15,141 @@ import (
 var ciCmd = &cobra.Command{
 	Use:   "ci",
 	Short: "Generate CI/CD workflow files",
-	Long:  `Generate CI/CD workflow files for various platforms.`,
-}
+	Long: `Generate a CI pipeline that deploys to Coolify on every push.
 
-var ciGithubCmd = &cobra.Command{
-	Use:   "github",
-	Short: "Generate GitHub Actions workflow for Coolify deployment",
-	Long: `Generate a GitHub Actions workflow file that deploys to Coolify.
+Pipelines are generated for GitHub Actions, GitLab CI, Woodpecker CI and
+Forgejo Actions from the project config: git projects have Coolify deploy
+the pushed branch, docker projects build the image with buildx and a build
+cache, push it and deploy that tag. The application UUID and the Coolify URL
+are filled in from the project and its instance when known, and read from
+the CI's secrets otherwise. --secret sets application variables from CI
+secrets before each deployment.
 
-The workflow triggers on push to main branch and deploys your application
-using the Coolify API.
+Run without a platform to choose one. 'cool-kit ci deploy' is the command
+for a pipeline's job itself: it deploys and waits for the result.
 
 Examples:
+  cool-kit ci
   cool-kit ci github
   cool-kit ci github --app-uuid=abc123
-  cool-kit ci github --branch=main`,
-	RunE: runCIGithub,
+  cool-kit ci gitlab --branch=main --secret DATABASE_URL`,
+	RunE: runCI,
 }
 
 var (
 	ciAppUUID string
 	ciBranch  string
+	ciURL     string
+	ciSecrets []string
 )
 
 func init() {
-	ciGithubCmd.Flags().StringVar(&ciAppUUID, "app-uuid", "", "Application UUID (or use COOLIFY_APP_UUID secret)")
-	ciGithubCmd.Flags().StringVar(&ciBranch, "branch", "main", "Branch to trigger deployment on")
-
-	ciCmd.AddCommand(ciGithubCmd)
+	ciCmd.PersistentFlags().StringVar(&ciAppUUID, "app-uuid", "", "Application UUID (default from the project config, or the COOLIFY_APP_UUID secret)")
+	ciCmd.PersistentFlags().StringVar(&ciBranch, "branch", "", "Branch to trigger deployment on (default the project's branch, or main)")
+	ciCmd.PersistentFlags().StringVar(&ciURL, "coolify-url", "", "Coolify URL (default the project's instance, or the COOLIFY_URL secret)")
+	ciCmd.PersistentFlags().StringSliceVar(&ciSecrets, "secret", nil, "Application variable to set from the CI secret of the same name (repeatable)")
+
+	for _, platform := range ci.Platforms {
+		ciCmd.AddCommand(&cobra.Command{
+			Use:   platform.Name,
+			Short: fmt.Sprintf("Generate %s pipeline for Coolify deployment", platform.Title),
+			Long: fmt.Sprintf(`Generate %s, a %s pipeline that deploys to Coolify.
+
+See 'cool-kit ci --help' for what it does.`, platform.Path, platform.Title),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return generateCI(platform)
+			},
+		})
+	}
 	rootCmd.AddCommand(ciCmd)
 }
 
-func runCIGithub(cmd *cobra.Command, args []string) error {
-	workflowDir := ".github/workflows"
-	workflowFile := filepath.Join(workflowDir, "coolify-deploy.yml")
+// runCI asks which CI system to generate a pipeline for
+func runCI(cmd *cobra.Command, args []string) error {
+	titles := make([]string, len(ci.Platforms))
+	for i, p := range ci.Platforms {
+		titles[i] = p.Title
+	}
+	choice, err := ui.SelectDefault("CI system:", titles, detectCIPlatform().Title)
+	if err != nil {
+		return err
+	}
+	for _, p := range ci.Platforms {
+		if p.Title == choice {
+			return generateCI(p)
+		}
+	}
+	return nil
+}
+
+// detectCIPlatform returns the CI system the repository already uses,
+// GitHub Actions if none
+func detectCIPlatform() *ci.Platform {
+	for _, marker := range []struct{ path, platform string }{
+		{".gitlab-ci.yml", "gitlab"},
+		{".woodpecker", "woodpecker"},
+		{".woodpecker.yml", "woodpecker"},
+		{".forgejo", "forgejo"},
+	} {
+		if _, err := os.Stat(marker.path); err == nil {
+			return ci.Lookup(marker.platform)
+		}
+	}
+	return ci.Lookup("github")
+}
+
+// ciParams fills the pipeline's parameters from the flags and the project
+func ciParams() (ci.Params, error) {
+	params := ci.Params{
+		Branch:       ciBranch,
+		DeployMethod: config.DeployMethodGit,
+		AppUUID:      ciAppUUID,
+		CoolifyURL:   ciURL,
+		EnvSecrets:   ciSecrets,
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil && !os.IsNotExist(err) {
+		return params, fmt.Errorf("failed to load project configuration: %w", err)
+	}
+	if projectCfg != nil {
+		if projectCfg.DeployMethod == config.DeployMethodBuildpacks {
+			return params, fmt.Errorf("CI pipelines for buildpacks projects aren't supported yet")
+		}
+		params.DeployMethod = projectCfg.DeployMethod
+		params.Image = projectCfg.DockerImage
+		params.Platform = projectCfg.Platform
+		if params.AppUUID == "" {
+			params.AppUUID = projectCfg.AppUUID
+		}
+		if params.Branch == "" {
+			params.Branch = projectCfg.Branch
+		}
+		if params.CoolifyURL == "" {
+			if globalCfg, err := config.LoadGlobal(); err == nil {
+				params.CoolifyURL = strings.TrimSuffix(globalCfg.CoolifyURL, "/")
+			}
+		}
+	}
+	if params.Branch == "" {
+		params.Branch = "main"
+	}
+	return params, nil
+}
+
+func generateCI(platform *ci.Platform) error {
+	params, err := ciParams()
+	if err != nil {
+		return err
+	}
+	pipeline, err := platform.Generate(params)
+	if err != nil {
+		return err
+	}
 
 	// Check if file already exists
-	if _, err := os.Stat(workflowFile); err == nil {
-		confirmed, err := ui.Confirm(fmt.Sprintf("%s already exists. Overwrite?", workflowFile))
+	if _, err := os.Stat(platform.Path); err == nil {
+		confirmed, err := ui.Confirm(fmt.Sprintf("%s already exists. Overwrite?", platform.Path))
 		if err != nil {
 			return err
 		}
@@ -60,60 +160,60 @@ func runCIGithub(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create directory
-	if err := os.MkdirAll(workflowDir, 0750); err != nil {
+	if err := os.MkdirAll(filepath.Dir(platform.Path), 0750); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Determine app UUID placeholder
-	appUUIDValue := "${{ secrets.COOLIFY_APP_UUID }}"
-	if ciAppUUID != "" {
-		appUUIDValue = ciAppUUID
-	}
-
-	// Generate workflow content
-	workflow := fmt.Sprintf(`name: Deploy to Coolify
-
-on:
-  push:
-    branches: [%s]
-  workflow_dispatch:
-
-jobs:
-  deploy:
-    runs-on: ubuntu-latest
-    steps:
-      - name: Deploy to Coolify
-        run: |
-          curl -X POST \
-            -H "Authorization: Bearer ${{ secrets.COOLIFY_TOKEN }}" \
-            "${{ secrets.COOLIFY_URL }}/api/v1/deploy?uuid=%s"
-        env:
-          COOLIFY_TOKEN: ${{ secrets.COOLIFY_TOKEN }}
-          COOLIFY_URL: ${{ secrets.COOLIFY_URL }}
-`, ciBranch, appUUIDValue)
-
 	// Write file
-	if err := os.WriteFile(workflowFile, []byte(workflow), 0600); err != nil {
+	if err := os.WriteFile(platform.Path, []byte(pipeline), 0600); err != nil {
 		return fmt.Errorf("failed to write workflow file: %w", err)
 	}
+	ui.Success(fmt.Sprintf("Created %s", platform.Path))
 
-	ui.Success(fmt.Sprintf("Created %s", workflowFile))
-	ui.Spacer()
-	ui.Info("Required GitHub Secrets:")
-	ui.List([]string{
-		"COOLIFY_URL - Your Coolify instance URL (e.g., https://coolify.example.com)",
-		"COOLIFY_TOKEN - Your Coolify API token",
-	})
-	if ciAppUUID == "" {
-		ui.List([]string{
-			"COOLIFY_APP_UUID - Your application UUID",
-		})
+	steps := []string{"Add secrets in " + platform.SecretsHelp}
+	switch platform.Name {
+	case "gitlab":
+		included, err := includeGitLabPipeline(platform.Path)
+		if err != nil {
+			return err
+		}
+		if !included {
+			steps = append(steps, fmt.Sprintf("Include %s from .gitlab-ci.yml (see the top of the file)", platform.Path))
+		}
+	case "woodpecker":
+		if _, err := os.Stat(".woodpecker.yml"); err == nil {
+			ui.Warning("Woodpecker ignores .woodpecker/ while .woodpecker.yml exists; move it into .woodpecker/")
+		}
 	}
+	if params.DeployMethod == config.DeployMethodDocker {
+		if _, err := os.Stat("Dockerfile"); os.IsNotExist(err) {
+			ui.Warning("The pipeline builds ./Dockerfile, which doesn't exist yet; commit one")
+		}
+	}
+
 	ui.Spacer()
-	ui.NextSteps([]string{
-		"Add secrets to your GitHub repository settings",
-		"Push to " + ciBranch + " branch to trigger deployment",
-	})
+	ui.Info("Required secrets:")
+	ui.List(platform.Secrets(params))
+	ui.Spacer()
+	ui.NextSteps(append(steps, "Push to "+params.Branch+" branch to trigger deployment"))
 
 	return nil
 }
+
+// includeGitLabPipeline creates .gitlab-ci.yml including path when the
+// repository has none, and reports whether path is included
+func includeGitLabPipeline(path string) (bool, error) {
+	data, err := os.ReadFile(".gitlab-ci.yml")
+	if err == nil {
+		return strings.Contains(string(data), path), nil
+	}
+	if !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read .gitlab-ci.yml: %w", err)
+	}
+	content := fmt.Sprintf("include:\n  - local: %s\n", path)
+	if err := os.WriteFile(".gitlab-ci.yml", []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to write .gitlab-ci.yml: %w", err)
+	}
+	ui.Success("Created .gitlab-ci.yml")
+	return true, nil
+}
@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/appdeploy"
+	"github.com/entro314-labs/cool-kit/internal/ci"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// Exit status of 'ci deploy' when the deployment doesn't finish in time;
+// it exits 1 when the deployment fails and 2 when an input is missing
+const ciExitTimeout = 3
+
+var ciDeployCmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "Deploy from a CI job and wait for the result",
+	Long: `Deploy an application from a CI job, without a login or a project config.
+
+It reads everything from flags and environment variables: the Coolify URL
+and token from --coolify-url or COOLIFY_URL and COOLIFY_TOKEN (or the
+global --fqdn and --token), and the application from --app-uuid or
+COOLIFY_APP_UUID, falling back to the project config. It then triggers
+the deployment and streams its build logs as plain text, folded into
+collapsible sections on GitHub Actions, Forgejo Actions and GitLab CI.
+
+It exits 0 when the deployment finished, 1 when it failed, 2 when an input
+is missing and 3 when it didn't finish within --timeout. --result-file
+writes the result as JSON for later steps; on GitHub and Forgejo Actions
+the deployment's status, url and deployment_uuid are step outputs too.
+
+Examples:
+  cool-kit ci deploy
+  cool-kit ci deploy --app-uuid=abc123 --image-tag=$GITHUB_SHA
+  cool-kit ci deploy --pr=42 --result-file=coolify.json
+  cool-kit ci deploy --secret DATABASE_URL --timeout=30m`,
+	Args: cobra.NoArgs,
+	RunE: runCIDeploy,
+}
+
+var (
+	ciDeployPR         int
+	ciDeployForce      bool
+	ciDeployWait       bool
+	ciDeployTimeout    time.Duration
+	ciDeployImageTag   string
+	ciDeployResultFile string
+)
+
+func init() {
+	ciDeployCmd.Flags().IntVar(&ciDeployPR, "pr", 0, "Deploy a preview of this pull request")
+	ciDeployCmd.Flags().BoolVar(&ciDeployForce, "force", false, "Rebuild without the build cache")
+	ciDeployCmd.Flags().BoolVar(&ciDeployWait, "wait", true, "Wait for the deployment to finish")
+	ciDeployCmd.Flags().DurationVar(&ciDeployTimeout, "timeout", appdeploy.DefaultWatchTimeout, "Maximum time to wait for the deployment, including time in the build queue")
+	ciDeployCmd.Flags().StringVar(&ciDeployImageTag, "image-tag", "", "Image tag to deploy, for docker images (default $COOLIFY_IMAGE_TAG)")
+	ciDeployCmd.Flags().StringVar(&ciDeployResultFile, "result-file", "", "Write the result as JSON to this file (default $COOLKIT_RESULT_FILE)")
+	ciCmd.AddCommand(ciDeployCmd)
+}
+
+// ciDeployResult is the JSON written by --result-file
+type ciDeployResult struct {
+	AppUUID        string  `json:"app_uuid"`
+	DeploymentUUID string  `json:"deployment_uuid,omitempty"`
+	Status         string  `json:"status"` // finished, failed, timeout or started
+	URL            string  `json:"url,omitempty"`
+	Commit         string  `json:"commit,omitempty"`
+	PR             int     `json:"pr,omitempty"`
+	StartedAt      string  `json:"started_at"`
+	FinishedAt     string  `json:"finished_at"`
+	Duration       float64 `json:"duration_seconds"`
+	Error          string  `json:"error,omitempty"`
+}
+
+func runCIDeploy(cmd *cobra.Command, args []string) error {
+	if err := ciLogin(); err != nil {
+		return err
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	appUUID, err := ciDeployAppUUID()
+	if err != nil {
+		return err
+	}
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	started := time.Now()
+	result := &ciDeployResult{
+		AppUUID:   appUUID,
+		Commit:    ciCommit(),
+		PR:        ciDeployPR,
+		StartedAt: started.UTC().Format(time.RFC3339),
+	}
+	sections := ci.NewSections(os.Stdout, ci.Detect())
+
+	err = ciDeploy(client, appUUID, result, sections)
+	sections.End()
+
+	finished := time.Now()
+	result.FinishedAt = finished.UTC().Format(time.RFC3339)
+	result.Duration = finished.Sub(started).Round(time.Second).Seconds()
+	triggered := result.Status != ""
+	if err != nil {
+		result.Error = err.Error()
+		if !triggered {
+			result.Status = "failed"
+		}
+	}
+	if werr := writeCIDeployResult(result); werr != nil {
+		ui.Warning(werr.Error())
+	}
+
+	switch {
+	case err == nil:
+		ui.Success(fmt.Sprintf("Deployment %s", result.Status))
+		if result.URL != "" {
+			ui.KeyValue("URL", result.URL)
+		}
+		return nil
+	case !triggered:
+		// Not triggered: an ordinary error, classified by Execute
+		return err
+	case errors.Is(err, appdeploy.ErrWatchTimeout):
+		ui.Error(err.Error())
+		return &exitStatusError{code: ciExitTimeout}
+	default:
+		ui.Error(err.Error())
+		return &exitStatusError{code: 1}
+	}
+}
+
+// ciDeploy sets the application's image tag and variables, triggers its
+// deployment and follows it, filling in result
+func ciDeploy(client *api.Client, appUUID string, result *ciDeployResult, sections *ci.Sections) error {
+	app, err := client.GetApplication(appUUID)
+	if err != nil {
+		return fmt.Errorf("failed to load application %s: %w", appUUID, err)
+	}
+	result.URL = app.PrimaryURL()
+	if ciDeployPR > 0 {
+		result.URL = app.PreviewURL(ciDeployPR)
+	}
+
+	sections.Start(fmt.Sprintf("Deploy %s", app.Name))
+	if tag := flagOrEnv(ciDeployImageTag, "COOLIFY_IMAGE_TAG"); tag != "" {
+		if err := client.UpdateApplication(appUUID, map[string]interface{}{"docker_registry_image_tag": tag}); err != nil {
+			return fmt.Errorf("failed to set image tag: %w", err)
+		}
+		ui.KeyValue("Image tag", tag)
+	}
+	if err := ciSetSecrets(client, appUUID); err != nil {
+		return err
+	}
+
+	// A rollback's pinned commit would be deployed instead of the branch
+	if err := client.UnpinCommit(appUUID); err != nil {
+		return err
+	}
+	resp, err := client.Deploy(appUUID, ciDeployForce, ciDeployPR)
+	if err != nil {
+		return fmt.Errorf("failed to trigger deployment: %w", err)
+	}
+	for _, d := range resp.Deployments {
+		if d.DeploymentUUID != "" {
+			result.DeploymentUUID = d.DeploymentUUID
+			break
+		}
+	}
+	if result.DeploymentUUID != "" {
+		ui.KeyValue("Deployment", result.DeploymentUUID)
+	}
+	if result.Commit != "" {
+		ui.KeyValue("Commit", result.Commit)
+	}
+
+	sections.Start("Build logs")
+	err = appdeploy.Watch(client, appUUID, appdeploy.WatchOptions{
+		NoWait:         !ciDeployWait,
+		Timeout:        ciDeployTimeout,
+		SkipMigrations: true,
+	})
+	switch {
+	case err == nil && !ciDeployWait:
+		result.Status = "started"
+	case err == nil:
+		result.Status = "finished"
+	case errors.Is(err, appdeploy.ErrWatchTimeout):
+		result.Status = "timeout"
+	default:
+		result.Status = "failed"
+	}
+	return err
+}
+
+// ciSetSecrets sets the application variables named by --secret from the
+// environment variables of the same name
+func ciSetSecrets(client *api.Client, appUUID string) error {
+	if len(ciSecrets) == 0 {
+		return nil
+	}
+	envs := make([]api.EnvironmentVariable, 0, len(ciSecrets))
+	for _, name := range ciSecrets {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return &ui.NonInteractiveError{What: name, Hint: fmt.Sprintf("--secret %s needs the environment variable %s", name, name)}
+		}
+		envs = append(envs, api.EnvironmentVariable{Key: name, Value: value})
+	}
+	if _, err := client.UpdateApplicationEnvsBulk(context.Background(), appUUID, envs); err != nil {
+		return fmt.Errorf("failed to set application variables: %w", err)
+	}
+	ui.KeyValue("Variables", strings.Join(ciSecrets, ", "))
+	return nil
+}
+
+// ciLogin uses COOLIFY_URL and COOLIFY_TOKEN, the secrets the generated
+// pipelines read, when no other login is given
+func ciLogin() error {
+	overrides := config.ActiveOverrides()
+	if url := flagOrEnv(ciURL, "COOLIFY_URL"); overrides.FQDN == "" && url != "" {
+		overrides.FQDN = url
+		overrides.Token = os.Getenv("COOLIFY_TOKEN")
+		config.SetFlagOverrides(overrides)
+		if err := config.ValidateOverrides(); err != nil {
+			return &ui.NonInteractiveError{What: "Coolify token", Hint: "set COOLIFY_TOKEN with COOLIFY_URL"}
+		}
+	}
+	if !config.IsLoggedIn() {
+		return &ui.NonInteractiveError{What: "Coolify URL", Hint: "set COOLIFY_URL and COOLIFY_TOKEN, or pass --fqdn and --token"}
+	}
+	return nil
+}
+
+// ciDeployAppUUID returns the application to deploy: by flag, environment
+// or the project config
+func ciDeployAppUUID() (string, error) {
+	if uuid := flagOrEnv(ciAppUUID, "COOLIFY_APP_UUID", "COOLKIT_APP_UUID"); uuid != "" {
+		return uuid, nil
+	}
+	projectCfg, err := config.LoadProject()
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to load project configuration: %w", err)
+	}
+	if projectCfg != nil && projectCfg.AppUUID != "" {
+		return projectCfg.AppUUID, nil
+	}
+	return "", &ui.NonInteractiveError{What: "Application UUID", Hint: "pass --app-uuid or set COOLIFY_APP_UUID"}
+}
+
+// ciCommit returns the commit being deployed, from the CI system or git
+func ciCommit() string {
+	if sha := ci.CommitSHA(); sha != "" {
+		return sha
+	}
+	if commit, err := git.GetCommitInfo(".", "HEAD"); err == nil {
+		return commit.Hash
+	}
+	return ""
+}
+
+// writeCIDeployResult writes the result to --result-file and the step
+// outputs
+func writeCIDeployResult(result *ciDeployResult) error {
+	for _, output := range []struct{ name, value string }{
+		{"status", result.Status},
+		{"url", result.URL},
+		{"deployment_uuid", result.DeploymentUUID},
+	} {
+		if err := ci.SetOutput(output.name, output.value); err != nil {
+			return err
+		}
+	}
+
+	path := flagOrEnv(ciDeployResultFile, "COOLKIT_RESULT_FILE")
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write result file: %w", err)
+	}
+	return nil
+}
+
+// flagOrEnv returns value, or else the first of the environment variables
+// that is set
+func flagOrEnv(value string, envs ...string) string {
+	if value != "" {
+		return value
+	}
+	for _, env := range envs {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
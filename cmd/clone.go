@@ -0,0 +1,415 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/appdeploy"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// defaultCloneDomainTemplate puts clones on a subdomain named after their environment
+const defaultCloneDomainTemplate = "{{env}}.{{domain}}"
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone --to ENVIRONMENT",
+	Short: "Copy the linked application into another environment",
+	Long: `Create a copy of the linked application in another environment, with the
+same source, build settings, health checks, resource limits and environment
+variables. Variables flagged shown-once are not copied, since their values
+are secret; set them on the copy yourself.
+
+The environment is created if it doesn't exist. It belongs to the application's
+project unless --project names another one.
+
+Each of the application's domains is rendered through --domain, where
+{{domain}} is the original host, {{env}} the environment and {{name}} the new
+application's name. Pass --domain "" to leave the domains to Coolify.
+
+Examples:
+  cool-kit clone --to staging
+  cool-kit clone --to staging --domain "{{env}}-{{domain}}"
+  cool-kit clone --to qa --project acme-internal --name shop-qa --deploy`,
+	Args: cobra.NoArgs,
+	RunE: runClone,
+}
+
+func init() {
+	cloneCmd.Flags().String("to", "", "Environment to create the copy in (required)")
+	cloneCmd.Flags().String("project", "", "Project name or UUID (default: the application's project)")
+	cloneCmd.Flags().String("name", "", "Name of the copy (default: NAME-ENVIRONMENT)")
+	cloneCmd.Flags().String("domain", defaultCloneDomainTemplate, "Domain template for the copy")
+	cloneCmd.Flags().String("server", "", "Server UUID (default: the application's server)")
+	cloneCmd.Flags().Bool("deploy", false, "Deploy the copy once it's created")
+	_ = cloneCmd.MarkFlagRequired("to")
+}
+
+func runClone(cmd *cobra.Command, args []string) error {
+	envName, _ := cmd.Flags().GetString("to")
+	projectRef, _ := cmd.Flags().GetString("project")
+	name, _ := cmd.Flags().GetString("name")
+	domainTemplate, _ := cmd.Flags().GetString("domain")
+	serverUUID, _ := cmd.Flags().GetString("server")
+	deploy, _ := cmd.Flags().GetBool("deploy")
+
+	envName = strings.TrimSpace(envName)
+	if envName == "" {
+		return fmt.Errorf("environment name cannot be empty")
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	ui.Section("Clone Application")
+
+	var (
+		app      *api.Application
+		project  *api.Project
+		env      *api.Environment
+		destUUID string
+	)
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-app",
+			ActiveName:   "Loading application...",
+			CompleteName: "✓ Loaded application",
+			Action: func() error {
+				var err error
+				app, err = client.GetApplication(appUUID)
+				if err != nil {
+					return fmt.Errorf("failed to get application: %w", err)
+				}
+				if projectRef == "" {
+					projectRef = projectCfg.ProjectUUID
+				}
+				if projectRef == "" {
+					project, err = applicationProject(client, app)
+				} else {
+					project, err = findProject(client, projectRef)
+				}
+				if err != nil {
+					return err
+				}
+				if serverUUID == "" {
+					serverUUID, destUUID, err = applicationDestination(client, app)
+					if err != nil {
+						return err
+					}
+				}
+				if serverUUID == "" {
+					serverUUID = projectCfg.ServerUUID
+				}
+				if serverUUID == "" {
+					return fmt.Errorf("can't tell which server the application runs on: pass --server")
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "environment",
+			ActiveName:   fmt.Sprintf("Preparing environment %s...", envName),
+			CompleteName: fmt.Sprintf("✓ Environment %s ready", envName),
+			Action: func() error {
+				for i := range project.Environments {
+					if strings.EqualFold(project.Environments[i].Name, envName) {
+						env = &project.Environments[i]
+						break
+					}
+				}
+				if env != nil && env.ID != 0 && env.ID == app.EnvironmentID {
+					return fmt.Errorf("%s is already in environment %s", app.Name, env.Name)
+				}
+				if env == nil {
+					var err error
+					env, err = client.CreateEnvironment(project.UUID, envName)
+					if err != nil {
+						return fmt.Errorf("failed to create environment %s: %w", envName, err)
+					}
+					if env.Name == "" {
+						env.Name = envName
+					}
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to prepare the copy")
+		return err
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("%s-%s", app.Name, env.Name)
+	}
+	domains := ""
+	if domainTemplate != "" {
+		domains, err = renderCloneDomains(app.GetFqdn(), domainTemplate, env.Name, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	var newUUID string
+	var skipped []string
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "create-app",
+			ActiveName:   fmt.Sprintf("Creating %s...", name),
+			CompleteName: fmt.Sprintf("✓ Created %s", name),
+			Action: func() error {
+				var err error
+				newUUID, err = createAppCopy(client, app, cloneTarget{
+					ProjectUUID:     project.UUID,
+					EnvironmentUUID: env.UUID,
+					ServerUUID:      serverUUID,
+					DestinationUUID: destUUID,
+					Name:            name,
+					Domains:         domains,
+				})
+				return err
+			},
+		},
+		{
+			Name:         "copy-settings",
+			ActiveName:   "Copying settings and variables...",
+			CompleteName: "✓ Copied settings and variables",
+			Action: func() error {
+				var err error
+				skipped, err = appdeploy.CopyApplication(client, app, newUUID, false)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to clone application")
+		return fmt.Errorf("failed to clone application: %w", err)
+	}
+
+	ui.Spacer()
+	ui.Success("Application cloned")
+	ui.KeyValue("Name", name)
+	ui.KeyValue("Project", project.Name)
+	ui.KeyValue("Environment", env.Name)
+	if domains != "" {
+		ui.KeyValue("Domains", domains)
+	}
+	ui.KeyValue("UUID", newUUID)
+
+	if len(skipped) > 0 {
+		ui.Spacer()
+		ui.Warning(fmt.Sprintf("Shown-once variables were not copied: %s", strings.Join(skipped, ", ")))
+	}
+
+	if deploy {
+		if _, err := client.Deploy(newUUID, false, 0); err != nil {
+			return fmt.Errorf("failed to trigger deployment: %w", err)
+		}
+		ui.Spacer()
+		ui.Info("Watching deployment...")
+		return appdeploy.Watch(client, newUUID, appdeploy.WatchOptions{})
+	}
+
+	steps := []string{}
+	if len(skipped) > 0 {
+		steps = append(steps, "Set the skipped variables in the Coolify dashboard")
+	}
+	steps = append(steps, "Deploy it from the Coolify dashboard, or clone with --deploy next time")
+	ui.NextSteps(steps)
+	return nil
+}
+
+// cloneTarget is where and under which name a copy of an application is created
+type cloneTarget struct {
+	ProjectUUID     string
+	EnvironmentUUID string
+	ServerUUID      string
+	DestinationUUID string
+	Name            string
+	Domains         string
+}
+
+// createAppCopy creates an application with the same source and build
+// settings as app and returns its UUID
+func createAppCopy(client *api.Client, app *api.Application, target cloneTarget) (string, error) {
+	if image := app.GetDockerRegistryImageName(); image != "" {
+		resp, err := client.CreateDockerImageApp(&api.CreateDockerImageAppRequest{
+			ProjectUUID:             target.ProjectUUID,
+			ServerUUID:              target.ServerUUID,
+			EnvironmentUUID:         target.EnvironmentUUID,
+			DestinationUUID:         target.DestinationUUID,
+			Name:                    target.Name,
+			Domains:                 target.Domains,
+			DockerRegistryImageName: image,
+			DockerRegistryImageTag:  app.GetDockerRegistryImageTag(),
+			PortsExposes:            app.PortsExposes,
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.UUID, nil
+	}
+
+	if app.GitRepository == "" {
+		return "", fmt.Errorf("cloning %s applications without a Git repository or image isn't supported", app.BuildPack)
+	}
+
+	// Applications with a source come from a GitHub App
+	if sourceID := app.GetSourceID(); sourceID != 0 {
+		githubApps, err := client.ListGitHubApps()
+		if err != nil {
+			return "", fmt.Errorf("failed to list GitHub Apps: %w", err)
+		}
+		for _, gh := range githubApps {
+			if gh.ID != sourceID {
+				continue
+			}
+			watchPaths := ""
+			if app.WatchPaths != nil {
+				watchPaths = *app.WatchPaths
+			}
+			resp, err := client.CreatePrivateGitHubApp(&api.CreatePrivateGitHubAppRequest{
+				ProjectUUID:      target.ProjectUUID,
+				ServerUUID:       target.ServerUUID,
+				EnvironmentUUID:  target.EnvironmentUUID,
+				DestinationUUID:  target.DestinationUUID,
+				GitHubAppUUID:    gh.UUID,
+				GitRepository:    app.GitRepository,
+				GitBranch:        app.GitBranch,
+				Name:             target.Name,
+				BuildPack:        app.BuildPack,
+				Domains:          target.Domains,
+				InstallCommand:   app.InstallCommand,
+				BuildCommand:     app.BuildCommand,
+				StartCommand:     app.StartCommand,
+				PortsExposes:     app.PortsExposes,
+				PublishDirectory: app.PublishDirectory,
+				BaseDirectory:    app.BaseDirectory,
+				WatchPaths:       watchPaths,
+			})
+			if err != nil {
+				return "", err
+			}
+			return resp.UUID, nil
+		}
+	}
+
+	repo := app.GetGitFullURL()
+	if repo == "" {
+		repo = app.GitRepository
+	}
+	resp, err := client.CreatePublicApp(&api.CreatePublicAppRequest{
+		ProjectUUID:      target.ProjectUUID,
+		ServerUUID:       target.ServerUUID,
+		EnvironmentUUID:  target.EnvironmentUUID,
+		DestinationUUID:  target.DestinationUUID,
+		GitRepository:    repo,
+		GitBranch:        app.GitBranch,
+		BuildPack:        app.BuildPack,
+		Name:             target.Name,
+		Domains:          target.Domains,
+		InstallCommand:   app.InstallCommand,
+		BuildCommand:     app.BuildCommand,
+		StartCommand:     app.StartCommand,
+		PortsExposes:     app.PortsExposes,
+		PublishDirectory: app.PublishDirectory,
+		BaseDirectory:    app.BaseDirectory,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.UUID, nil
+}
+
+// renderCloneDomains renders each of an application's comma-separated
+// domains through the template, keeping their scheme
+func renderCloneDomains(fqdn, template, env, name string) (string, error) {
+	var rendered []string
+	for _, domain := range strings.Split(fqdn, ",") {
+		domain = strings.TrimSpace(domain)
+		if domain == "" {
+			continue
+		}
+
+		scheme, host := "https", domain
+		if u, err := url.Parse(domain); err == nil && u.Host != "" {
+			scheme, host = u.Scheme, u.Host
+		}
+
+		r := strings.NewReplacer("{{domain}}", host, "{{env}}", env, "{{name}}", name).Replace(template)
+		if strings.Contains(r, "{{") {
+			return "", fmt.Errorf("unknown placeholder in domain template %q", template)
+		}
+		if !strings.Contains(r, "://") {
+			r = scheme + "://" + r
+		}
+		rendered = append(rendered, r)
+	}
+	return strings.Join(rendered, ","), nil
+}
+
+// findProject returns the project matching a UUID or name
+func findProject(client *api.Client, ref string) (*api.Project, error) {
+	projects, err := client.ListProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	for _, p := range projects {
+		if p.UUID == ref || strings.EqualFold(p.Name, ref) {
+			// Listed projects don't include their environments
+			project, err := client.GetProject(p.UUID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get project: %w", err)
+			}
+			return project, nil
+		}
+	}
+	return nil, fmt.Errorf("project not found: %s", ref)
+}
+
+// applicationProject returns the project holding the application's environment
+func applicationProject(client *api.Client, app *api.Application) (*api.Project, error) {
+	projects, err := client.ListProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	for _, p := range projects {
+		project, err := client.GetProject(p.UUID)
+		if err != nil {
+			continue
+		}
+		for _, env := range project.Environments {
+			if env.ID == app.EnvironmentID {
+				return project, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("can't tell which project %s belongs to: pass --project", app.Name)
+}
+
+// applicationDestination returns the server and destination UUIDs the
+// application is deployed to, or empty strings if they can't be found
+func applicationDestination(client *api.Client, app *api.Application) (string, string, error) {
+	if app.DestinationID == 0 {
+		return "", "", nil
+	}
+	destinations, err := client.ListDestinations()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list destinations: %w", err)
+	}
+	for _, d := range destinations {
+		if d.ID == app.DestinationID {
+			return d.ServerUUID, d.UUID, nil
+		}
+	}
+	return "", "", nil
+}
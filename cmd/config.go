@@ -4,9 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strconv"
+	"reflect"
 	"strings"
 
+	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -32,10 +33,9 @@ var configShowCmd = &cobra.Command{
 			}
 
 			// Get current value
-			currentVal := viper.Get(key)
-			currentStr := fmt.Sprintf("%v", currentVal)
-			if currentVal == nil {
-				currentStr = ""
+			currentStr := ""
+			if currentVal, err := config.GetValue(key); err == nil {
+				currentStr = fmt.Sprintf("%v", currentVal)
 			}
 
 			// Ask for new value
@@ -45,7 +45,7 @@ var configShowCmd = &cobra.Command{
 			}
 
 			// Set and save
-			if err := setConfigValue(key, newVal); err != nil {
+			if err := config.SetValue(key, newVal); err != nil {
 				ui.Error(fmt.Sprintf("Failed to set %s: %v", key, err))
 			} else {
 				ui.Success(fmt.Sprintf("Set %s = %s", key, newVal))
@@ -54,22 +54,65 @@ var configShowCmd = &cobra.Command{
 	},
 }
 
+var configGetCmd = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Show a configuration value",
+	Long: `Show a configuration value, or a whole section as JSON. Nested keys use
+dot notation.
+
+Examples:
+  cool-kit config get provider
+  cool-kit config get azure.vm_size
+  cool-kit config get gcp`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.Initialize(); err != nil {
+			return fmt.Errorf("failed to initialize configuration: %w", err)
+		}
+		value, err := config.GetValue(args[0])
+		if err != nil {
+			return err
+		}
+
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice:
+			data, err := json.MarshalIndent(value, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal %s: %w", args[0], err)
+			}
+			fmt.Println(string(data))
+		default:
+			fmt.Println(value)
+		}
+		return nil
+	},
+}
+
 var configSetCmd = &cobra.Command{
 	Use:   "set KEY VALUE",
 	Short: "Set configuration value",
 	Long: `Set a configuration value. Supports nested keys with dot notation.
 
+The value is converted to the key's type: numbers and true/false are
+checked, lists are comma separated. Unknown keys and invalid values, like
+a provider that doesn't exist or a port out of range, are rejected.
+Keys under settings are free-form.
+
 Examples:
   cool-kit config set provider azure
   cool-kit config set azure.location westeurope
   cool-kit config set azure.vm_size Standard_D2s_v3
+  cool-kit config set gcp.project my-proj
   cool-kit config set local.app_port 8000`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
 		value := args[1]
 
-		if err := setConfigValue(key, value); err != nil {
+		if err := config.Initialize(); err != nil {
+			return fmt.Errorf("failed to initialize configuration: %w", err)
+		}
+		if err := config.SetValue(key, value); err != nil {
 			return err
 		}
 
@@ -78,6 +121,29 @@ Examples:
 	},
 }
 
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset KEY",
+	Short: "Reset a configuration value to its default",
+	Long: `Reset a configuration value, or a whole section, to its default. Keys
+under settings are removed.
+
+Examples:
+  cool-kit config unset baremetal.host
+  cool-kit config unset azure`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.Initialize(); err != nil {
+			return fmt.Errorf("failed to initialize configuration: %w", err)
+		}
+		if err := config.UnsetValue(args[0]); err != nil {
+			return err
+		}
+
+		ui.Success(fmt.Sprintf("Unset %s", args[0]))
+		return nil
+	},
+}
+
 var configResetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset configuration to defaults",
@@ -191,22 +257,6 @@ Examples:
 	},
 }
 
-// setConfigValue sets a config value with proper type conversion
-func setConfigValue(key, value string) error {
-	// Try to parse as int
-	if i, err := strconv.Atoi(value); err == nil {
-		viper.Set(key, i)
-	} else if b, err := strconv.ParseBool(value); err == nil {
-		// Try to parse as bool
-		viper.Set(key, b)
-	} else {
-		// Keep as string
-		viper.Set(key, value)
-	}
-
-	return saveConfig()
-}
-
 // saveConfig writes viper config to file
 func saveConfig() error {
 	configPath := viper.ConfigFileUsed()
@@ -234,7 +284,9 @@ func saveConfig() error {
 
 func init() {
 	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
 	configCmd.AddCommand(configResetCmd)
 	configCmd.AddCommand(configExportCmd)
 	configCmd.AddCommand(configImportCmd)
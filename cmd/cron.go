@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// cronKeywords are the frequency shortcuts Coolify accepts besides cron expressions
+var cronKeywords = []string{"every_minute", "hourly", "daily", "weekly", "monthly", "yearly"}
+
+var cronCmd = &cobra.Command{
+	Use:   "cron",
+	Short: "Manage scheduled tasks",
+	Long: `Manage the linked application's scheduled tasks. Coolify runs each task's
+command inside the application's container on a cron schedule.
+
+Schedules are cron expressions or one of: ` + strings.Join(cronKeywords, ", ") + `.
+Tasks can be referred to by UUID or name.
+
+Available Commands:
+  cron ls       - List scheduled tasks
+  cron add      - Add a scheduled task
+  cron enable   - Enable a scheduled task
+  cron disable  - Disable a scheduled task
+  cron rm       - Delete a scheduled task
+  cron logs     - Show the output of recent runs`,
+}
+
+var cronListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List scheduled tasks",
+	Args:  cobra.NoArgs,
+	RunE:  runCronList,
+}
+
+var cronAddCmd = &cobra.Command{
+	Use:   "add SCHEDULE COMMAND",
+	Short: "Add a scheduled task",
+	Long: `Add a scheduled task that runs COMMAND in the application's container.
+
+Examples:
+  cool-kit cron add "* * * * *" "php artisan schedule:run"
+  cool-kit cron add daily "bundle exec rake cleanup" --name cleanup
+  cool-kit cron add "0 3 * * *" "python manage.py clearsessions" --timeout 600`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCronAdd,
+}
+
+var cronEnableCmd = &cobra.Command{
+	Use:   "enable TASK",
+	Short: "Enable a scheduled task",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCronEnabled(args[0], true)
+	},
+}
+
+var cronDisableCmd = &cobra.Command{
+	Use:   "disable TASK",
+	Short: "Disable a scheduled task",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCronEnabled(args[0], false)
+	},
+}
+
+var cronRemoveCmd = &cobra.Command{
+	Use:   "rm TASK",
+	Short: "Delete a scheduled task",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCronRemove,
+}
+
+var cronLogsCmd = &cobra.Command{
+	Use:   "logs TASK",
+	Short: "Show the output of a task's recent runs",
+	Long: `Show the status and output of a scheduled task's most recent runs.
+
+Examples:
+  cool-kit cron logs schedule
+  cool-kit cron logs <uuid> -n 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCronLogs,
+}
+
+func init() {
+	cronCmd.AddCommand(cronListCmd)
+	cronCmd.AddCommand(cronAddCmd)
+	cronCmd.AddCommand(cronEnableCmd)
+	cronCmd.AddCommand(cronDisableCmd)
+	cronCmd.AddCommand(cronRemoveCmd)
+	cronCmd.AddCommand(cronLogsCmd)
+	addTableFlags(cronListCmd)
+
+	cronAddCmd.Flags().String("name", "", "Task name (defaults to the command's first word)")
+	cronAddCmd.Flags().String("container", "", "Container to run in (for Docker Compose applications)")
+	cronAddCmd.Flags().Int("timeout", 0, "Seconds before the task is stopped (0 uses Coolify's default)")
+	cronAddCmd.Flags().Bool("disabled", false, "Create the task disabled")
+
+	cronLogsCmd.Flags().IntP("limit", "n", 1, "Number of runs to show")
+}
+
+func runCronList(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	ui.Section("Scheduled Tasks")
+
+	var tasks []api.ScheduledTask
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-tasks",
+			ActiveName:   "Loading scheduled tasks...",
+			CompleteName: "✓ Loaded scheduled tasks",
+			Action: func() error {
+				var err error
+				tasks, err = client.ListScheduledTasks(appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load scheduled tasks")
+		return fmt.Errorf("failed to list scheduled tasks: %w", err)
+	}
+
+	if len(tasks) == 0 {
+		ui.Dim("No scheduled tasks configured")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s cron add SCHEDULE COMMAND' to add one", execName()),
+		})
+		return nil
+	}
+
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "Name"},
+		{Name: "Schedule"},
+		{Name: "Command"},
+		{Name: "Status", Status: true},
+		{Name: "UUID"},
+	}}
+	for _, t := range tasks {
+		enabled := "enabled"
+		if !t.Enabled {
+			enabled = "disabled"
+		}
+		table.Rows = append(table.Rows, []string{t.Name, t.Frequency, t.Command, enabled, shortUUID(t.UUID)})
+	}
+
+	ui.Spacer()
+	return printTable(cmd, table)
+}
+
+func runCronAdd(cmd *cobra.Command, args []string) error {
+	schedule, command := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+	name, _ := cmd.Flags().GetString("name")
+	container, _ := cmd.Flags().GetString("container")
+	timeout, _ := cmd.Flags().GetInt("timeout")
+	disabled, _ := cmd.Flags().GetBool("disabled")
+
+	if err := validateCronSchedule(schedule); err != nil {
+		return err
+	}
+	if command == "" {
+		return fmt.Errorf("command cannot be empty")
+	}
+	if timeout < 0 {
+		return fmt.Errorf("invalid timeout: %d", timeout)
+	}
+	if name == "" {
+		name = strings.Fields(command)[0]
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	enabled := !disabled
+	req := &api.ScheduledTaskRequest{
+		Name:      name,
+		Command:   command,
+		Frequency: schedule,
+		Container: container,
+		Enabled:   &enabled,
+	}
+	if timeout > 0 {
+		req.Timeout = &timeout
+	}
+
+	var task *api.ScheduledTask
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "create-task",
+			ActiveName:   "Creating scheduled task...",
+			CompleteName: "✓ Created scheduled task",
+			Action: func() error {
+				var err error
+				task, err = client.CreateScheduledTask(appUUID, req)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to create scheduled task")
+		return fmt.Errorf("failed to create scheduled task: %w", err)
+	}
+
+	ui.Spacer()
+	ui.Success("Scheduled task created")
+	ui.KeyValue("Name", name)
+	ui.KeyValue("Schedule", schedule)
+	ui.KeyValue("Command", command)
+	if task.UUID != "" {
+		ui.KeyValue("UUID", task.UUID)
+	}
+	return nil
+}
+
+// validateCronSchedule accepts a Coolify frequency keyword or a five-field cron expression
+func validateCronSchedule(schedule string) error {
+	for _, k := range cronKeywords {
+		if schedule == k {
+			return nil
+		}
+	}
+	if len(strings.Fields(schedule)) != 5 {
+		return fmt.Errorf("invalid schedule %q: use a five-field cron expression or one of %s",
+			schedule, strings.Join(cronKeywords, ", "))
+	}
+	return nil
+}
+
+func setCronEnabled(ref string, enabled bool) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	task, err := findScheduledTask(client, appUUID, ref)
+	if err != nil {
+		return err
+	}
+
+	action, active, done := "enable", "Enabling", "Enabled"
+	if !enabled {
+		action, active, done = "disable", "Disabling", "Disabled"
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         action + "-task",
+			ActiveName:   fmt.Sprintf("%s %s...", active, task.Name),
+			CompleteName: fmt.Sprintf("✓ %s %s", done, task.Name),
+			Action: func() error {
+				return client.UpdateScheduledTask(appUUID, task.UUID, &api.ScheduledTaskRequest{Enabled: &enabled})
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to %s scheduled task", action))
+		return fmt.Errorf("failed to %s scheduled task: %w", action, err)
+	}
+	return nil
+}
+
+func runCronRemove(cmd *cobra.Command, args []string) error {
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	task, err := findScheduledTask(client, appUUID, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !yes {
+		confirmed, err := ui.ConfirmAction("delete", "scheduled task "+task.Name)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Dim("Cancelled")
+			return nil
+		}
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "delete-task",
+			ActiveName:   "Deleting scheduled task...",
+			CompleteName: "✓ Deleted scheduled task",
+			Action: func() error {
+				return client.DeleteScheduledTask(appUUID, task.UUID)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to delete scheduled task")
+		return fmt.Errorf("failed to delete scheduled task: %w", err)
+	}
+	return nil
+}
+
+func runCronLogs(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 1 {
+		limit = 1
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	task, err := findScheduledTask(client, appUUID, args[0])
+	if err != nil {
+		return err
+	}
+
+	executions, err := client.ListScheduledTaskExecutions(appUUID, task.UUID)
+	if err != nil {
+		return fmt.Errorf("failed to list task runs: %w", err)
+	}
+
+	ui.Section(fmt.Sprintf("Runs: %s", task.Name))
+	if len(executions) == 0 {
+		ui.Dim("No runs yet")
+		return nil
+	}
+	if len(executions) > limit {
+		executions = executions[:limit]
+	}
+
+	for i, e := range executions {
+		if i > 0 {
+			ui.Spacer()
+		}
+		ui.Bold(fmt.Sprintf("%s  %s", formatDeployTime(api.ParseTimestamp(e.CreatedAt)), styleDeploymentStatus(e.Status)))
+		if output := strings.TrimRight(e.Message, "\n"); output != "" {
+			fmt.Println(output)
+		} else {
+			ui.Dim("(no output)")
+		}
+	}
+	return nil
+}
+
+// findScheduledTask returns the application's task matching a UUID, UUID prefix or name
+func findScheduledTask(client *api.Client, appUUID, ref string) (*api.ScheduledTask, error) {
+	tasks, err := client.ListScheduledTasks(appUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled tasks: %w", err)
+	}
+
+	var match *api.ScheduledTask
+	for i := range tasks {
+		t := &tasks[i]
+		if t.UUID == ref || t.Name == ref {
+			return t, nil
+		}
+		if strings.HasPrefix(t.UUID, ref) {
+			if match != nil {
+				return nil, fmt.Errorf("task %q is ambiguous", ref)
+			}
+			match = t
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("scheduled task not found: %s", ref)
+	}
+	return match, nil
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+var dashboardCmd = &cobra.Command{
+	Use:     "dashboard",
+	Aliases: []string{"dash", "top"},
+	Short:   "Browse and manage the resources of the current instance",
+	Long: `Open a live dashboard of the applications, databases and services of the
+current Coolify instance. Statuses refresh every --interval.
+
+Keys:
+  ↑/↓ or j/k   select a resource
+  tab or 1-3   switch between applications, databases and services
+  d            deploy (databases and services are started)
+  r            restart, after confirming
+  s            stop, after confirming
+  l or enter   show the logs of an application, with the keys of 'logs'
+  o            open the resource in the browser
+  ctrl+r       refresh now
+  q            quit`,
+	Example: `  cool-kit dashboard
+  cool-kit dashboard --instance staging --interval 10s`,
+	RunE: runDashboard,
+}
+
+func init() {
+	dashboardCmd.Flags().Duration("interval", 5*time.Second, "How often to refresh statuses")
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+	instance, err := getCurrentInstance()
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("%s (%s)", instance.Name, instance.FQDN)
+	return ui.RunDashboard(&dashboardBackend{client: client, baseURL: instance.FQDN}, title, interval)
+}
+
+// dashboardBackend runs the dashboard's actions through the Coolify API
+type dashboardBackend struct {
+	client  *api.Client
+	baseURL string
+}
+
+// Resources lists the applications, databases and services, each by name
+func (b *dashboardBackend) Resources() ([]ui.Resource, error) {
+	var apps []api.Application
+	var databases []api.Database
+	var services []api.Service
+
+	g := new(errgroup.Group)
+	g.Go(func() (err error) {
+		apps, err = b.client.ListApplications()
+		return err
+	})
+	g.Go(func() (err error) {
+		databases, err = b.client.ListDatabases()
+		return err
+	})
+	g.Go(func() (err error) {
+		services, err = b.client.ListServices()
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	resources := make([]ui.Resource, 0, len(apps)+len(databases)+len(services))
+	for _, app := range apps {
+		detail := app.PrimaryURL()
+		if detail == "" {
+			detail = app.GitRepository
+		}
+		resources = append(resources, ui.Resource{
+			Kind:   ui.KindApplication,
+			UUID:   app.UUID,
+			Name:   app.Name,
+			Status: app.Status,
+			Detail: detail,
+			URL:    app.PrimaryURL(),
+		})
+	}
+	for _, db := range databases {
+		engine := db.DatabaseType
+		if engine == "" {
+			engine = db.Type
+		}
+		resources = append(resources, ui.Resource{
+			Kind:   ui.KindDatabase,
+			UUID:   db.UUID,
+			Name:   db.Name,
+			Status: db.Status,
+			Detail: strings.TrimPrefix(engine, "standalone-"),
+		})
+	}
+	for _, svc := range services {
+		r := ui.Resource{
+			Kind:   ui.KindService,
+			UUID:   svc.UUID,
+			Name:   svc.Name,
+			Status: svc.Status,
+			Detail: svc.Type,
+		}
+		for _, app := range svc.Applications {
+			if app.Fqdn != nil && *app.Fqdn != "" {
+				r.URL = strings.TrimSpace(strings.Split(*app.Fqdn, ",")[0])
+				break
+			}
+		}
+		resources = append(resources, r)
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return strings.ToLower(resources[i].Name) < strings.ToLower(resources[j].Name)
+	})
+	return resources, nil
+}
+
+// Deploy deploys an application, or starts a database or service
+func (b *dashboardBackend) Deploy(r ui.Resource) (string, error) {
+	switch r.Kind {
+	case ui.KindDatabase:
+		if _, err := b.client.StartDatabase(r.UUID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Started %s", r.Name), nil
+	case ui.KindService:
+		if _, err := b.client.StartService(r.UUID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Started %s", r.Name), nil
+	}
+
+	if err := b.client.UnpinCommit(r.UUID); err != nil {
+		return "", err
+	}
+	resp, err := b.client.Deploy(r.UUID, false, 0)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Deployments) > 0 && resp.Deployments[0].DeploymentUUID != "" {
+		return fmt.Sprintf("Queued deployment %s of %s", resp.Deployments[0].DeploymentUUID, r.Name), nil
+	}
+	return fmt.Sprintf("Queued a deployment of %s", r.Name), nil
+}
+
+// Restart restarts a resource
+func (b *dashboardBackend) Restart(r ui.Resource) (string, error) {
+	var err error
+	switch r.Kind {
+	case ui.KindApplication:
+		_, err = b.client.RestartApplication(context.Background(), r.UUID)
+	case ui.KindDatabase:
+		_, err = b.client.RestartDatabase(r.UUID)
+	case ui.KindService:
+		_, err = b.client.RestartService(r.UUID)
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Restarting %s", r.Name), nil
+}
+
+// Stop stops a resource
+func (b *dashboardBackend) Stop(r ui.Resource) (string, error) {
+	var err error
+	switch r.Kind {
+	case ui.KindApplication:
+		_, err = b.client.StopApplication(context.Background(), r.UUID)
+	case ui.KindDatabase:
+		_, err = b.client.StopDatabase(r.UUID)
+	case ui.KindService:
+		_, err = b.client.StopService(r.UUID)
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Stopping %s", r.Name), nil
+}
+
+// Logs returns the latest logs of an application. Coolify's API has no logs
+// for databases and services.
+func (b *dashboardBackend) Logs(r ui.Resource) (string, error) {
+	if r.Kind != ui.KindApplication {
+		return "", fmt.Errorf("Coolify's API only has logs for applications: open %s in Coolify to see its logs", r.Name)
+	}
+	resp, err := b.client.GetApplicationLogs(context.Background(), r.UUID, 200)
+	if err != nil {
+		return "", err
+	}
+	return resp.Logs, nil
+}
+
+// Open opens the resource's domain, or Coolify when it has none
+func (b *dashboardBackend) Open(r ui.Resource) error {
+	url := r.URL
+	if url == "" {
+		url = b.baseURL
+	}
+	if !strings.Contains(url, "://") {
+		url = "https://" + url
+	}
+	return openBrowser(url)
+}
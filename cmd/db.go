@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage standalone databases",
+	Long: `Create and operate standalone databases on your Coolify instance.
+
+Supported engines: ` + strings.Join(api.DatabaseEngines, ", ") + `
+
+Available Commands:
+  db create    - Create a database
+  db start     - Start a database
+  db stop      - Stop a database
+  db restart   - Restart a database
+  db expose    - Expose a database on a public port
+  db unexpose  - Make a database private again
+  db tunnel    - Forward a local port to a database over ssh
+  db backups   - Manage scheduled backups`,
+}
+
+var dbCreateCmd = &cobra.Command{
+	Use:   "create ENGINE",
+	Short: "Create a database",
+	Long: `Create a standalone database. Credentials are generated by Coolify.
+
+Examples:
+  cool-kit db create postgresql --name app-db
+  cool-kit db create redis --image redis:7-alpine --instant-deploy
+  cool-kit db create clickhouse --project <uuid> --server <uuid>`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: api.DatabaseEngines,
+	RunE:      runDBCreate,
+}
+
+var dbStartCmd = &cobra.Command{
+	Use:   "start [UUID]",
+	Short: "Start a database",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDBStart,
+}
+
+var dbStopCmd = &cobra.Command{
+	Use:   "stop [UUID]",
+	Short: "Stop a database",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDBStop,
+}
+
+var dbRestartCmd = &cobra.Command{
+	Use:   "restart [UUID]",
+	Short: "Restart a database",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDBRestart,
+}
+
+var dbExposeCmd = &cobra.Command{
+	Use:   "expose [UUID]",
+	Short: "Expose a database on a public port",
+	Long: `Make a database reachable from outside the Coolify server.
+
+Examples:
+  cool-kit db expose <uuid> --port 54320`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDBExpose,
+}
+
+var dbUnexposeCmd = &cobra.Command{
+	Use:   "unexpose [UUID]",
+	Short: "Make a database private",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDBUnexpose,
+}
+
+func init() {
+	dbCmd.AddCommand(dbCreateCmd)
+	dbCmd.AddCommand(dbStartCmd)
+	dbCmd.AddCommand(dbStopCmd)
+	dbCmd.AddCommand(dbRestartCmd)
+	dbCmd.AddCommand(dbExposeCmd)
+	dbCmd.AddCommand(dbUnexposeCmd)
+
+	dbCreateCmd.Flags().String("name", "", "Database name (defaults to the engine name)")
+	dbCreateCmd.Flags().String("image", "", "Docker image (defaults to Coolify's image for the engine)")
+	dbCreateCmd.Flags().String("project", "", "Project UUID")
+	dbCreateCmd.Flags().String("environment", "production", "Environment name")
+	dbCreateCmd.Flags().String("server", "", "Server UUID")
+	dbCreateCmd.Flags().Int("public-port", 0, "Expose the database on this public port")
+	dbCreateCmd.Flags().Bool("instant-deploy", false, "Start the database right after creation")
+
+	dbExposeCmd.Flags().Int("port", 0, "Public port (keeps the configured port if omitted)")
+}
+
+func runDBCreate(cmd *cobra.Command, args []string) error {
+	engine := strings.ToLower(args[0])
+
+	name, _ := cmd.Flags().GetString("name")
+	image, _ := cmd.Flags().GetString("image")
+	projectUUID, _ := cmd.Flags().GetString("project")
+	environment, _ := cmd.Flags().GetString("environment")
+	serverUUID, _ := cmd.Flags().GetString("server")
+	publicPort, _ := cmd.Flags().GetInt("public-port")
+	instantDeploy, _ := cmd.Flags().GetBool("instant-deploy")
+
+	if name == "" {
+		name = engine
+	}
+
+	if !isDatabaseEngine(engine) {
+		return fmt.Errorf("unsupported database engine: %s (supported: %s)", engine, strings.Join(api.DatabaseEngines, ", "))
+	}
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	ui.Section(fmt.Sprintf("Create Database: %s", engine))
+
+	if projectUUID == "" {
+		projectUUID, err = promptProjectUUID(client)
+		if err != nil {
+			return err
+		}
+	}
+
+	if serverUUID == "" {
+		serverUUID, err = promptServerUUID(client)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := api.NewDatabaseRequest(engine, api.CreateDatabaseRequest{
+		Name:            name,
+		Image:           image,
+		ProjectUUID:     projectUUID,
+		EnvironmentName: environment,
+		ServerUUID:      serverUUID,
+		IsPublic:        publicPort > 0,
+		PublicPort:      publicPort,
+		InstantDeploy:   instantDeploy,
+	})
+	if err != nil {
+		return err
+	}
+
+	var resp *api.CreateDatabaseResponse
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "create-database",
+			ActiveName:   fmt.Sprintf("Creating %s database...", engine),
+			CompleteName: fmt.Sprintf("✓ Created %s database", engine),
+			Action: func() error {
+				var err error
+				resp, err = client.CreateDatabase(req)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to create database")
+		return fmt.Errorf("failed to create database: %w", err)
+	}
+
+	ui.Spacer()
+	ui.Success("Database created successfully")
+	ui.KeyValue("UUID", resp.UUID)
+	if resp.InternalDBURL != "" {
+		ui.KeyValue("Internal URL", resp.InternalDBURL)
+	}
+	if resp.ExternalDBURL != "" {
+		ui.KeyValue("External URL", resp.ExternalDBURL)
+	}
+
+	steps := []string{
+		fmt.Sprintf("Run '%s services info %s' to view connection details", execName(), resp.UUID),
+	}
+	if !instantDeploy {
+		steps = append(steps, fmt.Sprintf("Run '%s db start %s' to start it", execName(), resp.UUID))
+	}
+	ui.NextSteps(steps)
+
+	return nil
+}
+
+// isDatabaseEngine reports whether engine is supported by the create endpoint
+func isDatabaseEngine(engine string) bool {
+	for _, e := range api.DatabaseEngines {
+		if e == engine {
+			return true
+		}
+	}
+	return false
+}
+
+func runDBStart(cmd *cobra.Command, args []string) error {
+	return runDatabaseAction(args[0], "start", "Starting", "Started", func(client *api.Client, uuid string) error {
+		_, err := client.StartDatabase(uuid)
+		return err
+	})
+}
+
+func runDBStop(cmd *cobra.Command, args []string) error {
+	return runDatabaseAction(args[0], "stop", "Stopping", "Stopped", func(client *api.Client, uuid string) error {
+		_, err := client.StopDatabase(uuid)
+		return err
+	})
+}
+
+func runDBRestart(cmd *cobra.Command, args []string) error {
+	return runDatabaseAction(args[0], "restart", "Restarting", "Restarted", func(client *api.Client, uuid string) error {
+		_, err := client.RestartDatabase(uuid)
+		return err
+	})
+}
+
+func runDBExpose(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	if port < 0 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+
+	if err := runDatabaseAction(args[0], "expose", "Exposing", "Exposed", func(client *api.Client, uuid string) error {
+		return client.SetDatabasePublic(uuid, true, port)
+	}); err != nil {
+		return err
+	}
+
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s db restart %s' to apply the change", execName(), args[0]),
+	})
+	return nil
+}
+
+func runDBUnexpose(cmd *cobra.Command, args []string) error {
+	if err := runDatabaseAction(args[0], "unexpose", "Unexposing", "Unexposed", func(client *api.Client, uuid string) error {
+		return client.SetDatabasePublic(uuid, false, 0)
+	}); err != nil {
+		return err
+	}
+
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s db restart %s' to apply the change", execName(), args[0]),
+	})
+	return nil
+}
+
+// runDatabaseAction runs a lifecycle action against a database with spinner feedback
+func runDatabaseAction(uuid, action, activeVerb, doneVerb string, fn func(*api.Client, string) error) error {
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         action + "-database",
+			ActiveName:   fmt.Sprintf("%s database...", activeVerb),
+			CompleteName: fmt.Sprintf("✓ %s database", doneVerb),
+			Action: func() error {
+				return fn(client, uuid)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to %s database", action))
+		return fmt.Errorf("failed to %s database: %w", action, err)
+	}
+
+	return nil
+}
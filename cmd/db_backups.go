@@ -0,0 +1,680 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/entro314-labs/cool-kit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var dbBackupsCmd = &cobra.Command{
+	Use:   "backups",
+	Short: "Manage scheduled database backups",
+	Long: `Configure Coolify's scheduled backups for a database, inspect backup runs,
+and download or restore a backup file.
+
+Frequency accepts a cron expression or one of: every_minute, hourly, daily,
+weekly, monthly, yearly.
+
+Available Commands:
+  db backups ls          - List backup schedules
+  db backups create      - Create a backup schedule
+  db backups update      - Update a backup schedule
+  db backups rm          - Delete a backup schedule
+  db backups run         - Run a backup now
+  db backups executions  - List backup runs
+  db backups download    - Download a backup file
+  db backups restore     - Restore a backup into the database`,
+}
+
+var dbBackupsListCmd = &cobra.Command{
+	Use:   "ls DB_UUID",
+	Short: "List backup schedules",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDBBackupsList,
+}
+
+var dbBackupsCreateCmd = &cobra.Command{
+	Use:   "create DB_UUID",
+	Short: "Create a backup schedule",
+	Long: `Create a scheduled backup for a database.
+
+Examples:
+  cool-kit db backups create <uuid> --frequency daily --retention 7
+  cool-kit db backups create <uuid> --frequency "0 3 * * *" --s3-storage <s3-uuid> --now`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDBBackupsCreate,
+}
+
+var dbBackupsUpdateCmd = &cobra.Command{
+	Use:   "update DB_UUID BACKUP_UUID",
+	Short: "Update a backup schedule",
+	Long: `Update a scheduled backup. Only the flags you pass are changed.
+
+Examples:
+  cool-kit db backups update <uuid> <backup-uuid> --retention-days 30
+  cool-kit db backups update <uuid> <backup-uuid> --enabled=false`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDBBackupsUpdate,
+}
+
+var dbBackupsRemoveCmd = &cobra.Command{
+	Use:   "rm DB_UUID BACKUP_UUID",
+	Short: "Delete a backup schedule",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runDBBackupsRemove,
+}
+
+var dbBackupsRunCmd = &cobra.Command{
+	Use:   "run DB_UUID [BACKUP_UUID]",
+	Short: "Run a backup now",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runDBBackupsRun,
+}
+
+var dbBackupsExecutionsCmd = &cobra.Command{
+	Use:   "executions DB_UUID [BACKUP_UUID]",
+	Short: "List backup runs",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runDBBackupsExecutions,
+}
+
+var dbBackupsDownloadCmd = &cobra.Command{
+	Use:   "download DB_UUID EXECUTION_UUID",
+	Short: "Download a backup file",
+	Long: `Download a backup file from the server that runs the database (over scp).
+
+Examples:
+  cool-kit db backups download <uuid> <execution-uuid> --server <server-uuid>
+  cool-kit db backups download <uuid> <execution-uuid> --output ./backups/`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDBBackupsDownload,
+}
+
+var dbBackupsRestoreCmd = &cobra.Command{
+	Use:   "restore DB_UUID EXECUTION_UUID",
+	Short: "Restore a backup into the database",
+	Long: `Restore a backup file into the running database container (over ssh).
+Supported engines: postgresql, mysql, mariadb, mongodb.
+
+This overwrites existing data in the target database.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDBBackupsRestore,
+}
+
+func init() {
+	dbCmd.AddCommand(dbBackupsCmd)
+	dbBackupsCmd.AddCommand(dbBackupsListCmd)
+	dbBackupsCmd.AddCommand(dbBackupsCreateCmd)
+	dbBackupsCmd.AddCommand(dbBackupsUpdateCmd)
+	dbBackupsCmd.AddCommand(dbBackupsRemoveCmd)
+	dbBackupsCmd.AddCommand(dbBackupsRunCmd)
+	dbBackupsCmd.AddCommand(dbBackupsExecutionsCmd)
+	dbBackupsCmd.AddCommand(dbBackupsDownloadCmd)
+	dbBackupsCmd.AddCommand(dbBackupsRestoreCmd)
+
+	addBackupScheduleFlags(dbBackupsCreateCmd)
+	dbBackupsCreateCmd.Flags().Bool("now", false, "Run the first backup immediately")
+	addBackupScheduleFlags(dbBackupsUpdateCmd)
+
+	for _, c := range []*cobra.Command{dbBackupsDownloadCmd, dbBackupsRestoreCmd} {
+		c.Flags().String("server", "", "UUID of the server running the database")
+		c.Flags().StringP("identity", "i", "", "SSH private key file")
+	}
+	dbBackupsDownloadCmd.Flags().String("output", ".", "Output file or directory")
+	addTableFlags(dbBackupsListCmd, dbBackupsExecutionsCmd)
+}
+
+// addBackupScheduleFlags registers the flags shared by backup create and update
+func addBackupScheduleFlags(c *cobra.Command) {
+	c.Flags().String("frequency", "daily", "Cron expression or keyword (hourly, daily, weekly, ...)")
+	c.Flags().Bool("enabled", true, "Enable the schedule")
+	c.Flags().String("databases", "", "Comma-separated databases to back up (defaults to the main database)")
+	c.Flags().Bool("dump-all", false, "Dump all databases in a single file")
+	c.Flags().Int("retention", 0, "Number of local backups to keep (0 keeps all)")
+	c.Flags().Int("retention-days", 0, "Days to keep local backups (0 keeps forever)")
+	c.Flags().String("s3-storage", "", "S3 storage UUID to upload backups to")
+	c.Flags().Int("s3-retention", 0, "Number of S3 backups to keep (0 keeps all)")
+	c.Flags().Int("s3-retention-days", 0, "Days to keep S3 backups (0 keeps forever)")
+}
+
+// backupRequestFromFlags builds a request containing only the flags set by the user
+func backupRequestFromFlags(c *cobra.Command) *api.BackupRequest {
+	flags := c.Flags()
+	req := &api.BackupRequest{}
+
+	if flags.Changed("frequency") {
+		req.Frequency, _ = flags.GetString("frequency")
+	}
+	if flags.Changed("enabled") {
+		v, _ := flags.GetBool("enabled")
+		req.Enabled = &v
+	}
+	if flags.Changed("databases") {
+		req.DatabasesToBackup, _ = flags.GetString("databases")
+	}
+	if flags.Changed("dump-all") {
+		v, _ := flags.GetBool("dump-all")
+		req.DumpAll = &v
+	}
+	if flags.Changed("s3-storage") {
+		req.S3StorageUUID, _ = flags.GetString("s3-storage")
+		saveS3 := req.S3StorageUUID != ""
+		req.SaveS3 = &saveS3
+	}
+
+	intFlags := map[string]**int{
+		"retention":         &req.RetentionAmountLocally,
+		"retention-days":    &req.RetentionDaysLocally,
+		"s3-retention":      &req.RetentionAmountS3,
+		"s3-retention-days": &req.RetentionDaysS3,
+	}
+	for name, field := range intFlags {
+		if flags.Changed(name) {
+			v, _ := flags.GetInt(name)
+			*field = &v
+		}
+	}
+
+	return req
+}
+
+func runDBBackupsList(cmd *cobra.Command, args []string) error {
+	dbUUID := args[0]
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	ui.Section("Backup Schedules")
+
+	var backups []api.ScheduledBackup
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-backups",
+			ActiveName:   "Loading backup schedules...",
+			CompleteName: "✓ Loaded backup schedules",
+			Action: func() error {
+				var err error
+				backups, err = client.ListDatabaseBackups(dbUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load backup schedules")
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	if len(backups) == 0 {
+		ui.Dim("No backup schedules configured")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s db backups create %s' to schedule backups", execName(), dbUUID),
+		})
+		return nil
+	}
+
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "UUID"},
+		{Name: "Frequency"},
+		{Name: "Enabled"},
+		{Name: "S3"},
+		{Name: "Retention"},
+	}}
+	for _, b := range backups {
+		table.Rows = append(table.Rows, []string{
+			b.UUID,
+			b.Frequency,
+			fmt.Sprintf("%t", b.Enabled),
+			fmt.Sprintf("%t", b.SaveS3),
+			formatRetention(b.RetentionAmountLocally, b.RetentionDaysLocally),
+		})
+	}
+
+	ui.Spacer()
+	return printTable(cmd, table)
+}
+
+// formatRetention describes a retention policy in words
+func formatRetention(amount, days int) string {
+	var parts []string
+	if amount > 0 {
+		parts = append(parts, fmt.Sprintf("%d backups", amount))
+	}
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%d days", days))
+	}
+	if len(parts) == 0 {
+		return "keep all"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runDBBackupsCreate(cmd *cobra.Command, args []string) error {
+	dbUUID := args[0]
+
+	req := backupRequestFromFlags(cmd)
+	if req.Frequency == "" {
+		req.Frequency, _ = cmd.Flags().GetString("frequency")
+	}
+	if now, _ := cmd.Flags().GetBool("now"); now {
+		req.BackupNow = &now
+	}
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	var resp *api.CreateResponse
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "create-backup",
+			ActiveName:   "Creating backup schedule...",
+			CompleteName: "✓ Created backup schedule",
+			Action: func() error {
+				var err error
+				resp, err = client.CreateDatabaseBackup(dbUUID, req)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to create backup schedule")
+		return fmt.Errorf("failed to create backup schedule: %w", err)
+	}
+
+	ui.Spacer()
+	ui.Success("Backup schedule created")
+	if resp.UUID != "" {
+		ui.KeyValue("UUID", resp.UUID)
+	}
+	ui.KeyValue("Frequency", req.Frequency)
+
+	return nil
+}
+
+func runDBBackupsUpdate(cmd *cobra.Command, args []string) error {
+	dbUUID, backupUUID := args[0], args[1]
+
+	req := backupRequestFromFlags(cmd)
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "update-backup",
+			ActiveName:   "Updating backup schedule...",
+			CompleteName: "✓ Updated backup schedule",
+			Action: func() error {
+				return client.UpdateDatabaseBackup(dbUUID, backupUUID, req)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to update backup schedule")
+		return fmt.Errorf("failed to update backup schedule: %w", err)
+	}
+
+	return nil
+}
+
+func runDBBackupsRemove(cmd *cobra.Command, args []string) error {
+	dbUUID, backupUUID := args[0], args[1]
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := ui.ConfirmAction("delete", "backup schedule "+shortUUID(backupUUID))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		ui.Dim("Cancelled")
+		return nil
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "delete-backup",
+			ActiveName:   "Deleting backup schedule...",
+			CompleteName: "✓ Deleted backup schedule",
+			Action: func() error {
+				return client.DeleteDatabaseBackup(dbUUID, backupUUID)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to delete backup schedule")
+		return fmt.Errorf("failed to delete backup schedule: %w", err)
+	}
+
+	return nil
+}
+
+func runDBBackupsRun(cmd *cobra.Command, args []string) error {
+	dbUUID := args[0]
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	backupUUID, err := resolveBackupSchedule(client, dbUUID, args[1:])
+	if err != nil {
+		return err
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "run-backup",
+			ActiveName:   "Triggering backup...",
+			CompleteName: "✓ Backup started",
+			Action: func() error {
+				return client.TriggerDatabaseBackup(dbUUID, backupUUID)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to trigger backup")
+		return fmt.Errorf("failed to trigger backup: %w", err)
+	}
+
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s db backups executions %s %s' to follow its progress", execName(), dbUUID, backupUUID),
+	})
+	return nil
+}
+
+func runDBBackupsExecutions(cmd *cobra.Command, args []string) error {
+	dbUUID := args[0]
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	backupUUID, err := resolveBackupSchedule(client, dbUUID, args[1:])
+	if err != nil {
+		return err
+	}
+
+	ui.Section("Backup Runs")
+
+	var executions []api.BackupExecution
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-executions",
+			ActiveName:   "Loading backup runs...",
+			CompleteName: "✓ Loaded backup runs",
+			Action: func() error {
+				var err error
+				executions, err = client.ListBackupExecutions(dbUUID, backupUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load backup runs")
+		return fmt.Errorf("failed to list backup executions: %w", err)
+	}
+
+	if len(executions) == 0 {
+		ui.Dim("No backup runs yet")
+		return nil
+	}
+
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "UUID"},
+		{Name: "Status", Status: true},
+		{Name: "Database"},
+		{Name: "Size"},
+		{Name: "Created"},
+	}}
+	for _, e := range executions {
+		table.Rows = append(table.Rows, []string{
+			e.UUID,
+			e.Status,
+			e.DatabaseName,
+			formatBytes(e.Size),
+			e.CreatedAt,
+		})
+	}
+
+	ui.Spacer()
+	return printTable(cmd, table)
+}
+
+func runDBBackupsDownload(cmd *cobra.Command, args []string) error {
+	dbUUID, executionUUID := args[0], args[1]
+	output, _ := cmd.Flags().GetString("output")
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	execution, err := findBackupExecution(client, dbUUID, executionUUID)
+	if err != nil {
+		return err
+	}
+
+	server, err := backupServer(cmd, client)
+	if err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(output); err == nil && info.IsDir() {
+		output = filepath.Join(output, filepath.Base(execution.Filename))
+	}
+
+	identity, _ := cmd.Flags().GetString("identity")
+	opts, target := serverSSHTarget(server, identity)
+	scpArgs := append(opts, fmt.Sprintf("%s:%s", target, execution.Filename), output)
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "download-backup",
+			ActiveName:   fmt.Sprintf("Downloading %s...", filepath.Base(execution.Filename)),
+			CompleteName: "✓ Downloaded backup",
+			Action: func() error {
+				out, err := exec.Command("scp", scpArgs...).CombinedOutput()
+				if err != nil {
+					return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to download backup")
+		return fmt.Errorf("failed to download backup: %w", err)
+	}
+
+	ui.Spacer()
+	ui.KeyValue("File", output)
+	ui.KeyValue("Size", formatBytes(execution.Size))
+	return nil
+}
+
+func runDBBackupsRestore(cmd *cobra.Command, args []string) error {
+	dbUUID, executionUUID := args[0], args[1]
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	database, err := client.GetDatabase(dbUUID)
+	if err != nil {
+		return fmt.Errorf("failed to get database: %w", err)
+	}
+
+	execution, err := findBackupExecution(client, dbUUID, executionUUID)
+	if err != nil {
+		return err
+	}
+
+	restore, err := restoreCommand(database.Engine(), execution.DatabaseName)
+	if err != nil {
+		return err
+	}
+
+	server, err := backupServer(cmd, client)
+	if err != nil {
+		return err
+	}
+
+	if !skipConfirm {
+		ui.Warning(fmt.Sprintf("This overwrites data in %s with the backup from %s", database.Name, execution.CreatedAt))
+		confirmed, err := ui.ConfirmAction("restore", "database "+database.Name)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Dim("Cancelled")
+			return nil
+		}
+	}
+
+	file := utils.ShellQuote(execution.Filename)
+	reader := "cat " + file
+	if strings.HasSuffix(execution.Filename, ".gz") && database.Engine() != api.DatabaseMongoDB {
+		reader = "gunzip -c " + file
+	}
+	remote := fmt.Sprintf("%s | docker exec -i %s sh -c %s", reader, utils.ShellQuote(database.UUID), utils.ShellQuote(restore))
+
+	identity, _ := cmd.Flags().GetString("identity")
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "restore-backup",
+			ActiveName:   "Restoring backup...",
+			CompleteName: "✓ Restored backup",
+			Action: func() error {
+				_, err := runServerCommand(server, identity, remote)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to restore backup")
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	ui.Success(fmt.Sprintf("Restored %s into %s", filepath.Base(execution.Filename), database.Name))
+	return nil
+}
+
+// dbNamePattern guards database names interpolated into restore commands
+var dbNamePattern = regexp.MustCompile(`^[A-Za-z0-9_\-]*$`)
+
+// restoreCommand returns the in-container shell command that reads a backup from stdin
+func restoreCommand(engine, dbName string) (string, error) {
+	if !dbNamePattern.MatchString(dbName) {
+		return "", fmt.Errorf("refusing to restore into database with unexpected name %q", dbName)
+	}
+
+	switch engine {
+	case api.DatabasePostgreSQL:
+		if dbName == "" {
+			return `psql -U "$POSTGRES_USER" -d postgres`, nil
+		}
+		return fmt.Sprintf(`pg_restore --clean --if-exists -U "$POSTGRES_USER" -d %s`, dbName), nil
+	case api.DatabaseMySQL:
+		return fmt.Sprintf(`mysql -u root -p"$MYSQL_ROOT_PASSWORD" %s`, dbName), nil
+	case api.DatabaseMariaDB:
+		return fmt.Sprintf(`mariadb -u root -p"$MARIADB_ROOT_PASSWORD" %s`, dbName), nil
+	case api.DatabaseMongoDB:
+		return `mongorestore --drop --gzip --archive -u "$MONGO_INITDB_ROOT_USERNAME" -p "$MONGO_INITDB_ROOT_PASSWORD" --authenticationDatabase admin`, nil
+	default:
+		return "", fmt.Errorf("restore is not supported for %s databases", engine)
+	}
+}
+
+// resolveBackupSchedule returns the schedule UUID from args, or picks one for the database
+func resolveBackupSchedule(client *api.Client, dbUUID string, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	backups, err := client.ListDatabaseBackups(dbUUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	switch len(backups) {
+	case 0:
+		return "", fmt.Errorf("no backup schedules configured: run '%s db backups create %s' first", execName(), dbUUID)
+	case 1:
+		return backups[0].UUID, nil
+	}
+
+	options := make(map[string]string, len(backups))
+	for _, b := range backups {
+		options[b.UUID] = fmt.Sprintf("%s (%s)", b.Frequency, shortUUID(b.UUID))
+	}
+	return ui.SelectWithKeys("Select backup schedule:", options)
+}
+
+// findBackupExecution looks up an execution across all backup schedules of a database
+func findBackupExecution(client *api.Client, dbUUID, executionUUID string) (*api.BackupExecution, error) {
+	backups, err := client.ListDatabaseBackups(dbUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	for _, b := range backups {
+		executions, err := client.ListBackupExecutions(dbUUID, b.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list backup executions: %w", err)
+		}
+		for i := range executions {
+			e := &executions[i]
+			if e.UUID != executionUUID {
+				continue
+			}
+			if e.LocalStorageDeleted || e.Filename == "" {
+				return nil, fmt.Errorf("backup %s is no longer stored on the server", shortUUID(executionUUID))
+			}
+			return e, nil
+		}
+	}
+
+	return nil, fmt.Errorf("backup execution not found: %s", executionUUID)
+}
+
+// backupServer returns the server from --server, prompting when it is not set
+func backupServer(cmd *cobra.Command, client *api.Client) (*api.Server, error) {
+	serverUUID, _ := cmd.Flags().GetString("server")
+	if serverUUID == "" {
+		var err error
+		serverUUID, err = promptServerUUID(client)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	server, err := client.GetServer(serverUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server: %w", err)
+	}
+	if server.IP == "" {
+		return nil, fmt.Errorf("server %s has no IP address", serverUUID)
+	}
+	return server, nil
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/entro314-labs/cool-kit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// databaseDefaultPorts are the ports each engine listens on inside its container
+var databaseDefaultPorts = map[string]int{
+	api.DatabasePostgreSQL: 5432,
+	api.DatabaseMySQL:      3306,
+	api.DatabaseMariaDB:    3306,
+	api.DatabaseMongoDB:    27017,
+	api.DatabaseRedis:      6379,
+	api.DatabaseKeyDB:      6379,
+	api.DatabaseDragonfly:  6379,
+	api.DatabaseClickHouse: 9000,
+}
+
+var dbTunnelCmd = &cobra.Command{
+	Use:   "tunnel DATABASE",
+	Short: "Forward a local port to a database over ssh",
+	Long: `Open a local port forwarded over ssh to a database container on its Coolify
+server, so migrations and GUI clients can reach databases that are not public.
+
+DATABASE is a database UUID, name or engine (postgres, mysql, redis, ...). The
+tunnel stays open until Ctrl+C. A connection string pointing at the local port
+is printed once it is ready.
+
+Examples:
+  cool-kit db tunnel postgres
+  cool-kit db tunnel app-db --port 15432
+  cool-kit db tunnel <uuid> -i ~/.ssh/coolify`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDBTunnel,
+}
+
+func init() {
+	dbCmd.AddCommand(dbTunnelCmd)
+
+	dbTunnelCmd.Flags().Int("port", 0, "Local port (defaults to the engine's port, or a free one)")
+	dbTunnelCmd.Flags().StringP("identity", "i", "", "SSH private key file")
+	dbTunnelCmd.Flags().String("server", "", "Server UUID (defaults to the database's server)")
+}
+
+func runDBTunnel(cmd *cobra.Command, args []string) error {
+	localPort, _ := cmd.Flags().GetInt("port")
+	identity, _ := cmd.Flags().GetString("identity")
+	serverUUID, _ := cmd.Flags().GetString("server")
+
+	if localPort < 0 || localPort > 65535 {
+		return fmt.Errorf("invalid port: %d", localPort)
+	}
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	database, err := findTunnelDatabase(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	server, err := resourceServer(client, database.DestinationID, serverUUID)
+	if err != nil {
+		return err
+	}
+
+	remotePort := databasePort(database)
+	if remotePort == 0 {
+		return fmt.Errorf("unknown port for %s database %s", database.Engine(), database.Name)
+	}
+
+	var containerIP string
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "find-container",
+			ActiveName:   "Finding database container...",
+			CompleteName: "✓ Found database container",
+			Action: func() error {
+				var err error
+				containerIP, err = databaseContainerIP(server, identity, database.UUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to find database container")
+		return fmt.Errorf("failed to find database container: %w", err)
+	}
+
+	if localPort == 0 {
+		localPort, err = tunnelLocalPort(remotePort)
+		if err != nil {
+			return err
+		}
+	}
+
+	opts, target := serverSSHTarget(server, identity)
+	sshArgs := append(opts,
+		"-N",
+		"-o", "ExitOnForwardFailure=yes",
+		"-L", fmt.Sprintf("127.0.0.1:%d:%s:%d", localPort, containerIP, remotePort),
+		target,
+	)
+
+	ssh := exec.Command("ssh", sshArgs...)
+	ssh.Stdin = os.Stdin
+	ssh.Stderr = os.Stderr
+	if err := ssh.Start(); err != nil {
+		return fmt.Errorf("failed to run ssh: %w", err)
+	}
+
+	// Closing the tunnel with Ctrl+C is the normal way to stop
+	interrupted := make(chan struct{})
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	go func() {
+		<-sigs
+		close(interrupted)
+		_ = ssh.Process.Kill()
+	}()
+
+	ui.Spacer()
+	ui.Section(fmt.Sprintf("Tunnel: %s", database.Name))
+	ui.KeyValue("Engine", database.Engine())
+	ui.KeyValue("Local", fmt.Sprintf("127.0.0.1:%d", localPort))
+	ui.KeyValue("Remote", fmt.Sprintf("%s → %s:%d", server.Name, shortUUID(database.UUID), remotePort))
+	if conn := tunnelConnectionString(database.InternalDBURL, localPort); conn != "" {
+		ui.KeyValue("Connection", conn)
+	}
+	ui.Spacer()
+	ui.Dim("Tunnel open (Ctrl+C to close)")
+
+	err = ssh.Wait()
+	select {
+	case <-interrupted:
+		ui.Spacer()
+		ui.Dim("Tunnel closed")
+		return nil
+	default:
+	}
+	if err != nil {
+		return fmt.Errorf("tunnel closed: %w", err)
+	}
+	return nil
+}
+
+// findTunnelDatabase returns the database matching a UUID, name or engine,
+// prompting when several match
+func findTunnelDatabase(client *api.Client, query string) (*api.Database, error) {
+	databases, err := client.ListDatabases()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+
+	query = strings.ToLower(query)
+	var matches []api.Database
+	for _, db := range databases {
+		if db.UUID == query || strings.EqualFold(db.Name, query) {
+			return &db, nil
+		}
+		if strings.HasPrefix(db.Engine(), query) {
+			matches = append(matches, db)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("database not found: %s", query)
+	case 1:
+		return &matches[0], nil
+	}
+
+	options := make(map[string]string, len(matches))
+	for _, db := range matches {
+		options[db.UUID] = fmt.Sprintf("%s (%s, %s)", db.Name, db.Engine(), shortUUID(db.UUID))
+	}
+	uuid, err := ui.SelectWithKeys("Select database:", options)
+	if err != nil {
+		return nil, err
+	}
+	for i := range matches {
+		if matches[i].UUID == uuid {
+			return &matches[i], nil
+		}
+	}
+	return nil, fmt.Errorf("database not found: %s", uuid)
+}
+
+// databasePort returns the port a database listens on inside its container
+func databasePort(database *api.Database) int {
+	if u, err := url.Parse(database.InternalDBURL); err == nil && u.Port() != "" {
+		if port, err := strconv.Atoi(u.Port()); err == nil {
+			return port
+		}
+	}
+	return databaseDefaultPorts[database.Engine()]
+}
+
+// databaseContainerIP returns the Docker network address of a database container
+func databaseContainerIP(server *api.Server, identity, uuid string) (string, error) {
+	remote := fmt.Sprintf("docker inspect -f '{{range .NetworkSettings.Networks}}{{.IPAddress}} {{end}}' %s", utils.ShellQuote(uuid))
+	out, err := runServerCommand(server, identity, remote)
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("container %s has no network address; is the database running?", uuid)
+	}
+	return fields[0], nil
+}
+
+// tunnelLocalPort returns port when it is free locally, or any free port
+func tunnelLocalPort(port int) (int, error) {
+	if l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port)); err == nil {
+		l.Close()
+		return port, nil
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free local port: %w", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// tunnelConnectionString rewrites a database's internal URL to go through the tunnel
+func tunnelConnectionString(internalURL string, localPort int) string {
+	if internalURL == "" {
+		return ""
+	}
+	u, err := url.Parse(internalURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.Host = fmt.Sprintf("127.0.0.1:%d", localPort)
+	return u.String()
+}
@@ -3,10 +3,16 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
 	"github.com/entro314-labs/cool-kit/internal/appdeploy"
 	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/docker"
+	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/runs"
 	"github.com/entro314-labs/cool-kit/internal/smart"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 	"github.com/spf13/cobra"
@@ -15,6 +21,21 @@ import (
 var (
 	deployProdFlag    bool
 	deployPreviewFlag bool
+	deployAllowSecret bool
+	deployPRFlag      int
+	deployPreviewEnv  string
+	deployAllFlag     bool
+	deployLocalFlag   bool
+	deployWait        bool
+	deployTimeout     time.Duration
+	deployStrategy    string
+	deploySkipMigrate bool
+	deployPlanFlag    bool
+	deployScan        bool
+	deployScanSet     bool // --scan was passed, true or false
+	deploySeverity    string
+	deployRemoteBuild bool
+	deploySetup       appdeploy.SetupInputs
 )
 
 var deployCmd = &cobra.Command{
@@ -24,12 +45,90 @@ var deployCmd = &cobra.Command{
 
 Use --prod to explicitly deploy to production (default).
 Use --preview to create a preview deployment for testing.
+Use --pr N to create or refresh the preview deployment of GitHub pull request N.
+
+Branches can be mapped to Coolify environments in the project's
+"branch_environments", like {"main": "production", "develop": "staging"}.
+Deploying a mapped branch uses its environment's application, created along
+with the environment on first use. --prod always deploys to production.
+Variables in .env.preview (or --preview-env) are applied as preview variables first.
+
+In a monorepo, each app has its own config in .coolify-deployer/apps/NAME.json
+with a base directory. Use --app NAME to deploy (or set up) one app and --all
+to deploy every app with changes since its last successful deployment. Changes
+are matched against the app's "watch_paths", or its base directory.
+
+Git deployments commit and push the whole working tree. Files are scanned
+for credentials first and the push is blocked if any are found; tune the
+rules in the project's "secret_scan" config or pass --allow-secrets.
+
+Docker images are scanned with Trivy before they're pushed when the project
+has an "image_scan" config or --scan is passed; vulnerabilities at or above
+--severity (default CRITICAL) block the push. --remote-build builds and
+pushes the image on the instance's build server over ssh instead, for slow
+connections and machines of another architecture.
+
+Deployments wait in the server's build queue when its concurrent build limit
+is reached; the queue position and what is building are shown meanwhile. By
+default the command waits up to --timeout for the deployment to finish and
+fails if it doesn't, so CI jobs behave predictably. Use --wait=false to return
+as soon as the deployment is queued.
+
+Checks in the project's "smoke_tests" config run once the deployment
+finishes: HTTP requests with an expected status and body, and local commands
+that get the app's URL as $APP_URL. A failing check fails the deploy, and with
+"rollback": true the previous version is redeployed.
+
+The migrations detected at setup ("migrate_command" in the project config)
+run in the application once a production deployment finishes, and seed data
+after the first one. Output is shown as it would be from 'cool-kit exec'; a
+failing migration fails the deploy. Pass --skip-migrations to leave them to
+another step, like a CI job that migrates separately.
+
+Use --plan to preview a deployment without touching Coolify: the services
+that would be created (engine, version and a memory estimate), the variables
+that would be set on the application, the existing resources that would be
+reused and the migrations that would run. Nothing is created or changed.
+
+Set "strategy": "blue-green" in the project config (or pass --strategy) for
+zero-downtime production deployments: a second application is created next
+to the live one and deployed, the smoke tests run against it on its own
+domain ("blue_green": {"staging_domain": ...}, or the domain Coolify
+generates), and only then are the live domains moved to it and the old
+application stopped and deleted ("keep_previous": true only stops it).
+Settings and environment variables are copied over, persistent volumes are
+not. Coolify has no weighted routing, so canary releases aren't supported.
+
+Use --local to deploy the working directory without committing to or pushing
+your branch. Docker projects are built from the files git would push, so
+ignored files stay out of the build. Git projects push a one-off snapshot to
+the "cdp-local" branch, deploy it, and switch the application back to its
+branch afterwards.
+
+The first deployment of a project runs its setup. The setup's questions can
+be answered with --method, --server, --destination, --project, --port,
+--branch, --domain and --platform. With --non-interactive (or in CI) the rest
+take their defaults: a Git deployment, the only server and destination, a
+project named after the directory, and no optional services or workers. The
+confirmation of later deployments needs --yes.
 
 Examples:
   cool-kit deploy              # Deploy to production (default)
   cool-kit deploy --prod       # Explicitly deploy to production
-  cool-kit deploy --preview    # Create preview deployment`,
+  cool-kit deploy --preview    # Create preview deployment
+  cool-kit deploy --pr 42      # Deploy the preview of pull request #42
+  cool-kit deploy --app api    # Deploy the "api" app of a monorepo
+  cool-kit deploy --all        # Deploy the monorepo apps that changed
+  cool-kit deploy --local      # Deploy uncommitted work as it is
+  cool-kit deploy --timeout 5m # Fail if not deployed within 5 minutes
+  cool-kit deploy --skip-migrations
+  cool-kit deploy --plan       # Show what would be created and reused
+  cool-kit deploy --strategy blue-green
+  cool-kit deploy --scan --severity HIGH
+  cool-kit deploy --remote-build
+  cool-kit deploy --non-interactive --yes --server prod-1 --domain app.example.com`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		deployScanSet = cmd.Flags().Changed("scan")
 		return runDeploy()
 	},
 }
@@ -37,13 +136,47 @@ Examples:
 func init() {
 	deployCmd.Flags().BoolVar(&deployProdFlag, "prod", false, "Deploy to production (default)")
 	deployCmd.Flags().BoolVar(&deployPreviewFlag, "preview", false, "Create preview deployment")
+	deployCmd.Flags().IntVar(&deployPRFlag, "pr", 0, "Deploy a preview of this pull request")
+	deployCmd.Flags().StringVar(&deployPreviewEnv, "preview-env", defaultPreviewEnvFile, "Preview variables to apply before a --pr deployment")
+	deployCmd.Flags().BoolVar(&deployAllowSecret, "allow-secrets", false, "Push even if the secret scan finds credentials")
+	deployCmd.Flags().BoolVar(&deployAllFlag, "all", false, "Deploy every monorepo app changed since its last deployment")
+	deployCmd.Flags().BoolVar(&deployLocalFlag, "local", false, "Deploy the working directory without pushing the current branch")
+	deployCmd.Flags().BoolVar(&deployWait, "wait", true, "Wait for the deployment to finish")
+	deployCmd.Flags().DurationVar(&deployTimeout, "timeout", appdeploy.DefaultWatchTimeout, "Maximum time to wait for the deployment, including time in the build queue")
+	deployCmd.Flags().BoolVar(&deploySkipMigrate, "skip-migrations", false, "Don't run the project's migrations after the deployment")
+	deployCmd.Flags().BoolVar(&deployScan, "scan", false, "Scan the docker image with Trivy before pushing it (default from project config)")
+	deployCmd.Flags().StringVar(&deploySeverity, "severity", "", "Lowest vulnerability severity that blocks the push: LOW, MEDIUM, HIGH or CRITICAL")
+	deployCmd.Flags().BoolVar(&deployRemoteBuild, "remote-build", false, "Build and push the docker image on the instance's build server instead of locally")
+	deployCmd.Flags().BoolVar(&deployPlanFlag, "plan", false, "Show what the deployment would create and reuse without calling the API")
+	deployCmd.Flags().StringVar(&deployStrategy, "strategy", "", "Production deployment strategy: recreate or blue-green (default from project config)")
+
+	// Answers for the first-time setup
+	deployCmd.Flags().StringVar(&deploySetup.Method, "method", "", "Setup: deployment method, git, docker or buildpacks")
+	deployCmd.Flags().StringVar(&deploySetup.Server, "server", "", "Setup: server to deploy to, by name, UUID or IP")
+	deployCmd.Flags().StringVar(&deploySetup.Destination, "destination", "", "Setup: server destination, by name or UUID")
+	deployCmd.Flags().StringVar(&deploySetup.Project, "project", "", "Setup: Coolify project, created if it doesn't exist (default the directory name)")
+	deployCmd.Flags().StringVar(&deploySetup.Port, "port", "", "Setup: port the application listens on (default detected)")
+	deployCmd.Flags().StringVar(&deploySetup.Branch, "branch", "", "Setup: Git branch to deploy")
+	deployCmd.Flags().StringVar(&deploySetup.Domain, "domain", "", "Setup: custom domain, or service=domain,... for a compose stack")
+	deployCmd.Flags().StringVar(&deploySetup.Platform, "platform", "", "Setup: Docker build platform, like linux/arm64")
 }
 
 func runDeploy() error {
+	if deployPlanFlag {
+		return runDeployPlan()
+	}
+
 	if err := checkLogin(); err != nil {
 		return err
 	}
 
+	if deployAllFlag {
+		if config.SelectedApp() != "" {
+			return fmt.Errorf("cannot use both --app and --all flags")
+		}
+		return runDeployAll()
+	}
+
 	globalCfg, err := config.LoadGlobal()
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
@@ -54,17 +187,22 @@ func runDeploy() error {
 		return fmt.Errorf("failed to load project configuration: %w", err)
 	}
 
-	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
 
 	isFirstDeploy := false
 	var deploymentConfig *smart.DeploymentConfig
 
 	// First-time setup if no project config exists
 	if projectCfg == nil {
-		ui.Section("New Project Setup")
-		ui.Dim("Let's configure your project for deployment")
+		var setupResult *appdeploy.SetupResult
+		if app := config.SelectedApp(); app != "" {
+			setupResult, err = appdeploy.SetupApp(client, globalCfg, app, loadMonorepoBase(), deploySetup)
+		} else {
+			ui.Section("New Project Setup")
+			ui.Dim("Let's configure your project for deployment")
 
-		setupResult, err := appdeploy.FirstTimeSetup(client, globalCfg)
+			setupResult, err = appdeploy.FirstTimeSetup(client, globalCfg, deploySetup)
+		}
 		if err != nil {
 			return err
 		}
@@ -73,22 +211,12 @@ func runDeploy() error {
 		isFirstDeploy = true
 	}
 
-	// Determine deployment type based on flags
-	var prNumber int
-	var deploymentType string
-
-	if deployPreviewFlag && deployProdFlag {
-		return fmt.Errorf("cannot use both --prod and --preview flags")
+	prNumber, deploymentType, err := deployTarget(projectCfg)
+	if err != nil {
+		return err
 	}
-
-	if deployPreviewFlag {
-		// Preview deployment - use PR number 1 for manual previews
-		prNumber = 1
-		deploymentType = "preview"
-	} else {
-		// Production deployment (default)
-		prNumber = 0
-		deploymentType = "production"
+	if prNumber == 0 && !deployProdFlag {
+		projectCfg, deploymentType = branchEnvironment(projectCfg, deploymentType)
 	}
 
 	ui.Spacer()
@@ -110,16 +238,173 @@ func runDeploy() error {
 	} else {
 		ui.Section("Deploy")
 	}
+
+	return deployProject(client, globalCfg, projectCfg, deploymentConfig, prNumber, deploymentType)
+}
+
+// runDeployPlan prints what the deployment would do, without calling the API
+func runDeployPlan() error {
+	if deployAllFlag {
+		return fmt.Errorf("cannot use both --plan and --all flags")
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load project configuration: %w", err)
+	}
+
+	target := projectCfg
+	if target == nil {
+		// Validate the flags against what the setup would save
+		target = &config.ProjectConfig{}
+	}
+	prNumber, deploymentType, err := deployTarget(target)
+	if err != nil {
+		return err
+	}
+
+	return appdeploy.PrintPlan(projectCfg, prNumber, deploymentType)
+}
+
+// deployTarget validates the deployment flags and returns the pull request
+// number (0 for production) and a label for the deployment type
+func deployTarget(projectCfg *config.ProjectConfig) (int, string, error) {
+	if deployPreviewFlag && deployProdFlag {
+		return 0, "", fmt.Errorf("cannot use both --prod and --preview flags")
+	}
+	if deployPRFlag < 0 {
+		return 0, "", fmt.Errorf("invalid pull request number: %d", deployPRFlag)
+	}
+	if deployPRFlag > 0 && deployProdFlag {
+		return 0, "", fmt.Errorf("cannot use both --prod and --pr flags")
+	}
+	if deployPRFlag > 0 && config.BuildsImage(projectCfg.DeployMethod) {
+		return 0, "", fmt.Errorf("pull request previews require a Git deployment")
+	}
+	if deployLocalFlag && deployPRFlag > 0 {
+		return 0, "", fmt.Errorf("cannot use both --local and --pr flags")
+	}
+	if deployRemoteBuild && projectCfg.DeployMethod == config.DeployMethodGit {
+		return 0, "", fmt.Errorf("--remote-build only applies to image deployments: git deployments already build on Coolify")
+	}
+	if deploySeverity != "" && !slices.Contains(docker.Severities, strings.ToUpper(deploySeverity)) {
+		return 0, "", fmt.Errorf("invalid severity %q: use %s", deploySeverity, strings.Join(docker.Severities, ", "))
+	}
+	switch deployStrategy {
+	case "", config.StrategyRecreate:
+	case config.StrategyBlueGreen:
+		if deployLocalFlag {
+			return 0, "", fmt.Errorf("cannot use both --local and --strategy %s", config.StrategyBlueGreen)
+		}
+		if deployPRFlag > 0 || deployPreviewFlag {
+			return 0, "", fmt.Errorf("the %s strategy only applies to production deployments", config.StrategyBlueGreen)
+		}
+	default:
+		return 0, "", fmt.Errorf("invalid strategy %q: use %s or %s", deployStrategy, config.StrategyRecreate, config.StrategyBlueGreen)
+	}
+
+	switch {
+	case deployPRFlag > 0:
+		return deployPRFlag, fmt.Sprintf("preview (PR #%d)", deployPRFlag), nil
+	case deployPreviewFlag:
+		// Preview deployment - use PR number 1 for manual previews
+		return 1, "preview", nil
+	default:
+		// Production deployment (default)
+		return 0, "production", nil
+	}
+}
+
+// applyBuildFlags applies --remote-build, and --scan and --severity over
+// the project's image_scan config, for this deployment
+func applyBuildFlags(projectCfg *config.ProjectConfig) {
+	if deployRemoteBuild {
+		projectCfg.ForceRemoteBuild()
+	}
+	if !deployScanSet && deploySeverity == "" {
+		return
+	}
+	scan := config.ImageScanConfig{}
+	if projectCfg.ImageScan != nil {
+		scan = *projectCfg.ImageScan
+	}
+	if deployScanSet {
+		scan.Disabled = !deployScan
+	}
+	if deploySeverity != "" {
+		scan.Severity = strings.ToUpper(deploySeverity)
+	}
+	projectCfg.OverrideImageScan(&scan)
+}
+
+// branchEnvironment switches to the environment the current branch is
+// mapped to in the project's "branch_environments"
+func branchEnvironment(projectCfg *config.ProjectConfig, deploymentType string) (*config.ProjectConfig, string) {
+	if len(projectCfg.BranchEnvironments) == 0 {
+		return projectCfg, deploymentType
+	}
+	branch, err := git.GetCurrentBranch(".")
+	if err != nil {
+		return projectCfg, deploymentType
+	}
+	env := projectCfg.BranchEnvironments[branch]
+	if env == "" {
+		return projectCfg, deploymentType
+	}
+	return projectCfg.ForEnvironment(env, branch), env
+}
+
+// deployProject deploys a configured project with its deploy method, saving
+// a transcript of the deployment
+func deployProject(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, prNumber int, deploymentType string) (err error) {
+	rec := ui.StartTranscript(runs.KindDeploy, projectCfg.Name, nil)
+	rec.Detail("type", deploymentType)
+	rec.Detail("method", projectCfg.DeployMethod)
+	rec.Detail("app", projectCfg.AppUUID)
+	defer func() {
+		rec.Finish(err)
+		ui.EndTranscript(rec)
+	}()
+
 	ui.KeyValue("Project", projectCfg.Name)
+	if app := config.SelectedApp(); app != "" {
+		ui.KeyValue("App", fmt.Sprintf("%s (%s)", app, projectCfg.SourceDir()))
+	}
 	ui.KeyValue("Type", deploymentType)
 	ui.KeyValue("Method", projectCfg.DeployMethod)
 
+	if prNumber > 0 && projectCfg.AppUUID != "" {
+		if err := applyPreviewEnvFile(client, projectCfg.AppUUID, deployPreviewEnv); err != nil {
+			return err
+		}
+	}
+
+	applyBuildFlags(projectCfg)
+
 	// Check verbose mode
 	verbose := IsVerbose()
+	watch := appdeploy.WatchOptions{NoWait: !deployWait, Timeout: deployTimeout, SkipMigrations: deploySkipMigrate, Transcript: rec}
+
+	if deployLocalFlag {
+		ui.KeyValue("Source", "working directory")
+		return appdeploy.DeployLocal(client, globalCfg, projectCfg, deploymentConfig, prNumber, deployAllowSecret, verbose, watch)
+	}
+
+	strategy := projectCfg.Strategy
+	if deployStrategy != "" {
+		strategy = deployStrategy
+	}
+	if strategy == config.StrategyBlueGreen && prNumber == 0 {
+		if projectCfg.AppUUID != "" {
+			ui.KeyValue("Strategy", strategy)
+			return appdeploy.DeployBlueGreen(client, globalCfg, projectCfg, deployAllowSecret, verbose, watch)
+		}
+		ui.Dim("First deployment: creating the application in place")
+	}
 
 	// Deploy based on method
-	if projectCfg.DeployMethod == config.DeployMethodDocker {
-		return appdeploy.DeployDocker(client, globalCfg, projectCfg, deploymentConfig, prNumber, verbose)
+	if config.BuildsImage(projectCfg.DeployMethod) {
+		return appdeploy.DeployDocker(client, globalCfg, projectCfg, deploymentConfig, prNumber, verbose, watch)
 	}
-	return appdeploy.DeployGit(client, globalCfg, projectCfg, deploymentConfig, prNumber, verbose)
+	return appdeploy.DeployGit(client, globalCfg, projectCfg, deploymentConfig, prNumber, deployAllowSecret, verbose, watch)
 }
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// monorepoApp is a configured monorepo app and whether it needs a deploy
+type monorepoApp struct {
+	name    string
+	cfg     *config.ProjectConfig
+	changed bool
+	reason  string
+}
+
+// runDeployAll deploys every monorepo app with changes since its last
+// successful production deployment
+func runDeployAll() error {
+	if _, err := config.LoadGlobal(); err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	names, err := config.ListApps()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		ui.Error("No monorepo apps configured")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s deploy --app NAME' to set up an app", execName()),
+		})
+		return fmt.Errorf("no apps found in .coolify-deployer/apps")
+	}
+
+	ui.Section("Monorepo Deploy")
+
+	var apps []monorepoApp
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "detect-changes",
+			ActiveName:   "Detecting changed apps...",
+			CompleteName: "✓ Detected changed apps",
+			Action: func() error {
+				var err error
+				apps, err = detectChangedApps(names)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to detect changed apps")
+		return fmt.Errorf("failed to detect changed apps: %w", err)
+	}
+
+	var changed []monorepoApp
+	rows := make([][]string, 0, len(apps))
+	for _, app := range apps {
+		status := ui.DimStyle.Render(app.reason)
+		if app.changed {
+			status = ui.WarningStyle.Render(app.reason)
+			changed = append(changed, app)
+		}
+		rows = append(rows, []string{app.name, app.cfg.SourceDir(), status})
+	}
+	ui.Spacer()
+	ui.Table([]string{"App", "Directory", "Changes"}, rows)
+
+	if len(changed) == 0 {
+		ui.Spacer()
+		ui.Success("All apps are up to date")
+		return nil
+	}
+
+	// Flags apply to every app, so validate them against each before deploying
+	var (
+		prNumber       int
+		deploymentType string
+	)
+	for _, app := range changed {
+		if prNumber, deploymentType, err = deployTarget(app.cfg); err != nil {
+			return fmt.Errorf("%s: %w", app.name, err)
+		}
+	}
+
+	ui.Spacer()
+	confirmed, err := ui.Confirm(fmt.Sprintf("Deploy %d app(s) to %s?", len(changed), deploymentType))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		ui.Dim("Deployment canceled")
+		return nil
+	}
+
+	results := make([][]string, 0, len(changed))
+	failed := 0
+	for i, app := range changed {
+		if err := config.SelectApp(app.name); err != nil {
+			return err
+		}
+
+		ui.Spacer()
+		ui.StepProgress(i+1, len(changed), app.name)
+
+		result := ui.SuccessStyle.Render("deployed")
+		globalCfg, client, err := appClient()
+		if err == nil {
+			err = deployProject(client, globalCfg, app.cfg, nil, prNumber, deploymentType)
+		}
+		if err != nil {
+			failed++
+			result = ui.ErrorStyle.Render(err.Error())
+		}
+		results = append(results, []string{app.name, result})
+	}
+
+	ui.Spacer()
+	ui.Section("Summary")
+	ui.Table([]string{"App", "Result"}, results)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d app deployments failed", failed, len(changed))
+	}
+	return nil
+}
+
+// detectChangedApps loads each app's config and compares the working tree
+// with the commit of its last successful production deployment
+func detectChangedApps(names []string) ([]monorepoApp, error) {
+	defer config.SelectApp("")
+
+	apps := make([]monorepoApp, 0, len(names))
+	for _, name := range names {
+		if err := config.SelectApp(name); err != nil {
+			return nil, err
+		}
+		cfg, err := config.LoadProject()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load app %s: %w", name, err)
+		}
+
+		_, client, err := appClient()
+		if err != nil {
+			return nil, fmt.Errorf("app %s: %w", name, err)
+		}
+		app := monorepoApp{name: name, cfg: cfg}
+		app.changed, app.reason, err = appChanged(client, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check app %s: %w", name, err)
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// appClient returns the login config and API client of the selected app,
+// which may pin its own instance
+func appClient() (*config.GlobalConfig, *api.Client, error) {
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return nil, nil, err
+	}
+	return globalCfg, newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken), nil
+}
+
+// appChanged reports whether an app has changes to deploy, and why
+func appChanged(client *api.Client, cfg *config.ProjectConfig) (bool, string, error) {
+	if cfg.AppUUID == "" {
+		return true, "new app", nil
+	}
+
+	deployments, err := client.ListDeployments(cfg.AppUUID)
+	if err != nil {
+		return false, "", err
+	}
+
+	var lastCommit string
+	for _, d := range deployments {
+		if strings.EqualFold(d.Status, "finished") && d.PullRequest() == 0 && d.CommitSHA() != "" {
+			lastCommit = d.CommitSHA()
+			break
+		}
+	}
+	if lastCommit == "" {
+		return true, "never deployed", nil
+	}
+
+	files, err := git.ChangedFiles(".", lastCommit)
+	if err != nil {
+		return true, fmt.Sprintf("%s not found locally", shortCommit(lastCommit)), nil
+	}
+
+	matched := 0
+	for _, f := range files {
+		if cfg.MatchesPath(f) {
+			matched++
+		}
+	}
+	if matched == 0 {
+		return false, fmt.Sprintf("unchanged since %s", shortCommit(lastCommit)), nil
+	}
+	return true, fmt.Sprintf("%d file(s) changed since %s", matched, shortCommit(lastCommit)), nil
+}
+
+// loadMonorepoBase returns a config to copy shared settings from when a new
+// monorepo app is set up: the repository's project config or the first app's
+func loadMonorepoBase() *config.ProjectConfig {
+	selected := config.SelectedApp()
+	defer config.SelectApp(selected)
+
+	_ = config.SelectApp("")
+	if cfg, err := config.LoadProject(); err == nil {
+		return cfg
+	}
+
+	names, err := config.ListApps()
+	if err != nil {
+		return nil
+	}
+	for _, name := range names {
+		if name == selected {
+			continue
+		}
+		_ = config.SelectApp(name)
+		if cfg, err := config.LoadProject(); err == nil {
+			return cfg
+		}
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var deploymentsCmd = &cobra.Command{
+	Use:     "deployments",
+	Aliases: []string{"history"},
+	Short:   "Show deployment history",
+	Long: `Show past deployments of the linked application.
+
+Authors are read from the local git repository when the deployed commits are
+available in it.
+
+Available Commands:
+  deployments diff A B  - Compare two deployments`,
+	Args: cobra.NoArgs,
+	RunE: runDeployments,
+}
+
+var deploymentsDiffCmd = &cobra.Command{
+	Use:   "diff A B",
+	Short: "Show commits and variable changes between two deployments",
+	Long: `Show the commit range and environment variable changes between two deployments.
+
+A and B are deployment UUIDs or unique prefixes of them. Commits are read from
+the local git repository. Coolify does not keep variable history, so the
+variables listed are those created or updated between the two deployments;
+deleted variables are not shown.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDeploymentsDiff,
+}
+
+func init() {
+	deploymentsCmd.AddCommand(deploymentsDiffCmd)
+
+	deploymentsCmd.Flags().IntP("limit", "n", 20, "Number of deployments to show")
+	addTableFlags(deploymentsCmd)
+}
+
+func runDeployments(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	format, err := structuredFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	if format == "" {
+		ui.Section("Deployments")
+	}
+
+	var deployments []api.Deployment
+	err = loadTask(format, ui.Task{
+		Name:         "load-deployments",
+		ActiveName:   "Loading deployments...",
+		CompleteName: "✓ Loaded deployments",
+		Action: func() error {
+			var err error
+			deployments, err = client.ListDeployments(appUUID)
+			return err
+		},
+	})
+	if err != nil {
+		if format == "" {
+			ui.Error("Failed to load deployments")
+		}
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	if len(deployments) == 0 && format == "" {
+		ui.Dim("No deployments found")
+		return nil
+	}
+	if limit > 0 && len(deployments) > limit {
+		deployments = deployments[:limit]
+	}
+
+	authors := make(map[string]string)
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "Status", Status: true},
+		{Name: "Target"},
+		{Name: "Started"},
+		{Name: "Duration"},
+		{Name: "Commit"},
+		{Name: "Author", Wide: true},
+		{Name: "Message", Wide: true},
+		{Name: "Deployment"},
+	}}
+	records := make([]deploymentRecord, 0, len(deployments))
+	for _, d := range deployments {
+		commit := d.CommitSHA()
+		author, ok := authors[commit]
+		if !ok && commit != "" {
+			if info, err := git.GetCommitInfo(".", commit); err == nil {
+				author = info.Author
+			}
+			authors[commit] = author
+		}
+		records = append(records, newDeploymentRecord(&d, author))
+
+		target := "production"
+		if pr := d.PullRequest(); pr > 0 {
+			target = fmt.Sprintf("PR #%d", pr)
+		}
+
+		msg, _, _ := strings.Cut(d.CommitMessage, "\n")
+
+		table.Rows = append(table.Rows, []string{
+			d.Status,
+			target,
+			formatDeployTime(d.Created()),
+			formatDuration(d.Duration()),
+			orDash(shortCommit(commit)),
+			orDash(author),
+			msg,
+			d.DeploymentUUID,
+		})
+	}
+
+	if format != "" {
+		return formatOutput(format, records)
+	}
+
+	ui.Spacer()
+	if err := printTable(cmd, table); err != nil {
+		return err
+	}
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s deployments diff A B' to compare two deployments", execName()),
+	})
+	return nil
+}
+
+func runDeploymentsDiff(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	var (
+		deployments []api.Deployment
+		envs        []api.EnvironmentVariable
+	)
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-deployments",
+			ActiveName:   "Loading deployments...",
+			CompleteName: "✓ Loaded deployments",
+			Action: func() error {
+				var err error
+				deployments, err = client.ListDeployments(appUUID)
+				return err
+			},
+		},
+		{
+			Name:         "load-envs",
+			ActiveName:   "Loading environment variables...",
+			CompleteName: "✓ Loaded environment variables",
+			Action: func() error {
+				var err error
+				envs, err = client.ListApplicationEnvs(context.Background(), appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load deployments")
+		return fmt.Errorf("failed to load deployments: %w", err)
+	}
+
+	from, err := findDeployment(deployments, args[0])
+	if err != nil {
+		return err
+	}
+	to, err := findDeployment(deployments, args[1])
+	if err != nil {
+		return err
+	}
+	if to.Created().Before(from.Created()) {
+		from, to = to, from
+	}
+
+	ui.Section("Deployment Diff")
+	ui.KeyValue("From", describeDeployment(from))
+	ui.KeyValue("To", describeDeployment(to))
+
+	printCommitRange(from, to)
+	printEnvChangesBetween(envs, from, to)
+	return nil
+}
+
+// findDeployment matches a deployment UUID or a unique prefix of one
+func findDeployment(deployments []api.Deployment, ref string) (*api.Deployment, error) {
+	var match *api.Deployment
+	for i := range deployments {
+		if !strings.HasPrefix(deployments[i].DeploymentUUID, ref) {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("%q matches more than one deployment; use a longer prefix", ref)
+		}
+		match = &deployments[i]
+	}
+	if match == nil {
+		return nil, fmt.Errorf("deployment %q not found", ref)
+	}
+	return match, nil
+}
+
+// describeDeployment returns a one-line summary of a deployment
+func describeDeployment(d *api.Deployment) string {
+	return fmt.Sprintf("%s  %s  %s  %s",
+		d.DeploymentUUID,
+		orDash(shortCommit(d.CommitSHA())),
+		formatDeployTime(d.Created()),
+		styleDeploymentStatus(d.Status))
+}
+
+// printCommitRange lists the commits deployed between two deployments
+func printCommitRange(from, to *api.Deployment) {
+	fromSHA, toSHA := from.CommitSHA(), to.CommitSHA()
+
+	ui.Spacer()
+	ui.Section("Commits")
+
+	switch {
+	case fromSHA == "" || toSHA == "":
+		ui.Dim("Commit information is not available for these deployments")
+		return
+	case fromSHA == toSHA:
+		ui.Dim(fmt.Sprintf("Both deployments are at %s", shortCommit(toSHA)))
+		return
+	}
+
+	ui.KeyValue("Range", fmt.Sprintf("%s..%s", shortCommit(fromSHA), shortCommit(toSHA)))
+
+	commits, err := git.LogRange(".", fromSHA, toSHA)
+	if err != nil {
+		ui.Dim("Commits are not available in the local repository; run 'git fetch' and try again")
+		return
+	}
+	if len(commits) == 0 {
+		ui.Dim(fmt.Sprintf("%s is not ahead of %s", shortCommit(toSHA), shortCommit(fromSHA)))
+		return
+	}
+
+	items := make([]string, 0, len(commits))
+	for _, c := range commits {
+		items = append(items, fmt.Sprintf("%s %s %s", c.ShortHash, c.Message, ui.DimStyle.Render("("+c.Author+")")))
+	}
+	ui.List(items)
+}
+
+// printEnvChangesBetween lists variables created or updated between two deployments
+func printEnvChangesBetween(envs []api.EnvironmentVariable, from, to *api.Deployment) {
+	start, end := from.Created(), to.Created()
+	preview := to.PullRequest() > 0
+
+	var changes []envChange
+	for _, env := range envs {
+		if env.IsPreview != preview {
+			continue
+		}
+		switch {
+		case inRange(api.ParseTimestamp(env.CreatedAt), start, end):
+			changes = append(changes, envChange{kind: envCreate, key: env.Key})
+		case inRange(api.ParseTimestamp(env.UpdatedAt), start, end):
+			changes = append(changes, envChange{kind: envUpdate, key: env.Key})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].key < changes[j].key })
+
+	ui.Spacer()
+	ui.Section(fmt.Sprintf("Environment Variables (%s)", envTargetName(preview)))
+	if len(changes) == 0 {
+		ui.Dim("No variables were created or updated between these deployments")
+		return
+	}
+	printEnvChanges(changes)
+	ui.Spacer()
+	ui.Dim("Variables deleted since the first deployment are not tracked by Coolify")
+}
+
+// inRange reports whether t is strictly between start and end
+func inRange(t, start, end time.Time) bool {
+	return t.After(start) && t.Before(end)
+}
+
+// styleDeploymentStatus colours a deployment status
+func styleDeploymentStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "finished":
+		return ui.SuccessStyle.Render(status)
+	case "failed", "error":
+		return ui.ErrorStyle.Render(status)
+	case "in_progress", "queued", "running":
+		return ui.InfoStyle.Render(status)
+	case "cancelled", "cancelled-by-user":
+		return ui.DimStyle.Render(status)
+	}
+	return status
+}
+
+// formatDuration formats a deployment duration, or "-" when unknown
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.Round(time.Second).String()
+}
+
+// orDash returns s, or "-" when it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var destinationsCmd = &cobra.Command{
+	Use:     "destinations",
+	Aliases: []string{"destination", "dest"},
+	Short:   "Manage Docker network destinations",
+	Long: `Manage destinations, the Docker networks on a server that resources are
+deployed into.
+
+Available Commands:
+  destinations ls      - List destinations
+  destinations create  - Create a destination on a server
+  destinations rm      - Delete a destination`,
+}
+
+var destinationsListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List destinations",
+	RunE:  runDestinationsList,
+}
+
+var destinationsCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Create a destination",
+	Long: `Create a Docker network destination on a server.
+
+Examples:
+  cool-kit destinations create staging --server <server-uuid>
+  cool-kit destinations create internal --server <server-uuid> --network internal-net`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDestinationsCreate,
+}
+
+var destinationsRemoveCmd = &cobra.Command{
+	Use:   "rm UUID",
+	Short: "Delete a destination",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDestinationsRemove,
+}
+
+func init() {
+	destinationsCmd.AddCommand(destinationsListCmd)
+	destinationsCmd.AddCommand(destinationsCreateCmd)
+	destinationsCmd.AddCommand(destinationsRemoveCmd)
+
+	destinationsListCmd.Flags().String("server", "", "Only show destinations on this server")
+	addTableFlags(destinationsListCmd)
+	destinationsCreateCmd.Flags().String("server", "", "Server UUID")
+	destinationsCreateCmd.Flags().String("network", "", "Docker network name (defaults to a generated name)")
+}
+
+func runDestinationsList(cmd *cobra.Command, args []string) error {
+	serverUUID, _ := cmd.Flags().GetString("server")
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	ui.Section("Destinations")
+
+	var destinations []api.Destination
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-destinations",
+			ActiveName:   "Loading destinations...",
+			CompleteName: "✓ Loaded destinations",
+			Action: func() error {
+				var err error
+				if serverUUID != "" {
+					destinations, err = client.ListServerDestinations(serverUUID)
+				} else {
+					destinations, err = client.ListDestinations()
+				}
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load destinations")
+		return fmt.Errorf("failed to list destinations: %w", err)
+	}
+
+	if len(destinations) == 0 {
+		ui.Dim("No destinations found")
+		return nil
+	}
+
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "Name"},
+		{Name: "Network"},
+		{Name: "Server"},
+		{Name: "Resources"},
+		{Name: "UUID"},
+	}}
+	for _, d := range destinations {
+		table.Rows = append(table.Rows, []string{
+			d.Name,
+			d.NetworkName,
+			shortUUID(d.ServerUUID),
+			fmt.Sprintf("%d", d.ResourceCount),
+			d.UUID,
+		})
+	}
+
+	ui.Spacer()
+	return printTable(cmd, table)
+}
+
+func runDestinationsCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	serverUUID, _ := cmd.Flags().GetString("server")
+	network, _ := cmd.Flags().GetString("network")
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	if serverUUID == "" {
+		serverUUID, err = promptServerUUID(client)
+		if err != nil {
+			return err
+		}
+	}
+
+	var resp *api.CreateResponse
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "create-destination",
+			ActiveName:   "Creating destination...",
+			CompleteName: "✓ Created destination",
+			Action: func() error {
+				var err error
+				resp, err = client.CreateDestination(&api.CreateDestinationRequest{
+					Name:       name,
+					ServerUUID: serverUUID,
+					Network:    network,
+				})
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to create destination")
+		return fmt.Errorf("failed to create destination: %w", err)
+	}
+
+	ui.Spacer()
+	ui.KeyValue("UUID", resp.UUID)
+	return nil
+}
+
+func runDestinationsRemove(cmd *cobra.Command, args []string) error {
+	uuid := args[0]
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := ui.ConfirmAction("delete", "destination "+shortUUID(uuid))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		ui.Dim("Cancelled")
+		return nil
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "delete-destination",
+			ActiveName:   "Deleting destination...",
+			CompleteName: "✓ Deleted destination",
+			Action: func() error {
+				return client.DeleteDestination(uuid)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to delete destination")
+		return fmt.Errorf("failed to delete destination: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/filewatch"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Redeploy automatically when files change",
+	Long: `Deploy the current project without prompting, and with --watch keep
+redeploying whenever files change.
+
+Files ignored by git are never watched. In a monorepo app (--app) only changes
+matching its "watch_paths", or its base directory, trigger a deploy. Changes
+are batched until the tree has been quiet for --debounce.
+
+Git projects are committed, pushed and deployed; Docker projects are rebuilt
+and pushed. Deployment logs are streamed as each deploy runs.
+
+Examples:
+  cool-kit dev --watch              # Redeploy production on every change
+  cool-kit dev --watch --preview    # Redeploy the preview deployment instead`,
+	Args: cobra.NoArgs,
+	RunE: runDev,
+}
+
+func init() {
+	devCmd.Flags().BoolP("watch", "w", false, "Keep watching and redeploy on changes")
+	devCmd.Flags().Bool("preview", false, "Deploy the preview deployment instead of production")
+	devCmd.Flags().Duration("debounce", filewatch.DefaultDebounce, "Quiet period before changes are deployed")
+	devCmd.Flags().BoolVar(&deployAllowSecret, "allow-secrets", false, "Push even if the secret scan finds credentials")
+}
+
+func runDev(cmd *cobra.Command, args []string) error {
+	watch, _ := cmd.Flags().GetBool("watch")
+	preview, _ := cmd.Flags().GetBool("preview")
+	debounce, _ := cmd.Flags().GetDuration("debounce")
+
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load project configuration: %w", err)
+	}
+	if err != nil || projectCfg == nil {
+		ui.Error("No project configuration found")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s deploy' to set up and deploy the project first", execName()),
+		})
+		return fmt.Errorf("not linked to a project")
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	prNumber, deploymentType := 0, "production"
+	if preview {
+		prNumber, deploymentType = 1, "preview"
+	}
+
+	deploy := func() {
+		ui.Spacer()
+		ui.Bold(fmt.Sprintf("Deploy (%s)", time.Now().Format("15:04:05")))
+		ui.Spacer()
+		if err := deployProject(client, globalCfg, projectCfg, nil, prNumber, deploymentType); err != nil {
+			ui.Error(fmt.Sprintf("Deploy failed: %v", err))
+		}
+	}
+
+	if !watch {
+		return deployProject(client, globalCfg, projectCfg, nil, prNumber, deploymentType)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher := &filewatch.Watcher{
+		Dir:      cwd,
+		Debounce: debounce,
+		Filter: func(file string) bool {
+			// Deploys write the project config and a temporary Dockerfile;
+			// don't redeploy because of them
+			if strings.HasPrefix(file, ".coolify-deployer/") || path.Base(file) == "Dockerfile.cdp" {
+				return false
+			}
+			return projectCfg.MatchesPath(file)
+		},
+	}
+
+	ui.Section("Dev")
+	ui.KeyValue("Project", projectCfg.Name)
+	ui.KeyValue("Directory", projectCfg.SourceDir())
+	ui.KeyValue("Type", deploymentType)
+	ui.Spacer()
+	ui.Dim("Watching for changes... (Ctrl+C to stop)")
+
+	err = watcher.Run(ctx, func(files []string) {
+		ui.Spacer()
+		ui.Info(fmt.Sprintf("%d file(s) changed: %s", len(files), summarizeFiles(files, 3)))
+		deploy()
+		if ctx.Err() == nil {
+			ui.Spacer()
+			ui.Dim("Watching for changes... (Ctrl+C to stop)")
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("file watcher failed: %w", err)
+	}
+
+	ui.Spacer()
+	ui.Dim("Stopped watching")
+	return nil
+}
+
+// summarizeFiles lists up to max files and counts the rest
+func summarizeFiles(files []string, max int) string {
+	if len(files) <= max {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:max], ", "), len(files)-max)
+}
@@ -1,13 +1,14 @@
 package cmd
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
 	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/dotenv"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -42,14 +43,41 @@ var envRmCmd = &cobra.Command{
 
 var envPullCmd = &cobra.Command{
 	Use:   "pull",
-	Short: "Pull environment variables to local .env file",
-	RunE:  runEnvPull,
+	Short: "Pull environment variables to a local .env.coolify file",
+	Long: `Write the application's environment variables to a local file
+(.env.coolify by default) so they can be edited and pushed back.`,
+	RunE: runEnvPull,
 }
 
 var envPushCmd = &cobra.Command{
 	Use:   "push",
-	Short: "Push local .env file to Coolify",
-	RunE:  runEnvPush,
+	Short: "Push local .env.coolify changes to Coolify",
+	Long: `Compare a local env file (.env.coolify by default) with Coolify, preview
+the variables that will be created, updated or deleted, and apply them.
+
+Variables that exist in Coolify but not in the file are deleted unless
+--keep-remote is set.
+
+Examples:
+  cool-kit env push --dry-run
+  cool-kit env push --prod
+  cool-kit env push --file .env.build --build-time --keep-remote`,
+	RunE: runEnvPush,
+}
+
+var envImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import variables from a dotenv file in one batch",
+	Long: `Parse a dotenv file (quoted and multiline values included), create or
+update its variables in a single batch request, and report what changed.
+Variables that exist only in Coolify are left untouched.
+
+Examples:
+  cool-kit env import .env
+  cool-kit env import .env.production --prod --build-time NEXT_PUBLIC_API_URL,NODE_ENV
+  cool-kit env import .env --literal --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvImport,
 }
 
 func init() {
@@ -58,9 +86,27 @@ func init() {
 	envCmd.AddCommand(envRmCmd)
 	envCmd.AddCommand(envPullCmd)
 	envCmd.AddCommand(envPushCmd)
+	envCmd.AddCommand(envImportCmd)
+
+	addTableFlags(envLsCmd)
 
 	// Add --prod flag for env commands to target production deployments
 	envCmd.PersistentFlags().BoolVar(&prodFlag, "prod", false, "Target production environment (default is preview)")
+
+	for _, c := range []*cobra.Command{envPullCmd, envPushCmd, envImportCmd} {
+		c.Flags().Bool("preview", false, "Target preview environment (the default)")
+	}
+	for _, c := range []*cobra.Command{envPullCmd, envPushCmd} {
+		c.Flags().String("file", defaultEnvSyncFile, "Local env file")
+	}
+	envPullCmd.Flags().BoolP("force", "f", false, "Overwrite the file without asking")
+	envPushCmd.Flags().Bool("build-time", false, "Mark created and updated variables as build-time")
+	envPushCmd.Flags().Bool("keep-remote", false, "Do not delete variables missing from the file")
+	envPushCmd.Flags().Bool("dry-run", false, "Only show the changes")
+
+	envImportCmd.Flags().StringSlice("build-time", nil, "Keys to mark as build-time variables (comma-separated)")
+	envImportCmd.Flags().Bool("literal", false, "Store values literally, without variable interpolation")
+	envImportCmd.Flags().Bool("dry-run", false, "Only show the changes")
 }
 
 func getAppUUID() (string, *api.Client, error) {
@@ -86,16 +132,33 @@ func getAppUUID() (string, *api.Client, error) {
 		return "", nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
 	return appUUID, client, nil
 }
 
 func runEnvLs(cmd *cobra.Command, args []string) error {
+	format, err := structuredFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	appUUID, client, err := getAppUUID()
 	if err != nil {
 		return err
 	}
 
+	if format != "" {
+		allEnvVars, err := client.GetApplicationEnvVars(appUUID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch environment variables: %w", err)
+		}
+		records := make([]envRecord, 0, len(allEnvVars))
+		for i := range allEnvVars {
+			records = append(records, newEnvRecord(&allEnvVars[i]))
+		}
+		return formatOutput(format, records)
+	}
+
 	ui.Section("Environment Variables")
 
 	ui.Info("Loading environment variables...")
@@ -112,14 +175,18 @@ func runEnvLs(cmd *cobra.Command, args []string) error {
 		ui.Dim("No environment variables configured")
 		ui.NextSteps([]string{
 			fmt.Sprintf("Run '%s env add KEY=value' to add variables", execName()),
-			fmt.Sprintf("Run '%s env push' to upload from .env file", execName()),
+			fmt.Sprintf("Run '%s env push' to upload from .env.coolify", execName()),
 		})
 		return nil
 	}
 
 	// Build table with environment label
-	headers := []string{"Environment", "Key", "Value"}
-	rows := [][]string{}
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "Environment"},
+		{Name: "Key"},
+		{Name: "Value"},
+		{Name: "Build", Wide: true},
+	}}
 
 	for _, env := range allEnvVars {
 		value := env.Value
@@ -127,9 +194,7 @@ func runEnvLs(cmd *cobra.Command, args []string) error {
 		if len(value) > 50 {
 			value = value[:20] + "..." + value[len(value)-10:]
 		}
-		if strings.Contains(strings.ToLower(env.Key), "secret") ||
-			strings.Contains(strings.ToLower(env.Key), "password") ||
-			strings.Contains(strings.ToLower(env.Key), "token") {
+		if isSecretKey(env.Key) {
 			value = "••••••••"
 		}
 
@@ -138,11 +203,13 @@ func runEnvLs(cmd *cobra.Command, args []string) error {
 			envLabel = "Preview"
 		}
 
-		rows = append(rows, []string{envLabel, env.Key, value})
+		table.Rows = append(table.Rows, []string{envLabel, env.Key, value, fmt.Sprintf("%t", env.IsBuildTime)})
 	}
 
 	ui.Spacer()
-	ui.Table(headers, rows)
+	if err := printTable(cmd, table); err != nil {
+		return err
+	}
 	ui.Spacer()
 	ui.Dim(fmt.Sprintf("Total: %d variables", len(allEnvVars)))
 
@@ -242,35 +309,39 @@ func runEnvRm(cmd *cobra.Command, args []string) error {
 }
 
 func runEnvPull(cmd *cobra.Command, args []string) error {
-	appUUID, client, err := getAppUUID()
+	file, _ := cmd.Flags().GetString("file")
+	force, _ := cmd.Flags().GetBool("force")
+
+	preview, err := envTargetPreview(cmd)
 	if err != nil {
 		return err
 	}
 
-	deploymentType := "preview"
-	if prodFlag {
-		deploymentType = "production"
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
 	}
 
+	deploymentType := envTargetName(preview)
 	ui.Section(fmt.Sprintf("Pull Environment Variables - %s", deploymentType))
 
 	ui.Info("Fetching environment variables...")
-	envVars, err := client.GetApplicationEnvVars(appUUID)
+	envs, err := listTargetEnvs(client, appUUID, preview)
 	if err != nil {
 		ui.Error("Failed to fetch environment variables")
 		return fmt.Errorf("failed to fetch environment variables: %w", err)
 	}
 	ui.Success("Fetched environment variables")
 
-	if len(envVars) == 0 {
-		ui.Warning("No environment variables to pull")
+	if len(envs) == 0 {
+		ui.Warning(fmt.Sprintf("No %s environment variables to pull", deploymentType))
 		return nil
 	}
 
-	// Check if .env already exists
-	if _, err := os.Stat(".env"); err == nil {
+	// Check if the file already exists
+	if _, err := os.Stat(file); err == nil && !force {
 		ui.Spacer()
-		overwrite, err := ui.Confirm(".env already exists. Overwrite?")
+		overwrite, err := ui.Confirm(fmt.Sprintf("%s already exists. Overwrite?", file))
 		if err != nil {
 			return err
 		}
@@ -280,121 +351,250 @@ func runEnvPull(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	file, err := os.Create(".env")
+	vars := make([]dotenv.Var, 0, len(envs))
+	buildTime := 0
+	for _, env := range envs {
+		vars = append(vars, dotenv.Var{Key: env.Key, Value: env.Value})
+		if env.IsBuildTime {
+			buildTime++
+		}
+	}
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
 	if err != nil {
-		return fmt.Errorf("failed to create .env file: %w", err)
+		return fmt.Errorf("failed to create %s: %w", file, err)
 	}
-	defer file.Close()
+	defer f.Close()
 
-	for _, env := range envVars {
-		file.WriteString(fmt.Sprintf("%s=%s\n", env.Key, env.Value))
+	header := fmt.Sprintf("Coolify %s environment for application %s\nEdit and run '%s env push' to apply changes", deploymentType, appUUID, execName())
+	if err := dotenv.Write(f, header, vars); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
 	}
 
 	ui.Spacer()
-	ui.Success(fmt.Sprintf("Pulled %d variables to .env", len(envVars)))
+	ui.Success(fmt.Sprintf("Pulled %d variables to %s", len(vars), file))
 	ui.Spacer()
-	ui.KeyValue("File", ".env")
-	ui.KeyValue("Variables", fmt.Sprintf("%d", len(envVars)))
+	ui.KeyValue("File", file)
+	ui.KeyValue("Variables", fmt.Sprintf("%d", len(vars)))
+	if buildTime > 0 {
+		ui.KeyValue("Build-time", fmt.Sprintf("%d", buildTime))
+	}
+	ui.Spacer()
+	ui.Dim(fmt.Sprintf("%s contains secrets; keep it out of version control", file))
 
 	return nil
 }
 
 func runEnvPush(cmd *cobra.Command, args []string) error {
-	// Read .env file
-	file, err := os.Open(".env")
+	file, _ := cmd.Flags().GetString("file")
+	buildTime, _ := cmd.Flags().GetBool("build-time")
+	keepRemote, _ := cmd.Flags().GetBool("keep-remote")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	preview, err := envTargetPreview(cmd)
 	if err != nil {
-		ui.Error("Could not open .env file")
-		ui.NextSteps([]string{
-			"Create a .env file with your environment variables",
-			"Format: KEY=value (one per line)",
-		})
-		return fmt.Errorf("failed to open .env file: %w", err)
+		return err
+	}
+
+	local, err := dotenv.ParseFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			ui.Error(fmt.Sprintf("Could not open %s", file))
+			ui.NextSteps([]string{
+				fmt.Sprintf("Run '%s env pull' to create it from Coolify", execName()),
+				"Or pass --file with a KEY=value file",
+			})
+		}
+		return fmt.Errorf("failed to read %s: %w", file, err)
 	}
-	defer file.Close()
 
 	appUUID, client, err := getAppUUID()
 	if err != nil {
 		return err
 	}
 
-	deploymentType := "preview"
-	if prodFlag {
-		deploymentType = "production"
+	deploymentType := envTargetName(preview)
+	ui.Section(fmt.Sprintf("Push Environment Variables - %s", deploymentType))
+
+	ui.Info("Comparing with Coolify...")
+	remote, err := listTargetEnvs(client, appUUID, preview)
+	if err != nil {
+		ui.Error("Failed to fetch environment variables")
+		return fmt.Errorf("failed to fetch environment variables: %w", err)
 	}
 
-	ui.Section(fmt.Sprintf("Push Environment Variables - %s", deploymentType))
+	changes := diffEnvs(remote, local, envDiffOptions{
+		preview:   preview,
+		prune:     !keepRemote,
+		buildTime: func(string) bool { return buildTime },
+	})
+	if len(changes) == 0 {
+		ui.Success(fmt.Sprintf("%s is up to date", deploymentType))
+		return nil
+	}
 
-	var envVars []struct {
-		Key   string
-		Value string
+	created, updated, deleted := countEnvChanges(changes)
+	ui.Spacer()
+	printEnvChanges(changes)
+	ui.Spacer()
+	ui.Dim(fmt.Sprintf("%d to create, %d to update, %d to delete", created, updated, deleted))
+
+	if dryRun {
+		return nil
 	}
 
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+	if !skipConfirm {
+		ui.Spacer()
+		confirmed, err := ui.Confirm(fmt.Sprintf("Apply %d changes to %s?", len(changes), deploymentType))
+		if err != nil {
+			return err
 		}
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			ui.Warning(fmt.Sprintf("Skipping invalid line %d: %s", lineNum, line))
-			continue
+		if !confirmed {
+			ui.Dim("Cancelled")
+			return nil
 		}
-		envVars = append(envVars, struct {
-			Key   string
-			Value string
-		}{Key: parts[0], Value: parts[1]})
 	}
 
-	if len(envVars) == 0 {
-		ui.Warning("No valid environment variables found in .env")
-		return nil
+	ui.Spacer()
+	failed := 0
+	for _, c := range changes {
+		if err := applyEnvChange(client, appUUID, c); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to update %s: %v", c.key, err))
+			failed++
+		}
 	}
 
 	ui.Spacer()
-	ui.KeyValue("Found", fmt.Sprintf("%d variables", len(envVars)))
-	ui.Spacer()
+	if failed > 0 {
+		ui.Warning(fmt.Sprintf("Applied %d changes (%d failed)", len(changes)-failed, failed))
+	} else {
+		ui.Success(fmt.Sprintf("Applied %d changes", len(changes)))
+	}
+
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' to redeploy with new variables", execName()),
+	})
+
+	if failed > 0 {
+		return fmt.Errorf("%d environment variable changes failed", failed)
+	}
+	return nil
+}
 
-	confirmed, err := ui.Confirm(fmt.Sprintf("Push %d variables to %s?", len(envVars), deploymentType))
+func runEnvImport(cmd *cobra.Command, args []string) error {
+	file := args[0]
+	buildTimeKeys, _ := cmd.Flags().GetStringSlice("build-time")
+	literal, _ := cmd.Flags().GetBool("literal")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	preview, err := envTargetPreview(cmd)
 	if err != nil {
 		return err
 	}
-	if !confirmed {
-		ui.Dim("Cancelled")
+
+	local, err := dotenv.ParseFile(file)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Could not parse %s", file))
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if len(local) == 0 {
+		ui.Warning(fmt.Sprintf("No variables found in %s", file))
+		return nil
+	}
+
+	inFile := make(map[string]bool, len(local))
+	for _, v := range local {
+		inFile[v.Key] = true
+	}
+	buildTime := make(map[string]bool, len(buildTimeKeys))
+	for _, key := range buildTimeKeys {
+		key = strings.TrimSpace(key)
+		if !inFile[key] {
+			ui.Warning(fmt.Sprintf("--build-time key %s is not in %s", key, file))
+		}
+		buildTime[key] = true
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	deploymentType := envTargetName(preview)
+	ui.Section(fmt.Sprintf("Import Environment Variables - %s", deploymentType))
+
+	ui.Info("Comparing with Coolify...")
+	remote, err := listTargetEnvs(client, appUUID, preview)
+	if err != nil {
+		ui.Error("Failed to fetch environment variables")
+		return fmt.Errorf("failed to fetch environment variables: %w", err)
+	}
+
+	changes := diffEnvs(remote, local, envDiffOptions{
+		preview:   preview,
+		buildTime: func(key string) bool { return buildTime[key] },
+		literal:   literal,
+	})
+
+	created, updated, _ := countEnvChanges(changes)
+	unchanged := len(local) - created - updated
+
+	if len(changes) == 0 {
+		ui.Success(fmt.Sprintf("All %d variables are up to date", len(local)))
 		return nil
 	}
 
 	ui.Spacer()
-	pushed := 0
-	failed := 0
+	printEnvChanges(changes)
+	ui.Spacer()
+	ui.Dim(fmt.Sprintf("%d to create, %d to update, %d unchanged", created, updated, unchanged))
 
-	// Set is_preview based on flag (default is preview, --prod targets production)
-	isPreview := !prodFlag
+	if dryRun {
+		return nil
+	}
 
-	for _, env := range envVars {
-		ui.Info(fmt.Sprintf("Pushing %s...", env.Key))
-		_, err := client.CreateApplicationEnvVar(appUUID, env.Key, env.Value, false, isPreview)
+	if !skipConfirm {
+		ui.Spacer()
+		confirmed, err := ui.Confirm(fmt.Sprintf("Import %d variables to %s?", len(changes), deploymentType))
 		if err != nil {
-			ui.Warning(fmt.Sprintf("Failed to push %s: %v", env.Key, err))
-			failed++
-		} else {
-			ui.Success(fmt.Sprintf("Pushed %s", env.Key))
-			pushed++
+			return err
+		}
+		if !confirmed {
+			ui.Dim("Cancelled")
+			return nil
 		}
 	}
 
-	ui.Spacer()
-	if failed > 0 {
-		ui.Warning(fmt.Sprintf("Pushed %d variables (%d failed)", pushed, failed))
-	} else {
-		ui.Success(fmt.Sprintf("Pushed %d variables", pushed))
+	envs := make([]api.EnvironmentVariable, 0, len(changes))
+	for _, c := range changes {
+		envs = append(envs, c.env)
 	}
 
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "import-envs",
+			ActiveName:   fmt.Sprintf("Importing %d variables...", len(envs)),
+			CompleteName: fmt.Sprintf("✓ Imported %d variables", len(envs)),
+			Action: func() error {
+				_, err := client.UpdateApplicationEnvsBulk(context.Background(), appUUID, envs)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to import environment variables")
+		return fmt.Errorf("failed to import environment variables: %w", err)
+	}
+
+	ui.Spacer()
+	ui.KeyValue("Created", fmt.Sprintf("%d", created))
+	ui.KeyValue("Updated", fmt.Sprintf("%d", updated))
+	ui.KeyValue("Unchanged", fmt.Sprintf("%d", unchanged))
+
 	ui.NextSteps([]string{
 		fmt.Sprintf("Run '%s' to redeploy with new variables", execName()),
 	})
-
 	return nil
 }
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var envEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit environment variables in an interactive editor",
+	Long: `Edit the application's preview and production variables in a table.
+Values stay masked until revealed, flags toggle with a key, and multi-line
+values like certificates can be pasted and edited in place. The changes are
+listed for review before anything is saved to Coolify.
+
+Keys:
+  enter   edit the value (ctrl+s when done)
+  a       add a variable
+  d       delete or restore a variable
+  u       undo the changes to a variable
+  v / V   reveal the value / every value
+  b / l   toggle build-time / literal
+  p       move between preview and production
+  s       review and save
+  q       quit`,
+	Args: cobra.NoArgs,
+	RunE: runEnvEdit,
+}
+
+func init() {
+	envCmd.AddCommand(envEditCmd)
+}
+
+func runEnvEdit(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	var envs []api.EnvironmentVariable
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-envs",
+			ActiveName:   "Loading environment variables...",
+			CompleteName: "✓ Loaded environment variables",
+			Action: func() error {
+				var err error
+				envs, err = client.ListApplicationEnvs(context.Background(), appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load environment variables")
+		return fmt.Errorf("failed to fetch environment variables: %w", err)
+	}
+
+	vars := make([]ui.EnvVar, 0, len(envs))
+	remote := make(map[string]*api.EnvironmentVariable, len(envs))
+	for i := range envs {
+		env := &envs[i]
+		remote[env.UUID] = env
+		vars = append(vars, ui.EnvVar{
+			UUID:      env.UUID,
+			Key:       env.Key,
+			Value:     env.Value,
+			BuildTime: env.IsBuildTime,
+			Preview:   env.IsPreview,
+			Literal:   env.IsLiteral,
+		})
+	}
+
+	title := "Environment Variables"
+	if projectCfg, err := config.LoadProject(); err == nil && projectCfg != nil && projectCfg.Name != "" {
+		title += " - " + projectCfg.Name
+	}
+	edits, err := ui.EditEnvVars(title, vars)
+	if err != nil {
+		return err
+	}
+	if len(edits) == 0 {
+		ui.Dim("No changes saved")
+		return nil
+	}
+
+	changes := envEditChanges(edits, remote)
+	ui.Section("Saving Environment Variables")
+	for _, e := range edits {
+		fmt.Println(ui.DimStyle.Render("  " + e.Describe()))
+	}
+	ui.Spacer()
+	failed := 0
+	for _, c := range changes {
+		if err := applyEnvChange(client, appUUID, c); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to update %s: %v", c.key, err))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		ui.Warning(fmt.Sprintf("Saved the changes with %d failures", failed))
+	} else {
+		ui.Success(fmt.Sprintf("Saved %d changes", len(edits)))
+	}
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s' to redeploy with new variables", execName()),
+	})
+
+	if failed > 0 {
+		return fmt.Errorf("%d environment variable changes failed", failed)
+	}
+	return nil
+}
+
+// envEditChanges turns the editor's edits into API changes, deletes first so
+// a variable moved between preview and production doesn't collide with
+// itself. The API can't reliably turn a flag off or move a variable, so
+// those edits delete the variable and create it again.
+func envEditChanges(edits []ui.EnvEdit, remote map[string]*api.EnvironmentVariable) []envChange {
+	var deletes, updates, creates []envChange
+	for _, e := range edits {
+		var want api.EnvironmentVariable
+		if e.New != nil {
+			want = api.EnvironmentVariable{
+				Key:         e.New.Key,
+				Value:       e.New.Value,
+				IsPreview:   e.New.Preview,
+				IsBuildTime: e.New.BuildTime,
+				IsLiteral:   e.New.Literal,
+				IsMultiline: strings.Contains(e.New.Value, "\n"),
+			}
+		}
+
+		switch {
+		case e.Old == nil:
+			creates = append(creates, envChange{kind: envCreate, key: want.Key, env: want})
+		case e.New == nil:
+			deletes = append(deletes, envChange{kind: envDelete, key: e.Old.Key, remote: remote[e.Old.UUID]})
+		case e.Old.Preview != e.New.Preview || e.Old.Key != e.New.Key ||
+			(e.Old.BuildTime && !e.New.BuildTime) || (e.Old.Literal && !e.New.Literal):
+			deletes = append(deletes, envChange{kind: envDelete, key: e.Old.Key, remote: remote[e.Old.UUID]})
+			creates = append(creates, envChange{kind: envCreate, key: want.Key, env: want})
+		default:
+			updates = append(updates, envChange{kind: envUpdate, key: want.Key, env: want, remote: remote[e.Old.UUID]})
+		}
+	}
+	return append(append(deletes, updates...), creates...)
+}
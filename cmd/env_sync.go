@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/dotenv"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// defaultEnvSyncFile is the file 'env pull' writes and 'env push' reads
+const defaultEnvSyncFile = ".env.coolify"
+
+// envChangeKind is the kind of change a push makes to a variable
+type envChangeKind int
+
+const (
+	envCreate envChangeKind = iota
+	envUpdate
+	envDelete
+)
+
+// envChange is a single difference between a local file and Coolify
+type envChange struct {
+	kind envChangeKind
+	key  string
+
+	// env is the desired variable for creates and updates
+	env api.EnvironmentVariable
+
+	remote *api.EnvironmentVariable
+}
+
+// envDiffOptions controls how local variables are compared with Coolify
+type envDiffOptions struct {
+	// preview is the deployment type the variables belong to
+	preview bool
+
+	// prune deletes remote variables missing locally
+	prune bool
+
+	// buildTime reports whether a key must be a build-time variable
+	buildTime func(key string) bool
+
+	// literal marks created and updated variables as literal (no interpolation)
+	literal bool
+}
+
+// envTargetPreview reports whether a command targets preview variables.
+// Preview is the default; --prod selects production.
+func envTargetPreview(cmd *cobra.Command) (bool, error) {
+	preview, _ := cmd.Flags().GetBool("preview")
+	if preview && prodFlag {
+		return false, fmt.Errorf("--preview and --prod cannot be used together")
+	}
+	return !prodFlag, nil
+}
+
+// envTargetName returns a label for the targeted deployment type
+func envTargetName(preview bool) string {
+	if preview {
+		return "preview"
+	}
+	return "production"
+}
+
+// listTargetEnvs returns an application's variables for one deployment type
+func listTargetEnvs(client *api.Client, appUUID string, preview bool) ([]api.EnvironmentVariable, error) {
+	all, err := client.ListApplicationEnvs(context.Background(), appUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	envs := make([]api.EnvironmentVariable, 0, len(all))
+	for _, env := range all {
+		if env.IsPreview == preview {
+			envs = append(envs, env)
+		}
+	}
+	return envs, nil
+}
+
+// diffEnvs compares local variables with remote ones. Remote variables missing
+// locally are deleted only with opts.prune. Existing variables are also updated
+// when they lack a build-time or literal flag the options require.
+func diffEnvs(remote []api.EnvironmentVariable, local []dotenv.Var, opts envDiffOptions) []envChange {
+	byKey := make(map[string]*api.EnvironmentVariable, len(remote))
+	for i := range remote {
+		byKey[remote[i].Key] = &remote[i]
+	}
+
+	var changes []envChange
+	seen := make(map[string]bool, len(local))
+	for _, v := range local {
+		seen[v.Key] = true
+
+		buildTime := opts.buildTime != nil && opts.buildTime(v.Key)
+		want := api.EnvironmentVariable{
+			Key:         v.Key,
+			Value:       v.Value,
+			IsPreview:   opts.preview,
+			IsBuildTime: buildTime,
+			IsLiteral:   opts.literal,
+			IsMultiline: strings.Contains(v.Value, "\n"),
+		}
+
+		r, ok := byKey[v.Key]
+		switch {
+		case !ok:
+			changes = append(changes, envChange{kind: envCreate, key: v.Key, env: want})
+		case r.Value != v.Value || (buildTime && !r.IsBuildTime) || (opts.literal && !r.IsLiteral):
+			// Keep flags set in Coolify that the options don't ask for
+			want.IsBuildTime = want.IsBuildTime || r.IsBuildTime
+			want.IsLiteral = want.IsLiteral || r.IsLiteral
+			changes = append(changes, envChange{kind: envUpdate, key: v.Key, env: want, remote: r})
+		}
+	}
+
+	if opts.prune {
+		var deleted []envChange
+		for i := range remote {
+			if !seen[remote[i].Key] {
+				deleted = append(deleted, envChange{kind: envDelete, key: remote[i].Key, remote: &remote[i]})
+			}
+		}
+		sort.Slice(deleted, func(i, j int) bool { return deleted[i].key < deleted[j].key })
+		changes = append(changes, deleted...)
+	}
+
+	return changes
+}
+
+// printEnvChanges shows a diff preview. Values are not printed.
+func printEnvChanges(changes []envChange) {
+	for _, c := range changes {
+		switch c.kind {
+		case envCreate:
+			fmt.Println(ui.SuccessStyle.Render("  + " + c.key))
+		case envUpdate:
+			fmt.Println(ui.WarningStyle.Render("  ~ " + c.key))
+		case envDelete:
+			fmt.Println(ui.ErrorStyle.Render("  - " + c.key))
+		}
+	}
+}
+
+// countEnvChanges returns the number of creates, updates and deletes
+func countEnvChanges(changes []envChange) (created, updated, deleted int) {
+	for _, c := range changes {
+		switch c.kind {
+		case envCreate:
+			created++
+		case envUpdate:
+			updated++
+		case envDelete:
+			deleted++
+		}
+	}
+	return created, updated, deleted
+}
+
+// applyEnvChange performs a single change against the API
+func applyEnvChange(client *api.Client, appUUID string, c envChange) error {
+	ctx := context.Background()
+
+	switch c.kind {
+	case envCreate:
+		_, err := client.CreateApplicationEnv(ctx, appUUID, c.env)
+		return err
+	case envUpdate:
+		_, err := client.UpdateApplicationEnv(ctx, appUUID, c.env)
+		return err
+	case envDelete:
+		_, err := client.DeleteApplicationEnv(ctx, appUUID, c.remote.UUID)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/appdeploy"
+	"github.com/entro314-labs/cool-kit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec -- COMMAND [ARGS...]",
+	Short: "Run a one-off command in the application container",
+	Long: `Run a command inside the linked application's running container using
+Coolify's execute-command API: migrations, artisan, rake, and so on.
+
+A single argument is run as a shell command line, so pipes and && work when it
+is quoted. Several arguments are quoted individually. The output is printed
+when the command finishes and the command's exit status becomes the exit status
+of cool-kit.
+
+Examples:
+  cool-kit exec -- php artisan migrate --force
+  cool-kit exec -- bundle exec rake db:migrate
+  cool-kit exec -- "ls -la /app | head"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
+}
+
+func init() {
+	execCmd.Flags().Duration("timeout", 5*time.Minute, "Maximum time to wait for the command")
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	command := args[0]
+	if len(args) > 1 {
+		quoted := make([]string, len(args))
+		for i, arg := range args {
+			quoted[i] = utils.ShellQuote(arg)
+		}
+		command = strings.Join(quoted, " ")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := appdeploy.Exec(ctx, client, appUUID, command)
+	if err != nil {
+		return err
+	}
+
+	output := result.Output
+	fmt.Print(output)
+	if output != "" && !strings.HasSuffix(output, "\n") {
+		fmt.Println()
+	}
+
+	if !result.StatusKnown {
+		fmt.Fprintln(os.Stderr, "warning: the command's exit status was not reported")
+		return nil
+	}
+	if result.ExitStatus != 0 {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return &exitStatusError{code: result.ExitStatus}
+	}
+	return nil
+}
+
+// exitStatusError makes cool-kit exit with a command's exit status
+type exitStatusError struct {
+	code int
+}
+
+func (e *exitStatusError) Error() string {
+	return fmt.Sprintf("command exited with status %d", e.code)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/gitops"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [APP]",
+	Short: "Export live configuration as an apply spec",
+	Long: `Write an application's live configuration - settings, domains, env vars and
+the databases in its environment - as YAML in the schema 'apply' reads.
+Without APP (a UUID or name) the linked application is exported; --all
+exports every project on the instance.
+
+Secret env values (matched by name, like *_SECRET or *_TOKEN, or by value,
+like connection strings with a password) are replaced with ${KEY} references
+by default, which apply resolves from the local environment. Use
+--secrets redact to leave them out, or --secrets include to export them as
+they are. Shown-once variables can't be read and are always referenced.
+
+An export of one application doesn't list the other resources of its
+environment, so don't apply it with --prune.
+
+Examples:
+  cool-kit export > app.yaml
+  cool-kit export my-api --secrets redact -f my-api.yaml
+  cool-kit export --all -f backup.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().Bool("all", false, "Export every project on the instance")
+	exportCmd.Flags().String("secrets", string(gitops.SecretsReference), "How to export secret values (reference, redact, include)")
+	exportCmd.Flags().StringP("file", "f", "", "Write to a file instead of stdout")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	secrets, _ := cmd.Flags().GetString("secrets")
+	file, _ := cmd.Flags().GetString("file")
+
+	if all && len(args) > 0 {
+		return fmt.Errorf("--all can't be combined with an application")
+	}
+
+	if err := checkLogin(); err != nil {
+		return err
+	}
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	var state *gitops.State
+	opts := gitops.ExportOptions{Secrets: gitops.SecretMode(secrets)}
+	if all {
+		state, err = gitops.FetchState(client)
+	} else {
+		var ref string
+		if len(args) > 0 {
+			ref = args[0]
+		}
+		opts.Application, err = resolveAppRef(client, ref)
+		if errors.Is(err, errNotLinkedApp) {
+			return fmt.Errorf("%w: pass APP or --all", err)
+		}
+		if err != nil {
+			return err
+		}
+		state, err = gitops.FetchApplicationState(client, opts.Application)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load live configuration: %w", err)
+	}
+
+	spec, referenced, err := gitops.Export(state, opts)
+	if err != nil {
+		return err
+	}
+	data, err := gitops.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode spec: %w", err)
+	}
+
+	// Status goes to stderr so stdout can be redirected to a file
+	if file == "" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			return err
+		}
+	} else {
+		if err := os.WriteFile(file, data, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		fmt.Fprintln(os.Stderr, ui.SuccessStyle.Render("✓ Exported to "+file))
+	}
+
+	if len(referenced) > 0 && opts.Secrets != gitops.SecretsInclude {
+		verb := "referenced"
+		if opts.Secrets == gitops.SecretsRedact {
+			verb = "left out (shown-once values are referenced)"
+		}
+		fmt.Fprintln(os.Stderr, ui.WarningStyle.Render(fmt.Sprintf("⚠ %d secret variable(s) %s:", len(referenced), verb)))
+		for _, key := range referenced {
+			fmt.Fprintln(os.Stderr, ui.DimStyle.Render("  "+key))
+		}
+	}
+	return nil
+}
+
+// errNotLinkedApp is returned by resolveAppRef without a reference outside a
+// linked project
+var errNotLinkedApp = errors.New("not linked to an application")
+
+// resolveAppRef returns the UUID of the application matching a UUID or name,
+// or of the linked application if ref is empty
+func resolveAppRef(client *api.Client, ref string) (string, error) {
+	if ref == "" {
+		projectCfg, err := config.LoadProject()
+		if err != nil {
+			return "", fmt.Errorf("failed to load project config: %w", err)
+		}
+		if projectCfg == nil || projectCfg.AppUUID == "" {
+			return "", errNotLinkedApp
+		}
+		return projectCfg.AppUUID, nil
+	}
+
+	apps, err := client.ListApplications()
+	if err != nil {
+		return "", fmt.Errorf("failed to list applications: %w", err)
+	}
+	for _, app := range apps {
+		if app.UUID == ref || app.Name == ref {
+			return app.UUID, nil
+		}
+	}
+	return "", fmt.Errorf("application not found: %s", ref)
+}
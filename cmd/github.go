@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/entro314-labs/cool-kit/internal/service"
+	"github.com/entro314-labs/cool-kit/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -123,11 +124,11 @@ var githubDeleteCmd = &cobra.Command{
 
 		force, _ := cmd.Flags().GetBool("force")
 		if !force {
-			fmt.Printf("Are you sure you want to delete GitHub App '%s'? This cannot be undone.\n", args[0])
-			fmt.Print("Type 'yes' to confirm: ")
-			var confirm string
-			fmt.Scanln(&confirm)
-			if confirm != "yes" {
+			confirmed, err := ui.TypeToConfirm(fmt.Sprintf("Delete GitHub App '%s'? This cannot be undone.", args[0]), "yes")
+			if err != nil {
+				return err
+			}
+			if !confirmed {
 				fmt.Println("Cancelled")
 				return nil
 			}
@@ -154,10 +155,10 @@ func splitOwnerRepo(ownerRepo string) []string {
 
 func init() {
 	// Add format flags
-	githubListCmd.Flags().String("format", "table", "Output format: table, json, pretty")
-	githubGetCmd.Flags().String("format", "table", "Output format: table, json, pretty")
-	githubReposCmd.Flags().String("format", "table", "Output format: table, json, pretty")
-	githubBranchesCmd.Flags().String("format", "table", "Output format: table, json, pretty")
+	githubListCmd.Flags().String("format", "table", "Output format: table, json, yaml or pretty")
+	githubGetCmd.Flags().String("format", "table", "Output format: table, json, yaml or pretty")
+	githubReposCmd.Flags().String("format", "table", "Output format: table, json, yaml or pretty")
+	githubBranchesCmd.Flags().String("format", "table", "Output format: table, json, yaml or pretty")
 	githubDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation")
 
 	// Wire commands
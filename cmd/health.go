@@ -95,6 +95,72 @@ func runHealth(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Check GitLab, only when set up since it's the less common host
+	if cfg.GitLabToken != "" {
+		glClient := git.NewGitLabClient(cfg.GitLabInstance(), cfg.GitLabToken)
+		user, err := glClient.GetUser()
+		if err != nil {
+			checks = append(checks, check{
+				name:   "GitLab",
+				status: "Authentication failed",
+				detail: cfg.GitLabInstance(),
+				ok:     false,
+			})
+			allHealthy = false
+		} else {
+			checks = append(checks, check{
+				name:   "GitLab",
+				status: "Authenticated",
+				detail: user.Username,
+				ok:     true,
+			})
+		}
+	}
+
+	// Check Gitea, likewise only when set up
+	if cfg.GiteaToken != "" && cfg.GiteaURL != "" {
+		giteaClient := git.NewGiteaClient(cfg.GiteaURL, cfg.GiteaToken)
+		user, err := giteaClient.GetUser()
+		if err != nil {
+			checks = append(checks, check{
+				name:   "Gitea",
+				status: "Authentication failed",
+				detail: cfg.GiteaURL,
+				ok:     false,
+			})
+			allHealthy = false
+		} else {
+			checks = append(checks, check{
+				name:   "Gitea",
+				status: "Authenticated",
+				detail: user.Login,
+				ok:     true,
+			})
+		}
+	}
+
+	// Check Bitbucket, likewise only when set up
+	if cfg.BitbucketUser != "" && cfg.BitbucketToken != "" {
+		bbClient := git.NewBitbucketClient(cfg.BitbucketUser, cfg.BitbucketToken)
+		user, err := bbClient.GetUser()
+		if err != nil {
+			checks = append(checks, check{
+				name:   "Bitbucket",
+				status: "Authentication failed",
+				detail: cfg.BitbucketUser,
+				ok:     false,
+			})
+			allHealthy = false
+		} else {
+			checks = append(checks, check{
+				name:   "Bitbucket",
+				status: "Authenticated",
+				detail: user.Username,
+				ok:     true,
+			})
+		}
+	}
+
 	// Check Docker (local)
 	if !docker.IsDockerAvailable() {
 		checks = append(checks, check{
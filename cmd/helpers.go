@@ -4,8 +4,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
 
+	"github.com/entro314-labs/cool-kit/internal/api"
 	"github.com/entro314-labs/cool-kit/internal/config"
 )
 
@@ -52,21 +56,89 @@ func getWorkingDirName() string {
 	return filepath.Base(cwd)
 }
 
-// Instance override set by --instance flag
-var instanceOverride string
+// getCurrentInstance gets the current instance, respecting the --instance,
+// --fqdn and --token flags and their environment variables
+func getCurrentInstance() (*config.Instance, error) {
+	return config.GetCurrentInstance()
+}
 
-// SetInstanceOverride sets the instance override (called by PersistentPreRun)
-func SetInstanceOverride(name string) {
-	instanceOverride = name
+// newAPIClient returns an API client that caches rarely-changing responses
+// (servers, projects, teams, GitHub Apps) on disk between invocations.
+// Set COOLKIT_NO_CACHE=1 to always query the instance.
+func newAPIClient(baseURL, token string) *api.Client {
+	if os.Getenv("COOLKIT_NO_CACHE") == "" {
+		if dir, err := api.DefaultCacheDir(); err == nil {
+			return api.NewClient(baseURL, token, api.WithCache(api.NewResponseCache(dir)))
+		}
+	}
+	return api.NewClient(baseURL, token)
 }
 
-// getCurrentInstance gets the current instance, respecting --instance flag override
-func getCurrentInstance() (*config.Instance, error) {
-	// Check for instance override
-	if instanceOverride != "" {
-		return config.GetInstance(instanceOverride)
+// clearAPICache removes cached API responses
+func clearAPICache() {
+	if dir, err := api.DefaultCacheDir(); err == nil {
+		_ = api.NewResponseCache(dir).Clear()
 	}
+}
 
-	// Use context-based instance
-	return config.GetCurrentInstance()
+// newInstanceClient returns an API client for the current instance
+func newInstanceClient() (*api.Client, error) {
+	if err := checkLogin(); err != nil {
+		return nil, err
+	}
+
+	instance, err := getCurrentInstance()
+	if err != nil {
+		return nil, err
+	}
+
+	return newAPIClient(instance.FQDN, instance.Token), nil
+}
+
+// serverSSHTarget returns the ssh/scp options and user@host for a Coolify server
+func serverSSHTarget(server *api.Server, identity string) ([]string, string) {
+	user := server.User
+	if user == "" {
+		user = "root"
+	}
+	port := server.Port
+	if port == 0 {
+		port = 22
+	}
+
+	args := []string{
+		"-o", "StrictHostKeyChecking=accept-new",
+		"-o", "ConnectTimeout=30",
+		"-o", fmt.Sprintf("Port=%d", port),
+	}
+	if identity != "" {
+		args = append(args, "-i", identity)
+	}
+	return args, fmt.Sprintf("%s@%s", user, server.IP)
+}
+
+// runServerCommand runs a shell command on a Coolify server over ssh and returns its output
+func runServerCommand(server *api.Server, identity, command string) (string, error) {
+	opts, target := serverSSHTarget(server, identity)
+	args := append(opts, target, command)
+
+	out, err := exec.Command("ssh", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// openBrowser opens url in the default browser
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
 }
@@ -35,6 +35,25 @@ func init() {
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
+	if !ui.Interactive() {
+		provider := config.ActiveOverrides().Provider
+		if provider == "" {
+			return &ui.NonInteractiveError{
+				What: "Select provider",
+				Hint: fmt.Sprintf("run 'install <provider>', pass --provider or set %s", config.EnvProvider),
+			}
+		}
+		return performInstall(provider)
+	}
+
+	if ui.Accessible() {
+		provider, err := ui.SelectProvider()
+		if err != nil {
+			return err
+		}
+		return performInstall(provider)
+	}
+
 	// Start interactive TUI to select provider
 	model := ui.NewModel() // This model should handle provider selection
 	program := tea.NewProgram(model)
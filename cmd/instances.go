@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
@@ -42,8 +43,22 @@ var instancesRemoveCmd = &cobra.Command{
 var instancesUseCmd = &cobra.Command{
 	Use:   "use NAME",
 	Short: "Switch to a different instance",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runInstancesUse,
+	Long: `Switch to a different instance.
+
+With --project, the current project is pinned to the instance instead: its
+commands use it whatever the current instance is, so projects on different
+instances can be worked on side by side. NAME can also be the instance's URL.
+--instance and COOLKIT_INSTANCE still take precedence over the pin.`,
+	Example: `  cool-kit instances use staging
+  cool-kit instances use client-a --project`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInstancesUse,
+}
+
+var instancesUnpinCmd = &cobra.Command{
+	Use:   "unpin",
+	Short: "Make the current project use the current instance again",
+	RunE:  runInstancesUnpin,
 }
 
 var instancesCurrentCmd = &cobra.Command{
@@ -58,19 +73,39 @@ func init() {
 	instancesCmd.AddCommand(instancesRemoveCmd)
 	instancesCmd.AddCommand(instancesUseCmd)
 	instancesCmd.AddCommand(instancesCurrentCmd)
+	instancesCmd.AddCommand(instancesUnpinCmd)
 
 	// Add flags
 	instancesAddCmd.Flags().String("url", "", "Coolify URL")
 	instancesAddCmd.Flags().String("token", "", "API token")
 	instancesAddCmd.Flags().Bool("default", false, "Set as default instance")
+	instancesUseCmd.Flags().Bool("project", false, "Pin the current project to the instance")
+	addTableFlags(instancesListCmd)
 }
 
 func runInstancesList(cmd *cobra.Command, args []string) error {
+	format, err := structuredFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	instances, err := config.ListInstances()
 	if err != nil {
 		return fmt.Errorf("failed to load instances: %w", err)
 	}
 
+	if format != "" {
+		current := ""
+		if inst, err := config.GetCurrentInstance(); err == nil && inst != nil {
+			current = inst.Name
+		}
+		records := make([]instanceRecord, 0, len(instances))
+		for i := range instances {
+			records = append(records, newInstanceRecord(&instances[i], current))
+		}
+		return formatOutput(format, records)
+	}
+
 	if len(instances) == 0 {
 		ui.Info("No instances configured")
 		ui.Spacer()
@@ -90,23 +125,26 @@ func runInstancesList(cmd *cobra.Command, args []string) error {
 		currentName = currentInst.Name
 	}
 
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "Name"},
+		{Name: "URL"},
+		{Name: "Team", Wide: true},
+		{Name: "Version", Wide: true},
+		{Name: "Default"},
+		{Name: "Current"},
+	}}
 	for _, inst := range instances {
-		marker := "  "
-		if inst.Name == currentName {
-			marker = "→ "
-		}
-
-		defaultMarker := ""
-		if inst.Default {
-			defaultMarker = " " + ui.SuccessStyle.Render("(default)")
-		}
-
-		ui.Print(fmt.Sprintf("%s%s - %s%s",
-			marker,
-			ui.BoldStyle.Render(inst.Name),
-			ui.DimStyle.Render(inst.FQDN),
-			defaultMarker,
-		))
+		table.Rows = append(table.Rows, []string{
+			inst.Name,
+			inst.FQDN,
+			orDash(inst.Team),
+			orDash(inst.Version),
+			fmt.Sprintf("%t", inst.Default),
+			fmt.Sprintf("%t", inst.Name == currentName),
+		})
+	}
+	if err := printTable(cmd, table); err != nil {
+		return err
 	}
 
 	ui.Spacer()
@@ -159,20 +197,25 @@ func runInstancesAdd(cmd *cobra.Command, args []string) error {
 	// Validate credentials
 	ui.Spacer()
 	ui.Info("Validating credentials...")
-	client := api.NewClient(url, token)
-	if err := client.HealthCheck(); err != nil {
+	info, err := api.NewClient(url, token).Verify()
+	if err != nil {
 		ui.Error("Connection failed")
-		return fmt.Errorf("failed to connect: %w", err)
+		return err
 	}
 	ui.Success("Connection verified")
 
 	// Add instance
-	if err := config.AddInstance(name, url, token, setAsDefault); err != nil {
+	inst := config.Instance{Name: name, FQDN: url, Token: token, Version: info.Version, Team: info.Team}
+	if err := config.AddInstance(inst, setAsDefault); err != nil {
 		return fmt.Errorf("failed to add instance: %w", err)
 	}
 
 	ui.Spacer()
 	ui.Success(fmt.Sprintf("Instance '%s' added successfully", name))
+	ui.KeyValue("Team", info.Team)
+	if info.Version != "" {
+		ui.KeyValue("Coolify", info.Version)
+	}
 
 	if setAsDefault {
 		ui.KeyValue("Status", "Set as default instance")
@@ -206,6 +249,10 @@ func runInstancesRemove(cmd *cobra.Command, args []string) error {
 func runInstancesUse(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
+	if pin, _ := cmd.Flags().GetBool("project"); pin {
+		return pinProjectInstance(name)
+	}
+
 	if err := config.UseInstance(name); err != nil {
 		return fmt.Errorf("failed to switch instance: %w", err)
 	}
@@ -214,16 +261,76 @@ func runInstancesUse(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// pinProjectInstance saves the instance in the current project's config
+func pinProjectInstance(name string) error {
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("not linked to a project. Run '%s' or '%s link' first", execName(), execName())
+		}
+		return fmt.Errorf("failed to load project configuration: %w", err)
+	}
+
+	inst, err := config.FindInstance(name)
+	if err != nil {
+		return err
+	}
+	projectCfg.Instance = strings.TrimSuffix(name, "/")
+	if err := config.SaveProject(projectCfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	ui.Success(fmt.Sprintf("Pinned %s to instance '%s'", projectCfg.Name, projectCfg.Instance))
+	ui.KeyValue("URL", inst.FQDN)
+	return nil
+}
+
+func runInstancesUnpin(cmd *cobra.Command, args []string) error {
+	projectCfg, err := config.LoadProject()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("not linked to a project. Run '%s' or '%s link' first", execName(), execName())
+		}
+		return fmt.Errorf("failed to load project configuration: %w", err)
+	}
+	if projectCfg.Instance == "" {
+		ui.Info(fmt.Sprintf("%s isn't pinned to an instance", projectCfg.Name))
+		return nil
+	}
+
+	projectCfg.Instance = ""
+	if err := config.SaveProject(projectCfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	ui.Success(fmt.Sprintf("%s uses the current instance again", projectCfg.Name))
+	return nil
+}
+
 func runInstancesCurrent(cmd *cobra.Command, args []string) error {
+	format, err := structuredFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	inst, err := config.GetCurrentInstance()
 	if err != nil {
 		return err
 	}
 
+	if format != "" {
+		return formatOutput(format, newInstanceRecord(inst, inst.Name))
+	}
+
 	ui.Section("Current Instance")
 	ui.Spacer()
 	ui.KeyValue("Name", inst.Name)
 	ui.KeyValue("URL", inst.FQDN)
+	if inst.Team != "" {
+		ui.KeyValue("Team", inst.Team)
+	}
+	if inst.Version != "" {
+		ui.KeyValue("Coolify", inst.Version)
+	}
 
 	if inst.Default {
 		ui.KeyValue("Status", ui.SuccessStyle.Render("Default"))
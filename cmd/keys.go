@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/entro314-labs/cool-kit/internal/output"
 	"github.com/entro314-labs/cool-kit/internal/service"
 	"github.com/spf13/cobra"
 )
@@ -18,8 +20,9 @@ var keysCmd = &cobra.Command{
 }
 
 var keysListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all private keys",
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List all private keys",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getAPIClient()
 		if err != nil {
@@ -37,10 +40,11 @@ var keysListCmd = &cobra.Command{
 	},
 }
 
-var keysGetCmd = &cobra.Command{
-	Use:   "get <uuid>",
-	Short: "Get a private key by UUID",
-	Args:  cobra.ExactArgs(1),
+var keysShowCmd = &cobra.Command{
+	Use:     "show <uuid>",
+	Aliases: []string{"get"},
+	Short:   "Show a private key by UUID",
+	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getAPIClient()
 		if err != nil {
@@ -54,7 +58,97 @@ var keysGetCmd = &cobra.Command{
 		}
 
 		format, _ := cmd.Flags().GetString("format")
-		return formatOutput(format, key)
+		if err := formatOutput(format, key); err != nil {
+			return err
+		}
+
+		// The public key is too long for the table, print it separately
+		if (format == "" || format == output.FormatTable) && key.PublicKey != "" {
+			fmt.Printf("\nPublic key:\n%s\n", key.PublicKey)
+		}
+		return nil
+	},
+}
+
+var keysCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a private key",
+	Long: `Create a private key in Coolify.
+
+By default a new ed25519 keypair is generated locally and saved to
+~/.ssh/coolkit_<name> (private) and ~/.ssh/coolkit_<name>.pub (public).
+Add the public key to the server's ~/.ssh/authorized_keys before validating it.
+
+Examples:
+  cool-kit keys create deploy
+  cool-kit keys create deploy --server <server-uuid>
+  cool-kit keys create existing --file ~/.ssh/id_ed25519`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		name := args[0]
+		description, _ := cmd.Flags().GetString("description")
+		file, _ := cmd.Flags().GetString("file")
+		outputPath, _ := cmd.Flags().GetString("output")
+		servers, _ := cmd.Flags().GetStringSlice("server")
+
+		var keyContent string
+		var generated *service.GeneratedKey
+		if file != "" {
+			data, err := os.ReadFile(expandHome(file))
+			if err != nil {
+				return fmt.Errorf("failed to read key file: %w", err)
+			}
+			keyContent = string(data)
+		} else {
+			if outputPath == "" {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return fmt.Errorf("failed to get home directory: %w", err)
+				}
+				outputPath = filepath.Join(home, ".ssh", "coolkit_"+keyFileName(name))
+			}
+			outputPath = expandHome(outputPath)
+
+			generated, err = service.GenerateED25519Key("cool-kit-" + keyFileName(name))
+			if err != nil {
+				return err
+			}
+			if err := writeKeyPair(outputPath, generated); err != nil {
+				return err
+			}
+			keyContent = generated.PrivateKey
+		}
+
+		svc := service.NewPrivateKeyService(client)
+		key, err := svc.Create(context.Background(), service.PrivateKeyCreateRequest{
+			Name:        name,
+			Description: description,
+			PrivateKey:  keyContent,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Private key '%s' created with UUID: %s\n", name, key.UUID)
+
+		for _, serverUUID := range servers {
+			if err := svc.AssignToServer(context.Background(), key.UUID, serverUUID); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Assigned to server %s\n", serverUUID)
+		}
+
+		if generated != nil {
+			fmt.Printf("\nSaved to %s (public key: %s.pub)\n", outputPath, outputPath)
+			fmt.Printf("Fingerprint: %s\n", generated.Fingerprint)
+			fmt.Printf("\nAdd this line to ~/.ssh/authorized_keys on your server:\n%s\n", generated.PublicKey)
+		}
+		return nil
 	},
 }
 
@@ -77,16 +171,7 @@ Use @filename to read the key from a file:
 
 		// Handle @filename syntax
 		if strings.HasPrefix(keyContent, "@") {
-			filename := strings.TrimPrefix(keyContent, "@")
-			// Expand ~ to home directory
-			if strings.HasPrefix(filename, "~") {
-				home, err := os.UserHomeDir()
-				if err != nil {
-					return fmt.Errorf("failed to get home directory: %w", err)
-				}
-				filename = strings.Replace(filename, "~", home, 1)
-			}
-			data, err := os.ReadFile(filename)
+			data, err := os.ReadFile(expandHome(strings.TrimPrefix(keyContent, "@")))
 			if err != nil {
 				return fmt.Errorf("failed to read key file: %w", err)
 			}
@@ -110,9 +195,9 @@ Use @filename to read the key from a file:
 	},
 }
 
-var keysRemoveCmd = &cobra.Command{
-	Use:     "remove <uuid>",
-	Aliases: []string{"rm", "delete"},
+var keysDeleteCmd = &cobra.Command{
+	Use:     "delete <uuid>",
+	Aliases: []string{"rm", "remove"},
 	Short:   "Remove a private key",
 	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -131,15 +216,62 @@ var keysRemoveCmd = &cobra.Command{
 	},
 }
 
+// expandHome expands a leading ~ to the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return strings.Replace(path, "~", home, 1)
+}
+
+// keyFileName turns a key name into a safe file name component
+func keyFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+// writeKeyPair saves a generated keypair without overwriting existing files
+func writeKeyPair(path string, key *service.GeneratedKey) error {
+	for _, p := range []string{path, path + ".pub"} {
+		if _, err := os.Stat(p); err == nil {
+			return fmt.Errorf("%s already exists: choose another name or use --output", p)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create key directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(key.PrivateKey), 0600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := os.WriteFile(path+".pub", []byte(key.PublicKey+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+	return nil
+}
+
 func init() {
 	// Add format flags
-	keysListCmd.Flags().String("format", "table", "Output format: table, json, pretty")
-	keysGetCmd.Flags().String("format", "table", "Output format: table, json, pretty")
+	keysListCmd.Flags().String("format", "table", "Output format: table, json, yaml or pretty")
+	keysShowCmd.Flags().String("format", "table", "Output format: table, json, yaml or pretty")
 	keysAddCmd.Flags().String("description", "", "Key description")
+	keysCreateCmd.Flags().String("description", "", "Key description")
+	keysCreateCmd.Flags().String("file", "", "Upload an existing private key file instead of generating one")
+	keysCreateCmd.Flags().String("output", "", "Where to save the generated key (default ~/.ssh/coolkit_<name>)")
+	keysCreateCmd.Flags().StringSlice("server", nil, "Server UUID to use this key (repeatable)")
 
 	// Wire commands
 	keysCmd.AddCommand(keysListCmd)
-	keysCmd.AddCommand(keysGetCmd)
+	keysCmd.AddCommand(keysShowCmd)
+	keysCmd.AddCommand(keysCreateCmd)
 	keysCmd.AddCommand(keysAddCmd)
-	keysCmd.AddCommand(keysRemoveCmd)
+	keysCmd.AddCommand(keysDeleteCmd)
 }
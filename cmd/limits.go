@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// memoryLimitPattern matches the memory sizes Docker accepts (512m, 1g, 1.5G, 1073741824)
+var memoryLimitPattern = regexp.MustCompile(`^(?i)(\d+(?:\.\d+)?)([bkmg]?)$`)
+
+// recommendedMemoryMB are the smallest memory limits, in MiB, a framework
+// runs comfortably with. Builds run outside the container, so these only
+// cover the running application.
+var recommendedMemoryMB = map[string]int64{
+	"Next.js":      512,
+	"T3 Stack":     512,
+	"Nuxt":         384,
+	"Remix":        256,
+	"SvelteKit":    256,
+	"Astro":        256,
+	"NestJS":       256,
+	"AdonisJS":     256,
+	"Strapi":       1024,
+	"Rails":        512,
+	"Laravel":      256,
+	"Symfony":      256,
+	"Django":       256,
+	"Phoenix":      256,
+	"FastAPI":      128,
+	"Flask":        128,
+	"Spring Boot":  768,
+	"Quarkus":      256,
+	"Micronaut":    384,
+	"Java":         512,
+	"ASP.NET Core": 256,
+}
+
+var limitsCmd = &cobra.Command{
+	Use:   "limits",
+	Short: "Manage application resource limits",
+	Long: `Show and change the memory and CPU limits of the linked application's container.
+
+Available Commands:
+  limits show  - Show the current limits
+  limits set   - Change the memory or CPU limits`,
+}
+
+var limitsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the application's resource limits",
+	Args:  cobra.NoArgs,
+	RunE:  runLimitsShow,
+}
+
+var limitsSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the application's resource limits",
+	Long: `Set the memory and CPU limits of the linked application. Values use Docker's
+formats: memory as a number with an optional b, k, m or g suffix, CPUs as a
+decimal number of cores. 0 removes the limit.
+
+The new limits apply the next time the application is deployed or restarted.
+
+Examples:
+  cool-kit limits set --memory 512M --cpus 0.5
+  cool-kit limits set --memory 1g
+  cool-kit limits set --cpus 0     # Remove the CPU limit`,
+	Args: cobra.NoArgs,
+	RunE: runLimitsSet,
+}
+
+func init() {
+	limitsCmd.AddCommand(limitsShowCmd)
+	limitsCmd.AddCommand(limitsSetCmd)
+
+	limitsSetCmd.Flags().String("memory", "", "Memory limit (e.g. 512M, 1g, 0 for none)")
+	limitsSetCmd.Flags().String("memory-reservation", "", "Memory soft limit (e.g. 256M)")
+	limitsSetCmd.Flags().String("cpus", "", "Number of CPUs (e.g. 0.5, 2, 0 for none)")
+}
+
+func runLimitsShow(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	app, err := client.GetApplication(appUUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch application: %w", err)
+	}
+
+	ui.Section(fmt.Sprintf("Limits: %s", app.Name))
+	ui.KeyValue("Memory", formatLimit(app.LimitsMemory))
+	ui.KeyValue("Memory reservation", formatLimit(app.LimitsMemoryReservation))
+	ui.KeyValue("Memory swap", formatLimit(app.LimitsMemorySwap))
+	ui.KeyValue("CPUs", formatLimit(app.LimitsCPUs))
+	if cpuSet := app.GetLimitsCPUSet(); cpuSet != "" {
+		ui.KeyValue("CPU set", cpuSet)
+	}
+	if app.LimitsCPUShares != 0 {
+		ui.KeyValue("CPU shares", strconv.Itoa(app.LimitsCPUShares))
+	}
+	return nil
+}
+
+func runLimitsSet(cmd *cobra.Command, args []string) error {
+	memory, _ := cmd.Flags().GetString("memory")
+	reservation, _ := cmd.Flags().GetString("memory-reservation")
+	cpus, _ := cmd.Flags().GetString("cpus")
+
+	if memory == "" && reservation == "" && cpus == "" {
+		return fmt.Errorf("nothing to change: set --memory, --memory-reservation or --cpus")
+	}
+
+	updates := make(map[string]interface{})
+	if memory != "" {
+		if _, err := parseMemoryLimit(memory); err != nil {
+			return err
+		}
+		updates["limits_memory"] = normalizeMemoryLimit(memory)
+	}
+	if reservation != "" {
+		if _, err := parseMemoryLimit(reservation); err != nil {
+			return err
+		}
+		updates["limits_memory_reservation"] = normalizeMemoryLimit(reservation)
+	}
+	if cpus != "" {
+		if err := validateCPULimit(cpus); err != nil {
+			return err
+		}
+		updates["limits_cpus"] = cpus
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	if memory != "" {
+		warnLowMemory(memory)
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "update-limits",
+			ActiveName:   "Updating resource limits...",
+			CompleteName: "✓ Updated resource limits",
+			Action: func() error {
+				return client.UpdateApplication(appUUID, updates)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to update resource limits")
+		return fmt.Errorf("failed to update resource limits: %w", err)
+	}
+
+	ui.Spacer()
+	if memory != "" {
+		ui.KeyValue("Memory", formatLimit(normalizeMemoryLimit(memory)))
+	}
+	if reservation != "" {
+		ui.KeyValue("Memory reservation", formatLimit(normalizeMemoryLimit(reservation)))
+	}
+	if cpus != "" {
+		ui.KeyValue("CPUs", formatLimit(cpus))
+	}
+
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s deploy' to apply the new limits", execName()),
+	})
+	return nil
+}
+
+// parseMemoryLimit returns the size in bytes of a Docker memory limit
+func parseMemoryLimit(s string) (int64, error) {
+	m := memoryLimitPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid memory limit %q: use a number with an optional b, k, m or g suffix", s)
+	}
+
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory limit %q: %w", s, err)
+	}
+
+	multiplier := map[string]float64{"": 1, "b": 1, "k": 1 << 10, "m": 1 << 20, "g": 1 << 30}[strings.ToLower(m[2])]
+	bytes := int64(value * multiplier)
+
+	// Docker refuses limits below 6 MiB
+	if bytes != 0 && bytes < 6<<20 {
+		return 0, fmt.Errorf("invalid memory limit %q: the minimum is 6m", s)
+	}
+	return bytes, nil
+}
+
+// normalizeMemoryLimit lowercases the unit suffix the way Docker prints it
+func normalizeMemoryLimit(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// validateCPULimit checks a Docker --cpus value
+func validateCPULimit(s string) error {
+	cpus, err := strconv.ParseFloat(s, 64)
+	if err != nil || cpus < 0 {
+		return fmt.Errorf("invalid CPU limit %q: use a decimal number of cores, such as 0.5 or 2", s)
+	}
+	if cpus != 0 && cpus < 0.01 {
+		return fmt.Errorf("invalid CPU limit %q: the minimum is 0.01", s)
+	}
+	return nil
+}
+
+// warnLowMemory warns when a memory limit is below the linked framework's
+// recommended minimum
+func warnLowMemory(memory string) {
+	bytes, err := parseMemoryLimit(memory)
+	if err != nil || bytes == 0 {
+		return
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil || projectCfg == nil {
+		return
+	}
+
+	minimum, ok := recommendedMemoryMB[projectCfg.Framework]
+	if !ok || bytes >= minimum<<20 {
+		return
+	}
+	ui.Warning(fmt.Sprintf("%s apps usually need at least %dM of memory; %s may cause out-of-memory restarts",
+		projectCfg.Framework, minimum, memory))
+}
+
+// formatLimit shows Coolify's "0" and empty limits as unlimited
+func formatLimit(value string) string {
+	if value == "" || value == "0" {
+		return ui.DimStyle.Render("unlimited")
+	}
+	return value
+}
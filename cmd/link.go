@@ -2,19 +2,32 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
 	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/detect"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var linkCmd = &cobra.Command{
-	Use:   "link",
+	Use:   "link [APP]",
 	Short: "Link this directory to an existing Coolify application",
 	Long: `Link the current directory to an existing Coolify application.
 
-This allows you to deploy to an app that was created in the Coolify dashboard.`,
+This allows you to deploy to an app that was created in the Coolify dashboard.
+The application's build pack, commands, port, domains, branch, base directory,
+project, environment and server are imported into the project config, and
+any that differ from what is detected locally are listed.
+
+APP is an application UUID or name; without it you pick one from a list.
+
+Examples:
+  cool-kit link
+  cool-kit link my-app`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runLink,
 }
 
@@ -45,7 +58,7 @@ func runLink(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
 
 	// List applications
 	ui.Info("Loading applications...")
@@ -67,49 +80,69 @@ func runLink(cmd *cobra.Command, args []string) error {
 	}
 
 	// Select application
-	ui.Spacer()
-	appOptions := make(map[string]string)
-	appMap := make(map[string]api.Application)
-	for _, app := range apps {
-		displayName := app.Name
-		if app.Fqdn != nil && *app.Fqdn != "" {
-			displayName = fmt.Sprintf("%s (%s)", app.Name, *app.Fqdn)
+	var appUUID string
+	if len(args) == 1 {
+		for _, app := range apps {
+			if app.UUID == args[0] || app.Name == args[0] {
+				appUUID = app.UUID
+				break
+			}
+		}
+		if appUUID == "" {
+			return fmt.Errorf("application not found: %s", args[0])
+		}
+	} else {
+		ui.Spacer()
+		sort.SliceStable(apps, func(i, j int) bool {
+			return strings.ToLower(apps[i].Name) < strings.ToLower(apps[j].Name)
+		})
+		appOptions := make([]ui.PickOption, 0, len(apps))
+		for _, app := range apps {
+			displayName := app.Name
+			if app.Fqdn != nil && *app.Fqdn != "" {
+				displayName = fmt.Sprintf("%s (%s)", app.Name, *app.Fqdn)
+			}
+			appOptions = append(appOptions, ui.PickOption{Key: app.UUID, Label: displayName})
+		}
+
+		appUUID, err = ui.Pick("Select application to link:", ui.PickApp, appOptions)
+		if err != nil {
+			return err
 		}
-		appOptions[app.UUID] = displayName
-		appMap[app.UUID] = app
 	}
 
-	appUUID, err := ui.SelectWithKeys("Select application to link:", appOptions)
+	// The list leaves out some settings, so fetch the application itself
+	ui.Spacer()
+	var projectCfg *config.ProjectConfig
+	var app *api.Application
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "import-app",
+			ActiveName:   "Importing application settings...",
+			CompleteName: "✓ Imported application settings",
+			Action: func() error {
+				var err error
+				app, err = client.GetApplication(appUUID)
+				if err != nil {
+					return fmt.Errorf("failed to get application: %w", err)
+				}
+				projectCfg, err = projectConfigFromApp(client, app)
+				return err
+			},
+		},
+	})
 	if err != nil {
+		ui.Error("Failed to import application")
 		return err
 	}
 
-	app := appMap[appUUID]
-
-	// Determine deploy method based on app config
-	deployMethod := config.DeployMethodGit
-	if app.DockerRegistryImageName != nil && *app.DockerRegistryImageName != "" {
-		deployMethod = config.DeployMethodDocker
-	}
-
-	// Create project config
-	projectCfg := &config.ProjectConfig{
-		Name:            getWorkingDirName(),
-		DeployMethod:    deployMethod,
-		ServerUUID:      "",
-		AppUUID:         appUUID,
-		EnvironmentUUID: "", // Will be fetched from app if needed
-		Framework:       app.BuildPack,
-		InstallCommand:  app.InstallCommand,
-		BuildCommand:    app.BuildCommand,
-		StartCommand:    app.StartCommand,
-	}
-
-	if app.DockerRegistryImageName != nil && *app.DockerRegistryImageName != "" {
-		projectCfg.DockerImage = *app.DockerRegistryImageName
-	}
-	if app.GitRepository != "" {
-		projectCfg.GitHubRepo = app.GitRepository
+	// Prefer the locally detected framework name, and compare the rest
+	var mismatches [][]string
+	if framework, err := detect.Detect(projectCfg.SourceDir()); err == nil {
+		if framework.Name != "" {
+			projectCfg.Framework = framework.Name
+		}
+		mismatches = linkMismatches(projectCfg, framework)
 	}
 
 	if err := config.SaveProject(projectCfg); err != nil {
@@ -120,7 +153,26 @@ func runLink(cmd *cobra.Command, args []string) error {
 	ui.Success("Project linked successfully")
 	ui.Spacer()
 	ui.KeyValue("Application", app.Name)
-	ui.KeyValue("Deploy method", deployMethod)
+	ui.KeyValue("Deploy method", projectCfg.DeployMethod)
+	if projectCfg.BuildPack != "" {
+		ui.KeyValue("Build pack", projectCfg.BuildPack)
+	}
+	if projectCfg.Branch != "" {
+		ui.KeyValue("Branch", projectCfg.Branch)
+	}
+	if projectCfg.Domain != "" {
+		ui.KeyValue("Domains", projectCfg.Domain)
+	}
+	if projectCfg.BaseDirectory != "" {
+		ui.KeyValue("Base directory", projectCfg.BaseDirectory)
+	}
+
+	if len(mismatches) > 0 {
+		ui.Spacer()
+		ui.Warning("Coolify's settings differ from what was detected locally")
+		ui.Table([]string{"Setting", "Coolify", "Detected"}, mismatches)
+		ui.Dim("The Coolify settings were kept; update the project config or the application to align them")
+	}
 
 	ui.NextSteps([]string{
 		fmt.Sprintf("Run '%s' to deploy to this application", execName()),
@@ -129,3 +181,89 @@ func runLink(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// projectConfigFromApp builds a project config for an existing application
+func projectConfigFromApp(client *api.Client, app *api.Application) (*config.ProjectConfig, error) {
+	projectCfg := &config.ProjectConfig{
+		Name:           getWorkingDirName(),
+		DeployMethod:   config.DeployMethodGit,
+		AppUUID:        app.UUID,
+		Framework:      app.BuildPack,
+		BuildPack:      app.BuildPack,
+		InstallCommand: app.InstallCommand,
+		BuildCommand:   app.BuildCommand,
+		StartCommand:   app.StartCommand,
+		PublishDir:     app.PublishDirectory,
+		Port:           app.PortsExposes,
+		Branch:         app.GitBranch,
+		Domain:         app.GetFqdn(),
+		BaseDirectory:  strings.Trim(app.BaseDirectory, "/"),
+	}
+
+	if image := app.GetDockerRegistryImageName(); image != "" {
+		projectCfg.DeployMethod = config.DeployMethodDocker
+		projectCfg.DockerImage = image
+		projectCfg.BuildPack = ""
+	}
+	if app.GitRepository != "" {
+		// Coolify stores "owner/repo"; the config keeps the repository name
+		repo := app.GitRepository
+		if i := strings.LastIndex(repo, "/"); i >= 0 {
+			repo = repo[i+1:]
+		}
+		projectCfg.GitHubRepo = strings.TrimSuffix(repo, ".git")
+	}
+	if app.WatchPaths != nil && *app.WatchPaths != "" {
+		projectCfg.WatchPaths = strings.Fields(*app.WatchPaths)
+	}
+
+	if project, err := applicationProject(client, app); err == nil {
+		projectCfg.ProjectUUID = project.UUID
+		for _, env := range project.Environments {
+			if env.ID == app.EnvironmentID {
+				projectCfg.EnvironmentUUID = env.UUID
+				break
+			}
+		}
+	}
+
+	serverUUID, destUUID, err := applicationDestination(client, app)
+	if err != nil {
+		return nil, err
+	}
+	projectCfg.ServerUUID = serverUUID
+	projectCfg.DestinationUUID = destUUID
+
+	if sourceID := app.GetSourceID(); sourceID != 0 {
+		if githubApps, err := client.ListGitHubApps(); err == nil {
+			for _, gh := range githubApps {
+				if gh.ID == sourceID {
+					projectCfg.GitHubAppUUID = gh.UUID
+					break
+				}
+			}
+		}
+	}
+
+	return projectCfg, nil
+}
+
+// linkMismatches lists the imported settings that differ from local detection
+func linkMismatches(projectCfg *config.ProjectConfig, framework *detect.FrameworkInfo) [][]string {
+	var rows [][]string
+	compare := func(setting, coolify, detected string) {
+		if detected != "" && coolify != detected {
+			rows = append(rows, []string{setting, orDash(coolify), detected})
+		}
+	}
+
+	if projectCfg.DeployMethod == config.DeployMethodGit {
+		compare("Build pack", projectCfg.BuildPack, framework.BuildPack)
+		compare("Install command", projectCfg.InstallCommand, framework.InstallCommand)
+		compare("Build command", projectCfg.BuildCommand, framework.BuildCommand)
+		compare("Start command", projectCfg.StartCommand, framework.StartCommand)
+		compare("Publish directory", strings.Trim(projectCfg.PublishDir, "/"), strings.Trim(framework.PublishDirectory, "/"))
+	}
+	compare("Port", projectCfg.Port, framework.Port)
+	return rows
+}
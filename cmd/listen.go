@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/entro314-labs/cool-kit/internal/webhook"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultListenAddr = ":9000"
+	webhookPath       = "/webhook"
+)
+
+var listenCmd = &cobra.Command{
+	Use:   "listen",
+	Short: "Receive Coolify webhooks and run local actions",
+	Long: `Run a small HTTP server that receives Coolify webhook notifications
+(deployment started, finished, failed) and runs actions for them:
+
+  --hook CMD         Run a shell command. Event details are passed in
+                     COOLKIT_EVENT, COOLKIT_EVENT_TYPE, COOLKIT_APP,
+                     COOLKIT_MESSAGE, COOLKIT_DEPLOYMENT_URL, ... and the
+                     raw JSON payload on stdin.
+  --notify           Show a desktop notification
+  --slack-webhook    Post to a Slack incoming webhook
+
+Defaults for every flag can be stored in the "listen" section of the
+cool-kit configuration file.
+
+Point the Webhook notification channel in Coolify at the URL printed on
+startup. Requests must carry the secret as a bearer token or as the
+"token" query parameter.
+
+Examples:
+  cool-kit listen --notify
+  cool-kit listen --events failed --slack-webhook https://hooks.slack.com/services/...
+  cool-kit listen --hook './on-deploy.sh' --addr 127.0.0.1:9000`,
+	RunE: runListen,
+}
+
+func init() {
+	listenCmd.Flags().String("addr", defaultListenAddr, "Address to listen on")
+	listenCmd.Flags().String("secret", "", "Shared secret required on requests (generated if empty)")
+	listenCmd.Flags().StringSlice("events", nil, "Event types to act on (started, finished, failed, other)")
+	listenCmd.Flags().String("hook", "", "Shell command to run for each event")
+	listenCmd.Flags().Bool("notify", false, "Show a desktop notification for each event")
+	listenCmd.Flags().String("slack-webhook", "", "Slack incoming webhook URL")
+}
+
+func runListen(cmd *cobra.Command, args []string) error {
+	cfg := listenConfig()
+
+	flags := cmd.Flags()
+	if flags.Changed("addr") || cfg.Addr == "" {
+		cfg.Addr, _ = flags.GetString("addr")
+	}
+	if flags.Changed("secret") {
+		cfg.Secret, _ = flags.GetString("secret")
+	}
+	if flags.Changed("events") {
+		cfg.Events, _ = flags.GetStringSlice("events")
+	}
+	if flags.Changed("hook") {
+		cfg.Hook, _ = flags.GetString("hook")
+	}
+	if flags.Changed("notify") {
+		cfg.Notify, _ = flags.GetBool("notify")
+	}
+	if flags.Changed("slack-webhook") {
+		cfg.SlackWebhookURL, _ = flags.GetString("slack-webhook")
+	}
+
+	events, err := parseEventTypes(cfg.Events)
+	if err != nil {
+		return err
+	}
+
+	var actions []webhook.Action
+	if cfg.Hook != "" {
+		actions = append(actions, &webhook.ShellHook{Command: cfg.Hook})
+	}
+	if cfg.Notify {
+		actions = append(actions, webhook.DesktopNotifier{})
+	}
+	if cfg.SlackWebhookURL != "" {
+		actions = append(actions, webhook.NewSlackNotifier(cfg.SlackWebhookURL))
+	}
+	if len(actions) == 0 {
+		return fmt.Errorf("no actions configured: use --hook, --notify or --slack-webhook")
+	}
+
+	if cfg.Secret == "" {
+		cfg.Secret, err = generateListenSecret()
+		if err != nil {
+			return err
+		}
+	}
+
+	handler := &webhook.Handler{
+		Secret:  cfg.Secret,
+		Events:  events,
+		Actions: actions,
+		OnEvent: func(e *webhook.Event, accepted bool) {
+			line := fmt.Sprintf("%s  %s", time.Now().Format("15:04:05"), e.Summary())
+			if accepted {
+				ui.Info(line)
+			} else {
+				ui.Dim(line + " (ignored)")
+			}
+		},
+		OnResult: func(r webhook.Result) {
+			if r.Err != nil {
+				ui.Warning(fmt.Sprintf("%s action failed: %v", r.Action, r.Err))
+			}
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(webhookPath, handler)
+
+	server := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ui.Section("Listening for Coolify webhooks")
+	ui.KeyValue("Address", cfg.Addr)
+	ui.KeyValue("Webhook URL", fmt.Sprintf("http://%s%s?token=%s", listenHost(cfg.Addr), webhookPath, cfg.Secret))
+	ui.KeyValue("Events", listenEventsLabel(events))
+	names := make([]string, 0, len(actions))
+	for _, a := range actions {
+		names = append(names, a.Name())
+	}
+	ui.KeyValue("Actions", strings.Join(names, ", "))
+	ui.Spacer()
+	ui.Dim("Press Ctrl+C to stop")
+	ui.Spacer()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("webhook server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to stop webhook server: %w", err)
+	}
+
+	ui.Spacer()
+	ui.Dim("Stopped")
+	return nil
+}
+
+// listenConfig returns the stored listen defaults, if the config can be loaded
+func listenConfig() config.ListenConfig {
+	if err := config.Initialize(); err != nil {
+		return config.ListenConfig{}
+	}
+	if cfg := config.Get(); cfg != nil {
+		return cfg.Listen
+	}
+	return config.ListenConfig{}
+}
+
+// parseEventTypes validates event type names
+func parseEventTypes(names []string) ([]webhook.EventType, error) {
+	types := make([]webhook.EventType, 0, len(names))
+	for _, name := range names {
+		t := webhook.EventType(strings.ToLower(strings.TrimSpace(name)))
+		valid := false
+		for _, known := range webhook.EventTypes {
+			if t == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown event type %q (valid: started, finished, failed, other)", name)
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+func listenEventsLabel(events []webhook.EventType) string {
+	if len(events) == 0 {
+		return "all"
+	}
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = string(e)
+	}
+	return strings.Join(names, ", ")
+}
+
+// listenHost returns a host to show in the webhook URL for addr
+func listenHost(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "<this-host>" + addr
+	}
+	return addr
+}
+
+// generateListenSecret returns a random secret for a single listen session
+func generateListenSecret() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
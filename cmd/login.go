@@ -33,6 +33,13 @@ Optional:
 }
 
 func runLogin(cmd *cobra.Command, args []string) error {
+	if !ui.Interactive() {
+		return &ui.NonInteractiveError{
+			What: "Coolify Authentication",
+			Hint: fmt.Sprintf("set %s and %s instead, which need no login", config.EnvFQDN, config.EnvToken),
+		}
+	}
+
 	// Load existing config if any
 	cfg, err := config.LoadGlobal()
 	if err != nil {
@@ -74,16 +81,17 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	// Validate credentials
 	ui.Spacer()
 	ui.Info("Connecting to Coolify...")
-	client := api.NewClient(coolifyURL, token)
-	if err := client.HealthCheck(); err != nil {
+	info, err := api.NewClient(coolifyURL, token).Verify()
+	if err != nil {
 		ui.Error("Connection failed")
-		return fmt.Errorf("failed to connect: %w", err)
+		return err
 	}
-	ui.Success("Connected to Coolify")
+	ui.Success(fmt.Sprintf("Connected to Coolify as team %s", info.Team))
 
 	// Save instance (will be set as default if it's the first one)
 	isFirstInstance := !config.HasInstances()
-	if err := config.AddInstance(instanceName, coolifyURL, token, isFirstInstance); err != nil {
+	inst := config.Instance{Name: instanceName, FQDN: coolifyURL, Token: token, Version: info.Version, Team: info.Team}
+	if err := config.AddInstance(inst, isFirstInstance); err != nil {
 		return fmt.Errorf("failed to save instance: %w", err)
 	}
 
@@ -96,7 +104,7 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	ui.Dim("Enable git-based deployments with automatic repository management")
 	ui.Spacer()
 
-	setupGitHub, err := ui.Confirm("Configure GitHub?")
+	setupGitHub, err := ui.Ask("Configure GitHub?", false)
 	if err != nil {
 		return err
 	}
@@ -127,12 +135,141 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Step 3: Optional Docker registry setup
+	// Step 3: Optional GitLab setup
+	ui.Section("GitLab Integration (Optional)")
+	ui.Dim("Deploy from projects on gitlab.com or a self-hosted GitLab")
+	ui.Spacer()
+
+	setupGitLab, err := ui.Ask("Configure GitLab?", false)
+	if err != nil {
+		return err
+	}
+
+	if setupGitLab {
+		ui.Spacer()
+		gitlabURL, err := ui.InputWithDefault("GitLab URL", cfg.GitLabInstance())
+		if err != nil {
+			return err
+		}
+		gitlabURL = strings.TrimSuffix(gitlabURL, "/")
+		ui.Dim(fmt.Sprintf("→ Create a token at %s/-/user_settings/personal_access_tokens", gitlabURL))
+		ui.Dim("  Required scope: api")
+		ui.Spacer()
+
+		gitlabToken, err := ui.Password("GitLab Token")
+		if err != nil {
+			return err
+		}
+		if gitlabToken != "" {
+			ui.Info("Verifying GitLab token...")
+			glClient := git.NewGitLabClient(gitlabURL, gitlabToken)
+			user, err := glClient.GetUser()
+			if err != nil {
+				ui.Warning("GitLab verification failed: " + err.Error())
+			} else {
+				ui.Success("GitLab token verified")
+				cfg.GitLabToken = gitlabToken
+				cfg.GitLabURL = ""
+				if gitlabURL != config.DefaultGitLabURL {
+					cfg.GitLabURL = gitlabURL
+				}
+				ui.Spacer()
+				ui.KeyValue("GitLab user", user.Username)
+			}
+		}
+	}
+
+	// Step 4: Optional Gitea setup
+	ui.Section("Gitea Integration (Optional)")
+	ui.Dim("Deploy from repositories on a Gitea or Forgejo instance")
+	ui.Spacer()
+
+	setupGitea, err := ui.Ask("Configure Gitea?", false)
+	if err != nil {
+		return err
+	}
+
+	if setupGitea {
+		ui.Spacer()
+		giteaURL, err := ui.InputWithDefault("Gitea URL", cfg.GiteaURL)
+		if err != nil {
+			return err
+		}
+		giteaURL = strings.TrimSuffix(giteaURL, "/")
+		if giteaURL == "" {
+			ui.Warning("Gitea URL is required, skipping Gitea")
+		} else {
+			ui.Dim(fmt.Sprintf("→ Create a token at %s/user/settings/applications", giteaURL))
+			ui.Dim("  Required scopes: write:repository, read:user")
+			ui.Spacer()
+
+			giteaToken, err := ui.Password("Gitea Token")
+			if err != nil {
+				return err
+			}
+			if giteaToken != "" {
+				ui.Info("Verifying Gitea token...")
+				giteaClient := git.NewGiteaClient(giteaURL, giteaToken)
+				user, err := giteaClient.GetUser()
+				if err != nil {
+					ui.Warning("Gitea verification failed: " + err.Error())
+				} else {
+					ui.Success("Gitea token verified")
+					cfg.GiteaToken = giteaToken
+					cfg.GiteaURL = giteaURL
+					ui.Spacer()
+					ui.KeyValue("Gitea user", user.Login)
+				}
+			}
+		}
+	}
+
+	// Step 5: Optional Bitbucket setup
+	ui.Section("Bitbucket Integration (Optional)")
+	ui.Dim("Deploy from repositories on Bitbucket Cloud")
+	ui.Spacer()
+
+	setupBitbucket, err := ui.Ask("Configure Bitbucket?", false)
+	if err != nil {
+		return err
+	}
+
+	if setupBitbucket {
+		ui.Spacer()
+		ui.Dim("→ Create an app password at https://bitbucket.org/account/settings/app-passwords/")
+		ui.Dim("  Required permissions: Account read, Repositories admin and write, Webhooks read and write")
+		ui.Spacer()
+
+		bitbucketUser, err := ui.InputWithDefault("Bitbucket username", cfg.BitbucketUser)
+		if err != nil {
+			return err
+		}
+		bitbucketToken, err := ui.Password("App password")
+		if err != nil {
+			return err
+		}
+		if bitbucketUser != "" && bitbucketToken != "" {
+			ui.Info("Verifying Bitbucket app password...")
+			bbClient := git.NewBitbucketClient(bitbucketUser, bitbucketToken)
+			user, err := bbClient.GetUser()
+			if err != nil {
+				ui.Warning("Bitbucket verification failed: " + err.Error())
+			} else {
+				ui.Success("Bitbucket app password verified")
+				cfg.BitbucketUser = bitbucketUser
+				cfg.BitbucketToken = bitbucketToken
+				ui.Spacer()
+				ui.KeyValue("Bitbucket user", user.Username)
+			}
+		}
+	}
+
+	// Step 6: Optional Docker registry setup
 	ui.Section("Docker Registry (Optional)")
 	ui.Dim("Enable container-based deployments with private registries")
 	ui.Spacer()
 
-	setupDocker, err := ui.Confirm("Configure Docker registry?")
+	setupDocker, err := ui.Ask("Configure Docker registry?", false)
 	if err != nil {
 		return err
 	}
@@ -196,6 +333,15 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	if cfg.GitHubToken != "" {
 		ui.KeyValue("GitHub", "configured")
 	}
+	if cfg.GitLabToken != "" {
+		ui.KeyValue("GitLab", cfg.GitLabInstance())
+	}
+	if cfg.GiteaToken != "" {
+		ui.KeyValue("Gitea", cfg.GiteaURL)
+	}
+	if cfg.BitbucketToken != "" {
+		ui.KeyValue("Bitbucket", cfg.BitbucketUser)
+	}
 	if cfg.DockerRegistry != nil {
 		ui.KeyValue("Docker registry", cfg.DockerRegistry.URL)
 	}
@@ -32,6 +32,8 @@ func runLogout(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	clearAPICache()
+
 	ui.Success("Logged out successfully")
 	ui.Spacer()
 	ui.Dim("Run 'cdp login' to authenticate again")
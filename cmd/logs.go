@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"time"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
 	"github.com/entro314-labs/cool-kit/internal/config"
@@ -10,11 +14,42 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// logsPollInterval is how often followed logs are fetched again
+const logsPollInterval = 2 * time.Second
+
 var logsCmd = &cobra.Command{
 	Use:   "logs",
-	Short: "View deployment logs",
-	Long:  "Display logs from the most recent deployment.",
-	RunE:  runLogs,
+	Short: "View application and deployment logs",
+	Long: `Display the logs of the linked application's containers, or with --build
+the build logs of its latest deployment.
+
+In a terminal the logs open in a viewer that follows new lines. Keys:
+  ↑/↓ ←/→ pgup/pgdn   scroll
+  /                   search with a regular expression (ignores case
+                      unless the pattern has upper case letters)
+  n / N               next and previous match
+  &                   show only the lines that match
+  e                   show only info, warn or error lines and above
+  t                   jump to a time, like 14:05 or 2024-05-01T14:05:00Z
+  f                   follow new lines on and off
+  g / G               top and bottom
+  q                   quit
+
+With --plain, in non-interactive mode or when the output isn't a terminal,
+the logs are printed instead; --follow keeps printing new lines until
+interrupted.`,
+	Example: `  cool-kit logs
+  cool-kit logs --build
+  cool-kit logs --plain --lines 500 | grep -i error
+  cool-kit logs --plain --follow`,
+	RunE: runLogs,
+}
+
+func init() {
+	logsCmd.Flags().IntP("lines", "n", 200, "Number of container log lines to fetch")
+	logsCmd.Flags().BoolP("follow", "f", false, "Keep printing new lines (with --plain)")
+	logsCmd.Flags().Bool("build", false, "Show the build logs of the latest deployment")
+	logsCmd.Flags().Bool("plain", false, "Print the logs instead of opening the viewer")
 }
 
 func runLogs(cmd *cobra.Command, args []string) error {
@@ -23,6 +58,9 @@ func runLogs(cmd *cobra.Command, args []string) error {
 	}
 
 	projectCfg, err := config.LoadProject()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load project configuration: %w", err)
+	}
 	if err != nil || projectCfg == nil {
 		ui.Error("No project configuration found")
 		ui.NextSteps([]string{
@@ -45,38 +83,123 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
 
-	ui.Section("Deployment Logs")
+	lines, _ := cmd.Flags().GetInt("lines")
+	follow, _ := cmd.Flags().GetBool("follow")
+	build, _ := cmd.Flags().GetBool("build")
+	plain, _ := cmd.Flags().GetBool("plain")
 
-	ui.Info("Fetching logs...")
-	logs, err := client.GetDeploymentLogs(appUUID)
-	if err != nil {
-		ui.Error("Failed to fetch logs")
-		return fmt.Errorf("failed to fetch logs: %w", err)
+	title := fmt.Sprintf("Logs of %s", projectCfg.Name)
+	source := containerLogs(client, appUUID, lines)
+	if build {
+		title = fmt.Sprintf("Build logs of %s", projectCfg.Name)
+		source = buildLogs(client, appUUID)
 	}
 
-	if logs == "" {
+	if !plain && ui.LiveOutput() && isTerminal(os.Stdout) {
+		return ui.RunLogViewer(title, source, logsPollInterval)
+	}
+	return printLogs(source, follow)
+}
+
+// containerLogs reads the last lines of the application's container logs
+func containerLogs(client *api.Client, appUUID string, lines int) ui.LogSource {
+	return func() (string, bool, error) {
+		resp, err := client.GetApplicationLogs(context.Background(), appUUID, lines)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to fetch logs: %w", err)
+		}
+		return resp.Logs, false, nil
+	}
+}
+
+// buildLogs reads the build logs of the application's latest deployment,
+// which are done once it finishes or fails
+func buildLogs(client *api.Client, appUUID string) ui.LogSource {
+	return func() (string, bool, error) {
+		deployments, err := client.ListDeployments(appUUID)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		if len(deployments) == 0 {
+			return "", false, nil
+		}
+		deployUUID := deployments[0].DeploymentUUID
+		if deployUUID == "" {
+			deployUUID = deployments[0].UUID
+		}
+		detail, err := client.GetDeployment(deployUUID)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to fetch deployment %s: %w", deployUUID, err)
+		}
+		switch strings.ToLower(detail.Status) {
+		case "finished", "failed", "error", "cancelled":
+			return api.ParseLogsWithTimestamps(detail.Logs), true, nil
+		}
+		return api.ParseLogsWithTimestamps(detail.Logs), false, nil
+	}
+}
+
+// printLogs prints the logs of source colored by level. With follow, it
+// keeps printing the lines that weren't there before until source is done
+// or it's interrupted.
+func printLogs(source ui.LogSource, follow bool) error {
+	logs, done, err := source()
+	if err != nil {
+		return err
+	}
+	if logs == "" && !follow {
 		ui.Dim("No logs available yet")
-		ui.Spacer()
-		ui.NextSteps([]string{
-			"Wait for deployment to start",
-			fmt.Sprintf("Run '%s logs' again to check", execName()),
-		})
+		return nil
+	}
+	printed := printNewLines(nil, logs)
+	if !follow || done {
 		return nil
 	}
 
-	// Display logs
-	ui.Spacer()
-	logStream := ui.NewLogStream()
-
-	// Process and display logs line by line
-	lines := strings.Split(logs, "\n")
-	for _, line := range lines {
-		if line != "" {
-			logStream.WriteRaw(line + "\n")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		logs, done, err := source()
+		if err != nil {
+			ui.Warning(err.Error())
+			continue
+		}
+		printed = printNewLines(printed, logs)
+		if done {
+			return nil
 		}
 	}
+}
 
-	return nil
+// printNewLines prints the lines of logs that follow the last of printed,
+// since container logs are a window that moves as lines are added, and
+// returns the lines now printed
+func printNewLines(printed []string, logs string) []string {
+	lines := strings.Split(strings.TrimRight(logs, "\n"), "\n")
+	if logs == "" {
+		lines = nil
+	}
+	start := 0
+	if len(printed) > 0 {
+		last := printed[len(printed)-1]
+		for i := len(lines) - 1; i >= 0; i-- {
+			if lines[i] == last {
+				start = i + 1
+				break
+			}
+		}
+	}
+	if start < len(lines) {
+		fmt.Println(ui.RenderLogs(strings.Join(lines[start:], "\n")))
+	}
+	return lines
 }
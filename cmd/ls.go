@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
@@ -12,34 +13,59 @@ import (
 
 var lsCmd = &cobra.Command{
 	Use:     "ls",
-	Aliases: []string{"list", "status"},
+	Aliases: []string{"list"},
 	Short:   "List project deployments",
 	Long:    "Display all environments and their deployment status for this project.",
 	RunE:    runLs,
 }
 
 func runLs(cmd *cobra.Command, args []string) error {
-	if err := checkLogin(); err != nil {
+	format, err := structuredFormat(cmd)
+	if err != nil {
+		return err
+	}
+	projectCfg, client, err := linkedProject()
+	if err != nil {
 		return err
 	}
 
+	if format != "" {
+		return printProjectRecord(format, client, projectCfg)
+	}
+	_, err = showProject(client, projectCfg)
+	return err
+}
+
+// linkedProject loads the project config and a client for its instance
+func linkedProject() (*config.ProjectConfig, *api.Client, error) {
+	if err := checkLogin(); err != nil {
+		return nil, nil, err
+	}
+
 	projectCfg, err := config.LoadProject()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to load project configuration: %w", err)
+	}
 	if err != nil || projectCfg == nil {
 		ui.Error("No project configuration found")
 		ui.NextSteps([]string{
 			fmt.Sprintf("Run '%s' to set up a new project", execName()),
 			fmt.Sprintf("Run '%s link' to link to an existing app", execName()),
 		})
-		return fmt.Errorf("not linked to a project")
+		return nil, nil, fmt.Errorf("not linked to a project")
 	}
 
 	globalCfg, err := config.LoadGlobal()
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	return projectCfg, newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken), nil
+}
 
+// showProject prints the project and the status of its application, and
+// returns the application, nil when it isn't deployed yet
+func showProject(client *api.Client, projectCfg *config.ProjectConfig) (*api.Application, error) {
 	ui.Section(fmt.Sprintf("Project: %s", projectCfg.Name))
 
 	appUUID := projectCfg.AppUUID
@@ -48,41 +74,24 @@ func runLs(cmd *cobra.Command, args []string) error {
 		ui.NextSteps([]string{
 			fmt.Sprintf("Run '%s' to deploy", execName()),
 		})
-		return nil
+		return nil, nil
 	}
 
 	// Fetch application info
 	app, err := client.GetApplication(appUUID)
 	if err != nil {
 		ui.Error("Failed to fetch application info")
-		return fmt.Errorf("failed to fetch application: %w", err)
+		return nil, fmt.Errorf("failed to fetch application: %w", err)
 	}
 
-	status := app.Status
-	if status == "" {
-		status = "unknown"
-	}
+	ui.KeyValue("Status", styleAppStatus(app.Status))
 
-	// Style status based on value
-	var statusDisplay string
-	statusLower := strings.ToLower(status)
-	switch statusLower {
-	case "running":
-		statusDisplay = ui.SuccessStyle.Render(ui.IconSuccess + " " + status)
-	case "stopped", "exited":
-		statusDisplay = ui.DimStyle.Render(ui.IconDot + " " + status)
-	case "starting", "restarting":
-		statusDisplay = ui.InfoStyle.Render(ui.IconDot + " " + status)
-	case "error", "failed":
-		statusDisplay = ui.ErrorStyle.Render(ui.IconError + " " + status)
-	default:
-		statusDisplay = status
+	url := app.GetFqdn()
+	if url == "" {
+		url = app.PrimaryURL()
 	}
-
-	ui.KeyValue("Status", statusDisplay)
-
-	if app.Fqdn != nil && *app.Fqdn != "" {
-		ui.KeyValue("Production URL", ui.InfoStyle.Render(*app.Fqdn))
+	if url != "" {
+		ui.KeyValue("Production URL", ui.InfoStyle.Render(url))
 	}
 
 	if app.PreviewURLTemplate != "" {
@@ -93,5 +102,47 @@ func runLs(cmd *cobra.Command, args []string) error {
 	ui.KeyValue("Deploy method", projectCfg.DeployMethod)
 	ui.KeyValue("Framework", projectCfg.Framework)
 
-	return nil
+	return app, nil
+}
+
+// styleAppStatus colours an application or container status
+func styleAppStatus(status string) string {
+	if status == "" {
+		status = "unknown"
+	}
+
+	// Coolify reports states like "running:healthy"
+	state, _, _ := strings.Cut(strings.ToLower(status), ":")
+	switch state {
+	case "running":
+		return ui.SuccessStyle.Render(ui.IconSuccess + " " + status)
+	case "stopped", "exited":
+		return ui.DimStyle.Render(ui.IconDot + " " + status)
+	case "starting", "restarting":
+		return ui.InfoStyle.Render(ui.IconDot + " " + status)
+	case "error", "failed":
+		return ui.ErrorStyle.Render(ui.IconError + " " + status)
+	}
+	return status
+}
+
+// printProjectRecord prints the project and the status of its application
+// as JSON or YAML
+func printProjectRecord(format string, client *api.Client, projectCfg *config.ProjectConfig) error {
+	record := projectRecord{
+		Name:         projectCfg.Name,
+		AppUUID:      projectCfg.AppUUID,
+		DeployMethod: projectCfg.DeployMethod,
+		Framework:    projectCfg.Framework,
+	}
+	if projectCfg.AppUUID != "" {
+		app, err := client.GetApplication(projectCfg.AppUUID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch application: %w", err)
+		}
+		record.Status = app.Status
+		record.URL = app.PrimaryURL()
+		record.PreviewURLTemplate = app.PreviewURLTemplate
+	}
+	return formatOutput(format, record)
 }
@@ -6,7 +6,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/entro314-labs/cool-kit/internal/api"
 	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/mcp"
 	"github.com/entro314-labs/cool-kit/internal/ui"
@@ -35,19 +34,42 @@ To use with Claude Desktop, add to your config:
 Available tools exposed via MCP:
   • list_applications     - List all Coolify applications
   • get_application       - Get details of a specific application
-  • get_application_logs  - Retrieve application logs
+  • get_application_logs  - Retrieve application logs, a page at a time
+  • search_logs           - Search the logs of many applications at once
   • start_application     - Start an application
   • stop_application      - Stop an application  
   • restart_application   - Restart an application
   • deploy_application    - Trigger a deployment
   • list_deployments      - List deployments for an application
-  • get_deployment        - Get deployment details with logs`,
+  • get_deployment        - Get deployment details with logs
+  • list_databases        - List all databases
+  • get_database          - Get details of a specific database
+  • start_database        - Start a database
+  • stop_database         - Stop a database
+  • restart_database      - Restart a database
+  • list_services         - List all services and their containers
+  • restart_service       - Restart a service
+
+Use --read-only to hand the server to an assistant that may list, get and
+read logs but never deploy, start, stop or restart anything. To serve only
+some tools, list them in the login config (~/.coolify-deployer/config.json):
+
+  "mcp": {
+    "read_only": true,
+    "allowed_tools": ["list_applications", "get_application_logs"]
+  }
+
+Tools outside the policy aren't listed, and calling one fails with a
+"forbidden by policy" error (code -32001) naming the tool.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runMCP()
 	},
 }
 
+var mcpReadOnly bool
+
 func init() {
+	mcpCmd.Flags().BoolVar(&mcpReadOnly, "read-only", false, "Only serve tools that list, get or read logs")
 	rootCmd.AddCommand(mcpCmd)
 }
 
@@ -66,7 +88,7 @@ func runMCP() error {
 	}
 
 	// Create API client
-	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
 
 	// Verify connection
 	if err := client.HealthCheck(); err != nil {
@@ -74,7 +96,16 @@ func runMCP() error {
 		return err
 	}
 
+	policy := mcp.Policy{ReadOnly: mcpReadOnly}
+	if globalCfg.MCP != nil {
+		policy.ReadOnly = policy.ReadOnly || globalCfg.MCP.ReadOnly
+		policy.AllowedTools = globalCfg.MCP.AllowedTools
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
 	// Start MCP server
-	server := mcp.NewMCPServer(client)
+	server := mcp.NewMCPServer(client, policy)
 	return server.Start()
 }
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the application in the browser",
+	Long: `Open the linked application's URL in the browser.
+
+Use --dashboard to open the application's page in the Coolify dashboard, or
+--deployment to open the logs of its latest deployment.
+
+Examples:
+  cool-kit open                # Open the app's domain
+  cool-kit open --dashboard    # Open the app in Coolify
+  cool-kit open --deployment   # Open the latest deployment logs`,
+	Args: cobra.NoArgs,
+	RunE: runOpen,
+}
+
+func init() {
+	openCmd.Flags().Bool("dashboard", false, "Open the application in the Coolify dashboard")
+	openCmd.Flags().Bool("deployment", false, "Open the latest deployment in the Coolify dashboard")
+	openCmd.Flags().Bool("print", false, "Print the URL instead of opening it")
+	openCmd.MarkFlagsMutuallyExclusive("dashboard", "deployment")
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	dashboard, _ := cmd.Flags().GetBool("dashboard")
+	deployment, _ := cmd.Flags().GetBool("deployment")
+	printOnly, _ := cmd.Flags().GetBool("print")
+
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	projectCfg, err := config.LoadProject()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load project configuration: %w", err)
+	}
+	if err != nil || projectCfg == nil {
+		return fmt.Errorf("not linked to a project. Run '%s' or '%s link' first", execName(), execName())
+	}
+	if projectCfg.AppUUID == "" {
+		return fmt.Errorf("no application found. Deploy first with '%s'", execName())
+	}
+
+	globalCfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+
+	app, err := client.GetApplication(projectCfg.AppUUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch application: %w", err)
+	}
+
+	var url string
+	switch {
+	case dashboard:
+		url, err = appDashboardURL(client, globalCfg.CoolifyURL, projectCfg, app)
+	case deployment:
+		url, err = latestDeploymentURL(client, globalCfg.CoolifyURL, projectCfg, app)
+	default:
+		url = app.PrimaryURL()
+		if url == "" {
+			err = fmt.Errorf("application %s has no domain; use --dashboard to open it in Coolify", app.Name)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if printOnly {
+		fmt.Println(url)
+		return nil
+	}
+
+	if err := openBrowser(url); err != nil {
+		ui.Warning("Could not open a browser")
+		fmt.Println(url)
+		return nil
+	}
+	ui.Success(fmt.Sprintf("Opened %s", url))
+	return nil
+}
+
+// appDashboardURL returns the application's page in the Coolify dashboard
+func appDashboardURL(client *api.Client, baseURL string, projectCfg *config.ProjectConfig, app *api.Application) (string, error) {
+	projectUUID, environmentUUID := projectCfg.ProjectUUID, projectCfg.EnvironmentUUID
+	if projectUUID == "" || environmentUUID == "" {
+		var err error
+		projectUUID, environmentUUID, err = findAppEnvironment(client, app.EnvironmentID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%s/project/%s/environment/%s/application/%s",
+		strings.TrimSuffix(baseURL, "/"), projectUUID, environmentUUID, app.UUID), nil
+}
+
+// latestDeploymentURL returns the dashboard page of the application's latest deployment
+func latestDeploymentURL(client *api.Client, baseURL string, projectCfg *config.ProjectConfig, app *api.Application) (string, error) {
+	deployments, err := client.ListDeployments(app.UUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list deployments: %w", err)
+	}
+	if len(deployments) == 0 {
+		return "", fmt.Errorf("application %s has no deployments", app.Name)
+	}
+	deploymentUUID := deployments[0].DeploymentUUID
+
+	// Prefer the URL Coolify reports for the deployment
+	if detail, err := client.GetDeployment(deploymentUUID); err == nil && detail.DeploymentURL != "" {
+		if strings.HasPrefix(detail.DeploymentURL, "http") {
+			return detail.DeploymentURL, nil
+		}
+		return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(detail.DeploymentURL, "/"), nil
+	}
+
+	appURL, err := appDashboardURL(client, baseURL, projectCfg, app)
+	if err != nil {
+		return "", err
+	}
+	return appURL + "/deployment/" + deploymentUUID, nil
+}
+
+// findAppEnvironment returns the project and environment UUIDs of an environment ID
+func findAppEnvironment(client *api.Client, environmentID int) (string, string, error) {
+	projects, err := client.ListProjects()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	for _, p := range projects {
+		// The project list omits environments, so fetch each project
+		project, err := client.GetProject(p.UUID)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get project %s: %w", p.Name, err)
+		}
+		for _, env := range project.Environments {
+			if env.ID == environmentID {
+				return project.UUID, env.UUID, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("could not find the application's project")
+}
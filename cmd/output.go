@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/output"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// The records below are what list and get commands print with --format json,
+// pretty or yaml. Their field names are part of the CLI's interface: add
+// fields, but don't rename or remove them. Empty values are kept, so every
+// record of a kind has the same keys.
+
+// appRecord is an application
+type appRecord struct {
+	UUID          string `json:"uuid"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	URL           string `json:"url"`
+	GitRepository string `json:"git_repository"`
+	GitBranch     string `json:"git_branch"`
+	BuildPack     string `json:"build_pack"`
+}
+
+func newAppRecord(app *api.Application) appRecord {
+	return appRecord{
+		UUID:          app.UUID,
+		Name:          app.Name,
+		Status:        app.Status,
+		URL:           app.PrimaryURL(),
+		GitRepository: app.GitRepository,
+		GitBranch:     app.GitBranch,
+		BuildPack:     app.BuildPack,
+	}
+}
+
+// projectRecord is the linked project and its application
+type projectRecord struct {
+	Name               string `json:"name"`
+	AppUUID            string `json:"app_uuid"`
+	Status             string `json:"status"`
+	URL                string `json:"url"`
+	PreviewURLTemplate string `json:"preview_url_template"`
+	DeployMethod       string `json:"deploy_method"`
+	Framework          string `json:"framework"`
+}
+
+// statusRecord is the linked project with its server's resource usage,
+// its containers and its latest deployments. Server is null when the
+// server couldn't be read.
+type statusRecord struct {
+	projectRecord
+	Server      *serverUsageRecord `json:"server"`
+	Containers  []containerRecord  `json:"containers"`
+	Deployments []deploymentRecord `json:"recent_deployments"`
+}
+
+// serverUsageRecord is a server's resource usage, in percent. CPU and
+// memory are null without Sentinel.
+type serverUsageRecord struct {
+	UUID          string   `json:"uuid"`
+	Name          string   `json:"name"`
+	CPU           *float64 `json:"cpu_percent"`
+	Memory        *float64 `json:"memory_percent"`
+	Disk          *float64 `json:"disk_percent"`
+	SentinelError string   `json:"sentinel_error"`
+}
+
+// containerRecord is a container of an application
+type containerRecord struct {
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Health    string `json:"health"`
+	Restarts  int    `json:"restarts"`
+	StartedAt string `json:"started_at"`
+}
+
+// serverRecord is a server. Status is ready, reachable, unreachable or unknown.
+type serverRecord struct {
+	UUID      string `json:"uuid"`
+	Name      string `json:"name"`
+	IP        string `json:"ip"`
+	User      string `json:"user"`
+	Port      int    `json:"port"`
+	Status    string `json:"status"`
+	Reachable bool   `json:"reachable"`
+	Usable    bool   `json:"usable"`
+}
+
+func newServerRecord(s *api.Server) serverRecord {
+	r := serverRecord{
+		UUID:   s.UUID,
+		Name:   s.Name,
+		IP:     s.IP,
+		User:   s.User,
+		Port:   s.Port,
+		Status: "unknown",
+	}
+	if s.Settings != nil {
+		r.Reachable = s.Settings.IsReachable
+		r.Usable = s.Settings.IsUsable
+		switch {
+		case s.IsReady():
+			r.Status = "ready"
+		case r.Reachable:
+			r.Status = "reachable"
+		default:
+			r.Status = "unreachable"
+		}
+	}
+	return r
+}
+
+// deploymentRecord is a deployment. PullRequest is 0 for production, and
+// DurationSeconds is null while the deployment runs.
+type deploymentRecord struct {
+	UUID            string   `json:"uuid"`
+	Status          string   `json:"status"`
+	PullRequest     int      `json:"pull_request"`
+	Commit          string   `json:"commit"`
+	CommitMessage   string   `json:"commit_message"`
+	Author          string   `json:"author"`
+	CreatedAt       string   `json:"created_at"`
+	DurationSeconds *float64 `json:"duration_seconds"`
+}
+
+func newDeploymentRecord(d *api.Deployment, author string) deploymentRecord {
+	r := deploymentRecord{
+		UUID:          d.DeploymentUUID,
+		Status:        d.Status,
+		PullRequest:   d.PullRequest(),
+		Commit:        d.CommitSHA(),
+		CommitMessage: d.CommitMessage,
+		Author:        author,
+	}
+	if created := d.Created(); !created.IsZero() {
+		r.CreatedAt = created.UTC().Format(time.RFC3339)
+	}
+	if duration := d.Duration(); duration > 0 {
+		seconds := duration.Seconds()
+		r.DurationSeconds = &seconds
+	}
+	return r
+}
+
+// envRecord is an environment variable. Values of keys that look secret are
+// masked, as in the table; 'env pull' writes the values.
+type envRecord struct {
+	UUID      string `json:"uuid"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Preview   bool   `json:"preview"`
+	BuildTime bool   `json:"build_time"`
+}
+
+func newEnvRecord(env *api.EnvVar) envRecord {
+	value := env.Value
+	if isSecretKey(env.Key) {
+		value = output.SensitiveOverlay
+	}
+	return envRecord{
+		UUID:      env.UUID,
+		Key:       env.Key,
+		Value:     value,
+		Preview:   env.IsPreview,
+		BuildTime: env.IsBuildTime,
+	}
+}
+
+// isSecretKey reports variable names whose values aren't shown
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "secret") ||
+		strings.Contains(key, "password") ||
+		strings.Contains(key, "token")
+}
+
+// instanceRecord is a configured instance, without its token
+type instanceRecord struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Team    string `json:"team"`
+	Version string `json:"version"`
+	Default bool   `json:"default"`
+	Current bool   `json:"current"`
+}
+
+func newInstanceRecord(inst *config.Instance, current string) instanceRecord {
+	return instanceRecord{
+		Name:    inst.Name,
+		URL:     inst.FQDN,
+		Team:    inst.Team,
+		Version: inst.Version,
+		Default: inst.Default,
+		Current: inst.Name == current,
+	}
+}
+
+// structuredFormat returns the --format of cmd when it's json, pretty or
+// yaml, or "" for the table
+func structuredFormat(cmd *cobra.Command) (string, error) {
+	format, _ := cmd.Flags().GetString("format")
+	switch format {
+	case "", output.FormatTable:
+		return "", nil
+	case output.FormatJSON, output.FormatPretty, output.FormatYAML, "yml":
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported format: %s (use table, json, pretty or yaml)", format)
+}
+
+// loadTask runs task with a spinner, or quietly when the output is JSON or
+// YAML and must stay parseable
+func loadTask(format string, task ui.Task) error {
+	if format != "" {
+		return task.Action()
+	}
+	return ui.RunTasks([]ui.Task{task})
+}
+
+// addTableFlags adds the flags that select how a list command's table is shown
+func addTableFlags(cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		c.Flags().String("sort", "", "Sort by a column, descending with a leading - (like --sort=-started)")
+		c.Flags().StringSlice("columns", nil, "Columns to show, in order (comma-separated)")
+		c.Flags().StringArray("filter", nil, "Only show rows where column=text or column!=text, or with text in any column (repeatable)")
+		c.Flags().Bool("wide", false, "Show every column in full instead of fitting the terminal")
+	}
+}
+
+// printTable prints a list command's table with the options of its flags
+func printTable(cmd *cobra.Command, t ui.ListTable) error {
+	var opts ui.TableOptions
+	opts.Sort, _ = cmd.Flags().GetString("sort")
+	opts.Columns, _ = cmd.Flags().GetStringSlice("columns")
+	opts.Filters, _ = cmd.Flags().GetStringArray("filter")
+	opts.Wide, _ = cmd.Flags().GetBool("wide")
+	return t.PrintTable(opts)
+}
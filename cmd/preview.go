@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/dotenv"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// defaultPreviewEnvFile holds preview-only variables applied by 'deploy --pr'
+const defaultPreviewEnvFile = ".env.preview"
+
+var previewCmd = &cobra.Command{
+	Use:     "preview",
+	Aliases: []string{"previews"},
+	Short:   "Manage pull request preview deployments",
+	Long: `List and remove the preview deployments of pull requests.
+
+Create or refresh a preview with 'cool-kit deploy --pr N'.
+
+Available Commands:
+  preview ls  - List preview deployments
+  preview rm  - Remove a pull request preview`,
+}
+
+var previewListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List preview deployments",
+	RunE:  runPreviewList,
+}
+
+var previewRemoveCmd = &cobra.Command{
+	Use:   "rm PR",
+	Short: "Remove a pull request preview",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPreviewRemove,
+}
+
+func init() {
+	previewCmd.AddCommand(previewListCmd)
+	previewCmd.AddCommand(previewRemoveCmd)
+	addTableFlags(previewListCmd)
+}
+
+func runPreviewList(cmd *cobra.Command, args []string) error {
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	ui.Section("Preview Deployments")
+
+	var (
+		app         *api.Application
+		deployments []api.Deployment
+	)
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-previews",
+			ActiveName:   "Loading previews...",
+			CompleteName: "✓ Loaded previews",
+			Action: func() error {
+				var err error
+				if app, err = client.GetApplication(appUUID); err != nil {
+					return err
+				}
+				deployments, err = client.ListDeployments(appUUID)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load previews")
+		return fmt.Errorf("failed to list previews: %w", err)
+	}
+
+	// Keep the most recent deployment of each pull request
+	latest := make(map[int]api.Deployment)
+	for _, d := range deployments {
+		pr := d.PullRequest()
+		if pr == 0 {
+			continue
+		}
+		if prev, ok := latest[pr]; !ok || d.CreatedAt > prev.CreatedAt {
+			latest[pr] = d
+		}
+	}
+
+	if len(latest) == 0 {
+		ui.Dim("No preview deployments found")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s deploy --pr N' to deploy a pull request", execName()),
+		})
+		return nil
+	}
+
+	prs := make([]int, 0, len(latest))
+	for pr := range latest {
+		prs = append(prs, pr)
+	}
+	sort.Ints(prs)
+
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "PR"},
+		{Name: "Status", Status: true},
+		{Name: "Commit"},
+		{Name: "URL"},
+		{Name: "Deployed"},
+	}}
+	for _, pr := range prs {
+		d := latest[pr]
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprintf("#%d", pr),
+			d.Status,
+			shortCommit(d.CommitSHA()),
+			app.PreviewURL(pr),
+			formatDeployTime(d.Created()),
+		})
+	}
+
+	ui.Spacer()
+	return printTable(cmd, table)
+}
+
+func runPreviewRemove(cmd *cobra.Command, args []string) error {
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	pr, err := strconv.Atoi(args[0])
+	if err != nil || pr <= 0 {
+		return fmt.Errorf("invalid pull request number: %s", args[0])
+	}
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	if !skipConfirm {
+		confirmed, err := ui.ConfirmAction("remove", fmt.Sprintf("preview for PR #%d", pr))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Dim("Cancelled")
+			return nil
+		}
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "remove-preview",
+			ActiveName:   fmt.Sprintf("Removing preview for PR #%d...", pr),
+			CompleteName: fmt.Sprintf("✓ Removed preview for PR #%d", pr),
+			Action: func() error {
+				return client.DeleteApplicationPreview(appUUID, pr)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to remove preview")
+		return fmt.Errorf("failed to remove preview: %w", err)
+	}
+
+	return nil
+}
+
+// applyPreviewEnvFile creates or updates preview variables from a dotenv file.
+// A missing default file is not an error.
+func applyPreviewEnvFile(client *api.Client, appUUID, file string) error {
+	if _, err := os.Stat(file); os.IsNotExist(err) && file == defaultPreviewEnvFile {
+		return nil
+	}
+
+	local, err := dotenv.ParseFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	remote, err := listTargetEnvs(client, appUUID, true)
+	if err != nil {
+		return fmt.Errorf("failed to fetch preview variables: %w", err)
+	}
+
+	changes := diffEnvs(remote, local, envDiffOptions{preview: true})
+	if len(changes) == 0 {
+		ui.Dim(fmt.Sprintf("Preview variables from %s are up to date", file))
+		return nil
+	}
+
+	envs := make([]api.EnvironmentVariable, 0, len(changes))
+	for _, c := range changes {
+		envs = append(envs, c.env)
+	}
+
+	return ui.RunTasks([]ui.Task{
+		{
+			Name:         "preview-envs",
+			ActiveName:   fmt.Sprintf("Applying %d preview variables from %s...", len(envs), file),
+			CompleteName: fmt.Sprintf("✓ Applied %d preview variables", len(envs)),
+			Action: func() error {
+				_, err := client.UpdateApplicationEnvsBulk(context.Background(), appUUID, envs)
+				return err
+			},
+		},
+	})
+}
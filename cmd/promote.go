@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/gitops"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote --to INSTANCE",
+	Short: "Copy an application to another Coolify instance",
+	Long: `Re-create an application and the databases of its environment on another
+configured instance, e.g. from staging to production. Settings, domains and
+non-secret env vars are copied; secrets and shown-once variables aren't, so
+set them on the target afterwards. Running promote again updates the copy.
+
+The target project, environment and servers are asked for, defaulting to
+the source names; pass them as flags to skip the prompts, or --yes to take
+the defaults and apply without confirmation. --app selects the
+application on the source instance (a UUID or name), otherwise the linked
+one is promoted.
+
+Examples:
+  cool-kit promote --from staging --to prod --app my-api
+  cool-kit promote --to prod --project shop --environment production --server main -y
+  cool-kit promote --to prod --domain https://api.example.com --deploy`,
+	Args: cobra.NoArgs,
+	RunE: runPromote,
+}
+
+func init() {
+	promoteCmd.Flags().String("from", "", "Source instance (default: current instance)")
+	promoteCmd.Flags().String("to", "", "Target instance")
+	promoteCmd.Flags().String("project", "", "Target project (default: same name)")
+	promoteCmd.Flags().String("environment", "", "Target environment (default: same name)")
+	promoteCmd.Flags().String("server", "", "Target server name or UUID")
+	promoteCmd.Flags().String("domain", "", "Comma-separated domains on the target (default: same domains)")
+	promoteCmd.Flags().Bool("deploy", false, "Deploy the application on the target")
+	_ = promoteCmd.MarkFlagRequired("to")
+}
+
+// promoteTarget is where on the target instance the application goes
+type promoteTarget struct {
+	project     string
+	environment string
+	server      string
+	domains     string
+}
+
+func runPromote(cmd *cobra.Command, args []string) error {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	appRef, _ := cmd.Flags().GetString("app")
+	yes, _ := cmd.Flags().GetBool("yes")
+	deploy, _ := cmd.Flags().GetBool("deploy")
+
+	if err := checkLogin(); err != nil {
+		return err
+	}
+
+	var source *config.Instance
+	var err error
+	if from == "" {
+		source, err = getCurrentInstance()
+	} else {
+		source, err = config.GetInstance(from)
+	}
+	if err != nil {
+		return err
+	}
+	target, err := config.GetInstance(to)
+	if err != nil {
+		return err
+	}
+	if source.Name == target.Name {
+		return fmt.Errorf("source and target are the same instance: %s", target.Name)
+	}
+	srcClient := newAPIClient(source.FQDN, source.Token)
+	dstClient := newAPIClient(target.FQDN, target.Token)
+
+	ui.Section(fmt.Sprintf("Promote %s → %s", source.Name, target.Name))
+
+	appUUID, err := resolveAppRef(srcClient, appRef)
+	if err != nil {
+		if errors.Is(err, errNotLinkedApp) {
+			return fmt.Errorf("%w: pass --app", err)
+		}
+		return err
+	}
+
+	var spec *gitops.Spec
+	var skipped []string
+	var targetState *gitops.State
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-source",
+			ActiveName:   fmt.Sprintf("Loading application from %s...", source.Name),
+			CompleteName: fmt.Sprintf("✓ Loaded application from %s", source.Name),
+			Action: func() error {
+				state, err := gitops.FetchApplicationState(srcClient, appUUID)
+				if err != nil {
+					return err
+				}
+				spec, skipped, err = gitops.Export(state, gitops.ExportOptions{
+					Secrets:     gitops.SecretsRedact,
+					Application: appUUID,
+				})
+				return err
+			},
+		},
+		{
+			Name:         "load-target",
+			ActiveName:   fmt.Sprintf("Loading resources on %s...", target.Name),
+			CompleteName: fmt.Sprintf("✓ Loaded resources on %s", target.Name),
+			Action: func() error {
+				var err error
+				targetState, err = gitops.FetchState(dstClient)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load resources")
+		return err
+	}
+
+	project := &spec.Projects[0]
+	env := &project.Environments[0]
+	app := &env.Applications[0]
+
+	// Shown-once variables come back as references; drop them like secrets
+	for _, key := range skipped {
+		delete(app.Env, strings.TrimPrefix(key, app.Name+"/"))
+	}
+
+	dest, err := promptPromoteTarget(cmd, targetState, project.Name, env.Name, app, yes)
+	if err != nil {
+		return err
+	}
+	project.Name = dest.project
+	env.Name = dest.environment
+	app.Server = dest.server
+	for i := range env.Databases {
+		env.Databases[i].Server = dest.server
+	}
+	if dest.domains != "" {
+		app.Domains = nil
+		for _, d := range strings.Split(dest.domains, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				app.Domains = append(app.Domains, d)
+			}
+		}
+	}
+	if app.GitHubApp != "" {
+		if app.GitHubApp, err = promptPromoteGitHubApp(targetState, app.GitHubApp, yes); err != nil {
+			return err
+		}
+	}
+
+	changes, err := gitops.Plan(spec, targetState, gitops.PlanOptions{})
+	if err != nil {
+		return err
+	}
+	printPlan(changes)
+	if len(skipped) > 0 {
+		ui.Spacer()
+		ui.Warning(fmt.Sprintf("%d secret variable(s) won't be copied:", len(skipped)))
+		for _, key := range skipped {
+			ui.Dim("  " + strings.TrimPrefix(key, app.Name+"/"))
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if !yes {
+		ui.Spacer()
+		confirmed, err := ui.Confirm(fmt.Sprintf("Apply %d change(s) to %s?", len(changes), target.Name))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Dim("Cancelled")
+			return nil
+		}
+	}
+
+	ui.Spacer()
+	result, err := gitops.Apply(dstClient, targetState, changes, func(c gitops.Change) {
+		fmt.Printf("  %s %s %s\n", changeSymbol(c.Action), c.Kind, c.Path)
+	})
+	if err != nil {
+		ui.Error("Promote stopped")
+		return err
+	}
+	ui.Spacer()
+	ui.Success(fmt.Sprintf("Promoted %s to %s", app.Name, target.Name))
+
+	var steps []string
+	if len(skipped) > 0 {
+		steps = append(steps, fmt.Sprintf("Set the skipped secret variables on %s", target.Name))
+	}
+	if deploy {
+		for _, uuid := range result.Applications {
+			if _, err := dstClient.Deploy(uuid, false, 0); err != nil {
+				return fmt.Errorf("failed to trigger deployment: %w", err)
+			}
+		}
+		ui.Success("Deployment triggered")
+	} else if len(result.Applications) > 0 {
+		steps = append(steps, "Deploy it on the target, or rerun with --deploy")
+	}
+	if len(steps) > 0 {
+		ui.NextSteps(steps)
+	}
+	return nil
+}
+
+// promptPromoteTarget maps the source project, environment and server onto
+// the target instance, asking for whatever flags don't set
+func promptPromoteTarget(cmd *cobra.Command, state *gitops.State, project, environment string, app *gitops.Application, yes bool) (*promoteTarget, error) {
+	t := &promoteTarget{}
+	t.project, _ = cmd.Flags().GetString("project")
+	t.environment, _ = cmd.Flags().GetString("environment")
+	t.server, _ = cmd.Flags().GetString("server")
+	t.domains, _ = cmd.Flags().GetString("domain")
+
+	var err error
+	if t.project == "" {
+		t.project = project
+		if !yes && len(state.Projects) > 0 {
+			options := []ui.PickOption{}
+			exists := false
+			for _, p := range state.Projects {
+				options = append(options, ui.PickOption{Key: p.Name, Label: p.Name})
+				exists = exists || strings.EqualFold(p.Name, project)
+			}
+			if !exists {
+				options = append([]ui.PickOption{{Key: project, Label: project + " (new)"}}, options...)
+			}
+			ui.Spacer()
+			if t.project, err = ui.Pick("Target project:", ui.PickProject, options); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if t.environment == "" {
+		t.environment = environment
+		if !yes {
+			if t.environment, err = ui.InputWithDefault("Target environment:", environment); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if t.server == "" {
+		switch {
+		case len(state.Servers) == 1:
+			t.server = state.Servers[0].Name
+		case yes:
+			// Keep the source server name; planning fails if the target lacks it
+			t.server = app.Server
+		default:
+			options := make([]ui.PickOption, 0, len(state.Servers))
+			for _, s := range state.Servers {
+				options = append(options, ui.PickOption{Key: s.Name, Label: s.Name})
+			}
+			if t.server, err = ui.Pick("Target server:", ui.PickServer, options); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if t.domains == "" && !yes && len(app.Domains) > 0 {
+		current := strings.Join(app.Domains, ",")
+		if t.domains, err = ui.InputWithDefault("Domains on the target:", current); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// promptPromoteGitHubApp maps the source GitHub App onto one installed on the
+// target instance
+func promptPromoteGitHubApp(state *gitops.State, name string, yes bool) (string, error) {
+	options := make([]string, 0, len(state.GitHubApps))
+	for _, gh := range state.GitHubApps {
+		if gh.Name == name {
+			return name, nil
+		}
+		options = append(options, gh.Name)
+	}
+	if len(options) == 0 {
+		return "", fmt.Errorf("the target has no GitHub App to deploy the private repository with: add one in Coolify first")
+	}
+	if yes {
+		return "", fmt.Errorf("GitHub App %s isn't on the target: run without -y to pick one", name)
+	}
+	return ui.Select(fmt.Sprintf("GitHub App %s isn't on the target. Use:", name), options)
+}
@@ -5,7 +5,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/entro314-labs/cool-kit/internal/api"
 	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/git"
 	"github.com/entro314-labs/cool-kit/internal/ui"
@@ -51,6 +50,11 @@ func runReset(cmd *cobra.Command, args []string) error {
 	if projectCfg.AppUUID != "" {
 		fmt.Printf("  Coolify app: %s\n", projectCfg.AppUUID)
 	}
+	for _, w := range projectCfg.Workers {
+		if w.AppUUID != "" {
+			fmt.Printf("  Coolify worker: %s (%s)\n", w.AppName(projectCfg), w.AppUUID)
+		}
+	}
 	fmt.Println()
 
 	confirm, err := ui.Confirm("Are you sure? This cannot be undone!")
@@ -70,7 +74,7 @@ func runReset(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cancelled")
 	}
 
-	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
 
 	// Delete Coolify app
 	if projectCfg.AppUUID != "" {
@@ -82,6 +86,16 @@ func runReset(cmd *cobra.Command, args []string) error {
 			ui.Success("Deleted Coolify app")
 		}
 	}
+	for _, w := range projectCfg.Workers {
+		if w.AppUUID == "" {
+			continue
+		}
+		if err := client.DeleteApplication(w.AppUUID); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to delete worker %s: %v", w.AppName(projectCfg), err))
+		} else {
+			ui.Success("Deleted worker " + w.AppName(projectCfg))
+		}
+	}
 
 	// Delete Coolify project with retries
 	// (Coolify requires all resources to be deleted first)
@@ -2,27 +2,74 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/appdeploy"
 	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+// maxRollbackTargets limits how many previous versions are offered
+const maxRollbackTargets = 10
+
 var rollbackCmd = &cobra.Command{
-	Use:   "rollback",
+	Use:   "rollback [deployment-uuid]",
 	Short: "Rollback to a previous deployment",
-	Long:  "List recent deployments and rollback to a previous version.",
-	RunE:  runRollback,
+	Long: `List recent successful deployments and redeploy a previous version.
+
+Git deployments are rolled back to the selected commit; Coolify reuses the
+image it built for that commit when it is still on the server. Docker
+deployments are rolled back to a previously pushed image tag.
+
+A git rollback pins the application to the commit: Coolify keeps deploying
+it, also on pushes, until the next 'deploy', 'ci deploy' or 'apps redeploy'
+moves the application back to the latest code.
+
+Examples:
+  cool-kit rollback                  # Pick a previous deployment
+  cool-kit rollback 3f2a9c1 --yes    # Roll back to a deployment, commit or image tag`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRollback,
+}
+
+func init() {
+	rollbackCmd.Flags().Bool("no-watch", false, "Don't wait for the rollback deployment to finish")
+}
+
+// rollbackTarget is a previous version that can be redeployed
+type rollbackTarget struct {
+	// id is the deployment UUID, or the image tag for docker deployments
+	id      string
+	commit  string
+	image   string
+	message string
+	created time.Time
+}
+
+// label returns a short description of the target
+func (t rollbackTarget) label() string {
+	if t.commit != "" {
+		return shortCommit(t.commit)
+	}
+	return t.image
 }
 
 func runRollback(cmd *cobra.Command, args []string) error {
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+	noWatch, _ := cmd.Flags().GetBool("no-watch")
+
 	if err := checkLogin(); err != nil {
 		return err
 	}
 
 	projectCfg, err := config.LoadProject()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load project configuration: %w", err)
+	}
 	if err != nil || projectCfg == nil {
 		ui.Error("No project configuration found")
 		ui.NextSteps([]string{
@@ -31,16 +78,6 @@ func runRollback(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not linked to a project")
 	}
 
-	if projectCfg.DeployMethod == config.DeployMethodDocker {
-		ui.Error("Rollback is not supported for Docker-based deployments")
-		ui.Spacer()
-		ui.Dim("For Docker deployments, manually redeploy a previous image tag")
-		ui.NextSteps([]string{
-			fmt.Sprintf("Run '%s --help' for usage", execName()),
-		})
-		return nil
-	}
-
 	appUUID := projectCfg.AppUUID
 	if appUUID == "" {
 		ui.Error("No application found")
@@ -55,133 +92,283 @@ func runRollback(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	client := api.NewClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
+	client := newAPIClient(globalCfg.CoolifyURL, globalCfg.CoolifyToken)
 
 	ui.Section("Rollback")
 
-	// List recent deployments
-	ui.Info("Fetching deployment history...")
-	deployments, err := client.ListDeployments(appUUID)
+	var targets []rollbackTarget
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-history",
+			ActiveName:   "Fetching deployment history...",
+			CompleteName: "✓ Fetched deployment history",
+			Action: func() error {
+				var err error
+				if config.BuildsImage(projectCfg.DeployMethod) {
+					targets, err = imageRollbackTargets(client, appUUID, projectCfg)
+				} else {
+					targets, err = commitRollbackTargets(client, appUUID)
+				}
+				return err
+			},
+		},
+	})
 	if err != nil {
 		ui.Error("Failed to fetch deployments")
 		return fmt.Errorf("failed to fetch deployments: %w", err)
 	}
-	ui.Success("Fetched deployment history")
 
-	if len(deployments) < 2 {
+	if len(targets) == 0 {
 		ui.Spacer()
-		ui.Warning("No previous deployments available")
-		ui.Dim("You need at least 2 deployments to rollback")
+		ui.Warning("No previous successful deployments available")
+		ui.Dim("You need at least 2 successful deployments to rollback")
 		return nil
 	}
 
-	// Show deployment options (skip the current one)
-	ui.Spacer()
-	ui.Dim("Select a deployment to rollback to:")
-	ui.Spacer()
-
-	options := make(map[string]string)
-	for i, d := range deployments {
-		if i == 0 {
-			continue // Skip current deployment
-		}
-		if i > 10 {
-			break // Limit to last 10
-		}
-
-		commit := d.GitCommitSha
-		if len(commit) > 7 {
-			commit = commit[:7]
+	var target rollbackTarget
+	if len(args) == 1 {
+		target, err = findRollbackTarget(targets, args[0])
+		if err != nil {
+			return err
 		}
-		if commit == "" {
-			commit = "unknown"
+	} else {
+		target, err = selectRollbackTarget(targets)
+		if err != nil {
+			return err
 		}
+	}
 
-		msg := d.CommitMessage
-		if len(msg) > 40 {
-			msg = msg[:40] + "..."
+	if !skipConfirm {
+		ui.Spacer()
+		confirmed, err := ui.ConfirmAction("rollback to", target.label())
+		if err != nil {
+			return err
 		}
-		if msg == "" {
-			msg = "(no message)"
+		if !confirmed {
+			ui.Dim("Cancelled")
+			return nil
 		}
+	}
 
-		status := d.Status
-		if strings.ToLower(status) == "finished" {
-			status = ui.SuccessStyle.Render(status)
-		} else if strings.ToLower(status) == "failed" {
-			status = ui.ErrorStyle.Render(status)
-		}
+	ui.Spacer()
 
-		displayName := fmt.Sprintf("%s - %s [%s]", commit, msg, status)
-		options[d.DeploymentUUID] = displayName
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "rollback",
+			ActiveName:   fmt.Sprintf("Rolling back to %s...", target.label()),
+			CompleteName: fmt.Sprintf("✓ Rollback to %s started", target.label()),
+			Action: func() error {
+				return triggerRollback(client, appUUID, projectCfg, target)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to trigger rollback")
+		return fmt.Errorf("rollback failed: %w", err)
 	}
 
-	if len(options) == 0 {
-		ui.Warning("No previous successful deployments found")
+	if noWatch {
+		warnPinned(projectCfg, appUUID, target)
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s logs' to monitor deployment progress", execName()),
+		})
 		return nil
 	}
 
-	selectedUUID, err := ui.SelectWithKeys("Choose deployment:", options)
+	if !appdeploy.WatchDeployment(client, appUUID) {
+		ui.Spacer()
+		ui.Error(fmt.Sprintf("Rollback to %s failed", target.label()))
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s logs' to see what went wrong", execName()),
+		})
+		return fmt.Errorf("rollback deployment failed")
+	}
+
+	ui.Spacer()
+	ui.Success(fmt.Sprintf("Rolled back to %s", target.label()))
+	if config.BuildsImage(projectCfg.DeployMethod) {
+		ui.Dim("Your next deploy will move the application back to the latest code")
+	}
+	warnPinned(projectCfg, appUUID, target)
+	return nil
+}
+
+// warnPinned says that a git application stays on the rolled back commit,
+// whatever is pushed, and how to move it back to its branch
+func warnPinned(projectCfg *config.ProjectConfig, appUUID string, target rollbackTarget) {
+	if config.BuildsImage(projectCfg.DeployMethod) {
+		return
+	}
+	ui.Spacer()
+	ui.Warning(fmt.Sprintf("The application is pinned to %s: pushes keep deploying it", shortCommit(target.commit)))
+	ui.Dim(fmt.Sprintf("Run '%s deploy' or '%s apps redeploy --uuid %s' to deploy the latest code again", execName(), execName(), appUUID))
+}
+
+// commitRollbackTargets returns successful production deployments, skipping
+// the one that is currently live
+func commitRollbackTargets(client *api.Client, appUUID string) ([]rollbackTarget, error) {
+	deployments, err := client.ListDeployments(appUUID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Find the selected deployment
-	var selectedDeployment *api.Deployment
+	var (
+		targets []rollbackTarget
+		live    string
+		seen    = make(map[string]bool)
+	)
 	for _, d := range deployments {
-		if d.DeploymentUUID == selectedUUID {
-			selectedDeployment = &d
+		if !strings.EqualFold(d.Status, "finished") || d.PullRequest() != 0 {
+			continue
+		}
+		commit := d.CommitSHA()
+		if commit == "" {
+			continue
+		}
+		if live == "" {
+			live = commit
+			continue
+		}
+		// Several deployments of the live or an older commit are the same version
+		if commit == live || seen[commit] {
+			continue
+		}
+		seen[commit] = true
+
+		targets = append(targets, rollbackTarget{
+			id:      d.DeploymentUUID,
+			commit:  commit,
+			image:   fmt.Sprintf("%s:%s", appUUID, commit),
+			message: d.CommitMessage,
+			created: d.Created(),
+		})
+		if len(targets) == maxRollbackTargets {
 			break
 		}
 	}
+	return targets, nil
+}
 
-	if selectedDeployment == nil {
-		return fmt.Errorf("deployment not found")
+// imageRollbackTargets returns the image tags previously deployed from this
+// project, newest first, skipping the tag that is currently live
+func imageRollbackTargets(client *api.Client, appUUID string, projectCfg *config.ProjectConfig) ([]rollbackTarget, error) {
+	app, err := client.GetApplication(appUUID)
+	if err != nil {
+		return nil, err
 	}
+	live := app.GetDockerRegistryImageTag()
 
-	// Confirm rollback
-	commit := selectedDeployment.GitCommitSha
-	if len(commit) > 7 {
-		commit = commit[:7]
+	var targets []rollbackTarget
+	for i := len(projectCfg.ImageTags) - 1; i >= 0; i-- {
+		tag := projectCfg.ImageTags[i]
+		if tag == live {
+			continue
+		}
+		targets = append(targets, rollbackTarget{
+			id:    tag,
+			image: fmt.Sprintf("%s:%s", projectCfg.DockerImage, tag),
+		})
 	}
+	return targets, nil
+}
 
-	ui.Spacer()
-	confirmed, err := ui.ConfirmAction("rollback to", commit)
-	if err != nil {
-		return err
+// findRollbackTarget matches a deployment UUID, commit or image tag (or a prefix of one)
+func findRollbackTarget(targets []rollbackTarget, ref string) (rollbackTarget, error) {
+	var matches []rollbackTarget
+	for _, t := range targets {
+		if strings.HasPrefix(t.id, ref) || (t.commit != "" && strings.HasPrefix(t.commit, ref)) {
+			matches = append(matches, t)
+		}
 	}
-	if !confirmed {
-		ui.Dim("Cancelled")
-		return nil
+
+	switch len(matches) {
+	case 0:
+		return rollbackTarget{}, fmt.Errorf("no previous successful deployment matches %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return rollbackTarget{}, fmt.Errorf("%q matches %d deployments; use a longer prefix", ref, len(matches))
 	}
+}
 
-	ui.Spacer()
+// selectRollbackTarget shows the targets and asks which one to deploy
+func selectRollbackTarget(targets []rollbackTarget) (rollbackTarget, error) {
+	rows := make([][]string, 0, len(targets))
+	options := make(map[string]string, len(targets))
+	for _, t := range targets {
+		msg := t.message
+		if len(msg) > 40 {
+			msg = msg[:40] + "..."
+		}
 
-	// Trigger rollback
-	ui.Info("Initiating rollback...")
-	if selectedDeployment.GitCommitSha != "" {
-		err = client.UpdateApplication(appUUID, map[string]any{
-			"git_commit_sha": selectedDeployment.GitCommitSha,
-		})
-		if err != nil {
-			ui.Error("Failed to update application")
-			return fmt.Errorf("rollback failed: %w", err)
+		commit := "-"
+		if t.commit != "" {
+			commit = shortCommit(t.commit)
 		}
-	}
 
-	// Deploy with PR 0 (production)
-	_, err = client.Deploy(appUUID, true, 0)
-	if err != nil {
-		ui.Error("Failed to trigger deployment")
-		return fmt.Errorf("rollback failed: %w", err)
+		rows = append(rows, []string{commit, formatDeployTime(t.created), t.image, msg})
+
+		display := t.label()
+		if msg != "" {
+			display += " - " + msg
+		}
+		if !t.created.IsZero() {
+			display += fmt.Sprintf(" (%s)", formatDeployTime(t.created))
+		}
+		options[t.id] = display
 	}
 
 	ui.Spacer()
-	ui.Success(fmt.Sprintf("Rollback to %s started", commit))
+	ui.Table([]string{"Commit", "Deployed", "Image", "Message"}, rows)
+	ui.Spacer()
 
-	ui.NextSteps([]string{
-		fmt.Sprintf("Run '%s logs' to monitor deployment progress", execName()),
-	})
+	selected, err := ui.SelectWithKeys("Choose deployment:", options)
+	if err != nil {
+		return rollbackTarget{}, err
+	}
+	for _, t := range targets {
+		if t.id == selected {
+			return t, nil
+		}
+	}
+	return rollbackTarget{}, fmt.Errorf("deployment not found")
+}
 
+// triggerRollback points the application at the target and deploys it
+func triggerRollback(client *api.Client, appUUID string, projectCfg *config.ProjectConfig, target rollbackTarget) error {
+	if config.BuildsImage(projectCfg.DeployMethod) {
+		if err := client.UpdateApplication(appUUID, map[string]any{
+			"docker_registry_image_tag": target.id,
+		}); err != nil {
+			return fmt.Errorf("failed to update image tag: %w", err)
+		}
+	} else {
+		if err := client.UpdateApplication(appUUID, map[string]any{
+			"git_commit_sha": target.commit,
+		}); err != nil {
+			return fmt.Errorf("failed to pin commit: %w", err)
+		}
+	}
+
+	// Not forced, so Coolify can reuse the image already built for the commit
+	if _, err := client.Deploy(appUUID, false, 0); err != nil {
+		return fmt.Errorf("failed to trigger deployment: %w", err)
+	}
 	return nil
 }
+
+// shortCommit abbreviates a commit SHA
+func shortCommit(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// formatDeployTime formats a deployment timestamp in local time
+func formatDeployTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/git"
 	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
@@ -29,16 +36,74 @@ Features:
   • Multi-instance management (switch between Coolify instances)
   • Production and preview deployments
   • Environment variable management
-  • Deployment monitoring and rollbacks`,
+  • Deployment monitoring and rollbacks
+
+Settings can be given without a config file, which is how CI jobs log in.
+Flags take precedence over environment variables, which take precedence
+over the config files; neither is ever saved:
+  --non-interactive  COOLKIT_NON_INTERACTIVE  Never prompt (see below)
+  --instance  COOLKIT_INSTANCE   Configured instance to use
+  --fqdn      COOLKIT_FQDN       Coolify URL (with --token)
+  --token     COOLKIT_TOKEN      Coolify API token (with --fqdn)
+  --provider  COOLKIT_PROVIDER   Infrastructure provider for installs
+              COOLKIT_GITHUB_TOKEN, COOLKIT_GITLAB_TOKEN, COOLKIT_GITLAB_URL,
+              COOLKIT_GITEA_TOKEN, COOLKIT_GITEA_URL,
+              COOLKIT_BITBUCKET_USERNAME, COOLKIT_BITBUCKET_TOKEN
+              COOLKIT_REGISTRY_URL, COOLKIT_REGISTRY_USERNAME,
+              COOLKIT_REGISTRY_PASSWORD
+
+A project's .coolify-deployer/config.json can be committed and shared. Keys
+set in .coolify-deployer/config.local.json, which is git-ignored, override it
+on this machine and are saved back there (apps/NAME.local.json for monorepo
+apps). The flags and variables above still take precedence over both.
+
+In non-interactive mode, on by --non-interactive, COOLKIT_NON_INTERACTIVE=true,
+CI=true or when stdin isn't a terminal, nothing prompts or takes over the
+screen. Answers must come from flags or environment variables; a prompt
+without one fails the command, and confirmations fail unless --yes is given.
+Progress is printed line by line and errors are printed to stderr as JSON:
+  {"error":{"code":"input_required","message":"..."}}
+Commands exit with 2 when an answer is missing and 1 on other errors.
+
+Accessible mode, on by COOLKIT_ACCESSIBLE=true or 'config set ui.accessible
+true', is for screen readers. Spinners, progress bars and full-screen views
+are replaced by status lines printed one after another, without colors,
+emoji or box drawing, and prompts ask numbered plain-text questions.
+
+Deploys commit and push with go-git, so git needn't be installed. With
+COOLKIT_GIT_SHELL=true or 'config set git.shell true' they run the git CLI
+instead, for repositories or git config go-git doesn't handle.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		yes, _ := cmd.Flags().GetBool("yes")
+		ui.SetAssumeYes(yes)
+		ui.SetAccessible(accessibleMode())
+		git.SetShell(gitShellMode())
+		if nonInteractiveMode(cmd) {
+			ui.SetNonInteractive(true)
+			// Errors are printed by Execute, as JSON
+			cmd.Root().SilenceErrors = true
+			cmd.Root().SilenceUsage = true
+		}
+
+		config.SetFlagOverrides(globalFlagOverrides(cmd))
+		if err := config.ValidateOverrides(); err != nil {
+			return err
+		}
+		app, _ := cmd.Flags().GetString("app")
+		return config.SelectApp(app)
+	},
 	RunE: runMainTUI,
 }
 
 // runMainTUI runs the main TUI menu and dispatches to subcommands
 func runMainTUI(cmd *cobra.Command, args []string) error {
-	selection, err := ui.RunMainMenu()
+	selection, project, err := ui.RunMainMenu()
 	if err != nil {
 		return err
 	}
+	if project != nil {
+		return runForProject(selection, project)
+	}
 
 	switch selection {
 	case ui.SelectionInstall:
@@ -66,7 +131,7 @@ func runMainTUI(cmd *cobra.Command, args []string) error {
 	case ui.SelectionBadge:
 		return runBadgeInteractive()
 	case ui.SelectionCI:
-		return runCIGithub(cmd, args)
+		return runCI(cmd, args)
 	case ui.SelectionHelp:
 		return cmd.Help()
 	case ui.SelectionExit:
@@ -75,20 +140,139 @@ func runMainTUI(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runForProject runs a main menu action for one of its projects, from the
+// project's directory
+func runForProject(selection ui.MainMenuSelection, project *config.RecentProject) error {
+	if err := os.Chdir(project.Dir); err != nil {
+		return fmt.Errorf("failed to open %s: %w", project.Name, err)
+	}
+	if err := config.SelectApp(project.App); err != nil {
+		return err
+	}
+
+	switch selection {
+	case ui.SelectionLogs:
+		return runLogs(logsCmd, nil)
+	case ui.SelectionStatus:
+		return runStatus(statusCmd, nil)
+	}
+	return runDeploy()
+}
+
+// globalFlagOverrides reads the global flags that override the config files
+func globalFlagOverrides(cmd *cobra.Command) config.Overrides {
+	// A subcommand's own flag of the same name shadows the global one
+	flag := func(name string) string {
+		if cmd.HasParent() && cmd.InheritedFlags().Lookup(name) == nil {
+			return ""
+		}
+		value, _ := cmd.Flags().GetString(name)
+		return value
+	}
+	return config.Overrides{
+		Instance: flag("instance"),
+		FQDN:     flag("fqdn"),
+		Token:    flag("token"),
+		Provider: flag("provider"),
+	}
+}
+
+// nonInteractiveMode reports whether prompts and full-screen views are off:
+// by --non-interactive, $COOLKIT_NON_INTERACTIVE, $CI or a stdin that isn't
+// a terminal
+func nonInteractiveMode(cmd *cobra.Command) bool {
+	if flag, _ := cmd.Flags().GetBool("non-interactive"); flag {
+		return true
+	}
+	for _, env := range []string{config.EnvNonInteractive, "CI"} {
+		if on, err := strconv.ParseBool(os.Getenv(env)); err == nil && on {
+			return true
+		}
+	}
+	return !isTerminal(os.Stdin)
+}
+
+// accessibleMode reports whether output is plain lines for screen readers:
+// by $COOLKIT_ACCESSIBLE, or else ui.accessible in the config
+func accessibleMode() bool {
+	if on, err := strconv.ParseBool(os.Getenv(config.EnvAccessible)); err == nil {
+		return on
+	}
+	return config.ReadUI().Accessible
+}
+
+// gitShellMode reports whether git operations run the git CLI instead of
+// go-git: by $COOLKIT_GIT_SHELL, or else git.shell in the config
+func gitShellMode() bool {
+	if on, err := strconv.ParseBool(os.Getenv(config.EnvGitShell)); err == nil {
+		return on
+	}
+	return config.ReadGit().Shell
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or a file
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
 func Execute(version, commit, date string) {
 	rootCmd.Version = fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date)
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		var exitErr *exitStatusError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+		code, status := errorCode(err)
+		if ui.Interactive() {
+			fmt.Fprintln(os.Stderr, err)
+		} else {
+			printJSONError(code, err)
+		}
+		os.Exit(status)
 	}
 }
 
+// errorCode classifies err for the JSON errors of non-interactive mode and
+// returns the exit status to go with it
+func errorCode(err error) (string, int) {
+	var inputErr *ui.NonInteractiveError
+	switch {
+	case errors.As(err, &inputErr):
+		return "input_required", 2
+	case api.IsUnauthorized(err):
+		return "unauthorized", 1
+	case api.IsForbidden(err):
+		return "forbidden", 1
+	case api.IsNotFound(err):
+		return "not_found", 1
+	}
+	return "error", 1
+}
+
+// printJSONError prints err to stderr as a line of JSON
+func printJSONError(code string, err error) {
+	enc := json.NewEncoder(os.Stderr)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(map[string]interface{}{
+		"error": map[string]string{
+			"code":    code,
+			"message": err.Error(),
+		},
+	})
+}
+
 func init() {
 	// Add global flags
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Verbose output")
-	rootCmd.PersistentFlags().String("instance", "", "Target Coolify instance (overrides context)")
-	rootCmd.PersistentFlags().StringP("format", "o", "table", "Output format (table, json, pretty)")
+	rootCmd.PersistentFlags().String("instance", "", "Target Coolify instance (overrides context, or $COOLKIT_INSTANCE)")
+	rootCmd.PersistentFlags().String("fqdn", "", "Coolify URL to use instead of the configured instance (or $COOLKIT_FQDN)")
+	rootCmd.PersistentFlags().String("token", "", "Coolify API token for --fqdn (or $COOLKIT_TOKEN)")
+	rootCmd.PersistentFlags().String("provider", "", "Infrastructure provider to use instead of the configured one (or $COOLKIT_PROVIDER)")
+	rootCmd.PersistentFlags().StringP("format", "o", "table", "Output format: table, json, yaml or pretty (indented JSON)")
+	rootCmd.PersistentFlags().String("app", "", "Monorepo app to use (.coolify-deployer/apps/NAME.json)")
+	rootCmd.PersistentFlags().Bool("non-interactive", false, "Never prompt; take every answer from flags and environment variables (or $COOLKIT_NON_INTERACTIVE)")
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "Answer yes to confirmations")
 
 	// Pillar 1: Deploy Coolify
 	rootCmd.AddCommand(installCmd)
@@ -105,9 +289,22 @@ func init() {
 	rootCmd.AddCommand(deployCmd)
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(lsCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(dashboardCmd)
 	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(openCmd)
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(shellCmd)
 	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(deploymentsCmd)
+	rootCmd.AddCommand(runsCmd)
+	rootCmd.AddCommand(devCmd)
+	rootCmd.AddCommand(previewCmd)
 	rootCmd.AddCommand(linkCmd)
+	rootCmd.AddCommand(cloneCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(promoteCmd)
 
 	// Instance & Auth
 	rootCmd.AddCommand(loginCmd)
@@ -115,11 +312,18 @@ func init() {
 	rootCmd.AddCommand(instancesCmd)
 
 	// Management
+	rootCmd.AddCommand(appsCmd)
 	rootCmd.AddCommand(servicesCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(serversCmd)
+	rootCmd.AddCommand(destinationsCmd)
 	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(limitsCmd)
+	rootCmd.AddCommand(cronCmd)
 	rootCmd.AddCommand(teamCmd)
 	rootCmd.AddCommand(keysCmd)
 	rootCmd.AddCommand(githubCmd)
+	rootCmd.AddCommand(listenCmd)
 
 	// AI Integration
 	rootCmd.AddCommand(mcpCmd)
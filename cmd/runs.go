@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/logview"
+	"github.com/entro314-labs/cool-kit/internal/runs"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var runsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "Review past installs and deployments",
+	Long: `Review past installs and deployments. Each run's steps and logs are saved
+to ~/.cool-kit/runs/<id>.log, with a JSON summary next to it. The last 100
+runs are kept.
+
+Available Commands:
+  runs ls    - List past runs
+  runs show  - Show a run's steps and log`,
+}
+
+var runsListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List past runs",
+	Args:    cobra.NoArgs,
+	RunE:    runRunsList,
+}
+
+var runsShowCmd = &cobra.Command{
+	Use:   "show [ID]",
+	Short: "Show a run's steps and log",
+	Long: `Show a run's steps and log. ID can be the start of a run's ID, and
+defaults to the latest run.
+
+Examples:
+  cool-kit runs show
+  cool-kit runs show 2024-05-01T10-42
+  cool-kit runs show --tail 50`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRunsShow,
+}
+
+func init() {
+	runsShowCmd.Flags().Int("tail", 0, "Only show the last N lines of the log")
+	addTableFlags(runsListCmd)
+
+	runsCmd.AddCommand(runsListCmd)
+	runsCmd.AddCommand(runsShowCmd)
+}
+
+func runRunsList(cmd *cobra.Command, args []string) error {
+	format, err := structuredFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	list, err := runs.List()
+	if err != nil {
+		return fmt.Errorf("failed to list runs: %w", err)
+	}
+	if format != "" {
+		return formatOutput(format, list)
+	}
+
+	ui.Section("Runs")
+	if len(list) == 0 {
+		ui.Dim("No runs yet")
+		return nil
+	}
+
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "ID"},
+		{Name: "Kind"},
+		{Name: "Target"},
+		{Name: "Status", Status: true},
+		{Name: "Started"},
+		{Name: "Duration"},
+		{Name: "Error", Wide: true},
+	}}
+	for i := range list {
+		r := &list[i]
+		table.Rows = append(table.Rows, []string{
+			r.ID,
+			r.Kind,
+			orDash(r.Target),
+			r.Status,
+			formatDeployTime(r.StartedAt),
+			formatDuration(r.Duration()),
+			orDash(r.Error),
+		})
+	}
+
+	if err := printTable(cmd, table); err != nil {
+		return err
+	}
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s runs show ID' to see a run's steps and log", execName()),
+	})
+	return nil
+}
+
+func runRunsShow(cmd *cobra.Command, args []string) error {
+	format, err := structuredFormat(cmd)
+	if err != nil {
+		return err
+	}
+	tail, _ := cmd.Flags().GetInt("tail")
+
+	id := ""
+	if len(args) > 0 {
+		id = args[0]
+	}
+	run, err := runs.Find(id)
+	if errors.Is(err, runs.ErrNotFound) {
+		return fmt.Errorf("%w (run '%s runs ls' to list them)", err, execName())
+	}
+	if err != nil {
+		return err
+	}
+	if format != "" {
+		return formatOutput(format, run)
+	}
+
+	ui.Section(fmt.Sprintf("Run %s", run.ID))
+	ui.KeyValue("Kind", run.Kind)
+	ui.KeyValue("Target", orDash(run.Target))
+	ui.KeyValue("Status", ui.StatusStyle(run.Status).Render(run.Status))
+	ui.KeyValue("Started", formatDeployTime(run.StartedAt))
+	ui.KeyValue("Duration", formatDuration(run.Duration()))
+	if run.Error != "" {
+		ui.KeyValue("Error", ui.ErrorStyle.Render(run.Error))
+	}
+	keys := make([]string, 0, len(run.Details))
+	for key := range run.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		ui.KeyValue(strings.ToUpper(key[:1])+key[1:], run.Details[key])
+	}
+
+	if len(run.Steps) > 0 {
+		ui.Section("Steps")
+		for i, step := range run.Steps {
+			duration := "-"
+			if step.StartedAt != nil && step.FinishedAt != nil {
+				duration = formatDuration(step.FinishedAt.Sub(*step.StartedAt))
+			}
+			fmt.Printf("  %d. %s %s %s\n", i+1, step.Name,
+				ui.StatusStyle(step.Status).Render(step.Status), ui.DimStyle.Render(duration))
+		}
+	}
+
+	data, err := os.ReadFile(run.Log)
+	if err != nil {
+		return fmt.Errorf("failed to read the run's log: %w", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	ui.Section("Log")
+	for _, line := range lines {
+		fmt.Println(ui.RenderLogLine(line, logview.DetectLevel(line)))
+	}
+	ui.Spacer()
+	ui.Dim(run.Log)
+	return nil
+}
@@ -0,0 +1,363 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/service"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var serversCmd = &cobra.Command{
+	Use:     "servers",
+	Aliases: []string{"server"},
+	Short:   "Manage servers connected to Coolify",
+	Long: `Register and validate the servers Coolify deploys to.
+
+Available Commands:
+  servers ls        - List servers
+  servers add       - Register and validate a new server
+  servers validate  - Re-run validation for a server
+  servers rm        - Remove a server
+  servers proxy     - Manage the server's reverse proxy`,
+}
+
+var serversListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List servers",
+	RunE:  runServersList,
+}
+
+var serversAddCmd = &cobra.Command{
+	Use:   "add IP",
+	Short: "Register a new server",
+	Long: `Register a server in Coolify, then validate that it is reachable over SSH
+and ready to run Docker workloads.
+
+The server must accept the private key for the given user. Use
+'cool-kit keys create' to generate and upload a key first.
+
+Examples:
+  cool-kit servers add 203.0.113.10 --private-key <key-uuid>
+  cool-kit servers add 203.0.113.10 --name worker-1 --user deploy --port 2222`,
+	Args: cobra.ExactArgs(1),
+	RunE: runServersAdd,
+}
+
+var serversValidateCmd = &cobra.Command{
+	Use:   "validate UUID",
+	Short: "Validate a server",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServersValidate,
+}
+
+var serversRemoveCmd = &cobra.Command{
+	Use:   "rm UUID",
+	Short: "Remove a server",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServersRemove,
+}
+
+func init() {
+	serversCmd.AddCommand(serversListCmd)
+	serversCmd.AddCommand(serversAddCmd)
+	serversCmd.AddCommand(serversValidateCmd)
+	serversCmd.AddCommand(serversRemoveCmd)
+
+	addTableFlags(serversListCmd)
+
+	serversAddCmd.Flags().String("name", "", "Server name (defaults to the IP)")
+	serversAddCmd.Flags().String("description", "", "Server description")
+	serversAddCmd.Flags().String("user", "root", "SSH user")
+	serversAddCmd.Flags().Int("port", 22, "SSH port")
+	serversAddCmd.Flags().String("private-key", "", "Private key UUID")
+	serversAddCmd.Flags().Bool("build-server", false, "Use this server only for builds")
+	serversAddCmd.Flags().Bool("skip-validation", false, "Register the server without validating it")
+
+	for _, c := range []*cobra.Command{serversAddCmd, serversValidateCmd} {
+		c.Flags().Duration("timeout", 3*time.Minute, "How long to wait for validation")
+	}
+}
+
+func runServersList(cmd *cobra.Command, args []string) error {
+	format, err := structuredFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	if format == "" {
+		ui.Section("Servers")
+	}
+
+	var servers []api.Server
+	err = loadTask(format, ui.Task{
+		Name:         "load-servers",
+		ActiveName:   "Loading servers...",
+		CompleteName: "✓ Loaded servers",
+		Action: func() error {
+			var err error
+			servers, err = client.ListServers()
+			return err
+		},
+	})
+	if err != nil {
+		if format == "" {
+			ui.Error("Failed to load servers")
+		}
+		return fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	if format != "" {
+		records := make([]serverRecord, 0, len(servers))
+		for i := range servers {
+			records = append(records, newServerRecord(&servers[i]))
+		}
+		return formatOutput(format, records)
+	}
+
+	if len(servers) == 0 {
+		ui.Dim("No servers found")
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s servers add IP' to register one", execName()),
+		})
+		return nil
+	}
+
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "Name"},
+		{Name: "IP"},
+		{Name: "User", Wide: true},
+		{Name: "Status", Status: true},
+		{Name: "UUID"},
+	}}
+	for _, s := range servers {
+		table.Rows = append(table.Rows, []string{
+			s.Name,
+			s.IP,
+			s.User,
+			newServerRecord(&s).Status,
+			s.UUID,
+		})
+	}
+
+	ui.Spacer()
+	return printTable(cmd, table)
+}
+
+func runServersAdd(cmd *cobra.Command, args []string) error {
+	ip := args[0]
+
+	name, _ := cmd.Flags().GetString("name")
+	description, _ := cmd.Flags().GetString("description")
+	user, _ := cmd.Flags().GetString("user")
+	port, _ := cmd.Flags().GetInt("port")
+	keyUUID, _ := cmd.Flags().GetString("private-key")
+	buildServer, _ := cmd.Flags().GetBool("build-server")
+	skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	if name == "" {
+		name = ip
+	}
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	ui.Section(fmt.Sprintf("Add Server: %s", ip))
+
+	if keyUUID == "" {
+		keyUUID, err = promptPrivateKeyUUID(client)
+		if err != nil {
+			return err
+		}
+	}
+
+	var resp *api.CreateResponse
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "create-server",
+			ActiveName:   "Registering server...",
+			CompleteName: "✓ Registered server",
+			Action: func() error {
+				var err error
+				resp, err = client.CreateServer(&api.CreateServerRequest{
+					Name:           name,
+					Description:    description,
+					IP:             ip,
+					Port:           port,
+					User:           user,
+					PrivateKeyUUID: keyUUID,
+					IsBuildServer:  buildServer,
+				})
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to register server")
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	ui.KeyValue("UUID", resp.UUID)
+
+	if skipValidation {
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s servers validate %s' when the server is ready", execName(), resp.UUID),
+		})
+		return nil
+	}
+
+	return validateServer(client, resp.UUID, timeout)
+}
+
+func runServersValidate(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	return validateServer(client, args[0], timeout)
+}
+
+// validateServer triggers validation and waits until the server is usable or validation fails
+func validateServer(client *api.Client, uuid string, timeout time.Duration) error {
+	var server *api.Server
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "validate-server",
+			ActiveName:   "Validating server (SSH, Docker, proxy)...",
+			CompleteName: "✓ Server validated",
+			Action: func() error {
+				if _, err := client.ValidateServer(uuid); err != nil {
+					return err
+				}
+
+				var err error
+				server, err = waitForServerValidation(client, uuid, timeout)
+				return err
+			},
+		},
+	})
+
+	if server != nil {
+		ui.Spacer()
+		ui.KeyValue("Reachable", fmt.Sprintf("%t", server.Settings != nil && server.Settings.IsReachable))
+		ui.KeyValue("Usable", fmt.Sprintf("%t", server.Settings != nil && server.Settings.IsUsable))
+	}
+
+	if err != nil {
+		ui.Error("Server validation failed")
+		if server != nil && server.ValidationLogs != "" {
+			ui.Spacer()
+			ui.Dim(cleanValidationLogs(server.ValidationLogs))
+		}
+		return fmt.Errorf("failed to validate server: %w", err)
+	}
+
+	ui.Success("Server is ready for deployments")
+	return nil
+}
+
+// waitForServerValidation polls a server until it is ready, validation logs report a failure, or timeout
+func waitForServerValidation(client *api.Client, uuid string, timeout time.Duration) (*api.Server, error) {
+	deadline := time.Now().Add(timeout)
+	var server *api.Server
+
+	for {
+		// Give the validation job time to start and clear logs from earlier runs
+		time.Sleep(3 * time.Second)
+
+		var err error
+		server, err = client.GetServer(uuid)
+		if err != nil {
+			return nil, err
+		}
+		if server.IsReady() {
+			return server, nil
+		}
+		if server.ValidationLogs != "" {
+			return server, fmt.Errorf("server is not usable")
+		}
+		if time.Now().After(deadline) {
+			return server, fmt.Errorf("timed out after %s", timeout)
+		}
+	}
+}
+
+// htmlTagPattern matches markup in Coolify's validation logs
+var htmlTagPattern = regexp.MustCompile(`<[^>]+>`)
+
+// cleanValidationLogs converts Coolify's HTML validation logs to plain text
+func cleanValidationLogs(logs string) string {
+	logs = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n").Replace(logs)
+	return strings.TrimSpace(htmlTagPattern.ReplaceAllString(logs, ""))
+}
+
+func runServersRemove(cmd *cobra.Command, args []string) error {
+	uuid := args[0]
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := ui.ConfirmAction("remove", "server "+shortUUID(uuid))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		ui.Dim("Cancelled")
+		return nil
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "delete-server",
+			ActiveName:   "Removing server...",
+			CompleteName: "✓ Removed server",
+			Action: func() error {
+				return client.DeleteServer(uuid)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to remove server")
+		return fmt.Errorf("failed to delete server: %w", err)
+	}
+
+	return nil
+}
+
+// promptPrivateKeyUUID asks the user to pick a private key
+func promptPrivateKeyUUID(client *api.Client) (string, error) {
+	keys, err := service.NewPrivateKeyService(client).List(context.Background())
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		return "", fmt.Errorf("no private keys found: run '%s keys create NAME' first", execName())
+	}
+
+	options := make(map[string]string, len(keys))
+	for _, k := range keys {
+		options[k.UUID] = k.Name
+	}
+	return ui.SelectWithKeys("Select private key:", options)
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/entro314-labs/cool-kit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// proxyContainerName is the container Coolify runs the server proxy in
+const proxyContainerName = "coolify-proxy"
+
+// proxyDynamicDirs maps proxy types to their dynamic configuration directory on the server
+var proxyDynamicDirs = map[string]string{
+	api.ProxyTraefik: "/data/coolify/proxy/dynamic",
+	api.ProxyCaddy:   "/data/coolify/proxy/caddy/dynamic",
+}
+
+var serversProxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Manage a server's reverse proxy",
+	Long: `Inspect and manage the reverse proxy (Traefik or Caddy) Coolify runs on a server.
+
+Available Commands:
+  servers proxy show     - Show proxy type and status
+  servers proxy set      - Change the proxy type (traefik, caddy, none)
+  servers proxy restart  - Restart the proxy container
+  servers proxy config   - Show dynamic proxy configuration`,
+}
+
+var serversProxyShowCmd = &cobra.Command{
+	Use:   "show UUID",
+	Short: "Show proxy type and status",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServersProxyShow,
+}
+
+var serversProxySetCmd = &cobra.Command{
+	Use:       "set UUID TYPE",
+	Short:     "Change the proxy type",
+	Long:      `Change the proxy type of a server. Restart the proxy afterwards to apply it.`,
+	Args:      cobra.ExactArgs(2),
+	ValidArgs: []string{api.ProxyTraefik, api.ProxyCaddy, api.ProxyNone},
+	RunE:      runServersProxySet,
+}
+
+var serversProxyRestartCmd = &cobra.Command{
+	Use:   "restart UUID",
+	Short: "Restart the proxy container (over ssh)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServersProxyRestart,
+}
+
+var serversProxyConfigCmd = &cobra.Command{
+	Use:   "config UUID",
+	Short: "Show dynamic proxy configuration (over ssh)",
+	Long: `List the dynamic proxy configuration files on a server, or print one of them.
+
+Examples:
+  cool-kit servers proxy config <uuid>
+  cool-kit servers proxy config <uuid> --file coolify.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runServersProxyConfig,
+}
+
+func init() {
+	serversCmd.AddCommand(serversProxyCmd)
+	serversProxyCmd.AddCommand(serversProxyShowCmd)
+	serversProxyCmd.AddCommand(serversProxySetCmd)
+	serversProxyCmd.AddCommand(serversProxyRestartCmd)
+	serversProxyCmd.AddCommand(serversProxyConfigCmd)
+
+	for _, c := range []*cobra.Command{serversProxyRestartCmd, serversProxyConfigCmd} {
+		c.Flags().StringP("identity", "i", "", "SSH private key file")
+	}
+	serversProxyConfigCmd.Flags().String("file", "", "Print this configuration file")
+}
+
+func runServersProxyShow(cmd *cobra.Command, args []string) error {
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	server, proxy, err := loadServerProxy(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	ui.Section(fmt.Sprintf("Proxy: %s", server.Name))
+	ui.KeyValue("Type", proxy.Type)
+	if proxy.Status != "" {
+		ui.KeyValue("Status", proxy.Status)
+	}
+	ui.KeyValue("Force stopped", fmt.Sprintf("%t", proxy.ForceStop))
+	if proxy.RedirectEnabled && proxy.RedirectURL != "" {
+		ui.KeyValue("Default redirect", proxy.RedirectURL)
+	}
+
+	return nil
+}
+
+func runServersProxySet(cmd *cobra.Command, args []string) error {
+	uuid, proxyType := args[0], strings.ToLower(args[1])
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "set-proxy",
+			ActiveName:   fmt.Sprintf("Switching proxy to %s...", proxyType),
+			CompleteName: fmt.Sprintf("✓ Proxy set to %s", proxyType),
+			Action: func() error {
+				return client.SetServerProxyType(uuid, proxyType)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to change proxy")
+		return fmt.Errorf("failed to change proxy: %w", err)
+	}
+
+	if proxyType != api.ProxyNone {
+		ui.NextSteps([]string{
+			fmt.Sprintf("Run '%s servers proxy restart %s' to start the new proxy", execName(), uuid),
+		})
+	}
+	return nil
+}
+
+func runServersProxyRestart(cmd *cobra.Command, args []string) error {
+	identity, _ := cmd.Flags().GetString("identity")
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	server, proxy, err := loadServerProxy(client, args[0])
+	if err != nil {
+		return err
+	}
+	if proxy.Type == api.ProxyNone {
+		return fmt.Errorf("server %s has no proxy configured", server.Name)
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "restart-proxy",
+			ActiveName:   "Restarting proxy...",
+			CompleteName: "✓ Restarted proxy",
+			Action: func() error {
+				_, err := runServerCommand(server, identity, "docker restart "+proxyContainerName)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to restart proxy")
+		return fmt.Errorf("failed to restart proxy: %w", err)
+	}
+
+	return nil
+}
+
+func runServersProxyConfig(cmd *cobra.Command, args []string) error {
+	identity, _ := cmd.Flags().GetString("identity")
+	file, _ := cmd.Flags().GetString("file")
+
+	if strings.Contains(file, "/") || file == ".." {
+		return fmt.Errorf("invalid file name: %s", file)
+	}
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	server, proxy, err := loadServerProxy(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	dir, ok := proxyDynamicDirs[proxy.Type]
+	if !ok {
+		return fmt.Errorf("no dynamic configuration for proxy type %q", proxy.Type)
+	}
+
+	remote := "ls -1 " + utils.ShellQuote(dir)
+	if file != "" {
+		remote = "cat " + utils.ShellQuote(dir+"/"+file)
+	}
+
+	out, err := runServerCommand(server, identity, remote)
+	if err != nil {
+		return fmt.Errorf("failed to read proxy configuration: %w", err)
+	}
+
+	if file != "" {
+		fmt.Print(out)
+		return nil
+	}
+
+	ui.Section(fmt.Sprintf("Dynamic configuration (%s)", dir))
+	files := strings.Fields(out)
+	if len(files) == 0 {
+		ui.Dim("No configuration files")
+		return nil
+	}
+	ui.List(files)
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s servers proxy config %s --file NAME' to print a file", execName(), args[0]),
+	})
+	return nil
+}
+
+// loadServerProxy fetches a server and decodes its proxy settings
+func loadServerProxy(client *api.Client, uuid string) (*api.Server, *api.ServerProxy, error) {
+	server, err := client.GetServer(uuid)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get server: %w", err)
+	}
+
+	proxy, err := server.ProxyConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	if proxy.Type == "" {
+		proxy.Type = api.ProxyNone
+	}
+	return server, proxy, nil
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
 	"github.com/entro314-labs/cool-kit/internal/ui"
@@ -14,13 +16,20 @@ var servicesCmd = &cobra.Command{
 	Long: `Manage services associated with your applications.
 
 Services include databases (PostgreSQL, MySQL, MongoDB), caches (Redis),
-search engines (Meilisearch, Elasticsearch), and other infrastructure.
+search engines (Meilisearch, Elasticsearch), and one-click services from
+the Coolify catalog (Plausible, Umami, n8n, ...).
 
 Available Commands:
-  services ls      - List all services
-  services create  - Create a new service
-  services rm      - Remove a service
-  services info    - Show service details`,
+  services ls        - List all services
+  services templates - List available one-click service templates
+  services create    - Create a one-click service from a template
+  services start     - Start a service
+  services stop      - Stop a service
+  services restart   - Restart a service
+  services env       - Show a service's environment variables
+  services domains   - Show a service's domains
+  services rm        - Remove a service
+  services info      - Show service details`,
 }
 
 var servicesListCmd = &cobra.Command{
@@ -46,10 +55,82 @@ var servicesRemoveCmd = &cobra.Command{
 	RunE:  runServicesRemove,
 }
 
+var servicesTemplatesCmd = &cobra.Command{
+	Use:   "templates [SEARCH]",
+	Short: "List one-click service templates",
+	Long:  `List the one-click service templates available in Coolify, optionally filtered by name or tag.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runServicesTemplates,
+}
+
+var servicesCreateCmd = &cobra.Command{
+	Use:   "create TEMPLATE",
+	Short: "Create a one-click service",
+	Long: `Create a service from a Coolify one-click template.
+
+Examples:
+  cool-kit services create plausible
+  cool-kit services create umami --name analytics --project <uuid> --server <uuid>
+  cool-kit services create n8n --instant-deploy`,
+	Args: cobra.ExactArgs(1),
+	RunE: runServicesCreate,
+}
+
+var servicesStartCmd = &cobra.Command{
+	Use:   "start [UUID]",
+	Short: "Start a service",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServicesStart,
+}
+
+var servicesStopCmd = &cobra.Command{
+	Use:   "stop [UUID]",
+	Short: "Stop a service",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServicesStop,
+}
+
+var servicesRestartCmd = &cobra.Command{
+	Use:   "restart [UUID]",
+	Short: "Restart a service",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServicesRestart,
+}
+
+var servicesEnvCmd = &cobra.Command{
+	Use:   "env [UUID]",
+	Short: "Show a service's environment variables",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServicesEnv,
+}
+
+var servicesDomainsCmd = &cobra.Command{
+	Use:   "domains [UUID]",
+	Short: "Show a service's domains",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runServicesDomains,
+}
+
 func init() {
 	servicesCmd.AddCommand(servicesListCmd)
+	addTableFlags(servicesListCmd)
 	servicesCmd.AddCommand(servicesInfoCmd)
 	servicesCmd.AddCommand(servicesRemoveCmd)
+	servicesCmd.AddCommand(servicesTemplatesCmd)
+	servicesCmd.AddCommand(servicesCreateCmd)
+	servicesCmd.AddCommand(servicesStartCmd)
+	servicesCmd.AddCommand(servicesStopCmd)
+	servicesCmd.AddCommand(servicesRestartCmd)
+	servicesCmd.AddCommand(servicesEnvCmd)
+	servicesCmd.AddCommand(servicesDomainsCmd)
+
+	servicesCreateCmd.Flags().String("name", "", "Service name (defaults to the template name)")
+	servicesCreateCmd.Flags().String("project", "", "Project UUID")
+	servicesCreateCmd.Flags().String("environment", "production", "Environment name")
+	servicesCreateCmd.Flags().String("server", "", "Server UUID")
+	servicesCreateCmd.Flags().Bool("instant-deploy", false, "Start the service right after creation")
+
+	servicesEnvCmd.Flags().Bool("show-values", false, "Show values instead of masking them")
 }
 
 func runServicesList(cmd *cobra.Command, args []string) error {
@@ -62,11 +143,12 @@ func runServicesList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(instance.FQDN, instance.Token)
+	client := newAPIClient(instance.FQDN, instance.Token)
 
 	ui.Section("Services")
 
 	var databases []api.Database
+	var services []api.Service
 	err = ui.RunTasks([]ui.Task{
 		{
 			Name:         "load-databases",
@@ -75,41 +157,56 @@ func runServicesList(cmd *cobra.Command, args []string) error {
 			Action: func() error {
 				var err error
 				databases, err = client.ListDatabases()
-				return err
+				if err != nil {
+					return fmt.Errorf("failed to list databases: %w", err)
+				}
+				services, err = client.ListServices()
+				if err != nil {
+					return fmt.Errorf("failed to list services: %w", err)
+				}
+				return nil
 			},
 		},
 	})
 	if err != nil {
 		ui.Error("Failed to load services")
-		return fmt.Errorf("failed to list databases: %w", err)
+		return err
 	}
 
-	if len(databases) == 0 {
+	if len(databases) == 0 && len(services) == 0 {
 		ui.Dim("No services found")
 		ui.Spacer()
 		ui.NextSteps([]string{
 			"Deploy an application with smart detection to auto-provision services",
-			"Or create services manually in the Coolify dashboard",
+			fmt.Sprintf("Or run '%s services create TEMPLATE' to add a one-click service", execName()),
 		})
 		return nil
 	}
 
-	ui.Spacer()
+	table := ui.ListTable{Columns: []ui.Column{
+		{Name: "Name"},
+		{Name: "Kind", Wide: true},
+		{Name: "Type"},
+		{Name: "Status", Status: true},
+		{Name: "UUID"},
+	}}
 	for _, db := range databases {
-		statusIcon := "●"
-		if db.Status == "running" {
-			statusIcon = ui.SuccessStyle.Render("●")
-		} else if db.Status == "exited" || db.Status == "stopped" {
-			statusIcon = ui.ErrorStyle.Render("●")
-		}
-
-		ui.KeyValue(
-			fmt.Sprintf("%s %s", statusIcon, db.Name),
-			fmt.Sprintf("%s (%s)", db.Type, db.UUID[:8]+"..."),
-		)
+		table.Rows = append(table.Rows, []string{db.Name, "database", db.Type, orDash(db.Status), db.UUID})
+	}
+	for _, svc := range services {
+		table.Rows = append(table.Rows, []string{svc.Name, "service", svc.Type, orDash(svc.Status), svc.UUID})
 	}
 
-	return nil
+	ui.Spacer()
+	return printTable(cmd, table)
+}
+
+// shortUUID abbreviates a UUID for list output
+func shortUUID(uuid string) string {
+	if len(uuid) <= 8 {
+		return uuid
+	}
+	return uuid[:8] + "..."
 }
 
 func runServicesInfo(cmd *cobra.Command, args []string) error {
@@ -124,7 +221,7 @@ func runServicesInfo(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(instance.FQDN, instance.Token)
+	client := newAPIClient(instance.FQDN, instance.Token)
 
 	ui.Section(fmt.Sprintf("Service: %s", uuid[:8]+"..."))
 
@@ -153,6 +250,12 @@ func runServicesInfo(cmd *cobra.Command, args []string) error {
 	ui.KeyValue("Image", database.Image)
 	ui.KeyValue("Status", database.Status)
 	ui.KeyValue("Public", fmt.Sprintf("%t", database.IsPublic))
+	if database.IsPublic && database.PublicPort > 0 {
+		ui.KeyValue("Public Port", fmt.Sprintf("%d", database.PublicPort))
+	}
+	if database.InternalDBURL != "" {
+		ui.KeyValue("Internal URL", database.InternalDBURL)
+	}
 
 	if database.Description != "" {
 		ui.Spacer()
@@ -174,7 +277,7 @@ func runServicesRemove(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(instance.FQDN, instance.Token)
+	client := newAPIClient(instance.FQDN, instance.Token)
 
 	// Get service info first
 	var database *api.Database
@@ -229,3 +332,347 @@ func runServicesRemove(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runServicesTemplates(cmd *cobra.Command, args []string) error {
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	ui.Section("Service Templates")
+
+	var templates []api.ServiceTemplate
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-templates",
+			ActiveName:   "Loading service templates...",
+			CompleteName: "✓ Loaded service templates",
+			Action: func() error {
+				var err error
+				templates, err = client.ListServiceTemplates()
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load service templates")
+		return err
+	}
+
+	search := ""
+	if len(args) > 0 {
+		search = strings.ToLower(args[0])
+	}
+
+	rows := [][]string{}
+	for _, tmpl := range templates {
+		if search != "" && !templateMatches(tmpl, search) {
+			continue
+		}
+		slogan := tmpl.Slogan
+		if len(slogan) > 60 {
+			slogan = slogan[:57] + "..."
+		}
+		rows = append(rows, []string{tmpl.Name, slogan})
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Template", "Description"}, rows)
+	ui.Spacer()
+	ui.Dim(fmt.Sprintf("Total: %d templates", len(rows)))
+	ui.NextSteps([]string{
+		fmt.Sprintf("Run '%s services create TEMPLATE' to create a service", execName()),
+	})
+
+	return nil
+}
+
+// templateMatches reports whether a template name or tag contains the search term
+func templateMatches(tmpl api.ServiceTemplate, search string) bool {
+	if strings.Contains(strings.ToLower(tmpl.Name), search) {
+		return true
+	}
+	for _, tag := range tmpl.Tags {
+		if strings.Contains(strings.ToLower(tag), search) {
+			return true
+		}
+	}
+	return false
+}
+
+func runServicesCreate(cmd *cobra.Command, args []string) error {
+	templateType := args[0]
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	name, _ := cmd.Flags().GetString("name")
+	projectUUID, _ := cmd.Flags().GetString("project")
+	environment, _ := cmd.Flags().GetString("environment")
+	serverUUID, _ := cmd.Flags().GetString("server")
+	instantDeploy, _ := cmd.Flags().GetBool("instant-deploy")
+
+	ui.Section(fmt.Sprintf("Create Service: %s", templateType))
+
+	if projectUUID == "" {
+		projectUUID, err = promptProjectUUID(client)
+		if err != nil {
+			return err
+		}
+	}
+
+	if serverUUID == "" {
+		serverUUID, err = promptServerUUID(client)
+		if err != nil {
+			return err
+		}
+	}
+
+	if name == "" {
+		name = templateType
+	}
+
+	var resp *api.CreateServiceResponse
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "create-service",
+			ActiveName:   fmt.Sprintf("Creating %s...", templateType),
+			CompleteName: fmt.Sprintf("✓ Created %s", templateType),
+			Action: func() error {
+				var err error
+				resp, err = client.CreateService(&api.CreateServiceRequest{
+					Type:            templateType,
+					Name:            name,
+					ProjectUUID:     projectUUID,
+					EnvironmentName: environment,
+					ServerUUID:      serverUUID,
+					InstantDeploy:   instantDeploy,
+				})
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to create service")
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+
+	ui.Spacer()
+	ui.Success("Service created successfully")
+	ui.KeyValue("UUID", resp.UUID)
+	for _, domain := range resp.Domains {
+		ui.KeyValue("Domain", ui.InfoStyle.Render(domain))
+	}
+
+	steps := []string{
+		fmt.Sprintf("Run '%s services env %s' to review generated credentials", execName(), resp.UUID),
+	}
+	if !instantDeploy {
+		steps = append(steps, fmt.Sprintf("Run '%s services start %s' to start it", execName(), resp.UUID))
+	}
+	ui.NextSteps(steps)
+
+	return nil
+}
+
+func runServicesStart(cmd *cobra.Command, args []string) error {
+	return runServiceAction(args[0], "start", "Starting", "Started", func(client *api.Client, uuid string) error {
+		_, err := client.StartService(uuid)
+		return err
+	})
+}
+
+func runServicesStop(cmd *cobra.Command, args []string) error {
+	return runServiceAction(args[0], "stop", "Stopping", "Stopped", func(client *api.Client, uuid string) error {
+		_, err := client.StopService(uuid)
+		return err
+	})
+}
+
+func runServicesRestart(cmd *cobra.Command, args []string) error {
+	return runServiceAction(args[0], "restart", "Restarting", "Restarted", func(client *api.Client, uuid string) error {
+		_, err := client.RestartService(uuid)
+		return err
+	})
+}
+
+// runServiceAction runs a lifecycle action against a service with spinner feedback
+func runServiceAction(uuid, action, activeVerb, doneVerb string, fn func(*api.Client, string) error) error {
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         action + "-service",
+			ActiveName:   fmt.Sprintf("%s service...", activeVerb),
+			CompleteName: fmt.Sprintf("✓ %s service", doneVerb),
+			Action: func() error {
+				return fn(client, uuid)
+			},
+		},
+	})
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to %s service", action))
+		return fmt.Errorf("failed to %s service: %w", action, err)
+	}
+
+	return nil
+}
+
+func runServicesEnv(cmd *cobra.Command, args []string) error {
+	uuid := args[0]
+	showValues, _ := cmd.Flags().GetBool("show-values")
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	ui.Section(fmt.Sprintf("Service Environment: %s", shortUUID(uuid)))
+
+	var envs []api.EnvironmentVariable
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-envs",
+			ActiveName:   "Loading environment variables...",
+			CompleteName: "✓ Loaded environment variables",
+			Action: func() error {
+				var err error
+				envs, err = client.ListServiceEnvs(uuid)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load environment variables")
+		return fmt.Errorf("failed to list service environment variables: %w", err)
+	}
+
+	rows := [][]string{}
+	for _, env := range envs {
+		value := env.Value
+		if !showValues && value != "" {
+			value = "••••••••"
+		}
+		rows = append(rows, []string{env.Key, value})
+	}
+
+	ui.Spacer()
+	ui.Table([]string{"Key", "Value"}, rows)
+	if !showValues && len(rows) > 0 {
+		ui.Spacer()
+		ui.Dim("Use --show-values to reveal values")
+	}
+
+	return nil
+}
+
+func runServicesDomains(cmd *cobra.Command, args []string) error {
+	uuid := args[0]
+
+	client, err := newInstanceClient()
+	if err != nil {
+		return err
+	}
+
+	var svc *api.Service
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-service",
+			ActiveName:   "Loading service...",
+			CompleteName: "✓ Loaded service",
+			Action: func() error {
+				var err error
+				svc, err = client.GetService(uuid)
+				return err
+			},
+		},
+	})
+	if err != nil {
+		ui.Error("Failed to load service")
+		return fmt.Errorf("failed to get service: %w", err)
+	}
+
+	ui.Section(fmt.Sprintf("Service Domains: %s", svc.Name))
+
+	rows := [][]string{}
+	for _, app := range svc.Applications {
+		fqdn := ""
+		if app.Fqdn != nil {
+			fqdn = *app.Fqdn
+		}
+		rows = append(rows, []string{app.Name, fqdn, app.Status})
+	}
+	ui.Table([]string{"Container", "Domains", "Status"}, rows)
+
+	return nil
+}
+
+// promptProjectUUID asks the user to pick a project
+func promptProjectUUID(client *api.Client) (string, error) {
+	var projects []api.Project
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-projects",
+			ActiveName:   "Loading projects...",
+			CompleteName: "✓ Loaded projects",
+			Action: func() error {
+				var err error
+				projects, err = client.ListProjects()
+				return err
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list projects: %w", err)
+	}
+	if len(projects) == 0 {
+		return "", fmt.Errorf("no projects found: create one in the Coolify dashboard first")
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		return strings.ToLower(projects[i].Name) < strings.ToLower(projects[j].Name)
+	})
+	options := make([]ui.PickOption, 0, len(projects))
+	for _, p := range projects {
+		options = append(options, ui.PickOption{Key: p.UUID, Label: p.Name})
+	}
+	return ui.Pick("Select project:", ui.PickProject, options)
+}
+
+// promptServerUUID asks the user to pick a server
+func promptServerUUID(client *api.Client) (string, error) {
+	var servers []api.Server
+	err := ui.RunTasks([]ui.Task{
+		{
+			Name:         "load-servers",
+			ActiveName:   "Loading servers...",
+			CompleteName: "✓ Loaded servers",
+			Action: func() error {
+				var err error
+				servers, err = client.ListServers()
+				return err
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list servers: %w", err)
+	}
+	if len(servers) == 0 {
+		return "", fmt.Errorf("no servers available")
+	}
+
+	options := make([]ui.PickOption, 0, len(servers))
+	for _, s := range servers {
+		displayName := s.Name
+		if s.IP != "" {
+			displayName = fmt.Sprintf("%s (%s)", s.Name, s.IP)
+		}
+		options = append(options, ui.PickOption{Key: s.UUID, Label: displayName})
+	}
+	return ui.Pick("Select server:", ui.PickServer, options)
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Open an interactive shell in the application container",
+	Long: `Open an interactive shell inside the linked application's running container.
+
+cool-kit connects to the application's server over ssh and runs 'docker exec'
+in the container Coolify started for the application. bash is used when the
+image has it, otherwise sh. The server must accept your ssh key; use
+--identity to choose the key.
+
+Examples:
+  cool-kit shell
+  cool-kit shell --shell zsh
+  cool-kit shell -i ~/.ssh/coolify`,
+	Args: cobra.NoArgs,
+	RunE: runShell,
+}
+
+func init() {
+	shellCmd.Flags().StringP("identity", "i", "", "SSH private key file")
+	shellCmd.Flags().String("server", "", "Server UUID (defaults to the application's server)")
+	shellCmd.Flags().String("shell", "", "Shell to start (defaults to bash, falling back to sh)")
+}
+
+func runShell(cmd *cobra.Command, args []string) error {
+	identity, _ := cmd.Flags().GetString("identity")
+	serverUUID, _ := cmd.Flags().GetString("server")
+	shell, _ := cmd.Flags().GetString("shell")
+
+	appUUID, client, err := getAppUUID()
+	if err != nil {
+		return err
+	}
+
+	server, err := appServer(client, appUUID, serverUUID)
+	if err != nil {
+		return err
+	}
+
+	start := "command -v bash >/dev/null 2>&1 && exec bash || exec sh"
+	if shell != "" {
+		start = "exec " + utils.ShellQuote(shell)
+	}
+
+	// Coolify names application containers after the application UUID;
+	// preview deployments add a -pr-N suffix
+	remote := fmt.Sprintf(
+		`c=$(docker ps --filter name=^%s --format '{{.Names}}' | grep -v -- '-pr-' | head -n 1); `+
+			`if [ -z "$c" ]; then echo "no running container found for application %s" >&2; exit 1; fi; `+
+			`exec docker exec -it "$c" sh -c %s`,
+		appUUID, appUUID, utils.ShellQuote(start))
+
+	opts, target := serverSSHTarget(server, identity)
+	sshArgs := append(opts, "-t", target, remote)
+
+	ssh := exec.Command("ssh", sshArgs...)
+	ssh.Stdin = os.Stdin
+	ssh.Stdout = os.Stdout
+	ssh.Stderr = os.Stderr
+
+	if err := ssh.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			return &exitStatusError{code: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("failed to run ssh: %w", err)
+	}
+	return nil
+}
+
+// appServer returns the server an application runs on, or the server from
+// serverUUID when it is set
+func appServer(client *api.Client, appUUID, serverUUID string) (*api.Server, error) {
+	if serverUUID != "" {
+		return resourceServer(client, 0, serverUUID)
+	}
+
+	app, err := client.GetApplication(appUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch application: %w", err)
+	}
+	return resourceServer(client, app.DestinationID, "")
+}
+
+// resourceServer returns the server of a resource's destination. serverUUID
+// overrides the lookup, and the user is prompted when it can't be resolved.
+func resourceServer(client *api.Client, destinationID int, serverUUID string) (*api.Server, error) {
+	if serverUUID == "" && destinationID != 0 {
+		destinations, err := client.ListDestinations()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list destinations: %w", err)
+		}
+		for _, d := range destinations {
+			if d.ID == destinationID {
+				serverUUID = d.ServerUUID
+				break
+			}
+		}
+	}
+
+	if serverUUID == "" {
+		var err error
+		serverUUID, err = promptServerUUID(client)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	server, err := client.GetServer(serverUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server: %w", err)
+	}
+	if server.IP == "" {
+		return nil, fmt.Errorf("server %s has no IP address", serverUUID)
+	}
+	return server, nil
+}
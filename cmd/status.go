@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/docker"
+	"github.com/entro314-labs/cool-kit/internal/sentinel"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the application's health and its server's usage",
+	Long: `Show the linked application's status, the CPU, memory and disk usage of
+the server it runs on, the state and restart counts of its containers and
+the outcome of its latest deployments.
+
+CPU and memory come from Coolify's Sentinel agent on the server, over the
+last 30 minutes; enable Sentinel and its metrics in the server's settings,
+and use an API token that can read sensitive data, which the Sentinel token
+is. The disk usage and the containers are read with docker on the server.
+cool-kit reads all of them over ssh, so the server must accept your ssh key;
+use --identity to choose the key. 'cool-kit ls' shows the status alone.
+
+Examples:
+  cool-kit status
+  cool-kit status -i ~/.ssh/coolify
+  cool-kit status --format json`,
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+}
+
+const (
+	// statusWindow is how far back the CPU and memory usage go
+	statusWindow = 30 * time.Minute
+	// statusDeployments is how many deployments status lists
+	statusDeployments = 5
+)
+
+func init() {
+	statusCmd.Flags().StringP("identity", "i", "", "SSH private key file")
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	identity, _ := cmd.Flags().GetString("identity")
+	format, err := structuredFormat(cmd)
+	if err != nil {
+		return err
+	}
+	projectCfg, client, err := linkedProject()
+	if err != nil {
+		return err
+	}
+
+	if format != "" {
+		return printStatusRecord(format, client, projectCfg, identity)
+	}
+
+	app, err := showProject(client, projectCfg)
+	if err != nil || app == nil {
+		return err
+	}
+
+	server, snapshot, err := readServer(client, projectCfg, app, identity, format)
+	if err != nil {
+		ui.Spacer()
+		ui.Warning(fmt.Sprintf("Couldn't read the server: %v", err))
+	} else {
+		showServerUsage(server, snapshot)
+		showContainers(snapshot.Containers)
+	}
+
+	deployments, err := client.ListDeployments(app.UUID)
+	if err != nil {
+		ui.Spacer()
+		ui.Warning(fmt.Sprintf("Couldn't load deployments: %v", err))
+		return nil
+	}
+	showRecentDeployments(deployments)
+	return nil
+}
+
+// readServer reads the usage of the application's server and the state of
+// its containers over ssh
+func readServer(client *api.Client, projectCfg *config.ProjectConfig, app *api.Application, identity, format string) (*api.Server, *sentinel.Snapshot, error) {
+	// The application's destination is where it runs now
+	serverUUID := ""
+	if app.DestinationID == 0 {
+		serverUUID = projectCfg.ServerUUID
+	}
+	server, err := resourceServer(client, app.DestinationID, serverUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token, note := "", ""
+	switch s := server.Settings; {
+	case s == nil || !s.IsSentinelEnabled:
+		note = "Sentinel isn't enabled on the server"
+	case !s.IsMetricsEnabled:
+		note = "Sentinel's metrics aren't enabled on the server"
+	case s.SentinelToken == "":
+		note = "the API token can't read the server's Sentinel token"
+	default:
+		token = s.SentinelToken
+	}
+
+	var snapshot *sentinel.Snapshot
+	err = loadTask(format, ui.Task{
+		Name:         "read-server",
+		ActiveName:   fmt.Sprintf("Reading %s over ssh...", server.Name),
+		CompleteName: fmt.Sprintf("✓ Read %s", server.Name),
+		Action: func() error {
+			out, err := runServerCommand(server, identity, sentinel.Script(app.UUID, token, time.Now().Add(-statusWindow)))
+			if err != nil {
+				return err
+			}
+			snapshot, err = sentinel.Parse(out)
+			return err
+		},
+	})
+	if err != nil {
+		return server, nil, err
+	}
+	if note != "" {
+		snapshot.SentinelError = note
+	}
+	return server, snapshot, nil
+}
+
+func showServerUsage(server *api.Server, snapshot *sentinel.Snapshot) {
+	ui.Spacer()
+	ui.Bold(fmt.Sprintf("Server: %s (%s)", server.Name, server.IP))
+	if snapshot.CPU != nil {
+		ui.KeyValue("CPU", metricSummary(snapshot.CPU))
+		ui.KeyValue("Memory", metricSummary(snapshot.Memory))
+	} else {
+		ui.KeyValue("CPU, memory", ui.DimStyle.Render("unavailable: "+snapshot.SentinelError))
+	}
+	if d := snapshot.Disk; d != nil {
+		ui.KeyValue("Disk", fmt.Sprintf("%s  %s of %s", usageBar(d.Percent()), docker.FormatSize(d.Used), docker.FormatSize(d.Total)))
+	}
+}
+
+// metricSummary shows a metric's latest sample with a bar, and its average
+// and peak over the window
+func metricSummary(m *sentinel.Metric) string {
+	return fmt.Sprintf("%s  avg %.0f%%, peak %.0f%% over %d min", usageBar(m.Current()), m.Average(), m.Peak(), int(statusWindow.Minutes()))
+}
+
+// usageBar draws a percentage as a bar, coloured as it gets full
+func usageBar(percent float64) string {
+	const width = 20
+	filled := int(percent/100*width + 0.5)
+	filled = min(max(filled, 0), width)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+
+	style := ui.SuccessStyle
+	switch {
+	case percent >= 90:
+		style = ui.ErrorStyle
+	case percent >= 75:
+		style = ui.WarningStyle
+	}
+	return fmt.Sprintf("%s %5.1f%%", style.Render(bar), percent)
+}
+
+func showContainers(containers []sentinel.Container) {
+	ui.Spacer()
+	ui.Bold("Containers")
+	if len(containers) == 0 {
+		ui.Dim("No containers found on the server")
+		return
+	}
+
+	rows := make([][]string, 0, len(containers))
+	restarting := false
+	for _, c := range containers {
+		up := "-"
+		if c.State == "running" && !c.StartedAt.IsZero() {
+			up = formatUptime(time.Since(c.StartedAt))
+		}
+		rows = append(rows, []string{c.Name, c.State, orDash(c.Health), strconv.Itoa(c.Restarts), up})
+		restarting = restarting || c.Restarts > 0
+	}
+	ui.Table([]string{"CONTAINER", "STATE", "HEALTH", "RESTARTS", "UP"}, rows)
+	if restarting {
+		ui.Dim(fmt.Sprintf("A container restarted: run '%s logs' to see why", execName()))
+	}
+}
+
+// formatUptime formats how long a container has run, like 3d4h or 12m
+func formatUptime(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
+
+func showRecentDeployments(deployments []api.Deployment) {
+	ui.Spacer()
+	ui.Bold("Recent deployments")
+	if len(deployments) == 0 {
+		ui.Dim("No deployments found")
+		return
+	}
+	if len(deployments) > statusDeployments {
+		deployments = deployments[:statusDeployments]
+	}
+
+	rows := make([][]string, 0, len(deployments))
+	finished := 0
+	for _, d := range deployments {
+		target := "production"
+		if pr := d.PullRequest(); pr > 0 {
+			target = fmt.Sprintf("PR #%d", pr)
+		}
+		if d.Status == "finished" {
+			finished++
+		}
+		rows = append(rows, []string{d.Status, target, formatDeployTime(d.Created()), formatDuration(d.Duration()), orDash(shortCommit(d.CommitSHA()))})
+	}
+	ui.Table([]string{"STATUS", "TARGET", "STARTED", "DURATION", "COMMIT"}, rows)
+	ui.Dim(fmt.Sprintf("%d of the last %d finished", finished, len(deployments)))
+}
+
+// printStatusRecord prints the project, its server's usage, its containers
+// and its latest deployments as JSON or YAML
+func printStatusRecord(format string, client *api.Client, projectCfg *config.ProjectConfig, identity string) error {
+	record := statusRecord{
+		projectRecord: projectRecord{
+			Name:         projectCfg.Name,
+			AppUUID:      projectCfg.AppUUID,
+			DeployMethod: projectCfg.DeployMethod,
+			Framework:    projectCfg.Framework,
+		},
+		Containers:  []containerRecord{},
+		Deployments: []deploymentRecord{},
+	}
+	if projectCfg.AppUUID == "" {
+		return formatOutput(format, record)
+	}
+
+	app, err := client.GetApplication(projectCfg.AppUUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch application: %w", err)
+	}
+	record.Status = app.Status
+	record.URL = app.PrimaryURL()
+	record.PreviewURLTemplate = app.PreviewURLTemplate
+
+	// A server that can't be read leaves it null, like the table's warning
+	if server, snapshot, err := readServer(client, projectCfg, app, identity, format); err == nil {
+		usage := &serverUsageRecord{UUID: server.UUID, Name: server.Name, SentinelError: snapshot.SentinelError}
+		if snapshot.CPU != nil {
+			cpu, memory := snapshot.CPU.Current(), snapshot.Memory.Current()
+			usage.CPU, usage.Memory = &cpu, &memory
+		}
+		if snapshot.Disk != nil {
+			disk := snapshot.Disk.Percent()
+			usage.Disk = &disk
+		}
+		record.Server = usage
+		for _, c := range snapshot.Containers {
+			r := containerRecord{Name: c.Name, State: c.State, Health: c.Health, Restarts: c.Restarts}
+			if !c.StartedAt.IsZero() {
+				r.StartedAt = c.StartedAt.UTC().Format(time.RFC3339)
+			}
+			record.Containers = append(record.Containers, r)
+		}
+	}
+
+	deployments, err := client.ListDeployments(app.UUID)
+	if err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+	if len(deployments) > statusDeployments {
+		deployments = deployments[:statusDeployments]
+	}
+	for _, d := range deployments {
+		record.Deployments = append(record.Deployments, newDeploymentRecord(&d, ""))
+	}
+	return formatOutput(format, record)
+}
@@ -97,7 +97,7 @@ func getAPIClient() (*api.Client, error) {
 		return nil, fmt.Errorf("no Coolify instance configured. Run 'cool-kit login' first")
 	}
 
-	return api.NewClient(cfg.CoolifyURL, cfg.CoolifyToken), nil
+	return newAPIClient(cfg.CoolifyURL, cfg.CoolifyToken), nil
 }
 
 // formatOutput formats and prints output
@@ -115,9 +115,9 @@ func formatOutput(format string, data interface{}) error {
 
 func init() {
 	// Add format flag
-	teamListCmd.Flags().String("format", "table", "Output format: table, json, pretty")
-	teamCurrentCmd.Flags().String("format", "table", "Output format: table, json, pretty")
-	teamMembersCmd.Flags().String("format", "table", "Output format: table, json, pretty")
+	teamListCmd.Flags().String("format", "table", "Output format: table, json, yaml or pretty")
+	teamCurrentCmd.Flags().String("format", "table", "Output format: table, json, yaml or pretty")
+	teamMembersCmd.Flags().String("format", "table", "Output format: table, json, yaml or pretty")
 
 	// Wire commands
 	teamCmd.AddCommand(teamListCmd)
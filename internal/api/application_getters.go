@@ -1,5 +1,13 @@
 package api
 
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 // GetRepositoryProjectID returns the RepositoryProjectID or 0 if nil
 func (a *Application) GetRepositoryProjectID() int {
 	if a.RepositoryProjectID == nil {
@@ -167,3 +175,73 @@ func (a *Application) GetRedirect() string {
 	}
 	return *a.Redirect
 }
+
+// CommitPinned reports whether the application deploys a fixed commit, as
+// after a rollback, instead of its branch head
+func (a *Application) CommitPinned() bool {
+	return a.GitCommitSHA != "" && a.GitCommitSHA != "HEAD"
+}
+
+// PrimaryURL returns the application's first domain, or "" if it has none.
+// A docker compose application's is the first of its services' by name.
+func (a *Application) PrimaryURL() string {
+	fqdn := a.GetFqdn()
+	if fqdn == "" {
+		domains := a.ComposeDomains()
+		names := make([]string, 0, len(domains))
+		for name := range domains {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) > 0 {
+			fqdn = domains[names[0]]
+		}
+	}
+	return strings.TrimSpace(strings.Split(fqdn, ",")[0])
+}
+
+// ComposeDomains returns the domains of a docker compose application's
+// services by service name, leaving out those without one
+func (a *Application) ComposeDomains() map[string]string {
+	var stored map[string]struct {
+		Domain string `json:"domain"`
+	}
+	if err := json.Unmarshal([]byte(a.GetDockerComposeDomains()), &stored); err != nil {
+		return nil
+	}
+	domains := map[string]string{}
+	for name, s := range stored {
+		if s.Domain != "" {
+			domains[name] = s.Domain
+		}
+	}
+	return domains
+}
+
+// defaultPreviewURLTemplate is Coolify's default preview domain template
+const defaultPreviewURLTemplate = "{{pr_id}}.{{domain}}"
+
+// PreviewURL renders the preview URL template for a pull request using the
+// application's first domain. It returns "" when the application has no domain.
+func (a *Application) PreviewURL(pr int) string {
+	fqdn := a.PrimaryURL()
+	if fqdn == "" {
+		return ""
+	}
+
+	scheme, host := "https", fqdn
+	if u, err := url.Parse(fqdn); err == nil && u.Host != "" {
+		scheme, host = u.Scheme, u.Host
+	}
+
+	template := a.PreviewURLTemplate
+	if template == "" {
+		template = defaultPreviewURLTemplate
+	}
+
+	domain := strings.NewReplacer(
+		"{{pr_id}}", strconv.Itoa(pr),
+		"{{domain}}", host,
+	).Replace(template)
+	return scheme + "://" + domain
+}
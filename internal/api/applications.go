@@ -68,6 +68,25 @@ func (c *Client) UpdateApplication(uuid string, updates map[string]interface{})
 	return c.Patch("/applications/"+uuid, updates, nil)
 }
 
+// UnpinCommit points the application back at its branch head after a
+// rollback pinned it to an older commit. Coolify deploys the pinned commit
+// on every push and API deploy until then.
+func (c *Client) UnpinCommit(uuid string) error {
+	app, err := c.GetApplication(uuid)
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+	if !app.CommitPinned() {
+		return nil
+	}
+	if err := c.UpdateApplication(uuid, map[string]interface{}{
+		"git_commit_sha": "HEAD",
+	}); err != nil {
+		return fmt.Errorf("failed to reset pinned commit: %w", err)
+	}
+	return nil
+}
+
 // UpdateApplicationWithContext updates an existing application (CAGC pattern)
 func (c *Client) UpdateApplicationWithContext(ctx context.Context, uuid string, app Application) (*CreateResponse, error) {
 	path := fmt.Sprintf("/applications/%s", uuid)
@@ -90,6 +109,11 @@ func (c *Client) DeleteApplicationWithContext(ctx context.Context, uuid string,
 	return &response, err
 }
 
+// DeleteApplicationPreview removes the preview deployment of a pull request
+func (c *Client) DeleteApplicationPreview(uuid string, pr int) error {
+	return c.Delete(fmt.Sprintf("/applications/%s/previews/%d", uuid, pr))
+}
+
 // StartApplication starts an application (CAGC pattern)
 func (c *Client) StartApplication(ctx context.Context, uuid string, force, instantDeploy bool) (*DeploymentResponse, error) {
 	path := fmt.Sprintf("/applications/%s/start?force=%t&instant_deploy=%t", uuid, force, instantDeploy)
@@ -214,6 +238,14 @@ func (c *Client) CreatePrivateGitHubApp(req *CreatePrivateGitHubAppRequest) (*Cr
 	return &resp, err
 }
 
+// CreatePrivateDeployKeyApp creates an application from a private repository
+// on any git host, cloned with the Coolify private key in req.PrivateKeyUUID
+func (c *Client) CreatePrivateDeployKeyApp(req *CreatePrivateGitHubAppRequest) (*CreateAppResponse, error) {
+	var resp CreateAppResponse
+	err := c.Post("/applications/private-deploy-key", req, &resp)
+	return &resp, err
+}
+
 // CreatePrivateGithubAppApplication creates a new application based on a private repo through Github App (CAGC pattern)
 func (c *Client) CreatePrivateGithubAppApplication(ctx context.Context, app Application) (*CreateResponse, error) {
 	var response CreateResponse
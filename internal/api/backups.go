@@ -0,0 +1,97 @@
+package api
+
+import "fmt"
+
+// ScheduledBackup is a backup schedule attached to a standalone database
+type ScheduledBackup struct {
+	ID                     int               `json:"id"`
+	UUID                   string            `json:"uuid"`
+	Enabled                bool              `json:"enabled"`
+	Frequency              string            `json:"frequency"`
+	SaveS3                 bool              `json:"save_s3"`
+	S3StorageID            *int              `json:"s3_storage_id,omitempty"`
+	DumpAll                bool              `json:"dump_all"`
+	DatabasesToBackup      string            `json:"databases_to_backup,omitempty"`
+	RetentionAmountLocally int               `json:"database_backup_retention_amount_locally"`
+	RetentionDaysLocally   int               `json:"database_backup_retention_days_locally"`
+	RetentionAmountS3      int               `json:"database_backup_retention_amount_s3"`
+	RetentionDaysS3        int               `json:"database_backup_retention_days_s3"`
+	Executions             []BackupExecution `json:"executions,omitempty"`
+	CreatedAt              string            `json:"created_at"`
+	UpdatedAt              string            `json:"updated_at"`
+}
+
+// BackupExecution is a single run of a scheduled backup
+type BackupExecution struct {
+	ID                  int    `json:"id"`
+	UUID                string `json:"uuid"`
+	Status              string `json:"status"`
+	Message             string `json:"message,omitempty"`
+	Size                int64  `json:"size"`
+	Filename            string `json:"filename"`
+	DatabaseName        string `json:"database_name,omitempty"`
+	S3Uploaded          *bool  `json:"s3_uploaded,omitempty"`
+	LocalStorageDeleted bool   `json:"local_storage_deleted"`
+	CreatedAt           string `json:"created_at"`
+	FinishedAt          string `json:"finished_at,omitempty"`
+}
+
+// BackupRequest is the request body for creating or updating a backup schedule.
+// Nil fields are left unchanged on update.
+type BackupRequest struct {
+	Frequency              string `json:"frequency,omitempty"`
+	Enabled                *bool  `json:"enabled,omitempty"`
+	SaveS3                 *bool  `json:"save_s3,omitempty"`
+	S3StorageUUID          string `json:"s3_storage_uuid,omitempty"`
+	DumpAll                *bool  `json:"dump_all,omitempty"`
+	DatabasesToBackup      string `json:"databases_to_backup,omitempty"`
+	BackupNow              *bool  `json:"backup_now,omitempty"`
+	RetentionAmountLocally *int   `json:"database_backup_retention_amount_locally,omitempty"`
+	RetentionDaysLocally   *int   `json:"database_backup_retention_days_locally,omitempty"`
+	RetentionAmountS3      *int   `json:"database_backup_retention_amount_s3,omitempty"`
+	RetentionDaysS3        *int   `json:"database_backup_retention_days_s3,omitempty"`
+}
+
+// ListDatabaseBackups returns the backup schedules of a database
+func (c *Client) ListDatabaseBackups(dbUUID string) ([]ScheduledBackup, error) {
+	var backups []ScheduledBackup
+	err := c.Get(fmt.Sprintf("/databases/%s/backups", dbUUID), &backups)
+	return backups, err
+}
+
+// CreateDatabaseBackup creates a backup schedule for a database
+func (c *Client) CreateDatabaseBackup(dbUUID string, req *BackupRequest) (*CreateResponse, error) {
+	var resp CreateResponse
+	err := c.Post(fmt.Sprintf("/databases/%s/backups", dbUUID), req, &resp)
+	return &resp, err
+}
+
+// UpdateDatabaseBackup updates a backup schedule
+func (c *Client) UpdateDatabaseBackup(dbUUID, backupUUID string, req *BackupRequest) error {
+	return c.Patch(fmt.Sprintf("/databases/%s/backups/%s", dbUUID, backupUUID), req, nil)
+}
+
+// DeleteDatabaseBackup deletes a backup schedule
+func (c *Client) DeleteDatabaseBackup(dbUUID, backupUUID string) error {
+	return c.Delete(fmt.Sprintf("/databases/%s/backups/%s", dbUUID, backupUUID))
+}
+
+// TriggerDatabaseBackup runs a backup schedule immediately
+func (c *Client) TriggerDatabaseBackup(dbUUID, backupUUID string) error {
+	now := true
+	return c.UpdateDatabaseBackup(dbUUID, backupUUID, &BackupRequest{BackupNow: &now})
+}
+
+// ListBackupExecutions returns the executions of a backup schedule, newest first
+func (c *Client) ListBackupExecutions(dbUUID, backupUUID string) ([]BackupExecution, error) {
+	var resp struct {
+		Executions []BackupExecution `json:"executions"`
+	}
+	err := c.Get(fmt.Sprintf("/databases/%s/backups/%s/executions", dbUUID, backupUUID), &resp)
+	return resp.Executions, err
+}
+
+// DeleteBackupExecution deletes a backup execution and its stored file
+func (c *Client) DeleteBackupExecution(dbUUID, backupUUID, executionUUID string) error {
+	return c.Delete(fmt.Sprintf("/databases/%s/backups/%s/executions/%s", dbUUID, backupUUID, executionUUID))
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// cacheRules lists the GET endpoints whose responses change rarely enough to be
+// cached between CLI invocations, keyed by path relative to /api/v1. A "*"
+// segment matches any single path segment.
+//
+// Single servers are deliberately absent: they are polled while validating.
+var cacheRules = []struct {
+	pattern string
+	ttl     time.Duration
+}{
+	{"servers", 30 * time.Second},
+	{"projects", time.Minute},
+	{"projects/*", time.Minute},
+	{"teams", 5 * time.Minute},
+	{"teams/current", 5 * time.Minute},
+	{"github-apps", 5 * time.Minute},
+}
+
+// ResponseCache stores responses of cacheable GET requests on disk. Fresh
+// entries are served without contacting the instance; stale entries are
+// revalidated with If-None-Match / If-Modified-Since when the server sent
+// validators.
+type ResponseCache struct {
+	dir string
+}
+
+// cacheEntry is the on-disk representation of a cached response
+type cacheEntry struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+	Body         []byte    `json:"body"`
+}
+
+// NewResponseCache returns a cache that stores entries in dir
+func NewResponseCache(dir string) *ResponseCache {
+	return &ResponseCache{dir: dir}
+}
+
+// DefaultCacheDir returns the directory used for cached API responses
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cool-kit", "api"), nil
+}
+
+// Clear removes all cached responses
+func (rc *ResponseCache) Clear() error {
+	return os.RemoveAll(rc.dir)
+}
+
+// WithCache serves rarely-changing GET responses from rc
+func WithCache(rc *ResponseCache) ClientOption {
+	return func(c *Client) {
+		c.cache = rc
+	}
+}
+
+// cachingTransport answers cacheable GETs from a ResponseCache and invalidates
+// entries when other requests may have changed the underlying resources.
+type cachingTransport struct {
+	base  http.RoundTripper
+	cache *ResponseCache
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := apiRelativePath(req.URL.Path)
+
+	ttl, cacheable := cacheTTL(path)
+	if req.Method != http.MethodGet || !cacheable {
+		resp, err := t.base.RoundTrip(req)
+		// Coolify triggers actions such as restart or validate with GET, so any
+		// other successful request touching a resource drops its cached entries.
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			t.cache.invalidate(path)
+		}
+		return resp, err
+	}
+
+	key := t.cache.key(req, path)
+	entry := t.cache.load(key)
+
+	if entry != nil && time.Since(entry.StoredAt) < ttl {
+		return cachedResponse(req, entry.Body), nil
+	}
+
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		drainAndClose(resp.Body)
+		entry.StoredAt = time.Now()
+		t.cache.store(key, entry)
+		return cachedResponse(req, entry.Body), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	t.cache.store(key, &cacheEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		StoredAt:     time.Now(),
+		Body:         body,
+	})
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// cachedResponse builds a 200 response from a cached body
+func cachedResponse(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// apiRelativePath strips everything up to and including /api/v1/ from a URL path
+func apiRelativePath(path string) string {
+	if i := strings.Index(path, "/api/v1/"); i >= 0 {
+		path = path[i+len("/api/v1/"):]
+	}
+	return strings.Trim(path, "/")
+}
+
+// cacheTTL reports whether path may be cached and for how long
+func cacheTTL(path string) (time.Duration, bool) {
+	segments := strings.Split(path, "/")
+	for _, rule := range cacheRules {
+		if matchSegments(strings.Split(rule.pattern, "/"), segments) {
+			return rule.ttl, true
+		}
+	}
+	return 0, false
+}
+
+// matchSegments matches path segments against a pattern where "*" matches any segment
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// key names the cache file for a request. Entries are prefixed with the
+// resource so they can be invalidated together, and keyed by token so
+// different accounts never share responses.
+func (rc *ResponseCache) key(req *http.Request, path string) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization") + "\n" + req.URL.String()))
+	return resourceOf(path) + "-" + hex.EncodeToString(sum[:16])
+}
+
+// resourceOf returns the top-level resource of an API path, e.g. "servers"
+func resourceOf(path string) string {
+	resource, _, _ := strings.Cut(path, "/")
+	return resource
+}
+
+func (rc *ResponseCache) load(key string) *cacheEntry {
+	data, err := os.ReadFile(filepath.Join(rc.dir, key+".json"))
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// store writes an entry atomically. Failures are ignored: the cache is an
+// optimisation and must never break a request.
+func (rc *ResponseCache) store(key string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(rc.dir, 0700); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(rc.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	_ = os.Rename(tmp.Name(), filepath.Join(rc.dir, key+".json"))
+}
+
+// invalidate removes all entries for the resource path belongs to
+func (rc *ResponseCache) invalidate(path string) {
+	resource := resourceOf(path)
+	if resource == "" {
+		return
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(rc.dir, resource+"-*.json"))
+	for _, m := range matches {
+		_ = os.Remove(m)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -22,6 +23,7 @@ type Client struct {
 	debug      bool
 	retries    int
 	timeout    time.Duration
+	cache      *ResponseCache
 }
 
 // APIError represents an error from the Coolify API
@@ -50,6 +52,25 @@ func IsNotFound(err error) bool {
 	return false
 }
 
+// IsUnauthorized returns true if the error is a 401 Unauthorized
+func IsUnauthorized(err error) bool {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.StatusCode == 401
+	}
+	return false
+}
+
+// IsForbidden returns true if the error is a 403 Forbidden
+func IsForbidden(err error) bool {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.StatusCode == 403
+	}
+	return false
+}
+
+// plainText receives a response body that isn't JSON, like /version's
+type plainText string
+
 // ClientOption is a functional option for configuring the client
 type ClientOption func(*Client)
 
@@ -75,6 +96,35 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithTransport replaces the shared HTTP transport, e.g. for tests
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Transport = rt
+	}
+}
+
+// sharedTransport is reused by every client so the many sequential calls made by
+// commands like the setup wizard share pooled keep-alive connections.
+var sharedTransport = newTransport()
+
+// newTransport returns a transport tuned for talking to a single Coolify host.
+// Compression is left enabled, so responses are requested and decoded as gzip.
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
 // NewClient creates a new Coolify API client with optional configuration
 func NewClient(baseURL, token string, opts ...ClientOption) *Client {
 	// Ensure baseURL doesn't have trailing slash
@@ -100,7 +150,8 @@ func NewClient(baseURL, token string, opts ...ClientOption) *Client {
 		retries: DefaultRetries,
 		timeout: DefaultTimeout,
 		httpClient: &http.Client{
-			Timeout: DefaultTimeout,
+			Timeout:   DefaultTimeout,
+			Transport: sharedTransport,
 		},
 	}
 
@@ -109,6 +160,13 @@ func NewClient(baseURL, token string, opts ...ClientOption) *Client {
 		opt(client)
 	}
 
+	if client.cache != nil {
+		client.httpClient.Transport = &cachingTransport{
+			base:  client.httpClient.Transport,
+			cache: client.cache,
+		}
+	}
+
 	return client
 }
 
@@ -122,7 +180,7 @@ const (
 
 // doRequest performs an HTTP request with context support (CAGC pattern)
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}, v interface{}) error {
-	u, err := c.BaseURL.Parse(path)
+	u, err := c.resolve(path)
 	if err != nil {
 		return err
 	}
@@ -138,6 +196,21 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	return c.doWithRetry(ctx, method, u.String(), reqBody, v)
 }
 
+// resolve returns the absolute URL for an API path. Paths are always relative to
+// /api/v1, with or without a leading slash.
+func (c *Client) resolve(path string) (*url.URL, error) {
+	rel, err := url.Parse(strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	base := *c.BaseURL
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	return base.ResolveReference(rel), nil
+}
+
 // doWithRetry executes request with exponential backoff
 func (c *Client) doWithRetry(ctx context.Context, method, urlStr string, body []byte, v interface{}) error {
 	var lastErr error
@@ -186,12 +259,12 @@ func (c *Client) doWithRetry(ctx context.Context, method, urlStr string, body []
 		// Don't retry on client errors (4xx) except maybe 429?
 		// For now simple logic: if 5xx retry, else return
 		if resp.StatusCode >= 500 {
-			resp.Body.Close()
+			drainAndClose(resp.Body)
 			lastErr = fmt.Errorf("server error: %d", resp.StatusCode)
 			continue
 		}
 
-		defer resp.Body.Close()
+		defer drainAndClose(resp.Body)
 
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			bodyBytes, err := io.ReadAll(resp.Body)
@@ -204,6 +277,14 @@ func (c *Client) doWithRetry(ctx context.Context, method, urlStr string, body []
 			}
 		}
 
+		if text, ok := v.(*plainText); ok {
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+			*text = plainText(strings.TrimSpace(string(data)))
+			return nil
+		}
 		if v != nil {
 			if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
 				return err
@@ -216,6 +297,12 @@ func (c *Client) doWithRetry(ctx context.Context, method, urlStr string, body []
 	return fmt.Errorf("request failed after %d retries: %w", c.retries, lastErr)
 }
 
+// drainAndClose reads any unread body bytes so the connection can return to the pool
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 64<<10))
+	body.Close()
+}
+
 // Convenience methods that use context.Background() internally.
 // For cancellation support and timeouts, use the context-based methods below directly.
 
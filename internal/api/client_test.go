@@ -0,0 +1,260 @@
+package api
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newWizardServer serves the endpoints the setup wizard calls in sequence
+func newWizardServer(tb testing.TB) *httptest.Server {
+	tb.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/servers", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Server{{UUID: "srv-1", Name: "main", IP: "203.0.113.10"}})
+	})
+	mux.HandleFunc("/api/v1/projects", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Project{{UUID: "prj-1", Name: "web"}})
+	})
+	mux.HandleFunc("/api/v1/github-apps", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]GitHubApp{{UUID: "gh-1", Name: "coolify"}})
+	})
+
+	srv := httptest.NewUnstartedServer(mux)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	tb.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClientResolvesPathsUnderAPIRoot(t *testing.T) {
+	c := NewClient("https://coolify.example.com/", "token")
+
+	for path, want := range map[string]string{
+		"/servers":        "https://coolify.example.com/api/v1/servers",
+		"security/keys":   "https://coolify.example.com/api/v1/security/keys",
+		"/apps?tag=web":   "https://coolify.example.com/api/v1/apps?tag=web",
+		"/databases/x/go": "https://coolify.example.com/api/v1/databases/x/go",
+	} {
+		u, err := c.resolve(path)
+		if err != nil {
+			t.Fatalf("resolve(%q) returned error: %v", path, err)
+		}
+		if u.String() != want {
+			t.Errorf("resolve(%q) = %s, want %s", path, u, want)
+		}
+	}
+}
+
+// BenchmarkWizardCalls measures ListServers+ListProjects+ListGitHubApps latency
+// with the pooled shared transport versus a connection per request.
+func BenchmarkWizardCalls(b *testing.B) {
+	srv := newWizardServer(b)
+	tlsConfig := srv.Client().Transport.(*http.Transport).TLSClientConfig
+
+	run := func(b *testing.B, newTransport func(*tls.Config) http.RoundTripper) {
+		for i := 0; i < b.N; i++ {
+			c := NewClient(srv.URL, "token", WithTransport(newTransport(tlsConfig)))
+			if _, err := c.ListServers(); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := c.ListProjects(); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := c.ListGitHubApps(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	pooled := newTransport()
+	b.Run("pooled", func(b *testing.B) {
+		run(b, func(cfg *tls.Config) http.RoundTripper {
+			pooled.TLSClientConfig = cfg
+			return pooled
+		})
+	})
+
+	b.Run("unpooled", func(b *testing.B) {
+		run(b, func(cfg *tls.Config) http.RoundTripper {
+			cfg = cfg.Clone()
+			cfg.NextProtos = []string{"http/1.1"}
+			return &http.Transport{
+				TLSClientConfig:     cfg,
+				DisableKeepAlives:   true,
+				TLSHandshakeTimeout: 10 * time.Second,
+			}
+		})
+	})
+}
+
+func TestResponseCacheRevalidatesAndInvalidates(t *testing.T) {
+	var hits, notModified int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/servers", func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.Method == http.MethodPost {
+			json.NewEncoder(w).Encode(CreateResponse{UUID: "srv-2"})
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		json.NewEncoder(w).Encode([]Server{{UUID: "srv-1", Name: "main"}})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	rc := NewResponseCache(t.TempDir())
+	c := NewClient(srv.URL, "token", WithCache(rc))
+
+	list := func() {
+		t.Helper()
+		servers, err := c.ListServers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(servers) != 1 || servers[0].UUID != "srv-1" {
+			t.Fatalf("unexpected servers: %+v", servers)
+		}
+	}
+
+	list()
+	list()
+	if hits != 1 {
+		t.Fatalf("fresh entry was not served from cache: %d requests", hits)
+	}
+
+	// Age the entry past its TTL so the next call revalidates with the ETag
+	u, _ := c.resolve("/servers")
+	req, _ := http.NewRequest(http.MethodGet, u.String(), nil)
+	req.Header.Set("Authorization", "Bearer token")
+	key := rc.key(req, "servers")
+	entry := rc.load(key)
+	entry.StoredAt = time.Now().Add(-time.Hour)
+	rc.store(key, entry)
+
+	list()
+	if notModified != 1 {
+		t.Fatalf("stale entry was not revalidated: %d requests, %d not modified", hits, notModified)
+	}
+
+	if _, err := c.CreateServer(&CreateServerRequest{Name: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if rc.load(key) != nil {
+		t.Fatal("POST /servers did not invalidate cached server list")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/api/v1/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("4.0.0-beta.420\n"))
+	})
+	mux.HandleFunc("/api/v1/teams/current", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"Unauthenticated."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(Team{ID: 0, Name: "Root Team"})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	info, err := NewClient(srv.URL, "good").Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "4.0.0-beta.420" || info.Team != "Root Team" {
+		t.Errorf("info = %+v", info)
+	}
+
+	_, err = NewClient(srv.URL, "typo").Verify()
+	if err == nil || !strings.Contains(err.Error(), "rejected the API token") {
+		t.Errorf("bad token: got %v", err)
+	}
+
+	_, err = NewClient(srv.URL+"/not-coolify", "good").Verify()
+	if err == nil || !strings.Contains(err.Error(), "doesn't look like a Coolify instance") {
+		t.Errorf("wrong URL: got %v", err)
+	}
+}
+
+func TestParseLogs(t *testing.T) {
+	raw := `[{"output":"Building","timestamp":"2024-05-01T10:00:00.000000Z","order":1},` +
+		`{"output":"secret","hidden":true,"order":2}]` +
+		`[{"output":"Done","timestamp":"2024-05-01T10:01:00.000000Z","order":3}]`
+
+	if got := ParseLogs(raw); got != "Building\nDone" {
+		t.Errorf("ParseLogs = %q", got)
+	}
+	want := "2024-05-01T10:00:00.000000Z Building\n2024-05-01T10:01:00.000000Z Done"
+	if got := ParseLogsWithTimestamps(raw); got != want {
+		t.Errorf("ParseLogsWithTimestamps = %q", got)
+	}
+	if got := ParseLogs("plain text"); got != "plain text" {
+		t.Errorf("ParseLogs(plain) = %q", got)
+	}
+}
+
+func TestComposePrimaryURL(t *testing.T) {
+	domains := `{"worker":{"domain":""},"web":{"domain":"https://shop.example.com:3000"},"api":{"domain":"https://api.example.com"}}`
+	app := &Application{DockerComposeDomains: &domains}
+
+	if got := app.ComposeDomains(); len(got) != 2 || got["web"] != "https://shop.example.com:3000" {
+		t.Errorf("ComposeDomains = %v", got)
+	}
+	if got := app.PrimaryURL(); got != "https://api.example.com" {
+		t.Errorf("PrimaryURL = %q, want the api service's domain", got)
+	}
+
+	fqdn := "https://example.com,https://www.example.com"
+	app.Fqdn = &fqdn
+	if got := app.PrimaryURL(); got != "https://example.com" {
+		t.Errorf("PrimaryURL = %q, want the application's own domain", got)
+	}
+}
+
+func TestUnpinCommit(t *testing.T) {
+	pinned := map[string]string{"pinned": "3f2a9c1e", "head": "HEAD", "unset": ""}
+	var patched []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/applications/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		uuid := r.PathValue("uuid")
+		if r.Method == http.MethodPatch {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["git_commit_sha"] != "HEAD" {
+				t.Errorf("PATCH %s with %v", uuid, body)
+			}
+			patched = append(patched, uuid)
+			return
+		}
+		json.NewEncoder(w).Encode(Application{UUID: uuid, GitCommitSHA: pinned[uuid]})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := NewClient(srv.URL, "t")
+	for _, uuid := range []string{"pinned", "head", "unset"} {
+		if err := client.UnpinCommit(uuid); err != nil {
+			t.Fatalf("UnpinCommit(%s): %v", uuid, err)
+		}
+	}
+	if len(patched) != 1 || patched[0] != "pinned" {
+		t.Errorf("patched %v, want only the pinned application", patched)
+	}
+}
@@ -1,63 +1,209 @@
 package api
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+// Database engines supported by the Coolify API
+const (
+	DatabasePostgreSQL = "postgresql"
+	DatabaseMySQL      = "mysql"
+	DatabaseMariaDB    = "mariadb"
+	DatabaseMongoDB    = "mongodb"
+	DatabaseRedis      = "redis"
+	DatabaseKeyDB      = "keydb"
+	DatabaseDragonfly  = "dragonfly"
+	DatabaseClickHouse = "clickhouse"
+)
+
+// DatabaseEngines lists every engine that can be created through the API
+var DatabaseEngines = []string{
+	DatabasePostgreSQL,
+	DatabaseMySQL,
+	DatabaseMariaDB,
+	DatabaseMongoDB,
+	DatabaseRedis,
+	DatabaseKeyDB,
+	DatabaseDragonfly,
+	DatabaseClickHouse,
+}
 
 // Database represents a Coolify database
 type Database struct {
-	ID          int    `json:"id"`
-	UUID        string `json:"uuid"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Type        string `json:"type"`
-	Status      string `json:"status"`
-	Image       string `json:"image"`
-	IsPublic    bool   `json:"is_public"`
-}
-
-// Service represents a Coolify service
-type Service struct {
-	ID          int    `json:"id"`
-	UUID        string `json:"uuid"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Type        string `json:"type"`
-	Status      string `json:"status"`
-	Image       string `json:"image"`
-	IsPublic    bool   `json:"is_public"`
-}
-
-// CreateDatabaseRequest is the request body for creating a database
+	ID            int    `json:"id"`
+	UUID          string `json:"uuid"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Type          string `json:"type"`
+	DatabaseType  string `json:"database_type,omitempty"`
+	Status        string `json:"status"`
+	Image         string `json:"image"`
+	DestinationID int    `json:"destination_id,omitempty"`
+	EnvironmentID int    `json:"environment_id,omitempty"`
+	IsPublic      bool   `json:"is_public"`
+	PublicPort    int    `json:"public_port,omitempty"`
+	InternalDBURL string `json:"internal_db_url,omitempty"`
+	ExternalDBURL string `json:"external_db_url,omitempty"`
+	LimitsMemory  string `json:"limits_memory,omitempty"`
+	LimitsCPUs    string `json:"limits_cpus,omitempty"`
+}
+
+// DatabaseRequest is implemented by every engine-specific create request
+type DatabaseRequest interface {
+	// Engine returns the engine path segment used by the create endpoint
+	Engine() string
+}
+
+// CreateDatabaseRequest holds the fields shared by every database engine
 type CreateDatabaseRequest struct {
 	ProjectUUID     string `json:"project_uuid"`
 	ServerUUID      string `json:"server_uuid"`
 	EnvironmentName string `json:"environment_name,omitempty"`
-	Name            string `json:"name"`
+	EnvironmentUUID string `json:"environment_uuid,omitempty"`
+	DestinationUUID string `json:"destination_uuid,omitempty"`
+	Name            string `json:"name,omitempty"`
 	Description     string `json:"description,omitempty"`
-	Type            string `json:"type"`
 	Image           string `json:"image,omitempty"`
 	IsPublic        bool   `json:"is_public,omitempty"`
+	PublicPort      int    `json:"public_port,omitempty"`
+	LimitsMemory    string `json:"limits_memory,omitempty"`
+	LimitsCPUs      string `json:"limits_cpus,omitempty"`
+	InstantDeploy   bool   `json:"instant_deploy,omitempty"`
 }
 
-// CreateServiceRequest is the request body for creating a service
-type CreateServiceRequest struct {
-	ProjectUUID     string `json:"project_uuid"`
-	ServerUUID      string `json:"server_uuid"`
-	EnvironmentName string `json:"environment_name,omitempty"`
-	Name            string `json:"name"`
-	Description     string `json:"description,omitempty"`
-	Type            string `json:"type"`
-	Image           string `json:"image,omitempty"`
-	IsPublic        bool   `json:"is_public,omitempty"`
+// CreatePostgreSQLRequest is the request body for creating a PostgreSQL database
+type CreatePostgreSQLRequest struct {
+	CreateDatabaseRequest
+	PostgresUser           string `json:"postgres_user,omitempty"`
+	PostgresPassword       string `json:"postgres_password,omitempty"`
+	PostgresDB             string `json:"postgres_db,omitempty"`
+	PostgresInitdbArgs     string `json:"postgres_initdb_args,omitempty"`
+	PostgresHostAuthMethod string `json:"postgres_host_auth_method,omitempty"`
+	PostgresConf           string `json:"postgres_conf,omitempty"`
 }
 
-// CreateDatabaseResponse is the response from creating a database
-type CreateDatabaseResponse struct {
-	UUID string `json:"uuid"`
+// Engine implements DatabaseRequest
+func (r *CreatePostgreSQLRequest) Engine() string { return DatabasePostgreSQL }
+
+// CreateMySQLRequest is the request body for creating a MySQL database
+type CreateMySQLRequest struct {
+	CreateDatabaseRequest
+	MySQLRootPassword string `json:"mysql_root_password,omitempty"`
+	MySQLUser         string `json:"mysql_user,omitempty"`
+	MySQLPassword     string `json:"mysql_password,omitempty"`
+	MySQLDatabase     string `json:"mysql_database,omitempty"`
+	MySQLConf         string `json:"mysql_conf,omitempty"`
+}
+
+// Engine implements DatabaseRequest
+func (r *CreateMySQLRequest) Engine() string { return DatabaseMySQL }
+
+// CreateMariaDBRequest is the request body for creating a MariaDB database
+type CreateMariaDBRequest struct {
+	CreateDatabaseRequest
+	MariaDBRootPassword string `json:"mariadb_root_password,omitempty"`
+	MariaDBUser         string `json:"mariadb_user,omitempty"`
+	MariaDBPassword     string `json:"mariadb_password,omitempty"`
+	MariaDBDatabase     string `json:"mariadb_database,omitempty"`
+	MariaDBConf         string `json:"mariadb_conf,omitempty"`
+}
+
+// Engine implements DatabaseRequest
+func (r *CreateMariaDBRequest) Engine() string { return DatabaseMariaDB }
+
+// CreateMongoDBRequest is the request body for creating a MongoDB database
+type CreateMongoDBRequest struct {
+	CreateDatabaseRequest
+	MongoInitdbRootUsername string `json:"mongo_initdb_root_username,omitempty"`
+	MongoInitdbRootPassword string `json:"mongo_initdb_root_password,omitempty"`
+	MongoInitdbDatabase     string `json:"mongo_initdb_database,omitempty"`
+	MongoConf               string `json:"mongo_conf,omitempty"`
+}
+
+// Engine implements DatabaseRequest
+func (r *CreateMongoDBRequest) Engine() string { return DatabaseMongoDB }
+
+// CreateRedisRequest is the request body for creating a Redis database
+type CreateRedisRequest struct {
+	CreateDatabaseRequest
+	RedisPassword string `json:"redis_password,omitempty"`
+	RedisConf     string `json:"redis_conf,omitempty"`
+}
+
+// Engine implements DatabaseRequest
+func (r *CreateRedisRequest) Engine() string { return DatabaseRedis }
+
+// CreateKeyDBRequest is the request body for creating a KeyDB database
+type CreateKeyDBRequest struct {
+	CreateDatabaseRequest
+	KeyDBPassword string `json:"keydb_password,omitempty"`
+	KeyDBConf     string `json:"keydb_conf,omitempty"`
+}
+
+// Engine implements DatabaseRequest
+func (r *CreateKeyDBRequest) Engine() string { return DatabaseKeyDB }
+
+// CreateDragonflyRequest is the request body for creating a Dragonfly database
+type CreateDragonflyRequest struct {
+	CreateDatabaseRequest
+	DragonflyPassword string `json:"dragonfly_password,omitempty"`
+}
+
+// Engine implements DatabaseRequest
+func (r *CreateDragonflyRequest) Engine() string { return DatabaseDragonfly }
+
+// CreateClickHouseRequest is the request body for creating a ClickHouse database
+type CreateClickHouseRequest struct {
+	CreateDatabaseRequest
+	ClickHouseAdminUser     string `json:"clickhouse_admin_user,omitempty"`
+	ClickHouseAdminPassword string `json:"clickhouse_admin_password,omitempty"`
+}
+
+// Engine implements DatabaseRequest
+func (r *CreateClickHouseRequest) Engine() string { return DatabaseClickHouse }
+
+// NewDatabaseRequest returns an empty engine-specific request carrying the common fields
+func NewDatabaseRequest(engine string, common CreateDatabaseRequest) (DatabaseRequest, error) {
+	switch engine {
+	case DatabasePostgreSQL:
+		return &CreatePostgreSQLRequest{CreateDatabaseRequest: common}, nil
+	case DatabaseMySQL:
+		return &CreateMySQLRequest{CreateDatabaseRequest: common}, nil
+	case DatabaseMariaDB:
+		return &CreateMariaDBRequest{CreateDatabaseRequest: common}, nil
+	case DatabaseMongoDB:
+		return &CreateMongoDBRequest{CreateDatabaseRequest: common}, nil
+	case DatabaseRedis:
+		return &CreateRedisRequest{CreateDatabaseRequest: common}, nil
+	case DatabaseKeyDB:
+		return &CreateKeyDBRequest{CreateDatabaseRequest: common}, nil
+	case DatabaseDragonfly:
+		return &CreateDragonflyRequest{CreateDatabaseRequest: common}, nil
+	case DatabaseClickHouse:
+		return &CreateClickHouseRequest{CreateDatabaseRequest: common}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database engine: %s", engine)
+	}
 }
 
-// CreateServiceResponse is the response from creating a service
-type CreateServiceResponse struct {
-	UUID string `json:"uuid"`
+// UpdateDatabaseRequest is the request body for updating a database.
+// Nil fields are left unchanged.
+type UpdateDatabaseRequest struct {
+	Name         *string `json:"name,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	Image        *string `json:"image,omitempty"`
+	IsPublic     *bool   `json:"is_public,omitempty"`
+	PublicPort   *int    `json:"public_port,omitempty"`
+	LimitsMemory *string `json:"limits_memory,omitempty"`
+	LimitsCPUs   *string `json:"limits_cpus,omitempty"`
+}
+
+// CreateDatabaseResponse is the response from creating a database
+type CreateDatabaseResponse struct {
+	UUID          string `json:"uuid"`
+	InternalDBURL string `json:"internal_db_url,omitempty"`
+	ExternalDBURL string `json:"external_db_url,omitempty"`
 }
 
 // ListDatabases returns all databases
@@ -74,19 +220,26 @@ func (c *Client) GetDatabase(uuid string) (*Database, error) {
 	return &database, err
 }
 
-// CreateDatabase creates a new database
-func (c *Client) CreateDatabase(config map[string]interface{}) (map[string]interface{}, error) {
-	var result map[string]interface{}
-	err := c.Post("/databases", config, &result)
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
+// CreateDatabase creates a new database using the engine-specific endpoint
+func (c *Client) CreateDatabase(req DatabaseRequest) (*CreateDatabaseResponse, error) {
+	var resp CreateDatabaseResponse
+	err := c.Post("/databases/"+req.Engine(), req, &resp)
+	return &resp, err
 }
 
 // UpdateDatabase updates a database
-func (c *Client) UpdateDatabase(uuid string, updates map[string]interface{}) error {
-	return c.Patch("/databases/"+uuid, updates, nil)
+func (c *Client) UpdateDatabase(uuid string, req *UpdateDatabaseRequest) error {
+	return c.Patch("/databases/"+uuid, req, nil)
+}
+
+// SetDatabasePublic toggles public exposure of a database.
+// A port of 0 keeps the port currently configured in Coolify.
+func (c *Client) SetDatabasePublic(uuid string, public bool, port int) error {
+	req := &UpdateDatabaseRequest{IsPublic: &public}
+	if public && port > 0 {
+		req.PublicPort = &port
+	}
+	return c.UpdateDatabase(uuid, req)
 }
 
 // DeleteDatabase deletes a database
@@ -95,65 +248,31 @@ func (c *Client) DeleteDatabase(uuid string) error {
 }
 
 // StartDatabase starts a database
-func (c *Client) StartDatabase(uuid string) error {
-	return c.Post(fmt.Sprintf("/databases/%s/start", uuid), nil, nil)
+func (c *Client) StartDatabase(uuid string) (*MessageResponse, error) {
+	var resp MessageResponse
+	err := c.Get(fmt.Sprintf("/databases/%s/start", uuid), &resp)
+	return &resp, err
 }
 
 // StopDatabase stops a database
-func (c *Client) StopDatabase(uuid string) error {
-	return c.Post(fmt.Sprintf("/databases/%s/stop", uuid), nil, nil)
+func (c *Client) StopDatabase(uuid string) (*MessageResponse, error) {
+	var resp MessageResponse
+	err := c.Get(fmt.Sprintf("/databases/%s/stop", uuid), &resp)
+	return &resp, err
 }
 
 // RestartDatabase restarts a database
-func (c *Client) RestartDatabase(uuid string) error {
-	return c.Post(fmt.Sprintf("/databases/%s/restart", uuid), nil, nil)
-}
-
-// ListServices returns all services
-func (c *Client) ListServices() ([]Service, error) {
-	var services []Service
-	err := c.Get("/services", &services)
-	return services, err
-}
-
-// GetService returns a service by UUID
-func (c *Client) GetService(uuid string) (*Service, error) {
-	var service Service
-	err := c.Get("/services/"+uuid, &service)
-	return &service, err
+func (c *Client) RestartDatabase(uuid string) (*MessageResponse, error) {
+	var resp MessageResponse
+	err := c.Get(fmt.Sprintf("/databases/%s/restart", uuid), &resp)
+	return &resp, err
 }
 
-// CreateService creates a new service
-func (c *Client) CreateService(config map[string]interface{}) (map[string]interface{}, error) {
-	var result map[string]interface{}
-	err := c.Post("/services", config, &result)
-	if err != nil {
-		return nil, err
+// Engine returns the database engine (postgresql, mysql, ...) derived from the database type
+func (d *Database) Engine() string {
+	t := d.DatabaseType
+	if t == "" {
+		t = d.Type
 	}
-	return result, nil
-}
-
-// UpdateService updates a service
-func (c *Client) UpdateService(uuid string, updates map[string]interface{}) error {
-	return c.Patch("/services/"+uuid, updates, nil)
-}
-
-// DeleteService deletes a service
-func (c *Client) DeleteService(uuid string) error {
-	return c.Delete("/services/" + uuid)
-}
-
-// StartService starts a service
-func (c *Client) StartService(uuid string) error {
-	return c.Post(fmt.Sprintf("/services/%s/start", uuid), nil, nil)
-}
-
-// StopService stops a service
-func (c *Client) StopService(uuid string) error {
-	return c.Post(fmt.Sprintf("/services/%s/stop", uuid), nil, nil)
-}
-
-// RestartService restarts a service
-func (c *Client) RestartService(uuid string) error {
-	return c.Post(fmt.Sprintf("/services/%s/restart", uuid), nil, nil)
+	return strings.TrimPrefix(t, "standalone-")
 }
@@ -3,7 +3,9 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Deploy triggers a deployment for an application
@@ -62,10 +64,66 @@ type Deployment struct {
 	RollbackToUUID   string      `json:"rollback_to"`
 	CurrentProcessID string      `json:"current_process_id"`
 	DestinationID    interface{} `json:"destination_id"`
+	ServerID         interface{} `json:"server_id,omitempty"`
+	ServerName       string      `json:"server_name,omitempty"`
+	ApplicationName  string      `json:"application_name,omitempty"`
 	CreatedAt        string      `json:"created_at"`
 	UpdatedAt        string      `json:"updated_at"`
 }
 
+// PullRequest returns the pull request number of a preview deployment, or 0
+func (d *Deployment) PullRequest() int {
+	switch v := d.PullRequestID.(type) {
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+// CommitSHA returns the deployed commit, or "" if unknown
+func (d *Deployment) CommitSHA() string {
+	if d.GitCommitSha != "" {
+		return d.GitCommitSha
+	}
+	if d.Commit == "HEAD" {
+		return ""
+	}
+	return d.Commit
+}
+
+// Created returns when the deployment was queued, or the zero time if unknown
+func (d *Deployment) Created() time.Time {
+	return ParseTimestamp(d.CreatedAt)
+}
+
+// Duration returns how long a completed deployment took, or 0 while it is running
+func (d *Deployment) Duration() time.Duration {
+	switch strings.ToLower(d.Status) {
+	case "finished", "failed", "cancelled", "cancelled-by-user":
+	default:
+		return 0
+	}
+	start, end := d.Created(), ParseTimestamp(d.UpdatedAt)
+	if start.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// ParseTimestamp parses the timestamp formats returned by Coolify, returning
+// the zero time if s is empty or unrecognised
+func ParseTimestamp(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 // DeploymentLogsResponse represents the response from the deployment logs endpoint
 type DeploymentLogsResponse struct {
 	Logs string `json:"logs"`
@@ -78,6 +136,13 @@ func (c *Client) ListDeployments(appUUID string) ([]Deployment, error) {
 	return deployments, err
 }
 
+// ListRunningDeployments returns the queued and in-progress deployments of every application
+func (c *Client) ListRunningDeployments() ([]Deployment, error) {
+	var deployments []Deployment
+	err := c.Get("/deployments", &deployments)
+	return deployments, err
+}
+
 // DeploymentDetail contains full deployment info including logs
 // Note: Coolify API returns some IDs as strings, so we use json.Number/interface{} for flexibility
 type DeploymentDetail struct {
@@ -115,17 +180,39 @@ type LogEntry struct {
 
 // ParseLogs extracts readable log output from Coolify's JSON log format
 func ParseLogs(rawLogs string) string {
-	if rawLogs == "" {
-		return ""
+	entries := ParseLogEntries(rawLogs)
+	if entries == nil {
+		// Not JSON, return raw logs
+		return rawLogs
 	}
+	return formatLogEntries(entries, false)
+}
 
-	// Try to parse as JSON array
-	var entries []LogEntry
+// ParseLogsWithTimestamps is ParseLogs with each entry's output prefixed by
+// its timestamp, so the log viewer can jump to a time
+func ParseLogsWithTimestamps(rawLogs string) string {
+	entries := ParseLogEntries(rawLogs)
+	if entries == nil {
+		return rawLogs
+	}
+	return formatLogEntries(entries, true)
+}
+
+// ParseLogEntries parses the entries of Coolify's JSON log format. It returns
+// nil if rawLogs isn't in that format.
+func ParseLogEntries(rawLogs string) []LogEntry {
+	if rawLogs == "" {
+		return nil
+	}
 
 	// The logs might be multiple JSON arrays concatenated, so we need to handle that
 	// First, try parsing as a single array
+	var entries []LogEntry
 	if err := json.Unmarshal([]byte(rawLogs), &entries); err == nil {
-		return formatLogEntries(entries)
+		if entries == nil {
+			entries = []LogEntry{}
+		}
+		return entries
 	}
 
 	// If that fails, try to find and parse JSON arrays within the string
@@ -168,21 +255,23 @@ func ParseLogs(rawLogs string) string {
 	}
 
 	if len(allEntries) > 0 {
-		return formatLogEntries(allEntries)
+		return allEntries
 	}
-
-	// If nothing worked, return raw logs
-	return rawLogs
+	return nil
 }
 
-func formatLogEntries(entries []LogEntry) string {
+func formatLogEntries(entries []LogEntry, timestamps bool) string {
 	var lines []string
 	for _, e := range entries {
 		// Skip hidden entries and empty output
 		if e.Hidden || e.Output == "" {
 			continue
 		}
-		lines = append(lines, e.Output)
+		if timestamps && e.Timestamp != "" {
+			lines = append(lines, e.Timestamp+" "+e.Output)
+		} else {
+			lines = append(lines, e.Output)
+		}
 	}
 	return strings.Join(lines, "\n")
 }
@@ -0,0 +1,50 @@
+package api
+
+// CreateDestinationRequest is the request body for creating a Docker network destination
+type CreateDestinationRequest struct {
+	Name       string `json:"name"`
+	ServerUUID string `json:"server_uuid"`
+	Network    string `json:"network,omitempty"`
+}
+
+// ListDestinations returns all destinations
+func (c *Client) ListDestinations() ([]Destination, error) {
+	var destinations []Destination
+	err := c.Get("/destinations", &destinations)
+	return destinations, err
+}
+
+// ListServerDestinations returns the destinations that belong to a server
+func (c *Client) ListServerDestinations(serverUUID string) ([]Destination, error) {
+	destinations, err := c.ListDestinations()
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []Destination
+	for _, d := range destinations {
+		if d.ServerUUID == serverUUID {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered, nil
+}
+
+// GetDestination returns a destination by UUID
+func (c *Client) GetDestination(uuid string) (*Destination, error) {
+	var destination Destination
+	err := c.Get("/destinations/"+uuid, &destination)
+	return &destination, err
+}
+
+// CreateDestination creates a Docker network destination on a server
+func (c *Client) CreateDestination(req *CreateDestinationRequest) (*CreateResponse, error) {
+	var resp CreateResponse
+	err := c.Post("/destinations", req, &resp)
+	return &resp, err
+}
+
+// DeleteDestination deletes a destination
+func (c *Client) DeleteDestination(uuid string) error {
+	return c.Delete("/destinations/" + uuid)
+}
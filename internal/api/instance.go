@@ -0,0 +1,63 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InstanceInfo is what Verify finds out about a Coolify instance
+type InstanceInfo struct {
+	Version string
+	Team    string
+}
+
+// Health checks that the Coolify API answers. It doesn't need a token.
+func (c *Client) Health() error {
+	var text plainText
+	return c.Get("/health", &text)
+}
+
+// Version returns the version of Coolify, like 4.0.0-beta.420
+func (c *Client) Version() (string, error) {
+	var text plainText
+	if err := c.Get("/version", &text); err != nil {
+		return "", err
+	}
+	return strings.Trim(string(text), "\" \n"), nil
+}
+
+// CurrentTeam returns the team the API token belongs to
+func (c *Client) CurrentTeam() (*Team, error) {
+	var team Team
+	if err := c.Get("/teams/current", &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// Verify checks that the instance is reachable and accepts the token, so a
+// mistyped URL or token is reported before it's saved
+func (c *Client) Verify() (*InstanceInfo, error) {
+	host := strings.TrimSuffix(c.BaseURL.String(), "/api/v1")
+	if err := c.Health(); err != nil {
+		if IsNotFound(err) {
+			return nil, fmt.Errorf("%s doesn't look like a Coolify instance: its API has no health endpoint", host)
+		}
+		return nil, fmt.Errorf("can't reach Coolify at %s: %w", host, err)
+	}
+
+	team, err := c.CurrentTeam()
+	if err != nil {
+		switch {
+		case IsUnauthorized(err):
+			return nil, fmt.Errorf("%s rejected the API token: check it under Keys & Tokens in Coolify", host)
+		case IsForbidden(err):
+			return nil, fmt.Errorf("the API token can't read its team: give it the read permission in Coolify")
+		}
+		return nil, fmt.Errorf("failed to check the API token: %w", err)
+	}
+
+	// Only informational, so an instance that doesn't report it is fine
+	version, _ := c.Version()
+	return &InstanceInfo{Version: version, Team: team.Name}, nil
+}
@@ -0,0 +1,69 @@
+package api
+
+import "fmt"
+
+// ScheduledTask is a cron job Coolify runs inside an application's container
+type ScheduledTask struct {
+	ID        int    `json:"id"`
+	UUID      string `json:"uuid"`
+	Name      string `json:"name"`
+	Command   string `json:"command"`
+	Frequency string `json:"frequency"`
+	Container string `json:"container,omitempty"`
+	Timeout   int    `json:"timeout,omitempty"`
+	Enabled   bool   `json:"enabled"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// ScheduledTaskExecution is a single run of a scheduled task
+type ScheduledTaskExecution struct {
+	ID         int    `json:"id"`
+	UUID       string `json:"uuid"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	FinishedAt string `json:"finished_at,omitempty"`
+}
+
+// ScheduledTaskRequest is the request body for creating or updating a
+// scheduled task. Empty and nil fields are left unchanged on update.
+type ScheduledTaskRequest struct {
+	Name      string `json:"name,omitempty"`
+	Command   string `json:"command,omitempty"`
+	Frequency string `json:"frequency,omitempty"`
+	Container string `json:"container,omitempty"`
+	Timeout   *int   `json:"timeout,omitempty"`
+	Enabled   *bool  `json:"enabled,omitempty"`
+}
+
+// ListScheduledTasks returns the scheduled tasks of an application
+func (c *Client) ListScheduledTasks(appUUID string) ([]ScheduledTask, error) {
+	var tasks []ScheduledTask
+	err := c.Get(fmt.Sprintf("/applications/%s/scheduled-tasks", appUUID), &tasks)
+	return tasks, err
+}
+
+// CreateScheduledTask creates a scheduled task for an application
+func (c *Client) CreateScheduledTask(appUUID string, req *ScheduledTaskRequest) (*ScheduledTask, error) {
+	var task ScheduledTask
+	err := c.Post(fmt.Sprintf("/applications/%s/scheduled-tasks", appUUID), req, &task)
+	return &task, err
+}
+
+// UpdateScheduledTask updates a scheduled task
+func (c *Client) UpdateScheduledTask(appUUID, taskUUID string, req *ScheduledTaskRequest) error {
+	return c.Patch(fmt.Sprintf("/applications/%s/scheduled-tasks/%s", appUUID, taskUUID), req, nil)
+}
+
+// DeleteScheduledTask deletes a scheduled task
+func (c *Client) DeleteScheduledTask(appUUID, taskUUID string) error {
+	return c.Delete(fmt.Sprintf("/applications/%s/scheduled-tasks/%s", appUUID, taskUUID))
+}
+
+// ListScheduledTaskExecutions returns the runs of a scheduled task, newest first
+func (c *Client) ListScheduledTaskExecutions(appUUID, taskUUID string) ([]ScheduledTaskExecution, error) {
+	var executions []ScheduledTaskExecution
+	err := c.Get(fmt.Sprintf("/applications/%s/scheduled-tasks/%s/executions", appUUID, taskUUID), &executions)
+	return executions, err
+}
@@ -1,5 +1,11 @@
 package api
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 // ListServers returns all servers
 func (c *Client) ListServers() ([]Server, error) {
 	var servers []Server
@@ -13,3 +19,81 @@ func (c *Client) GetServer(uuid string) (*Server, error) {
 	err := c.Get("/servers/"+uuid, &server)
 	return &server, err
 }
+
+// CreateServerRequest is the request body for registering a server
+type CreateServerRequest struct {
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	IP              string `json:"ip"`
+	Port            int    `json:"port"`
+	User            string `json:"user"`
+	PrivateKeyUUID  string `json:"private_key_uuid"`
+	IsBuildServer   bool   `json:"is_build_server,omitempty"`
+	InstantValidate bool   `json:"instant_validate,omitempty"`
+}
+
+// CreateServer registers a new server
+func (c *Client) CreateServer(req *CreateServerRequest) (*CreateResponse, error) {
+	var resp CreateResponse
+	err := c.Post("/servers", req, &resp)
+	return &resp, err
+}
+
+// ValidateServer starts validation of a server's connection and Docker setup
+func (c *Client) ValidateServer(uuid string) (*MessageResponse, error) {
+	var resp MessageResponse
+	err := c.Get("/servers/"+uuid+"/validate", &resp)
+	return &resp, err
+}
+
+// DeleteServer removes a server
+func (c *Client) DeleteServer(uuid string) error {
+	return c.Delete("/servers/" + uuid)
+}
+
+// IsReady reports whether Coolify considers the server reachable and usable
+func (s *Server) IsReady() bool {
+	return s.Settings != nil && s.Settings.IsReachable && s.Settings.IsUsable
+}
+
+// Proxy types supported by Coolify
+const (
+	ProxyTraefik = "traefik"
+	ProxyCaddy   = "caddy"
+	ProxyNone    = "none"
+)
+
+// ServerProxy is the decoded form of Server.Proxy
+type ServerProxy struct {
+	Type            string `json:"type,omitempty"`
+	Status          string `json:"status,omitempty"`
+	ForceStop       bool   `json:"force_stop,omitempty"`
+	RedirectEnabled bool   `json:"redirect_enabled,omitempty"`
+	RedirectURL     string `json:"redirect_url,omitempty"`
+}
+
+// ProxyConfig decodes the server's proxy settings. The proxy type falls back to ProxyType.
+func (s *Server) ProxyConfig() (*ServerProxy, error) {
+	proxy := &ServerProxy{}
+	if len(s.Proxy) > 0 && string(s.Proxy) != "null" {
+		if err := json.Unmarshal(s.Proxy, proxy); err != nil {
+			return nil, fmt.Errorf("failed to parse proxy settings: %w", err)
+		}
+	}
+	if proxy.Type == "" {
+		proxy.Type = s.ProxyType
+	}
+	proxy.Type = strings.ToLower(proxy.Type)
+	return proxy, nil
+}
+
+// SetServerProxyType changes the proxy used by a server (traefik, caddy or none)
+func (c *Client) SetServerProxyType(uuid, proxyType string) error {
+	switch proxyType {
+	case ProxyTraefik, ProxyCaddy, ProxyNone:
+	default:
+		return fmt.Errorf("unsupported proxy type: %s (use traefik, caddy or none)", proxyType)
+	}
+	req := map[string]string{"proxy_type": proxyType}
+	return c.Patch("/servers/"+uuid, req, nil)
+}
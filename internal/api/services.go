@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ServiceTemplatesURL is the public catalog of Coolify one-click service templates
+const ServiceTemplatesURL = "https://cdn.coollabs.io/coolify/service-templates.json"
+
+// Service represents a Coolify one-click service (Plausible, Umami, n8n, ...)
+type Service struct {
+	ID           int                  `json:"id"`
+	UUID         string               `json:"uuid"`
+	Name         string               `json:"name"`
+	Description  string               `json:"description"`
+	Type         string               `json:"service_type"`
+	Status       string               `json:"status"`
+	ServerStatus bool                 `json:"server_status"`
+	Applications []ServiceApplication `json:"applications,omitempty"`
+	Databases    []ServiceDatabase    `json:"databases,omitempty"`
+	CreatedAt    string               `json:"created_at"`
+	UpdatedAt    string               `json:"updated_at"`
+}
+
+// ServiceApplication is a web-facing container that belongs to a service
+type ServiceApplication struct {
+	UUID   string  `json:"uuid"`
+	Name   string  `json:"name"`
+	Fqdn   *string `json:"fqdn"`
+	Image  string  `json:"image"`
+	Status string  `json:"status"`
+}
+
+// ServiceDatabase is a database container that belongs to a service
+type ServiceDatabase struct {
+	UUID   string `json:"uuid"`
+	Name   string `json:"name"`
+	Image  string `json:"image"`
+	Status string `json:"status"`
+}
+
+// ServiceTemplate describes an entry of the one-click service catalog
+type ServiceTemplate struct {
+	Name          string   `json:"name"`
+	Slogan        string   `json:"slogan"`
+	Documentation string   `json:"documentation"`
+	Tags          []string `json:"tags"`
+	Logo          string   `json:"logo"`
+	MinVersion    string   `json:"minversion"`
+	Port          string   `json:"port"`
+}
+
+// CreateServiceRequest is the request body for creating a one-click service
+type CreateServiceRequest struct {
+	Type             string `json:"type"`
+	Name             string `json:"name,omitempty"`
+	Description      string `json:"description,omitempty"`
+	ProjectUUID      string `json:"project_uuid"`
+	ServerUUID       string `json:"server_uuid"`
+	EnvironmentName  string `json:"environment_name,omitempty"`
+	EnvironmentUUID  string `json:"environment_uuid,omitempty"`
+	DestinationUUID  string `json:"destination_uuid,omitempty"`
+	InstantDeploy    bool   `json:"instant_deploy,omitempty"`
+	DockerComposeRaw string `json:"docker_compose_raw,omitempty"`
+}
+
+// CreateServiceResponse is the response from creating a service
+type CreateServiceResponse struct {
+	UUID    string   `json:"uuid"`
+	Domains []string `json:"domains"`
+}
+
+// GetDomains returns the FQDNs of all web-facing containers in the service
+func (s *Service) GetDomains() []string {
+	var domains []string
+	for _, app := range s.Applications {
+		if app.Fqdn == nil || *app.Fqdn == "" {
+			continue
+		}
+		for _, d := range strings.Split(*app.Fqdn, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				domains = append(domains, d)
+			}
+		}
+	}
+	return domains
+}
+
+// ListServiceTemplates returns the one-click service catalog sorted by name
+func (c *Client) ListServiceTemplates() ([]ServiceTemplate, error) {
+	resp, err := c.httpClient.Get(ServiceTemplatesURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch service templates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch service templates: status %d", resp.StatusCode)
+	}
+
+	var catalog map[string]ServiceTemplate
+	if err := json.NewDecoder(resp.Body).Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse service templates: %w", err)
+	}
+
+	templates := make([]ServiceTemplate, 0, len(catalog))
+	for name, tmpl := range catalog {
+		tmpl.Name = name
+		templates = append(templates, tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// ListServices returns all services
+func (c *Client) ListServices() ([]Service, error) {
+	var services []Service
+	err := c.Get("/services", &services)
+	return services, err
+}
+
+// GetService returns a service by UUID
+func (c *Client) GetService(uuid string) (*Service, error) {
+	var service Service
+	err := c.Get("/services/"+uuid, &service)
+	return &service, err
+}
+
+// CreateService creates a new service from a one-click template
+func (c *Client) CreateService(req *CreateServiceRequest) (*CreateServiceResponse, error) {
+	var resp CreateServiceResponse
+	err := c.Post("/services", req, &resp)
+	return &resp, err
+}
+
+// UpdateService updates a service
+func (c *Client) UpdateService(uuid string, updates map[string]interface{}) error {
+	return c.Patch("/services/"+uuid, updates, nil)
+}
+
+// DeleteService deletes a service
+func (c *Client) DeleteService(uuid string) error {
+	return c.Delete("/services/" + uuid)
+}
+
+// StartService starts a service
+func (c *Client) StartService(uuid string) (*MessageResponse, error) {
+	var resp MessageResponse
+	err := c.Get(fmt.Sprintf("/services/%s/start", uuid), &resp)
+	return &resp, err
+}
+
+// StopService stops a service
+func (c *Client) StopService(uuid string) (*MessageResponse, error) {
+	var resp MessageResponse
+	err := c.Get(fmt.Sprintf("/services/%s/stop", uuid), &resp)
+	return &resp, err
+}
+
+// RestartService restarts a service
+func (c *Client) RestartService(uuid string) (*MessageResponse, error) {
+	var resp MessageResponse
+	err := c.Get(fmt.Sprintf("/services/%s/restart", uuid), &resp)
+	return &resp, err
+}
+
+// ListServiceEnvs returns environment variables for a service
+func (c *Client) ListServiceEnvs(uuid string) ([]EnvironmentVariable, error) {
+	var envs []EnvironmentVariable
+	err := c.Get(fmt.Sprintf("/services/%s/envs", uuid), &envs)
+	return envs, err
+}
+
+// UpdateServiceEnvsBulk creates or updates multiple environment variables for a service
+func (c *Client) UpdateServiceEnvsBulk(uuid string, envs []EnvironmentVariable) error {
+	req := map[string][]EnvironmentVariable{"data": envs}
+	return c.Patch(fmt.Sprintf("/services/%s/envs/bulk", uuid), req, nil)
+}
@@ -196,6 +196,7 @@ type CreatePublicAppRequest struct {
 	ServerUUID       string `json:"server_uuid"`
 	EnvironmentName  string `json:"environment_name,omitempty"`
 	EnvironmentUUID  string `json:"environment_uuid,omitempty"`
+	DestinationUUID  string `json:"destination_uuid,omitempty"`
 	GitRepository    string `json:"git_repository"`
 	GitBranch        string `json:"git_branch"`
 	BuildPack        string `json:"build_pack,omitempty"`
@@ -217,6 +218,7 @@ type CreateDockerImageAppRequest struct {
 	ServerUUID              string `json:"server_uuid"`
 	EnvironmentName         string `json:"environment_name,omitempty"`
 	EnvironmentUUID         string `json:"environment_uuid,omitempty"`
+	DestinationUUID         string `json:"destination_uuid,omitempty"`
 	Name                    string `json:"name,omitempty"`
 	Description             string `json:"description,omitempty"`
 	Domains                 string `json:"domains,omitempty"`
@@ -275,13 +277,17 @@ type GitHubApp struct {
 	IsSystemWide   bool   `json:"is_system_wide"`
 }
 
-// CreatePrivateGitHubAppRequest is the request body for creating a private GitHub app
+// CreatePrivateGitHubAppRequest is the request body for creating an
+// application from a private repository, through a GitHub App or, with
+// PrivateKeyUUID, a deploy key
 type CreatePrivateGitHubAppRequest struct {
 	ProjectUUID        string `json:"project_uuid"`
 	ServerUUID         string `json:"server_uuid"`
 	EnvironmentName    string `json:"environment_name,omitempty"`
 	EnvironmentUUID    string `json:"environment_uuid,omitempty"`
-	GitHubAppUUID      string `json:"github_app_uuid"`
+	DestinationUUID    string `json:"destination_uuid,omitempty"`
+	GitHubAppUUID      string `json:"github_app_uuid,omitempty"`
+	PrivateKeyUUID     string `json:"private_key_uuid,omitempty"`
 	GitRepository      string `json:"git_repository"`
 	GitBranch          string `json:"git_branch"`
 	BuildPack          string `json:"build_pack,omitempty"`
@@ -296,12 +302,23 @@ type CreatePrivateGitHubAppRequest struct {
 	PortsExposes       string `json:"ports_exposes,omitempty"`
 	PublishDirectory   string `json:"publish_directory,omitempty"`
 	BaseDirectory      string `json:"base_directory,omitempty"`
+	WatchPaths         string `json:"watch_paths,omitempty"`
 	HealthCheckEnabled bool   `json:"health_check_enabled,omitempty"`
 	HealthCheckPath    string `json:"health_check_path,omitempty"`
+
+	// Docker compose applications only
+	DockerComposeLocation string          `json:"docker_compose_location,omitempty"`
+	DockerComposeDomains  []ComposeDomain `json:"docker_compose_domains,omitempty"`
+}
+
+// ComposeDomain is the domain of a service of a docker compose application
+type ComposeDomain struct {
+	Name   string `json:"name"`
+	Domain string `json:"domain"`
 }
 
 // NOTE: Database, Service, and Deployment types are in their respective files
-// (databases.go, deployments.go) to avoid duplication
+// (databases.go, services.go, deployments.go) to avoid duplication
 
 // EnvironmentVariable represents a Coolify environment variable (from CAGC)
 type EnvironmentVariable struct {
@@ -313,6 +330,8 @@ type EnvironmentVariable struct {
 	IsLiteral   bool   `json:"is_literal,omitempty"`
 	IsMultiline bool   `json:"is_multiline,omitempty"`
 	IsShownOnce bool   `json:"is_shown_once,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
 }
 
 // CreateResponse is a generic response for create operations (from CAGC)
@@ -349,17 +368,18 @@ type PrivateKey struct {
 	UUID         string `json:"uuid,omitempty"`
 	Name         string `json:"name,omitempty"`
 	Description  string `json:"description,omitempty"`
-	PrivateKey   string `json:"private_key,omitempty"`
+	PrivateKey   string `json:"private_key,omitempty" sensitive:"true"`
 	IsGitRelated bool   `json:"is_git_related,omitempty"`
 	TeamID       int    `json:"team_id,omitempty"`
 	CreatedAt    string `json:"created_at,omitempty"`
 	UpdatedAt    string `json:"updated_at,omitempty"`
-	PublicKey    string `json:"public_key,omitempty"`
+	PublicKey    string `json:"public_key,omitempty" table:"-"`
 	Fingerprint  string `json:"fingerprint,omitempty"`
 }
 
 // Destination represents a Coolify destination (from CAGC)
 type Destination struct {
+	ID            int    `json:"id,omitempty"`
 	UUID          string `json:"uuid,omitempty"`
 	Name          string `json:"name,omitempty"`
 	Description   string `json:"description,omitempty"`
@@ -0,0 +1,428 @@
+package appdeploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/detect"
+	"github.com/entro314-labs/cool-kit/internal/docker"
+	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// DeployBlueGreen deploys a production release to a new "green" application
+// next to the live "blue" one. Once green is deployed and passes the smoke
+// tests, it takes over blue's domains and blue is retired. Blue keeps serving
+// until green is ready, so a failed build or check leaves production as it was.
+func DeployBlueGreen(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, allowSecrets, verbose bool, watch WatchOptions) error {
+	if projectCfg.AppUUID == "" {
+		return fmt.Errorf("blue/green deployments need an existing application")
+	}
+	if !config.BuildsImage(projectCfg.DeployMethod) {
+		if projectCfg.GitHubAppUUID == "" && projectCfg.PrivateKeyUUID == "" {
+			return fmt.Errorf("blue/green deployments of Git projects need a GitHub App or deploy key: deploy once without a strategy")
+		}
+		if err := git.Check(); err != nil {
+			return err
+		}
+	}
+
+	blue, err := client.GetApplication(projectCfg.AppUUID)
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+	domains := blue.GetFqdn()
+	if domains == "" {
+		return fmt.Errorf("application %s has no domains to move to a new version", blue.Name)
+	}
+
+	bg := projectCfg.BlueGreen
+	if bg == nil {
+		bg = &config.BlueGreenConfig{}
+	}
+	greenName := greenAppName(blue.Name)
+
+	ui.Spacer()
+	ui.Divider()
+	ui.Bold("Blue/Green Deployment")
+	ui.Spacer()
+	ui.KeyValue("Live", blue.Name)
+	ui.KeyValue("New", greenName)
+
+	var tasks []ui.Task
+	var tag string
+	if config.BuildsImage(projectCfg.DeployMethod) {
+		tag = docker.GenerateTag("production")
+		ui.KeyValue("Tag", tag)
+		ui.Spacer()
+		restore, err := useBuildServer(client, projectCfg)
+		if err != nil {
+			return err
+		}
+		defer restore()
+		if err := buildDockerImage(globalCfg, projectCfg, projectCfg.SourceDir(), tag, verbose); err != nil {
+			return err
+		}
+		ui.Spacer()
+		tasks = append(tasks, pushImageTask(globalCfg, projectCfg, tag, verbose))
+	} else if usesDeployKey(projectCfg) {
+		// The first deploy pointed origin at the repository
+		tasks = append(tasks, ui.Task{
+			Name:         "push-code",
+			ActiveName:   "Pushing code...",
+			CompleteName: "✓ Pushed code",
+			Action: func() error {
+				return commitAndPush(globalCfg, projectCfg, allowSecrets, verbose)
+			},
+		})
+	} else {
+		ghClient := git.NewGitHubClient(globalCfg.GitHubToken)
+		user, err := getGitHubUser(ghClient, verbose)
+		if err != nil {
+			return err
+		}
+		tasks = append(tasks, pushCodeTask(ghClient, globalCfg, projectCfg, user.Login, allowSecrets, verbose))
+	}
+
+	var greenUUID string
+	var skipped []string
+	tasks = append(tasks,
+		ui.Task{
+			Name:         "create-green",
+			ActiveName:   fmt.Sprintf("Creating %s...", greenName),
+			CompleteName: fmt.Sprintf("✓ Created %s", greenName),
+			Action: func() error {
+				var err error
+				greenUUID, err = createGreenApp(client, projectCfg, blue, greenName, tag, bg.StagingDomain)
+				return err
+			},
+		},
+		ui.Task{
+			Name:         "copy-settings",
+			ActiveName:   fmt.Sprintf("Copying settings and variables from %s...", blue.Name),
+			CompleteName: fmt.Sprintf("✓ Copied settings and variables from %s", blue.Name),
+			Action: func() error {
+				var err error
+				skipped, err = CopyApplication(client, blue, greenUUID, true)
+				return err
+			},
+		},
+		ui.Task{
+			Name:         "trigger-deploy",
+			ActiveName:   "Triggering deployment...",
+			CompleteName: "✓ Triggered deployment",
+			Action: func() error {
+				if _, err := client.Deploy(greenUUID, false, 0); err != nil {
+					return fmt.Errorf("failed to trigger deployment: %w", err)
+				}
+				return nil
+			},
+		},
+	)
+
+	if err := ui.RunTasksVerbose(tasks, verbose); err != nil {
+		ui.Error("Deployment setup failed")
+		discardGreen(client, greenUUID, greenName)
+		return err
+	}
+
+	if len(skipped) > 0 {
+		ui.Warning(fmt.Sprintf("Not copied (values are hidden): %s", strings.Join(skipped, ", ")))
+	}
+
+	// The swap needs the new version running, so NoWait doesn't apply
+	ui.Info("Watching deployment...")
+	if err := Watch(client, greenUUID, WatchOptions{Timeout: watch.Timeout, Transcript: watch.Transcript}); err != nil {
+		ui.Error("Deployment failed")
+		ui.Dim(fmt.Sprintf("%s is still live", blue.Name))
+		discardGreen(client, greenUUID, greenName)
+		return err
+	}
+	ui.Success("Deployment complete")
+
+	var stagingURL string
+	if green, err := client.GetApplication(greenUUID); err == nil {
+		stagingURL = green.PrimaryURL()
+	}
+	if stagingURL != "" {
+		ui.Spacer()
+		ui.KeyValue("Staging URL", ui.InfoStyle.Render(stagingURL))
+	}
+
+	// Green runs the migrations, so blue keeps serving until the schema is ready
+	if err := runMigrations(client, greenUUID, projectCfg, nil, 0, WatchOptions{SkipMigrations: watch.SkipMigrations}); err != nil {
+		ui.Dim(fmt.Sprintf("%s is still live", blue.Name))
+		discardGreen(client, greenUUID, greenName)
+		return err
+	}
+
+	if st := projectCfg.SmokeTests; st != nil && len(st.Checks) > 0 {
+		if err := checkSmokeTests(st, stagingURL); err != nil {
+			ui.Dim(fmt.Sprintf("%s is still live", blue.Name))
+			discardGreen(client, greenUUID, greenName)
+			return err
+		}
+	}
+
+	ui.Spacer()
+	ui.Divider()
+	ui.Bold("Switch Traffic")
+	ui.Spacer()
+
+	err = ui.RunTasksVerbose([]ui.Task{
+		{
+			Name:         "move-domains",
+			ActiveName:   fmt.Sprintf("Moving domains to %s...", greenName),
+			CompleteName: fmt.Sprintf("✓ Moved domains to %s", greenName),
+			Action: func() error {
+				// Blue keeps its domains until it's stopped, so both serve them
+				// for a moment instead of neither
+				if err := client.UpdateApplication(greenUUID, map[string]interface{}{
+					"domains":               domains,
+					"force_domain_override": true,
+				}); err != nil {
+					return fmt.Errorf("failed to set domains: %w", err)
+				}
+				// Restarting regenerates the proxy labels without a rebuild
+				if _, err := client.RestartApplication(context.Background(), greenUUID); err != nil {
+					return fmt.Errorf("failed to restart application: %w", err)
+				}
+				return nil
+			},
+		},
+	}, verbose)
+	if err == nil {
+		err = Watch(client, greenUUID, WatchOptions{Timeout: watch.Timeout, Transcript: watch.Transcript})
+	}
+	if err != nil {
+		ui.Error("Failed to switch traffic")
+		ui.Dim(fmt.Sprintf("%s is still live", blue.Name))
+		discardGreen(client, greenUUID, greenName)
+		return err
+	}
+
+	projectCfg.AppUUID = greenUUID
+	if tag != "" {
+		projectCfg.RecordImageTag(tag)
+	}
+	if err := config.SaveProject(projectCfg); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to save the new application UUID %s: %v", greenUUID, err))
+	}
+
+	retireName, retireDone := "Deleting", "Deleted"
+	if bg.KeepPrevious {
+		retireName, retireDone = "Stopping", "Stopped"
+	}
+	err = ui.RunTasksVerbose([]ui.Task{
+		{
+			Name:         "retire-blue",
+			ActiveName:   fmt.Sprintf("%s %s...", retireName, blue.Name),
+			CompleteName: fmt.Sprintf("✓ %s %s", retireDone, blue.Name),
+			Action: func() error {
+				return retireApplication(client, blue.UUID, bg.KeepPrevious)
+			},
+		},
+	}, verbose)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to retire %s: %v", blue.Name, err))
+		ui.Dim("Stop or delete it in the Coolify dashboard")
+	}
+
+	ui.Spacer()
+	ui.Success(fmt.Sprintf("%s is live", greenName))
+	ui.KeyValue("URL", ui.InfoStyle.Render(domains))
+	return nil
+}
+
+// greenAppName alternates a "-blue"/"-green" suffix so the new application
+// can be told apart from the one it replaces
+func greenAppName(name string) string {
+	if base, ok := strings.CutSuffix(name, "-green"); ok {
+		return base + "-blue"
+	}
+	return strings.TrimSuffix(name, "-blue") + "-green"
+}
+
+// createGreenApp creates the application a blue/green deployment deploys to,
+// from the same image or repository as blue
+func createGreenApp(client *api.Client, projectCfg *config.ProjectConfig, blue *api.Application, name, tag, domain string) (string, error) {
+	port := blue.PortsExposes
+	if port == "" {
+		port = config.DefaultPort
+	}
+
+	var uuid string
+	if config.BuildsImage(projectCfg.DeployMethod) {
+		resp, err := client.CreateDockerImageApp(&api.CreateDockerImageAppRequest{
+			ProjectUUID:             projectCfg.ProjectUUID,
+			ServerUUID:              projectCfg.ServerUUID,
+			EnvironmentUUID:         projectCfg.EnvironmentUUID,
+			DestinationUUID:         projectCfg.DestinationUUID,
+			Name:                    name,
+			Domains:                 domain,
+			DockerRegistryImageName: projectCfg.DockerImage,
+			DockerRegistryImageTag:  tag,
+			PortsExposes:            port,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create Coolify application %q: %w", name, err)
+		}
+		uuid = resp.UUID
+	} else {
+		watchPaths := ""
+		if blue.WatchPaths != nil {
+			watchPaths = *blue.WatchPaths
+		}
+		resp, err := createGitApp(client, projectCfg, &api.CreatePrivateGitHubAppRequest{
+			ProjectUUID:      projectCfg.ProjectUUID,
+			ServerUUID:       projectCfg.ServerUUID,
+			EnvironmentUUID:  projectCfg.EnvironmentUUID,
+			DestinationUUID:  projectCfg.DestinationUUID,
+			GitRepository:    blue.GitRepository,
+			GitBranch:        blue.GitBranch,
+			Name:             name,
+			BuildPack:        blue.BuildPack,
+			IsStatic:         projectCfg.BuildPack == detect.BuildPackStatic,
+			Domains:          domain,
+			InstallCommand:   blue.InstallCommand,
+			BuildCommand:     blue.BuildCommand,
+			StartCommand:     blue.StartCommand,
+			PublishDirectory: blue.PublishDirectory,
+			BaseDirectory:    blue.BaseDirectory,
+			WatchPaths:       watchPaths,
+			PortsExposes:     port,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create Coolify application %q: %w", name, err)
+		}
+		uuid = resp.UUID
+	}
+	return uuid, nil
+}
+
+// CopyApplication copies an application's runtime settings and environment
+// variables onto another application. Variables flagged shown-once are left
+// out unless includeShownOnce is set, and also when their value isn't
+// readable; their keys are returned.
+func CopyApplication(client *api.Client, from *api.Application, toUUID string, includeShownOnce bool) ([]string, error) {
+	if err := client.UpdateApplication(toUUID, applicationSettings(from)); err != nil {
+		return nil, fmt.Errorf("failed to copy settings: %w", err)
+	}
+
+	ctx := context.Background()
+	envs, err := client.ListApplicationEnvs(ctx, from.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environment variables: %w", err)
+	}
+
+	var skipped []string
+	copied := make([]api.EnvironmentVariable, 0, len(envs))
+	for _, e := range envs {
+		if e.IsShownOnce && (!includeShownOnce || e.Value == "") {
+			skipped = append(skipped, e.Key)
+			continue
+		}
+		copied = append(copied, api.EnvironmentVariable{
+			Key:         e.Key,
+			Value:       e.Value,
+			IsPreview:   e.IsPreview,
+			IsBuildTime: e.IsBuildTime,
+			IsLiteral:   e.IsLiteral,
+			IsMultiline: e.IsMultiline,
+			IsShownOnce: e.IsShownOnce,
+		})
+	}
+	if len(copied) == 0 {
+		return skipped, nil
+	}
+	if _, err := client.UpdateApplicationEnvsBulk(ctx, toUUID, copied); err != nil {
+		return skipped, fmt.Errorf("failed to copy environment variables: %w", err)
+	}
+	return skipped, nil
+}
+
+// applicationSettings returns the settings of app that carry over to a copy
+// of it. Domains and host port mappings are left out since they can't be
+// shared between running applications.
+func applicationSettings(app *api.Application) map[string]interface{} {
+	settings := map[string]interface{}{
+		"health_check_enabled": app.HealthCheckEnabled,
+	}
+
+	strs := map[string]string{
+		"ports_exposes":             app.PortsExposes,
+		"dockerfile_location":       app.DockerfileLocation,
+		"health_check_path":         app.HealthCheckPath,
+		"health_check_method":       app.HealthCheckMethod,
+		"health_check_scheme":       app.HealthCheckScheme,
+		"limits_memory":             app.LimitsMemory,
+		"limits_memory_swap":        app.LimitsMemorySwap,
+		"limits_memory_reservation": app.LimitsMemoryReservation,
+		"limits_cpus":               app.LimitsCPUs,
+	}
+	for key, value := range strs {
+		if value != "" {
+			settings[key] = value
+		}
+	}
+
+	ints := map[string]int{
+		"health_check_return_code":  app.HealthCheckReturnCode,
+		"health_check_interval":     app.HealthCheckInterval,
+		"health_check_timeout":      app.HealthCheckTimeout,
+		"health_check_retries":      app.HealthCheckRetries,
+		"health_check_start_period": app.HealthCheckStartPeriod,
+	}
+	for key, value := range ints {
+		if value > 0 {
+			settings[key] = value
+		}
+	}
+
+	ptrs := map[string]*string{
+		"health_check_port":                 app.HealthCheckPort,
+		"health_check_host":                 app.HealthCheckHost,
+		"health_check_response_text":        app.HealthCheckResponseText,
+		"custom_docker_run_options":         app.CustomDockerRunOptions,
+		"pre_deployment_command":            app.PreDeploymentCommand,
+		"pre_deployment_command_container":  app.PreDeploymentCommandContainer,
+		"post_deployment_command":           app.PostDeploymentCommand,
+		"post_deployment_command_container": app.PostDeploymentCommandContainer,
+		"redirect":                          app.Redirect,
+	}
+	for key, value := range ptrs {
+		if value != nil && *value != "" {
+			settings[key] = *value
+		}
+	}
+	return settings
+}
+
+// retireApplication stops an application that has been replaced and deletes
+// it unless it's kept
+func retireApplication(client *api.Client, uuid string, keep bool) error {
+	if _, err := client.StopApplication(context.Background(), uuid); err != nil {
+		return fmt.Errorf("failed to stop application: %w", err)
+	}
+	if keep {
+		// Free the domains so the stopped application can't claim them back
+		return client.UpdateApplication(uuid, map[string]interface{}{"domains": ""})
+	}
+	return client.DeleteApplication(uuid)
+}
+
+// discardGreen deletes the application of a blue/green deployment that
+// didn't go live
+func discardGreen(client *api.Client, uuid, name string) {
+	if uuid == "" {
+		return
+	}
+	if err := client.DeleteApplication(uuid); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to delete %s: %v", name, err))
+		return
+	}
+	ui.Dim(fmt.Sprintf("Deleted %s", name))
+}
@@ -0,0 +1,106 @@
+package appdeploy
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// useBuildServer points docker at the project's build server over ssh when
+// it builds remotely, so the build, the image and its push all happen
+// there. The returned func points docker back at what it used before.
+func useBuildServer(client *api.Client, projectCfg *config.ProjectConfig) (func(), error) {
+	if !projectCfg.RemoteBuild() {
+		return func() {}, nil
+	}
+	// Trivy can't read images from a docker it reaches over ssh
+	if projectCfg.ImageScanSettings() != nil {
+		return nil, fmt.Errorf("the image scan doesn't support remote builds yet: pass --scan=false, or build locally")
+	}
+	var want string
+	if projectCfg.Build != nil {
+		want = projectCfg.Build.BuildServer
+	}
+
+	var server *api.Server
+	var host string
+	err := ui.RunTasks([]ui.Task{{
+		Name:         "build-server",
+		ActiveName:   "Connecting to build server...",
+		CompleteName: "✓ Connected to build server",
+		Action: func() error {
+			var err error
+			if server, err = findBuildServer(client, want); err != nil {
+				return err
+			}
+			user, port := serverLogin(server)
+			host = fmt.Sprintf("ssh://%s@%s:%d", user, server.IP, port)
+			// docker doesn't accept new host keys on its own
+			probe := exec.Command("ssh", "-o", "StrictHostKeyChecking=accept-new", "-o", "ConnectTimeout=30", "-p", fmt.Sprint(port), user+"@"+server.IP, "docker version --format '{{.Server.Version}}'")
+			if out, err := probe.CombinedOutput(); err != nil {
+				return fmt.Errorf("failed to reach docker on %s over ssh: %s", server.Name, strings.TrimSpace(string(out)))
+			}
+			return nil
+		},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("remote build failed: %w", err)
+	}
+	ui.KeyValue("Build server", fmt.Sprintf("%s (%s)", server.Name, server.IP))
+	ui.Spacer()
+
+	previous, had := os.LookupEnv("DOCKER_HOST")
+	if err := os.Setenv("DOCKER_HOST", host); err != nil {
+		return nil, err
+	}
+	return func() {
+		if had {
+			_ = os.Setenv("DOCKER_HOST", previous)
+		} else {
+			_ = os.Unsetenv("DOCKER_HOST")
+		}
+	}, nil
+}
+
+// findBuildServer returns the server named or with the UUID want, or the
+// instance's first build server
+func findBuildServer(client *api.Client, want string) (*api.Server, error) {
+	servers, err := client.ListServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+	for i := range servers {
+		s := &servers[i]
+		if want != "" {
+			if s.UUID == want || strings.EqualFold(s.Name, want) {
+				return s, nil
+			}
+			continue
+		}
+		if s.IsBuildServer || s.Settings != nil && s.Settings.IsBuildServer {
+			return s, nil
+		}
+	}
+	if want != "" {
+		return nil, fmt.Errorf("no server %q", want)
+	}
+	return nil, fmt.Errorf("the instance has no build server: mark one as a build server in Coolify, or set build.build_server")
+}
+
+// serverLogin returns the ssh user and port of a server
+func serverLogin(server *api.Server) (string, int) {
+	user := server.User
+	if user == "" {
+		user = "root"
+	}
+	port := server.Port
+	if port == 0 {
+		port = 22
+	}
+	return user, port
+}
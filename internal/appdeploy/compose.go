@@ -0,0 +1,336 @@
+package appdeploy
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/detect"
+	"github.com/entro314-labs/cool-kit/internal/smart"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// composeStackOption keeps deploying the compose file as a whole
+const composeStackOption = "Deploy the compose file as one stack"
+
+// mapComposeServices shows the services of a compose project and offers to
+// deploy one of them as the Coolify application, with the others
+// provisioned as Coolify databases and services, instead of running the
+// whole file. It returns the framework to deploy with.
+func mapComposeServices(framework *detect.FrameworkInfo, deploymentConfig *smart.DeploymentConfig) (*detect.FrameworkInfo, error) {
+	compose := framework.Compose
+	if compose == nil || len(compose.Services) == 0 {
+		return framework, nil
+	}
+
+	ui.Spacer()
+	ui.Dim("Compose services:")
+	rows := make([][]string, 0, len(compose.Services))
+	for _, s := range compose.Services {
+		source := s.Image
+		if s.Build != nil {
+			source = "build " + s.Build.Context
+		}
+		engine, _ := s.Engine()
+		rows = append(rows, []string{s.Name, source, orNone(strings.Join(s.Ports, ", ")), orNone(strings.Join(s.Volumes, ", ")), orNone(engine)})
+	}
+	ui.Table([]string{"Service", "Source", "Ports", "Volumes", "Coolify"}, rows)
+
+	// Every other service must have a Coolify equivalent for a split
+	options := []string{composeStackOption}
+	splits := map[string]detect.ComposeService{}
+	mapped := map[string][]smart.RequiredService{}
+	var reason error
+	for _, candidate := range smart.ComposeAppCandidates(compose) {
+		services, err := smart.ServicesFromCompose(compose, candidate.Name)
+		if err != nil {
+			reason = err
+			continue
+		}
+		option := fmt.Sprintf("Deploy %s as the application", candidate.Name)
+		if len(services) > 0 {
+			types := make([]string, 0, len(services))
+			for _, s := range services {
+				types = append(types, s.Type)
+			}
+			option += " and provision " + strings.Join(types, ", ")
+		}
+		options = append(options, option)
+		splits[option] = candidate
+		mapped[option] = services
+	}
+
+	if len(splits) == 0 {
+		dropComposeProvided(compose, deploymentConfig)
+		ui.Spacer()
+		if reason != nil {
+			ui.Dim(fmt.Sprintf("The compose file is deployed as one stack: %s", reason))
+		} else {
+			ui.Dim("The compose file is deployed as one stack: no service is built from the Dockerfile at the repository root")
+		}
+		return framework, nil
+	}
+
+	ui.Spacer()
+	choice, err := ui.SelectDefault("How should the compose services be deployed?", options, composeStackOption)
+	if err != nil {
+		return nil, err
+	}
+	if choice == composeStackOption {
+		dropComposeProvided(compose, deploymentConfig)
+		return framework, nil
+	}
+
+	app := splits[choice]
+	port := app.Port()
+	if port == "" {
+		port = "3000"
+	}
+	split := &detect.FrameworkInfo{
+		Name:      "Dockerfile",
+		BuildPack: detect.BuildPackDockerfile,
+		Port:      port,
+		IsStatic:  false,
+	}
+
+	// The compose services replace whatever dependency scanning found
+	deploymentConfig.Framework = split
+	deploymentConfig.Services = mapped[choice]
+	provided := map[string]bool{}
+	for _, s := range deploymentConfig.Services {
+		provided[s.EnvVarName] = true
+	}
+	keys := make([]string, 0, len(app.Environment))
+	for key := range app.Environment {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := app.Environment[key]
+		// Connection URLs are generated, and ${VAR} interpolation only works in compose
+		if provided[key] || strings.Contains(value, "${") || pointsAtComposeService(value, compose) {
+			continue
+		}
+		deploymentConfig.Environment = append(deploymentConfig.Environment, smart.EnvironmentVariable{
+			Key:         key,
+			Value:       value,
+			Description: fmt.Sprintf("From service %s", app.Name),
+		})
+	}
+
+	ui.Success(fmt.Sprintf("%s will be deployed from its Dockerfile on port %s", app.Name, port))
+	for _, v := range app.Volumes {
+		ui.Warning(fmt.Sprintf("Volume %s isn't created automatically: add it as persistent storage in Coolify", v))
+	}
+	return split, nil
+}
+
+// dropComposeProvided removes detected services the compose stack already
+// runs, so they aren't provisioned twice
+func dropComposeProvided(compose *detect.ComposeFile, deploymentConfig *smart.DeploymentConfig) {
+	running := map[string]bool{}
+	for _, s := range compose.Services {
+		if engine, _ := s.Engine(); engine != "" {
+			running[engine] = true
+		}
+	}
+	services := deploymentConfig.Services[:0]
+	for _, s := range deploymentConfig.Services {
+		if !running[s.Type] {
+			services = append(services, s)
+		}
+	}
+	deploymentConfig.Services = services
+}
+
+// pointsAtComposeService reports whether value is a URL to another compose service
+func pointsAtComposeService(value string, compose *detect.ComposeFile) bool {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+	for _, s := range compose.Services {
+		if u.Hostname() == s.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateComposeStack parses and checks the compose file in dir that is
+// deployed as a stack, so its mistakes show before Coolify builds it
+func validateComposeStack(dir string) (*detect.ComposeFile, error) {
+	path := detect.FindComposeFile(dir)
+	compose, err := detect.ParseComposeFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := compose.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid compose file %s:\n%w", filepath.Base(path), err)
+	}
+	ui.Success(fmt.Sprintf("%s is valid: %d service(s)", filepath.Base(path), len(compose.Services)))
+	return compose, nil
+}
+
+// isComposeStack reports whether framework deploys a compose file as a whole
+func isComposeStack(framework *detect.FrameworkInfo) bool {
+	return framework.BuildPack == detect.BuildPackDockerCompose
+}
+
+// checkComposeMethod rejects deploying a compose stack with a method other
+// than git: Coolify builds and runs compose stacks from the repository
+func checkComposeMethod(framework *detect.FrameworkInfo, deployMethod string) error {
+	if isComposeStack(framework) && deployMethod != config.DeployMethodGit {
+		return fmt.Errorf("compose stacks deploy with the git method: Coolify builds and runs them from the repository")
+	}
+	return nil
+}
+
+// configureComposeStack returns how a compose stack maps onto its Coolify
+// application, asking for the services' domains, or nil for other
+// frameworks
+func configureComposeStack(framework *detect.FrameworkInfo, domain string) (*config.ComposeConfig, error) {
+	if !isComposeStack(framework) {
+		return nil, nil
+	}
+
+	domains, err := chooseComposeDomains(framework.Compose, domain)
+	if err != nil {
+		return nil, err
+	}
+	return &config.ComposeConfig{
+		Location: "/" + filepath.Base(framework.Compose.Path),
+		Domains:  domains,
+	}, nil
+}
+
+// chooseComposeDomains returns the domains of the compose services that
+// publish a port, other than databases. domain, from --domain, maps them
+// like "web=example.com,api=api.example.com", or is the first one's;
+// without it each is asked for.
+func chooseComposeDomains(compose *detect.ComposeFile, domain string) (map[string]string, error) {
+	var served []detect.ComposeService
+	for _, s := range compose.Services {
+		if engine, _ := s.Engine(); s.Port() != "" && engine == "" {
+			served = append(served, s)
+		}
+	}
+
+	domains := map[string]string{}
+	if domain != "" && !strings.Contains(domain, "=") {
+		if len(served) == 0 {
+			return nil, fmt.Errorf("no compose service publishes a port for %s: use --domain service=%s", domain, domain)
+		}
+		domains[served[0].Name] = composeDomain(domain, served[0].Port())
+		return domains, nil
+	}
+	if domain != "" {
+		for _, pair := range strings.Split(domain, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			service := composeService(compose, name)
+			if service == nil || value == "" {
+				return nil, fmt.Errorf("invalid --domain %s: use service=domain with a service of the compose file", pair)
+			}
+			domains[name] = composeDomain(value, service.Port())
+		}
+		return domains, nil
+	}
+
+	if len(served) == 0 || !ui.Interactive() {
+		return domains, nil
+	}
+	ui.Spacer()
+	ui.Dim("Services without a domain are only reachable inside the stack")
+	for _, s := range served {
+		value, err := ui.Input(fmt.Sprintf("Domain for %s on port %s (empty for none):", s.Name, s.Port()), "app.example.com")
+		if err != nil {
+			return nil, err
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			domains[s.Name] = composeDomain(value, s.Port())
+			ui.Dim(fmt.Sprintf("→ %s", domains[s.Name]))
+		}
+	}
+	return domains, nil
+}
+
+func composeService(compose *detect.ComposeFile, name string) *detect.ComposeService {
+	for i := range compose.Services {
+		if compose.Services[i].Name == name {
+			return &compose.Services[i]
+		}
+	}
+	return nil
+}
+
+// composeDomain adds https:// to domain's entries without a scheme and the
+// container port Coolify routes them to, unless it's 80
+func composeDomain(domain, port string) string {
+	entries := strings.Split(domain, ",")
+	for i, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "://") {
+			entry = "https://" + entry
+		}
+		if u, err := url.Parse(entry); err == nil && u.Port() == "" && port != "" && port != "80" {
+			u.Host += ":" + port
+			entry = u.String()
+		}
+		entries[i] = entry
+	}
+	return strings.Join(entries, ",")
+}
+
+// composeDomainRequests returns the services' domains for the API, sorted
+// by service name
+func composeDomainRequests(domains map[string]string) []api.ComposeDomain {
+	names := make([]string, 0, len(domains))
+	for name := range domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	requests := make([]api.ComposeDomain, 0, len(names))
+	for _, name := range names {
+		requests = append(requests, api.ComposeDomain{Name: name, Domain: domains[name]})
+	}
+	return requests
+}
+
+// composeLocation returns the path of the compose file Coolify deploys, in
+// the base directory
+func composeLocation(projectCfg *config.ProjectConfig) string {
+	if projectCfg.Compose != nil && projectCfg.Compose.Location != "" {
+		return projectCfg.Compose.Location
+	}
+	if path := detect.FindComposeFile(projectCfg.SourceDir()); path != "" {
+		return "/" + filepath.Base(path)
+	}
+	return ""
+}
+
+// composeEnvReferences returns the variables a compose stack interpolates,
+// except the SERVICE_* ones Coolify generates
+func composeEnvReferences(compose *detect.ComposeFile) []smart.EnvReference {
+	if compose == nil {
+		return nil
+	}
+	var refs []smart.EnvReference
+	for _, v := range compose.Variables {
+		if strings.HasPrefix(v.Name, "SERVICE_") {
+			continue
+		}
+		refs = append(refs, smart.EnvReference{Key: v.Name, File: filepath.Base(compose.Path), Optional: !v.Required})
+	}
+	return refs
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return ui.DimStyle.Render("-")
+	}
+	return s
+}
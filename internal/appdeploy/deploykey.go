@@ -0,0 +1,594 @@
+package appdeploy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/service"
+	"github.com/entro314-labs/cool-kit/internal/smart"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// generateDeployKey is the deploy key option that makes a new key
+const generateDeployKey = "Generate a new deploy key"
+
+// keyHost is a git host that Coolify clones from with a deploy key and that
+// calls Coolify's manual webhook on every push: GitLab, Gitea, Bitbucket and
+// GitHub without a GitHub App
+type keyHost interface {
+	// Name is the host's name in messages
+	Name() string
+	// Username checks the token and returns whose it is
+	Username() (string, error)
+	// Repo returns the repository at path, like owner/name
+	Repo(path string) (*hostRepo, error)
+	// CreateRepo creates a repository in the user's namespace
+	CreateRepo(name, description string, private bool) (*hostRepo, error)
+	AddDeployKey(repo *hostRepo, title, key string) error
+	AddWebhook(repo *hostRepo, hookURL, secret string) error
+	// WebhookURLs returns the URLs the repository's webhooks call
+	WebhookURLs(repo *hostRepo) ([]string, error)
+}
+
+// hostRepo is a repository on a keyHost
+type hostRepo struct {
+	ID       int
+	Path     string // owner/name
+	CloneURL string // https, for pushing
+	SSHURL   string // for Coolify, with the deploy key
+}
+
+// usesDeployKey reports whether Coolify clones the project's repository
+// with a deploy key rather than a GitHub App
+func usesDeployKey(projectCfg *config.ProjectConfig) bool {
+	return projectCfg.GitHubDeployKey || gitProvider(projectCfg) != config.GitProviderGitHub
+}
+
+// gitProvider returns the project's git host, GitHub unless set
+func gitProvider(projectCfg *config.ProjectConfig) string {
+	if projectCfg.GitProvider == "" {
+		return config.GitProviderGitHub
+	}
+	return projectCfg.GitProvider
+}
+
+// newKeyHost returns the client of the project's git host
+func newKeyHost(globalCfg *config.GlobalConfig, provider string) keyHost {
+	switch provider {
+	case config.GitProviderGitHub:
+		return gitHubHost{git.NewGitHubClient(globalCfg.GitHubToken)}
+	case config.GitProviderGitea:
+		return giteaHost{git.NewGiteaClient(globalCfg.GiteaURL, globalCfg.GiteaToken)}
+	case config.GitProviderBitbucket:
+		return bitbucketHost{git.NewBitbucketClient(globalCfg.BitbucketUser, globalCfg.BitbucketToken)}
+	}
+	return gitLabHost{git.NewGitLabClient(globalCfg.GitLabInstance(), globalCfg.GitLabToken)}
+}
+
+// deployWithKey is DeployGit for a repository on a keyHost. Coolify clones
+// it with a deploy key, and a webhook redeploys on every push.
+func deployWithKey(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, prNumber int, allowSecrets, verbose bool, watch WatchOptions) error {
+	host := newKeyHost(globalCfg, gitProvider(projectCfg))
+
+	username, err := getHostUsername(host, verbose)
+	if err != nil {
+		return err
+	}
+
+	// A name alone is a repository in the user's namespace
+	path := projectCfg.GitRepo
+	if !strings.Contains(path, "/") {
+		path = username + "/" + path
+	}
+	repo, err := host.Repo(path)
+	needsRepoCreation := git.IsNotFound(err)
+	if err != nil && !needsRepoCreation {
+		return fmt.Errorf("failed to get %s repository %q: %w", host.Name(), path, err)
+	}
+	if needsRepoCreation {
+		// New repositories go in the user's namespace; others need creating first
+		name, ok := strings.CutPrefix(path, username+"/")
+		if !ok {
+			return fmt.Errorf("%s repository %q not found: create it on %s first, or set git_repo to a name to create it in your namespace", host.Name(), path, host.Name())
+		}
+		projectCfg.GitRepo = name
+		if err := handleHostRepoSetup(host, projectCfg, username); err != nil {
+			return err
+		}
+	}
+
+	deployKey, err := handleDeployKeySelection(client, host, projectCfg, needsRepoCreation, verbose)
+	if err != nil {
+		return err
+	}
+
+	// Execute deployment tasks
+	ui.Spacer()
+	ui.Divider()
+
+	tasks := coolifyProjectTasks(client, projectCfg)
+	if needsRepoCreation {
+		tasks = append(tasks, ui.Task{
+			Name:         "create-repo",
+			ActiveName:   fmt.Sprintf("Creating %s repository...", host.Name()),
+			CompleteName: fmt.Sprintf("✓ Created %s repository", host.Name()),
+			Action: func() error {
+				if err := CreateReadmeIfMissing(projectCfg); err != nil {
+					ui.Dim(fmt.Sprintf("Warning: Failed to create README: %v", err))
+				}
+
+				var err error
+				repo, err = host.CreateRepo(
+					projectCfg.GitRepo,
+					fmt.Sprintf("Deployment repository for %s", projectCfg.Name),
+					projectCfg.GitPrivate,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to create %s repository %q: %w", host.Name(), projectCfg.GitRepo, err)
+				}
+				projectCfg.GitRepo = repo.Path
+				return config.SaveProject(projectCfg)
+			},
+		})
+	}
+	if !git.IsRepo(".") {
+		tasks = append(tasks, initGitTask())
+	}
+	tasks = append(tasks, ui.Task{
+		Name:         "push-code",
+		ActiveName:   fmt.Sprintf("Pushing code to %s...", host.Name()),
+		CompleteName: fmt.Sprintf("✓ Pushed code to %s", host.Name()),
+		Action: func() error {
+			if err := git.SetRemote(".", "origin", repo.CloneURL); err != nil {
+				return fmt.Errorf("failed to configure git remote: %w", err)
+			}
+			return commitAndPush(globalCfg, projectCfg, allowSecrets, verbose)
+		},
+	})
+	if projectCfg.PrivateKeyUUID == "" {
+		tasks = append(tasks, ui.Task{
+			Name:         "deploy-key",
+			ActiveName:   fmt.Sprintf("Adding deploy key to %s...", host.Name()),
+			CompleteName: fmt.Sprintf("✓ Added deploy key to %s", host.Name()),
+			Action: func() error {
+				return addDeployKey(client, host, projectCfg, repo, deployKey)
+			},
+		})
+	}
+
+	if err := ui.RunTasksVerbose(tasks, verbose); err != nil {
+		ui.Error("Deployment setup failed")
+		return err
+	}
+
+	// The clone URL is only known once the repository exists
+	webhook := ui.Task{
+		Name:         "webhook",
+		ActiveName:   fmt.Sprintf("Adding %s webhook...", host.Name()),
+		CompleteName: fmt.Sprintf("✓ Added %s webhook", host.Name()),
+		Action: func() error {
+			return addHostWebhook(client, host, globalCfg, projectCfg, repo)
+		},
+	}
+	appExists := projectCfg.AppUUID != ""
+	tasks = gitAppTasks(client, projectCfg, deploymentConfig, repo.SSHURL, prNumber, []ui.Task{webhook})
+	if appExists {
+		// Applications linked or created before the webhook was added
+		tasks = append(tasks, ui.Task{
+			Name:         "webhook",
+			ActiveName:   fmt.Sprintf("Checking %s webhook...", host.Name()),
+			CompleteName: fmt.Sprintf("✓ %s webhook in place", host.Name()),
+			Action: func() error {
+				// The deployment doesn't need it, so a token without
+				// webhook rights only costs push-to-deploy
+				if err := ensureHostWebhook(client, host, globalCfg, projectCfg, repo); err != nil {
+					ui.Dim(fmt.Sprintf("Warning: %v", err))
+				}
+				return nil
+			},
+		})
+	}
+	if err := ui.RunTasksVerbose(tasks, verbose); err != nil {
+		ui.Error("Deployment setup failed")
+		return err
+	}
+
+	var ghClient *git.GitHubClient
+	if h, ok := host.(gitHubHost); ok {
+		ghClient = h.client
+	}
+	return finishGitDeployment(client, projectCfg, deploymentConfig, prNumber, watch, ghClient, repo.Path)
+}
+
+func getHostUsername(host keyHost, verbose bool) (string, error) {
+	var username string
+	err := ui.RunTasksVerbose([]ui.Task{
+		{
+			Name:         "host-check",
+			ActiveName:   fmt.Sprintf("Checking %s connection...", host.Name()),
+			CompleteName: fmt.Sprintf("✓ Connected to %s", host.Name()),
+			Action: func() error {
+				var err error
+				username, err = host.Username()
+				return err
+			},
+		},
+	}, verbose)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to connect to %s", host.Name()))
+		return "", fmt.Errorf("failed to connect to %s: %w", host.Name(), err)
+	}
+	return username, nil
+}
+
+func handleHostRepoSetup(host keyHost, projectCfg *config.ProjectConfig, username string) error {
+	ui.Spacer()
+	ui.Divider()
+	ui.Bold("Git Deployment")
+	ui.Spacer()
+	ui.Bold(fmt.Sprintf("%s Repository Setup", host.Name()))
+	ui.Spacer()
+
+	repoName, err := ui.InputWithDefault("Repository name:", projectCfg.GitRepo)
+	if err != nil {
+		return err
+	}
+	projectCfg.GitRepo = repoName
+	ui.Dim(fmt.Sprintf("→ %s/%s", username, repoName))
+
+	visibilityOptions := []string{"Private", "Public"}
+	visibility, err := ui.SelectDefault("Repository visibility:", visibilityOptions, "Private")
+	if err != nil {
+		return err
+	}
+	projectCfg.GitPrivate = (visibility == "Private")
+	ui.Dim(fmt.Sprintf("→ %s", visibility))
+
+	return nil
+}
+
+// handleDeployKeySelection asks which Coolify private key clones the
+// repository, returning nil for a new one
+func handleDeployKeySelection(client *api.Client, host keyHost, projectCfg *config.ProjectConfig, needsRepoCreation bool, verbose bool) (*api.PrivateKey, error) {
+	// Use saved deploy key if available
+	if projectCfg.PrivateKeyUUID != "" {
+		return nil, nil
+	}
+
+	var keys []api.PrivateKey
+	err := ui.RunTasksVerbose([]ui.Task{
+		{
+			Name:         "load-keys",
+			ActiveName:   "Loading private keys...",
+			CompleteName: "✓ Loaded private keys",
+			Action: func() error {
+				var err error
+				keys, err = service.NewPrivateKeyService(client).List(context.Background())
+				return err
+			},
+		},
+	}, verbose)
+	if err != nil {
+		ui.Error("Failed to load private keys")
+		return nil, err
+	}
+
+	// Only keys with a public half can be added to the host
+	options := []string{generateDeployKey}
+	byName := make(map[string]*api.PrivateKey)
+	for i := range keys {
+		if keys[i].PublicKey == "" || byName[keys[i].Name] != nil {
+			continue
+		}
+		options = append(options, keys[i].Name)
+		byName[keys[i].Name] = &keys[i]
+	}
+	if len(options) == 1 {
+		return nil, nil
+	}
+
+	if !needsRepoCreation {
+		ui.Spacer()
+		ui.Divider()
+		ui.Bold("Git Deployment")
+	} else {
+		ui.Spacer()
+	}
+	choice, err := ui.SelectDefault(fmt.Sprintf("%s deploy key:", host.Name()), options, generateDeployKey)
+	if err != nil {
+		return nil, err
+	}
+	ui.Dim(fmt.Sprintf("→ %s", choice))
+	return byName[choice], nil
+}
+
+// addDeployKey adds key, or a new key it also stores in Coolify when nil, to
+// the repository as a read-only deploy key
+func addDeployKey(client *api.Client, host keyHost, projectCfg *config.ProjectConfig, repo *hostRepo, key *api.PrivateKey) error {
+	if key == nil {
+		name := fmt.Sprintf("%s-%s", projectCfg.Name, gitProvider(projectCfg))
+		generated, err := service.GenerateED25519Key("cool-kit-" + name)
+		if err != nil {
+			return err
+		}
+		key, err = service.NewPrivateKeyService(client).Create(context.Background(), service.PrivateKeyCreateRequest{
+			Name:        name,
+			Description: fmt.Sprintf("Deploy key for %s, added by cool-kit", repo.Path),
+			PrivateKey:  generated.PrivateKey,
+		})
+		if err != nil {
+			return err
+		}
+		key.PublicKey = generated.PublicKey
+	}
+
+	if err := host.AddDeployKey(repo, "Coolify", key.PublicKey); err != nil {
+		return fmt.Errorf("failed to add deploy key to %s: %w", host.Name(), err)
+	}
+	projectCfg.PrivateKeyUUID = key.UUID
+	return config.SaveProject(projectCfg)
+}
+
+// addHostWebhook makes the host call Coolify's manual webhook on every push,
+// signed with a new secret stored on the application
+func addHostWebhook(client *api.Client, host keyHost, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, repo *hostRepo) error {
+	secret, err := webhookSecret()
+	if err != nil {
+		return err
+	}
+	// Coolify names the field and the endpoint after the provider
+	provider := gitProvider(projectCfg)
+	if err := client.UpdateApplication(projectCfg.AppUUID, map[string]interface{}{
+		"manual_webhook_secret_" + provider: secret,
+	}); err != nil {
+		return fmt.Errorf("failed to set webhook secret: %w", err)
+	}
+
+	if err := host.AddWebhook(repo, manualWebhookURL(globalCfg, provider), secret); err != nil {
+		return fmt.Errorf("failed to add %s webhook: %w", host.Name(), err)
+	}
+	return nil
+}
+
+// ensureHostWebhook adds the webhook unless the repository already calls
+// Coolify's manual webhook
+func ensureHostWebhook(client *api.Client, host keyHost, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, repo *hostRepo) error {
+	urls, err := host.WebhookURLs(repo)
+	if err != nil {
+		return fmt.Errorf("failed to list %s webhooks: %w", host.Name(), err)
+	}
+	hookURL := manualWebhookURL(globalCfg, gitProvider(projectCfg))
+	for _, u := range urls {
+		if u == hookURL {
+			return nil
+		}
+	}
+	return addHostWebhook(client, host, globalCfg, projectCfg, repo)
+}
+
+// manualWebhookURL is the Coolify endpoint that git hosts without a Coolify
+// source call on every push
+func manualWebhookURL(globalCfg *config.GlobalConfig, provider string) string {
+	return fmt.Sprintf("%s/webhooks/source/%s/events/manual", strings.TrimSuffix(globalCfg.CoolifyURL, "/"), provider)
+}
+
+// webhookSecret returns a random secret for a git host's webhook
+func webhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// gitHubHost is GitHub for users who can't install a GitHub App, like
+// members without admin rights on their organization
+type gitHubHost struct {
+	client *git.GitHubClient
+}
+
+func (h gitHubHost) Name() string { return "GitHub" }
+
+func (h gitHubHost) Username() (string, error) {
+	user, err := h.client.GetUser()
+	if err != nil {
+		return "", err
+	}
+	return user.Login, nil
+}
+
+func (h gitHubHost) Repo(path string) (*hostRepo, error) {
+	owner, name, _ := strings.Cut(path, "/")
+	repo, err := h.client.GetRepo(owner, name)
+	if err != nil {
+		return nil, err
+	}
+	return gitHubRepo(repo), nil
+}
+
+func (h gitHubHost) CreateRepo(name, description string, private bool) (*hostRepo, error) {
+	repo, err := h.client.CreateRepo(name, description, private)
+	if err != nil {
+		return nil, err
+	}
+	return gitHubRepo(repo), nil
+}
+
+func (h gitHubHost) AddDeployKey(repo *hostRepo, title, key string) error {
+	owner, name, _ := strings.Cut(repo.Path, "/")
+	return h.client.AddDeployKey(owner, name, title, key)
+}
+
+func (h gitHubHost) AddWebhook(repo *hostRepo, hookURL, secret string) error {
+	owner, name, _ := strings.Cut(repo.Path, "/")
+	return h.client.AddPushWebhook(owner, name, hookURL, secret)
+}
+
+func (h gitHubHost) WebhookURLs(repo *hostRepo) ([]string, error) {
+	owner, name, _ := strings.Cut(repo.Path, "/")
+	return h.client.WebhookURLs(owner, name)
+}
+
+func gitHubRepo(r *git.Repository) *hostRepo {
+	return &hostRepo{ID: r.ID, Path: r.FullName, CloneURL: r.CloneURL, SSHURL: r.SSHURL}
+}
+
+// gitLabHost is GitLab, where repositories are called projects
+type gitLabHost struct {
+	client *git.GitLabClient
+}
+
+func (h gitLabHost) Name() string { return "GitLab" }
+
+func (h gitLabHost) Username() (string, error) {
+	user, err := h.client.GetUser()
+	if err != nil {
+		return "", err
+	}
+	return user.Username, nil
+}
+
+func (h gitLabHost) Repo(path string) (*hostRepo, error) {
+	project, err := h.client.GetProject(path)
+	if err != nil {
+		return nil, err
+	}
+	return gitLabRepo(project), nil
+}
+
+func (h gitLabHost) CreateRepo(name, description string, private bool) (*hostRepo, error) {
+	project, err := h.client.CreateProject(name, description, private)
+	if err != nil {
+		return nil, err
+	}
+	return gitLabRepo(project), nil
+}
+
+func (h gitLabHost) AddDeployKey(repo *hostRepo, title, key string) error {
+	return h.client.AddDeployKey(repo.ID, title, key)
+}
+
+func (h gitLabHost) AddWebhook(repo *hostRepo, hookURL, secret string) error {
+	return h.client.AddPushWebhook(repo.ID, hookURL, secret)
+}
+
+func (h gitLabHost) WebhookURLs(repo *hostRepo) ([]string, error) {
+	return h.client.WebhookURLs(repo.ID)
+}
+
+func gitLabRepo(p *git.GitLabProject) *hostRepo {
+	return &hostRepo{ID: p.ID, Path: p.PathWithNamespace, CloneURL: p.HTTPURLToRepo, SSHURL: p.SSHURLToRepo}
+}
+
+// giteaHost is a Gitea or Forgejo instance
+type giteaHost struct {
+	client *git.GiteaClient
+}
+
+func (h giteaHost) Name() string { return "Gitea" }
+
+func (h giteaHost) Username() (string, error) {
+	user, err := h.client.GetUser()
+	if err != nil {
+		return "", err
+	}
+	return user.Login, nil
+}
+
+func (h giteaHost) Repo(path string) (*hostRepo, error) {
+	owner, name, _ := strings.Cut(path, "/")
+	repo, err := h.client.GetRepo(owner, name)
+	if err != nil {
+		return nil, err
+	}
+	return giteaRepo(repo), nil
+}
+
+func (h giteaHost) CreateRepo(name, description string, private bool) (*hostRepo, error) {
+	repo, err := h.client.CreateRepo(name, description, private)
+	if err != nil {
+		return nil, err
+	}
+	return giteaRepo(repo), nil
+}
+
+func (h giteaHost) AddDeployKey(repo *hostRepo, title, key string) error {
+	owner, name, _ := strings.Cut(repo.Path, "/")
+	return h.client.AddDeployKey(owner, name, title, key)
+}
+
+func (h giteaHost) AddWebhook(repo *hostRepo, hookURL, secret string) error {
+	owner, name, _ := strings.Cut(repo.Path, "/")
+	return h.client.AddPushWebhook(owner, name, hookURL, secret)
+}
+
+func (h giteaHost) WebhookURLs(repo *hostRepo) ([]string, error) {
+	owner, name, _ := strings.Cut(repo.Path, "/")
+	return h.client.WebhookURLs(owner, name)
+}
+
+func giteaRepo(r *git.GiteaRepo) *hostRepo {
+	return &hostRepo{ID: r.ID, Path: r.FullName, CloneURL: r.CloneURL, SSHURL: r.SSHURL}
+}
+
+// bitbucketHost is Bitbucket Cloud, where the user's namespace is their
+// personal workspace
+type bitbucketHost struct {
+	client *git.BitbucketClient
+}
+
+func (h bitbucketHost) Name() string { return "Bitbucket" }
+
+func (h bitbucketHost) Username() (string, error) {
+	user, err := h.client.GetUser()
+	if err != nil {
+		return "", err
+	}
+	return user.Username, nil
+}
+
+func (h bitbucketHost) Repo(path string) (*hostRepo, error) {
+	workspace, slug, _ := strings.Cut(path, "/")
+	repo, err := h.client.GetRepo(workspace, slug)
+	if err != nil {
+		return nil, err
+	}
+	return bitbucketRepo(repo), nil
+}
+
+func (h bitbucketHost) CreateRepo(name, description string, private bool) (*hostRepo, error) {
+	username, err := h.Username()
+	if err != nil {
+		return nil, err
+	}
+	repo, err := h.client.CreateRepo(username, name, description, private)
+	if err != nil {
+		return nil, err
+	}
+	return bitbucketRepo(repo), nil
+}
+
+func (h bitbucketHost) AddDeployKey(repo *hostRepo, title, key string) error {
+	workspace, slug, _ := strings.Cut(repo.Path, "/")
+	return h.client.AddDeployKey(workspace, slug, title, key)
+}
+
+func (h bitbucketHost) AddWebhook(repo *hostRepo, hookURL, secret string) error {
+	workspace, slug, _ := strings.Cut(repo.Path, "/")
+	return h.client.AddPushWebhook(workspace, slug, hookURL, secret)
+}
+
+func (h bitbucketHost) WebhookURLs(repo *hostRepo) ([]string, error) {
+	workspace, slug, _ := strings.Cut(repo.Path, "/")
+	return h.client.WebhookURLs(workspace, slug)
+}
+
+func bitbucketRepo(r *git.BitbucketRepo) *hostRepo {
+	return &hostRepo{Path: r.FullName, CloneURL: r.CloneURL("https"), SSHURL: r.CloneURL("ssh")}
+}
@@ -13,9 +13,14 @@ import (
 )
 
 // DeployDocker handles Docker-based deployments
-func DeployDocker(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, prNumber int, verbose bool) error {
+func DeployDocker(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, prNumber int, verbose bool, watch WatchOptions) error {
+	return deployDocker(client, globalCfg, projectCfg, deploymentConfig, prNumber, projectCfg.SourceDir(), verbose, watch)
+}
+
+// deployDocker builds the image from contextDir, pushes it and deploys it
+func deployDocker(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, prNumber int, contextDir string, verbose bool, watch WatchOptions) error {
 	// Generate tag based on PR number (0 = production, >0 = preview)
-	deployType := "production"
+	deployType := projectCfg.EnvironmentName()
 	if prNumber > 0 {
 		deployType = fmt.Sprintf("pr-%d", prNumber)
 	}
@@ -23,17 +28,31 @@ func DeployDocker(client *api.Client, globalCfg *config.GlobalConfig, projectCfg
 
 	needsProjectCreation := projectCfg.ProjectUUID == ""
 
+	restore, err := useBuildServer(client, projectCfg)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
 	ui.Spacer()
 	ui.Divider()
-	ui.Bold("Docker Build")
+	if projectCfg.DeployMethod == config.DeployMethodBuildpacks {
+		ui.Bold("Buildpacks Build")
+	} else {
+		ui.Bold("Docker Build")
+	}
 	ui.Spacer()
 	ui.KeyValue("Image", projectCfg.DockerImage)
 	ui.KeyValue("Tag", tag)
-	ui.KeyValue("Platform", projectCfg.Platform)
+	if projectCfg.DeployMethod == config.DeployMethodBuildpacks {
+		ui.KeyValue("Builder", projectCfg.Buildpacks.BuilderImage())
+	} else {
+		ui.KeyValue("Platform", projectCfg.Platform)
+	}
 	ui.Spacer()
 
 	// Build Docker image
-	if err := buildDockerImage(projectCfg, tag, verbose); err != nil {
+	if err := buildDockerImage(globalCfg, projectCfg, contextDir, tag, verbose); err != nil {
 		return err
 	}
 
@@ -51,37 +70,85 @@ func DeployDocker(client *api.Client, globalCfg *config.GlobalConfig, projectCfg
 	// Watch deployment
 	ui.Info("Watching deployment...")
 
-	success := WatchDeployment(client, projectCfg.AppUUID)
-
-	if !success {
+	if err := Watch(client, projectCfg.AppUUID, watch); err != nil {
 		ui.Error("Deployment failed")
 		ui.Spacer()
 		ui.NextSteps([]string{
 			"Run 'cdp logs' to view deployment logs",
 			"Check the Coolify dashboard for more details",
 		})
-		return fmt.Errorf("deployment failed")
+		return err
+	}
+	if watch.NoWait {
+		return nil
 	}
 
 	// Get app info for URL
 	ui.Success("Deployment complete")
 
+	var appURL string
 	app, err := client.GetApplication(projectCfg.AppUUID)
 	if err == nil && app.Fqdn != nil && *app.Fqdn != "" {
 		ui.Spacer()
 		ui.KeyValue("URL", ui.InfoStyle.Render(*app.Fqdn))
+		appURL = app.PrimaryURL()
 	}
 
-	return nil
+	if err := runMigrations(client, projectCfg.AppUUID, projectCfg, deploymentConfig, prNumber, watch); err != nil {
+		return err
+	}
+	return runSmokeTests(client, projectCfg, appURL, prNumber, watch)
 }
 
-func buildDockerImage(projectCfg *config.ProjectConfig, tag string, verbose bool) error {
+func buildDockerImage(globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, contextDir, tag string, verbose bool) error {
 	framework := &detect.FrameworkInfo{
 		Name:             projectCfg.Framework,
 		InstallCommand:   projectCfg.InstallCommand,
 		BuildCommand:     projectCfg.BuildCommand,
 		StartCommand:     projectCfg.StartCommand,
 		PublishDirectory: projectCfg.PublishDir,
+		Port:             projectCfg.Port,
+	}
+	// The runtime version isn't saved in the project config
+	if detected, err := detect.Detect(contextDir); err == nil && detected.Name == framework.Name {
+		framework.Runtime = detected.Runtime
+		framework.RuntimeVersion = detected.RuntimeVersion
+	}
+
+	opts := &docker.BuildOptions{
+		Dir:       contextDir,
+		ImageName: projectCfg.DockerImage,
+		Tag:       tag,
+		Framework: framework,
+		Platform:  projectCfg.Platform,
+		Verbose:   verbose,
+		Config:    projectCfg.Build,
+		Project:   projectCfg.Name,
+	}
+	if n := len(projectCfg.ImageTags); n > 0 {
+		opts.PreviousTag = projectCfg.ImageTags[n-1]
+	}
+	if cfg := projectCfg.Build; cfg != nil && (cfg.Cache == config.BuildCacheRegistry || cfg.InlineCache) {
+		registry, err := registryLogin(globalCfg, projectCfg)
+		if err != nil {
+			return fmt.Errorf("failed to get registry credentials: %w", err)
+		}
+		opts.Registry = registry
+	}
+
+	build := func() error {
+		return docker.Build(opts)
+	}
+	if projectCfg.DeployMethod == config.DeployMethodBuildpacks {
+		build = func() error {
+			return docker.BuildPack(&docker.PackOptions{
+				Dir:       contextDir,
+				ImageName: projectCfg.DockerImage,
+				Tag:       tag,
+				Config:    projectCfg.Buildpacks,
+				Verbose:   verbose,
+			})
+		}
 	}
 
 	// Use spinner for build unless verbose mode is enabled
@@ -91,30 +158,14 @@ func buildDockerImage(projectCfg *config.ProjectConfig, tag string, verbose bool
 			Name:         "build-image",
 			ActiveName:   "Building Docker image...",
 			CompleteName: "✓ Image built successfully",
-			Action: func() error {
-				return docker.Build(&docker.BuildOptions{
-					Dir:       ".",
-					ImageName: projectCfg.DockerImage,
-					Tag:       tag,
-					Framework: framework,
-					Platform:  projectCfg.Platform,
-					Verbose:   false,
-				})
-			},
+			Action:       build,
 		}
 		err = ui.RunTasks([]ui.Task{buildTask})
 	} else {
 		// In verbose mode, show build output directly
 		ui.Info("Building Docker image...")
 		ui.Spacer()
-		err = docker.Build(&docker.BuildOptions{
-			Dir:       ".",
-			ImageName: projectCfg.DockerImage,
-			Tag:       tag,
-			Framework: framework,
-			Platform:  projectCfg.Platform,
-			Verbose:   true,
-		})
+		err = build()
 		ui.Spacer()
 		if err == nil {
 			ui.Success("Image built successfully")
@@ -126,7 +177,8 @@ func buildDockerImage(projectCfg *config.ProjectConfig, tag string, verbose bool
 		return fmt.Errorf("build failed: %w", err)
 	}
 
-	return nil
+	reportImageSize(projectCfg, tag, opts.PreviousTag)
+	return scanImage(projectCfg, tag)
 }
 
 func buildDockerDeploymentTasks(
@@ -152,13 +204,21 @@ func buildDockerDeploymentTasks(
 	tasks = append(tasks, pushImageTask(globalCfg, projectCfg, tag, verbose))
 
 	// Create app if needed
-	if projectCfg.AppUUID == "" {
+	creatingApp := projectCfg.AppUUID == ""
+	if creatingApp {
 		tasks = append(tasks, createDockerAppTask(client, projectCfg, tag))
 	}
 
-	// Provision services if detected (only on first deploy)
-	if deploymentConfig != nil && len(deploymentConfig.Services) > 0 {
-		tasks = append(tasks, provisionServicesTask(client, projectCfg, deploymentConfig))
+	// Provision services and set variables if detected (only on first deploy)
+	if needsProvisioning(deploymentConfig) {
+		var provisioned []smart.ProvisionedService
+		tasks = append(tasks, provisionServicesTask(client, projectCfg, deploymentConfig, &provisioned))
+		if len(deploymentConfig.Services) > 0 {
+			tasks = append(tasks, waitForServicesTask(client, projectCfg, &provisioned))
+		}
+	}
+	if creatingApp && deploymentConfig != nil && len(deploymentConfig.ScheduledTasks) > 0 {
+		tasks = append(tasks, createScheduledTasksTask(client, projectCfg, deploymentConfig.ScheduledTasks))
 	}
 
 	// Trigger deployment
@@ -190,23 +250,24 @@ func setupEnvironmentTask(client *api.Client, projectCfg *config.ProjectConfig)
 		CompleteName: "✓ Set up environment",
 		Action: func() error {
 			// Fetch project to check for auto-created environments
+			name := projectCfg.EnvironmentName()
 			project, err := client.GetProject(projectCfg.ProjectUUID)
 			if err == nil {
 				for _, env := range project.Environments {
-					if strings.ToLower(env.Name) == "production" {
+					if strings.EqualFold(env.Name, name) {
 						projectCfg.EnvironmentUUID = env.UUID
 						break
 					}
 				}
 			}
 
-			// Create production environment if missing
+			// Create the environment if missing
 			if projectCfg.EnvironmentUUID == "" {
-				prodEnv, err := client.CreateEnvironment(projectCfg.ProjectUUID, "production")
+				created, err := client.CreateEnvironment(projectCfg.ProjectUUID, name)
 				if err != nil {
-					return fmt.Errorf("failed to create production environment: %w", err)
+					return fmt.Errorf("failed to create %s environment: %w", name, err)
 				}
-				projectCfg.EnvironmentUUID = prodEnv.UUID
+				projectCfg.EnvironmentUUID = created.UUID
 			}
 
 			return config.SaveProject(projectCfg)
@@ -221,10 +282,11 @@ func checkEnvironmentTask(client *api.Client, projectCfg *config.ProjectConfig)
 		CompleteName: "✓ Environment ready",
 		Action: func() error {
 			if projectCfg.EnvironmentUUID == "" {
+				name := projectCfg.EnvironmentName()
 				project, err := client.GetProject(projectCfg.ProjectUUID)
 				if err == nil {
 					for _, env := range project.Environments {
-						if strings.ToLower(env.Name) == "production" {
+						if strings.EqualFold(env.Name, name) {
 							projectCfg.EnvironmentUUID = env.UUID
 							break
 						}
@@ -233,12 +295,12 @@ func checkEnvironmentTask(client *api.Client, projectCfg *config.ProjectConfig)
 
 				// Create if still missing
 				if projectCfg.EnvironmentUUID == "" {
-					prodEnv, err := client.CreateEnvironment(projectCfg.ProjectUUID, "production")
+					created, err := client.CreateEnvironment(projectCfg.ProjectUUID, name)
 					if err != nil && !api.IsConflict(err) {
 						return err
 					}
-					if prodEnv != nil {
-						projectCfg.EnvironmentUUID = prodEnv.UUID
+					if created != nil {
+						projectCfg.EnvironmentUUID = created.UUID
 					}
 				}
 
@@ -255,15 +317,22 @@ func pushImageTask(globalCfg *config.GlobalConfig, projectCfg *config.ProjectCon
 		ActiveName:   "Pushing image to registry...",
 		CompleteName: "✓ Pushed image to registry",
 		Action: func() error {
-			err := docker.Push(&docker.PushOptions{
+			// Fetched right before pushing, as helper credentials expire
+			registry, err := registryLogin(globalCfg, projectCfg)
+			if err != nil {
+				return fmt.Errorf("failed to get registry credentials: %w", err)
+			}
+			opts := &docker.PushOptions{
 				ImageName: projectCfg.DockerImage,
 				Tag:       tag,
-				Registry:  globalCfg.DockerRegistry.URL,
-				Username:  globalCfg.DockerRegistry.Username,
-				Password:  globalCfg.DockerRegistry.Password,
 				Verbose:   verbose,
-			})
-			if err != nil {
+			}
+			if registry != nil {
+				opts.Registry = registry.URL
+				opts.Username = registry.Username
+				opts.Password = registry.Password
+			}
+			if err := docker.Push(opts); err != nil {
 				return fmt.Errorf("failed to push image %s:%s to registry: %w", projectCfg.DockerImage, tag, err)
 			}
 			return nil
@@ -286,6 +355,7 @@ func createDockerAppTask(client *api.Client, projectCfg *config.ProjectConfig, t
 				ProjectUUID:             projectCfg.ProjectUUID,
 				ServerUUID:              projectCfg.ServerUUID,
 				EnvironmentUUID:         projectCfg.EnvironmentUUID,
+				DestinationUUID:         projectCfg.DestinationUUID,
 				Name:                    projectCfg.Name,
 				DockerRegistryImageName: projectCfg.DockerImage,
 				DockerRegistryImageTag:  tag,
@@ -318,7 +388,10 @@ func triggerDeploymentTask(client *api.Client, projectCfg *config.ProjectConfig,
 			if err != nil {
 				return fmt.Errorf("failed to trigger deployment: %w", err)
 			}
-			return nil
+
+			// Remember the tag so 'rollback' can redeploy it later
+			projectCfg.RecordImageTag(tag)
+			return config.SaveProject(projectCfg)
 		},
 	}
 }
@@ -0,0 +1,92 @@
+package appdeploy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/detect"
+	"github.com/entro314-labs/cool-kit/internal/gitops"
+	"github.com/entro314-labs/cool-kit/internal/smart"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// collectMissingEnv lists the required variables the code reads, or a
+// compose stack interpolates, that the first deployment wouldn't set, and
+// asks for their values, so the app doesn't boot-loop on missing
+// configuration. compose is nil unless the project deploys as a stack.
+func collectMissingEnv(dir string, compose *detect.ComposeFile, deploymentConfig *smart.DeploymentConfig) error {
+	refs, err := smart.ScanEnvReferences(dir)
+	if err != nil {
+		// The scan is advisory; deploying without it is what happened before
+		ui.Dim(fmt.Sprintf("Skipped the environment variable scan: %v", err))
+	}
+	scanned := map[string]bool{}
+	for _, ref := range refs {
+		scanned[ref.Key] = true
+	}
+	for _, ref := range composeEnvReferences(compose) {
+		if !scanned[ref.Key] {
+			refs = append(refs, ref)
+		}
+	}
+	missing := deploymentConfig.MissingEnv(refs)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	ui.Spacer()
+	ui.Warning(fmt.Sprintf("%d required variable(s) aren't set:", len(missing)))
+	rows := make([][]string, 0, len(missing))
+	for _, ref := range missing {
+		rows = append(rows, []string{ref.Key, ref.File})
+	}
+	ui.Table([]string{"Variable", "Read in"}, rows)
+
+	setNow, err := ui.Ask("Set them now?", false)
+	if err != nil {
+		return err
+	}
+
+	var skipped []string
+	for _, ref := range missing {
+		value := ""
+		if setNow {
+			prompt := fmt.Sprintf("%s (empty to skip):", ref.Key)
+			if gitops.IsSecret(ref.Key, "") {
+				value, err = ui.Password(prompt)
+			} else {
+				value, err = ui.Input(prompt, "")
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if value == "" {
+			skipped = append(skipped, ref.Key)
+			continue
+		}
+		setEnvironment(deploymentConfig, ref.Key, value)
+	}
+
+	if len(skipped) > 0 {
+		ui.Dim(fmt.Sprintf("Not set: %s", strings.Join(skipped, ", ")))
+		ui.Dim("Add them before the app needs them with 'cool-kit env add KEY=value'")
+	}
+	return nil
+}
+
+// setEnvironment sets a variable's value, replacing the one from .env.example
+func setEnvironment(deploymentConfig *smart.DeploymentConfig, key, value string) {
+	for i := range deploymentConfig.Environment {
+		if deploymentConfig.Environment[i].Key == key {
+			deploymentConfig.Environment[i].Value = value
+			return
+		}
+	}
+	deploymentConfig.Environment = append(deploymentConfig.Environment, smart.EnvironmentVariable{
+		Key:         key,
+		Value:       value,
+		Description: "Entered during setup",
+		Secret:      gitops.IsSecret(key, value),
+	})
+}
@@ -0,0 +1,52 @@
+package appdeploy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/utils"
+)
+
+// execExitMarker prefixes the exit status line appended to the command output
+const execExitMarker = "__COOLKIT_EXIT_STATUS__="
+
+// ExecResult is the outcome of a command run in an application's container
+type ExecResult struct {
+	Output     string
+	ExitStatus int
+	// StatusKnown is false if the output didn't report the exit status,
+	// like when the command was killed
+	StatusKnown bool
+}
+
+// Exec runs a shell command line in the application's running container
+// through Coolify's execute-command API
+func Exec(ctx context.Context, client *api.Client, appUUID, command string) (*ExecResult, error) {
+	// The API only returns the output, so report the exit status in it
+	wrapped := fmt.Sprintf("sh -c %s 2>&1; echo %s$?", utils.ShellQuote(command), execExitMarker)
+
+	resp, err := client.ExecuteCommand(ctx, appUUID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute command: %w", err)
+	}
+
+	output, status, ok := splitExitStatus(resp.Response)
+	return &ExecResult{Output: output, ExitStatus: status, StatusKnown: ok}, nil
+}
+
+// splitExitStatus removes the exit status line from the command output
+func splitExitStatus(output string) (string, int, bool) {
+	i := strings.LastIndex(output, execExitMarker)
+	if i < 0 {
+		return output, 0, false
+	}
+
+	status, err := strconv.Atoi(strings.TrimSpace(output[i+len(execExitMarker):]))
+	if err != nil {
+		return output, 0, false
+	}
+	return output[:i], status, true
+}
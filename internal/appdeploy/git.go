@@ -2,6 +2,7 @@ package appdeploy
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
 	"github.com/entro314-labs/cool-kit/internal/config"
@@ -12,7 +13,23 @@ import (
 )
 
 // DeployGit handles Git-based deployments
-func DeployGit(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, prNumber int, verbose bool) error {
+func DeployGit(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, prNumber int, allowSecrets, verbose bool, watch WatchOptions) error {
+	if err := git.Check(); err != nil {
+		return err
+	}
+	if usesDeployKey(projectCfg) {
+		return deployWithKey(client, globalCfg, projectCfg, deploymentConfig, prNumber, allowSecrets, verbose, watch)
+	}
+
+	// Handle GitHub App selection (if needed); a deploy key needs no App
+	useDeployKey, err := handleGitHubAppSelection(client, projectCfg, verbose)
+	if err != nil {
+		return err
+	}
+	if useDeployKey {
+		return deployWithKey(client, globalCfg, projectCfg, deploymentConfig, prNumber, allowSecrets, verbose, watch)
+	}
+
 	ghClient := git.NewGitHubClient(globalCfg.GitHubToken)
 
 	// Get GitHub user
@@ -27,47 +44,73 @@ func DeployGit(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *c
 		return err
 	}
 
-	// Handle GitHub App selection (if needed)
-	if err := handleGitHubAppSelection(client, projectCfg, needsRepoCreation, verbose); err != nil {
-		return err
-	}
-
 	// Execute deployment tasks
 	ui.Spacer()
 	ui.Divider()
 
-	tasks := buildGitDeploymentTasks(client, ghClient, globalCfg, projectCfg, deploymentConfig, user.Login, needsRepoCreation, verbose)
+	tasks := buildGitDeploymentTasks(client, ghClient, globalCfg, projectCfg, deploymentConfig, user.Login, needsRepoCreation, prNumber, allowSecrets, verbose)
 
 	if err := ui.RunTasksVerbose(tasks, verbose); err != nil {
 		ui.Error("Deployment setup failed")
 		return err
 	}
 
+	return finishGitDeployment(client, projectCfg, deploymentConfig, prNumber, watch, ghClient, fmt.Sprintf("%s/%s", user.Login, projectCfg.GitHubRepo))
+}
+
+// finishGitDeployment watches the triggered deployment, then runs the
+// migrations and smoke tests. A deployment from GitHub, when ghClient is
+// set, is reported on the commit of the repository at ghRepo.
+func finishGitDeployment(client *api.Client, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, prNumber int, watch WatchOptions, ghClient *git.GitHubClient, ghRepo string) error {
+	// Nothing would report the end of a deployment that isn't watched
+	if ghClient != nil && !watch.NoWait {
+		watch.gitHub = newGitHubStatus(ghClient, client, projectCfg, ghRepo, prNumber)
+	}
+
 	// Watch deployment
 	ui.Info("Watching deployment...")
 
-	success := WatchDeployment(client, projectCfg.AppUUID)
-
-	if !success {
+	if err := Watch(client, projectCfg.AppUUID, watch); err != nil {
 		ui.Error("Deployment failed")
 		ui.Spacer()
 		ui.NextSteps([]string{
 			"Run 'cdp logs' to view deployment logs",
 			"Check the Coolify dashboard for more details",
 		})
-		return fmt.Errorf("deployment failed")
+		return err
+	}
+	if watch.NoWait {
+		return nil
 	}
 
 	// Get app info for URL
 	ui.Success("Deployment complete")
 
+	var url string
 	app, err := client.GetApplication(projectCfg.AppUUID)
-	if err == nil && app.Fqdn != nil && *app.Fqdn != "" {
-		ui.Spacer()
-		ui.KeyValue("URL", ui.InfoStyle.Render(*app.Fqdn))
+	if err == nil {
+		url = app.GetFqdn()
+		if prNumber > 0 {
+			url = app.PreviewURL(prNumber)
+		}
+		if url != "" {
+			ui.Spacer()
+			ui.KeyValue("URL", ui.InfoStyle.Render(url))
+		} else if domains := app.ComposeDomains(); prNumber == 0 && len(domains) > 0 {
+			ui.Spacer()
+			for _, d := range composeDomainRequests(domains) {
+				ui.KeyValue(d.Name, ui.InfoStyle.Render(d.Domain))
+			}
+		}
+		if prNumber == 0 {
+			url = app.PrimaryURL()
+		}
 	}
 
-	return nil
+	if err := runMigrations(client, projectCfg.AppUUID, projectCfg, deploymentConfig, prNumber, watch); err != nil {
+		return err
+	}
+	return runSmokeTests(client, projectCfg, url, prNumber, watch)
 }
 
 func getGitHubUser(ghClient *git.GitHubClient, verbose bool) (*git.User, error) {
@@ -98,9 +141,6 @@ func handleGitHubRepoSetup(ghClient *git.GitHubClient, projectCfg *config.Projec
 
 	// Show section header
 	ui.Spacer()
-	ui.Divider()
-	ui.Bold("Git Deployment")
-	ui.Spacer()
 	ui.Bold("GitHub Repository Setup")
 	ui.Spacer()
 
@@ -115,7 +155,7 @@ func handleGitHubRepoSetup(ghClient *git.GitHubClient, projectCfg *config.Projec
 
 	// Ask for visibility
 	visibilityOptions := []string{"Private", "Public"}
-	visibility, err := ui.Select("Repository visibility:", visibilityOptions)
+	visibility, err := ui.SelectDefault("Repository visibility:", visibilityOptions, "Private")
 	if err != nil {
 		return err
 	}
@@ -125,20 +165,19 @@ func handleGitHubRepoSetup(ghClient *git.GitHubClient, projectCfg *config.Projec
 	return nil
 }
 
-func handleGitHubAppSelection(client *api.Client, projectCfg *config.ProjectConfig, needsRepoCreation bool, verbose bool) error {
+// handleGitHubAppSelection asks which GitHub App Coolify clones the
+// repository through, or whether it uses a deploy key instead, which needs no
+// admin rights on the organization. It reports whether a deploy key was picked.
+func handleGitHubAppSelection(client *api.Client, projectCfg *config.ProjectConfig, verbose bool) (bool, error) {
 	// Use saved GitHub App if available
 	if projectCfg.GitHubAppUUID != "" {
-		return nil
+		return false, nil
 	}
 
-	// Show section header if not already shown
-	if !needsRepoCreation {
-		ui.Spacer()
-		ui.Divider()
-		ui.Bold("Git Deployment")
-	} else {
-		ui.Spacer()
-	}
+	// Show section header
+	ui.Spacer()
+	ui.Divider()
+	ui.Bold("Git Deployment")
 
 	// Load GitHub Apps
 	var githubApps []api.GitHubApp
@@ -149,7 +188,7 @@ func handleGitHubAppSelection(client *api.Client, projectCfg *config.ProjectConf
 			CompleteName: "✓ Loaded GitHub Apps",
 			Action: func() error {
 				var err error
-				githubApps, err = client.ListGitHubApps()
+				githubApps, err = listGitHubApps(client)
 				return err
 			},
 		},
@@ -158,37 +197,35 @@ func handleGitHubAppSelection(client *api.Client, projectCfg *config.ProjectConf
 		ui.Error("Failed to load GitHub Apps")
 		ui.Spacer()
 		ui.Dim("Configure a GitHub App in Coolify: Sources → GitHub App")
-		return fmt.Errorf("failed to list GitHub Apps: %w", err)
+		return false, fmt.Errorf("failed to list GitHub Apps: %w", err)
 	}
 
 	if len(githubApps) == 0 {
-		ui.Error("No GitHub Apps configured in Coolify")
-		ui.Spacer()
-		ui.Dim("Add a GitHub App in Coolify: Sources → GitHub App")
-		return fmt.Errorf("no GitHub Apps configured")
-	}
-
-	// Select GitHub App
-	var githubAppUUID, selectedAppName string
-	if len(githubApps) == 1 {
-		githubAppUUID = githubApps[0].UUID
-		selectedAppName = githubApps[0].Name
-	} else {
-		appOptions := make(map[string]string)
-		for _, app := range githubApps {
-			displayName := app.Name
-			if app.Organization != "" {
-				displayName = fmt.Sprintf("%s (%s)", app.Name, app.Organization)
-			}
-			appOptions[app.UUID] = displayName
-		}
-		githubAppUUID, err = ui.SelectWithKeys("Select GitHub App:", appOptions)
-		if err != nil {
-			return err
+		ui.Dim("No GitHub Apps configured in Coolify, so it will clone with a deploy key")
+		return true, useGitHubDeployKey(projectCfg)
+	}
+
+	// Select GitHub App, or a deploy key
+	options := make([]string, 0, len(githubApps)+1)
+	appUUIDs := make(map[string]string)
+	for _, app := range githubApps {
+		displayName := app.Name
+		if app.Organization != "" {
+			displayName = fmt.Sprintf("%s (%s)", app.Name, app.Organization)
 		}
-		selectedAppName = appOptions[githubAppUUID]
+		options = append(options, displayName)
+		appUUIDs[displayName] = app.UUID
+	}
+	options = append(options, useDeployKeyOption)
+	selectedAppName, err := ui.SelectDefault("Clone the repository with:", options, options[0])
+	if err != nil {
+		return false, err
 	}
 	ui.Dim(fmt.Sprintf("→ %s", selectedAppName))
+	if selectedAppName == useDeployKeyOption {
+		return true, useGitHubDeployKey(projectCfg)
+	}
+	githubAppUUID := appUUIDs[selectedAppName]
 
 	// Save the selected GitHub App UUID
 	projectCfg.GitHubAppUUID = githubAppUUID
@@ -197,7 +234,19 @@ func handleGitHubAppSelection(client *api.Client, projectCfg *config.ProjectConf
 		ui.Warning("Failed to save GitHub App selection")
 	}
 
-	return nil
+	return false, nil
+}
+
+// useDeployKeyOption is the GitHub App option that uses a deploy key instead
+const useDeployKeyOption = "A deploy key (no GitHub App)"
+
+// useGitHubDeployKey switches the project to the deploy key flow, which
+// keeps the repository in GitRepo like the other hosts
+func useGitHubDeployKey(projectCfg *config.ProjectConfig) error {
+	projectCfg.GitHubDeployKey = true
+	projectCfg.GitRepo = projectCfg.GitHubRepo
+	projectCfg.GitHubRepo = ""
+	return config.SaveProject(projectCfg)
 }
 
 func buildGitDeploymentTasks(
@@ -208,18 +257,11 @@ func buildGitDeploymentTasks(
 	deploymentConfig *smart.DeploymentConfig,
 	username string,
 	needsRepoCreation bool,
+	prNumber int,
+	allowSecrets bool,
 	verbose bool,
 ) []ui.Task {
-	tasks := []ui.Task{}
-
-	// Create project and environment if needed
-	needsProjectCreation := projectCfg.ProjectUUID == ""
-	if needsProjectCreation {
-		tasks = append(tasks, createProjectTask(client, projectCfg))
-		tasks = append(tasks, setupEnvironmentTask(client, projectCfg))
-	} else {
-		tasks = append(tasks, checkEnvironmentTask(client, projectCfg))
-	}
+	tasks := coolifyProjectTasks(client, projectCfg)
 
 	// Create GitHub repo if needed
 	if needsRepoCreation {
@@ -232,20 +274,66 @@ func buildGitDeploymentTasks(
 	}
 
 	// Push code to GitHub
-	tasks = append(tasks, pushCodeTask(ghClient, globalCfg, projectCfg, username, verbose))
+	tasks = append(tasks, pushCodeTask(ghClient, globalCfg, projectCfg, username, allowSecrets, verbose))
+
+	repository := fmt.Sprintf("%s/%s", username, projectCfg.GitHubRepo)
+	return append(tasks, gitAppTasks(client, projectCfg, deploymentConfig, repository, prNumber, nil)...)
+}
+
+// coolifyProjectTasks create the project's Coolify project and environment,
+// or check the environment still exists
+func coolifyProjectTasks(client *api.Client, projectCfg *config.ProjectConfig) []ui.Task {
+	if projectCfg.ProjectUUID == "" {
+		return []ui.Task{
+			createProjectTask(client, projectCfg),
+			setupEnvironmentTask(client, projectCfg),
+		}
+	}
+	return []ui.Task{checkEnvironmentTask(client, projectCfg)}
+}
+
+// gitAppTasks create the application and its workers from repository if
+// needed, run the first-deploy setup and trigger the deployment. afterCreate
+// runs once the application is created, like a git host's webhook setup.
+func gitAppTasks(client *api.Client, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, repository string, prNumber int, afterCreate []ui.Task) []ui.Task {
+	var tasks []ui.Task
 
 	// Create Coolify app if needed
-	if projectCfg.AppUUID == "" {
-		tasks = append(tasks, createGitAppTask(client, projectCfg, username))
+	creatingApp := projectCfg.AppUUID == ""
+	if creatingApp {
+		tasks = append(tasks, createGitAppTask(client, projectCfg, repository))
+		tasks = append(tasks, afterCreate...)
+	}
+	if len(workerUUIDs(projectCfg)) < len(projectCfg.Workers) {
+		tasks = append(tasks, createWorkerAppsTask(client, projectCfg, repository))
+	}
+
+	// Pin the runtime version detected at setup; later changes are the user's
+	if creatingApp && deploymentConfig != nil && deploymentConfig.Framework != nil {
+		if env := deploymentConfig.Framework.BuildEnv(); len(env) > 0 {
+			tasks = append(tasks, setBuildEnvTask(client, projectCfg, env))
+		}
 	}
 
-	// Provision services if detected (only on first deploy)
-	if deploymentConfig != nil && len(deploymentConfig.Services) > 0 {
-		tasks = append(tasks, provisionServicesTask(client, projectCfg, deploymentConfig))
+	// Provision services and set variables if detected (only on first deploy)
+	if needsProvisioning(deploymentConfig) {
+		var provisioned []smart.ProvisionedService
+		tasks = append(tasks, provisionServicesTask(client, projectCfg, deploymentConfig, &provisioned))
+		if len(deploymentConfig.Services) > 0 {
+			tasks = append(tasks, waitForServicesTask(client, projectCfg, &provisioned))
+		}
+	}
+	if creatingApp && deploymentConfig != nil && len(deploymentConfig.ScheduledTasks) > 0 {
+		tasks = append(tasks, createScheduledTasksTask(client, projectCfg, deploymentConfig.ScheduledTasks))
 	}
 
 	// Trigger deployment
-	tasks = append(tasks, triggerGitDeploymentTask(client, projectCfg))
+	tasks = append(tasks, triggerGitDeploymentTask(client, projectCfg, prNumber))
+
+	// Previews only deploy the application itself
+	if prNumber == 0 && len(projectCfg.Workers) > 0 {
+		tasks = append(tasks, triggerWorkerDeploymentsTask(client, projectCfg))
+	}
 
 	return tasks
 }
@@ -289,7 +377,7 @@ func initGitTask() ui.Task {
 	}
 }
 
-func pushCodeTask(ghClient *git.GitHubClient, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, username string, verbose bool) ui.Task {
+func pushCodeTask(ghClient *git.GitHubClient, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, username string, allowSecrets, verbose bool) ui.Task {
 	return ui.Task{
 		Name:         "push-code",
 		ActiveName:   "Pushing code to GitHub...",
@@ -303,91 +391,68 @@ func pushCodeTask(ghClient *git.GitHubClient, globalCfg *config.GlobalConfig, pr
 				return fmt.Errorf("failed to configure git remote: %w", err)
 			}
 
-			// Auto-commit any changes
-			if err := git.AutoCommitVerbose(".", verbose); err != nil {
-				ui.Dim(fmt.Sprintf("Warning: Failed to auto-commit: %v", err))
-			}
+			return commitAndPush(globalCfg, projectCfg, allowSecrets, verbose)
+		},
+	}
+}
 
-			// Determine branch
-			branch := projectCfg.Branch
-			if branch == "" {
-				b, err := git.GetCurrentBranch(".")
-				if err != nil {
-					ui.Dim(fmt.Sprintf("Warning: Failed to get current branch: %v", err))
-				}
-				if b == "" {
-					branch = config.DefaultBranch
-				} else {
-					branch = b
-				}
-			}
+// commitAndPush commits the working tree and pushes the project's branch to
+// origin, blocking credentials first unless allowSecrets is set
+func commitAndPush(globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, allowSecrets, verbose bool) error {
+	// Everything in the working tree is auto-committed, so block credentials first
+	if !allowSecrets {
+		if err := checkForSecrets(".", projectCfg); err != nil {
+			return err
+		}
+	}
 
-			// Use secure token-based authentication
-			return git.PushWithTokenVerbose(".", "origin", branch, globalCfg.GitHubToken, verbose)
-		},
+	// Auto-commit any changes
+	if err := git.AutoCommitVerbose(".", verbose); err != nil {
+		ui.Dim(fmt.Sprintf("Warning: Failed to auto-commit: %v", err))
 	}
+
+	// Determine branch
+	branch := projectCfg.Branch
+	if branch == "" {
+		b, err := git.GetCurrentBranch(".")
+		if err != nil {
+			ui.Dim(fmt.Sprintf("Warning: Failed to get current branch: %v", err))
+		}
+		if b == "" {
+			branch = config.DefaultBranch
+		} else {
+			branch = b
+		}
+	}
+
+	// Use secure token-based authentication
+	return pushWithToken(globalCfg, projectCfg, branch, verbose)
 }
 
-func createGitAppTask(client *api.Client, projectCfg *config.ProjectConfig, username string) ui.Task {
+// pushWithToken pushes refspec to origin, logged in to the project's git host
+// with its token
+func pushWithToken(globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, refspec string, verbose bool) error {
+	switch projectCfg.GitProvider {
+	case config.GitProviderGitLab:
+		return git.PushWithLoginVerbose(".", "origin", refspec, "oauth2", globalCfg.GitLabToken, verbose)
+	case config.GitProviderGitea:
+		// Gitea takes a token as the username with this placeholder password
+		return git.PushWithLoginVerbose(".", "origin", refspec, globalCfg.GiteaToken, "x-oauth-basic", verbose)
+	case config.GitProviderBitbucket:
+		return git.PushWithLoginVerbose(".", "origin", refspec, globalCfg.BitbucketUser, globalCfg.BitbucketToken, verbose)
+	}
+	return git.PushWithTokenVerbose(".", "origin", refspec, globalCfg.GitHubToken, verbose)
+}
+
+func createGitAppTask(client *api.Client, projectCfg *config.ProjectConfig, repository string) ui.Task {
 	return ui.Task{
 		Name:         "create-app",
 		ActiveName:   "Creating Coolify application...",
 		CompleteName: "✓ Created Coolify application",
 		Action: func() error {
-			buildPack := projectCfg.BuildPack
-			if buildPack == "" {
-				buildPack = detect.BuildPackNixpacks
-			}
-
-			port := projectCfg.Port
-			if port == "" {
-				port = config.DefaultPort
-			}
-
-			branch := projectCfg.Branch
-			if branch == "" {
-				b, err := git.GetCurrentBranch(".")
-				if err != nil {
-					ui.Dim(fmt.Sprintf("Warning: Failed to get current branch: %v", err))
-				}
-				if b == "" {
-					branch = config.DefaultBranch
-				} else {
-					branch = b
-				}
-			}
-
-			fullRepoName := fmt.Sprintf("%s/%s", username, projectCfg.GitHubRepo)
-
-			// Use Coolify's static site feature for static builds
-			isStatic := buildPack == detect.BuildPackStatic
-
-			// Enable health check for static sites
-			healthCheckEnabled := isStatic
-			healthCheckPath := "/"
-
-			resp, err := client.CreatePrivateGitHubApp(&api.CreatePrivateGitHubAppRequest{
-				ProjectUUID:        projectCfg.ProjectUUID,
-				ServerUUID:         projectCfg.ServerUUID,
-				EnvironmentUUID:    projectCfg.EnvironmentUUID,
-				GitHubAppUUID:      projectCfg.GitHubAppUUID,
-				GitRepository:      fullRepoName,
-				GitBranch:          branch,
-				Name:               projectCfg.Name,
-				BuildPack:          buildPack,
-				IsStatic:           isStatic,
-				Domains:            projectCfg.Domain,
-				InstallCommand:     projectCfg.InstallCommand,
-				BuildCommand:       projectCfg.BuildCommand,
-				StartCommand:       projectCfg.StartCommand,
-				PublishDirectory:   projectCfg.PublishDir,
-				PortsExposes:       port,
-				HealthCheckEnabled: healthCheckEnabled,
-				HealthCheckPath:    healthCheckPath,
-				InstantDeploy:      false,
-			})
+			resp, err := createGitApp(client, projectCfg, gitAppRequest(projectCfg, repository))
 			if err != nil {
-				return fmt.Errorf("failed to create Coolify application %q with GitHub integration: %w", projectCfg.Name, err)
+				return fmt.Errorf("failed to create Coolify application %q: %w", projectCfg.Name, err)
 			}
 			projectCfg.AppUUID = resp.UUID
 
@@ -396,13 +461,103 @@ func createGitAppTask(client *api.Client, projectCfg *config.ProjectConfig, user
 	}
 }
 
-func triggerGitDeploymentTask(client *api.Client, projectCfg *config.ProjectConfig) ui.Task {
+// createGitApp creates an application from req's repository, through the
+// project's GitHub App or, on other hosts, its deploy key
+func createGitApp(client *api.Client, projectCfg *config.ProjectConfig, req *api.CreatePrivateGitHubAppRequest) (*api.CreateAppResponse, error) {
+	if usesDeployKey(projectCfg) {
+		req.PrivateKeyUUID = projectCfg.PrivateKeyUUID
+		return client.CreatePrivateDeployKeyApp(req)
+	}
+	req.GitHubAppUUID = projectCfg.GitHubAppUUID
+	return client.CreatePrivateGitHubApp(req)
+}
+
+// gitAppRequest returns the request that creates the project's application
+// from repository, "owner/name" on GitHub or a clone URL elsewhere
+func gitAppRequest(projectCfg *config.ProjectConfig, repository string) *api.CreatePrivateGitHubAppRequest {
+	buildPack := projectCfg.BuildPack
+	if buildPack == "" {
+		buildPack = detect.BuildPackNixpacks
+	}
+
+	port := projectCfg.Port
+	if port == "" {
+		port = config.DefaultPort
+	}
+
+	branch := projectCfg.Branch
+	if branch == "" {
+		b, err := git.GetCurrentBranch(".")
+		if err != nil {
+			ui.Dim(fmt.Sprintf("Warning: Failed to get current branch: %v", err))
+		}
+		if b == "" {
+			branch = config.DefaultBranch
+		} else {
+			branch = b
+		}
+	}
+
+	// Use Coolify's static site feature for static builds
+	isStatic := buildPack == detect.BuildPackStatic
+
+	// Enable health check for static sites
+	healthCheckEnabled := isStatic
+	healthCheckPath := "/"
+
+	req := &api.CreatePrivateGitHubAppRequest{
+		ProjectUUID:        projectCfg.ProjectUUID,
+		ServerUUID:         projectCfg.ServerUUID,
+		EnvironmentUUID:    projectCfg.EnvironmentUUID,
+		DestinationUUID:    projectCfg.DestinationUUID,
+		GitRepository:      repository,
+		GitBranch:          branch,
+		Name:               projectCfg.Name,
+		BuildPack:          buildPack,
+		IsStatic:           isStatic,
+		Domains:            projectCfg.Domain,
+		InstallCommand:     projectCfg.InstallCommand,
+		BuildCommand:       projectCfg.BuildCommand,
+		StartCommand:       projectCfg.StartCommand,
+		PublishDirectory:   projectCfg.PublishDir,
+		BaseDirectory:      baseDirectory(projectCfg),
+		WatchPaths:         strings.Join(projectCfg.WatchPaths, "\n"),
+		PortsExposes:       port,
+		HealthCheckEnabled: healthCheckEnabled,
+		HealthCheckPath:    healthCheckPath,
+		InstantDeploy:      false,
+	}
+
+	// A compose stack's domains are its services'
+	if buildPack == detect.BuildPackDockerCompose {
+		req.Domains = ""
+		req.DockerComposeLocation = composeLocation(projectCfg)
+		if projectCfg.Compose != nil {
+			req.DockerComposeDomains = composeDomainRequests(projectCfg.Compose.Domains)
+		}
+	}
+	return req
+}
+
+func triggerGitDeploymentTask(client *api.Client, projectCfg *config.ProjectConfig, prNumber int) ui.Task {
+	activeName, completeName := "Triggering deployment...", "✓ Triggered deployment"
+	if prNumber > 0 {
+		activeName = fmt.Sprintf("Triggering preview deployment for PR #%d...", prNumber)
+		completeName = fmt.Sprintf("✓ Triggered preview deployment for PR #%d", prNumber)
+	}
+
 	return ui.Task{
 		Name:         "trigger-deploy",
-		ActiveName:   "Triggering deployment...",
-		CompleteName: "✓ Triggered deployment",
+		ActiveName:   activeName,
+		CompleteName: completeName,
 		Action: func() error {
-			_, err := client.Deploy(projectCfg.AppUUID, false, 0)
+			if prNumber == 0 {
+				if err := client.UnpinCommit(projectCfg.AppUUID); err != nil {
+					return err
+				}
+			}
+
+			_, err := client.Deploy(projectCfg.AppUUID, false, prNumber)
 			if err != nil {
 				return fmt.Errorf("failed to trigger deployment: %w", err)
 			}
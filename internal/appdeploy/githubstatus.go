@@ -0,0 +1,199 @@
+package appdeploy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// gitHubStatusContext names the commit status on GitHub
+const gitHubStatusContext = "coolify"
+
+// previewCommentMarker marks the pull request comment updated with the
+// preview's status, so later deployments find it
+const previewCommentMarker = "<!-- cool-kit preview -->"
+
+// gitHubStatus reports a deployment's progress back to GitHub, as a GitHub
+// deployment and a commit status, so branches and pull requests show it, and
+// for a pull request preview as a comment on the pull request too. Its
+// methods do nothing on a nil gitHubStatus. The deployment doesn't need it,
+// so a failing report prints a warning and stops that kind of report.
+type gitHubStatus struct {
+	client      *git.GitHubClient
+	owner, name string
+	sha         string
+	id          int64  // of the GitHub deployment
+	url         string // the application's, or its pull request preview's
+	state       string // last reported
+
+	statusFailed  bool
+	pr            int   // the preview's pull request, or 0
+	commentID     int64 // of the preview comment, once there is one
+	commentFailed bool
+}
+
+// newGitHubStatus creates a GitHub deployment of the local HEAD in the
+// repository at path, like owner/name, and finds the preview comment of
+// pull request prNumber. It returns nil when it has nothing to report to.
+func newGitHubStatus(ghClient *git.GitHubClient, client *api.Client, projectCfg *config.ProjectConfig, path string, prNumber int) *gitHubStatus {
+	commit, err := git.GetCommitInfo(".", "HEAD")
+	if err != nil {
+		return nil
+	}
+	owner, name, _ := strings.Cut(path, "/")
+	s := &gitHubStatus{client: ghClient, owner: owner, name: name, sha: commit.Hash, pr: prNumber}
+	if app, err := client.GetApplication(projectCfg.AppUUID); err == nil {
+		s.url = app.PrimaryURL()
+		if prNumber > 0 {
+			s.url = app.PreviewURL(prNumber)
+		}
+	}
+
+	environment := projectCfg.EnvironmentName()
+	if prNumber > 0 {
+		environment = fmt.Sprintf("pr-%d", prNumber)
+	}
+	deployment, err := ghClient.CreateDeployment(owner, name, &git.DeploymentRequest{
+		Ref:         s.sha,
+		Environment: environment,
+		Description: "Deployed to Coolify by cool-kit",
+		Transient:   prNumber > 0,
+		Production:  environment == config.ProductionEnvironment,
+	})
+	if err != nil {
+		ui.Dim(fmt.Sprintf("Warning: Failed to create GitHub deployment: %v", err))
+		if prNumber == 0 {
+			return nil
+		}
+		s.statusFailed = true
+	} else {
+		s.id = deployment.ID
+	}
+
+	if prNumber > 0 {
+		comments, err := ghClient.ListIssueComments(owner, name, prNumber)
+		if err != nil {
+			ui.Dim(fmt.Sprintf("Warning: Failed to load pull request comments: %v", err))
+			s.commentFailed = true
+		}
+		for _, c := range comments {
+			if strings.HasPrefix(c.Body, previewCommentMarker) {
+				s.commentID = c.ID
+				break
+			}
+		}
+	}
+	return s
+}
+
+// report sends a Coolify deployment status to GitHub when it changes
+func (s *gitHubStatus) report(status string) {
+	if s == nil {
+		return
+	}
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "queued":
+		s.set("queued", "pending", "Queued in Coolify")
+	case "running", "in_progress":
+		s.set("in_progress", "pending", "Building in Coolify")
+	case "finished":
+		s.set("success", "success", "Deployed")
+	case "failed", "error":
+		s.set("failure", "failure", "Deployment failed")
+	case "cancelled":
+		s.set("error", "error", "Deployment cancelled")
+	}
+}
+
+// fail reports a deployment that stopped being watched before it finished
+func (s *gitHubStatus) fail(err error) {
+	if s == nil {
+		return
+	}
+	switch s.state {
+	case "success", "failure", "error":
+		return
+	}
+	// GitHub limits descriptions to 140 characters
+	description := err.Error()
+	if len(description) > 140 {
+		description = description[:137] + "..."
+	}
+	s.set("error", "error", description)
+}
+
+func (s *gitHubStatus) set(state, commitState, description string) {
+	if state == s.state {
+		return
+	}
+	s.state = state
+
+	if !s.statusFailed {
+		var environmentURL string
+		if state == "success" {
+			environmentURL = s.url
+		}
+		err := s.client.CreateDeploymentStatus(s.owner, s.name, s.id, state, environmentURL, description)
+		if err == nil {
+			err = s.client.CreateCommitStatus(s.owner, s.name, s.sha, commitState, s.url, description, gitHubStatusContext)
+		}
+		if err != nil {
+			ui.Dim(fmt.Sprintf("Warning: Failed to report deployment status to GitHub: %v", err))
+			s.statusFailed = true
+		}
+	}
+
+	if s.pr > 0 && !s.commentFailed {
+		if err := s.comment(state, description); err != nil {
+			ui.Dim(fmt.Sprintf("Warning: Failed to comment on pull request #%d: %v", s.pr, err))
+			s.commentFailed = true
+		}
+	}
+}
+
+// comment posts the preview's status on its pull request, or updates the
+// comment posted by an earlier deployment
+func (s *gitHubStatus) comment(state, description string) error {
+	body := previewComment(state, description, s.url, s.sha)
+	if s.commentID != 0 {
+		return s.client.UpdateIssueComment(s.owner, s.name, s.commentID, body)
+	}
+	c, err := s.client.CreateIssueComment(s.owner, s.name, s.pr, body)
+	if err != nil {
+		return err
+	}
+	s.commentID = c.ID
+	return nil
+}
+
+// previewComment renders the pull request comment of a preview deployment
+func previewComment(state, description, url, sha string) string {
+	icon := "⏳"
+	switch state {
+	case "success":
+		icon = "✅"
+	case "failure", "error":
+		icon = "❌"
+	}
+	preview := url
+	if preview == "" {
+		preview = "No domain set in Coolify"
+	} else if state != "success" {
+		preview += " (once deployed)"
+	}
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+
+	var b strings.Builder
+	b.WriteString(previewCommentMarker + "\n")
+	b.WriteString("**Coolify preview**\n\n")
+	b.WriteString("| Status | Preview | Commit |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	fmt.Fprintf(&b, "| %s %s | %s | `%s` |\n", icon, description, preview, sha)
+	return b.String()
+}
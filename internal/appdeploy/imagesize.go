@@ -0,0 +1,70 @@
+package appdeploy
+
+import (
+	"fmt"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/docker"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// reportImageSize prints the built image's layers by size, what changed
+// since the previous tag when it's still available locally, and the build
+// steps likely to bloat it. The deployment doesn't need it, so failures
+// only print a warning.
+func reportImageSize(projectCfg *config.ProjectConfig, tag, previousTag string) {
+	layers, err := docker.History(projectCfg.DockerImage, tag)
+	if err != nil {
+		ui.Dim(fmt.Sprintf("Warning: Failed to read image layers: %v", err))
+		return
+	}
+	var previous []docker.Layer
+	if previousTag != "" && previousTag != tag {
+		previous, _ = docker.History(projectCfg.DockerImage, previousTag)
+	}
+	// Layers the previous image had too, by build step, matched once each
+	unmatched := map[string][]int64{}
+	for _, l := range previous {
+		unmatched[l.CreatedBy] = append(unmatched[l.CreatedBy], l.Size)
+	}
+
+	var rows [][]string
+	for _, l := range layers {
+		if l.Size == 0 {
+			continue
+		}
+		change := ""
+		if previous != nil {
+			change = "new"
+			if sizes := unmatched[l.CreatedBy]; len(sizes) > 0 {
+				change = sizeChange(l.Size - sizes[0])
+				unmatched[l.CreatedBy] = sizes[1:]
+			}
+		}
+		rows = append(rows, []string{docker.FormatSize(l.Size), change, docker.TruncateStep(l.CreatedBy, 70)})
+	}
+
+	ui.Section("Image Size")
+	ui.Table([]string{"SIZE", "CHANGE", "STEP"}, rows)
+	total := docker.TotalSize(layers)
+	if previous != nil {
+		ui.KeyValue("Total", fmt.Sprintf("%s (%s since %s)", docker.FormatSize(total), sizeChange(total-docker.TotalSize(previous)), previousTag))
+	} else {
+		ui.KeyValue("Total", docker.FormatSize(total))
+	}
+	for _, w := range docker.BloatWarnings(layers) {
+		ui.Warning(w)
+	}
+	ui.Spacer()
+}
+
+// sizeChange formats the difference between two sizes, like "+1.2 MB"
+func sizeChange(delta int64) string {
+	switch {
+	case delta > 0:
+		return "+" + docker.FormatSize(delta)
+	case delta < 0:
+		return "-" + docker.FormatSize(-delta)
+	}
+	return "same"
+}
@@ -0,0 +1,221 @@
+package appdeploy
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/smart"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// LocalBranch is the branch local snapshots of Git projects are pushed to
+const LocalBranch = "cdp-local"
+
+// DeployLocal deploys the working directory as it is, without committing to
+// or pushing the current branch. Docker projects are built from a copy of the
+// files git would push, so ignored files stay out of the build context. Git
+// projects push a parentless snapshot commit to LocalBranch and deploy that
+// branch once, then point the application back at its own branch.
+func DeployLocal(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, prNumber int, allowSecrets, verbose bool, watch WatchOptions) error {
+	if config.BuildsImage(projectCfg.DeployMethod) {
+		contextDir, cleanup, err := stageBuildContext(projectCfg.SourceDir())
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		return deployDocker(client, globalCfg, projectCfg, deploymentConfig, prNumber, contextDir, verbose, watch)
+	}
+
+	if prNumber > 0 {
+		return fmt.Errorf("local deployments of Git projects can't be previews")
+	}
+	if projectCfg.AppUUID == "" {
+		return fmt.Errorf("local deployments of Git projects need an existing application: deploy once without --local")
+	}
+	return deploySnapshot(client, globalCfg, projectCfg, allowSecrets, verbose, watch)
+}
+
+// stageBuildContext copies the files git would push from dir into a temporary
+// directory. Directories that are not git repositories are used as they are.
+func stageBuildContext(dir string) (string, func(), error) {
+	files, err := git.ListPushableFiles(dir)
+	if err != nil {
+		return dir, func() {}, nil
+	}
+
+	contextDir, err := os.MkdirTemp("", "cdp-context-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create build context: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(contextDir) }
+
+	err = ui.RunTasks([]ui.Task{
+		{
+			Name:         "stage-context",
+			ActiveName:   "Packaging working directory...",
+			CompleteName: fmt.Sprintf("✓ Packaged %d files", len(files)),
+			Action: func() error {
+				for _, f := range files {
+					if err := copyContextFile(filepath.Join(dir, f), filepath.Join(contextDir, f)); err != nil {
+						return fmt.Errorf("failed to copy %s: %w", f, err)
+					}
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		cleanup()
+		ui.Error("Failed to package working directory")
+		return "", nil, err
+	}
+	return contextDir, cleanup, nil
+}
+
+// copyContextFile copies a file or symlink, skipping tracked files that were deleted
+func copyContextFile(src, dst string) error {
+	info, err := os.Lstat(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// deploySnapshot pushes the working tree to LocalBranch and deploys it
+func deploySnapshot(client *api.Client, globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig, allowSecrets, verbose bool, watch WatchOptions) error {
+	// Snapshots are committed through a temporary index, with the CLI
+	if err := git.CheckCLI(); err != nil {
+		return err
+	}
+	app, err := client.GetApplication(projectCfg.AppUUID)
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+	branch := app.GitBranch
+
+	ui.Spacer()
+	ui.Divider()
+
+	var commit string
+	tasks := []ui.Task{
+		{
+			Name:         "snapshot",
+			ActiveName:   "Snapshotting working directory...",
+			CompleteName: "✓ Snapshotted working directory",
+			Action: func() error {
+				if !allowSecrets {
+					if err := checkForSecrets(".", projectCfg); err != nil {
+						return err
+					}
+				}
+				var err error
+				commit, err = git.SnapshotCommit(".", "Local deploy via cdp")
+				return err
+			},
+		},
+		{
+			Name:         "push-snapshot",
+			ActiveName:   fmt.Sprintf("Pushing snapshot to %s...", LocalBranch),
+			CompleteName: fmt.Sprintf("✓ Pushed snapshot to %s", LocalBranch),
+			Action: func() error {
+				// Snapshots have no parent, so the branch is always replaced
+				refspec := fmt.Sprintf("+%s:refs/heads/%s", commit, LocalBranch)
+				return pushWithToken(globalCfg, projectCfg, refspec, verbose)
+			},
+		},
+		{
+			Name:         "trigger-deploy",
+			ActiveName:   "Triggering deployment...",
+			CompleteName: "✓ Triggered deployment",
+			Action: func() error {
+				if err := client.UnpinCommit(projectCfg.AppUUID); err != nil {
+					return err
+				}
+				if err := client.UpdateApplication(projectCfg.AppUUID, map[string]interface{}{
+					"git_branch": LocalBranch,
+				}); err != nil {
+					return fmt.Errorf("failed to switch application branch: %w", err)
+				}
+				if _, err := client.Deploy(projectCfg.AppUUID, false, 0); err != nil {
+					return fmt.Errorf("failed to trigger deployment: %w", err)
+				}
+				return nil
+			},
+		},
+	}
+
+	err = ui.RunTasksVerbose(tasks, verbose)
+	if err == nil {
+		// The branch is switched back once the deployment has checked out the
+		// snapshot, so keep watching even with NoWait
+		ui.Info("Watching deployment...")
+		err = Watch(client, projectCfg.AppUUID, WatchOptions{Timeout: watch.Timeout, Transcript: watch.Transcript})
+	}
+
+	// Point the application back at its branch so regular and webhook
+	// deployments keep working
+	if branch != "" && branch != LocalBranch {
+		if restoreErr := client.UpdateApplication(projectCfg.AppUUID, map[string]interface{}{
+			"git_branch": branch,
+		}); restoreErr != nil {
+			ui.Warning(fmt.Sprintf("Failed to switch the application back to branch %s: %v", branch, restoreErr))
+		}
+	}
+
+	if err != nil {
+		ui.Error("Deployment failed")
+		ui.Spacer()
+		ui.NextSteps([]string{
+			"Run 'cdp logs' to view deployment logs",
+			"Check the Coolify dashboard for more details",
+		})
+		return err
+	}
+
+	ui.Success("Deployment complete")
+	if url := app.GetFqdn(); url != "" {
+		ui.Spacer()
+		ui.KeyValue("URL", ui.InfoStyle.Render(url))
+	}
+	if err := runMigrations(client, projectCfg.AppUUID, projectCfg, nil, 0, WatchOptions{SkipMigrations: watch.SkipMigrations}); err != nil {
+		return err
+	}
+	return runSmokeTests(client, projectCfg, app.PrimaryURL(), 0, watch)
+}
@@ -0,0 +1,77 @@
+package appdeploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/smart"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// migrationTimeout is how long a migration or seed command may run
+const migrationTimeout = 10 * time.Minute
+
+// runMigrations runs the project's migrations in the deployed application,
+// and its seeds after the first deployment. Previews are skipped, since
+// they usually share the production database.
+func runMigrations(client *api.Client, appUUID string, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, prNumber int, watch WatchOptions) error {
+	if watch.NoWait || prNumber > 0 || projectCfg.MigrateCommand == "" {
+		return nil
+	}
+	if watch.SkipMigrations {
+		ui.Dim("Skipping migrations: " + projectCfg.MigrateCommand)
+		return nil
+	}
+
+	commands := []string{projectCfg.MigrateCommand}
+	if deploymentConfig != nil && deploymentConfig.Migration != nil && deploymentConfig.Migration.Seed != "" {
+		commands = append(commands, deploymentConfig.Migration.Seed)
+	}
+
+	ui.Spacer()
+	ui.Divider()
+	ui.Bold("Migrations")
+	ui.Spacer()
+
+	for _, command := range commands {
+		var result *ExecResult
+		err := ui.RunTasks([]ui.Task{
+			{
+				Name:         "migrate",
+				ActiveName:   fmt.Sprintf("Running %s...", command),
+				CompleteName: "✓ Ran " + command,
+				Action: func() error {
+					ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+					defer cancel()
+					var err error
+					result, err = Exec(ctx, client, appUUID, command)
+					return err
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to run %s: %w", command, err)
+		}
+
+		for _, line := range strings.Split(strings.TrimRight(result.Output, "\n"), "\n") {
+			if line != "" {
+				fmt.Println(ui.DimStyle.Render("  " + line))
+			}
+		}
+		if !result.StatusKnown {
+			ui.Warning(fmt.Sprintf("The exit status of %s was not reported", command))
+			continue
+		}
+		if result.ExitStatus != 0 {
+			ui.NextSteps([]string{
+				fmt.Sprintf("Fix the migration and run 'cool-kit exec -- %s'", command),
+			})
+			return fmt.Errorf("%s exited with status %d", command, result.ExitStatus)
+		}
+	}
+	return nil
+}
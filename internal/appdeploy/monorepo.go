@@ -0,0 +1,158 @@
+package appdeploy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/docker"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// SetupApp configures a new application of a monorepo and saves it as the
+// selected app's config. Settings shared by the repository (Coolify project,
+// server, Git repository, GitHub App) are copied from base when it is set;
+// otherwise the full first-time setup runs.
+func SetupApp(client *api.Client, globalCfg *config.GlobalConfig, name string, base *config.ProjectConfig, inputs SetupInputs) (*SetupResult, error) {
+	ui.Section(fmt.Sprintf("New App: %s", name))
+
+	dir, err := ui.InputWithDefault("Base directory:", defaultAppDir(name))
+	if err != nil {
+		return nil, err
+	}
+	dir = strings.Trim(filepath.ToSlash(dir), "/")
+	if dir == "" {
+		dir = "."
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("base directory %s not found", dir)
+	}
+	ui.Dim(fmt.Sprintf("→ %s", dir))
+
+	appName := fmt.Sprintf("%s-%s", getWorkingDirName(), name)
+	applyApp := func(cfg *config.ProjectConfig) {
+		cfg.Name = appName
+		if dir != "." {
+			cfg.BaseDirectory = "/" + dir
+		}
+	}
+
+	if base == nil {
+		return setupProject(client, globalCfg, dir, inputs, applyApp)
+	}
+
+	ui.Spacer()
+	ui.StepProgress(1, 3, "Framework Detection")
+
+	framework, err := detectFramework(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ui.Spacer()
+	ui.Divider()
+	ui.StepProgress(2, 3, "Service Detection")
+
+	deploymentConfig, framework, err := analyzeServices(dir, framework)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkComposeMethod(framework, base.DeployMethod); err != nil {
+		return nil, err
+	}
+	if err := collectMissingEnv(dir, framework.Compose, deploymentConfig); err != nil {
+		return nil, err
+	}
+	workers, err := chooseWorkers(deploymentConfig, base.DeployMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err := chooseScheduledTasks(deploymentConfig); err != nil {
+		return nil, err
+	}
+
+	ui.Spacer()
+	ui.Divider()
+	ui.StepProgress(3, 3, "Advanced Configuration")
+
+	advancedCfg, err := configureAdvancedOptions(base.DeployMethod, framework, inputs)
+	if err != nil {
+		return nil, err
+	}
+	composeCfg, err := configureComposeStack(framework, inputs.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	projectCfg := &config.ProjectConfig{
+		DeployMethod:    base.DeployMethod,
+		ProjectUUID:     base.ProjectUUID,
+		ServerUUID:      base.ServerUUID,
+		DestinationUUID: base.DestinationUUID,
+		EnvironmentUUID: base.EnvironmentUUID,
+		Framework:       framework.Name,
+		BuildPack:       framework.BuildPack,
+		InstallCommand:  framework.InstallCommand,
+		BuildCommand:    framework.BuildCommand,
+		StartCommand:    framework.StartCommand,
+		PublishDir:      framework.PublishDirectory,
+		Port:            advancedCfg.Port,
+		Platform:        advancedCfg.Platform,
+		Branch:          base.Branch,
+		Domain:          advancedCfg.Domain,
+		GitHubRepo:      base.GitHubRepo,
+		GitHubPrivate:   base.GitHubPrivate,
+		GitHubAppUUID:   base.GitHubAppUUID,
+		GitHubDeployKey: base.GitHubDeployKey,
+		GitProvider:     base.GitProvider,
+		GitRepo:         base.GitRepo,
+		GitPrivate:      base.GitPrivate,
+		PrivateKeyUUID:  base.PrivateKeyUUID,
+		SecretScan:      base.SecretScan,
+		Workers:         workers,
+		Compose:         composeCfg,
+	}
+	if m := deploymentConfig.Migration; m != nil {
+		projectCfg.MigrateCommand = m.Command
+	}
+	applyApp(projectCfg)
+
+	if config.BuildsImage(projectCfg.DeployMethod) && globalCfg.DockerRegistry != nil {
+		projectCfg.DockerImage = docker.GetImageFullName(
+			globalCfg.DockerRegistry.URL,
+			globalCfg.DockerRegistry.Username,
+			projectCfg.Name,
+		)
+	}
+
+	ui.Info("Saving configuration...")
+	if err := config.SaveProject(projectCfg); err != nil {
+		ui.Error("Failed to save configuration")
+		return nil, fmt.Errorf("failed to save configuration: %w", err)
+	}
+	ui.Success("Saved configuration")
+
+	return &SetupResult{
+		ProjectConfig:    projectCfg,
+		DeploymentConfig: deploymentConfig,
+	}, nil
+}
+
+// defaultAppDir guesses the base directory of a monorepo app
+func defaultAppDir(name string) string {
+	if info, err := os.Stat(filepath.Join("apps", name)); err == nil && info.IsDir() {
+		return "apps/" + name
+	}
+	return name
+}
+
+// baseDirectory returns the application's base directory in Coolify's format
+func baseDirectory(projectCfg *config.ProjectConfig) string {
+	if projectCfg.SourceDir() == "." {
+		return ""
+	}
+	return "/" + projectCfg.SourceDir()
+}
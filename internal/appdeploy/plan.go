@@ -0,0 +1,178 @@
+package appdeploy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/smart"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// PrintPlan shows what a deployment would create, set and reuse without
+// calling the Coolify API. projectCfg is nil before the first-time setup.
+func PrintPlan(projectCfg *config.ProjectConfig, prNumber int, deploymentType string) error {
+	dir, name := ".", getWorkingDirName()
+	switch {
+	case projectCfg != nil:
+		dir, name = projectCfg.SourceDir(), projectCfg.Name
+	case config.SelectedApp() != "":
+		dir = defaultAppDir(config.SelectedApp())
+		name = fmt.Sprintf("%s-%s", name, config.SelectedApp())
+	}
+
+	ui.Section("Deployment Plan")
+	ui.KeyValue("Project", name)
+	ui.KeyValue("Type", deploymentType)
+
+	deploymentConfig, err := smart.NewSmartDetector(dir).Detect()
+	if err != nil {
+		return err
+	}
+	if deploymentConfig.Framework != nil {
+		ui.KeyValue("Framework", deploymentConfig.Framework.Name)
+	}
+
+	ui.Spacer()
+	ui.Bold("Coolify Resources")
+	ui.Table([]string{"Resource", "Action"}, planResources(projectCfg, name))
+
+	ui.Spacer()
+	ui.Bold("Services")
+	switch {
+	case projectCfg != nil:
+		ui.Dim("None: services are only provisioned by the first-time setup")
+	case len(deploymentConfig.Services) == 0:
+		ui.Dim("None detected")
+	}
+
+	if projectCfg == nil {
+		plan := smart.NewServiceProvisioner(nil, "", "", "", name).Plan(deploymentConfig)
+		printPlannedServices(plan)
+
+		ui.Spacer()
+		ui.Bold("Environment Variables")
+		rows := make([][]string, 0, len(plan.EnvironmentVars))
+		for _, v := range plan.EnvironmentVars {
+			rows = append(rows, []string{v.Key, v.Value})
+		}
+		ui.Table([]string{"Key", "Value"}, rows)
+	}
+
+	printPlannedSteps(projectCfg, deploymentConfig, name, prNumber)
+
+	ui.Spacer()
+	if projectCfg == nil {
+		ui.Dim("The setup asks before creating each of these, so the result can differ.")
+	}
+	ui.Dim("Nothing was changed. Run 'cool-kit deploy' to apply.")
+	return nil
+}
+
+// planResources lists the Coolify resources a deployment reuses or creates
+func planResources(projectCfg *config.ProjectConfig, name string) [][]string {
+	if projectCfg == nil {
+		return [][]string{
+			{"Project", "chosen or created during setup"},
+			{"Server", "chosen during setup"},
+			{"Application", "create " + name},
+		}
+	}
+
+	reuse := func(resource, uuid, create string) []string {
+		if uuid == "" {
+			return []string{resource, create}
+		}
+		return []string{resource, "reuse " + uuid}
+	}
+	rows := [][]string{
+		reuse("Project", projectCfg.ProjectUUID, "create "+projectCfg.Name),
+		reuse("Environment", projectCfg.EnvironmentUUID, "create production"),
+		reuse("Server", projectCfg.ServerUUID, "none set"),
+		reuse("Application", projectCfg.AppUUID, "create "+projectCfg.Name),
+	}
+	for _, w := range projectCfg.Workers {
+		rows = append(rows, reuse("Worker", w.AppUUID, "create "+w.AppName(projectCfg)))
+	}
+	return rows
+}
+
+func printPlannedServices(plan *smart.ProvisioningPlan) {
+	if len(plan.Services) == 0 {
+		return
+	}
+
+	rows := make([][]string, 0, len(plan.Services))
+	for _, s := range plan.Services {
+		memory := "unknown"
+		if s.MemoryMB > 0 {
+			memory = "~" + smart.FormatMemory(s.MemoryMB)
+		}
+		reason := s.Reason
+		if !s.Required {
+			reason += " (optional)"
+		}
+		rows = append(rows, []string{s.Name, s.Type, s.Version, memory, reason})
+	}
+	ui.Table([]string{"Name", "Engine", "Version", "Memory", "Reason"}, rows)
+
+	total, complete := plan.MemoryMB()
+	estimate := "~" + smart.FormatMemory(total)
+	if !complete {
+		estimate += " (some services have no estimate)"
+	}
+	ui.KeyValue("Memory", estimate)
+	for _, s := range plan.Services {
+		if s.VersionWarning != "" {
+			ui.Warning(s.VersionWarning)
+		}
+	}
+}
+
+// printPlannedSteps shows the workers, scheduled tasks and migrations a
+// deployment would set up or run
+func printPlannedSteps(projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, name string, prNumber int) {
+	// Previews don't run migrations
+	migration := ""
+	switch {
+	case prNumber > 0:
+	case projectCfg != nil:
+		migration = projectCfg.MigrateCommand
+	case deploymentConfig.Migration != nil:
+		m := deploymentConfig.Migration
+		migration = m.Command
+		if m.Seed != "" {
+			migration += ", then " + m.Seed
+		}
+	}
+
+	var workers []string
+	if projectCfg == nil {
+		for _, w := range deploymentConfig.Workers {
+			workers = append(workers, fmt.Sprintf("%s-%s (%s)", name, w.Name, w.Tool))
+		}
+	}
+
+	var tasks []string
+	if projectCfg == nil {
+		for _, t := range deploymentConfig.ScheduledTasks {
+			tasks = append(tasks, fmt.Sprintf("%s (%s)", t.Name, t.Frequency))
+		}
+	}
+
+	if migration == "" && len(workers) == 0 && len(tasks) == 0 {
+		return
+	}
+
+	ui.Spacer()
+	ui.Bold("After Deploying")
+	if len(workers) > 0 {
+		ui.KeyValue("Workers", strings.Join(workers, ", "))
+	}
+	if len(tasks) > 0 {
+		ui.KeyValue("Scheduled tasks", strings.Join(tasks, ", "))
+	}
+	if migration != "" {
+		ui.KeyValue("Migrations", migration)
+	}
+}
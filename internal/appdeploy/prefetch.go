@@ -0,0 +1,100 @@
+package appdeploy
+
+import (
+	"sync"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"golang.org/x/sync/errgroup"
+)
+
+// wizardPrefetch holds the lists the setup wizard needs, fetched concurrently
+// while the user answers the first prompts.
+type wizardPrefetch struct {
+	client *api.Client
+	done   chan struct{}
+
+	servers    []api.Server
+	projects   []api.Project
+	githubApps []api.GitHubApp
+
+	serversErr    error
+	projectsErr   error
+	githubAppsErr error
+}
+
+var (
+	prefetchMu sync.Mutex
+	prefetched *wizardPrefetch
+)
+
+// startPrefetch begins loading servers, projects and GitHub Apps in the background
+func startPrefetch(client *api.Client) {
+	p := &wizardPrefetch{client: client, done: make(chan struct{})}
+
+	go func() {
+		defer close(p.done)
+
+		// Each list is optional for some deploy method, so errors are kept per
+		// list instead of cancelling the others.
+		var g errgroup.Group
+		g.Go(func() error {
+			p.servers, p.serversErr = client.ListServers()
+			return nil
+		})
+		g.Go(func() error {
+			p.projects, p.projectsErr = client.ListProjects()
+			return nil
+		})
+		g.Go(func() error {
+			p.githubApps, p.githubAppsErr = client.ListGitHubApps()
+			return nil
+		})
+		_ = g.Wait()
+	}()
+
+	prefetchMu.Lock()
+	prefetched = p
+	prefetchMu.Unlock()
+}
+
+// wait blocks until all lists are loaded
+func (p *wizardPrefetch) wait() {
+	<-p.done
+}
+
+// listServers returns prefetched servers, falling back to a direct call
+func listServers(client *api.Client) ([]api.Server, error) {
+	if p := prefetchFor(client); p != nil {
+		return p.servers, p.serversErr
+	}
+	return client.ListServers()
+}
+
+// listProjects returns prefetched projects, falling back to a direct call
+func listProjects(client *api.Client) ([]api.Project, error) {
+	if p := prefetchFor(client); p != nil {
+		return p.projects, p.projectsErr
+	}
+	return client.ListProjects()
+}
+
+// listGitHubApps returns prefetched GitHub Apps, falling back to a direct call
+func listGitHubApps(client *api.Client) ([]api.GitHubApp, error) {
+	if p := prefetchFor(client); p != nil {
+		return p.githubApps, p.githubAppsErr
+	}
+	return client.ListGitHubApps()
+}
+
+// prefetchFor returns the completed prefetch for client, if any
+func prefetchFor(client *api.Client) *wizardPrefetch {
+	prefetchMu.Lock()
+	p := prefetched
+	prefetchMu.Unlock()
+
+	if p == nil || p.client != client {
+		return nil
+	}
+	p.wait()
+	return p
+}
@@ -0,0 +1,16 @@
+package appdeploy
+
+import (
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/docker"
+)
+
+// registryLogin returns the credentials the project's image is pushed
+// with: fresh ones from its registry helper, or else the saved docker
+// registry login, which may be nil
+func registryLogin(globalCfg *config.GlobalConfig, projectCfg *config.ProjectConfig) (*config.DockerRegistry, error) {
+	if projectCfg.RegistryAuth == nil {
+		return globalCfg.DockerRegistry, nil
+	}
+	return docker.RegistryCredentials(projectCfg.RegistryAuth, projectCfg.DockerImage, globalCfg.GitHubToken)
+}
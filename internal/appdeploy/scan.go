@@ -0,0 +1,68 @@
+package appdeploy
+
+import (
+	"fmt"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/docker"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// maxReportedVulnerabilities is how many findings the scan lists
+const maxReportedVulnerabilities = 10
+
+// scanImage scans the built image when the project asks for it, and fails
+// when it finds vulnerabilities at or above the configured severity
+func scanImage(projectCfg *config.ProjectConfig, tag string) error {
+	scan := projectCfg.ImageScanSettings()
+	if scan == nil {
+		return nil
+	}
+	severity := scan.Severity
+	if severity == "" {
+		severity = "CRITICAL"
+	}
+
+	var report *docker.ScanReport
+	err := ui.RunTasks([]ui.Task{{
+		Name:         "scan-image",
+		ActiveName:   "Scanning image for vulnerabilities...",
+		CompleteName: "✓ Scanned image",
+		Action: func() error {
+			var err error
+			report, err = docker.Scan(&docker.ScanOptions{
+				ImageName:     projectCfg.DockerImage,
+				Tag:           tag,
+				Severity:      severity,
+				IgnoreUnfixed: scan.IgnoreUnfixed,
+			})
+			return err
+		},
+	}})
+	if err != nil {
+		return fmt.Errorf("image scan failed: %w", err)
+	}
+
+	if len(report.Vulnerabilities) == 0 {
+		ui.Dim(fmt.Sprintf("No %s or higher vulnerabilities found", severity))
+		ui.Spacer()
+		return nil
+	}
+
+	ui.Warning(fmt.Sprintf("Vulnerabilities: %s", report.Summary()))
+	var items []string
+	for i, v := range report.Vulnerabilities {
+		if i == maxReportedVulnerabilities {
+			items = append(items, fmt.Sprintf("... and %d more", len(report.Vulnerabilities)-i))
+			break
+		}
+		fix := "no fix yet"
+		if v.FixedVersion != "" {
+			fix = "fixed in " + v.FixedVersion
+		}
+		items = append(items, fmt.Sprintf("%s %s: %s %s (%s)", v.Severity, v.ID, v.Package, v.InstalledVersion, fix))
+	}
+	ui.List(items)
+	ui.Spacer()
+	return fmt.Errorf("image has %d %s or higher vulnerabilities: update the packages, or set image_scan.severity", len(report.Vulnerabilities), severity)
+}
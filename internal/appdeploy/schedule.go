@@ -0,0 +1,61 @@
+package appdeploy
+
+import (
+	"fmt"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/smart"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// chooseScheduledTasks offers to add each detected cron job, keeping those
+// the user accepts for the first deployment to create
+func chooseScheduledTasks(deploymentConfig *smart.DeploymentConfig) error {
+	if deploymentConfig == nil || len(deploymentConfig.ScheduledTasks) == 0 {
+		return nil
+	}
+
+	var accepted []smart.ScheduledTask
+	for _, task := range deploymentConfig.ScheduledTasks {
+		ui.Spacer()
+		ui.KeyValue("Scheduled task", fmt.Sprintf("%s (%s)", task.Command, task.Reason))
+		add, err := ui.Ask(fmt.Sprintf("Run '%s' on the schedule %q?", task.Command, task.Frequency), false)
+		if err != nil {
+			return err
+		}
+		if add {
+			accepted = append(accepted, task)
+		}
+	}
+	if len(accepted) < len(deploymentConfig.ScheduledTasks) {
+		ui.Dim("Add scheduled tasks later with 'cool-kit cron add'")
+	}
+	deploymentConfig.ScheduledTasks = accepted
+	return nil
+}
+
+// createScheduledTasksTask creates the scheduled tasks chosen at setup on
+// the new application
+func createScheduledTasksTask(client *api.Client, projectCfg *config.ProjectConfig, tasks []smart.ScheduledTask) ui.Task {
+	return ui.Task{
+		Name:         "create-scheduled-tasks",
+		ActiveName:   fmt.Sprintf("Creating %d scheduled task(s)...", len(tasks)),
+		CompleteName: fmt.Sprintf("✓ Created %d scheduled task(s)", len(tasks)),
+		Action: func() error {
+			enabled := true
+			for _, task := range tasks {
+				_, err := client.CreateScheduledTask(projectCfg.AppUUID, &api.ScheduledTaskRequest{
+					Name:      task.Name,
+					Command:   task.Command,
+					Frequency: task.Frequency,
+					Enabled:   &enabled,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to create scheduled task %q: %w", task.Name, err)
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package appdeploy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/secretscan"
+)
+
+// maxReportedFindings limits how many findings are listed in the error
+const maxReportedFindings = 10
+
+// checkForSecrets scans the files the auto-commit would push and fails if any
+// look like credentials. Rules can be tuned in the project's secret_scan config.
+func checkForSecrets(dir string, projectCfg *config.ProjectConfig) error {
+	scanner := secretscan.New()
+
+	if sc := projectCfg.SecretScan; sc != nil {
+		if sc.Disabled {
+			return nil
+		}
+		scanner.Allow = sc.Allow
+		scanner.Disabled = sc.DisabledRules
+		for _, r := range sc.Rules {
+			rule, err := secretscan.NewRule(r.ID, r.Description, r.Pattern)
+			if err != nil {
+				return err
+			}
+			scanner.Rules = append(scanner.Rules, rule)
+		}
+	}
+
+	files, err := git.ListPushableFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	findings, err := scanner.Scan(dir, files)
+	if err != nil {
+		return fmt.Errorf("secret scan failed: %w", err)
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "found %d potential secret(s) in files that would be pushed:\n", len(findings))
+	for i, f := range findings {
+		if i == maxReportedFindings {
+			fmt.Fprintf(&b, "  ... and %d more\n", len(findings)-i)
+			break
+		}
+		fmt.Fprintf(&b, "  %s\n", f)
+	}
+	fmt.Fprintf(&b, "Remove them or add the files to .gitignore, mark intended lines with %q, or rerun with --allow-secrets", secretscan.AllowMarker)
+	return errors.New(b.String())
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
@@ -22,13 +23,36 @@ type SetupResult struct {
 	DeploymentConfig *smart.DeploymentConfig
 }
 
+// SetupInputs are answers to the setup's questions given up front, like
+// with flags. The questions of empty fields are asked, or take their default
+// in non-interactive mode.
+type SetupInputs struct {
+	Method      string // config.DeployMethodGit, DeployMethodDocker or DeployMethodBuildpacks
+	Server      string // name, UUID or IP
+	Destination string // name or UUID
+	Project     string // name of an existing project, or of one to create
+	Port        string
+	Branch      string
+	Domain      string
+	Platform    string
+}
+
 // FirstTimeSetup walks the user through initial project configuration.
-func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*SetupResult, error) {
+func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig, inputs SetupInputs) (*SetupResult, error) {
+	return setupProject(client, globalCfg, ".", inputs, nil)
+}
+
+// setupProject runs the first-time setup for the application in dir. When
+// before is set it is applied to the config before it is saved.
+func setupProject(client *api.Client, globalCfg *config.GlobalConfig, dir string, inputs SetupInputs, before func(*config.ProjectConfig)) (*SetupResult, error) {
+	// Load servers, projects and GitHub Apps while the user answers the first prompts
+	startPrefetch(client)
+
 	ui.Spacer()
 	ui.StepProgress(1, 6, "Framework Detection")
 
 	// Detect framework
-	framework, err := detectFramework()
+	framework, err := detectFramework(dir)
 	if err != nil {
 		return nil, err
 	}
@@ -38,28 +62,58 @@ func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*SetupR
 	ui.Divider()
 	ui.StepProgress(2, 6, "Service Detection")
 
-	deploymentConfig, err := analyzeServices()
+	deploymentConfig, framework, err := analyzeServices(dir, framework)
 	if err != nil {
 		return nil, err
 	}
+	if err := collectMissingEnv(dir, framework.Compose, deploymentConfig); err != nil {
+		return nil, err
+	}
 
 	// Choose deployment method
 	ui.Spacer()
 	ui.Divider()
 	ui.StepProgress(3, 6, "Deployment Method")
 
-	deployMethod, err := chooseDeployMethod(globalCfg)
+	method := inputs.Method
+	if isComposeStack(framework) && method == "" {
+		method = config.DeployMethodGit
+	}
+	deployMethod, err := chooseDeployMethod(globalCfg, method)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkComposeMethod(framework, deployMethod); err != nil {
+		return nil, err
+	}
 	displayDeployMethod(deployMethod)
 
+	gitProvider := ""
+	if deployMethod == config.DeployMethodGit {
+		if gitProvider, err = chooseGitProvider(globalCfg); err != nil {
+			return nil, err
+		}
+	}
+
+	workers, err := chooseWorkers(deploymentConfig, deployMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err := chooseScheduledTasks(deploymentConfig); err != nil {
+		return nil, err
+	}
+
 	// Select server
 	ui.Spacer()
 	ui.Divider()
 	ui.StepProgress(4, 6, "Server Selection")
 
-	serverUUID, err := selectServer(client)
+	serverUUID, err := selectServer(client, inputs.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	destinationUUID, err := selectDestination(client, serverUUID, inputs.Destination)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +123,7 @@ func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*SetupR
 	ui.Divider()
 	ui.StepProgress(5, 6, "Project Configuration")
 
-	projectName, projectUUID, environmentUUID, err := selectOrCreateProject(client)
+	projectName, projectUUID, environmentUUID, err := selectOrCreateProject(client, inputs.Project)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +133,11 @@ func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*SetupR
 	ui.Divider()
 	ui.StepProgress(6, 6, "Advanced Configuration")
 
-	advancedCfg, err := configureAdvancedOptions(deployMethod, framework)
+	advancedCfg, err := configureAdvancedOptions(deployMethod, framework, inputs)
+	if err != nil {
+		return nil, err
+	}
+	composeCfg, err := configureComposeStack(framework, inputs.Domain)
 	if err != nil {
 		return nil, err
 	}
@@ -90,11 +148,22 @@ func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*SetupR
 		projectUUID,
 		environmentUUID,
 		serverUUID,
+		destinationUUID,
 		deployMethod,
+		gitProvider,
 		framework,
 		advancedCfg,
 		globalCfg,
 	)
+	projectCfg.Workers = workers
+	projectCfg.Compose = composeCfg
+	if m := deploymentConfig.Migration; m != nil {
+		projectCfg.MigrateCommand = m.Command
+	}
+
+	if before != nil {
+		before(projectCfg)
+	}
 
 	// Save project config
 	ui.Info("Saving configuration...")
@@ -115,22 +184,43 @@ func FirstTimeSetup(client *api.Client, globalCfg *config.GlobalConfig) (*SetupR
 	}, nil
 }
 
-func analyzeServices() (*smart.DeploymentConfig, error) {
+// analyzeServices detects the services the project needs. For a compose
+// project the user can instead map its services onto Coolify resources,
+// which may change the framework to deploy with.
+func analyzeServices(dir string, framework *detect.FrameworkInfo) (*smart.DeploymentConfig, *detect.FrameworkInfo, error) {
 	ui.Info("Analyzing project dependencies...")
 
 	// Run smart detector
-	detector := smart.NewSmartDetector(".")
+	detector := smart.NewSmartDetector(dir)
 	deploymentConfig, err := detector.Detect()
 	if err != nil {
 		ui.Error("Failed to analyze project")
-		return nil, fmt.Errorf("failed to detect services: %w", err)
+		return nil, nil, fmt.Errorf("failed to detect services: %w", err)
+	}
+
+	if framework.Compose != nil {
+		if framework, err = mapComposeServices(framework, deploymentConfig); err != nil {
+			return nil, nil, err
+		}
+	}
+	if isComposeStack(framework) {
+		if framework.Compose, err = validateComposeStack(dir); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if m := deploymentConfig.Migration; m != nil {
+		ui.KeyValue("Migrations", fmt.Sprintf("%s (%s, run after deploy)", m.Command, m.Tool))
+		if m.Seed != "" {
+			ui.KeyValue("Seeds", m.Seed+" (run after the first deploy)")
+		}
 	}
 
 	// Display detected services
 	if len(deploymentConfig.Services) == 0 {
 		ui.Success("No additional services required")
 		ui.Dim("Your application doesn't require databases or other services")
-		return deploymentConfig, nil
+		return deploymentConfig, framework, nil
 	}
 
 	ui.Success(fmt.Sprintf("Detected %d service(s)", len(deploymentConfig.Services)))
@@ -148,18 +238,60 @@ func analyzeServices() (*smart.DeploymentConfig, error) {
 			fmt.Sprintf("  %s %s", service.Type, service.Version),
 			fmt.Sprintf("%s%s", service.Reason, requiredText),
 		)
+		if service.VersionWarning != "" {
+			ui.Warning("    " + service.VersionWarning)
+		}
+		if len(service.ConnectionVars) > 0 {
+			ui.Dim("    Sets " + strings.Join(service.ConnectionVarNames(), ", "))
+		}
+		if tmpl, ok := deploymentConfig.ConnectionTemplates[service.Type]; ok {
+			text := "    Connection from " + smart.ConnectionTemplatesFile
+			if names := tmpl.VarNames(); len(names) > 0 {
+				text += ", also sets " + strings.Join(names, ", ")
+			}
+			ui.Dim(text)
+		}
+	}
+
+	if err := confirmServices(deploymentConfig); err != nil {
+		return nil, nil, err
+	}
+	if len(deploymentConfig.Services) == 0 {
+		return deploymentConfig, framework, nil
 	}
 
 	ui.Spacer()
 	ui.Dim("These services will be created during deployment and connection")
 	ui.Dim("strings will be automatically injected as environment variables.")
 
-	return deploymentConfig, nil
+	return deploymentConfig, framework, nil
 }
 
-func detectFramework() (*detect.FrameworkInfo, error) {
+// confirmServices asks about the services the app may get elsewhere, and
+// drops those the user declines
+func confirmServices(deploymentConfig *smart.DeploymentConfig) error {
+	services := deploymentConfig.Services[:0]
+	for _, service := range deploymentConfig.Services {
+		if service.Prompt != "" {
+			ui.Spacer()
+			provision, err := ui.Ask(service.Prompt, false)
+			if err != nil {
+				return err
+			}
+			if !provision {
+				ui.Dim(fmt.Sprintf("→ Skipping %s", service.Type))
+				continue
+			}
+		}
+		services = append(services, service)
+	}
+	deploymentConfig.Services = services
+	return nil
+}
+
+func detectFramework(dir string) (*detect.FrameworkInfo, error) {
 	ui.Info("Analyzing project...")
-	framework, err := detect.Detect(".")
+	framework, err := detect.Detect(dir)
 	if err != nil {
 		ui.Error("Failed to analyze project")
 		return nil, fmt.Errorf("failed to detect framework: %w", err)
@@ -182,8 +314,23 @@ func detectFramework() (*detect.FrameworkInfo, error) {
 	if framework.PublishDirectory != "" {
 		ui.KeyValue("  Publish dir", framework.PublishDirectory)
 	}
+	if framework.Runtime != "" && framework.RuntimeVersion != "" {
+		ui.KeyValue("  Runtime", framework.Runtime+" "+framework.RuntimeVersion)
+	}
+	if framework.PortSource != "" {
+		ui.KeyValue("  Port", fmt.Sprintf("%s (from %s)", framework.Port, framework.PortSource))
+	}
+	if devcontainer := detect.ReadDevcontainer(dir); devcontainer != nil {
+		if hints := devcontainer.Hints(framework); len(hints) > 0 {
+			ui.Spacer()
+			ui.Dim("Dev container:")
+			for _, hint := range hints {
+				ui.Dim("  " + hint)
+			}
+		}
+	}
 
-	editSettings, err := ui.Confirm("Customize build settings?")
+	editSettings, err := ui.Ask("Customize build settings?", false)
 	if err != nil {
 		return nil, err
 	}
@@ -236,21 +383,27 @@ func editBuildSettings(f *detect.FrameworkInfo) (*detect.FrameworkInfo, error) {
 	return f, nil
 }
 
-func chooseDeployMethod(globalCfg *config.GlobalConfig) (string, error) {
+// chooseDeployMethod asks how to deploy, unless only one method is
+// configured or want names one. Git is the default.
+func chooseDeployMethod(globalCfg *config.GlobalConfig, want string) (string, error) {
 	options := []string{}
 	optionMap := map[string]string{}
 
 	// Check what's available
 	hasDocker := docker.IsDockerAvailable() && globalCfg.DockerRegistry != nil
-	hasGitHub := globalCfg.GitHubToken != ""
+	hasGit := len(gitProviders(globalCfg)) > 0
 
-	if hasGitHub {
+	if hasGit {
 		options = append(options, "Git (recommended)")
 		optionMap["Git (recommended)"] = config.DeployMethodGit
 	}
 	if hasDocker {
 		options = append(options, "Docker (build locally)")
 		optionMap["Docker (build locally)"] = config.DeployMethodDocker
+		if docker.IsPackAvailable() {
+			options = append(options, "Buildpacks (build locally with pack)")
+			optionMap["Buildpacks (build locally with pack)"] = config.DeployMethodBuildpacks
+		}
 	}
 
 	if len(options) == 0 {
@@ -258,7 +411,7 @@ func chooseDeployMethod(globalCfg *config.GlobalConfig) (string, error) {
 		ui.Spacer()
 		ui.Dim("Configure at least one deployment method:")
 		ui.List([]string{
-			"GitHub token (for git-based deployments)",
+			"GitHub, GitLab, Gitea or Bitbucket login (for git-based deployments)",
 			"Docker registry (for container deployments)",
 		})
 		ui.Spacer()
@@ -268,28 +421,90 @@ func chooseDeployMethod(globalCfg *config.GlobalConfig) (string, error) {
 		return "", fmt.Errorf("no deployment method configured")
 	}
 
+	if want != "" {
+		for _, method := range optionMap {
+			if method == want {
+				return method, nil
+			}
+		}
+		switch want {
+		case config.DeployMethodGit:
+			return "", fmt.Errorf("git deployments need a GitHub, GitLab, Gitea or Bitbucket login: run 'cdp login' to add one")
+		case config.DeployMethodDocker:
+			return "", fmt.Errorf("docker deployments need Docker and a registry: run 'cdp login' to add one")
+		case config.DeployMethodBuildpacks:
+			return "", fmt.Errorf("buildpacks deployments need Docker, the pack CLI and a registry: run 'cdp login' to add one")
+		}
+		return "", fmt.Errorf("unknown deployment method %q: use %s, %s or %s", want, config.DeployMethodGit, config.DeployMethodDocker, config.DeployMethodBuildpacks)
+	}
+
 	if len(options) == 1 {
 		// Auto-select if only one option available
 		return optionMap[options[0]], nil
 	}
 
 	// Show options
-	selected, err := ui.Select("Choose deployment method:", options)
+	selected, err := ui.SelectDefault("Choose deployment method:", options, options[0])
 	if err != nil {
 		return "", err
 	}
 	return optionMap[selected], nil
 }
 
+// gitProviders returns the git hosts logged in to, by name
+func gitProviders(globalCfg *config.GlobalConfig) []string {
+	var names []string
+	if globalCfg.GitHubToken != "" {
+		names = append(names, "GitHub")
+	}
+	if globalCfg.GitLabToken != "" {
+		names = append(names, "GitLab")
+	}
+	if globalCfg.GiteaToken != "" && globalCfg.GiteaURL != "" {
+		names = append(names, "Gitea")
+	}
+	if globalCfg.BitbucketUser != "" && globalCfg.BitbucketToken != "" {
+		names = append(names, "Bitbucket")
+	}
+	return names
+}
+
+// chooseGitProvider asks where the repository goes when more than one git
+// host is logged in. GitHub is the default.
+func chooseGitProvider(globalCfg *config.GlobalConfig) (string, error) {
+	providers := map[string]string{
+		"GitHub":    config.GitProviderGitHub,
+		"GitLab":    config.GitProviderGitLab,
+		"Gitea":     config.GitProviderGitea,
+		"Bitbucket": config.GitProviderBitbucket,
+	}
+	names := gitProviders(globalCfg)
+	if len(names) == 1 {
+		return providers[names[0]], nil
+	}
+
+	selected, err := ui.SelectDefault("Host the repository on:", names, names[0])
+	if err != nil {
+		return "", err
+	}
+	ui.Dim(fmt.Sprintf("→ %s", selected))
+	return providers[selected], nil
+}
+
 func displayDeployMethod(deployMethod string) {
 	deployMethodDisplay := "Git"
-	if deployMethod == config.DeployMethodDocker {
+	switch deployMethod {
+	case config.DeployMethodDocker:
 		deployMethodDisplay = "Docker"
+	case config.DeployMethodBuildpacks:
+		deployMethodDisplay = "Buildpacks"
 	}
 	ui.Dim(fmt.Sprintf("→ %s", deployMethodDisplay))
 }
 
-func selectServer(client *api.Client) (string, error) {
+// selectServer asks which server to deploy to, unless want names one by
+// name, UUID or IP. In non-interactive mode a single server is taken.
+func selectServer(client *api.Client, want string) (string, error) {
 	var servers []api.Server
 	err := ui.RunTasks([]ui.Task{
 		{
@@ -298,7 +513,7 @@ func selectServer(client *api.Client) (string, error) {
 			CompleteName: "✓ Loaded servers",
 			Action: func() error {
 				var err error
-				servers, err = client.ListServers()
+				servers, err = listServers(client)
 				return err
 			},
 		},
@@ -316,27 +531,117 @@ func selectServer(client *api.Client) (string, error) {
 	}
 
 	serverOptions := make(map[string]string)
+	pickOptions := make([]ui.PickOption, 0, len(servers))
+	names := make([]string, 0, len(servers))
 	for _, s := range servers {
 		displayName := s.Name
 		if s.IP != "" {
 			displayName = fmt.Sprintf("%s (%s)", s.Name, s.IP)
 		}
 		serverOptions[s.UUID] = displayName
+		pickOptions = append(pickOptions, ui.PickOption{Key: s.UUID, Label: displayName})
+		names = append(names, s.Name)
+	}
+
+	var serverUUID string
+	switch {
+	case want != "":
+		for _, s := range servers {
+			if s.UUID == want || s.Name == want || (s.IP != "" && s.IP == want) {
+				serverUUID = s.UUID
+				break
+			}
+		}
+		if serverUUID == "" {
+			return "", fmt.Errorf("server %q not found: use one of %s", want, strings.Join(names, ", "))
+		}
+	case len(servers) == 1 && !ui.Interactive():
+		serverUUID = servers[0].UUID
+	case !ui.Interactive():
+		return "", &ui.NonInteractiveError{
+			What: "Select server",
+			Hint: fmt.Sprintf("pass --server with one of %s", strings.Join(names, ", ")),
+		}
+	default:
+		serverUUID, err = ui.Pick("Select server:", ui.PickServer, pickOptions)
+		if err != nil {
+			return "", err
+		}
 	}
 
-	serverUUID, err := ui.SelectWithKeys("Select server:", serverOptions)
+	selectedServerName := serverOptions[serverUUID]
+	ui.Dim(fmt.Sprintf("→ %s", selectedServerName))
+	ui.Spacer()
+
+	return serverUUID, nil
+}
+
+// selectDestination asks which Docker network to deploy into when the server has several,
+// unless want names one by name or UUID.
+// An empty result lets Coolify use the server's default destination.
+func selectDestination(client *api.Client, serverUUID, want string) (string, error) {
+	destinations, err := client.ListServerDestinations(serverUUID)
+	if err != nil {
+		if want != "" {
+			return "", fmt.Errorf("failed to list the server's destinations: %w", err)
+		}
+		// Older Coolify versions don't expose destinations; fall back to the default
+		ui.Dim("→ Using the server's default destination")
+		return "", nil
+	}
+
+	options := make(map[string]string, len(destinations))
+	names := make([]string, 0, len(destinations))
+	for _, d := range destinations {
+		displayName := d.Name
+		if d.NetworkName != "" {
+			displayName = fmt.Sprintf("%s (%s)", d.Name, d.NetworkName)
+		}
+		options[d.UUID] = displayName
+		names = append(names, d.Name)
+	}
+
+	if want != "" {
+		for _, d := range destinations {
+			if d.UUID == want || d.Name == want {
+				ui.Dim(fmt.Sprintf("→ %s", options[d.UUID]))
+				return d.UUID, nil
+			}
+		}
+		return "", fmt.Errorf("destination %q not found: use one of %s", want, strings.Join(names, ", "))
+	}
+
+	switch len(destinations) {
+	case 0:
+		return "", nil
+	case 1:
+		return destinations[0].UUID, nil
+	}
+
+	if !ui.Interactive() {
+		return "", &ui.NonInteractiveError{
+			What: "Select destination",
+			Hint: fmt.Sprintf("pass --destination with one of %s", strings.Join(names, ", ")),
+		}
+	}
+	destinationUUID, err := ui.SelectWithKeys("Select destination:", options)
 	if err != nil {
 		return "", err
 	}
 
-	selectedServerName := serverOptions[serverUUID]
-	ui.Dim(fmt.Sprintf("→ %s", selectedServerName))
+	ui.Dim(fmt.Sprintf("→ %s", options[destinationUUID]))
 	ui.Spacer()
 
-	return serverUUID, nil
+	return destinationUUID, nil
 }
 
-func selectOrCreateProject(client *api.Client) (projectName, projectUUID, environmentUUID string, err error) {
+// createProjectKey is the key of the option to create a project
+const createProjectKey = "+new"
+
+// selectOrCreateProject asks which Coolify project to deploy into, unless
+// want names one, which is created when it doesn't exist. In non-interactive
+// mode the project is named after the working directory.
+func selectOrCreateProject(client *api.Client, want string) (projectName, projectUUID, environmentUUID string, err error) {
 	var projects []api.Project
 	err = ui.RunTasks([]ui.Task{
 		{
@@ -345,7 +650,7 @@ func selectOrCreateProject(client *api.Client) (projectName, projectUUID, enviro
 			CompleteName: "✓ Loaded projects",
 			Action: func() error {
 				var err error
-				projects, err = client.ListProjects()
+				projects, err = listProjects(client)
 				return err
 			},
 		},
@@ -355,20 +660,37 @@ func selectOrCreateProject(client *api.Client) (projectName, projectUUID, enviro
 		return "", "", "", fmt.Errorf("failed to list projects: %w", err)
 	}
 
-	projectOptions := make([]string, 0, len(projects)+1)
-	projectOptions = append(projectOptions, "+ Create new project")
+	sort.SliceStable(projects, func(i, j int) bool {
+		return strings.ToLower(projects[i].Name) < strings.ToLower(projects[j].Name)
+	})
+	projectOptions := make([]ui.PickOption, 0, len(projects)+1)
+	projectOptions = append(projectOptions, ui.PickOption{Key: createProjectKey, Label: "+ Create new project"})
 	projectMap := make(map[string]api.Project)
 	for _, p := range projects {
-		projectOptions = append(projectOptions, p.Name)
+		projectOptions = append(projectOptions, ui.PickOption{Key: p.UUID, Label: p.Name})
 		projectMap[p.Name] = p
 	}
 
-	selectedProject, err := ui.Select("Select or create project:", projectOptions)
+	if want == "" && !ui.Interactive() {
+		want = getWorkingDirName()
+	}
+	if want != "" {
+		if project, ok := projectMap[want]; ok {
+			ui.Dim(fmt.Sprintf("→ %s", want))
+			ui.Spacer()
+			return want, project.UUID, "", nil
+		}
+		ui.Dim(fmt.Sprintf("→ %s (new)", want))
+		ui.Spacer()
+		return want, "", "", nil
+	}
+
+	selectedProject, err := ui.Pick("Select or create project:", ui.PickProject, projectOptions)
 	if err != nil {
 		return "", "", "", err
 	}
 
-	if selectedProject == "+ Create new project" {
+	if selectedProject == createProjectKey {
 		// Ask for project name
 		workingDirName := getWorkingDirName()
 		projectName, err = ui.InputWithDefault("Project name:", workingDirName)
@@ -380,9 +702,12 @@ func selectOrCreateProject(client *api.Client) (projectName, projectUUID, enviro
 		environmentUUID = ""
 	} else {
 		// Use existing project
-		project := projectMap[selectedProject]
-		projectName = selectedProject
-		projectUUID = project.UUID
+		projectUUID = selectedProject
+		for _, p := range projects {
+			if p.UUID == selectedProject {
+				projectName = p.Name
+			}
+		}
 		// Environment will be checked/created during deployment
 		environmentUUID = ""
 	}
@@ -400,24 +725,42 @@ type advancedConfig struct {
 	Domain   string
 }
 
-func configureAdvancedOptions(deployMethod string, framework *detect.FrameworkInfo) (*advancedConfig, error) {
-	configureAdvanced, err := ui.Confirm("Configure advanced options?")
-	if err != nil {
-		return nil, err
-	}
-
+// configureAdvancedOptions returns the port, platform, branch and domain,
+// from inputs or the defaults, and asks to change them when wanted
+func configureAdvancedOptions(deployMethod string, framework *detect.FrameworkInfo, inputs SetupInputs) (*advancedConfig, error) {
 	cfg := &advancedConfig{
 		Port:     framework.Port,
 		Platform: config.DefaultPlatform,
 		Branch:   config.DefaultBranch,
-		Domain:   "",
+		Domain:   inputs.Domain,
 	}
 
 	if cfg.Port == "" {
 		cfg.Port = config.DefaultPort
 	}
+	if inputs.Port != "" {
+		cfg.Port = inputs.Port
+	}
+	if inputs.Platform != "" {
+		cfg.Platform = inputs.Platform
+	}
+	if inputs.Branch != "" {
+		cfg.Branch = inputs.Branch
+	}
+	// A compose stack's ports and domains are its services'
+	stack := isComposeStack(framework)
+	if stack {
+		cfg.Domain = ""
+	}
 
+	configureAdvanced, err := ui.Ask("Configure advanced options?", false)
+	if err != nil {
+		return nil, err
+	}
 	if !configureAdvanced {
+		if !stack && framework.PortSource != "" && cfg.Port != framework.Port {
+			ui.Warning(fmt.Sprintf("The app listens on %s according to %s; Coolify will route traffic to %s", framework.Port, framework.PortSource, cfg.Port))
+		}
 		return cfg, nil
 	}
 
@@ -425,11 +768,16 @@ func configureAdvancedOptions(deployMethod string, framework *detect.FrameworkIn
 	ui.Dim("Leave blank to use defaults")
 
 	// Port
-	cfg.Port, err = ui.InputWithDefault("Application port:", cfg.Port)
-	if err != nil {
-		return nil, err
+	if !stack {
+		cfg.Port, err = ui.InputWithDefault("Application port:", cfg.Port)
+		if err != nil {
+			return nil, err
+		}
+		ui.Dim(fmt.Sprintf("→ Port: %s", cfg.Port))
+		if framework.PortSource != "" && cfg.Port != framework.Port {
+			ui.Warning(fmt.Sprintf("The app listens on %s according to %s; Coolify will route traffic to %s", framework.Port, framework.PortSource, cfg.Port))
+		}
 	}
-	ui.Dim(fmt.Sprintf("→ Port: %s", cfg.Port))
 
 	// Platform (for Docker builds)
 	if deployMethod == config.DeployMethodDocker {
@@ -438,6 +786,7 @@ func configureAdvancedOptions(deployMethod string, framework *detect.FrameworkIn
 		if err != nil {
 			return nil, err
 		}
+		cfg.Platform = config.DefaultPlatform
 		if strings.Contains(platformChoice, "arm64") {
 			cfg.Platform = "linux/arm64"
 		}
@@ -454,7 +803,18 @@ func configureAdvancedOptions(deployMethod string, framework *detect.FrameworkIn
 	}
 
 	// Domain
-	useDomain, err := ui.Confirm("Configure custom domain?")
+	if stack {
+		return cfg, nil
+	}
+	if cfg.Domain != "" {
+		cfg.Domain, err = ui.InputWithDefault("Domain:", cfg.Domain)
+		if err != nil {
+			return nil, err
+		}
+		ui.Dim(fmt.Sprintf("→ %s", cfg.Domain))
+		return cfg, nil
+	}
+	useDomain, err := ui.Ask("Configure custom domain?", false)
 	if err != nil {
 		return nil, err
 	}
@@ -470,7 +830,7 @@ func configureAdvancedOptions(deployMethod string, framework *detect.FrameworkIn
 }
 
 func buildProjectConfig(
-	projectName, projectUUID, environmentUUID, serverUUID, deployMethod string,
+	projectName, projectUUID, environmentUUID, serverUUID, destinationUUID, deployMethod, gitProvider string,
 	framework *detect.FrameworkInfo,
 	advancedCfg *advancedConfig,
 	globalCfg *config.GlobalConfig,
@@ -480,6 +840,7 @@ func buildProjectConfig(
 		DeployMethod:    deployMethod,
 		ProjectUUID:     projectUUID,
 		ServerUUID:      serverUUID,
+		DestinationUUID: destinationUUID,
 		EnvironmentUUID: environmentUUID,
 		AppUUID:         "", // Will be created on first deployment
 		Framework:       framework.Name,
@@ -495,7 +856,7 @@ func buildProjectConfig(
 	}
 
 	// Set up based on deploy method
-	if deployMethod == config.DeployMethodDocker {
+	if config.BuildsImage(deployMethod) {
 		if globalCfg.DockerRegistry != nil {
 			projectCfg.DockerImage = docker.GetImageFullName(
 				globalCfg.DockerRegistry.URL,
@@ -503,6 +864,9 @@ func buildProjectConfig(
 				projectCfg.Name,
 			)
 		}
+	} else if gitProvider != config.GitProviderGitHub {
+		projectCfg.GitProvider = gitProvider
+		projectCfg.GitRepo = git.GenerateRepoName(projectCfg.Name)
 	} else {
 		projectCfg.GitHubRepo = git.GenerateRepoName(projectCfg.Name)
 	}
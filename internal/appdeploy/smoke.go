@@ -0,0 +1,155 @@
+package appdeploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/smoketest"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// runSmokeTests runs the project's smoke tests against a finished deployment.
+// A failing production deployment is rolled back when the config asks for it;
+// the deploy fails either way.
+func runSmokeTests(client *api.Client, projectCfg *config.ProjectConfig, appURL string, prNumber int, watch WatchOptions) error {
+	st := projectCfg.SmokeTests
+	if st == nil || len(st.Checks) == 0 {
+		return nil
+	}
+
+	err := checkSmokeTests(st, appURL)
+	if err == nil || !st.Rollback || prNumber > 0 {
+		return err
+	}
+
+	ui.Spacer()
+	var label string
+	rbErr := ui.RunTasks([]ui.Task{
+		{
+			Name:         "rollback",
+			ActiveName:   "Rolling back to the previous version...",
+			CompleteName: "✓ Rollback started",
+			Action: func() error {
+				var err error
+				label, err = rollbackToPrevious(client, projectCfg)
+				return err
+			},
+		},
+	})
+	if rbErr != nil {
+		ui.Error(fmt.Sprintf("Rollback failed: %v", rbErr))
+		return err
+	}
+
+	ui.Info("Watching rollback deployment...")
+	if watchErr := Watch(client, projectCfg.AppUUID, WatchOptions{Timeout: watch.Timeout, Transcript: watch.Transcript}); watchErr != nil {
+		ui.Error(fmt.Sprintf("Rollback to %s failed: %v", label, watchErr))
+		return err
+	}
+	ui.Success(fmt.Sprintf("Rolled back to %s", label))
+	return err
+}
+
+// checkSmokeTests runs the checks against appURL and reports each result
+func checkSmokeTests(st *config.SmokeTestConfig, appURL string) error {
+	ui.Spacer()
+	ui.Divider()
+	ui.Bold("Smoke Tests")
+	ui.Spacer()
+
+	var results []smoketest.Result
+	_ = ui.RunTasks([]ui.Task{
+		{
+			Name:         "smoke-tests",
+			ActiveName:   fmt.Sprintf("Running %d smoke test(s)...", len(st.Checks)),
+			CompleteName: "✓ Ran smoke tests",
+			Action: func() error {
+				results = smoketest.Run(context.Background(), appURL, st.Checks)
+				return nil
+			},
+		},
+	})
+
+	for _, r := range results {
+		if r.Passed() {
+			fmt.Println(ui.SuccessStyle.Render("  ✓ " + r.Name))
+		} else {
+			fmt.Println(ui.ErrorStyle.Render(fmt.Sprintf("  ✗ %s: %v", r.Name, r.Err)))
+		}
+	}
+
+	failed := smoketest.Failed(results)
+	if len(failed) == 0 {
+		ui.Spacer()
+		ui.Success("All smoke tests passed")
+		return nil
+	}
+
+	ui.Spacer()
+	ui.Error(fmt.Sprintf("Smoke tests failed (%d of %d)", len(failed), len(results)))
+	return fmt.Errorf("%d of %d smoke tests failed", len(failed), len(results))
+}
+
+// rollbackToPrevious redeploys the version that was live before the latest
+// deployment and returns a label for it
+func rollbackToPrevious(client *api.Client, projectCfg *config.ProjectConfig) (string, error) {
+	if config.BuildsImage(projectCfg.DeployMethod) {
+		tags := projectCfg.ImageTags
+		if len(tags) < 2 {
+			return "", fmt.Errorf("no previous image to roll back to")
+		}
+		previous := tags[len(tags)-2]
+		if err := client.UpdateApplication(projectCfg.AppUUID, map[string]interface{}{
+			"docker_registry_image_tag": previous,
+		}); err != nil {
+			return "", fmt.Errorf("failed to update image tag: %w", err)
+		}
+		if _, err := client.Deploy(projectCfg.AppUUID, false, 0); err != nil {
+			return "", fmt.Errorf("failed to trigger deployment: %w", err)
+		}
+		return previous, nil
+	}
+
+	deployments, err := client.ListDeployments(projectCfg.AppUUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	// The first finished production deployment is the one that just failed
+	// its smoke tests; the next one with another commit is the previous version
+	var live, previous string
+	for _, d := range deployments {
+		if !strings.EqualFold(d.Status, "finished") || d.PullRequest() != 0 || d.CommitSHA() == "" {
+			continue
+		}
+		if live == "" {
+			live = d.CommitSHA()
+			continue
+		}
+		if d.CommitSHA() != live {
+			previous = d.CommitSHA()
+			break
+		}
+	}
+	if previous == "" {
+		return "", fmt.Errorf("no previous deployment to roll back to")
+	}
+
+	if err := client.UpdateApplication(projectCfg.AppUUID, map[string]interface{}{
+		"git_commit_sha": previous,
+	}); err != nil {
+		return "", fmt.Errorf("failed to pin commit: %w", err)
+	}
+	// Not forced, so Coolify can reuse the image already built for the commit
+	if _, err := client.Deploy(projectCfg.AppUUID, false, 0); err != nil {
+		return "", fmt.Errorf("failed to trigger deployment: %w", err)
+	}
+
+	if len(previous) > 7 {
+		return previous[:7], nil
+	}
+	return previous, nil
+}
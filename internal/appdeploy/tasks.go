@@ -3,6 +3,7 @@ package appdeploy
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
 	"github.com/entro314-labs/cool-kit/internal/config"
@@ -10,12 +11,30 @@ import (
 	"github.com/entro314-labs/cool-kit/internal/ui"
 )
 
-// provisionServicesTask provisions detected services and updates environment variables
-func provisionServicesTask(client *api.Client, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig) ui.Task {
+// serviceHealthTimeout is how long the deployment waits for provisioned
+// services to become healthy
+const serviceHealthTimeout = 5 * time.Minute
+
+// needsProvisioning reports whether setup detected services to create or
+// variables to set
+func needsProvisioning(deploymentConfig *smart.DeploymentConfig) bool {
+	return deploymentConfig != nil && (len(deploymentConfig.Services) > 0 || len(deploymentConfig.Environment) > 0)
+}
+
+// provisionServicesTask provisions detected services and updates environment
+// variables. The created services are stored in provisioned for
+// waitForServicesTask.
+func provisionServicesTask(client *api.Client, projectCfg *config.ProjectConfig, deploymentConfig *smart.DeploymentConfig, provisioned *[]smart.ProvisionedService) ui.Task {
+	activeName := fmt.Sprintf("Provisioning %d service(s)...", len(deploymentConfig.Services))
+	completeName := fmt.Sprintf("✓ Provisioned %d service(s)", len(deploymentConfig.Services))
+	if len(deploymentConfig.Services) == 0 {
+		activeName, completeName = "Setting environment variables...", "✓ Set environment variables"
+	}
+
 	return ui.Task{
 		Name:         "provision-services",
-		ActiveName:   fmt.Sprintf("Provisioning %d service(s)...", len(deploymentConfig.Services)),
-		CompleteName: fmt.Sprintf("✓ Provisioned %d service(s)", len(deploymentConfig.Services)),
+		ActiveName:   activeName,
+		CompleteName: completeName,
 		Action: func() error {
 			// Create provisioner
 			provisioner := smart.NewServiceProvisioner(
@@ -31,8 +50,9 @@ func provisionServicesTask(client *api.Client, projectCfg *config.ProjectConfig,
 			if err != nil {
 				return fmt.Errorf("service provisioning failed: %w", err)
 			}
+			*provisioned = result.Services
 
-			// Update application with generated environment variables
+			// Update application and its workers with generated environment variables
 			if len(result.EnvironmentVars) > 0 {
 				ctx := context.Background()
 				var envVars []api.EnvironmentVariable
@@ -43,9 +63,11 @@ func provisionServicesTask(client *api.Client, projectCfg *config.ProjectConfig,
 					})
 				}
 
-				_, err = client.UpdateApplicationEnvsBulk(ctx, projectCfg.AppUUID, envVars)
-				if err != nil {
-					return fmt.Errorf("failed to update environment variables: %w", err)
+				for _, appUUID := range append([]string{projectCfg.AppUUID}, workerUUIDs(projectCfg)...) {
+					_, err = client.UpdateApplicationEnvsBulk(ctx, appUUID, envVars)
+					if err != nil {
+						return fmt.Errorf("failed to update environment variables: %w", err)
+					}
 				}
 			}
 
@@ -55,7 +77,66 @@ func provisionServicesTask(client *api.Client, projectCfg *config.ProjectConfig,
 					ui.Dim(fmt.Sprintf("  Warning: %s", serviceErr.Error()))
 				}
 			}
+			for _, note := range result.Notes {
+				ui.Dim("  " + note)
+			}
+
+			return nil
+		},
+	}
+}
 
+// waitForServicesTask starts the services provisionServicesTask created and
+// waits for them to be healthy, so the application doesn't deploy before
+// its database is up
+func waitForServicesTask(client *api.Client, projectCfg *config.ProjectConfig, provisioned *[]smart.ProvisionedService) ui.Task {
+	return ui.Task{
+		Name:         "wait-services",
+		ActiveName:   "Waiting for services to be healthy...",
+		CompleteName: "✓ Services are healthy",
+		Run: func(report func(string)) error {
+			if len(*provisioned) == 0 {
+				return nil
+			}
+			provisioner := smart.NewServiceProvisioner(
+				client,
+				projectCfg.ProjectUUID,
+				projectCfg.EnvironmentUUID,
+				projectCfg.ServerUUID,
+				projectCfg.Name,
+			)
+			warnings, err := provisioner.WaitHealthy(*provisioned, serviceHealthTimeout, report)
+			for _, warning := range warnings {
+				ui.Dim("  Warning: " + warning)
+			}
+			return err
+		},
+	}
+}
+
+// setBuildEnvTask adds build-time variables, like the one pinning the
+// runtime version, to the application and its workers
+func setBuildEnvTask(client *api.Client, projectCfg *config.ProjectConfig, env map[string]string) ui.Task {
+	return ui.Task{
+		Name:         "build-env",
+		ActiveName:   "Setting build variables...",
+		CompleteName: fmt.Sprintf("✓ Set %d build variable(s)", len(env)),
+		Action: func() error {
+			envVars := make([]api.EnvironmentVariable, 0, len(env))
+			for key, value := range env {
+				envVars = append(envVars, api.EnvironmentVariable{
+					Key:         key,
+					Value:       value,
+					IsBuildTime: true,
+				})
+			}
+
+			ctx := context.Background()
+			for _, appUUID := range append([]string{projectCfg.AppUUID}, workerUUIDs(projectCfg)...) {
+				if _, err := client.UpdateApplicationEnvsBulk(ctx, appUUID, envVars); err != nil {
+					return fmt.Errorf("failed to set build variables: %w", err)
+				}
+			}
 			return nil
 		},
 	}
@@ -1,26 +1,60 @@
 package appdeploy
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/logview"
+	"github.com/entro314-labs/cool-kit/internal/runs"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 )
 
 const (
 	// Polling configuration
-	maxPollAttempts      = 120 // 4 minutes max (2s intervals)
 	pollInterval         = 2 * time.Second
 	noDeploymentTimeout  = 15 // attempts before giving up if no deployment found
 	maxConsecutiveErrors = 5  // max API errors before giving up
+	queueCheckInterval   = 5  // attempts between queue position checks
+
+	// DefaultWatchTimeout is how long a deployment is watched by default
+	DefaultWatchTimeout = 15 * time.Minute
 )
 
+// ErrWatchTimeout is wrapped by Watch's error when the deployment didn't
+// finish within the timeout
+var ErrWatchTimeout = errors.New("timed out")
+
+// WatchOptions controls how a triggered deployment is followed. The zero
+// value waits up to DefaultWatchTimeout for the deployment to finish.
+type WatchOptions struct {
+	// NoWait returns once the deployment has been queued or started
+	NoWait bool
+	// Timeout fails the watch if the deployment hasn't finished in time
+	Timeout time.Duration
+	// SkipMigrations doesn't run the project's migrations once the
+	// deployment finishes
+	SkipMigrations bool
+	// Transcript, when set, records the deployment's status and build logs
+	Transcript *runs.Recorder
+
+	// gitHub, when set, reports the deployment's status to GitHub
+	gitHub *gitHubStatus
+}
+
 // WatchDeployment polls the deployment status and displays build logs.
 // Returns true if deployment succeeded, false if it failed.
 func WatchDeployment(client *api.Client, appUUID string) bool {
+	return Watch(client, appUUID, WatchOptions{}) == nil
+}
+
+// Watch follows an application's latest deployment, printing its build logs
+// and its position in the server's build queue while it waits. It returns an
+// error if the deployment fails or doesn't finish within the timeout.
+func Watch(client *api.Client, appUUID string, opts WatchOptions) error {
 	ui.Spacer()
 
 	debug := os.Getenv("CDP_DEBUG") != ""
@@ -28,38 +62,68 @@ func WatchDeployment(client *api.Client, appUUID string) bool {
 		fmt.Printf("[DEBUG] Watching app UUID: %s\n", appUUID)
 	}
 
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultWatchTimeout
+	}
+
 	watcher := &deploymentWatcher{
 		client:            client,
 		appUUID:           appUUID,
 		debug:             debug,
+		opts:              opts,
 		consecutiveErrors: 0,
 		lastLogLen:        0,
 	}
 
-	return watcher.watch()
+	err := watcher.watch()
+	if err != nil {
+		opts.gitHub.fail(err)
+	}
+	return err
 }
 
 type deploymentWatcher struct {
 	client             *api.Client
 	appUUID            string
 	debug              bool
+	opts               WatchOptions
 	consecutiveErrors  int
 	lastLogLen         int
 	lastDeploymentUUID string
 	seenDeployment     bool
+	queued             bool
+	lastQueueReport    string
+	lastStatus         string
 }
 
-func (w *deploymentWatcher) watch() bool {
-	for attempt := 0; attempt < maxPollAttempts; attempt++ {
+func (w *deploymentWatcher) watch() error {
+	deadline := time.Now().Add(w.opts.Timeout)
+	for attempt := 0; ; attempt++ {
 		status, done := w.checkDeploymentStatus(attempt)
 		if done {
-			return status == deploymentSuccess
+			if status == deploymentSuccess {
+				return nil
+			}
+			return fmt.Errorf("deployment failed")
+		}
+
+		if w.opts.NoWait && w.seenDeployment {
+			if w.queued {
+				ui.Info("Deployment queued; not waiting for it to start")
+			} else {
+				ui.Info("Deployment started; not waiting for it to finish")
+			}
+			return nil
+		}
+
+		if time.Now().Add(pollInterval).After(deadline) {
+			if w.queued {
+				return fmt.Errorf("deployment still queued after %s (%s): %w", w.opts.Timeout, w.lastQueueReport, ErrWatchTimeout)
+			}
+			return fmt.Errorf("deployment did not finish within %s: %w", w.opts.Timeout, ErrWatchTimeout)
 		}
 		time.Sleep(pollInterval)
 	}
-
-	// Timeout reached - make final check
-	return w.checkFinalStatus()
 }
 
 type deploymentStatus int
@@ -136,6 +200,7 @@ func (w *deploymentWatcher) processDeployment(deployment api.Deployment, attempt
 		}
 		w.lastDeploymentUUID = deployUUID
 		w.lastLogLen = 0
+		w.opts.Transcript.Detail("deployment", deployUUID)
 	}
 
 	// Try to get detailed deployment info with logs
@@ -150,25 +215,127 @@ func (w *deploymentWatcher) processDeployment(deployment api.Deployment, attempt
 
 		// Check status from detailed info
 		if status, done := w.checkStatus(detail.Status); done {
+			w.recordStatus(detail.Status)
 			return status, true
 		}
 	}
 
 	// Fallback: check status from deployment list
 	if status, done := w.checkStatus(deployment.Status); done {
+		w.recordStatus(deployment.Status)
 		return status, true
 	}
 
+	status := deployment.Status
+	if err == nil && detail.Status != "" {
+		status = detail.Status
+	}
+	w.recordStatus(status)
+	w.queued = strings.EqualFold(strings.TrimSpace(status), "queued")
+	if w.queued && (w.lastQueueReport == "" || attempt%queueCheckInterval == 0) {
+		w.reportQueue(deployUUID)
+	}
+
 	return deploymentInProgress, false
 }
 
+// reportQueue prints the deployment's position in its server's build queue
+// and what is building ahead of it, whenever that changes
+func (w *deploymentWatcher) reportQueue(deployUUID string) {
+	running, err := w.client.ListRunningDeployments()
+	if err != nil {
+		if w.debug {
+			fmt.Printf("[DEBUG] ListRunningDeployments error: %v\n", err)
+		}
+		return
+	}
+
+	report := describeQueue(running, deployUUID)
+	if report == "" || report == w.lastQueueReport {
+		return
+	}
+	w.lastQueueReport = report
+	w.opts.Transcript.Log("info", report)
+	fmt.Println(ui.WarningStyle.Render("  ⏳ " + report))
+}
+
+// recordStatus adds the deployment's status to the transcript, and reports it
+// to GitHub, when it changes
+func (w *deploymentWatcher) recordStatus(status string) {
+	if status == "" || status == w.lastStatus {
+		return
+	}
+	w.lastStatus = status
+	w.opts.Transcript.Log("info", "Deployment status: "+status)
+	w.opts.gitHub.report(status)
+}
+
+// describeQueue summarizes the queue ahead of a deployment, or returns ""
+// when the deployment isn't in the list of running deployments
+func describeQueue(running []api.Deployment, deployUUID string) string {
+	var own *api.Deployment
+	for i := range running {
+		if running[i].DeploymentUUID == deployUUID {
+			own = &running[i]
+			break
+		}
+	}
+	if own == nil {
+		return ""
+	}
+
+	position := 1
+	var building []string
+	for _, d := range running {
+		if d.DeploymentUUID == deployUUID || !sameServer(&d, own) {
+			continue
+		}
+		switch strings.ToLower(d.Status) {
+		case "queued":
+			if d.Created().Before(own.Created()) {
+				position++
+			}
+		case "in_progress":
+			name := d.ApplicationName
+			if name == "" {
+				name = d.DeploymentUUID
+			}
+			building = append(building, name)
+		}
+	}
+
+	report := fmt.Sprintf("Queued at position %d", position)
+	if own.ServerName != "" {
+		report += " on " + own.ServerName
+	}
+	if len(building) > 0 {
+		report += "; building: " + strings.Join(building, ", ")
+	}
+	return report
+}
+
+// sameServer reports whether two deployments build on the same server
+func sameServer(a, b *api.Deployment) bool {
+	if a.ServerName != "" || b.ServerName != "" {
+		return a.ServerName == b.ServerName
+	}
+	return fmt.Sprint(a.ServerID) == fmt.Sprint(b.ServerID)
+}
+
 func (w *deploymentWatcher) printNewLogs(rawLogs string) {
 	parsedLogs := api.ParseLogs(rawLogs)
 	if len(parsedLogs) > w.lastLogLen {
 		newContent := parsedLogs[w.lastLogLen:]
 		lines := strings.Split(newContent, "\n")
 		for _, line := range lines {
-			if line != "" {
+			if line == "" {
+				continue
+			}
+			w.opts.Transcript.Log("build", line)
+			// Build output stays dim, warnings and errors stand out
+			if level := logview.DetectLevel(line); level >= logview.LevelWarn {
+				fmt.Println(ui.RenderLogLine("  "+line, level))
+			} else {
 				fmt.Println(ui.DimStyle.Render("  " + line))
 			}
 		}
@@ -191,24 +358,3 @@ func (w *deploymentWatcher) checkStatus(status string) (deploymentStatus, bool)
 		return deploymentInProgress, false
 	}
 }
-
-func (w *deploymentWatcher) checkFinalStatus() bool {
-	if w.debug {
-		fmt.Printf("[DEBUG] Timeout reached, checking final app status\n")
-	}
-
-	app, err := w.client.GetApplication(w.appUUID)
-	if err != nil {
-		if w.debug {
-			fmt.Printf("[DEBUG] GetApplication error: %v\n", err)
-		}
-		return false
-	}
-
-	appStatus := strings.ToLower(strings.TrimSpace(app.Status))
-	if w.debug {
-		fmt.Printf("[DEBUG] Final application status: %s\n", appStatus)
-	}
-
-	return appStatus == "running"
-}
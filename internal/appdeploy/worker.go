@@ -0,0 +1,123 @@
+package appdeploy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/smart"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// chooseWorkers offers to deploy each detected background worker as
+// another application built from the same repository
+func chooseWorkers(deploymentConfig *smart.DeploymentConfig, deployMethod string) ([]config.WorkerConfig, error) {
+	if deploymentConfig == nil || len(deploymentConfig.Workers) == 0 {
+		return nil, nil
+	}
+
+	if deployMethod != config.DeployMethodGit {
+		for _, w := range deploymentConfig.Workers {
+			ui.Dim(fmt.Sprintf("%s worker detected: workers are only deployed with the git method, add it in Coolify", w.Tool))
+		}
+		return nil, nil
+	}
+
+	var workers []config.WorkerConfig
+	for _, w := range deploymentConfig.Workers {
+		ui.Spacer()
+		ui.KeyValue("Worker", fmt.Sprintf("%s (%s)", w.Tool, w.Reason))
+		deploy, err := ui.Ask(fmt.Sprintf("Deploy a %s worker as a separate application?", w.Tool), false)
+		if err != nil {
+			return nil, err
+		}
+		if !deploy {
+			continue
+		}
+
+		command, err := ui.InputWithDefault("Worker start command:", w.StartCommand)
+		if err != nil {
+			return nil, err
+		}
+		command = strings.TrimSpace(command)
+		if command == "" {
+			ui.Warning("No start command given, skipping the worker")
+			continue
+		}
+		workers = append(workers, config.WorkerConfig{Name: w.Name, StartCommand: command})
+		ui.Dim(fmt.Sprintf("→ %s", command))
+	}
+	return workers, nil
+}
+
+// createWorkerAppsTask creates the worker applications that don't exist
+// yet, with the application's repository and build settings
+func createWorkerAppsTask(client *api.Client, projectCfg *config.ProjectConfig, repository string) ui.Task {
+	return ui.Task{
+		Name:         "create-workers",
+		ActiveName:   "Creating worker applications...",
+		CompleteName: "✓ Created worker applications",
+		Action: func() error {
+			for i := range projectCfg.Workers {
+				w := &projectCfg.Workers[i]
+				if w.AppUUID != "" {
+					continue
+				}
+
+				// Workers serve no traffic, so they get no domain or health check
+				req := gitAppRequest(projectCfg, repository)
+				req.Name = w.AppName(projectCfg)
+				req.StartCommand = w.StartCommand
+				req.Domains = ""
+				req.IsStatic = false
+				req.HealthCheckEnabled = false
+
+				resp, err := createGitApp(client, projectCfg, req)
+				if err != nil {
+					return fmt.Errorf("failed to create worker application %q: %w", req.Name, err)
+				}
+				w.AppUUID = resp.UUID
+				if err := config.SaveProject(projectCfg); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// triggerWorkerDeploymentsTask redeploys the workers so they run the same
+// commit as the application
+func triggerWorkerDeploymentsTask(client *api.Client, projectCfg *config.ProjectConfig) ui.Task {
+	return ui.Task{
+		Name:         "trigger-workers",
+		ActiveName:   fmt.Sprintf("Triggering %d worker deployment(s)...", len(projectCfg.Workers)),
+		CompleteName: fmt.Sprintf("✓ Triggered %d worker deployment(s)", len(projectCfg.Workers)),
+		Action: func() error {
+			for _, w := range projectCfg.Workers {
+				if w.AppUUID == "" {
+					continue
+				}
+				if err := client.UnpinCommit(w.AppUUID); err != nil {
+					return err
+				}
+				if _, err := client.Deploy(w.AppUUID, false, 0); err != nil {
+					return fmt.Errorf("failed to trigger deployment of worker %s: %w", w.AppName(projectCfg), err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// workerUUIDs returns the UUIDs of the workers that have been created
+func workerUUIDs(projectCfg *config.ProjectConfig) []string {
+	var uuids []string
+	for _, w := range projectCfg.Workers {
+		if w.AppUUID != "" {
+			uuids = append(uuids, w.AppUUID)
+		}
+	}
+	return uuids
+}
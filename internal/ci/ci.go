@@ -0,0 +1,235 @@
+// Package ci generates the CI pipelines that deploy a project to Coolify
+package ci
+
+import (
+	"embed"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// Params are what a pipeline is generated from, mostly the project config
+type Params struct {
+	Branch       string // deployed on push
+	DeployMethod string // config.DeployMethodGit or config.DeployMethodDocker
+	// AppUUID and CoolifyURL are read from the CI's secrets when empty
+	AppUUID    string
+	CoolifyURL string
+	// Image is the docker method's image, with its registry
+	Image    string
+	Platform string // docker method's build platform, like linux/arm64
+	// EnvSecrets are application variables set from the CI secrets of the
+	// same name before each deployment
+	EnvSecrets []string
+}
+
+// Platform is a CI system that pipelines can be generated for
+type Platform struct {
+	Name  string // as given to 'ci', like github
+	Title string
+	Path  string // of the generated file
+	// SecretsHelp says where the secrets are added
+	SecretsHelp string
+
+	template string
+	// shaVar is the variable holding the commit being built
+	shaVar string
+	// dollar escapes the $ of shell variables in commands
+	dollar string
+	// registryAuth is the registry the CI can push to without secrets
+	registryAuth string
+}
+
+// Platforms are the supported CI systems
+var Platforms = []*Platform{
+	{
+		Name:         "github",
+		Title:        "GitHub Actions",
+		Path:         ".github/workflows/coolify-deploy.yml",
+		SecretsHelp:  "Settings → Secrets and variables → Actions",
+		template:     "github.tmpl",
+		shaVar:       "GITHUB_SHA",
+		dollar:       "$",
+		registryAuth: "ghcr.io",
+	},
+	{
+		Name:         "gitlab",
+		Title:        "GitLab CI",
+		Path:         ".gitlab/ci/coolify-deploy.yml",
+		SecretsHelp:  "Settings → CI/CD → Variables (masked)",
+		template:     "gitlab.tmpl",
+		shaVar:       "CI_COMMIT_SHA",
+		dollar:       "$",
+		registryAuth: "registry.gitlab.com",
+	},
+	{
+		Name:        "woodpecker",
+		Title:       "Woodpecker CI",
+		Path:        ".woodpecker/coolify-deploy.yml",
+		SecretsHelp: "the repository's Secrets settings, in lower case",
+		template:    "woodpecker.tmpl",
+		shaVar:      "CI_COMMIT_SHA",
+		// Woodpecker substitutes $VAR in the pipeline itself
+		dollar: "$$",
+	},
+	{
+		Name:        "forgejo",
+		Title:       "Forgejo Actions",
+		Path:        ".forgejo/workflows/coolify-deploy.yml",
+		SecretsHelp: "Settings → Actions → Secrets",
+		template:    "forgejo.tmpl",
+		shaVar:      "GITHUB_SHA",
+		dollar:      "$",
+	},
+}
+
+// Lookup returns the platform called name, or nil
+func Lookup(name string) *Platform {
+	for _, p := range Platforms {
+		if p.Name == strings.ToLower(name) {
+			return p
+		}
+	}
+	return nil
+}
+
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// templateData is what the templates see
+type templateData struct {
+	Params
+	Docker   bool
+	Registry string
+	// RegistryAuth is set when the CI can push to Registry without secrets
+	RegistryAuth bool
+	EnvScript    string
+	DeployScript string
+}
+
+// Generate returns the pipeline deploying the project on p
+func (p *Platform) Generate(params Params) (string, error) {
+	if params.Branch == "" {
+		return "", fmt.Errorf("no branch to deploy")
+	}
+	for _, key := range params.EnvSecrets {
+		if !envName.MatchString(key) {
+			return "", fmt.Errorf("invalid variable name %q", key)
+		}
+	}
+
+	data := templateData{Params: params, Docker: params.DeployMethod == config.DeployMethodDocker}
+	if data.Docker {
+		if params.Image == "" {
+			return "", fmt.Errorf("no docker image set in the project config")
+		}
+		data.Registry = RegistryHost(params.Image)
+		data.RegistryAuth = p.registryAuth != "" && data.Registry == p.registryAuth
+	}
+	if len(params.EnvSecrets) > 0 {
+		data.EnvScript = p.shell(envScript(params.EnvSecrets))
+	}
+	data.DeployScript = p.shell(deployScript(data.Docker, p.shaVar))
+
+	tmpl, err := template.New(p.template).Delims("[[", "]]").Funcs(template.FuncMap{
+		"indent": indent,
+		"lower":  strings.ToLower,
+	}).ParseFS(templatesFS, "templates/"+p.template)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Secrets returns the secrets the pipeline needs, with what they hold
+func (p *Platform) Secrets(params Params) []string {
+	var secrets []string
+	if params.CoolifyURL == "" {
+		secrets = append(secrets, "COOLIFY_URL - Your Coolify instance URL (e.g., https://coolify.example.com)")
+	}
+	secrets = append(secrets, "COOLIFY_TOKEN - Your Coolify API token")
+	if params.AppUUID == "" {
+		secrets = append(secrets, "COOLIFY_APP_UUID - Your application UUID")
+	}
+	if params.DeployMethod == config.DeployMethodDocker && (p.registryAuth == "" || RegistryHost(params.Image) != p.registryAuth) {
+		secrets = append(secrets,
+			"REGISTRY_USERNAME - The user pushing to "+RegistryHost(params.Image),
+			"REGISTRY_PASSWORD - Its password or access token")
+	}
+	for _, key := range params.EnvSecrets {
+		secrets = append(secrets, key+" - Set on the application before each deployment")
+	}
+	if p.Name == "woodpecker" {
+		for i := range secrets {
+			name, rest, _ := strings.Cut(secrets[i], " ")
+			secrets[i] = strings.ToLower(name) + " " + rest
+		}
+	}
+	return secrets
+}
+
+// RegistryHost returns the registry of an image name, docker.io when it has
+// none
+func RegistryHost(image string) string {
+	host, _, found := strings.Cut(image, "/")
+	if !found || !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return "docker.io"
+	}
+	return host
+}
+
+// envScript sets the application variables from the environment, escaped
+// for JSON by jq
+func envScript(keys []string) string {
+	var args, items []string
+	for _, key := range keys {
+		args = append(args, fmt.Sprintf(`--arg %s "$%s"`, key, key))
+		items = append(items, fmt.Sprintf(`{key: "%s", value: $%s}`, key, key))
+	}
+	return fmt.Sprintf(`curl -fsS -X PATCH "$COOLIFY_URL/api/v1/applications/$COOLIFY_APP_UUID/envs/bulk" \
+  -H "Authorization: Bearer $COOLIFY_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d "$(jq -n %s '{data: [%s]}')"
+`, strings.Join(args, " "), strings.Join(items, ", "))
+}
+
+// deployScript points a docker application at the image of the commit in
+// shaVar, then deploys the application
+func deployScript(docker bool, shaVar string) string {
+	var b strings.Builder
+	if docker {
+		fmt.Fprintf(&b, `curl -fsS -X PATCH "$COOLIFY_URL/api/v1/applications/$COOLIFY_APP_UUID" \
+  -H "Authorization: Bearer $COOLIFY_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d "{\"docker_registry_image_tag\": \"$%s\"}"
+`, shaVar)
+	}
+	b.WriteString(`curl -fsS -X POST "$COOLIFY_URL/api/v1/deploy?uuid=$COOLIFY_APP_UUID" \
+  -H "Authorization: Bearer $COOLIFY_TOKEN"
+`)
+	return b.String()
+}
+
+// shell escapes a script's $ for the platform
+func (p *Platform) shell(script string) string {
+	return strings.ReplaceAll(script, "$", p.dollar)
+}
+
+// indent prefixes every line of s with n spaces
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = pad + line
+	}
+	return strings.Join(lines, "\n")
+}
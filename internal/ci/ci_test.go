@@ -0,0 +1,90 @@
+package ci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+)
+
+func TestGenerateGit(t *testing.T) {
+	out, err := Lookup("github").Generate(Params{Branch: "main", DeployMethod: config.DeployMethodGit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"COOLIFY_APP_UUID: ${{ secrets.COOLIFY_APP_UUID }}",
+		`"$COOLIFY_URL/api/v1/deploy?uuid=$COOLIFY_APP_UUID"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("workflow lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "build-push-action") {
+		t.Errorf("git workflow builds an image:\n%s", out)
+	}
+}
+
+func TestGenerateDocker(t *testing.T) {
+	params := Params{
+		Branch:       "main",
+		DeployMethod: config.DeployMethodDocker,
+		AppUUID:      "app-uuid",
+		CoolifyURL:   "https://coolify.example.com",
+		Image:        "ghcr.io/acme/web",
+		EnvSecrets:   []string{"DATABASE_URL"},
+	}
+	out, err := Lookup("github").Generate(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"COOLIFY_APP_UUID: app-uuid",
+		"tags: ghcr.io/acme/web:${{ github.sha }}",
+		"cache-to: type=gha,mode=max",
+		"password: ${{ secrets.GITHUB_TOKEN }}",
+		"DATABASE_URL: ${{ secrets.DATABASE_URL }}",
+		`\"docker_registry_image_tag\": \"$GITHUB_SHA\"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("workflow lacks %q:\n%s", want, out)
+		}
+	}
+	if secrets := Lookup("github").Secrets(params); len(secrets) != 2 {
+		t.Errorf("secrets = %q, want the token and DATABASE_URL", secrets)
+	}
+}
+
+func TestGenerateWoodpeckerEscapesVariables(t *testing.T) {
+	out, err := Lookup("woodpecker").Generate(Params{Branch: "main", DeployMethod: config.DeployMethodGit, EnvSecrets: []string{"API_KEY"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `"$$COOLIFY_URL/api/v1/deploy?uuid=$$COOLIFY_APP_UUID"`) || !strings.Contains(out, "from_secret: api_key") {
+		t.Errorf("pipeline:\n%s", out)
+	}
+}
+
+func TestGenerateRejects(t *testing.T) {
+	p := Lookup("gitlab")
+	if _, err := p.Generate(Params{Branch: "main", DeployMethod: config.DeployMethodDocker}); err == nil {
+		t.Error("generated a docker pipeline without an image")
+	}
+	if _, err := p.Generate(Params{Branch: "main", EnvSecrets: []string{"NOT-A-NAME"}}); err == nil {
+		t.Error("accepted an invalid variable name")
+	}
+}
+
+func TestRegistryHost(t *testing.T) {
+	for image, want := range map[string]string{
+		"ghcr.io/acme/web":        "ghcr.io",
+		"registry.local:5000/web": "registry.local:5000",
+		"localhost/web":           "localhost",
+		"acme/web":                "docker.io",
+		"web":                     "docker.io",
+	} {
+		if got := RegistryHost(image); got != want {
+			t.Errorf("RegistryHost(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
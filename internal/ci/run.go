@@ -0,0 +1,104 @@
+package ci
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Detect returns the platform of the CI job the process runs in, or nil
+// outside of one of the supported CI systems
+func Detect() *Platform {
+	switch {
+	case os.Getenv("FORGEJO_ACTIONS") == "true":
+		return Lookup("forgejo")
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return Lookup("github")
+	case os.Getenv("GITLAB_CI") == "true":
+		return Lookup("gitlab")
+	case os.Getenv("CI") == "woodpecker":
+		return Lookup("woodpecker")
+	}
+	return nil
+}
+
+// CommitSHA returns the commit the CI job runs for, or "" when the CI
+// system doesn't say
+func CommitSHA() string {
+	for _, env := range []string{"GITHUB_SHA", "CI_COMMIT_SHA"} {
+		if sha := strings.TrimSpace(os.Getenv(env)); sha != "" {
+			return sha
+		}
+	}
+	return ""
+}
+
+// Sections folds a job's log into collapsible sections, in the syntax of
+// the platform the job runs on, or plain headings without one
+type Sections struct {
+	w        io.Writer
+	platform string
+	open     string // the GitLab section's name
+	now      func() time.Time
+}
+
+// NewSections writes the sections of a log for platform, which may be nil
+func NewSections(w io.Writer, platform *Platform) *Sections {
+	s := &Sections{w: w, now: time.Now}
+	if platform != nil {
+		s.platform = platform.Name
+	}
+	return s
+}
+
+var sectionNameRe = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// Start begins a section titled title, ending the one before
+func (s *Sections) Start(title string) {
+	s.End()
+	s.open = title
+	switch s.platform {
+	case "github", "forgejo":
+		fmt.Fprintf(s.w, "::group::%s\n", title)
+	case "gitlab":
+		s.open = strings.Trim(sectionNameRe.ReplaceAllString(strings.ToLower(title), "_"), "_")
+		fmt.Fprintf(s.w, "\x1b[0Ksection_start:%d:%s\r\x1b[0K%s\n", s.now().Unix(), s.open, title)
+	default:
+		fmt.Fprintf(s.w, "--- %s\n", title)
+	}
+}
+
+// End ends the open section, if there is one
+func (s *Sections) End() {
+	if s.open == "" {
+		return
+	}
+	switch s.platform {
+	case "github", "forgejo":
+		fmt.Fprintln(s.w, "::endgroup::")
+	case "gitlab":
+		fmt.Fprintf(s.w, "\x1b[0Ksection_end:%d:%s\r\x1b[0K\n", s.now().Unix(), s.open)
+	}
+	s.open = ""
+}
+
+// SetOutput sets an output of the step on GitHub and Forgejo Actions, for
+// later steps to read. It does nothing elsewhere.
+func SetOutput(name, value string) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open step outputs: %w", err)
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintf(f, "%s=%s\n", name, strings.ReplaceAll(value, "\n", " ")); err != nil {
+		return fmt.Errorf("failed to write step outputs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package ci
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSections(t *testing.T) {
+	for _, tt := range []struct {
+		platform *Platform
+		want     string
+	}{
+		{Lookup("github"), "::group::Deploy web\nqueued\n::endgroup::\n::group::Build logs\n::endgroup::\n"},
+		{Lookup("gitlab"), "\x1b[0Ksection_start:100:deploy_web\r\x1b[0KDeploy web\nqueued\n\x1b[0Ksection_end:100:deploy_web\r\x1b[0K\n" +
+			"\x1b[0Ksection_start:100:build_logs\r\x1b[0KBuild logs\n\x1b[0Ksection_end:100:build_logs\r\x1b[0K\n"},
+		{nil, "--- Deploy web\nqueued\n--- Build logs\n"},
+	} {
+		var buf bytes.Buffer
+		s := NewSections(&buf, tt.platform)
+		s.now = func() time.Time { return time.Unix(100, 0) }
+		s.Start("Deploy web")
+		buf.WriteString("queued\n")
+		s.Start("Build logs")
+		s.End()
+		s.End()
+		if buf.String() != tt.want {
+			t.Errorf("sections on %v = %q, want %q", tt.platform, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for _, env := range []string{"FORGEJO_ACTIONS", "GITHUB_ACTIONS", "GITLAB_CI", "CI"} {
+		t.Setenv(env, "")
+	}
+	if p := Detect(); p != nil {
+		t.Errorf("Detect() outside CI = %s", p.Name)
+	}
+	t.Setenv("GITHUB_ACTIONS", "true")
+	if p := Detect(); p == nil || p.Name != "github" {
+		t.Errorf("Detect() on GitHub Actions = %v", p)
+	}
+	t.Setenv("FORGEJO_ACTIONS", "true")
+	if p := Detect(); p == nil || p.Name != "forgejo" {
+		t.Errorf("Detect() on Forgejo Actions = %v", p)
+	}
+}
+
+func TestSetOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	t.Setenv("GITHUB_OUTPUT", path)
+	if err := SetOutput("status", "finished"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetOutput("url", "https://web.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "status=finished\nurl=https://web.example.com\n"; string(data) != want {
+		t.Errorf("outputs = %q, want %q", data, want)
+	}
+}
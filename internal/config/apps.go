@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// appsConfigDir holds one project config per application in a monorepo
+const appsConfigDir = ".coolify-deployer/apps"
+
+// selectedApp is the monorepo application LoadProject and SaveProject use.
+// Empty means the repository's single project config.
+var selectedApp string
+
+// SelectApp makes LoadProject and SaveProject use the config of a monorepo
+// application (.coolify-deployer/apps/NAME.json). An empty name selects the
+// repository's single project config again.
+func SelectApp(name string) error {
+	if name != "" && (strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".local")) {
+		return fmt.Errorf("invalid app name %q", name)
+	}
+	selectedApp = name
+	globalCfg = nil // the app may pin another instance
+	return nil
+}
+
+// SelectedApp returns the monorepo application selected with SelectApp
+func SelectedApp() string {
+	return selectedApp
+}
+
+// ListApps returns the names of the monorepo applications configured in the
+// current directory, sorted
+func ListApps() ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(cwd, appsConfigDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read app configs: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") && !strings.HasSuffix(e.Name(), localConfigSuffix) {
+			names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SourceDir returns the application's directory relative to the repository
+// root, or "." when it is built from the root
+func (p *ProjectConfig) SourceDir() string {
+	dir := strings.Trim(filepath.ToSlash(p.BaseDirectory), "/")
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
+
+// MatchesPath reports whether a change to file (slash-separated, relative to
+// the repository root) affects the application. WatchPaths are used when set,
+// otherwise everything under the base directory matches.
+func (p *ProjectConfig) MatchesPath(file string) bool {
+	patterns := p.WatchPaths
+	if len(patterns) == 0 {
+		if p.SourceDir() == "." {
+			return true
+		}
+		patterns = []string{p.SourceDir() + "/**"}
+	}
+
+	for _, pattern := range patterns {
+		if matchPathPattern(strings.TrimPrefix(pattern, "/"), file) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathPattern matches a glob where a trailing "/**" (or a plain
+// directory) matches everything below it
+func matchPathPattern(pattern, file string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return file == prefix || strings.HasPrefix(file, prefix+"/")
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return file == pattern || strings.HasPrefix(file, strings.TrimSuffix(pattern, "/")+"/")
+	}
+	ok, _ := path.Match(pattern, file)
+	return ok
+}
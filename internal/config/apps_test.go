@@ -0,0 +1,48 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchesPath(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ProjectConfig
+		file string
+		want bool
+	}{
+		{"root app matches everything", ProjectConfig{}, "README.md", true},
+		{"base directory", ProjectConfig{BaseDirectory: "/apps/api"}, "apps/api/main.go", true},
+		{"outside base directory", ProjectConfig{BaseDirectory: "/apps/api"}, "apps/web/index.ts", false},
+		{"base directory prefix is not a match", ProjectConfig{BaseDirectory: "apps/api"}, "apps/api-docs/README.md", false},
+		{"watch path glob", ProjectConfig{BaseDirectory: "apps/api", WatchPaths: []string{"packages/*/go.mod"}}, "packages/shared/go.mod", true},
+		{"watch paths replace base directory", ProjectConfig{BaseDirectory: "apps/api", WatchPaths: []string{"packages/**"}}, "apps/api/main.go", false},
+		{"watch path directory", ProjectConfig{WatchPaths: []string{"/libs/core/"}}, "libs/core/x/y.go", true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.cfg.MatchesPath(tt.file); got != tt.want {
+			t.Errorf("%s: MatchesPath(%q) = %v, want %v", tt.name, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestListAppsSkipsLocalConfigs(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, name := range []string{"api.json", "api.local.json", "web.json"} {
+		writeFile(t, filepath.Join(dir, appsConfigDir, name), `{}`)
+	}
+
+	names, err := ListApps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "api" || names[1] != "web" {
+		t.Errorf("apps = %v", names)
+	}
+	if err := SelectApp("api.local"); err == nil {
+		t.Error("selected a local config as an app")
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// legacyAzureConfigPath is where 'azure deploy' kept its settings before
+// they moved to the azure section of the CLI config
+func legacyAzureConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".coolify", "azure-config.json")
+}
+
+// Validate checks the settings needed to deploy Coolify to Azure
+func (a *AzureConfig) Validate() error {
+	var problems []string
+	required := func(key, value string) {
+		if value == "" {
+			problems = append(problems, fmt.Sprintf("azure.%s is required", key))
+		}
+	}
+	port := func(key string, value int) {
+		if value < 1 || value > 65535 {
+			problems = append(problems, fmt.Sprintf("azure.%s must be a port between 1 and 65535, not %d", key, value))
+		}
+	}
+
+	required("location", a.Location)
+	required("vm_size", a.VMSize)
+	required("admin_username", a.AdminUsername)
+	required("ssh_key_path", a.SSHKeyPath)
+	if a.SSHKeyPath != "" {
+		if _, err := os.Stat(ExpandHome(a.SSHKeyPath)); os.IsNotExist(err) {
+			problems = append(problems, fmt.Sprintf("azure.ssh_key_path: SSH public key file does not exist: %s", a.SSHKeyPath))
+		}
+	}
+
+	port("networking.app_port", a.Networking.AppPort)
+	port("networking.ssh_port", a.Networking.SSHPort)
+	port("networking.websocket_port", a.Networking.WebSocketPort)
+
+	required("coolify.default_admin_email", a.Coolify.DefaultAdminEmail)
+	required("coolify.default_admin_password", a.Coolify.DefaultAdminPassword)
+	required("coolify.app_url_template", a.Coolify.AppURLTemplate)
+	required("paths.remote_base", a.Paths.RemoteBase)
+	required("docker.registry_url", a.Docker.RegistryURL)
+	required("docker.app_image", a.Docker.AppImage)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("configuration validation failed:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// ExpandHome replaces a leading ~/ in path with the home directory
+func ExpandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestAzureValidate(t *testing.T) {
+	key := filepath.Join(t.TempDir(), "id_rsa.pub")
+	writeFile(t, key, "ssh-rsa AAAAB3...")
+
+	valid := func() *AzureConfig {
+		azure := defaultConfig().Azure
+		azure.SSHKeyPath = key
+		return &azure
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("defaults failed validation: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*AzureConfig)
+		want   string
+	}{
+		{"empty location", func(a *AzureConfig) { a.Location = "" }, "azure.location is required"},
+		{"empty VM size", func(a *AzureConfig) { a.VMSize = "" }, "azure.vm_size is required"},
+		{"missing SSH key", func(a *AzureConfig) { a.SSHKeyPath = key + ".missing" }, "does not exist"},
+		{"invalid app port", func(a *AzureConfig) { a.Networking.AppPort = 0 }, "azure.networking.app_port must be a port"},
+		{"port too high", func(a *AzureConfig) { a.Networking.SSHPort = 70000 }, "azure.networking.ssh_port must be a port"},
+		{"empty admin email", func(a *AzureConfig) { a.Coolify.DefaultAdminEmail = "" }, "azure.coolify.default_admin_email is required"},
+		{"empty docker image", func(a *AzureConfig) { a.Docker.AppImage = "" }, "azure.docker.app_image is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			azure := valid()
+			tt.modify(azure)
+			err := azure.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateAzureConfig(t *testing.T) {
+	home := withHome(t)
+	writeFile(t, filepath.Join(home, ".coolify", "azure-config.json"), `{
+  "infrastructure": {"location": "westeurope", "vm_size": "Standard_B4ms", "ssh_public_key_path": "~/.ssh/azure.pub", "image_sku": "20_04-lts-gen2", "os_disk_size_gb": 64},
+  "networking": {"app_port": 8080, "vnet_address_prefix": "10.1.0.0/16"},
+  "coolify": {"db_password": "secret"},
+  "docker": {"registry_url": "registry.example.com", "unknown": true}
+}`)
+	path := filepath.Join(home, ".cool-kit", "config.json")
+	// vm_size was changed in the CLI config, location still has its default
+	writeFile(t, path, `{"config_version": 1, "azure": {"location": "swedencentral", "vm_size": "Standard_D2s_v3", "resource_group": "prod-rg"}}`)
+
+	applied, err := migrateFile(path, cliMigrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 {
+		t.Errorf("applied %v", applied)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	a := cfg.Azure
+	if a.Location != "westeurope" || a.VMSize != "Standard_D2s_v3" || a.ResourceGroup != "prod-rg" || a.SSHKeyPath != "~/.ssh/azure.pub" || a.OSDiskSizeGB != 64 {
+		t.Errorf("azure = %+v", a)
+	}
+	if a.Networking.AppPort != 8080 || a.Networking.VNetAddressPrefix != "10.1.0.0/16" || a.Coolify.DBPassword != "secret" || a.Docker.RegistryURL != "registry.example.com" {
+		t.Errorf("azure sections = %+v", a)
+	}
+	if strings.Contains(string(data), "image_sku") || strings.Contains(string(data), "unknown") {
+		t.Errorf("copied keys that aren't in the schema: %s", data)
+	}
+}
+
+func TestInitializeImportsAzureConfig(t *testing.T) {
+	home := withHome(t)
+	savedConfig, savedDir := globalConfig, configDir
+	t.Cleanup(func() {
+		globalConfig, configDir = savedConfig, savedDir
+		viper.Reset()
+	})
+	viper.Reset()
+	// Only 'azure deploy' was ever run, so there's no CLI config yet
+	writeFile(t, filepath.Join(home, ".coolify", "azure-config.json"), `{
+  "infrastructure": {"location": "westeurope", "admin_username": "ops"},
+  "networking": {"app_port": 8080},
+  "coolify": {"default_admin_password": "s3cret"},
+  "paths": {"remote_base": "/srv/coolify"}
+}`)
+
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	for _, cfg := range []*Config{Get(), readConfig(t, filepath.Join(home, ".cool-kit", "config.json"))} {
+		a := cfg.Azure
+		if a.Location != "westeurope" || a.AdminUsername != "ops" || a.Networking.AppPort != 8080 || a.Coolify.DefaultAdminPassword != "s3cret" || a.Paths.RemoteBase != "/srv/coolify" {
+			t.Errorf("azure = %+v", a)
+		}
+		if a.VMSize != defaultConfig().Azure.VMSize || cfg.ConfigVersion != ConfigVersion {
+			t.Errorf("defaults lost: vm_size = %s, config_version = %d", a.VMSize, cfg.ConfigVersion)
+		}
+	}
+}
+
+func readConfig(t *testing.T, path string) *Config {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
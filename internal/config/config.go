@@ -14,13 +14,16 @@ import (
 
 // Config holds all CLI configuration
 type Config struct {
+	// ConfigVersion is the version of the file's layout, see ConfigVersion
+	ConfigVersion int `json:"config_version" mapstructure:"config_version"`
+
 	// Multi-instance configuration
 	Instances           []Instance `json:"instances"`
 	CurrentContext      string     `json:"current_context"`
 	LastUpdateCheckTime string     `json:"lastUpdateCheckTime"`
 
 	// Legacy/Single-instance configuration (kept for backward compatibility)
-	Provider    string                 `json:"provider"`
+	Provider    string                 `json:"provider" validate:"oneof=local azure aws gcp hetzner digitalocean baremetal docker production"`
 	Environment string                 `json:"environment"`
 	Settings    map[string]interface{} `json:"settings"`
 	Git         GitConfig              `json:"git"`
@@ -30,6 +33,10 @@ type Config struct {
 	BareMetal   BareMetalConfig        `json:"baremetal,omitempty"`
 	Local       LocalConfig            `json:"local,omitempty"`
 	Production  ProductionConfig       `json:"production,omitempty"`
+	Listen      ListenConfig           `json:"listen,omitempty"`
+
+	Notifications NotificationsConfig `json:"notifications,omitempty"`
+	UI            UIConfig            `json:"ui,omitempty"`
 
 	path string // config file path (not serialized)
 }
@@ -37,6 +44,7 @@ type Config struct {
 // New creates a new config with default values
 func New() *Config {
 	return &Config{
+		ConfigVersion:       ConfigVersion,
 		Instances:           []Instance{},
 		LastUpdateCheckTime: time.Now().Format(time.RFC3339),
 		Settings:            make(map[string]interface{}),
@@ -51,6 +59,10 @@ type Instance struct {
 	FQDN    string `json:"fqdn"`
 	Token   string `json:"token"`
 	Default bool   `json:"default"`
+
+	// Detected when the instance was added
+	Version string `json:"version,omitempty"`
+	Team    string `json:"team,omitempty"`
 }
 
 // Validate validates the instance configuration
@@ -72,6 +84,10 @@ type GitConfig struct {
 	Repository string `json:"repository"`
 	Branch     string `json:"branch"`
 	WorkDir    string `json:"work_dir"`
+
+	// Shell runs the deploys' git operations with the git CLI instead of
+	// go-git, see EnvGitShell
+	Shell bool `json:"shell,omitempty"`
 }
 
 // AzureConfig represents Azure-specific configuration
@@ -83,12 +99,61 @@ type AzureConfig struct {
 	AdminUsername  string `json:"admin_username" mapstructure:"admin_username"`
 	SSHKeyPath     string `json:"ssh_key_path" mapstructure:"ssh_key_path"`
 	SubscriptionID string `json:"subscription_id" mapstructure:"subscription_id"`
+	OSImage        string `json:"os_image" mapstructure:"os_image"`
+	OSDiskSizeGB   int    `json:"os_disk_size_gb" mapstructure:"os_disk_size_gb"`
+
+	Networking AzureNetworkingConfig `json:"networking" mapstructure:"networking"`
+	Coolify    AzureCoolifyConfig    `json:"coolify" mapstructure:"coolify"`
+	Paths      AzurePathsConfig      `json:"paths" mapstructure:"paths"`
+	Docker     AzureDockerConfig     `json:"docker" mapstructure:"docker"`
+}
+
+// AzureNetworkingConfig contains the network settings of an Azure VM
+type AzureNetworkingConfig struct {
+	AppPort             int    `json:"app_port" mapstructure:"app_port" validate:"port"`
+	SSHPort             int    `json:"ssh_port" mapstructure:"ssh_port" validate:"port"`
+	WebSocketPort       int    `json:"websocket_port" mapstructure:"websocket_port" validate:"port"`
+	VNetAddressPrefix   string `json:"vnet_address_prefix" mapstructure:"vnet_address_prefix"`
+	SubnetAddressPrefix string `json:"subnet_address_prefix" mapstructure:"subnet_address_prefix"`
+}
+
+// AzureCoolifyConfig contains the Coolify settings installed on an Azure VM
+type AzureCoolifyConfig struct {
+	DefaultAdminEmail    string `json:"default_admin_email" mapstructure:"default_admin_email"`
+	DefaultAdminPassword string `json:"default_admin_password" mapstructure:"default_admin_password"`
+	AppURLTemplate       string `json:"app_url_template" mapstructure:"app_url_template"`
+	PusherHostTemplate   string `json:"pusher_host_template" mapstructure:"pusher_host_template"`
+	PusherPort           int    `json:"pusher_port" mapstructure:"pusher_port" validate:"port"`
+	AppID                string `json:"app_id" mapstructure:"app_id"`
+	AppKey               string `json:"app_key" mapstructure:"app_key"`
+	DBPassword           string `json:"db_password" mapstructure:"db_password"`
+	RedisPassword        string `json:"redis_password" mapstructure:"redis_password"`
+	PusherAppID          string `json:"pusher_app_id" mapstructure:"pusher_app_id"`
+	PusherAppKey         string `json:"pusher_app_key" mapstructure:"pusher_app_key"`
+	PusherAppSecret      string `json:"pusher_app_secret" mapstructure:"pusher_app_secret"`
+}
+
+// AzurePathsConfig contains the paths of Coolify on an Azure VM
+type AzurePathsConfig struct {
+	RemoteBase    string `json:"remote_base" mapstructure:"remote_base"`
+	RemoteEnv     string `json:"remote_env" mapstructure:"remote_env"`
+	RemoteStatus  string `json:"remote_status" mapstructure:"remote_status"`
+	RemoteLogs    string `json:"remote_logs" mapstructure:"remote_logs"`
+	RemoteBackups string `json:"remote_backups" mapstructure:"remote_backups"`
+}
+
+// AzureDockerConfig contains the Docker images run on an Azure VM
+type AzureDockerConfig struct {
+	RegistryURL   string `json:"registry_url" mapstructure:"registry_url"`
+	HelperImage   string `json:"helper_image" mapstructure:"helper_image"`
+	AppImage      string `json:"app_image" mapstructure:"app_image"`
+	RealtimeImage string `json:"realtime_image" mapstructure:"realtime_image"`
 }
 
 // LocalConfig represents local development configuration
 type LocalConfig struct {
-	AppPort       int    `json:"app_port"`
-	WebSocketPort int    `json:"websocket_port"`
+	AppPort       int    `json:"app_port" validate:"port"`
+	WebSocketPort int    `json:"websocket_port" validate:"port"`
 	WorkDir       string `json:"work_dir"`
 	Debug         bool   `json:"debug"`
 }
@@ -101,6 +166,40 @@ type ProductionConfig struct {
 	Namespace  string `json:"namespace"`
 }
 
+// ListenConfig configures the actions run by 'cool-kit listen' for Coolify webhooks
+type ListenConfig struct {
+	Addr            string   `json:"addr,omitempty" mapstructure:"addr"`
+	Secret          string   `json:"secret,omitempty" mapstructure:"secret"`
+	Events          []string `json:"events,omitempty" mapstructure:"events"`
+	Hook            string   `json:"hook,omitempty" mapstructure:"hook"`
+	Notify          bool     `json:"notify,omitempty" mapstructure:"notify"`
+	SlackWebhookURL string   `json:"slack_webhook_url,omitempty" mapstructure:"slack_webhook_url"`
+}
+
+// DefaultNotifyAfter is how long a run takes before it notifies by default
+const DefaultNotifyAfter = 5 * time.Minute
+
+// UIConfig configures how the CLI prints
+type UIConfig struct {
+	// Accessible prints plain lines for screen readers, see EnvAccessible
+	Accessible bool `json:"accessible,omitempty" mapstructure:"accessible"`
+}
+
+// NotificationsConfig configures the desktop notification shown when a long
+// install or deployment finishes
+type NotificationsConfig struct {
+	Desktop      bool `json:"desktop,omitempty" mapstructure:"desktop"`
+	AfterMinutes int  `json:"after_minutes,omitempty" mapstructure:"after_minutes"`
+}
+
+// After returns how long a run takes before it notifies
+func (n NotificationsConfig) After() time.Duration {
+	if n.AfterMinutes <= 0 {
+		return DefaultNotifyAfter
+	}
+	return time.Duration(n.AfterMinutes) * time.Minute
+}
+
 // AWSConfig represents AWS-specific configuration
 type AWSConfig struct {
 	Region       string `json:"region"`
@@ -126,12 +225,13 @@ type BareMetalConfig struct {
 	Host       string `json:"host"`
 	User       string `json:"user"`
 	SSHKeyPath string `json:"ssh_key_path"`
-	Port       int    `json:"port"`
+	Port       int    `json:"port" validate:"port"`
 }
 
 var (
 	globalConfig *Config
 	configDir    string
+	fileProvider string // the provider saved in the file, before overrides
 )
 
 // Default configuration values for CDP functionality
@@ -149,9 +249,6 @@ func Initialize() error {
 	}
 
 	configDir = filepath.Join(home, ".cool-kit")
-	if err := os.MkdirAll(configDir, 0750); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
 
 	// Initialize viper
 	viper.SetConfigName("config")
@@ -162,23 +259,59 @@ func Initialize() error {
 	// Set defaults
 	setDefaults()
 
+	// Upgrade a file written by an older release before reading it
+	configFile := filepath.Join(configDir, "config.json")
+	applied, err := migrateFile(configFile, cliMigrations)
+	if err != nil {
+		return err
+	}
+	for _, summary := range applied {
+		fmt.Fprintf(os.Stderr, "Upgraded %s: %s\n", configFile, summary)
+	}
+
 	// Read config file if it exists
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			// Config file not found; create default config
-			return createDefaultConfig()
+			// Config file not found; create default config, only in
+			// memory when the overrides configure the CLI
+			cfg, err := importedConfig()
+			if err != nil {
+				return err
+			}
+			if o := ActiveOverrides(); o.Provider != "" || (o.FQDN != "" && o.Token != "") {
+				globalConfig = cfg
+				fileProvider = globalConfig.Provider
+				applyProviderOverride()
+				return nil
+			}
+			return createDefaultConfig(cfg)
 		}
 		return fmt.Errorf("failed to read config: %w", err)
 	}
 
-	// Unmarshal config
-	if err := viper.Unmarshal(&globalConfig); err != nil {
+	// Unmarshal config through JSON: viper's decoder reads mapstructure tags
+	// only, which would drop keys like current_context
+	data, err := json.Marshal(viper.AllSettings())
+	if err != nil {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	globalConfig = &Config{}
+	if err := json.Unmarshal(data, globalConfig); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	fileProvider = globalConfig.Provider
+	applyProviderOverride()
 
 	return nil
 }
 
+// applyProviderOverride sets the provider given by the overrides, if any
+func applyProviderOverride() {
+	if provider := ActiveOverrides().Provider; provider != "" {
+		globalConfig.Provider = provider
+	}
+}
+
 // setDefaults sets default configuration values
 func setDefaults() {
 	viper.SetDefault("git.repository", "https://github.com/coollabsio/coolify.git")
@@ -191,6 +324,27 @@ func setDefaults() {
 	viper.SetDefault("azure.vm_size", "Standard_B2s")
 	viper.SetDefault("azure.admin_username", "azureuser")
 	viper.SetDefault("azure.ssh_key_path", "~/.ssh/id_rsa.pub")
+	viper.SetDefault("azure.os_image", "Canonical:0001-com-ubuntu-server-jammy:22_04-lts-gen2:latest")
+	viper.SetDefault("azure.os_disk_size_gb", 30)
+	viper.SetDefault("azure.networking.app_port", 80)
+	viper.SetDefault("azure.networking.ssh_port", 22)
+	viper.SetDefault("azure.networking.websocket_port", 6001)
+	viper.SetDefault("azure.networking.vnet_address_prefix", "10.0.0.0/16")
+	viper.SetDefault("azure.networking.subnet_address_prefix", "10.0.1.0/24")
+	viper.SetDefault("azure.coolify.default_admin_email", "admin@coolify.local")
+	viper.SetDefault("azure.coolify.default_admin_password", "admin123")
+	viper.SetDefault("azure.coolify.app_url_template", "http://{public_ip}")
+	viper.SetDefault("azure.coolify.pusher_host_template", "{public_ip}")
+	viper.SetDefault("azure.coolify.pusher_port", 6001)
+	viper.SetDefault("azure.paths.remote_base", "/home/azureuser/coolify")
+	viper.SetDefault("azure.paths.remote_env", "/home/azureuser/coolify/.env")
+	viper.SetDefault("azure.paths.remote_status", "/home/azureuser/coolify/.upgrade-status")
+	viper.SetDefault("azure.paths.remote_logs", "/home/azureuser/coolify/logs")
+	viper.SetDefault("azure.paths.remote_backups", "/home/azureuser/coolify/backups")
+	viper.SetDefault("azure.docker.registry_url", "ghcr.io")
+	viper.SetDefault("azure.docker.helper_image", "coollabsio/coolify-helper")
+	viper.SetDefault("azure.docker.app_image", "coollabsio/coolify")
+	viper.SetDefault("azure.docker.realtime_image", "coollabsio/coolify-realtime")
 
 	viper.SetDefault("local.app_port", 8000)
 	viper.SetDefault("local.websocket_port", 6001)
@@ -219,9 +373,21 @@ func setDefaults() {
 	viper.SetDefault("baremetal.ssh_key_path", "~/.ssh/id_rsa")
 }
 
-// createDefaultConfig creates a default configuration file
-func createDefaultConfig() error {
-	defaultConfig := &Config{
+// createDefaultConfig saves cfg as the first configuration file
+func createDefaultConfig(cfg *Config) error {
+	// Save default config
+	if err := Save(cfg); err != nil {
+		return fmt.Errorf("failed to save default config: %w", err)
+	}
+
+	globalConfig = cfg
+	return nil
+}
+
+// defaultConfig returns the configuration used when there's no file
+func defaultConfig() *Config {
+	return &Config{
+		ConfigVersion:       ConfigVersion,
 		Instances:           []Instance{},
 		LastUpdateCheckTime: time.Now().Format(time.RFC3339),
 		Provider:            "local",
@@ -239,6 +405,35 @@ func createDefaultConfig() error {
 			VMSize:        "Standard_B2s",
 			AdminUsername: "azureuser",
 			SSHKeyPath:    "~/.ssh/id_rsa.pub",
+			OSImage:       "Canonical:0001-com-ubuntu-server-jammy:22_04-lts-gen2:latest",
+			OSDiskSizeGB:  30,
+			Networking: AzureNetworkingConfig{
+				AppPort:             80,
+				SSHPort:             22,
+				WebSocketPort:       6001,
+				VNetAddressPrefix:   "10.0.0.0/16",
+				SubnetAddressPrefix: "10.0.1.0/24",
+			},
+			Coolify: AzureCoolifyConfig{
+				DefaultAdminEmail:    "admin@coolify.local",
+				DefaultAdminPassword: "admin123",
+				AppURLTemplate:       "http://{public_ip}",
+				PusherHostTemplate:   "{public_ip}",
+				PusherPort:           6001,
+			},
+			Paths: AzurePathsConfig{
+				RemoteBase:    "/home/azureuser/coolify",
+				RemoteEnv:     "/home/azureuser/coolify/.env",
+				RemoteStatus:  "/home/azureuser/coolify/.upgrade-status",
+				RemoteLogs:    "/home/azureuser/coolify/logs",
+				RemoteBackups: "/home/azureuser/coolify/backups",
+			},
+			Docker: AzureDockerConfig{
+				RegistryURL:   "ghcr.io",
+				HelperImage:   "coollabsio/coolify-helper",
+				AppImage:      "coollabsio/coolify",
+				RealtimeImage: "coollabsio/coolify-realtime",
+			},
 		},
 		AWS: AWSConfig{
 			Region:       "us-east-1",
@@ -269,14 +464,6 @@ func createDefaultConfig() error {
 			Namespace: "coolify",
 		},
 	}
-
-	// Save default config
-	if err := Save(defaultConfig); err != nil {
-		return fmt.Errorf("failed to save default config: %w", err)
-	}
-
-	globalConfig = defaultConfig
-	return nil
 }
 
 // Get returns the global configuration
@@ -284,16 +471,61 @@ func Get() *Config {
 	return globalConfig
 }
 
+// ReadUI returns the ui section of the CLI config. It reads just that from
+// the file when the config isn't loaded, so the output mode is known before
+// a command loads it, and never creates the file.
+func ReadUI() UIConfig {
+	if globalConfig != nil {
+		return globalConfig.UI
+	}
+	var file struct {
+		UI UIConfig `json:"ui"`
+	}
+	readConfigFile(&file)
+	return file.UI
+}
+
+// ReadGit returns the git section of the CLI config, read like ReadUI
+func ReadGit() GitConfig {
+	if globalConfig != nil {
+		return globalConfig.Git
+	}
+	var file struct {
+		Git GitConfig `json:"git"`
+	}
+	readConfigFile(&file)
+	return file.Git
+}
+
+// readConfigFile decodes the CLI config file into v, if there is one
+func readConfigFile(v interface{}) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".cool-kit", "config.json"))
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, v)
+}
+
 // Save saves the configuration to file
 func Save(cfg *Config) error {
 	globalConfig = cfg
 
 	// Update viper
+	viper.Set("config_version", ConfigVersion)
 	viper.Set("instances", cfg.Instances)
 	viper.Set("current_context", cfg.CurrentContext)
 	viper.Set("lastUpdateCheckTime", cfg.LastUpdateCheckTime)
 
-	viper.Set("provider", cfg.Provider)
+	// An overridden provider isn't saved, unless it was changed since
+	provider := cfg.Provider
+	if override := ActiveOverrides().Provider; override != "" && provider == override {
+		provider = fileProvider
+	}
+	viper.Set("provider", provider)
 	viper.Set("environment", cfg.Environment)
 	viper.Set("settings", cfg.Settings)
 	viper.Set("git", cfg.Git)
@@ -303,10 +535,23 @@ func Save(cfg *Config) error {
 	viper.Set("aws", cfg.AWS)
 	viper.Set("gcp", cfg.GCP)
 	viper.Set("baremetal", cfg.BareMetal)
+	viper.Set("listen", cfg.Listen)
+	viper.Set("notifications", cfg.Notifications)
+	viper.Set("ui", cfg.UI)
 
 	// Write config file
-	configFile := filepath.Join(configDir, "config.json")
-	return viper.WriteConfigAs(configFile)
+	if err := os.MkdirAll(configDir, 0750); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(viper.AllSettings(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := saveFile(filepath.Join(configDir, "config.json"), data, 0600); err != nil {
+		return err
+	}
+	fileProvider = provider
+	return nil
 }
 
 // UpdateProvider updates the provider and saves config
@@ -429,7 +674,8 @@ func ImportConfig(jsonData string) error {
 }
 
 // AddInstance adds a new instance to the configuration
-func AddInstance(name, fqdn, token string, setAsDefault bool) error {
+func AddInstance(inst Instance, setAsDefault bool) error {
+	name := inst.Name
 	cfg := Get()
 	if cfg == nil {
 		return fmt.Errorf("configuration not initialized")
@@ -452,12 +698,8 @@ func AddInstance(name, fqdn, token string, setAsDefault bool) error {
 	}
 
 	// Add new instance
-	cfg.Instances = append(cfg.Instances, Instance{
-		Name:    name,
-		FQDN:    fqdn,
-		Token:   token,
-		Default: len(cfg.Instances) == 0 || setAsDefault,
-	})
+	inst.Default = len(cfg.Instances) == 0 || setAsDefault
+	cfg.Instances = append(cfg.Instances, inst)
 
 	return Save(cfg)
 }
@@ -552,8 +794,14 @@ func HasInstances() bool {
 	return len(cfg.Instances) > 0
 }
 
-// GetCurrentInstance returns the current instance based on context
+// GetCurrentInstance returns the current instance based on context, or the
+// one selected by the flag and environment overrides or pinned by the
+// current project
 func GetCurrentInstance() (*Instance, error) {
+	if inst, err := selectedInstance(ActiveOverrides()); inst != nil || err != nil {
+		return inst, err
+	}
+
 	cfg := Get()
 	if cfg == nil {
 		return nil, fmt.Errorf("configuration not initialized")
@@ -0,0 +1,76 @@
+package config
+
+// ProductionEnvironment is the Coolify environment of the project's own
+// application
+const ProductionEnvironment = "production"
+
+// EnvironmentConfig is the application a project deploys to in a Coolify
+// environment other than production, created on its first deployment
+type EnvironmentConfig struct {
+	EnvironmentUUID string   `json:"environment_uuid,omitempty"`
+	AppUUID         string   `json:"app_uuid,omitempty"`
+	Domain          string   `json:"domain,omitempty"`
+	ImageTags       []string `json:"image_tags,omitempty"`
+}
+
+// EnvironmentName returns the name of the Coolify environment the config
+// deploys to
+func (p *ProjectConfig) EnvironmentName() string {
+	if p.environment == "" {
+		return ProductionEnvironment
+	}
+	return p.environment
+}
+
+// ForEnvironment returns the config that deploys branch to the environment
+// called name: the project itself for production, otherwise a copy with
+// that environment's application and without workers. SaveProject saves
+// the copy's application into the project's Environments.
+func (p *ProjectConfig) ForEnvironment(name, branch string) *ProjectConfig {
+	if name == "" || name == ProductionEnvironment {
+		return p
+	}
+	env := p.Environments[name]
+	if env == nil {
+		env = &EnvironmentConfig{}
+	}
+	c := *p
+	c.environment = name
+	c.Branch = branch
+	c.EnvironmentUUID = env.EnvironmentUUID
+	c.AppUUID = env.AppUUID
+	c.Domain = env.Domain
+	c.ImageTags = env.ImageTags
+	c.Workers = nil
+	return &c
+}
+
+// saveEnvironment saves a config returned by ForEnvironment: its
+// environment's application, and the settings it shares with the project
+func saveEnvironment(cfg *ProjectConfig) error {
+	saved, err := LoadProject()
+	if err != nil {
+		return err
+	}
+
+	project := *cfg
+	project.environment = ""
+	project.Branch = saved.Branch
+	project.EnvironmentUUID = saved.EnvironmentUUID
+	project.AppUUID = saved.AppUUID
+	project.Domain = saved.Domain
+	project.ImageTags = saved.ImageTags
+	project.Workers = saved.Workers
+
+	project.Environments = make(map[string]*EnvironmentConfig, len(saved.Environments)+1)
+	for name, env := range saved.Environments {
+		project.Environments[name] = env
+	}
+	project.Environments[cfg.environment] = &EnvironmentConfig{
+		EnvironmentUUID: cfg.EnvironmentUUID,
+		AppUUID:         cfg.AppUUID,
+		Domain:          cfg.Domain,
+		ImageTags:       cfg.ImageTags,
+	}
+	return SaveProject(&project)
+}
@@ -0,0 +1,48 @@
+package config
+
+import "testing"
+
+func TestSaveEnvironment(t *testing.T) {
+	t.Chdir(t.TempDir())
+	project := &ProjectConfig{
+		Name:               "web",
+		DeployMethod:       DeployMethodGit,
+		Branch:             "main",
+		AppUUID:            "prod-app",
+		Workers:            []WorkerConfig{{Name: "jobs", StartCommand: "npm run jobs"}},
+		BranchEnvironments: map[string]string{"main": ProductionEnvironment, "develop": "staging"},
+	}
+	if err := SaveProject(project); err != nil {
+		t.Fatal(err)
+	}
+	if got := project.ForEnvironment(ProductionEnvironment, "main"); got != project {
+		t.Error("production isn't the project itself")
+	}
+
+	staging := project.ForEnvironment("staging", "develop")
+	if staging.AppUUID != "" || staging.Branch != "develop" || staging.Workers != nil || staging.EnvironmentName() != "staging" {
+		t.Fatalf("staging = %+v", staging)
+	}
+	staging.AppUUID = "staging-app"
+	staging.PrivateKeyUUID = "key"
+	if err := SaveProject(staging); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := LoadProject()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.AppUUID != "prod-app" || saved.Branch != "main" || len(saved.Workers) != 1 {
+		t.Errorf("production application changed: %+v", saved)
+	}
+	if saved.PrivateKeyUUID != "key" {
+		t.Errorf("private key = %q, want the one set while deploying to staging", saved.PrivateKeyUUID)
+	}
+	if env := saved.Environments["staging"]; env == nil || env.AppUUID != "staging-app" {
+		t.Errorf("staging = %+v", env)
+	}
+	if again := saved.ForEnvironment("staging", "develop"); again.AppUUID != "staging-app" {
+		t.Errorf("staging application = %q after loading", again.AppUUID)
+	}
+}
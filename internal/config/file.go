@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockTimeout is how long a save waits for another process saving the same
+// file
+const lockTimeout = 10 * time.Second
+
+// saveFile replaces the file at path with data, holding its lock
+func saveFile(path string, data []byte, perm os.FileMode) error {
+	return withFileLock(path, func() error {
+		return writeFileAtomic(path, data, perm)
+	})
+}
+
+// withFileLock runs fn holding the advisory lock of path (path.lock), so
+// concurrent cool-kit processes don't interleave their writes
+func withFileLock(path string, fn func() error) error {
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file: %w", err)
+	}
+	// Closing the file releases the lock
+	defer lock.Close()
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLock(lock)
+		if err != nil {
+			return fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s is locked by another cool-kit process", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fn()
+}
+
+// writeFileAtomic writes data to a temporary file beside path and renames it
+// over path, so readers see the old file or the new one, never part of it
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
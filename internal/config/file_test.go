@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSaveFileConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data := fmt.Sprintf(`{"writer": %d, "padding": "%s"}`, i, strings.Repeat("x", 4096))
+			if err := saveFile(path, []byte(data), 0600); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Errorf("corrupt file: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if e.Name() != "config.json" && e.Name() != "config.json.lock" {
+			t.Errorf("left %s behind", e.Name())
+		}
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, %v", info.Mode(), err)
+	}
+}
+
+func TestTryLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json.lock")
+	open := func() *os.File {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	held := open()
+	if locked, err := tryLock(held); !locked || err != nil {
+		t.Fatalf("lock: %v, %v", locked, err)
+	}
+	other := open()
+	defer other.Close()
+	if locked, err := tryLock(other); locked || err != nil {
+		t.Errorf("took a held lock: %v, %v", locked, err)
+	}
+
+	held.Close()
+	if locked, err := tryLock(other); !locked || err != nil {
+		t.Errorf("lock after release: %v, %v", locked, err)
+	}
+}
@@ -7,9 +7,14 @@ import (
 	"path/filepath"
 )
 
-var globalCfg *GlobalConfig
+var (
+	globalCfg *GlobalConfig // with the overrides applied
+	fileCfg   *GlobalConfig // as saved, nil if there's no file
+)
 
-// LoadGlobal loads the global configuration from disk
+// LoadGlobal loads the global configuration from disk, with the flag and
+// environment overrides applied. Without a file, the overrides alone can
+// log in.
 func LoadGlobal() (*GlobalConfig, error) {
 	if globalCfg != nil {
 		return globalCfg, nil
@@ -20,19 +25,34 @@ func LoadGlobal() (*GlobalConfig, error) {
 		return nil, err
 	}
 
+	overrides := ActiveOverrides()
 	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if os.IsNotExist(err) {
+	switch {
+	case os.IsNotExist(err):
+		fileCfg = nil
+		inst, err := selectedInstance(overrides)
+		if err != nil {
+			return nil, err
+		}
+		if inst == nil {
 			return nil, fmt.Errorf("not logged in: run 'coolify-deployer login' first")
 		}
+	case err != nil:
 		return nil, fmt.Errorf("failed to read config: %w", err)
+	default:
+		// The overrides or the project can stand in for the URL and token
+		inst, _ := selectedInstance(overrides)
+		fileCfg = &GlobalConfig{}
+		if err := decodeFile(configPath, data, fileCfg, inst == nil); err != nil {
+			fileCfg = nil
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
 	}
 
-	cfg := &GlobalConfig{}
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+	cfg, err := applyOverrides(fileCfg, overrides)
+	if err != nil {
+		return nil, err
 	}
-
 	globalCfg = cfg
 	return cfg, nil
 }
@@ -50,18 +70,20 @@ func SaveGlobal(cfg *GlobalConfig) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Marshal config
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	// Marshal config, leaving out the overrides
+	saved := withoutOverrides(cfg, fileCfg, ActiveOverrides())
+	data, err := json.MarshalIndent(saved, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
 	// Write to file
-	if err := os.WriteFile(configPath, data, 0600); err != nil {
+	if err := saveFile(configPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
 	globalCfg = cfg
+	fileCfg = saved
 	return nil
 }
 
@@ -96,5 +118,6 @@ func ClearGlobal() error {
 	}
 
 	globalCfg = nil
+	fileCfg = nil
 	return nil
 }
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// managedKeys are saved by their own commands, not 'config set'
+var managedKeys = map[string]string{
+	"instances":           "use 'instances add' and 'instances remove'",
+	"current_context":     "use 'instances use'",
+	"config_version":      "it's updated when the file is migrated",
+	"lastUpdateCheckTime": "it's updated by the update check",
+}
+
+// configKey is a key of the CLI config resolved to its value
+type configKey struct {
+	path  string
+	value reflect.Value // the field, or the map holding a settings entry
+	field field
+	entry string // the key in the settings map, if any
+}
+
+// lookupKey resolves a dot path like azure.vm_size in cfg
+func lookupKey(cfg *Config, key string) (*configKey, error) {
+	if key == "" {
+		return nil, fmt.Errorf("key is required")
+	}
+	parts := strings.Split(key, ".")
+
+	v := reflect.ValueOf(cfg).Elem()
+	var path []string
+	for i, part := range parts {
+		if v.Kind() == reflect.Map {
+			if i != len(parts)-1 {
+				return nil, fmt.Errorf("%s values can't be nested", strings.Join(path, "."))
+			}
+			return &configKey{path: key, value: v, entry: part}, nil
+		}
+		if v.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%s has no keys", strings.Join(path, "."))
+		}
+
+		fields := structFields(v.Type())
+		f := lookupField(fields, part)
+		if f == nil {
+			msg := fmt.Sprintf("unknown key %q", strings.Join(append(path, part), "."))
+			if s := suggest(fields, part); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", strings.Join(append(path, s), "."))
+			}
+			return nil, fmt.Errorf("%s", msg)
+		}
+		path = append(path, f.name)
+		v = v.Field(f.index)
+		if i == len(parts)-1 {
+			return &configKey{path: strings.Join(path, "."), value: v, field: *f}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key %q", key)
+}
+
+// writable reports keys that are saved by their own commands
+func writable(key string) error {
+	section, _, _ := strings.Cut(key, ".")
+	if hint, ok := managedKeys[section]; ok {
+		return fmt.Errorf("%s can't be changed with config: %s", section, hint)
+	}
+	return nil
+}
+
+// GetValue returns the value of a CLI config key, or of a whole section
+func GetValue(key string) (interface{}, error) {
+	cfg := Get()
+	if cfg == nil {
+		return nil, fmt.Errorf("configuration not initialized")
+	}
+	k, err := lookupKey(cfg, key)
+	if err != nil {
+		return nil, err
+	}
+	if k.entry != "" {
+		value := k.value.MapIndex(reflect.ValueOf(k.entry))
+		if !value.IsValid() {
+			return nil, fmt.Errorf("%s isn't set", k.path)
+		}
+		return value.Interface(), nil
+	}
+	return k.value.Interface(), nil
+}
+
+// SetValue converts value to the type of a CLI config key, validates it and
+// saves it
+func SetValue(key, value string) error {
+	if err := writable(key); err != nil {
+		return err
+	}
+	cfg := Get()
+	if cfg == nil {
+		return fmt.Errorf("configuration not initialized")
+	}
+	k, err := lookupKey(cfg, key)
+	if err != nil {
+		return err
+	}
+
+	if k.entry != "" {
+		if k.value.IsNil() {
+			k.value.Set(reflect.MakeMap(k.value.Type()))
+		}
+		k.value.SetMapIndex(reflect.ValueOf(k.entry), reflect.ValueOf(parseSetting(value)))
+		return Save(cfg)
+	}
+
+	v := k.value
+	switch v.Kind() {
+	case reflect.String:
+		if err := checkRules(k.path, k.field, value); err != nil {
+			return err
+		}
+		v.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return fmt.Errorf("%s must be a whole number, not %q", k.path, value)
+		}
+		if err := checkRules(k.path, k.field, n); err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false, not %q", k.path, value)
+		}
+		v.SetBool(b)
+	case reflect.Struct, reflect.Map:
+		return fmt.Errorf("%s is a section: set one of its keys, like %s.%s", k.path, k.path, firstKey(v))
+	case reflect.Slice:
+		items := strings.Split(value, ",")
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%s can't be set with config", k.path)
+		}
+		list := reflect.MakeSlice(v.Type(), 0, len(items))
+		for _, item := range items {
+			if item = strings.TrimSpace(item); item != "" {
+				list = reflect.Append(list, reflect.ValueOf(item))
+			}
+		}
+		v.Set(list)
+	default:
+		return fmt.Errorf("%s can't be set with config", k.path)
+	}
+	return Save(cfg)
+}
+
+// UnsetValue puts a CLI config key, or a whole section, back to its default
+// and saves it. Settings entries are removed.
+func UnsetValue(key string) error {
+	if err := writable(key); err != nil {
+		return err
+	}
+	cfg := Get()
+	if cfg == nil {
+		return fmt.Errorf("configuration not initialized")
+	}
+	k, err := lookupKey(cfg, key)
+	if err != nil {
+		return err
+	}
+
+	if k.entry != "" {
+		if !k.value.IsNil() {
+			k.value.SetMapIndex(reflect.ValueOf(k.entry), reflect.Value{})
+		}
+		return Save(cfg)
+	}
+
+	def, err := lookupKey(defaultConfig(), k.path)
+	if err != nil {
+		return err
+	}
+	k.value.Set(def.value)
+	return Save(cfg)
+}
+
+// parseSetting converts a settings value like the CLI always has: whole
+// numbers and booleans are typed, anything else is a string
+func parseSetting(value string) interface{} {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	return value
+}
+
+// firstKey returns the first key of a section, for examples
+func firstKey(v reflect.Value) string {
+	if v.Kind() == reflect.Map {
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, fmt.Sprint(k.Interface()))
+		}
+		sort.Strings(keys)
+		if len(keys) > 0 {
+			return keys[0]
+		}
+		return "name"
+	}
+	fields := structFields(v.Type())
+	if len(fields) == 0 {
+		return "name"
+	}
+	return fields[0].name
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withConfig makes a default CLI config current, saved to a temporary
+// directory
+func withConfig(t *testing.T) *Config {
+	t.Helper()
+	withHome(t)
+	savedConfig, savedDir := globalConfig, configDir
+	t.Cleanup(func() { globalConfig, configDir = savedConfig, savedDir })
+	configDir = t.TempDir()
+	globalConfig = defaultConfig()
+	return globalConfig
+}
+
+func TestSetValue(t *testing.T) {
+	cfg := withConfig(t)
+
+	for key, value := range map[string]string{
+		"azure.vm_size":  "Standard_D2s_v3",
+		"gcp.project":    "my-proj",
+		"local.app_port": "9000",
+		"local.debug":    "false",
+		"provider":       "gcp",
+		"listen.events":  "deployment_success, deployment_failed",
+		"settings.theme": "dark",
+
+		"notifications.desktop":       "true",
+		"notifications.after_minutes": "10",
+		"ui.accessible":               "true",
+	} {
+		if err := SetValue(key, value); err != nil {
+			t.Errorf("set %s: %v", key, err)
+		}
+	}
+	if cfg.Azure.VMSize != "Standard_D2s_v3" || cfg.GCP.Project != "my-proj" || cfg.Local.AppPort != 9000 || cfg.Local.Debug || cfg.Provider != "gcp" {
+		t.Errorf("config = %+v", cfg)
+	}
+	if len(cfg.Listen.Events) != 2 || cfg.Listen.Events[1] != "deployment_failed" {
+		t.Errorf("events = %q", cfg.Listen.Events)
+	}
+	if !cfg.Notifications.Desktop || cfg.Notifications.After() != 10*time.Minute {
+		t.Errorf("notifications = %+v", cfg.Notifications)
+	}
+	if !cfg.UI.Accessible {
+		t.Error("ui.accessible not set")
+	}
+	if value, err := GetValue("settings.theme"); err != nil || value != "dark" {
+		t.Errorf("settings.theme = %v, %v", value, err)
+	}
+}
+
+func TestSetValueRejects(t *testing.T) {
+	withConfig(t)
+	for key, want := range map[string]string{
+		"azure.vm_sise":      `did you mean "azure.vm_size"?`,
+		"local.app_port=abc": "must be a whole number",
+		"local.app_port=0":   "must be a port",
+		"local.debug=maybe":  "must be true or false",
+		"provider=nope":      "must be one of",
+		"azure":              "is a section",
+		"instances":          "'instances add'",
+		"azure.location.x":   "has no keys",
+	} {
+		key, value, _ := strings.Cut(key, "=")
+		if value == "" {
+			value = "x"
+		}
+		err := SetValue(key, value)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("set %s %s: got %v, want %q", key, value, err, want)
+		}
+	}
+}
+
+func TestUnsetValue(t *testing.T) {
+	cfg := withConfig(t)
+	cfg.Azure.Location = "westeurope"
+	cfg.BareMetal.Host = "10.0.0.1"
+	cfg.Settings["theme"] = "dark"
+
+	for _, key := range []string{"azure.location", "baremetal.host", "settings.theme"} {
+		if err := UnsetValue(key); err != nil {
+			t.Fatalf("unset %s: %v", key, err)
+		}
+	}
+	if cfg.Azure.Location != "swedencentral" || cfg.BareMetal.Host != "" {
+		t.Errorf("azure.location = %s, baremetal.host = %s, want the defaults", cfg.Azure.Location, cfg.BareMetal.Host)
+	}
+	if _, err := GetValue("settings.theme"); err == nil {
+		t.Error("settings.theme is still set")
+	}
+}
+
+func TestReadUI(t *testing.T) {
+	home := withHome(t)
+	saved := globalConfig
+	t.Cleanup(func() { globalConfig = saved })
+	globalConfig = nil
+
+	if ReadUI().Accessible {
+		t.Error("accessible without a config file")
+	}
+	dir := filepath.Join(home, ".cool-kit")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"provider":"local","ui":{"accessible":true},"git":{"shell":true}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !ReadUI().Accessible {
+		t.Error("ui.accessible not read from the file")
+	}
+	if !ReadGit().Shell {
+		t.Error("git.shell not read from the file")
+	}
+}
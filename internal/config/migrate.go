@@ -0,0 +1,214 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ConfigVersion is the config_version of the CLI config written by this
+// release. Older files are migrated when loaded.
+const ConfigVersion = 2
+
+// migration upgrades a config file's JSON to its version from the previous
+// one. It works on the raw document so fields can be renamed or moved.
+type migration struct {
+	version int
+	summary string
+	apply   func(doc map[string]interface{}) error
+}
+
+// cliMigrations upgrade the CLI config, in order
+var cliMigrations = []migration{
+	{
+		version: 1,
+		summary: "added the Coolify instance of the login config to instances",
+		apply:   migrateLoginInstance,
+	},
+	{
+		version: 2,
+		summary: "copied the settings of ~/.coolify/azure-config.json to the azure section",
+		apply:   migrateAzureConfig,
+	},
+}
+
+// migrateFile brings the config file at path up to the last of migrations.
+// The previous file is kept beside it as path.vN.bak. It returns the
+// summaries of the migrations applied; a missing file or one that isn't a
+// JSON object is left for the loader to report.
+func migrateFile(path string, migrations []migration) ([]string, error) {
+	if len(migrations) == 0 {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil || doc == nil {
+		return nil, nil
+	}
+
+	version := 0
+	if v, ok := doc["config_version"].(float64); ok {
+		version = int(v)
+	}
+	latest := migrations[len(migrations)-1].version
+	if version > latest {
+		return nil, fmt.Errorf("%s is config version %d, newer than this release supports (%d): upgrade cool-kit", path, version, latest)
+	}
+	if version == latest {
+		return nil, nil
+	}
+
+	var applied []string
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		if err := m.apply(doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate %s to version %d: %w", path, m.version, err)
+		}
+		applied = append(applied, m.summary)
+	}
+	doc["config_version"] = latest
+
+	migrated, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := os.WriteFile(backup, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to back up config: %w", err)
+	}
+	if err := saveFile(path, migrated, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	return applied, nil
+}
+
+// migrateLoginInstance adds the URL and token saved by 'login' before
+// instances existed as the default instance, so a CLI config without any
+// keeps working with the same Coolify
+func migrateLoginInstance(doc map[string]interface{}) error {
+	if instances, _ := doc["instances"].([]interface{}); len(instances) > 0 {
+		return nil
+	}
+	login := savedLogin()
+	if login == nil || login.CoolifyURL == "" || login.CoolifyToken == "" {
+		return nil
+	}
+
+	doc["instances"] = []interface{}{
+		map[string]interface{}{
+			"name":    "default",
+			"fqdn":    login.CoolifyURL,
+			"token":   login.CoolifyToken,
+			"default": true,
+		},
+	}
+	doc["current_context"] = "default"
+	return nil
+}
+
+// legacyAzureKeys maps the infrastructure keys of azure-config.json to the
+// azure section. Its image_* keys were never read and are dropped.
+var legacyAzureKeys = map[string]string{
+	"location":            "location",
+	"vm_size":             "vm_size",
+	"admin_username":      "admin_username",
+	"ssh_public_key_path": "ssh_key_path",
+	"os_image":            "os_image",
+	"os_disk_size_gb":     "os_disk_size_gb",
+}
+
+// migrateAzureConfig copies the settings 'azure deploy' kept in its own file
+// to the azure section. Keys changed from their default in the CLI config
+// win; the old file is left in place but no longer read.
+func migrateAzureConfig(doc map[string]interface{}) error {
+	path := legacyAzureConfigPath()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var legacy map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	azure, _ := doc["azure"].(map[string]interface{})
+	if azure == nil {
+		azure = map[string]interface{}{}
+	}
+	for from, to := range legacyAzureKeys {
+		if value, ok := legacy["infrastructure"][from]; ok && isDefault(azure[to], "azure."+to) {
+			azure[to] = value
+		}
+	}
+	for _, section := range []string{"networking", "coolify", "paths", "docker"} {
+		if len(legacy[section]) == 0 {
+			continue
+		}
+		merged, _ := azure[section].(map[string]interface{})
+		if merged == nil {
+			merged = map[string]interface{}{}
+		}
+		for key, value := range legacy[section] {
+			if _, err := lookupKey(defaultConfig(), "azure."+section+"."+key); err != nil {
+				continue
+			}
+			if isDefault(merged[key], "azure."+section+"."+key) {
+				merged[key] = value
+			}
+		}
+		azure[section] = merged
+	}
+	doc["azure"] = azure
+	return nil
+}
+
+// importedConfig returns the configuration used when there's no file: the
+// defaults, with the settings of azure-config.json when 'azure deploy' was
+// run before the CLI config existed
+func importedConfig() (*Config, error) {
+	cfg := defaultConfig()
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if err := migrateAzureConfig(doc); err != nil {
+		return nil, err
+	}
+
+	if data, err = json.Marshal(doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	imported := &Config{}
+	if err := json.Unmarshal(data, imported); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return imported, nil
+}
+
+// isDefault reports a JSON value of key that's missing, empty or the
+// default
+func isDefault(value interface{}, key string) bool {
+	if value == nil || value == "" {
+		return true
+	}
+	def, err := lookupKey(defaultConfig(), key)
+	return err == nil && fmt.Sprint(value) == fmt.Sprint(def.value.Interface())
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigVersionIsLatest(t *testing.T) {
+	if last := cliMigrations[len(cliMigrations)-1].version; last != ConfigVersion {
+		t.Errorf("ConfigVersion = %d, last migration is %d", ConfigVersion, last)
+	}
+}
+
+func TestMigrateLoginInstance(t *testing.T) {
+	home := withHome(t)
+	writeFile(t, filepath.Join(home, ".coolify-deployer", "config.json"), `{"coolify_url": "https://coolify.example.com", "coolify_token": "t"}`)
+	path := filepath.Join(home, ".cool-kit", "config.json")
+	legacy := `{"provider": "local", "instances": [], "local": {"app_port": 8000}}`
+	writeFile(t, path, legacy)
+
+	applied, err := migrateFile(path, cliMigrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(cliMigrations) {
+		t.Errorf("applied %v", applied)
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil || string(backup) != legacy {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ConfigVersion != ConfigVersion || cfg.CurrentContext != "default" || cfg.Provider != "local" || cfg.Local.AppPort != 8000 {
+		t.Errorf("migrated to %s", data)
+	}
+	if len(cfg.Instances) != 1 || cfg.Instances[0].FQDN != "https://coolify.example.com" || cfg.Instances[0].Token != "t" {
+		t.Errorf("instances = %+v", cfg.Instances)
+	}
+
+	// Migrated files are left alone
+	if applied, err := migrateFile(path, cliMigrations); err != nil || applied != nil {
+		t.Errorf("migrated again: %v, %v", applied, err)
+	}
+}
+
+func TestMigrateKeepsInstances(t *testing.T) {
+	home := withHome(t)
+	writeFile(t, filepath.Join(home, ".coolify-deployer", "config.json"), `{"coolify_url": "https://login.example.com", "coolify_token": "t"}`)
+	path := filepath.Join(home, ".cool-kit", "config.json")
+	writeFile(t, path, `{"instances": [{"name": "a", "fqdn": "https://a.example.com", "token": "ta"}]}`)
+
+	if _, err := migrateFile(path, cliMigrations); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Instances) != 1 || cfg.Instances[0].Name != "a" {
+		t.Errorf("instances = %+v", cfg.Instances)
+	}
+}
+
+func TestMigrateNewerFile(t *testing.T) {
+	home := withHome(t)
+	path := filepath.Join(home, ".cool-kit", "config.json")
+	writeFile(t, path, `{"config_version": 99}`)
+
+	if _, err := migrateFile(path, cliMigrations); err == nil {
+		t.Error("migrated a file from a newer release")
+	}
+	if _, err := os.Stat(path + ".v99.bak"); !os.IsNotExist(err) {
+		t.Error("backed up a file that wasn't migrated")
+	}
+}
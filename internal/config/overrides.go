@@ -0,0 +1,310 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables that take precedence over the config files, so the
+// CLI runs in CI without a login
+const (
+	EnvInstance         = "COOLKIT_INSTANCE" // name of a configured instance
+	EnvFQDN             = "COOLKIT_FQDN"     // Coolify URL, used with EnvToken
+	EnvToken            = "COOLKIT_TOKEN"
+	EnvProvider         = "COOLKIT_PROVIDER"
+	EnvGitHubToken      = "COOLKIT_GITHUB_TOKEN"
+	EnvGitLabToken      = "COOLKIT_GITLAB_TOKEN"
+	EnvGitLabURL        = "COOLKIT_GITLAB_URL"
+	EnvGiteaToken       = "COOLKIT_GITEA_TOKEN"
+	EnvGiteaURL         = "COOLKIT_GITEA_URL"
+	EnvBitbucketUser    = "COOLKIT_BITBUCKET_USERNAME"
+	EnvBitbucketToken   = "COOLKIT_BITBUCKET_TOKEN"
+	EnvRegistryURL      = "COOLKIT_REGISTRY_URL"
+	EnvRegistryUsername = "COOLKIT_REGISTRY_USERNAME"
+	EnvRegistryPassword = "COOLKIT_REGISTRY_PASSWORD"
+
+	EnvNonInteractive = "COOLKIT_NON_INTERACTIVE" // true turns prompts off, like --non-interactive
+	EnvAdminPassword  = "COOLKIT_ADMIN_PASSWORD"  // password of the admin created by 'azure deploy'
+	EnvAccessible     = "COOLKIT_ACCESSIBLE"      // true prints plain lines for screen readers, like ui.accessible
+	EnvGitShell       = "COOLKIT_GIT_SHELL"       // true runs git operations with the git CLI, like git.shell
+)
+
+// Overrides are settings given on the command line or in the environment.
+// They apply on top of the config files and are never saved to them.
+type Overrides struct {
+	Instance         string
+	FQDN             string
+	Token            string
+	Provider         string
+	GitHubToken      string
+	GitLabToken      string
+	GitLabURL        string
+	GiteaToken       string
+	GiteaURL         string
+	BitbucketUser    string
+	BitbucketToken   string
+	RegistryURL      string
+	RegistryUsername string
+	RegistryPassword string
+}
+
+var flagOverrides Overrides
+
+// SetFlagOverrides records the global flags; they win over the environment
+func SetFlagOverrides(o Overrides) {
+	flagOverrides = o
+	globalCfg = nil
+}
+
+// ValidateOverrides reports overrides that can't be used, like a URL
+// without a token
+func ValidateOverrides() error {
+	o := ActiveOverrides()
+	if (o.FQDN == "") != (o.Token == "") {
+		return fmt.Errorf("%s and %s (--fqdn and --token) must be set together", EnvFQDN, EnvToken)
+	}
+	return nil
+}
+
+// ActiveOverrides returns the overrides in effect: each flag, or else its
+// environment variable
+func ActiveOverrides() Overrides {
+	pick := func(flag, env string) string {
+		if flag != "" {
+			return flag
+		}
+		return strings.TrimSpace(os.Getenv(env))
+	}
+	return Overrides{
+		Instance:         pick(flagOverrides.Instance, EnvInstance),
+		FQDN:             strings.TrimSuffix(pick(flagOverrides.FQDN, EnvFQDN), "/"),
+		Token:            pick(flagOverrides.Token, EnvToken),
+		Provider:         pick(flagOverrides.Provider, EnvProvider),
+		GitHubToken:      pick(flagOverrides.GitHubToken, EnvGitHubToken),
+		GitLabToken:      pick(flagOverrides.GitLabToken, EnvGitLabToken),
+		GitLabURL:        strings.TrimSuffix(pick(flagOverrides.GitLabURL, EnvGitLabURL), "/"),
+		GiteaToken:       pick(flagOverrides.GiteaToken, EnvGiteaToken),
+		GiteaURL:         strings.TrimSuffix(pick(flagOverrides.GiteaURL, EnvGiteaURL), "/"),
+		BitbucketUser:    pick(flagOverrides.BitbucketUser, EnvBitbucketUser),
+		BitbucketToken:   pick(flagOverrides.BitbucketToken, EnvBitbucketToken),
+		RegistryURL:      pick(flagOverrides.RegistryURL, EnvRegistryURL),
+		RegistryUsername: pick(flagOverrides.RegistryUsername, EnvRegistryUsername),
+		RegistryPassword: pick(flagOverrides.RegistryPassword, EnvRegistryPassword),
+	}
+}
+
+// overrideInstance returns the Coolify instance the overrides select: the
+// URL and token when both are set, or else the named instance. It's nil
+// when the overrides don't select one.
+func overrideInstance(o Overrides) (*Instance, error) {
+	switch {
+	case o.FQDN != "" && o.Token != "":
+		name := o.Instance
+		if name == "" {
+			name = "env"
+		}
+		return &Instance{Name: name, FQDN: o.FQDN, Token: o.Token}, nil
+	case o.FQDN != "" || o.Token != "":
+		return nil, ValidateOverrides()
+	case o.Instance == "":
+		return nil, nil
+	}
+
+	instances := savedInstances()
+	if cfg := Get(); cfg != nil {
+		instances = cfg.Instances
+	}
+	for _, inst := range instances {
+		if inst.Name == o.Instance {
+			return &inst, nil
+		}
+	}
+	return nil, fmt.Errorf("instance '%s' not found", o.Instance)
+}
+
+// selectedInstance returns the Coolify instance to use instead of the
+// config files' current one: the one selected by the overrides, or else the
+// one the project pins. It's nil when neither selects one.
+func selectedInstance(o Overrides) (*Instance, error) {
+	if inst, err := overrideInstance(o); inst != nil || err != nil {
+		return inst, err
+	}
+	return projectInstance()
+}
+
+// projectInstance returns the instance pinned by the current project's
+// config, if any
+func projectInstance() (*Instance, error) {
+	project, err := LoadProject()
+	if err != nil || project.Instance == "" {
+		return nil, nil
+	}
+	inst, err := FindInstance(project.Instance)
+	if err != nil {
+		return nil, fmt.Errorf("this project deploys to instance '%s', which isn't configured: add it with 'instances add' or pass --instance", project.Instance)
+	}
+	return inst, nil
+}
+
+// FindInstance returns the configured instance with the given name or URL.
+// A URL also matches the login config.
+func FindInstance(ref string) (*Instance, error) {
+	ref = strings.TrimSuffix(ref, "/")
+	isURL := strings.Contains(ref, "://")
+	instances := savedInstances()
+	if cfg := Get(); cfg != nil {
+		instances = cfg.Instances
+	}
+	for _, inst := range instances {
+		if inst.Name == ref || (isURL && strings.TrimSuffix(inst.FQDN, "/") == ref) {
+			return &inst, nil
+		}
+	}
+	if login := savedLogin(); isURL && login != nil && strings.TrimSuffix(login.CoolifyURL, "/") == ref && login.CoolifyToken != "" {
+		return &Instance{Name: ref, FQDN: ref, Token: login.CoolifyToken}, nil
+	}
+	return nil, fmt.Errorf("instance '%s' not found", ref)
+}
+
+// savedInstances reads the instances of the CLI config without initializing
+// it, which would write a default config file
+func savedInstances() []Instance {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".cool-kit", "config.json"))
+	if err != nil {
+		return nil
+	}
+	var cfg struct {
+		Instances []Instance `json:"instances"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil
+	}
+	return cfg.Instances
+}
+
+// savedLogin reads the login config as saved, without the overrides. It's
+// nil without one.
+func savedLogin() *GlobalConfig {
+	path, err := getGlobalConfigPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cfg GlobalConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil
+	}
+	return &cfg
+}
+
+// applyOverrides returns a copy of the login config with the overrides in
+// effect
+func applyOverrides(file *GlobalConfig, o Overrides) (*GlobalConfig, error) {
+	cfg := &GlobalConfig{}
+	if file != nil {
+		*cfg = *file
+		if file.DockerRegistry != nil {
+			registry := *file.DockerRegistry
+			cfg.DockerRegistry = &registry
+		}
+	}
+
+	inst, err := selectedInstance(o)
+	if err != nil {
+		return nil, err
+	}
+	if inst != nil {
+		cfg.CoolifyURL = inst.FQDN
+		cfg.CoolifyToken = inst.Token
+	}
+	if o.GitHubToken != "" {
+		cfg.GitHubToken = o.GitHubToken
+	}
+	if o.GitLabToken != "" {
+		cfg.GitLabToken = o.GitLabToken
+	}
+	if o.GitLabURL != "" {
+		cfg.GitLabURL = o.GitLabURL
+	}
+	if o.GiteaToken != "" {
+		cfg.GiteaToken = o.GiteaToken
+	}
+	if o.GiteaURL != "" {
+		cfg.GiteaURL = o.GiteaURL
+	}
+	if o.BitbucketUser != "" {
+		cfg.BitbucketUser = o.BitbucketUser
+	}
+	if o.BitbucketToken != "" {
+		cfg.BitbucketToken = o.BitbucketToken
+	}
+	if o.RegistryURL != "" || o.RegistryUsername != "" || o.RegistryPassword != "" {
+		if cfg.DockerRegistry == nil {
+			cfg.DockerRegistry = &DockerRegistry{}
+		}
+		if o.RegistryURL != "" {
+			cfg.DockerRegistry.URL = o.RegistryURL
+		}
+		if o.RegistryUsername != "" {
+			cfg.DockerRegistry.Username = o.RegistryUsername
+		}
+		if o.RegistryPassword != "" {
+			cfg.DockerRegistry.Password = o.RegistryPassword
+		}
+	}
+	return cfg, nil
+}
+
+// withoutOverrides returns a copy of cfg to save: the values that came from
+// the overrides are replaced with the ones in the file
+func withoutOverrides(cfg, file *GlobalConfig, o Overrides) *GlobalConfig {
+	if file == nil {
+		file = &GlobalConfig{}
+	}
+	applied, err := applyOverrides(file, o)
+	if err != nil {
+		return cfg
+	}
+
+	saved := *cfg
+	keep := func(value *string, overridden, original string) {
+		if *value == overridden && overridden != original {
+			*value = original
+		}
+	}
+	keep(&saved.CoolifyURL, applied.CoolifyURL, file.CoolifyURL)
+	keep(&saved.CoolifyToken, applied.CoolifyToken, file.CoolifyToken)
+	keep(&saved.GitHubToken, applied.GitHubToken, file.GitHubToken)
+	keep(&saved.GitLabToken, applied.GitLabToken, file.GitLabToken)
+	keep(&saved.GitLabURL, applied.GitLabURL, file.GitLabURL)
+	keep(&saved.GiteaToken, applied.GiteaToken, file.GiteaToken)
+	keep(&saved.GiteaURL, applied.GiteaURL, file.GiteaURL)
+	keep(&saved.BitbucketUser, applied.BitbucketUser, file.BitbucketUser)
+	keep(&saved.BitbucketToken, applied.BitbucketToken, file.BitbucketToken)
+
+	if cfg.DockerRegistry != nil && applied.DockerRegistry != nil {
+		registry := *cfg.DockerRegistry
+		original := DockerRegistry{}
+		if file.DockerRegistry != nil {
+			original = *file.DockerRegistry
+		}
+		keep(&registry.URL, applied.DockerRegistry.URL, original.URL)
+		keep(&registry.Username, applied.DockerRegistry.Username, original.Username)
+		keep(&registry.Password, applied.DockerRegistry.Password, original.Password)
+		saved.DockerRegistry = &registry
+		if registry == (DockerRegistry{}) {
+			saved.DockerRegistry = nil
+		}
+	}
+	return &saved
+}
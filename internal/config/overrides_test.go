@@ -0,0 +1,182 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withHome points the config files at an empty home directory and forgets
+// the loaded config
+func withHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, env := range []string{EnvInstance, EnvFQDN, EnvToken, EnvProvider, EnvGitHubToken, EnvGitLabToken, EnvGitLabURL, EnvGiteaToken, EnvGiteaURL, EnvBitbucketUser, EnvBitbucketToken, EnvRegistryURL, EnvRegistryUsername, EnvRegistryPassword} {
+		t.Setenv(env, "")
+	}
+	SetFlagOverrides(Overrides{})
+	t.Cleanup(func() {
+		SetFlagOverrides(Overrides{})
+		fileCfg = nil
+	})
+	return home
+}
+
+func TestLoadGlobalFromEnvironment(t *testing.T) {
+	home := withHome(t)
+	if _, err := LoadGlobal(); err == nil {
+		t.Fatal("logged in without a config file or overrides")
+	}
+
+	t.Setenv(EnvFQDN, "https://coolify.example.com/")
+	t.Setenv(EnvToken, "env-token")
+	t.Setenv(EnvRegistryURL, "ghcr.io")
+	t.Setenv(EnvGitLabToken, "gl-token")
+	t.Setenv(EnvGitLabURL, "https://gitlab.example.com/")
+	t.Setenv(EnvGiteaToken, "gitea-token")
+	t.Setenv(EnvGiteaURL, "https://git.example.com/")
+	SetFlagOverrides(Overrides{})
+
+	cfg, err := LoadGlobal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CoolifyURL != "https://coolify.example.com" || cfg.CoolifyToken != "env-token" {
+		t.Errorf("got %s with token %s", cfg.CoolifyURL, cfg.CoolifyToken)
+	}
+	if cfg.DockerRegistry == nil || cfg.DockerRegistry.URL != "ghcr.io" {
+		t.Errorf("registry = %+v", cfg.DockerRegistry)
+	}
+	if cfg.GitLabToken != "gl-token" || cfg.GitLabURL != "https://gitlab.example.com" {
+		t.Errorf("gitlab = %s with token %s", cfg.GitLabURL, cfg.GitLabToken)
+	}
+	if cfg.GiteaToken != "gitea-token" || cfg.GiteaURL != "https://git.example.com" {
+		t.Errorf("gitea = %s with token %s", cfg.GiteaURL, cfg.GiteaToken)
+	}
+	if entries, _ := os.ReadDir(home); len(entries) != 0 {
+		t.Errorf("loading wrote %d entries to $HOME", len(entries))
+	}
+}
+
+func TestFlagsWinOverEnvironment(t *testing.T) {
+	withHome(t)
+	t.Setenv(EnvFQDN, "https://env.example.com")
+	t.Setenv(EnvToken, "env-token")
+	SetFlagOverrides(Overrides{FQDN: "https://flag.example.com", Token: "flag-token"})
+
+	inst, err := GetCurrentInstance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst.FQDN != "https://flag.example.com" || inst.Token != "flag-token" {
+		t.Errorf("got %+v", inst)
+	}
+}
+
+func TestOverridesArentSaved(t *testing.T) {
+	home := withHome(t)
+	if err := SaveGlobal(&GlobalConfig{CoolifyURL: "https://file.example.com", CoolifyToken: "file-token"}); err != nil {
+		t.Fatal(err)
+	}
+
+	SetFlagOverrides(Overrides{FQDN: "https://flag.example.com", Token: "flag-token"})
+	cfg, err := LoadGlobal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CoolifyToken != "flag-token" {
+		t.Fatalf("token = %s, want the flag's", cfg.CoolifyToken)
+	}
+
+	cfg.GitHubToken = "gh-token"
+	if err := SaveGlobal(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".coolify-deployer", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := string(data)
+	if strings.Contains(saved, "flag") || !strings.Contains(saved, "file-token") || !strings.Contains(saved, "gh-token") {
+		t.Errorf("saved config = %s", saved)
+	}
+}
+
+func TestValidateOverrides(t *testing.T) {
+	withHome(t)
+	t.Setenv(EnvToken, "env-token")
+	if err := ValidateOverrides(); err == nil {
+		t.Error("token without a URL accepted")
+	}
+}
+
+func TestInstanceOverride(t *testing.T) {
+	home := withHome(t)
+	dir := filepath.Join(home, ".cool-kit")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"instances": [{"name": "a", "fqdn": "https://a.example.com", "token": "ta"}, {"name": "b", "fqdn": "https://b.example.com", "token": "tb"}], "current_context": "a"}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvInstance, "b")
+	inst, err := overrideInstance(ActiveOverrides())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst == nil || inst.Token != "tb" {
+		t.Errorf("got %+v, want instance b", inst)
+	}
+
+	t.Setenv(EnvInstance, "c")
+	if _, err := overrideInstance(ActiveOverrides()); err == nil {
+		t.Error("unknown instance accepted")
+	}
+}
+
+func TestProjectInstance(t *testing.T) {
+	home := withHome(t)
+	writeFile(t, filepath.Join(home, ".cool-kit", "config.json"), `{"instances": [{"name": "a", "fqdn": "https://a.example.com", "token": "ta"}, {"name": "b", "fqdn": "https://b.example.com/", "token": "tb"}]}`)
+	writeFile(t, filepath.Join(home, ".coolify-deployer", "config.json"), `{"coolify_url": "https://login.example.com", "coolify_token": "tl"}`)
+	project := t.TempDir()
+	t.Chdir(project)
+	pin := func(instance string) {
+		writeFile(t, filepath.Join(project, ".coolify-deployer", "config.json"), `{"name": "web", "deploy_method": "git", "instance": "`+instance+`"}`)
+		SetFlagOverrides(Overrides{})
+	}
+
+	pin("b")
+	cfg, err := LoadGlobal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CoolifyURL != "https://b.example.com/" || cfg.CoolifyToken != "tb" {
+		t.Errorf("login config = %+v, want instance b", cfg)
+	}
+
+	pin("https://a.example.com")
+	if inst, err := GetCurrentInstance(); err != nil || inst.Name != "a" {
+		t.Errorf("got %+v, %v, want instance a by URL", inst, err)
+	}
+
+	pin("https://login.example.com/")
+	if inst, err := GetCurrentInstance(); err != nil || inst.Token != "tl" {
+		t.Errorf("got %+v, %v, want the login config", inst, err)
+	}
+
+	t.Setenv(EnvInstance, "a")
+	pin("b")
+	if inst, err := GetCurrentInstance(); err != nil || inst.Name != "a" {
+		t.Errorf("got %+v, %v, want the environment to win", inst, err)
+	}
+	t.Setenv(EnvInstance, "")
+
+	pin("c")
+	if _, err := LoadGlobal(); err == nil {
+		t.Error("pinned to an unknown instance")
+	}
+}
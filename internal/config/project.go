@@ -5,17 +5,32 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 )
 
 const projectConfigFile = ".coolify-deployer/config.json"
 
-// LoadProject loads the project configuration from the current directory
+// localConfigSuffix names the local override of a project config file:
+// config.local.json beside config.json, apps/NAME.local.json beside
+// apps/NAME.json. It isn't committed.
+const localConfigSuffix = ".local.json"
+
+// LoadProject loads the project configuration from the current directory.
+// The keys of the local file override the shared one: objects are merged,
+// other values replaced. The flags and COOLKIT_* variables still win over
+// both.
 func LoadProject() (*ProjectConfig, error) {
 	configPath, err := getProjectConfigPath()
 	if err != nil {
 		return nil, err
 	}
+	return loadProjectFile(configPath)
+}
 
+// loadProjectFile loads the project config at configPath with its local
+// override
+func loadProjectFile(configPath string) (*ProjectConfig, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -25,15 +40,33 @@ func LoadProject() (*ProjectConfig, error) {
 	}
 
 	cfg := &ProjectConfig{}
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse project config: %w", err)
+	if err := decodeFile(configPath, data, cfg, true); err != nil {
+		return nil, fmt.Errorf("invalid project config: %w", err)
+	}
+
+	localPath := localConfigPath(configPath)
+	local, err := os.ReadFile(localPath)
+	switch {
+	case err == nil:
+		if err := decodeFile(localPath, local, cfg, false); err != nil {
+			return nil, fmt.Errorf("invalid local project config: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read local project config: %w", err)
 	}
 
 	return cfg, nil
 }
 
-// SaveProject saves the project configuration to the current directory
+// SaveProject saves the project configuration to the current directory.
+// The keys set in the local file are saved to it, the rest to the shared
+// file, so local values never end up committed. A config returned by
+// ForEnvironment is saved into the project's Environments.
 func SaveProject(cfg *ProjectConfig) error {
+	if cfg.environment != "" {
+		return saveEnvironment(cfg)
+	}
+
 	configPath, err := getProjectConfigPath()
 	if err != nil {
 		return err
@@ -44,28 +77,155 @@ func SaveProject(cfg *ProjectConfig) error {
 	if err := os.MkdirAll(configDir, 0750); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
+	if err := ignoreLocalConfigs(); err != nil {
+		return err
+	}
 
-	// Marshal config
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	// The lock of the shared file covers both
+	localPath := localConfigPath(configPath)
+	err = withFileLock(configPath, func() error {
+		shared, local, err := splitProject(cfg, configPath, localPath)
+		if err != nil {
+			return err
+		}
+
+		// Marshal config
+		data, err := json.MarshalIndent(shared, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+
+		// Write to file
+		if err := writeFileAtomic(configPath, data, 0600); err != nil {
+			return fmt.Errorf("failed to write config: %w", err)
+		}
+
+		if local != nil {
+			data, err := json.MarshalIndent(local, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal local config: %w", err)
+			}
+			if err := writeFileAtomic(localPath, data, 0600); err != nil {
+				return fmt.Errorf("failed to write local config: %w", err)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+		return err
+	}
+	// Losing the main menu's recent projects isn't worth failing the save over
+	_ = RecordPick(recentProjects, configPath)
+	return nil
+}
+
+// localConfigPath returns the path of a project config file's local override
+func localConfigPath(configPath string) string {
+	return strings.TrimSuffix(configPath, ".json") + localConfigSuffix
+}
+
+// splitProject returns what to save of cfg to the shared file and to the
+// local one. The keys of the local file keep their saved value in the shared
+// file; local is nil without a local file.
+func splitProject(cfg *ProjectConfig, configPath, localPath string) (*ProjectConfig, map[string]json.RawMessage, error) {
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to read local project config: %w", err)
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, nil, fmt.Errorf("invalid local project config %s: %w", localPath, err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(configPath, data, 0600); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
+	saved := &ProjectConfig{}
+	if data, err := os.ReadFile(configPath); err == nil {
+		_ = json.Unmarshal(data, saved)
+	}
+	full := map[string]json.RawMessage{}
+	data, err = json.Marshal(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := json.Unmarshal(data, &full); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal config: %w", err)
 	}
 
+	shared := *cfg
+	sharedValue := reflect.ValueOf(&shared).Elem()
+	savedValue := reflect.ValueOf(saved).Elem()
+	local := map[string]json.RawMessage{}
+	for key := range keys {
+		f := lookupField(structFields(sharedValue.Type()), key)
+		if f == nil {
+			continue
+		}
+		sharedValue.Field(f.index).Set(savedValue.Field(f.index))
+		if value, ok := full[f.name]; ok {
+			local[f.name] = value
+		}
+	}
+	return &shared, local, nil
+}
+
+// localIgnores are the patterns of the .gitignore beside the project config
+var localIgnores = []string{"*" + localConfigSuffix, "*.lock"}
+
+// ignoreLocalConfigs keeps the local project configs and the lock files out
+// of git with a .gitignore beside them. The patterns missing from one that's
+// there already, like one written before there were lock files, are added.
+func ignoreLocalConfigs() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	path := filepath.Join(cwd, filepath.Dir(projectConfigFile), ".gitignore")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	content := string(data)
+	present := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, pattern := range localIgnores {
+		if !present[pattern] {
+			missing = append(missing, pattern)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	switch {
+	case content == "":
+		content = "# Local overrides of the project config and lock files, not shared\n"
+	case !strings.HasSuffix(content, "\n"):
+		content += "\n"
+	}
+	content += strings.Join(missing, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
 	return nil
 }
 
-// getProjectConfigPath returns the path to the project config file
+// getProjectConfigPath returns the path to the project config file, or to the
+// selected monorepo application's config
 func getProjectConfigPath() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current directory: %w", err)
 	}
 
+	if selectedApp != "" {
+		return filepath.Join(cwd, appsConfigDir, selectedApp+".json"), nil
+	}
 	return filepath.Join(cwd, projectConfigFile), nil
 }
 
@@ -102,3 +262,51 @@ func HasProject() bool {
 func ProjectExists() bool {
 	return HasProject()
 }
+
+// maxImageTags is how many deployed image tags are kept for rollback
+const maxImageTags = 10
+
+// RecordImageTag appends a deployed image tag to the project's history
+func (p *ProjectConfig) RecordImageTag(tag string) {
+	tags := make([]string, 0, len(p.ImageTags)+1)
+	for _, t := range p.ImageTags {
+		if t != tag {
+			tags = append(tags, t)
+		}
+	}
+	tags = append(tags, tag)
+	if len(tags) > maxImageTags {
+		tags = tags[len(tags)-maxImageTags:]
+	}
+	p.ImageTags = tags
+}
+
+// ImageScanSettings returns how the docker method's image is scanned before
+// it's pushed, or nil when it isn't
+func (p *ProjectConfig) ImageScanSettings() *ImageScanConfig {
+	scan := p.ImageScan
+	if p.imageScan != nil {
+		scan = p.imageScan
+	}
+	if scan == nil || scan.Disabled {
+		return nil
+	}
+	return scan
+}
+
+// OverrideImageScan replaces the project's image scan settings for this
+// run, without saving them
+func (p *ProjectConfig) OverrideImageScan(scan *ImageScanConfig) {
+	p.imageScan = scan
+}
+
+// RemoteBuild reports whether the docker method builds on a build server
+func (p *ProjectConfig) RemoteBuild() bool {
+	return p.remoteBuild || p.Build != nil && p.Build.Remote
+}
+
+// ForceRemoteBuild builds on a build server for this run, without saving
+// it
+func (p *ProjectConfig) ForceRemoteBuild() {
+	p.remoteBuild = true
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalProjectConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	sharedPath := filepath.Join(dir, ".coolify-deployer", "config.json")
+	localPath := filepath.Join(dir, ".coolify-deployer", "config.local.json")
+	writeFile(t, sharedPath, `{"name": "web", "deploy_method": "git", "branch": "main", "smoke_tests": {"checks": [{"url": "/health"}]}}`)
+	writeFile(t, localPath, `{"branch": "dev", "instance": "client-a", "smoke_tests": {"rollback": true}}`)
+
+	cfg, err := LoadProject()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Branch != "dev" || cfg.Instance != "client-a" {
+		t.Errorf("branch %s, instance %s: want the local values", cfg.Branch, cfg.Instance)
+	}
+	if cfg.SmokeTests == nil || len(cfg.SmokeTests.Checks) != 1 || !cfg.SmokeTests.Rollback {
+		t.Errorf("smoke tests = %+v, want both files merged", cfg.SmokeTests)
+	}
+
+	cfg.Branch = "feature"
+	cfg.AppUUID = "app-uuid"
+	if err := SaveProject(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	shared := &ProjectConfig{}
+	data, _ := os.ReadFile(sharedPath)
+	if err := json.Unmarshal(data, shared); err != nil {
+		t.Fatal(err)
+	}
+	if shared.Branch != "main" || shared.Instance != "" || shared.AppUUID != "app-uuid" || shared.SmokeTests.Rollback {
+		t.Errorf("shared file = %s", data)
+	}
+	local := &ProjectConfig{}
+	data, _ = os.ReadFile(localPath)
+	if err := json.Unmarshal(data, local); err != nil {
+		t.Fatal(err)
+	}
+	if local.Branch != "feature" || local.Instance != "client-a" || local.AppUUID != "" {
+		t.Errorf("local file = %s", data)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".coolify-deployer", ".gitignore")); err != nil {
+		t.Errorf("local configs aren't ignored: %v", err)
+	}
+}
+
+func TestIgnoreLocalConfigs(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	// Written before lock files were ignored
+	path := filepath.Join(dir, ".coolify-deployer", ".gitignore")
+	writeFile(t, path, "# Local overrides of the project config, not shared\n*.local.json\nnotes.txt")
+
+	for range 2 {
+		if err := SaveProject(&ProjectConfig{Name: "web", DeployMethod: DeployMethodGit}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	if want := "# Local overrides of the project config, not shared\n*.local.json\nnotes.txt\n*.lock\n"; string(data) != want {
+		t.Errorf(".gitignore = %q, want %q", data, want)
+	}
+}
+
+func TestSaveProjectWithoutLocal(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := SaveProject(&ProjectConfig{Name: "web", DeployMethod: DeployMethodGit}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".coolify-deployer", "config.local.json")); !os.IsNotExist(err) {
+		t.Error("created a local config")
+	}
+	cfg, err := LoadProject()
+	if err != nil || cfg.Name != "web" {
+		t.Errorf("got %+v, %v", cfg, err)
+	}
+}
+
+func TestOverrideImageScan(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	cfg := &ProjectConfig{Name: "web", DeployMethod: DeployMethodDocker, ImageScan: &ImageScanConfig{Severity: "HIGH", Disabled: true}}
+	if scan := cfg.ImageScanSettings(); scan != nil {
+		t.Errorf("disabled scan = %+v", scan)
+	}
+
+	cfg.OverrideImageScan(&ImageScanConfig{Severity: "CRITICAL"})
+	if scan := cfg.ImageScanSettings(); scan == nil || scan.Severity != "CRITICAL" {
+		t.Errorf("overridden scan = %+v", scan)
+	}
+	if err := SaveProject(cfg); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := LoadProject()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ImageScan == nil || saved.ImageScan.Severity != "HIGH" || !saved.ImageScan.Disabled {
+		t.Errorf("saved scan = %+v, want the override left out", saved.ImageScan)
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxRecent is how many recent picks are kept of each kind
+const maxRecent = 20
+
+// recentProjects is the kind of the recent picks SaveProject records, the
+// paths of the project configs saved
+const recentProjects = "project_config"
+
+// RecentProject is a project whose config was saved recently
+type RecentProject struct {
+	Name string
+	Dir  string // the directory commands run in for it
+	App  string // the monorepo application, if it's one
+}
+
+// getRecentPath returns the path of the file of recent picks, next to the
+// global config
+func getRecentPath() (string, error) {
+	configPath, err := getGlobalConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "recent.json"), nil
+}
+
+// RecentPicks returns the keys last picked of a kind of option, like
+// servers or projects, most recent first. Without a file there are none.
+func RecentPicks(kind string) []string {
+	recent, err := loadRecent()
+	if err != nil {
+		return nil
+	}
+	return recent[kind]
+}
+
+// RecordPick moves key to the front of the recent picks of kind
+func RecordPick(kind, key string) error {
+	path, err := getRecentPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	return withFileLock(path, func() error {
+		recent, err := loadRecent()
+		if err != nil {
+			// A broken file only loses the order
+			recent = nil
+		}
+		if recent == nil {
+			recent = make(map[string][]string)
+		}
+
+		keys := []string{key}
+		for _, k := range recent[kind] {
+			if k != key && len(keys) < maxRecent {
+				keys = append(keys, k)
+			}
+		}
+		recent[kind] = keys
+
+		data, err := json.MarshalIndent(recent, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFileAtomic(path, data, 0600)
+	})
+}
+
+func loadRecent() (map[string][]string, error) {
+	path, err := getRecentPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var recent map[string][]string
+	if err := json.Unmarshal(data, &recent); err != nil {
+		return nil, fmt.Errorf("invalid recent picks: %w", err)
+	}
+	return recent, nil
+}
+
+// RecentProjects returns the projects whose config was saved recently, most
+// recent first, skipping the ones whose config is gone
+func RecentProjects() []RecentProject {
+	var projects []RecentProject
+	for _, path := range RecentPicks(recentProjects) {
+		cfg, err := loadProjectFile(path)
+		if err != nil {
+			continue
+		}
+		project := RecentProject{Name: cfg.Name, Dir: filepath.Dir(filepath.Dir(path))}
+		if filepath.Base(filepath.Dir(path)) == filepath.Base(appsConfigDir) {
+			project.Dir = filepath.Dir(project.Dir)
+			project.App = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		projects = append(projects, project)
+	}
+	return projects
+}
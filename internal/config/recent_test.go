@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRecordPick(t *testing.T) {
+	home := withHome(t)
+	if got := RecentPicks("server"); got != nil {
+		t.Fatalf("recent picks without a file = %v", got)
+	}
+
+	for _, key := range []string{"a", "b", "c", "a"} {
+		if err := RecordPick("server", key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := RecordPick("project", "p"); err != nil {
+		t.Fatal(err)
+	}
+	if got := RecentPicks("server"); !reflect.DeepEqual(got, []string{"a", "c", "b"}) {
+		t.Errorf("recent servers = %v", got)
+	}
+	if got := RecentPicks("project"); !reflect.DeepEqual(got, []string{"p"}) {
+		t.Errorf("recent projects = %v", got)
+	}
+
+	for i := 0; i < maxRecent+5; i++ {
+		if err := RecordPick("server", fmt.Sprint(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := RecentPicks("server"); len(got) != maxRecent || got[0] != fmt.Sprint(maxRecent+4) {
+		t.Errorf("recent servers after many picks = %v", got)
+	}
+
+	// A broken file starts over rather than failing the pick
+	path := filepath.Join(home, ".coolify-deployer", "recent.json")
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordPick("app", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if got := RecentPicks("app"); !reflect.DeepEqual(got, []string{"x"}) {
+		t.Errorf("recent apps after a broken file = %v", got)
+	}
+}
+
+func TestRecentProjects(t *testing.T) {
+	withHome(t)
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	t.Cleanup(func() {
+		os.Chdir(wd)
+		SelectApp("")
+	})
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SaveProject(&ProjectConfig{Name: "web", DeployMethod: "git"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SelectApp("api"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveProject(&ProjectConfig{Name: "api", DeployMethod: "docker"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, _ = os.Getwd() // resolved like SaveProject sees it
+	want := []RecentProject{{Name: "api", Dir: dir, App: "api"}, {Name: "web", Dir: dir}}
+	if got := RecentProjects(); !reflect.DeepEqual(got, want) {
+		t.Errorf("recent projects = %+v, want %+v", got, want)
+	}
+
+	if err := DeleteProject(); err != nil {
+		t.Fatal(err)
+	}
+	if got := RecentProjects(); len(got) != 1 || got[0].Name != "web" {
+		t.Errorf("recent projects after deleting one = %+v", got)
+	}
+}
@@ -2,25 +2,49 @@ package config
 
 // GlobalConfig represents user-level configuration for CDP functionality
 type GlobalConfig struct {
-	CoolifyURL     string          `json:"coolify_url"`
-	CoolifyToken   string          `json:"coolify_token"`
+	CoolifyURL     string          `json:"coolify_url" validate:"required"`
+	CoolifyToken   string          `json:"coolify_token" validate:"required"`
 	GitHubToken    string          `json:"github_token,omitempty"`
+	GitLabToken    string          `json:"gitlab_token,omitempty"`
+	GitLabURL      string          `json:"gitlab_url,omitempty"` // self-hosted GitLab; gitlab.com by default
+	GiteaToken     string          `json:"gitea_token,omitempty"`
+	GiteaURL       string          `json:"gitea_url,omitempty"` // Gitea or Forgejo, always self-hosted
+	BitbucketUser  string          `json:"bitbucket_username,omitempty"`
+	BitbucketToken string          `json:"bitbucket_token,omitempty"` // app password of BitbucketUser
 	DockerRegistry *DockerRegistry `json:"docker_registry,omitempty"`
+	MCP            *MCPConfig      `json:"mcp,omitempty"`
+}
+
+// GitLabInstance returns the URL of the GitLab the token is for
+func (g *GlobalConfig) GitLabInstance() string {
+	if g.GitLabURL == "" {
+		return DefaultGitLabURL
+	}
+	return g.GitLabURL
+}
+
+// MCPConfig limits what the MCP server lets assistants do
+type MCPConfig struct {
+	// ReadOnly hides the tools that change anything, like 'mcp --read-only'
+	ReadOnly bool `json:"read_only,omitempty"`
+	// AllowedTools are the only tools served when set
+	AllowedTools []string `json:"allowed_tools,omitempty"`
 }
 
 // DockerRegistry represents Docker registry credentials
 type DockerRegistry struct {
-	URL      string `json:"url"`
+	URL      string `json:"url" validate:"required"`
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
 // ProjectConfig represents per-project deployment configuration
 type ProjectConfig struct {
-	Name            string `json:"name"`
-	DeployMethod    string `json:"deploy_method"` // "git" or "docker"
+	Name            string `json:"name" validate:"required"`
+	DeployMethod    string `json:"deploy_method" validate:"required,oneof=git docker buildpacks"`
 	ProjectUUID     string `json:"project_uuid"`
 	ServerUUID      string `json:"server_uuid"`
+	DestinationUUID string `json:"destination_uuid,omitempty"`
 	EnvironmentUUID string `json:"environment_uuid"`
 	AppUUID         string `json:"app_uuid"`
 	Framework       string `json:"framework"`
@@ -37,11 +61,269 @@ type ProjectConfig struct {
 	GitHubRepo      string `json:"github_repo,omitempty"`
 	GitHubPrivate   bool   `json:"github_private,omitempty"`
 	GitHubAppUUID   string `json:"github_app_uuid,omitempty"`
+	// GitHubDeployKey clones the GitHub repository in GitRepo with a deploy
+	// key instead of a GitHub App
+	GitHubDeployKey bool `json:"github_deploy_key,omitempty"`
+
+	// GitProvider hosts the repository of the git method: GitProviderGitHub
+	// (the default), GitProviderGitLab, GitProviderGitea or
+	// GitProviderBitbucket
+	GitProvider string `json:"git_provider,omitempty" validate:"oneof=github gitlab gitea bitbucket"`
+	// GitRepo is the path of the repository when not on GitHub, like
+	// group/name; a name alone is created in the user's namespace
+	GitRepo    string `json:"git_repo,omitempty"`
+	GitPrivate bool   `json:"git_private,omitempty"`
+	// PrivateKeyUUID is the Coolify private key that clones GitRepo, added
+	// to it as a deploy key
+	PrivateKeyUUID string `json:"private_key_uuid,omitempty"`
+
+	// Instance pins the Coolify instance the project deploys to, by name
+	// or URL, instead of the current one. --instance and the COOLKIT_*
+	// variables still win.
+	Instance string `json:"instance,omitempty"`
+
+	// BaseDirectory is the application's directory in a monorepo
+	BaseDirectory string `json:"base_directory,omitempty"`
+	// WatchPaths are path globs whose changes trigger 'deploy --all'
+	// (default: everything under BaseDirectory)
+	WatchPaths []string `json:"watch_paths,omitempty"`
+
+	// ImageTags lists the image tags deployed by the docker method, oldest first
+	ImageTags []string `json:"image_tags,omitempty"`
+	// Build tunes the docker method's image builds
+	Build *BuildConfig `json:"build,omitempty"`
+	// Buildpacks tunes the buildpacks method's image builds
+	Buildpacks *BuildpacksConfig `json:"buildpacks,omitempty"`
+	// Compose is how a compose file deployed as a whole maps onto Coolify
+	Compose *ComposeConfig `json:"compose,omitempty"`
+	// RegistryAuth logs in to DockerImage's registry with short-lived
+	// credentials instead of the saved docker registry login
+	RegistryAuth *RegistryAuthConfig `json:"registry_auth,omitempty"`
+	// ImageScan scans the docker method's image with Trivy before it's
+	// pushed
+	ImageScan *ImageScanConfig `json:"image_scan,omitempty"`
+
+	SecretScan *SecretScanConfig `json:"secret_scan,omitempty"`
+	SmokeTests *SmokeTestConfig  `json:"smoke_tests,omitempty"`
+
+	// Strategy is how production deployments replace the running version:
+	// StrategyRecreate (the default) redeploys the application in place,
+	// StrategyBlueGreen deploys a new application and moves the domains
+	// over once it passes its smoke tests
+	Strategy  string           `json:"strategy,omitempty" validate:"oneof=recreate blue-green"`
+	BlueGreen *BlueGreenConfig `json:"blue_green,omitempty"`
+
+	// Workers are deployed from the same repository as the application
+	// with their own start command (git method only)
+	Workers []WorkerConfig `json:"workers,omitempty"`
+
+	// MigrateCommand runs in the application after every production
+	// deployment; setup fills it from the detected migrations
+	MigrateCommand string `json:"migrate_command,omitempty"`
+
+	// BranchEnvironments maps git branches to the Coolify environment
+	// 'deploy' uses for them, like "develop": "staging". Other branches
+	// deploy to production.
+	BranchEnvironments map[string]string `json:"branch_environments,omitempty"`
+	// Environments are the applications of the environments other than
+	// production, keyed by name
+	Environments map[string]*EnvironmentConfig `json:"environments,omitempty"`
+
+	// environment is set on the copies made by ForEnvironment
+	environment string
+	// imageScan is set by OverrideImageScan
+	imageScan *ImageScanConfig
+	// remoteBuild is set by ForceRemoteBuild
+	remoteBuild bool
+}
+
+// WorkerConfig is a background worker application
+type WorkerConfig struct {
+	Name         string `json:"name" validate:"required"` // appended to the application's name
+	StartCommand string `json:"start_command" validate:"required"`
+	AppUUID      string `json:"app_uuid,omitempty"` // set once the worker is created
+}
+
+// AppName returns the name of the worker's Coolify application
+func (w *WorkerConfig) AppName(project *ProjectConfig) string {
+	return project.Name + "-" + w.Name
+}
+
+// SecretScanConfig customises the secret scan run before code is pushed
+type SecretScanConfig struct {
+	Disabled      bool         `json:"disabled,omitempty"`
+	Allow         []string     `json:"allow,omitempty"`          // path patterns never reported
+	DisabledRules []string     `json:"disabled_rules,omitempty"` // built-in rule IDs to skip
+	Rules         []SecretRule `json:"rules,omitempty"`          // additional rules
+}
+
+// SecretRule is a user-defined secret pattern
+type SecretRule struct {
+	ID          string `json:"id" validate:"required"`
+	Description string `json:"description,omitempty"`
+	Pattern     string `json:"pattern" validate:"required"`
+}
+
+// SmokeTestConfig lists the checks run after every successful deployment
+type SmokeTestConfig struct {
+	Checks []SmokeCheck `json:"checks"`
+	// Rollback redeploys the previous version when a production check fails
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// SmokeCheck is an HTTP request or a local command that must succeed.
+// Exactly one of URL and Command is set.
+type SmokeCheck struct {
+	Name string `json:"name,omitempty"`
+
+	// URL is an absolute URL, or a path on the deployed application's URL
+	URL          string `json:"url,omitempty"`
+	Method       string `json:"method,omitempty"`        // default GET
+	ExpectStatus int    `json:"expect_status,omitempty"` // default 200
+	ExpectBody   string `json:"expect_body,omitempty"`   // substring of the response body
+
+	// Command runs locally with APP_URL set to the deployed application's URL
+	Command string `json:"command,omitempty"`
+
+	Timeout string `json:"timeout,omitempty"` // per attempt, default 10s
+	Retries int    `json:"retries,omitempty"` // extra attempts while the app starts, default 3, -1 for none
 }
 
+// BlueGreenConfig tunes blue/green deployments
+type BlueGreenConfig struct {
+	// StagingDomain is given to the new application while its smoke tests
+	// run (default: the domain Coolify generates, if any)
+	StagingDomain string `json:"staging_domain,omitempty"`
+	// KeepPrevious stops the replaced application instead of deleting it
+	KeepPrevious bool `json:"keep_previous,omitempty"`
+}
+
+// BuildConfig tunes the image builds of the docker method, made with
+// docker buildx when it's installed
+type BuildConfig struct {
+	// Builder is the buildx builder to build with (default: the current
+	// one, or a docker-container builder named cool-kit when the cache
+	// needs one)
+	Builder string `json:"builder,omitempty"`
+	// Cache keeps the build cache between builds: BuildCacheLocal in a
+	// directory, BuildCacheRegistry as an image in the image's registry.
+	// Without it builds only reuse the layers the docker daemon has.
+	Cache string `json:"cache,omitempty" validate:"oneof=local registry"`
+	// CacheRef is the cache's directory or image (default:
+	// ~/.cool-kit/buildcache/<name>, or the image tagged buildcache)
+	CacheRef string `json:"cache_ref,omitempty"`
+	// InlineCache embeds the cache metadata in the pushed image, and
+	// reuses the layers of the last deployed tag
+	InlineCache bool `json:"inline_cache,omitempty"`
+	// Remote builds and pushes the image on the instance's build server,
+	// over ssh, instead of with the local docker
+	Remote bool `json:"remote,omitempty"`
+	// BuildServer is the build server of remote builds, by name or UUID
+	// (default: the instance's first)
+	BuildServer string `json:"build_server,omitempty"`
+}
+
+// BuildpacksConfig tunes the images built with Cloud Native Buildpacks by
+// the pack CLI
+type BuildpacksConfig struct {
+	// Builder is the builder image (default DefaultBuildpacksBuilder), like
+	// paketobuildpacks/builder-jammy-base
+	Builder string `json:"builder,omitempty"`
+	// Buildpacks are used instead of the ones the builder detects, like
+	// heroku/nodejs
+	Buildpacks []string `json:"buildpacks,omitempty"`
+	// Env is set during the build, like BP_NODE_VERSION
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// BuilderImage returns the builder to build with, on a nil config too
+func (b *BuildpacksConfig) BuilderImage() string {
+	if b == nil || b.Builder == "" {
+		return DefaultBuildpacksBuilder
+	}
+	return b.Builder
+}
+
+// DefaultBuildpacksBuilder builds the buildpacks method's images without a
+// builder configured
+const DefaultBuildpacksBuilder = "heroku/builder:24"
+
+// ComposeConfig deploys the project's compose file as one Coolify
+// application, with the dockercompose build pack
+type ComposeConfig struct {
+	// Location is the compose file's path in the base directory, like
+	// /docker-compose.yml
+	Location string `json:"location,omitempty"`
+	// Domains are the domains of the services served on one, by service
+	// name, like "web": "https://example.com:3000"; the port is the
+	// container's
+	Domains map[string]string `json:"domains,omitempty"`
+}
+
+// RegistryAuthConfig gets short-lived registry credentials before each
+// push from a cloud's tools
+type RegistryAuthConfig struct {
+	// Helper is RegistryHelperECR (aws ecr get-login-password),
+	// RegistryHelperGHCR (the GitHub token) or RegistryHelperACR (az acr
+	// login)
+	Helper string `json:"helper" validate:"required,oneof=ecr ghcr acr"`
+	// Region is the AWS region of an ECR registry (default: from its host)
+	Region string `json:"region,omitempty"`
+	// Profile is the AWS CLI profile for ECR
+	Profile string `json:"profile,omitempty"`
+}
+
+// ImageScanConfig blocks pushing images with known vulnerabilities
+type ImageScanConfig struct {
+	// Severity is the lowest severity that blocks the push: LOW, MEDIUM,
+	// HIGH or CRITICAL (default CRITICAL)
+	Severity string `json:"severity,omitempty" validate:"oneof=LOW MEDIUM HIGH CRITICAL"`
+	// IgnoreUnfixed doesn't block on vulnerabilities without a fix yet
+	IgnoreUnfixed bool `json:"ignore_unfixed,omitempty"`
+	// Disabled keeps the settings without scanning
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Registry credential helpers
+const (
+	RegistryHelperECR  = "ecr"
+	RegistryHelperGHCR = "ghcr"
+	RegistryHelperACR  = "acr"
+)
+
+// Build caches
+const (
+	BuildCacheLocal    = "local"
+	BuildCacheRegistry = "registry"
+)
+
+// Deployment strategies
+const (
+	StrategyRecreate  = "recreate"
+	StrategyBlueGreen = "blue-green"
+)
+
 // Deployment methods
 const (
 	DeployMethodGit    = "git"
 	DeployMethodDocker = "docker"
-	DefaultPort        = "3000"
+	// DeployMethodBuildpacks builds the image with buildpacks instead of a
+	// Dockerfile, and deploys it like DeployMethodDocker
+	DeployMethodBuildpacks = "buildpacks"
+	DefaultPort            = "3000"
+)
+
+// BuildsImage reports whether a deployment method builds an image locally
+// and pushes it to the registry
+func BuildsImage(method string) bool {
+	return method == DeployMethodDocker || method == DeployMethodBuildpacks
+}
+
+// Hosts of the git method's repository
+const (
+	GitProviderGitHub    = "github"
+	GitProviderGitLab    = "gitlab"
+	GitProviderGitea     = "gitea" // Forgejo too
+	GitProviderBitbucket = "bitbucket"
+	DefaultGitLabURL     = "https://gitlab.com"
 )
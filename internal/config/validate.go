@@ -0,0 +1,438 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// The config types are checked against their json tags when loaded, and
+// against their validate tags:
+//
+//	validate:"required"           the key must be set and not empty
+//	validate:"oneof=git docker"   the value must be one of these
+//	validate:"port"               the value must be a TCP port
+//
+// Both can be given, separated by a comma.
+
+// ValidationError lists the problems found in a config file
+type ValidationError struct {
+	File     string
+	Problems []Problem
+}
+
+// Problem is something wrong at a position in a config file
+type Problem struct {
+	Line   int
+	Column int
+	Key    string // dotted path of the key, like workers[0].name
+	Msg    string
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	if len(e.Problems) > 1 {
+		lines = append(lines, fmt.Sprintf("%s has %d problems:", e.File, len(e.Problems)))
+	}
+	for _, p := range e.Problems {
+		line := fmt.Sprintf("%s:%d:%d: ", e.File, p.Line, p.Column)
+		if p.Key != "" {
+			line += p.Key + ": "
+		}
+		line += p.Msg
+		if len(e.Problems) > 1 {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// decodeFile validates the JSON of a config file against v's type, then
+// unmarshals it into v. Without checkRequired, missing required keys are
+// allowed.
+func decodeFile(file string, data []byte, v interface{}, checkRequired bool) error {
+	c := &checker{
+		data:          data,
+		dec:           json.NewDecoder(bytes.NewReader(data)),
+		checkRequired: checkRequired,
+	}
+	c.dec.UseNumber()
+
+	if _, err := c.value(reflect.TypeOf(v), ""); err != nil {
+		var syntax *json.SyntaxError
+		switch {
+		case errors.As(err, &syntax):
+			c.report(int(syntax.Offset), "", "%s", syntax.Error())
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			c.report(len(data), "", "unexpected end of file")
+		default:
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	if len(c.problems) > 0 {
+		return &ValidationError{File: file, Problems: c.problems}
+	}
+	return json.Unmarshal(data, v)
+}
+
+// checker walks the tokens of a JSON document alongside a Go type
+type checker struct {
+	data          []byte
+	dec           *json.Decoder
+	checkRequired bool
+	problems      []Problem
+}
+
+// field is a struct field as it appears in JSON
+type field struct {
+	name     string
+	index    int
+	typ      reflect.Type
+	required bool
+	oneOf    []string
+	port     bool
+}
+
+// start returns the offset where the next token starts
+func (c *checker) start() int {
+	offset := int(c.dec.InputOffset())
+	for offset < len(c.data) && strings.IndexByte(" \t\r\n,:", c.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// next returns the next token and the offset where it starts
+func (c *checker) next() (json.Token, int, error) {
+	offset := c.start()
+	tok, err := c.dec.Token()
+	return tok, offset, err
+}
+
+// position converts a byte offset to a line and column, both from 1
+func (c *checker) position(offset int) (int, int) {
+	if offset > len(c.data) {
+		offset = len(c.data)
+	}
+	before := c.data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := offset - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+func (c *checker) report(offset int, key, format string, args ...interface{}) {
+	line, col := c.position(offset)
+	c.problems = append(c.problems, Problem{Line: line, Column: col, Key: key, Msg: fmt.Sprintf(format, args...)})
+}
+
+// value checks the next JSON value against t and returns its first token
+func (c *checker) value(t reflect.Type, key string) (json.Token, error) {
+	tok, offset, err := c.next()
+	if err != nil {
+		return nil, err
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if tok == nil {
+		return nil, nil
+	}
+
+	delim, isDelim := tok.(json.Delim)
+	switch t.Kind() {
+	case reflect.Interface:
+		return tok, c.skip(tok)
+	case reflect.Struct:
+		if delim != '{' {
+			c.report(offset, key, "must be an object, not %s", describe(tok))
+			return tok, c.skip(tok)
+		}
+		return tok, c.object(t, key, offset)
+	case reflect.Map:
+		if delim != '{' {
+			c.report(offset, key, "must be an object, not %s", describe(tok))
+			return tok, c.skip(tok)
+		}
+		for c.dec.More() {
+			name, _, err := c.next()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := c.value(t.Elem(), join(key, name.(string))); err != nil {
+				return nil, err
+			}
+		}
+		_, _, err := c.next()
+		return tok, err
+	case reflect.Slice, reflect.Array:
+		if delim != '[' {
+			c.report(offset, key, "must be a list, not %s", describe(tok))
+			return tok, c.skip(tok)
+		}
+		for i := 0; c.dec.More(); i++ {
+			if _, err := c.value(t.Elem(), fmt.Sprintf("%s[%d]", key, i)); err != nil {
+				return nil, err
+			}
+		}
+		_, _, err := c.next()
+		return tok, err
+	}
+
+	if isDelim {
+		c.report(offset, key, "must be %s, not %s", kindName(t.Kind()), describe(tok))
+		return tok, c.skip(tok)
+	}
+	ok := false
+	switch t.Kind() {
+	case reflect.String:
+		_, ok = tok.(string)
+	case reflect.Bool:
+		_, ok = tok.(bool)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, isNumber := tok.(json.Number); isNumber {
+			if _, err := n.Int64(); err != nil {
+				c.report(offset, key, "must be a whole number, not %s", n)
+				return tok, nil
+			}
+			ok = true
+		}
+	case reflect.Float32, reflect.Float64:
+		_, ok = tok.(json.Number)
+	default:
+		ok = true
+	}
+	if !ok {
+		c.report(offset, key, "must be %s, not %s", kindName(t.Kind()), describe(tok))
+		return tok, nil
+	}
+	return tok, nil
+}
+
+// object checks the keys of a JSON object against struct type t. The
+// object's opening brace, at offset, has been read.
+func (c *checker) object(t reflect.Type, key string, offset int) error {
+	fields := structFields(t)
+	seen := map[string]bool{}
+	for c.dec.More() {
+		tok, keyOffset, err := c.next()
+		if err != nil {
+			return err
+		}
+		name := tok.(string)
+		f := lookupField(fields, name)
+		if f == nil {
+			msg := fmt.Sprintf("unknown key %q", name)
+			if s := suggest(fields, name); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+			c.report(keyOffset, key, "%s", msg)
+			tok, _, err := c.next()
+			if err != nil {
+				return err
+			}
+			if err := c.skip(tok); err != nil {
+				return err
+			}
+			continue
+		}
+
+		valueOffset := c.start()
+		value, err := c.value(f.typ, join(key, f.name))
+		if err != nil {
+			return err
+		}
+		if value != nil && value != "" {
+			seen[f.name] = true
+		}
+		var rule interface{} = value
+		if n, ok := value.(json.Number); ok {
+			rule, _ = n.Int64()
+		}
+		if err := checkRules("", *f, rule); err != nil {
+			c.report(valueOffset, join(key, f.name), "%s", err)
+		}
+	}
+	if _, _, err := c.next(); err != nil {
+		return err
+	}
+
+	if c.checkRequired {
+		for _, f := range fields {
+			if f.required && !seen[f.name] {
+				c.report(offset, key, "missing required key %q", f.name)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRules checks a value against the oneof and port rules of its field.
+// The message starts with key, when given.
+func checkRules(key string, f field, value interface{}) error {
+	prefix := ""
+	if key != "" {
+		prefix = key + " "
+	}
+	if s, ok := value.(string); ok && s != "" && len(f.oneOf) > 0 && !contains(f.oneOf, s) {
+		return fmt.Errorf("%smust be one of %s, not %q", prefix, strings.Join(f.oneOf, ", "), s)
+	}
+	if n, ok := value.(int64); ok && f.port && (n < 1 || n > 65535) {
+		return fmt.Errorf("%smust be a port between 1 and 65535, not %d", prefix, n)
+	}
+	return nil
+}
+
+// skip consumes the rest of a value whose first token has been read
+func (c *checker) skip(tok json.Token) error {
+	depth := 0
+	for {
+		if d, ok := tok.(json.Delim); ok {
+			if d == '{' || d == '[' {
+				depth++
+			} else {
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+		var err error
+		if tok, _, err = c.next(); err != nil {
+			return err
+		}
+	}
+}
+
+// structFields returns the fields of a struct type by JSON name
+func structFields(t reflect.Type) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if tag := sf.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n, _, _ := strings.Cut(tag, ","); n != "" {
+				name = n
+			}
+		}
+
+		f := field{name: name, index: i, typ: sf.Type}
+		for _, rule := range strings.Split(sf.Tag.Get("validate"), ",") {
+			switch {
+			case rule == "required":
+				f.required = true
+			case rule == "port":
+				f.port = true
+			case strings.HasPrefix(rule, "oneof="):
+				f.oneOf = strings.Fields(strings.TrimPrefix(rule, "oneof="))
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// lookupField finds the field for a key, ignoring case like encoding/json
+func lookupField(fields []field, name string) *field {
+	for i := range fields {
+		if fields[i].name == name {
+			return &fields[i]
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, name) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// suggest returns the field name closest to a misspelt key, if any is close
+func suggest(fields []field, name string) string {
+	best, bestDistance := "", 3
+	for _, f := range fields {
+		if d := editDistance(strings.ToLower(name), f.name); d < bestDistance {
+			best, bestDistance = f.name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// describe names the kind of a JSON token for error messages
+func describe(tok json.Token) string {
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			return "an object"
+		}
+		return "a list"
+	case string:
+		return fmt.Sprintf("the string %q", v)
+	case json.Number:
+		return "the number " + v.String()
+	case bool:
+		return fmt.Sprintf("%t", v)
+	}
+	return "null"
+}
+
+// kindName names what a Go kind is in JSON
+func kindName(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return "a whole number"
+}
+
+func join(key, name string) string {
+	if key == "" {
+		return name
+	}
+	return key + "." + name
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func problems(t *testing.T, data string, v interface{}, checkRequired bool) []Problem {
+	t.Helper()
+	err := decodeFile("config.json", []byte(data), v, checkRequired)
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("got %v, want a ValidationError", err)
+	}
+	return verr.Problems
+}
+
+func TestDecodeFileValid(t *testing.T) {
+	data := `{
+  "name": "web",
+  "deploy_method": "git",
+  "Branch": "main",
+  "workers": [{"name": "queue", "start_command": "npm run queue"}],
+  "smoke_tests": {"checks": [{"url": "/health", "expect_status": 200}]},
+  "secret_scan": null
+}`
+	cfg := &ProjectConfig{}
+	if p := problems(t, data, cfg, true); p != nil {
+		t.Fatalf("unexpected problems: %+v", p)
+	}
+	if cfg.Name != "web" || cfg.Branch != "main" || len(cfg.Workers) != 1 || cfg.SmokeTests.Checks[0].ExpectStatus != 200 {
+		t.Errorf("decoded %+v", cfg)
+	}
+}
+
+func TestDecodeFileProblems(t *testing.T) {
+	data := `{
+  "name": "web",
+  "deploy_methd": "git",
+  "port": 3000,
+  "strategy": "canary",
+  "workers": [{"name": "queue"}],
+  "smoke_tests": {"checks": [{"url": "/", "retries": 1.5}]}
+}`
+	got := problems(t, data, &ProjectConfig{}, true)
+
+	want := []Problem{
+		{Line: 3, Column: 3, Msg: `unknown key "deploy_methd" (did you mean "deploy_method"?)`},
+		{Line: 4, Column: 11, Key: "port", Msg: "must be a string, not the number 3000"},
+		{Line: 5, Column: 15, Key: "strategy", Msg: `must be one of recreate, blue-green, not "canary"`},
+		{Line: 6, Column: 15, Key: "workers[0]", Msg: `missing required key "start_command"`},
+		{Line: 7, Column: 54, Key: "smoke_tests.checks[0].retries", Msg: "must be a whole number, not 1.5"},
+		{Line: 1, Column: 1, Msg: `missing required key "deploy_method"`},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d problems, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("problem %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDecodeFileSyntaxError(t *testing.T) {
+	got := problems(t, "{\n  \"name\": \"web\",\n}", &ProjectConfig{}, true)
+	if len(got) != 1 || got[0].Line != 2 || got[0].Column != 17 {
+		t.Fatalf("got %+v, want the trailing comma at 2:17", got)
+	}
+
+	if got := problems(t, "", &ProjectConfig{}, true); len(got) != 1 {
+		t.Errorf("empty file: got %+v", got)
+	}
+}
+
+func TestDecodeFileRequired(t *testing.T) {
+	data := `{"github_token": "gh"}`
+	if got := problems(t, data, &GlobalConfig{}, true); len(got) != 2 {
+		t.Errorf("got %+v, want the URL and token missing", got)
+	}
+	if got := problems(t, data, &GlobalConfig{}, false); got != nil {
+		t.Errorf("got %+v without checking required keys", got)
+	}
+}
+
+func TestLoadGlobalReportsProblems(t *testing.T) {
+	home := withHome(t)
+	dir := filepath.Join(home, ".coolify-deployer")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	config := "{\n  \"coolify_url\": \"https://coolify.example.com\",\n  \"coolify_token\": \"t\",\n  \"docker_registry\": \"ghcr.io\"\n}"
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadGlobal()
+	if err == nil {
+		t.Fatal("loaded a config with a wrong type")
+	}
+	if msg := err.Error(); !strings.Contains(msg, "config.json:4:22: docker_registry: must be an object") {
+		t.Errorf("error = %s", msg)
+	}
+}
@@ -0,0 +1,339 @@
+package detect
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// composeFileNames are the file names Docker Compose looks for, in order
+var composeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yml", "docker-compose.yaml"}
+
+// ComposeFile is the part of a Docker Compose file that matters for mapping
+// its services onto Coolify resources
+type ComposeFile struct {
+	Path      string
+	Services  []ComposeService
+	Variables []ComposeVariable // sorted by name
+}
+
+// ComposeVariable is a variable the compose file interpolates, like ${NAME}
+type ComposeVariable struct {
+	Name     string
+	Required bool // some use has no default, like ${NAME} or ${NAME:?error}
+}
+
+// ComposeService is a service of a compose file
+type ComposeService struct {
+	Name        string
+	Image       string
+	Build       *ComposeBuild
+	Ports       []string // container ports
+	Volumes     []string
+	Environment map[string]string
+	DependsOn   []string
+}
+
+// ComposeBuild is a service's build section
+type ComposeBuild struct {
+	Context    string
+	Dockerfile string
+}
+
+// FindComposeFile returns the path of the compose file in dir, or "" if there is none
+func FindComposeFile(dir string) string {
+	for _, name := range composeFileNames {
+		if path := filepath.Join(dir, name); fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// ParseComposeFile reads a compose file. Services are sorted by name.
+func ParseComposeFile(path string) (*ComposeFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Services map[string]struct {
+			Image       string        `yaml:"image"`
+			Build       composeBuild  `yaml:"build"`
+			Ports       []composePort `yaml:"ports"`
+			Expose      []string      `yaml:"expose"`
+			Volumes     []yaml.Node   `yaml:"volumes"`
+			Environment composeEnv    `yaml:"environment"`
+			DependsOn   composeList   `yaml:"depends_on"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid compose file %s: %w", filepath.Base(path), err)
+	}
+
+	compose := &ComposeFile{Path: path}
+	for name, s := range raw.Services {
+		service := ComposeService{
+			Name:        name,
+			Image:       s.Image,
+			Build:       s.Build.build,
+			Environment: s.Environment,
+			DependsOn:   s.DependsOn,
+		}
+		for _, p := range s.Ports {
+			if p != "" {
+				service.Ports = append(service.Ports, string(p))
+			}
+		}
+		for _, e := range s.Expose {
+			service.Ports = append(service.Ports, strings.SplitN(e, "/", 2)[0])
+		}
+		for _, v := range s.Volumes {
+			switch v.Kind {
+			case yaml.ScalarNode:
+				service.Volumes = append(service.Volumes, v.Value)
+			case yaml.MappingNode:
+				var long struct {
+					Source string `yaml:"source"`
+					Target string `yaml:"target"`
+				}
+				if err := v.Decode(&long); err == nil {
+					service.Volumes = append(service.Volumes, strings.TrimPrefix(long.Source+":"+long.Target, ":"))
+				}
+			}
+		}
+		compose.Services = append(compose.Services, service)
+	}
+	sort.Slice(compose.Services, func(i, j int) bool {
+		return compose.Services[i].Name < compose.Services[j].Name
+	})
+	compose.Variables = composeVariables(string(data))
+	return compose, nil
+}
+
+// composeVariableRe matches ${NAME}, ${NAME:-default}, ${NAME?error} and
+// $NAME, with the operator after the name
+var composeVariableRe = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)(:?[-?+])?|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// composeVariables returns the variables content interpolates. A variable
+// is required unless every use has a default or only substitutes when set.
+func composeVariables(content string) []ComposeVariable {
+	required := map[string]bool{}
+	for _, m := range composeVariableRe.FindAllStringSubmatchIndex(content, -1) {
+		// $$ is a literal dollar sign
+		if m[0] > 0 && content[m[0]-1] == '$' {
+			continue
+		}
+		name, operator := "", ""
+		if m[2] >= 0 {
+			name = content[m[2]:m[3]]
+			if m[4] >= 0 {
+				operator = content[m[4]:m[5]]
+			}
+		} else {
+			name = content[m[6]:m[7]]
+		}
+		optional := strings.HasSuffix(operator, "-") || strings.HasSuffix(operator, "+")
+		required[name] = required[name] || !optional
+	}
+
+	vars := make([]ComposeVariable, 0, len(required))
+	for name, req := range required {
+		vars = append(vars, ComposeVariable{Name: name, Required: req})
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// Validate reports the mistakes that would make the stack fail to deploy:
+// no services, services with neither an image nor a build, build contexts
+// that don't exist and dependencies on undefined services
+func (c *ComposeFile) Validate() error {
+	if len(c.Services) == 0 {
+		return fmt.Errorf("%s defines no services", filepath.Base(c.Path))
+	}
+
+	defined := map[string]bool{}
+	for _, s := range c.Services {
+		defined[s.Name] = true
+	}
+	var errs []error
+	for _, s := range c.Services {
+		if s.Image == "" && s.Build == nil {
+			errs = append(errs, fmt.Errorf("service %s has neither an image nor a build", s.Name))
+		}
+		if s.Build != nil && !isRemoteContext(s.Build.Context) {
+			context := filepath.Join(filepath.Dir(c.Path), s.Build.Context)
+			if info, err := os.Stat(context); err != nil || !info.IsDir() {
+				errs = append(errs, fmt.Errorf("service %s builds from %s, which isn't a directory", s.Name, s.Build.Context))
+			}
+		}
+		for _, dep := range s.DependsOn {
+			if !defined[dep] {
+				errs = append(errs, fmt.Errorf("service %s depends on %s, which isn't defined", s.Name, dep))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isRemoteContext reports whether a build context is a git repository or
+// URL rather than a directory
+func isRemoteContext(context string) bool {
+	return strings.Contains(context, "://") || strings.HasPrefix(context, "git@")
+}
+
+// Port returns the service's first container port, or "" if it publishes none
+func (s *ComposeService) Port() string {
+	if len(s.Ports) == 0 {
+		return ""
+	}
+	return s.Ports[0]
+}
+
+// composeImageEngines maps image repositories to the engines Coolify can
+// provision in their place
+var composeImageEngines = map[string]string{
+	"postgres":                     "postgresql",
+	"postgis/postgis":              "postgresql",
+	"timescale/timescaledb":        "timescaledb",
+	"mysql":                        "mysql",
+	"mongo":                        "mongodb",
+	"clickhouse/clickhouse-server": "clickhouse",
+	"clickhouse":                   "clickhouse",
+	"bitnami/clickhouse":           "clickhouse",
+	"redis":                        "redis",
+	"valkey/valkey":                "redis",
+	"getmeili/meilisearch":         "meilisearch",
+	"elasticsearch":                "elasticsearch",
+	"docker.elastic.co/elasticsearch/elasticsearch": "elasticsearch",
+	"minio/minio":         "minio",
+	"quay.io/minio/minio": "minio",
+	"rabbitmq":            "rabbitmq",
+	"apache/kafka":        "kafka",
+	"bitnami/kafka":       "kafka",
+}
+
+// Engine returns the kind of backing service the service's image runs
+// (postgresql, timescaledb, mysql, mongodb, clickhouse, redis, meilisearch,
+// elasticsearch, minio, rabbitmq or kafka), and the image tag, or "" for
+// anything else
+func (s *ComposeService) Engine() (engine, version string) {
+	if s.Image == "" || s.Build != nil {
+		return "", ""
+	}
+	repo, tag := s.Image, ""
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo, tag = repo[:i], repo[i+1:]
+	}
+	repo = strings.TrimPrefix(repo, "docker.io/")
+	repo = strings.TrimPrefix(repo, "library/")
+	return composeImageEngines[repo], tag
+}
+
+// composeBuild accepts both "build: ./dir" and the long form
+type composeBuild struct {
+	build *ComposeBuild
+}
+
+func (b *composeBuild) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		b.build = &ComposeBuild{Context: node.Value}
+		return nil
+	}
+	var long struct {
+		Context    string `yaml:"context"`
+		Dockerfile string `yaml:"dockerfile"`
+	}
+	if err := node.Decode(&long); err != nil {
+		return err
+	}
+	if long.Context == "" {
+		long.Context = "."
+	}
+	b.build = &ComposeBuild{Context: long.Context, Dockerfile: long.Dockerfile}
+	return nil
+}
+
+// composePort is the container port of a short ("8080:80/tcp") or long
+// ({target: 80}) port entry
+type composePort string
+
+func (p *composePort) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var long struct {
+			Target int `yaml:"target"`
+		}
+		if err := node.Decode(&long); err != nil {
+			return err
+		}
+		if long.Target > 0 {
+			*p = composePort(strconv.Itoa(long.Target))
+		}
+		return nil
+	}
+	spec := strings.SplitN(node.Value, "/", 2)[0]
+	parts := strings.Split(spec, ":")
+	*p = composePort(parts[len(parts)-1])
+	return nil
+}
+
+// composeEnv accepts environment as a map or a list of KEY=value
+type composeEnv map[string]string
+
+func (e *composeEnv) UnmarshalYAML(node *yaml.Node) error {
+	env := composeEnv{}
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var list []string
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+		for _, item := range list {
+			key, value, _ := strings.Cut(item, "=")
+			env[key] = value
+		}
+	case yaml.MappingNode:
+		var m map[string]*string
+		if err := node.Decode(&m); err != nil {
+			return err
+		}
+		for key, value := range m {
+			if value != nil {
+				env[key] = *value
+			} else {
+				env[key] = ""
+			}
+		}
+	}
+	*e = env
+	return nil
+}
+
+// composeList accepts depends_on as a list or a map of service names
+type composeList []string
+
+func (l *composeList) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var list []string
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+		*l = list
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			*l = append(*l, node.Content[i].Value)
+		}
+		sort.Strings(*l)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package detect
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseComposeFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"compose.yaml": `
+services:
+  web:
+    build: .
+    ports: ["8080:3000", {target: 9229}]
+    environment:
+      - DATABASE_URL=postgres://app:pw@db:5432/app
+      - NODE_ENV=production
+    depends_on:
+      db: {condition: service_healthy}
+    volumes:
+      - uploads:/app/uploads
+  db:
+    image: postgres:16-alpine
+    environment:
+      POSTGRES_PASSWORD: pw
+    volumes:
+      - type: volume
+        source: pgdata
+        target: /var/lib/postgresql/data
+  mail:
+    image: docker.io/axllent/mailpit
+volumes:
+  uploads:
+  pgdata:
+`,
+	})
+
+	info, err := Detect(dir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.BuildPack != BuildPackDockerCompose || info.Compose == nil {
+		t.Fatalf("got %s without compose services, want a compose project", info.BuildPack)
+	}
+
+	services := info.Compose.Services
+	if len(services) != 3 || services[0].Name != "db" || services[2].Name != "web" {
+		t.Fatalf("services = %+v, want db, mail and web", services)
+	}
+	db, mail, web := services[0], services[1], services[2]
+
+	if web.Build == nil || web.Build.Context != "." {
+		t.Errorf("web build = %+v, want the repository root", web.Build)
+	}
+	if web.Port() != "3000" || len(web.Ports) != 2 || web.Ports[1] != "9229" {
+		t.Errorf("web ports = %v, want container ports 3000 and 9229", web.Ports)
+	}
+	if web.Environment["NODE_ENV"] != "production" || len(web.DependsOn) != 1 || web.DependsOn[0] != "db" {
+		t.Errorf("web env = %v, depends on %v", web.Environment, web.DependsOn)
+	}
+	if len(db.Volumes) != 1 || db.Volumes[0] != "pgdata:/var/lib/postgresql/data" {
+		t.Errorf("db volumes = %v", db.Volumes)
+	}
+
+	if engine, version := db.Engine(); engine != "postgresql" || version != "16-alpine" {
+		t.Errorf("db engine = %s %s, want postgresql 16-alpine", engine, version)
+	}
+	if engine, _ := mail.Engine(); engine != "" {
+		t.Errorf("mail engine = %s, want none", engine)
+	}
+	if engine, _ := web.Engine(); engine != "" {
+		t.Errorf("built service engine = %s, want none", engine)
+	}
+}
+
+func TestComposeVariables(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"docker-compose.yml": `
+services:
+  web:
+    image: shop:${TAG:-latest}
+    environment:
+      SECRET_KEY: ${SECRET_KEY:?set SECRET_KEY}
+      API_URL: $API_URL
+      SENTRY_DSN: ${SENTRY_DSN+}
+      PRICE: $$5
+      TAG_AGAIN: ${TAG}
+`,
+	})
+
+	compose, err := ParseComposeFile(filepath.Join(dir, "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("ParseComposeFile: %v", err)
+	}
+	want := []ComposeVariable{
+		{Name: "API_URL", Required: true},
+		{Name: "SECRET_KEY", Required: true},
+		{Name: "SENTRY_DSN"},
+		{Name: "TAG", Required: true},
+	}
+	if !reflect.DeepEqual(compose.Variables, want) {
+		t.Errorf("variables = %+v, want %+v", compose.Variables, want)
+	}
+}
+
+func TestComposeValidate(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"compose.yaml": `
+services:
+  web:
+    build: ./web
+    depends_on: [db, cache]
+  worker:
+    build: ./worker
+  db:
+    image: postgres:16
+  broken: {}
+`,
+		"web/Dockerfile": "FROM node:20\n",
+	})
+
+	compose, err := ParseComposeFile(filepath.Join(dir, "compose.yaml"))
+	if err != nil {
+		t.Fatalf("ParseComposeFile: %v", err)
+	}
+	err = compose.Validate()
+	if err == nil {
+		t.Fatal("Validate passed, want errors")
+	}
+	for _, want := range []string{
+		"service broken has neither an image nor a build",
+		"service web depends on cache, which isn't defined",
+		"service worker builds from ./worker, which isn't a directory",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate = %v, want %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "service db") || strings.Contains(err.Error(), "builds from ./web") {
+		t.Errorf("Validate = %v, reports a valid service", err)
+	}
+
+	empty := &ComposeFile{Path: filepath.Join(dir, "compose.yaml")}
+	if err := empty.Validate(); err == nil || !strings.Contains(err.Error(), "defines no services") {
+		t.Errorf("Validate of an empty file = %v", err)
+	}
+}
@@ -10,19 +10,42 @@ import (
 
 // Detect attempts to detect the framework in the given directory
 func Detect(dir string) (*FrameworkInfo, error) {
+	info, err := detectFramework(dir)
+	if err != nil {
+		return nil, err
+	}
+	applyProcfile(dir, info)
+	detectPort(dir, info)
+	return info, nil
+}
+
+func detectFramework(dir string) (*FrameworkInfo, error) {
 	// Check for Dockerfile first (highest priority)
 	if fileExists(filepath.Join(dir, "Dockerfile")) {
 		return detectDockerfile(dir)
 	}
 
 	// Check for Docker Compose
-	if fileExists(filepath.Join(dir, "docker-compose.yml")) || fileExists(filepath.Join(dir, "docker-compose.yaml")) {
-		return detectDockerCompose(dir)
+	if path := FindComposeFile(dir); path != "" {
+		return detectDockerCompose(path)
+	}
+
+	// Check for Maven/Gradle (before package.json, which JVM apps with a
+	// bundled frontend often have too)
+	if isJVMProject(dir) {
+		return detectJVMProject(dir)
+	}
+
+	// Check for .NET (.csproj or .sln)
+	if isDotNetProject(dir) {
+		info, err := detectDotNetProject(dir)
+		return withRuntime(info, err, RuntimeDotNet, "")
 	}
 
 	// Check for package.json (Node.js projects)
 	if fileExists(filepath.Join(dir, "package.json")) {
-		return detectNodeProject(dir)
+		info, err := detectNodeProject(dir)
+		return withRuntime(info, err, RuntimeNode, nodeVersion(dir))
 	}
 
 	// Check for Laravel/PHP (composer.json)
@@ -54,12 +77,14 @@ func Detect(dir string) (*FrameworkInfo, error) {
 
 	// Check for Go
 	if fileExists(filepath.Join(dir, "go.mod")) {
-		return detectGo(dir)
+		info, err := detectGo(dir)
+		return withRuntime(info, err, RuntimeGo, goVersion(dir))
 	}
 
 	// Check for Python
 	if fileExists(filepath.Join(dir, "requirements.txt")) || fileExists(filepath.Join(dir, "pyproject.toml")) || fileExists(filepath.Join(dir, "Pipfile")) {
-		return detectPythonProject(dir)
+		info, err := detectPythonProject(dir)
+		return withRuntime(info, err, RuntimePython, pythonVersion(dir))
 	}
 
 	// Check for Deno (deno.json or deno.jsonc)
@@ -102,287 +127,17 @@ func detectNodeProject(dir string) (*FrameworkInfo, error) {
 		allDeps[k] = v
 	}
 
-	// Check for Bun lockfile first (changes install command)
-	isBun := fileExists(filepath.Join(dir, "bun.lockb")) || fileExists(filepath.Join(dir, "bun.lock"))
-	installCmd := "npm install"
-	if isBun {
-		installCmd = "bun install"
-	} else if fileExists(filepath.Join(dir, "pnpm-lock.yaml")) {
-		installCmd = "pnpm install"
-	} else if fileExists(filepath.Join(dir, "yarn.lock")) {
-		installCmd = "yarn install"
-	}
-
-	// Detect T3 Stack (before Next.js since it uses Next.js)
-	if _, ok := allDeps["@trpc/server"]; ok {
-		if _, ok := allDeps["next"]; ok {
-			return &FrameworkInfo{
-				Name:           "T3 Stack",
-				BuildPack:      BuildPackNixpacks,
-				InstallCommand: installCmd,
-				BuildCommand:   "npm run build",
-				StartCommand:   "npm start",
-				Port:           "3000",
-				IsStatic:       false,
-			}, nil
-		}
-	}
-
-	// Detect Next.js
-	if _, ok := allDeps["next"]; ok {
-		return &FrameworkInfo{
-			Name:           "Next.js",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			BuildCommand:   "npm run build",
-			StartCommand:   "npm start",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
-	}
-
-	// Detect Remix
-	if _, ok := allDeps["@remix-run/node"]; ok {
-		return &FrameworkInfo{
-			Name:           "Remix",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			BuildCommand:   "npm run build",
-			StartCommand:   "npm start",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
-	}
-	if _, ok := allDeps["@remix-run/react"]; ok {
-		return &FrameworkInfo{
-			Name:           "Remix",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			BuildCommand:   "npm run build",
-			StartCommand:   "npm start",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
-	}
-
-	// Detect NestJS
-	if _, ok := allDeps["@nestjs/core"]; ok {
-		return &FrameworkInfo{
-			Name:           "NestJS",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			BuildCommand:   "npm run build",
-			StartCommand:   "node dist/main.js",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
-	}
-
-	// Detect AdonisJS
-	if _, ok := allDeps["@adonisjs/core"]; ok {
-		return &FrameworkInfo{
-			Name:           "AdonisJS",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			BuildCommand:   "node ace build --production",
-			StartCommand:   "node build/server.js",
-			Port:           "3333",
-			IsStatic:       false,
-		}, nil
-	}
-
-	// Detect Strapi
-	if _, ok := allDeps["@strapi/strapi"]; ok {
-		return &FrameworkInfo{
-			Name:           "Strapi",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			BuildCommand:   "npm run build",
-			StartCommand:   "npm start",
-			Port:           "1337",
-			IsStatic:       false,
-		}, nil
-	}
-
-	// Detect Astro
-	if _, ok := allDeps["astro"]; ok {
-		return &FrameworkInfo{
-			Name:             "Astro",
-			BuildPack:        BuildPackNixpacks,
-			InstallCommand:   installCmd,
-			BuildCommand:     "npm run build",
-			PublishDirectory: "dist",
-			Port:             "4321",
-			IsStatic:         true,
-		}, nil
-	}
-
-	// Detect Nuxt
-	if _, ok := allDeps["nuxt"]; ok {
-		return &FrameworkInfo{
-			Name:           "Nuxt",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			BuildCommand:   "npm run build",
-			StartCommand:   "node .output/server/index.mjs",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
-	}
-
-	// Detect SvelteKit
-	if _, ok := allDeps["@sveltejs/kit"]; ok {
-		return &FrameworkInfo{
-			Name:           "SvelteKit",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			BuildCommand:   "npm run build",
-			StartCommand:   "node build",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
-	}
-
-	// Detect SolidStart
-	if _, ok := allDeps["@solidjs/start"]; ok {
-		return &FrameworkInfo{
-			Name:           "SolidStart",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			BuildCommand:   "npm run build",
-			StartCommand:   "npm start",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
-	}
-
-	// Detect Solid.js (static)
-	if _, ok := allDeps["solid-js"]; ok {
-		return &FrameworkInfo{
-			Name:             "Solid.js",
-			BuildPack:        BuildPackNixpacks,
-			InstallCommand:   installCmd,
-			BuildCommand:     "npm run build",
-			PublishDirectory: "dist",
-			Port:             "3000",
-			IsStatic:         true,
-		}, nil
-	}
-
-	// Detect Qwik
-	if _, ok := allDeps["@builder.io/qwik"]; ok {
-		return &FrameworkInfo{
-			Name:           "Qwik",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			BuildCommand:   "npm run build",
-			StartCommand:   "npm run serve",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
-	}
-
-	// Detect Angular
-	if _, ok := allDeps["@angular/core"]; ok {
-		return &FrameworkInfo{
-			Name:             "Angular",
-			BuildPack:        BuildPackNixpacks,
-			InstallCommand:   installCmd,
-			BuildCommand:     "npm run build",
-			PublishDirectory: "dist",
-			Port:             "4200",
-			IsStatic:         true,
-		}, nil
-	}
-
-	// Detect Gatsby
-	if _, ok := allDeps["gatsby"]; ok {
-		return &FrameworkInfo{
-			Name:             "Gatsby",
-			BuildPack:        BuildPackNixpacks,
-			InstallCommand:   installCmd,
-			BuildCommand:     "gatsby build",
-			PublishDirectory: "public",
-			Port:             "9000",
-			IsStatic:         true,
-		}, nil
-	}
-
-	// Detect Vue.js
-	if _, ok := allDeps["vue"]; ok {
-		return &FrameworkInfo{
-			Name:             "Vue.js",
-			BuildPack:        BuildPackNixpacks,
-			InstallCommand:   installCmd,
-			BuildCommand:     "npm run build",
-			PublishDirectory: "dist",
-			Port:             "8080",
-			IsStatic:         true,
-		}, nil
-	}
-
-	// Detect Vite (generic)
-	if _, ok := allDeps["vite"]; ok {
-		return &FrameworkInfo{
-			Name:             "Vite",
-			BuildPack:        BuildPackNixpacks,
-			InstallCommand:   installCmd,
-			BuildCommand:     "npm run build",
-			PublishDirectory: "dist",
-			Port:             "5173",
-			IsStatic:         true,
-		}, nil
-	}
-
-	// Detect React (Create React App)
-	if _, ok := allDeps["react-scripts"]; ok {
-		return &FrameworkInfo{
-			Name:             "Create React App",
-			BuildPack:        BuildPackNixpacks,
-			InstallCommand:   installCmd,
-			BuildCommand:     "npm run build",
-			PublishDirectory: "build",
-			IsStatic:         true,
-		}, nil
-	}
-
-	// Detect Express.js
-	if _, ok := allDeps["express"]; ok {
-		return &FrameworkInfo{
-			Name:           "Express.js",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			StartCommand:   "node index.js",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
-	}
-
-	// Detect Fastify
-	if _, ok := allDeps["fastify"]; ok {
-		return &FrameworkInfo{
-			Name:           "Fastify",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			StartCommand:   "node index.js",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
+	rules, err := nodeRules()
+	if err != nil {
+		return nil, err
 	}
-
-	// Detect Hono
-	if _, ok := allDeps["hono"]; ok {
-		return &FrameworkInfo{
-			Name:           "Hono",
-			BuildPack:      BuildPackNixpacks,
-			InstallCommand: installCmd,
-			StartCommand:   "node index.js",
-			Port:           "3000",
-			IsStatic:       false,
-		}, nil
+	if rule := rules.match(dir, allDeps); rule != nil {
+		return rule.info(rules.installCommand(dir)), nil
 	}
 
 	// Generic Node.js / Bun
+	installCmd := rules.installCommand(dir)
+	isBun := installCmd == "bun install"
 	startCmd := ""
 	if _, ok := pkg.Scripts["start"]; ok {
 		startCmd = "npm start"
@@ -461,11 +216,14 @@ func detectDockerfile(dir string) (*FrameworkInfo, error) {
 	}, nil
 }
 
-func detectDockerCompose(dir string) (*FrameworkInfo, error) {
+func detectDockerCompose(path string) (*FrameworkInfo, error) {
+	// A file that doesn't parse is reported when setup validates the stack
+	compose, _ := ParseComposeFile(path)
 	return &FrameworkInfo{
 		Name:      "Docker Compose",
 		BuildPack: BuildPackDockerCompose,
 		IsStatic:  false,
+		Compose:   compose,
 	}, nil
 }
 
@@ -496,7 +254,7 @@ func detectPHPProject(dir string) (*FrameworkInfo, error) {
 			BuildPack:      BuildPackNixpacks,
 			InstallCommand: "composer install --no-dev --optimize-autoloader",
 			BuildCommand:   "npm install && npm run build",
-			StartCommand:   "php artisan serve --host=0.0.0.0 --port=8000",
+			StartCommand:   laravelStartCommand(dir, composer.Require),
 			Port:           "8000",
 			IsStatic:       false,
 		}, nil
@@ -628,64 +386,52 @@ func detectRust(dir string) (*FrameworkInfo, error) {
 
 // detectPythonProject detects Django, Flask, FastAPI and other Python frameworks
 func detectPythonProject(dir string) (*FrameworkInfo, error) {
-	// Check requirements.txt for framework hints
-	reqPath := filepath.Join(dir, "requirements.txt")
-	if fileExists(reqPath) {
-		data, err := os.ReadFile(reqPath)
-		if err == nil {
-			content := strings.ToLower(string(data))
-
-			// Detect Django
-			if strings.Contains(content, "django") {
-				return &FrameworkInfo{
-					Name:           "Django",
-					BuildPack:      BuildPackNixpacks,
-					InstallCommand: "pip install -r requirements.txt",
-					BuildCommand:   "python manage.py collectstatic --noinput",
-					StartCommand:   "gunicorn --bind 0.0.0.0:8000 $(basename $(pwd)).wsgi:application",
-					Port:           "8000",
-					IsStatic:       false,
-				}, nil
-			}
-
-			// Detect FastAPI
-			if strings.Contains(content, "fastapi") {
-				return &FrameworkInfo{
-					Name:           "FastAPI",
-					BuildPack:      BuildPackNixpacks,
-					InstallCommand: "pip install -r requirements.txt",
-					StartCommand:   "uvicorn main:app --host 0.0.0.0 --port 8000",
-					Port:           "8000",
-					IsStatic:       false,
-				}, nil
-			}
-
-			// Detect Flask
-			if strings.Contains(content, "flask") {
-				return &FrameworkInfo{
-					Name:           "Flask",
-					BuildPack:      BuildPackNixpacks,
-					InstallCommand: "pip install -r requirements.txt",
-					StartCommand:   "gunicorn --bind 0.0.0.0:5000 app:app",
-					Port:           "5000",
-					IsStatic:       false,
-				}, nil
-			}
-		}
+	project := readPyproject(dir)
+	tool := detectPythonTool(dir, project)
+	content := pythonDependencies(dir)
+
+	// Detect Django
+	if strings.Contains(content, "django") {
+		return &FrameworkInfo{
+			Name:           "Django",
+			BuildPack:      BuildPackNixpacks,
+			InstallCommand: tool.install,
+			BuildCommand:   tool.run + "python manage.py collectstatic --noinput",
+			StartCommand:   tool.run + "gunicorn --bind 0.0.0.0:8000 $(basename $(pwd)).wsgi:application",
+			Port:           "8000",
+			IsStatic:       false,
+		}, nil
+	}
+
+	// Detect FastAPI
+	if strings.Contains(content, "fastapi") {
+		return &FrameworkInfo{
+			Name:           "FastAPI",
+			BuildPack:      BuildPackNixpacks,
+			InstallCommand: tool.install,
+			StartCommand:   tool.run + "uvicorn main:app --host 0.0.0.0 --port 8000",
+			Port:           "8000",
+			IsStatic:       false,
+		}, nil
 	}
 
-	// Check for pyproject.toml
-	installCmd := "pip install -r requirements.txt"
-	if fileExists(filepath.Join(dir, "pyproject.toml")) {
-		installCmd = "pip install ."
-	} else if fileExists(filepath.Join(dir, "Pipfile")) {
-		installCmd = "pipenv install"
+	// Detect Flask
+	if strings.Contains(content, "flask") {
+		return &FrameworkInfo{
+			Name:           "Flask",
+			BuildPack:      BuildPackNixpacks,
+			InstallCommand: tool.install,
+			StartCommand:   tool.run + "gunicorn --bind 0.0.0.0:5000 app:app",
+			Port:           "5000",
+			IsStatic:       false,
+		}, nil
 	}
 
 	return &FrameworkInfo{
 		Name:           "Python",
 		BuildPack:      BuildPackNixpacks,
-		InstallCommand: installCmd,
+		InstallCommand: tool.install,
+		StartCommand:   scriptStartCommand(project, tool),
 		Port:           "8000",
 		IsStatic:       false,
 	}, nil
@@ -0,0 +1,144 @@
+package detect
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Devcontainer is what a project's dev container sets up
+type Devcontainer struct {
+	Image      string
+	Dockerfile string            // build.dockerfile, relative to the config
+	Runtimes   map[string]string // language runtime to version, "" if unpinned
+}
+
+// devcontainerRuntimes maps image and feature names to runtimes
+var devcontainerRuntimes = map[string]string{
+	"node": RuntimeNode, "javascript-node": RuntimeNode, "typescript-node": RuntimeNode,
+	"python": RuntimePython, "go": RuntimeGo, "golang": RuntimeGo,
+	"dotnet": RuntimeDotNet, "java": "java", "ruby": "ruby", "php": "php", "rust": "rust",
+}
+
+var (
+	jsoncComment       = regexp.MustCompile(`(?m)("(?:[^"\\]|\\.)*")|//[^\n]*|/\*[\s\S]*?\*/`)
+	jsoncTrailingComma = regexp.MustCompile(`,(\s*[}\]])`)
+	versionTagPart     = regexp.MustCompile(`^\d+(\.\d+)*$`)
+)
+
+// ReadDevcontainer reads .devcontainer/devcontainer.json or
+// .devcontainer.json, returning nil if the project has neither
+func ReadDevcontainer(dir string) *Devcontainer {
+	var data []byte
+	for _, path := range []string{".devcontainer/devcontainer.json", ".devcontainer.json"} {
+		if content, err := os.ReadFile(filepath.Join(dir, path)); err == nil {
+			data = content
+			break
+		}
+	}
+	if data == nil {
+		return nil
+	}
+
+	// devcontainer.json is JSON with comments and trailing commas
+	data = jsoncComment.ReplaceAll(data, []byte("$1"))
+	data = jsoncTrailingComma.ReplaceAll(data, []byte("$1"))
+	var raw struct {
+		Image string `json:"image"`
+		Build struct {
+			Dockerfile string `json:"dockerfile"`
+		} `json:"build"`
+		Features map[string]map[string]any `json:"features"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	dc := &Devcontainer{Image: raw.Image, Dockerfile: raw.Build.Dockerfile, Runtimes: map[string]string{}}
+	if raw.Image != "" {
+		name, tag := imageNameAndTag(raw.Image)
+		if runtime, ok := devcontainerRuntimes[name]; ok {
+			// A bare tag of a devcontainers image is the image's version
+			if strings.Contains(raw.Image, "/devcontainers/") && !strings.Contains(tag, "-") {
+				tag = ""
+			}
+			dc.Runtimes[runtime] = tagVersion(tag)
+		}
+	}
+	for id, options := range raw.Features {
+		// ghcr.io/devcontainers/features/node:1
+		name, _ := imageNameAndTag(id)
+		runtime, ok := devcontainerRuntimes[name]
+		if !ok {
+			continue
+		}
+		version, _ := options["version"].(string)
+		if version == "latest" || version == "lts" || version == "none" {
+			version = ""
+		}
+		if _, seen := dc.Runtimes[runtime]; !seen || version != "" {
+			dc.Runtimes[runtime] = version
+		}
+	}
+	return dc
+}
+
+// Hints are what a build of info should take from the dev container:
+// runtimes the build doesn't set up and versions it doesn't pin
+func (d *Devcontainer) Hints(info *FrameworkInfo) []string {
+	var hints []string
+	if d.Image != "" {
+		hints = append(hints, "Image "+d.Image)
+	}
+
+	runtimes := make([]string, 0, len(d.Runtimes))
+	for runtime := range d.Runtimes {
+		runtimes = append(runtimes, runtime)
+	}
+	sort.Strings(runtimes)
+	for _, runtime := range runtimes {
+		version := d.Runtimes[runtime]
+		switch {
+		case info.Name == "Unknown":
+			hints = append(hints, fmt.Sprintf("Sets up %s, which no build file points to", strings.TrimSpace(runtime+" "+version)))
+		case runtime != info.Runtime:
+			// Only projects with a known runtime can say what else they need
+			if info.Runtime != "" && info.BuildPack == BuildPackNixpacks {
+				hints = append(hints, fmt.Sprintf("Sets up %s, which the %s build won't install; a Dockerfile can", strings.TrimSpace(runtime+" "+version), info.Name))
+			}
+		case version != "" && info.RuntimeVersion == "":
+			hints = append(hints, fmt.Sprintf("Uses %s %s; pin it in the project to build with the same version", runtime, version))
+		case version != "" && !strings.HasPrefix(version, info.RuntimeVersion) && !strings.HasPrefix(info.RuntimeVersion, version):
+			hints = append(hints, fmt.Sprintf("Uses %s %s, but the build pins %s", runtime, version, info.RuntimeVersion))
+		}
+	}
+	if d.Dockerfile != "" && info.BuildPack != BuildPackDockerfile {
+		hints = append(hints, "Builds from "+d.Dockerfile+", which is for development; add a Dockerfile at the root to deploy with one")
+	}
+	return hints
+}
+
+// imageNameAndTag splits mcr.microsoft.com/devcontainers/python:1-3.12 into
+// python and 1-3.12
+func imageNameAndTag(image string) (name, tag string) {
+	slash := strings.LastIndex(image, "/")
+	name, tag, _ = strings.Cut(image[slash+1:], ":")
+	name, _, _ = strings.Cut(name, "@")
+	return name, tag
+}
+
+// tagVersion returns the last version in an image tag: 20 for 20-bookworm,
+// 3.12 for 1-3.12-bookworm
+func tagVersion(tag string) string {
+	version := ""
+	for _, part := range strings.Split(tag, "-") {
+		if versionTagPart.MatchString(part) {
+			version = part
+		}
+	}
+	return version
+}
@@ -0,0 +1,52 @@
+package detect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadDevcontainer(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".devcontainer/devcontainer.json": `{
+	// Python with Node for the frontend
+	"name": "shop",
+	"image": "mcr.microsoft.com/devcontainers/python:1-3.12-bookworm",
+	"features": {
+		"ghcr.io/devcontainers/features/node:1": {"version": "20"},
+		"ghcr.io/devcontainers/features/github-cli:1": {},
+	},
+}`,
+	})
+
+	dc := ReadDevcontainer(dir)
+	if dc == nil {
+		t.Fatal("ReadDevcontainer() = nil")
+	}
+	if want := map[string]string{RuntimePython: "3.12", RuntimeNode: "20"}; !reflect.DeepEqual(dc.Runtimes, want) {
+		t.Errorf("Runtimes = %v, want %v", dc.Runtimes, want)
+	}
+
+	info := &FrameworkInfo{Name: "Flask", BuildPack: BuildPackNixpacks, Runtime: RuntimePython, RuntimeVersion: "3.11"}
+	want := []string{
+		"Image mcr.microsoft.com/devcontainers/python:1-3.12-bookworm",
+		"Sets up node 20, which the Flask build won't install; a Dockerfile can",
+		"Uses python 3.12, but the build pins 3.11",
+	}
+	if got := dc.Hints(info); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hints() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestTagVersion(t *testing.T) {
+	for tag, want := range map[string]string{
+		"20-bookworm":     "20",
+		"1-3.12-bookworm": "3.12",
+		"1.22":            "1.22",
+		"lts-alpine":      "",
+		"":                "",
+	} {
+		if got := tagVersion(tag); got != want {
+			t.Errorf("tagVersion(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package detect
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// csproj is the part of an MSBuild project file detection needs
+type csproj struct {
+	Sdk            string `xml:"Sdk,attr"`
+	PropertyGroups []struct {
+		TargetFramework  string `xml:"TargetFramework"`
+		TargetFrameworks string `xml:"TargetFrameworks"`
+		AssemblyName     string `xml:"AssemblyName"`
+	} `xml:"PropertyGroup"`
+}
+
+// slnProject matches the project entries of a solution file
+var slnProject = regexp.MustCompile(`(?m)^Project\("[^"]*"\)\s*=\s*"[^"]*",\s*"([^"]+\.csproj)"`)
+
+// dotnetStartAny runs the published entry assembly, the only one with a
+// runtimeconfig.json, when its name isn't known
+const dotnetStartAny = "dotnet out/$(basename out/*.runtimeconfig.json .runtimeconfig.json).dll"
+
+// targetFrameworkVersion matches net8.0 and netcoreapp3.1 style monikers
+var targetFrameworkVersion = regexp.MustCompile(`^net(?:coreapp)?(\d+\.\d+)`)
+
+func isDotNetProject(dir string) bool {
+	return len(globFiles(dir, "*.csproj")) > 0 || len(globFiles(dir, "*.sln")) > 0
+}
+
+// detectDotNetProject detects ASP.NET Core and other .NET projects. In a
+// solution the web project is preferred.
+func detectDotNetProject(dir string) (*FrameworkInfo, error) {
+	candidates := globFiles(dir, "*.csproj")
+	if len(candidates) == 0 {
+		for _, sln := range globFiles(dir, "*.sln") {
+			data, err := os.ReadFile(filepath.Join(dir, sln))
+			if err != nil {
+				continue
+			}
+			for _, m := range slnProject.FindAllStringSubmatch(string(data), -1) {
+				candidates = append(candidates, filepath.ToSlash(strings.ReplaceAll(m[1], `\`, "/")))
+			}
+		}
+	}
+
+	// Rank web projects first and test projects last
+	var path string
+	var project csproj
+	best := -1
+	for _, candidate := range candidates {
+		data, err := os.ReadFile(filepath.Join(dir, candidate))
+		if err != nil {
+			continue
+		}
+		var p csproj
+		if err := xml.Unmarshal(data, &p); err != nil {
+			continue
+		}
+		rank := 1
+		if strings.Contains(strings.ToLower(candidate), "test") {
+			rank = 0
+		} else if p.Sdk == "Microsoft.NET.Sdk.Web" {
+			rank = 2
+		}
+		if rank > best {
+			path, project, best = candidate, p, rank
+		}
+	}
+
+	if path == "" {
+		return &FrameworkInfo{
+			Name:         ".NET",
+			BuildPack:    BuildPackNixpacks,
+			BuildCommand: "dotnet publish -c Release -o out",
+			StartCommand: dotnetStartAny,
+			Port:         "8080",
+			IsStatic:     false,
+		}, nil
+	}
+
+	assembly := strings.TrimSuffix(filepath.Base(path), ".csproj")
+	var targetFramework string
+	for _, g := range project.PropertyGroups {
+		if g.AssemblyName != "" {
+			assembly = g.AssemblyName
+		}
+		if g.TargetFramework != "" {
+			targetFramework = g.TargetFramework
+		} else if g.TargetFrameworks != "" && targetFramework == "" {
+			targetFramework = strings.Split(g.TargetFrameworks, ";")[0]
+		}
+	}
+
+	info := &FrameworkInfo{
+		Name:         ".NET",
+		BuildPack:    BuildPackNixpacks,
+		BuildCommand: "dotnet publish " + path + " -c Release -o out",
+		StartCommand: "dotnet out/" + assembly + ".dll",
+		IsStatic:     false,
+	}
+	if m := targetFrameworkVersion.FindStringSubmatch(targetFramework); m != nil {
+		info.RuntimeVersion = m[1]
+	}
+
+	if project.Sdk == "Microsoft.NET.Sdk.Web" {
+		info.Name = "ASP.NET Core"
+		// .NET 8 images moved the default port from 80 to 8080; Kestrel
+		// itself listens on 5000, so set it explicitly either way
+		info.Port = "8080"
+		if major, err := strconv.Atoi(strings.Split(info.RuntimeVersion, ".")[0]); err == nil && major < 8 {
+			info.Port = "5000"
+		}
+		info.StartCommand += " --urls http://0.0.0.0:" + info.Port
+	}
+	return info, nil
+}
+
+// globFiles returns the names of the files in dir matching pattern
+func globFiles(dir, pattern string) []string {
+	matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if fileExists(m) {
+			names = append(names, filepath.Base(m))
+		}
+	}
+	return names
+}
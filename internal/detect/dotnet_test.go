@@ -0,0 +1,70 @@
+package detect
+
+import "testing"
+
+func TestDetectDotNet(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    FrameworkInfo
+		version string
+	}{
+		{
+			name: "web project",
+			files: map[string]string{
+				"Shop.csproj": `<Project Sdk="Microsoft.NET.Sdk.Web"><PropertyGroup><TargetFramework>net8.0</TargetFramework></PropertyGroup></Project>`,
+			},
+			want: FrameworkInfo{
+				Name:         "ASP.NET Core",
+				BuildCommand: "dotnet publish Shop.csproj -c Release -o out",
+				StartCommand: "dotnet out/Shop.dll --urls http://0.0.0.0:8080",
+				Port:         "8080",
+			},
+			version: "8.0",
+		},
+		{
+			name: "solution with tests",
+			files: map[string]string{
+				"App.sln": "Project(\"{FAE04EC0}\") = \"Api\", \"src\\Api\\Api.csproj\", \"{1}\"\n" +
+					"Project(\"{FAE04EC0}\") = \"Api.Tests\", \"tests\\Api.Tests\\Api.Tests.csproj\", \"{2}\"\n",
+				"src/Api/Api.csproj":               `<Project Sdk="Microsoft.NET.Sdk.Web"><PropertyGroup><TargetFrameworks>netcoreapp3.1;net6.0</TargetFrameworks><AssemblyName>ShopApi</AssemblyName></PropertyGroup></Project>`,
+				"tests/Api.Tests/Api.Tests.csproj": `<Project Sdk="Microsoft.NET.Sdk.Web"><PropertyGroup><TargetFramework>net8.0</TargetFramework></PropertyGroup></Project>`,
+			},
+			want: FrameworkInfo{
+				Name:         "ASP.NET Core",
+				BuildCommand: "dotnet publish src/Api/Api.csproj -c Release -o out",
+				StartCommand: "dotnet out/ShopApi.dll --urls http://0.0.0.0:5000",
+				Port:         "5000",
+			},
+			version: "3.1",
+		},
+		{
+			name: "worker",
+			files: map[string]string{
+				"Worker.csproj": `<Project Sdk="Microsoft.NET.Sdk.Worker"><PropertyGroup><TargetFramework>net9.0</TargetFramework></PropertyGroup></Project>`,
+			},
+			want: FrameworkInfo{
+				Name:         ".NET",
+				BuildCommand: "dotnet publish Worker.csproj -c Release -o out",
+				StartCommand: "dotnet out/Worker.dll",
+			},
+			version: "9.0",
+		},
+	}
+
+	for _, tt := range tests {
+		info, err := Detect(writeFiles(t, tt.files))
+		if err != nil {
+			t.Fatalf("%s: Detect: %v", tt.name, err)
+		}
+		if info.Name != tt.want.Name || info.BuildCommand != tt.want.BuildCommand ||
+			info.StartCommand != tt.want.StartCommand || info.Port != tt.want.Port {
+			t.Errorf("%s: got %s / %q / %q / %s, want %s / %q / %q / %s", tt.name,
+				info.Name, info.BuildCommand, info.StartCommand, info.Port,
+				tt.want.Name, tt.want.BuildCommand, tt.want.StartCommand, tt.want.Port)
+		}
+		if info.RuntimeVersion != tt.version {
+			t.Errorf("%s: runtime version = %q, want %q", tt.name, info.RuntimeVersion, tt.version)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package detect
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// jvmStartFlags size the heap from the container's memory limit instead of
+// the host's memory
+const jvmStartFlags = "-XX:MaxRAMPercentage=75"
+
+// jvmFramework identifies a JVM framework by a Maven group ID or Gradle plugin prefix
+type jvmFramework struct {
+	name   string
+	marker string
+}
+
+// jvmFrameworks are checked in order; the first whose marker appears wins
+var jvmFrameworks = []jvmFramework{
+	{"Spring Boot", "org.springframework.boot"},
+	{"Quarkus", "io.quarkus"},
+	{"Micronaut", "io.micronaut"},
+}
+
+// pom is the part of a Maven pom.xml detection needs
+type pom struct {
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	Dependencies []struct {
+		GroupID string `xml:"groupId"`
+	} `xml:"dependencies>dependency"`
+	DependencyManagement []struct {
+		GroupID string `xml:"groupId"`
+	} `xml:"dependencyManagement>dependencies>dependency"`
+	Build struct {
+		FinalName string `xml:"finalName"`
+		Plugins   []struct {
+			GroupID string `xml:"groupId"`
+		} `xml:"plugins>plugin"`
+	} `xml:"build"`
+}
+
+// groupIDs returns every group ID the pom refers to
+func (p *pom) groupIDs() []string {
+	ids := []string{p.Parent.GroupID}
+	for _, d := range p.Dependencies {
+		ids = append(ids, d.GroupID)
+	}
+	for _, d := range p.DependencyManagement {
+		ids = append(ids, d.GroupID)
+	}
+	for _, plugin := range p.Build.Plugins {
+		ids = append(ids, plugin.GroupID)
+	}
+	return ids
+}
+
+// jarName returns the name Maven gives the packaged jar, or "" if it
+// depends on properties
+func (p *pom) jarName() string {
+	if p.Build.FinalName != "" {
+		if strings.Contains(p.Build.FinalName, "${") {
+			return ""
+		}
+		return p.Build.FinalName + ".jar"
+	}
+	version := p.Version
+	if version == "" {
+		version = p.Parent.Version
+	}
+	if p.ArtifactID == "" || version == "" || strings.Contains(p.ArtifactID+version, "${") {
+		return ""
+	}
+	return p.ArtifactID + "-" + version + ".jar"
+}
+
+func isJVMProject(dir string) bool {
+	return fileExists(filepath.Join(dir, "pom.xml")) ||
+		fileExists(filepath.Join(dir, "build.gradle")) ||
+		fileExists(filepath.Join(dir, "build.gradle.kts"))
+}
+
+// detectJVMProject detects Maven and Gradle projects and the Spring Boot,
+// Quarkus and Micronaut frameworks
+func detectJVMProject(dir string) (*FrameworkInfo, error) {
+	if fileExists(filepath.Join(dir, "pom.xml")) {
+		return detectMaven(dir)
+	}
+	return detectGradle(dir)
+}
+
+func detectMaven(dir string) (*FrameworkInfo, error) {
+	mvn := "mvn"
+	if fileExists(filepath.Join(dir, "mvnw")) {
+		mvn = "./mvnw"
+	}
+
+	var project pom
+	if data, err := os.ReadFile(filepath.Join(dir, "pom.xml")); err == nil {
+		// A pom that doesn't parse still builds with the generic settings
+		_ = xml.Unmarshal(data, &project)
+	}
+	name := jvmFrameworkName(project.groupIDs())
+
+	// Shaded builds leave an original-*.jar next to the real one, so
+	// prefer the exact name over a glob
+	jar := "target/*.jar"
+	if n := project.jarName(); n != "" {
+		jar = "target/" + n
+	}
+
+	info := &FrameworkInfo{
+		Name:         name,
+		BuildPack:    BuildPackNixpacks,
+		BuildCommand: mvn + " -DskipTests package",
+		StartCommand: "java " + jvmStartFlags + " -jar " + jar,
+		Port:         "8080",
+		IsStatic:     false,
+	}
+	if name == "Quarkus" {
+		info.StartCommand = "java " + jvmStartFlags + " -jar target/quarkus-app/quarkus-run.jar"
+	}
+	return info, nil
+}
+
+func detectGradle(dir string) (*FrameworkInfo, error) {
+	gradle := "gradle"
+	if fileExists(filepath.Join(dir, "gradlew")) {
+		gradle = "./gradlew"
+	}
+
+	var content string
+	for _, file := range []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"} {
+		if data, err := os.ReadFile(filepath.Join(dir, file)); err == nil {
+			content += string(data) + "\n"
+		}
+	}
+	name := jvmFrameworkName([]string{content})
+
+	info := &FrameworkInfo{
+		Name:      name,
+		BuildPack: BuildPackNixpacks,
+		Port:      "8080",
+		IsStatic:  false,
+	}
+	switch name {
+	case "Spring Boot":
+		// bootJar skips the plain jar, which would also match the glob
+		info.BuildCommand = gradle + " bootJar -x test"
+		info.StartCommand = "java " + jvmStartFlags + " -jar build/libs/*.jar"
+	case "Quarkus":
+		info.BuildCommand = gradle + " build -x test"
+		info.StartCommand = "java " + jvmStartFlags + " -jar build/quarkus-app/quarkus-run.jar"
+	case "Micronaut":
+		info.BuildCommand = gradle + " shadowJar -x test"
+		info.StartCommand = "java " + jvmStartFlags + " -jar build/libs/*-all.jar"
+	default:
+		info.BuildCommand = gradle + " build -x test"
+		info.StartCommand = "java " + jvmStartFlags + " -jar build/libs/*.jar"
+	}
+	return info, nil
+}
+
+// jvmFrameworkName returns the framework whose marker appears in any of the
+// given group IDs or build script contents
+func jvmFrameworkName(sources []string) string {
+	for _, fw := range jvmFrameworks {
+		for _, s := range sources {
+			if strings.Contains(s, fw.marker) {
+				return fw.name
+			}
+		}
+	}
+	return "Java"
+}
@@ -0,0 +1,104 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDetectJVM(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  FrameworkInfo
+	}{
+		{
+			name: "spring boot maven",
+			files: map[string]string{
+				"mvnw": "",
+				"pom.xml": `<project>
+  <parent><groupId>org.springframework.boot</groupId><artifactId>spring-boot-starter-parent</artifactId><version>3.3.0</version></parent>
+  <artifactId>shop</artifactId>
+  <version>1.0.0</version>
+</project>`,
+				"package.json": `{"dependencies": {"react": "18"}}`,
+			},
+			want: FrameworkInfo{
+				Name:         "Spring Boot",
+				BuildCommand: "./mvnw -DskipTests package",
+				StartCommand: "java -XX:MaxRAMPercentage=75 -jar target/shop-1.0.0.jar",
+			},
+		},
+		{
+			name: "quarkus maven",
+			files: map[string]string{
+				"pom.xml": `<project>
+  <artifactId>api</artifactId><version>${revision}</version>
+  <dependencyManagement><dependencies><dependency><groupId>io.quarkus.platform</groupId></dependency></dependencies></dependencyManagement>
+</project>`,
+			},
+			want: FrameworkInfo{
+				Name:         "Quarkus",
+				BuildCommand: "mvn -DskipTests package",
+				StartCommand: "java -XX:MaxRAMPercentage=75 -jar target/quarkus-app/quarkus-run.jar",
+			},
+		},
+		{
+			name:  "spring boot gradle",
+			files: map[string]string{"gradlew": "", "build.gradle.kts": `plugins { id("org.springframework.boot") version "3.3.0" }`},
+			want: FrameworkInfo{
+				Name:         "Spring Boot",
+				BuildCommand: "./gradlew bootJar -x test",
+				StartCommand: "java -XX:MaxRAMPercentage=75 -jar build/libs/*.jar",
+			},
+		},
+		{
+			name:  "micronaut gradle",
+			files: map[string]string{"build.gradle": `plugins { id 'io.micronaut.application' version '4.4.0' }`},
+			want: FrameworkInfo{
+				Name:         "Micronaut",
+				BuildCommand: "gradle shadowJar -x test",
+				StartCommand: "java -XX:MaxRAMPercentage=75 -jar build/libs/*-all.jar",
+			},
+		},
+		{
+			name:  "plain maven",
+			files: map[string]string{"pom.xml": `<project><artifactId>tool</artifactId><version>2.1</version><build><finalName>app</finalName></build></project>`},
+			want: FrameworkInfo{
+				Name:         "Java",
+				BuildCommand: "mvn -DskipTests package",
+				StartCommand: "java -XX:MaxRAMPercentage=75 -jar target/app.jar",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		info, err := Detect(writeFiles(t, tt.files))
+		if err != nil {
+			t.Fatalf("%s: Detect: %v", tt.name, err)
+		}
+		if info.Name != tt.want.Name || info.BuildCommand != tt.want.BuildCommand || info.StartCommand != tt.want.StartCommand {
+			t.Errorf("%s: got %s / %q / %q, want %s / %q / %q", tt.name,
+				info.Name, info.BuildCommand, info.StartCommand,
+				tt.want.Name, tt.want.BuildCommand, tt.want.StartCommand)
+		}
+		if info.Port != "8080" {
+			t.Errorf("%s: port = %s, want 8080", tt.name, info.Port)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var (
+	octaneServerEnv     = regexp.MustCompile(`(?m)^OCTANE_SERVER=["']?(\w+)`)
+	octaneServerDefault = regexp.MustCompile(`env\(\s*'OCTANE_SERVER'\s*,\s*'(\w+)'`)
+)
+
+// laravelStartCommand serves the application with Octane if it's installed,
+// which keeps the framework booted between requests, or with artisan serve
+func laravelStartCommand(dir string, require map[string]string) string {
+	if _, ok := require["laravel/octane"]; !ok {
+		return "php artisan serve --host=0.0.0.0 --port=8000"
+	}
+	return "php artisan octane:start --server=" + octaneServer(dir, require) + " --host=0.0.0.0 --port=8000"
+}
+
+// octaneServer returns the server Octane is configured for: OCTANE_SERVER in
+// .env.example, the default of config/octane.php, or the one whose package
+// is installed
+func octaneServer(dir string, require map[string]string) string {
+	if data, err := os.ReadFile(filepath.Join(dir, ".env.example")); err == nil {
+		if m := octaneServerEnv.FindSubmatch(data); m != nil {
+			return string(m[1])
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "config", "octane.php")); err == nil {
+		if m := octaneServerDefault.FindSubmatch(data); m != nil {
+			return string(m[1])
+		}
+	}
+	if _, ok := require["spiral/roadrunner-http"]; ok {
+		return "roadrunner"
+	}
+	if _, ok := require["ext-swoole"]; ok {
+		return "swoole"
+	}
+	return "frankenphp"
+}
@@ -0,0 +1,44 @@
+package detect
+
+import "testing"
+
+func TestLaravelStartCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "artisan serve",
+			files: map[string]string{"composer.json": `{"require": {"laravel/framework": "^11.0"}}`},
+			want:  "php artisan serve --host=0.0.0.0 --port=8000",
+		},
+		{
+			name: "octane server from env",
+			files: map[string]string{
+				"composer.json": `{"require": {"laravel/framework": "^11.0", "laravel/octane": "^2.0"}}`,
+				".env.example":  "APP_NAME=Shop\nOCTANE_SERVER=swoole\n",
+			},
+			want: "php artisan octane:start --server=swoole --host=0.0.0.0 --port=8000",
+		},
+		{
+			name: "octane roadrunner package",
+			files: map[string]string{
+				"composer.json": `{"require": {"laravel/framework": "^11.0", "laravel/octane": "^2.0", "spiral/roadrunner-http": "^3.0"}}`,
+			},
+			want: "php artisan octane:start --server=roadrunner --host=0.0.0.0 --port=8000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Detect(writeFiles(t, tt.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.StartCommand != tt.want {
+				t.Errorf("StartCommand = %q, want %q", info.StartCommand, tt.want)
+			}
+		})
+	}
+}
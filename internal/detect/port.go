@@ -0,0 +1,175 @@
+package detect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// portSource reads the port an app listens on from one file
+type portSource struct {
+	files []string
+	re    *regexp.Regexp // the port is the last non-empty group
+}
+
+var (
+	// Port flags of start commands: next start -p 4000, --port=4000,
+	// gunicorn -b 0.0.0.0:8000 and PORT=4000 node server.js
+	startCommandPort = regexp.MustCompile(`(?:(?:^|\s)(?:-p|--port)[=\s]+(\d{2,5})\b|(?:^|\s)(?:-b|--bind)[=\s]+\S*:(\d{2,5})\b|\bPORT=(\d{2,5})\b)`)
+	dockerfileExpose = regexp.MustCompile(`(?mi)^\s*EXPOSE\s+(\d{2,5})`)
+)
+
+// portConfigSources are framework config files, in the order they are checked
+var portConfigSources = []portSource{
+	// Vite's preview server and Astro's standalone server
+	{
+		files: []string{"vite.config.ts", "vite.config.js", "vite.config.mjs", "astro.config.mjs", "astro.config.ts"},
+		re:    regexp.MustCompile(`(?s)(?:preview|server)\s*:\s*\{[^}]*?\bport\s*:\s*(\d{2,5})`),
+	},
+	// Spring Boot, Quarkus and Micronaut; ${PORT:8081} falls back to 8081
+	{
+		files: []string{"src/main/resources/application.properties"},
+		re:    regexp.MustCompile(`(?m)^\s*(?:server\.port|quarkus\.http\.port|micronaut\.server\.port)\s*[=:]\s*(?:\$\{\w+:(\d{2,5})\}|(\d{2,5}))`),
+	},
+	{
+		files: []string{"src/main/resources/application.yml", "src/main/resources/application.yaml"},
+		re:    regexp.MustCompile(`(?m)^\s*(?:server|http):\s*\n(?:[ \t]+.*\n)*?[ \t]+port:\s*(?:\$\{\w+:(\d{2,5})\}|(\d{2,5}))`),
+	},
+	{
+		files: []string{"gunicorn.conf.py", "gunicorn_config.py", "gunicorn.py"},
+		re:    regexp.MustCompile(`(?m)^bind\s*=\s*\[?\s*["'][^"']*:(\d{2,5})["']`),
+	},
+}
+
+// portCodeFiles are the entry points whose listen calls are checked
+var portCodeFiles = []string{
+	"server.js", "index.js", "app.js", "main.js", "server.ts", "index.ts", "app.ts",
+	"src/server.js", "src/index.js", "src/app.js", "src/main.js",
+	"src/server.ts", "src/index.ts", "src/app.ts", "src/main.ts",
+	"main.go", "cmd/server/main.go",
+	"app.py", "main.py", "server.py", "wsgi.py", "asgi.py",
+}
+
+// portCodePatterns are the common ways code picks its port:
+// listen(4000), PORT || 4000, port=8000 and ":8080" addresses in Go
+var portCodePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\bPORT\b["'\]]*\s*(?:\|\||\?\?)\s*["']?(\d{2,5})\b`),
+	regexp.MustCompile(`\.listen\(\s*(\d{2,5})\b`),
+	regexp.MustCompile(`\b(?:ListenAndServe|Addr:?|Listen|Run)\(?\s*"(?:0\.0\.0\.0|localhost)?:(\d{2,5})"`),
+	regexp.MustCompile(`\bos\.(?:getenv|environ\.get)\(\s*["']PORT["']\s*,\s*["']?(\d{2,5})\b`),
+	regexp.MustCompile(`\b(?:app|uvicorn)\.run\([^)]*\bport\s*=\s*(\d{2,5})\b`),
+}
+
+// detectPort sets the port an app listens on from its start command,
+// framework config or code, keeping the framework default if none says
+func detectPort(dir string, info *FrameworkInfo) {
+	// .NET start commands already carry the port in --urls
+	if info.IsStatic || info.BuildPack == BuildPackDockerCompose || info.Runtime == RuntimeDotNet {
+		return
+	}
+	port, source := findPort(dir, info)
+	if port == "" {
+		return
+	}
+	// Start commands written for the default port would override the app's
+	// own configuration, so they follow it
+	info.StartCommand = startCommandPort.ReplaceAllStringFunc(info.StartCommand, func(flag string) string {
+		return strings.Replace(flag, info.Port, port, 1)
+	})
+	info.Port, info.PortSource = port, source
+}
+
+func findPort(dir string, info *FrameworkInfo) (port, source string) {
+	if info.BuildPack == BuildPackDockerfile {
+		if data, err := os.ReadFile(filepath.Join(dir, "Dockerfile")); err == nil {
+			if m := dockerfileExpose.FindStringSubmatch(string(data)); m != nil {
+				return m[1], "Dockerfile"
+			}
+		}
+		return "", ""
+	}
+
+	if p := lastGroup(startCommandPort.FindStringSubmatch(ReadProcfile(dir)["web"])); validPort(p) {
+		return p, "Procfile"
+	}
+	if p, script := packageScriptPort(dir, info.StartCommand); validPort(p) {
+		return p, "package.json " + script + " script"
+	}
+
+	for _, src := range portConfigSources {
+		for _, file := range src.files {
+			data, err := os.ReadFile(filepath.Join(dir, file))
+			if err != nil {
+				continue
+			}
+			if p := lastGroup(src.re.FindStringSubmatch(string(data))); validPort(p) {
+				return p, file
+			}
+		}
+	}
+
+	for _, file := range portCodeFiles {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			continue
+		}
+		for _, re := range portCodePatterns {
+			if p := lastGroup(re.FindStringSubmatch(string(data))); validPort(p) {
+				return p, file
+			}
+		}
+	}
+	return "", ""
+}
+
+// packageScriptPort reads the port flag of the package.json script an
+// "npm start" or "npm run x" start command runs
+func packageScriptPort(dir, startCommand string) (port, script string) {
+	fields := strings.Fields(startCommand)
+	switch {
+	case len(fields) == 2 && fields[1] == "start":
+		script = "start"
+	case len(fields) == 3 && fields[1] == "run":
+		script = fields[2]
+	default:
+		return "", ""
+	}
+	if manager := fields[0]; manager != "npm" && manager != "pnpm" && manager != "yarn" && manager != "bun" {
+		return "", ""
+	}
+
+	scripts := readPackageScripts(dir)
+	return lastGroup(startCommandPort.FindStringSubmatch(scripts[script])), script
+}
+
+func readPackageScripts(dir string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+	return pkg.Scripts
+}
+
+// lastGroup returns the last non-empty group of a match, or ""
+func lastGroup(m []string) string {
+	for i := len(m) - 1; i > 0; i-- {
+		if m[i] != "" {
+			return m[i]
+		}
+	}
+	return ""
+}
+
+func validPort(p string) bool {
+	n, err := strconv.Atoi(p)
+	return err == nil && n > 0 && n < 65536
+}
@@ -0,0 +1,89 @@
+package detect
+
+import "testing"
+
+func TestDetectPort(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		port   string
+		source string
+		start  string // start command, if it carries the port
+	}{
+		{
+			name: "framework default",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"next": "14"}, "scripts": {"start": "next start"}}`,
+			},
+			port: "3000",
+		},
+		{
+			name: "start script flag",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"next": "14"}, "scripts": {"start": "next start -p 4000"}}`,
+			},
+			port:   "4000",
+			source: "package.json start script",
+		},
+		{
+			name: "listen fallback",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"express": "4"}}`,
+				"server.js":    "const port = process.env.PORT || 5000;\napp.listen(port);\n",
+			},
+			port:   "5000",
+			source: "server.js",
+		},
+		{
+			name: "spring server.port",
+			files: map[string]string{
+				"pom.xml": `<project><dependencies><dependency><artifactId>spring-boot-starter-web</artifactId></dependency></dependencies></project>`,
+				"src/main/resources/application.properties": "spring.application.name=api\nserver.port=${PORT:8081}\n",
+			},
+			port:   "8081",
+			source: "src/main/resources/application.properties",
+		},
+		{
+			name: "gunicorn bind",
+			files: map[string]string{
+				"requirements.txt": "flask\ngunicorn\n",
+				"gunicorn.conf.py": "bind = \"0.0.0.0:8001\"\nworkers = 2\n",
+			},
+			port:   "8001",
+			source: "gunicorn.conf.py",
+			start:  "gunicorn --bind 0.0.0.0:8001 app:app",
+		},
+		{
+			name: "go listen address",
+			files: map[string]string{
+				"go.mod":  "module example.com/api\n\ngo 1.25\n",
+				"main.go": "package main\n\nfunc main() { http.ListenAndServe(\":9090\", nil) }\n",
+			},
+			port:   "9090",
+			source: "main.go",
+		},
+		{
+			name: "dockerfile expose",
+			files: map[string]string{
+				"Dockerfile": "FROM node:20\nEXPOSE 8080\nCMD [\"node\", \"server.js\"]\n",
+			},
+			port:   "8080",
+			source: "Dockerfile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Detect(writeFiles(t, tt.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Port != tt.port || info.PortSource != tt.source {
+				t.Errorf("port = %q from %q, want %q from %q", info.Port, info.PortSource, tt.port, tt.source)
+			}
+			if tt.start != "" && info.StartCommand != tt.start {
+				t.Errorf("StartCommand = %q, want %q", info.StartCommand, tt.start)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package detect
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadProcfile returns the commands of a Heroku style Procfile by process
+// type ("web", "worker", "release", ...), or nil if the project has none
+func ReadProcfile(dir string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(dir, "Procfile"))
+	if err != nil {
+		return nil
+	}
+
+	processes := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, command, ok := strings.Cut(line, ":")
+		name, command = strings.TrimSpace(name), strings.TrimSpace(command)
+		if !ok || name == "" || command == "" || strings.ContainsAny(name, " \t") {
+			continue
+		}
+		processes[name] = command
+	}
+	if len(processes) == 0 {
+		return nil
+	}
+	return processes
+}
+
+// applyProcfile starts the app with the Procfile's web command, which the
+// project wrote for itself and knows better than a framework default
+func applyProcfile(dir string, info *FrameworkInfo) {
+	if info.BuildPack != BuildPackNixpacks {
+		return
+	}
+	if web := ReadProcfile(dir)["web"]; web != "" {
+		info.StartCommand = web
+	}
+}
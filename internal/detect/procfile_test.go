@@ -0,0 +1,50 @@
+package detect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadProcfile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Procfile": "# processes\nweb: gunicorn app:app --bind 0.0.0.0:$PORT\n\nworker: celery -A tasks worker\nnot a process\n",
+	})
+	want := map[string]string{
+		"web":    "gunicorn app:app --bind 0.0.0.0:$PORT",
+		"worker": "celery -A tasks worker",
+	}
+	if got := ReadProcfile(dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadProcfile() = %v, want %v", got, want)
+	}
+	if got := ReadProcfile(t.TempDir()); got != nil {
+		t.Errorf("ReadProcfile() = %v without a Procfile, want nil", got)
+	}
+}
+
+func TestProcfileStartCommand(t *testing.T) {
+	info, err := Detect(writeFiles(t, map[string]string{
+		"package.json": `{"dependencies": {"express": "4"}}`,
+		"Procfile":     "web: node dist/server.js --port 4100\n",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.StartCommand != "node dist/server.js --port 4100" {
+		t.Errorf("StartCommand = %q, want the web process", info.StartCommand)
+	}
+	if info.Port != "4100" || info.PortSource != "Procfile" {
+		t.Errorf("port = %q from %q, want 4100 from Procfile", info.Port, info.PortSource)
+	}
+
+	// A Dockerfile's CMD wins over the Procfile
+	info, err = Detect(writeFiles(t, map[string]string{
+		"Dockerfile": "FROM node:20\n",
+		"Procfile":   "web: node server.js\n",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.StartCommand != "" {
+		t.Errorf("StartCommand = %q for a Dockerfile, want none", info.StartCommand)
+	}
+}
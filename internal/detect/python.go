@@ -0,0 +1,148 @@
+package detect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// pythonTool is how a project installs its dependencies and runs commands
+// in the environment it installed them to
+type pythonTool struct {
+	install string
+	run     string // prefix for commands, "" if they run as is
+	// installsProject is true if the project itself is installed, putting
+	// its [project.scripts] on the PATH
+	installsProject bool
+}
+
+// detectPythonTool picks the tool from the lockfile, then the manifest
+func detectPythonTool(dir string, project *pyproject) pythonTool {
+	switch {
+	case fileExists(filepath.Join(dir, "uv.lock")):
+		return pythonTool{install: "uv sync --frozen --no-dev", run: "uv run --no-sync ", installsProject: true}
+	case fileExists(filepath.Join(dir, "poetry.lock")) || (project != nil && project.Tool.Poetry != nil):
+		// --no-root installs only the dependencies, so a missing README or
+		// package layout doesn't fail the install
+		return pythonTool{install: "poetry install --no-root --only main", run: "poetry run "}
+	case fileExists(filepath.Join(dir, "pdm.lock")):
+		return pythonTool{install: "pdm install --prod", run: "pdm run ", installsProject: true}
+	case fileExists(filepath.Join(dir, "requirements.txt")):
+		return pythonTool{install: "pip install -r requirements.txt"}
+	case fileExists(filepath.Join(dir, "Pipfile")):
+		return pythonTool{install: "pipenv install", run: "pipenv run "}
+	default:
+		return pythonTool{install: "pip install .", installsProject: true}
+	}
+}
+
+// pyproject is the part of pyproject.toml detection reads
+type pyproject struct {
+	Project struct {
+		Name    string            `toml:"name"`
+		Scripts map[string]string `toml:"scripts"`
+	} `toml:"project"`
+	Tool struct {
+		Poetry *struct {
+			Name    string         `toml:"name"`
+			Scripts map[string]any `toml:"scripts"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+// readPyproject returns the project's pyproject.toml, or nil if it has none
+// or it doesn't parse
+func readPyproject(dir string) *pyproject {
+	data, err := os.ReadFile(filepath.Join(dir, "pyproject.toml"))
+	if err != nil {
+		return nil
+	}
+	var project pyproject
+	if err := toml.Unmarshal(data, &project); err != nil {
+		return nil
+	}
+	return &project
+}
+
+// scripts returns the console scripts of [project.scripts] and
+// [tool.poetry.scripts], by name to "module:function"
+func (p *pyproject) scripts() (name string, scripts map[string]string) {
+	scripts = map[string]string{}
+	for k, v := range p.Project.Scripts {
+		scripts[k] = v
+	}
+	name = p.Project.Name
+	if poetry := p.Tool.Poetry; poetry != nil {
+		if name == "" {
+			name = poetry.Name
+		}
+		for k, v := range poetry.Scripts {
+			// Poetry also has {callable = "..."} tables for extras
+			if entry, ok := v.(string); ok {
+				scripts[k] = entry
+			}
+		}
+	}
+	return name, scripts
+}
+
+// serverScripts are the script names that usually start the app
+var serverScripts = []string{"start", "serve", "server", "web", "app", "api"}
+
+// scriptStartCommand starts the project with one of its console scripts:
+// the only one, one named like a server or one named after the project
+func scriptStartCommand(project *pyproject, tool pythonTool) string {
+	if project == nil {
+		return ""
+	}
+	name, scripts := project.scripts()
+	if len(scripts) == 0 {
+		return ""
+	}
+
+	script := ""
+	if len(scripts) == 1 {
+		for k := range scripts {
+			script = k
+		}
+	} else {
+		candidates := append([]string{}, serverScripts...)
+		candidates = append(candidates, name, strings.ReplaceAll(name, "_", "-"))
+		for _, candidate := range candidates {
+			if _, ok := scripts[candidate]; ok && candidate != "" {
+				script = candidate
+				break
+			}
+		}
+	}
+	if script == "" {
+		return ""
+	}
+
+	if tool.installsProject {
+		return tool.run + script
+	}
+	// The project isn't installed, so its scripts aren't either
+	module, function, ok := strings.Cut(scripts[script], ":")
+	if !ok {
+		return ""
+	}
+	// Entries may name extras after the function: "app.cli:main [server]"
+	function, _, _ = strings.Cut(strings.TrimSpace(function), " ")
+	return fmt.Sprintf(`%spython -c "from %s import %s; %s()"`, tool.run, strings.TrimSpace(module), function, function)
+}
+
+// pythonDependencies returns the project's dependency files, lowercased,
+// for finding the framework it uses
+func pythonDependencies(dir string) string {
+	var deps []string
+	for _, file := range []string{"requirements.txt", "pyproject.toml", "Pipfile"} {
+		if data, err := os.ReadFile(filepath.Join(dir, file)); err == nil {
+			deps = append(deps, strings.ToLower(string(data)))
+		}
+	}
+	return strings.Join(deps, "\n")
+}
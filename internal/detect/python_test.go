@@ -0,0 +1,65 @@
+package detect
+
+import "testing"
+
+func TestPythonTooling(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    string
+		install string
+		start   string
+	}{
+		{
+			name: "uv with fastapi in pyproject",
+			files: map[string]string{
+				"pyproject.toml": "[project]\nname = \"api\"\ndependencies = [\"fastapi>=0.110\", \"uvicorn\"]\n",
+				"uv.lock":        "",
+			},
+			want:    "FastAPI",
+			install: "uv sync --frozen --no-dev",
+			start:   "uv run --no-sync uvicorn main:app --host 0.0.0.0 --port 8000",
+		},
+		{
+			name: "poetry script",
+			files: map[string]string{
+				"pyproject.toml": "[tool.poetry]\nname = \"bot\"\n\n[tool.poetry.scripts]\nbot = \"bot.main:run\"\nmigrate = \"bot.db:migrate\"\n",
+				"poetry.lock":    "",
+			},
+			want:    "Python",
+			install: "poetry install --no-root --only main",
+			start:   `poetry run python -c "from bot.main import run; run()"`,
+		},
+		{
+			name: "pdm project script",
+			files: map[string]string{
+				"pyproject.toml": "[project]\nname = \"worker\"\n\n[project.scripts]\nworker-serve = \"worker.app:serve\"\n",
+				"pdm.lock":       "",
+			},
+			want:    "Python",
+			install: "pdm install --prod",
+			start:   "pdm run worker-serve",
+		},
+		{
+			name: "plain pyproject",
+			files: map[string]string{
+				"pyproject.toml": "[project]\nname = \"tool\"\n\n[project.scripts]\ncli = \"tool:main\"\nlint = \"tool:lint\"\n",
+			},
+			want:    "Python",
+			install: "pip install .",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Detect(writeFiles(t, tt.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Name != tt.want || info.InstallCommand != tt.install || info.StartCommand != tt.start {
+				t.Errorf("detected %q, install %q, start %q; want %q, %q, %q",
+					info.Name, info.InstallCommand, info.StartCommand, tt.want, tt.install, tt.start)
+			}
+		})
+	}
+}
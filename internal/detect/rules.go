@@ -0,0 +1,208 @@
+package detect
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+)
+
+//go:embed rules.yaml
+var builtinRulesYAML []byte
+
+// Rules tell Node.js frameworks apart by their dependencies and files
+type Rules struct {
+	PackageManagers []PackageManagerRule `yaml:"package_managers"`
+	Frameworks      []FrameworkRule      `yaml:"frameworks"`
+}
+
+// PackageManagerRule picks the install command from the lockfile
+type PackageManagerRule struct {
+	Lockfiles []string `yaml:"lockfiles"`
+	Install   string   `yaml:"install"`
+}
+
+// FrameworkRule detects one framework, described in rules.yaml
+type FrameworkRule struct {
+	Name             string   `yaml:"name"`
+	Dependencies     []string `yaml:"dependencies"`
+	AnyDependency    []string `yaml:"any_dependency"`
+	Files            []string `yaml:"files"`
+	Install          string   `yaml:"install"`
+	Build            string   `yaml:"build"`
+	Start            string   `yaml:"start"`
+	PublishDirectory string   `yaml:"publish_directory"`
+	Port             string   `yaml:"port"`
+	Static           bool     `yaml:"static"`
+}
+
+var (
+	builtinRules     *Rules
+	builtinRulesErr  error
+	builtinRulesOnce sync.Once
+)
+
+// nodeRules returns the rules for Node.js projects: those in
+// ~/.cool-kit/detect.d first, then the built-in ones
+func nodeRules() (*Rules, error) {
+	builtinRulesOnce.Do(func() {
+		builtinRules, builtinRulesErr = parseRules(builtinRulesYAML)
+	})
+	if builtinRulesErr != nil {
+		return nil, fmt.Errorf("built-in detection rules: %w", builtinRulesErr)
+	}
+
+	rules := &Rules{}
+	if dir := config.GetConfigDir(); dir != "" {
+		user, err := loadRulesDir(filepath.Join(dir, "detect.d"))
+		if err != nil {
+			return nil, err
+		}
+		rules.add(user)
+	}
+	rules.add(builtinRules)
+	return rules, nil
+}
+
+// loadRulesDir reads the *.yaml and *.yml files of dir in name order. A
+// directory that doesn't exist has no rules.
+func loadRulesDir(dir string) (*Rules, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Rules{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read detection rules: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if ext := filepath.Ext(e.Name()); !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	rules := &Rules{}
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read detection rules: %w", err)
+		}
+		parsed, err := parseRules(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid detection rules in %s: %w", path, err)
+		}
+		rules.add(parsed)
+	}
+	return rules, nil
+}
+
+func parseRules(data []byte) (*Rules, error) {
+	rules := &Rules{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	// A misspelled key would silently match more than intended
+	dec.KnownFields(true)
+	if err := dec.Decode(rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	for i, r := range rules.Frameworks {
+		if r.Name == "" {
+			return nil, fmt.Errorf("framework %d has no name", i+1)
+		}
+		if len(r.Dependencies) == 0 && len(r.AnyDependency) == 0 && len(r.Files) == 0 {
+			return nil, fmt.Errorf("framework %q matches every project: set dependencies, any_dependency or files", r.Name)
+		}
+		if r.Static && r.PublishDirectory == "" {
+			return nil, fmt.Errorf("framework %q is static but has no publish_directory", r.Name)
+		}
+	}
+	for i, pm := range rules.PackageManagers {
+		if len(pm.Lockfiles) == 0 || pm.Install == "" {
+			return nil, fmt.Errorf("package manager %d needs lockfiles and install", i+1)
+		}
+	}
+	return rules, nil
+}
+
+func (r *Rules) add(other *Rules) {
+	r.PackageManagers = append(r.PackageManagers, other.PackageManagers...)
+	r.Frameworks = append(r.Frameworks, other.Frameworks...)
+}
+
+// installCommand returns the install command of the first package manager
+// whose lockfile the project has
+func (r *Rules) installCommand(dir string) string {
+	for _, pm := range r.PackageManagers {
+		for _, lockfile := range pm.Lockfiles {
+			if fileExists(filepath.Join(dir, lockfile)) {
+				return pm.Install
+			}
+		}
+	}
+	return "npm install"
+}
+
+// match returns the first framework the project's dependencies and files
+// match, or nil
+func (r *Rules) match(dir string, deps map[string]string) *FrameworkRule {
+	for i := range r.Frameworks {
+		if r.Frameworks[i].matches(dir, deps) {
+			return &r.Frameworks[i]
+		}
+	}
+	return nil
+}
+
+func (fr *FrameworkRule) matches(dir string, deps map[string]string) bool {
+	for _, dep := range fr.Dependencies {
+		if _, ok := deps[dep]; !ok {
+			return false
+		}
+	}
+	if len(fr.AnyDependency) > 0 {
+		found := false
+		for _, dep := range fr.AnyDependency {
+			if _, ok := deps[dep]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, file := range fr.Files {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (fr *FrameworkRule) info(installCmd string) *FrameworkInfo {
+	if fr.Install != "" {
+		installCmd = fr.Install
+	}
+	return &FrameworkInfo{
+		Name:             fr.Name,
+		BuildPack:        BuildPackNixpacks,
+		InstallCommand:   installCmd,
+		BuildCommand:     fr.Build,
+		StartCommand:     fr.Start,
+		PublishDirectory: fr.PublishDirectory,
+		Port:             fr.Port,
+		IsStatic:         fr.Static,
+	}
+}
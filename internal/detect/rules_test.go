@@ -0,0 +1,109 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNodeRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    string
+		install string
+	}{
+		{
+			name:    "t3 before next",
+			files:   map[string]string{"package.json": `{"dependencies": {"next": "14", "@trpc/server": "10"}}`},
+			want:    "T3 Stack",
+			install: "npm install",
+		},
+		{
+			name: "any dependency",
+			files: map[string]string{
+				"package.json":   `{"devDependencies": {"@remix-run/react": "2"}}`,
+				"pnpm-lock.yaml": "",
+			},
+			want:    "Remix",
+			install: "pnpm install",
+		},
+		{
+			name: "generic bun",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"zod": "3"}, "scripts": {"start": "bun server.ts"}}`,
+				"bun.lock":     "",
+			},
+			want:    "Bun",
+			install: "bun install",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Detect(writeFiles(t, tt.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Name != tt.want || info.InstallCommand != tt.install {
+				t.Errorf("detected %q installing with %q, want %q with %q", info.Name, info.InstallCommand, tt.want, tt.install)
+			}
+		})
+	}
+}
+
+func TestLoadRulesDir(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"redwood.yaml": `frameworks:
+  - name: RedwoodJS
+    dependencies: ["@redwoodjs/core"]
+    files: [redwood.toml]
+    build: yarn rw build
+    start: yarn rw serve
+    port: 8910
+`,
+		"notes.txt": "not rules",
+	})
+	rules, err := loadRulesDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules.Frameworks) != 1 || rules.Frameworks[0].Port != "8910" {
+		t.Fatalf("rules = %+v, want the RedwoodJS rule", rules.Frameworks)
+	}
+
+	project := writeFiles(t, map[string]string{"redwood.toml": ""})
+	deps := map[string]string{"@redwoodjs/core": "8", "react": "18"}
+	if rule := rules.match(project, deps); rule == nil || rule.Name != "RedwoodJS" {
+		t.Errorf("match() = %+v, want RedwoodJS", rule)
+	}
+	if rule := rules.match(t.TempDir(), deps); rule != nil {
+		t.Errorf("match() = %+v without redwood.toml, want nil", rule)
+	}
+
+	if rules, err := loadRulesDir(filepath.Join(dir, "missing")); err != nil || len(rules.Frameworks) != 0 {
+		t.Errorf("loadRulesDir() of a missing directory = %+v, %v, want no rules", rules, err)
+	}
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown key":   "frameworks:\n  - name: X\n    dependency: [x]\n",
+		"matches all":   "frameworks:\n  - name: X\n    start: node x.js\n",
+		"static no dir": "frameworks:\n  - name: X\n    dependencies: [x]\n    static: true\n",
+		"no install":    "package_managers:\n  - lockfiles: [x.lock]\n",
+	} {
+		if _, err := parseRules([]byte(data)); err == nil {
+			t.Errorf("%s: parseRules() succeeded, want an error", name)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bad.yml"), []byte("frameworks: [{name: X}]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadRulesDir(dir); err == nil || !strings.Contains(err.Error(), "bad.yml") {
+		t.Errorf("loadRulesDir() error = %v, want one naming bad.yml", err)
+	}
+}
@@ -0,0 +1,140 @@
+package detect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Language runtimes whose version detection reads
+const (
+	RuntimeNode   = "node"
+	RuntimePython = "python"
+	RuntimeGo     = "go"
+	RuntimeDotNet = "dotnet"
+)
+
+// nixpacksVersionVars are the variables that pin a runtime's version in a
+// Nixpacks build
+var nixpacksVersionVars = map[string]string{
+	RuntimeNode:   "NIXPACKS_NODE_VERSION",
+	RuntimePython: "NIXPACKS_PYTHON_VERSION",
+	RuntimeGo:     "NIXPACKS_GO_VERSION",
+}
+
+// nodeLTSNames maps the lts/<name> aliases of .nvmrc to major versions
+var nodeLTSNames = map[string]string{
+	"gallium":  "16",
+	"hydrogen": "18",
+	"iron":     "20",
+	"jod":      "22",
+}
+
+var (
+	majorVersion      = regexp.MustCompile(`\d+`)
+	majorMinorVersion = regexp.MustCompile(`\d+\.\d+`)
+	requiresPython    = regexp.MustCompile(`(?m)^requires-python\s*=\s*["']([^"']+)["']`)
+	goDirective       = regexp.MustCompile(`(?m)^go\s+(\d+\.\d+)`)
+)
+
+// BuildEnv returns the build-time variables that pin the runtime version
+// for Nixpacks, which otherwise builds with its own default
+func (f *FrameworkInfo) BuildEnv() map[string]string {
+	key, ok := nixpacksVersionVars[f.Runtime]
+	if !ok || f.RuntimeVersion == "" || f.BuildPack != BuildPackNixpacks {
+		return nil
+	}
+	return map[string]string{key: f.RuntimeVersion}
+}
+
+// withRuntime records the runtime of a detected project and the version the
+// project asks for
+func withRuntime(info *FrameworkInfo, err error, runtime, version string) (*FrameworkInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	info.Runtime = runtime
+	if info.RuntimeVersion == "" {
+		info.RuntimeVersion = version
+	}
+	return info, nil
+}
+
+// nodeVersion returns the major Node.js version from .nvmrc, .node-version,
+// .tool-versions or the engines field of package.json, or ""
+func nodeVersion(dir string) string {
+	for _, file := range []string{".nvmrc", ".node-version"} {
+		if data, err := os.ReadFile(filepath.Join(dir, file)); err == nil {
+			v := strings.ToLower(strings.TrimSpace(string(data)))
+			if name, ok := strings.CutPrefix(v, "lts/"); ok {
+				return nodeLTSNames[name]
+			}
+			return majorVersion.FindString(v)
+		}
+	}
+	if v := toolVersion(dir, "nodejs"); v != "" {
+		return majorVersion.FindString(v)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Engines map[string]string `json:"engines"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+	// The lowest version a range allows, e.g. 18 for ">=18 <21"
+	return majorVersion.FindString(pkg.Engines["node"])
+}
+
+// pythonVersion returns the Python major.minor version from
+// .python-version, .tool-versions, runtime.txt or requires-python, or ""
+func pythonVersion(dir string) string {
+	if data, err := os.ReadFile(filepath.Join(dir, ".python-version")); err == nil {
+		return majorMinorVersion.FindString(string(data))
+	}
+	if v := toolVersion(dir, "python"); v != "" {
+		return majorMinorVersion.FindString(v)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "runtime.txt")); err == nil {
+		return majorMinorVersion.FindString(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "pyproject.toml")); err == nil {
+		if m := requiresPython.FindStringSubmatch(string(data)); m != nil {
+			return majorMinorVersion.FindString(m[1])
+		}
+	}
+	return ""
+}
+
+// goVersion returns the major.minor version of the go.mod go directive, or ""
+func goVersion(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	if m := goDirective.FindStringSubmatch(string(data)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// toolVersion returns a tool's version from an asdf/mise .tool-versions file
+func toolVersion(dir, tool string) string {
+	data, err := os.ReadFile(filepath.Join(dir, ".tool-versions"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == tool {
+			return fields[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package detect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuntimeVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		runtime string
+		version string
+	}{
+		{
+			name: "nvmrc",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"express": "4"}, "engines": {"node": ">=18"}}`,
+				".nvmrc":       "v20.11.1\n",
+			},
+			runtime: RuntimeNode,
+			version: "20",
+		},
+		{
+			name: "nvmrc lts alias",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"next": "14"}}`,
+				".nvmrc":       "lts/iron",
+			},
+			runtime: RuntimeNode,
+			version: "20",
+		},
+		{
+			name: "package.json engines",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"vite": "5"}, "engines": {"node": "^22.0.0"}}`,
+			},
+			runtime: RuntimeNode,
+			version: "22",
+		},
+		{
+			name: "no node version",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"express": "4"}}`,
+			},
+			runtime: RuntimeNode,
+		},
+		{
+			name: "python-version",
+			files: map[string]string{
+				"requirements.txt": "flask\n",
+				".python-version":  "3.11.9\n",
+			},
+			runtime: RuntimePython,
+			version: "3.11",
+		},
+		{
+			name: "requires-python",
+			files: map[string]string{
+				"pyproject.toml": "[project]\nname = \"api\"\nrequires-python = \">=3.12\"\n",
+			},
+			runtime: RuntimePython,
+			version: "3.12",
+		},
+		{
+			name: "tool-versions",
+			files: map[string]string{
+				"requirements.txt": "fastapi\n",
+				".tool-versions":   "nodejs 20.1.0\npython 3.10.14\n",
+			},
+			runtime: RuntimePython,
+			version: "3.10",
+		},
+		{
+			name: "go directive",
+			files: map[string]string{
+				"go.mod": "module example.com/api\n\ngo 1.25.5\n\ntoolchain go1.25.6\n",
+			},
+			runtime: RuntimeGo,
+			version: "1.25",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Detect(writeFiles(t, tt.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Runtime != tt.runtime || info.RuntimeVersion != tt.version {
+				t.Errorf("runtime = %q %q, want %q %q", info.Runtime, info.RuntimeVersion, tt.runtime, tt.version)
+			}
+		})
+	}
+}
+
+func TestBuildEnv(t *testing.T) {
+	info := &FrameworkInfo{BuildPack: BuildPackNixpacks, Runtime: RuntimeNode, RuntimeVersion: "20"}
+	if got, want := info.BuildEnv(), map[string]string{"NIXPACKS_NODE_VERSION": "20"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildEnv() = %v, want %v", got, want)
+	}
+
+	// Only Nixpacks reads the variables
+	info.BuildPack = BuildPackDockerfile
+	if got := info.BuildEnv(); got != nil {
+		t.Errorf("BuildEnv() = %v for a Dockerfile build, want nil", got)
+	}
+}
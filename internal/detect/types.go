@@ -9,7 +9,11 @@ type FrameworkInfo struct {
 	StartCommand     string
 	PublishDirectory string
 	Port             string
+	PortSource       string // where Port was read from, "" for the framework default
 	IsStatic         bool
+	Runtime          string       // RuntimeNode, RuntimePython, RuntimeGo, RuntimeDotNet or ""
+	RuntimeVersion   string       // language runtime the project targets, e.g. 8.0 for .NET 8
+	Compose          *ComposeFile // services of a Docker Compose project
 }
 
 // Common build packs
@@ -24,8 +24,23 @@ type BuildOptions struct {
 	Framework *detect.FrameworkInfo
 	Platform  string // e.g., "linux/amd64" or "linux/arm64"
 	Verbose   bool   // Show full output instead of hiding it
+
+	// Config picks the buildx builder and build cache; nil builds without
+	// exporting a cache
+	Config *config.BuildConfig
+	// Project names the default local cache directory
+	Project string
+	// PreviousTag is the last deployed tag, the inline cache's source
+	PreviousTag string
+	// Registry is logged in to before builds that read or write a cache
+	// in it
+	Registry *config.DockerRegistry
 }
 
+// cacheBuilderName is the docker-container builder created for caches the
+// default docker driver can't export
+const cacheBuilderName = "cool-kit"
+
 // Build builds a Docker image for the project
 func Build(opts *BuildOptions) (err error) {
 	// Generate Dockerfile if one doesn't exist
@@ -60,8 +75,14 @@ func Build(opts *BuildOptions) (err error) {
 		platform = config.DefaultPlatform
 	}
 
-	imageTag := fmt.Sprintf("%s:%s", opts.ImageName, opts.Tag)
-	args := []string{"build", "--progress=plain", "--platform", platform, "-t", imageTag, "-f", dockerfilePath, opts.Dir}
+	args := []string{"build", "--progress=plain", "--platform", platform, "-t", fmt.Sprintf("%s:%s", opts.ImageName, opts.Tag), "-f", dockerfilePath, opts.Dir}
+	var cacheDir string
+	if hasBuildx() {
+		args, cacheDir, err = buildxArgs(opts, dockerfilePath, platform)
+		if err != nil {
+			return err
+		}
+	}
 
 	cmd := exec.Command("docker", args...)
 	cmd.Dir = opts.Dir
@@ -130,9 +151,115 @@ func Build(opts *BuildOptions) (err error) {
 		}
 	}
 
+	if cacheDir != "" {
+		return rotateLocalCache(cacheDir)
+	}
+	return nil
+}
+
+// buildxArgs returns the arguments of a docker buildx build, and the
+// directory of its local cache, if any
+func buildxArgs(opts *BuildOptions, dockerfilePath, platform string) ([]string, string, error) {
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = &config.BuildConfig{}
+	}
+	imageTag := fmt.Sprintf("%s:%s", opts.ImageName, opts.Tag)
+
+	builder := cfg.Builder
+	if builder == "" && cfg.Cache != "" {
+		var err error
+		if builder, err = cacheBuilder(); err != nil {
+			return nil, "", err
+		}
+	}
+	args := []string{"buildx", "build"}
+	if builder != "" {
+		args = append(args, "--builder", builder)
+	}
+	args = append(args, "--progress=plain", "--platform", platform, "-t", imageTag, "-f", dockerfilePath, "--load")
+
+	var cacheDir string
+	switch cfg.Cache {
+	case config.BuildCacheLocal:
+		cacheDir = cfg.CacheRef
+		if cacheDir == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to get home directory: %w", err)
+			}
+			cacheDir = filepath.Join(home, ".cool-kit", "buildcache", opts.Project)
+		}
+		if err := os.MkdirAll(filepath.Dir(cacheDir), 0750); err != nil {
+			return nil, "", fmt.Errorf("failed to create build cache directory: %w", err)
+		}
+		// Importing a cache that was never exported fails the build
+		if _, err := os.Stat(filepath.Join(cacheDir, "index.json")); err == nil {
+			args = append(args, "--cache-from", "type=local,src="+cacheDir)
+		}
+		// Exported next to the old cache, which isn't pruned otherwise
+		args = append(args, "--cache-to", "type=local,mode=max,dest="+cacheDir+".new")
+	case config.BuildCacheRegistry:
+		ref := cfg.CacheRef
+		if ref == "" {
+			ref = opts.ImageName + ":buildcache"
+		}
+		args = append(args, "--cache-from", "type=registry,ref="+ref, "--cache-to", "type=registry,mode=max,ref="+ref)
+	}
+	if cfg.InlineCache {
+		args = append(args, "--cache-to", "type=inline")
+		if opts.PreviousTag != "" {
+			args = append(args, "--cache-from", fmt.Sprintf("type=registry,ref=%s:%s", opts.ImageName, opts.PreviousTag))
+		}
+	}
+
+	if (cfg.Cache == config.BuildCacheRegistry || cfg.InlineCache) && opts.Registry != nil && opts.Registry.Username != "" && opts.Registry.Password != "" {
+		if err := login(opts.Registry.URL, opts.Registry.Username, opts.Registry.Password, opts.Verbose); err != nil {
+			return nil, "", fmt.Errorf("failed to login to registry: %w", err)
+		}
+	}
+	return append(args, opts.Dir), cacheDir, nil
+}
+
+// cacheBuilder returns the builder to export a cache with: the current
+// one, unless it uses the docker driver, which can't export caches
+func cacheBuilder() (string, error) {
+	out, err := exec.Command("docker", "buildx", "inspect").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect the buildx builder: %w", err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if driver, ok := strings.CutPrefix(strings.TrimSpace(line), "Driver:"); ok && strings.TrimSpace(driver) != "docker" {
+			return "", nil
+		}
+	}
+
+	if exec.Command("docker", "buildx", "inspect", cacheBuilderName).Run() == nil {
+		return cacheBuilderName, nil
+	}
+	if out, err := exec.Command("docker", "buildx", "create", "--name", cacheBuilderName, "--driver", "docker-container").CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to create buildx builder %s: %s", cacheBuilderName, strings.TrimSpace(string(out)))
+	}
+	return cacheBuilderName, nil
+}
+
+// rotateLocalCache replaces the local cache in dir with the one the build
+// exported
+func rotateLocalCache(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to replace build cache: %w", err)
+	}
+	if err := os.Rename(dir+".new", dir); err != nil {
+		return fmt.Errorf("failed to replace build cache: %w", err)
+	}
 	return nil
 }
 
+// hasBuildx reports whether the docker buildx plugin is installed
+func hasBuildx() bool {
+	return exec.Command("docker", "buildx", "version").Run() == nil
+}
+
 // GenerateTag generates a unique tag for the image
 func GenerateTag(env string) string {
 	// Create a short hash based on timestamp
@@ -0,0 +1,86 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/ci"
+	"github.com/entro314-labs/cool-kit/internal/config"
+)
+
+// acrTokenUser is the user name ACR access tokens are used with
+const acrTokenUser = "00000000-0000-0000-0000-000000000000"
+
+// RegistryCredentials gets short-lived credentials for the registry of
+// image from auth's helper. githubToken is the GHCR helper's token; the
+// GITHUB_TOKEN of GitHub Actions is used without one.
+func RegistryCredentials(auth *config.RegistryAuthConfig, image, githubToken string) (*config.DockerRegistry, error) {
+	host := ci.RegistryHost(image)
+	switch auth.Helper {
+	case config.RegistryHelperECR:
+		region := auth.Region
+		if region == "" {
+			// Like 123456789012.dkr.ecr.eu-west-1.amazonaws.com
+			if parts := strings.Split(host, "."); len(parts) >= 6 && parts[1] == "dkr" && parts[2] == "ecr" {
+				region = parts[3]
+			}
+		}
+		if region == "" {
+			return nil, fmt.Errorf("%s is not an ECR registry; set registry_auth.region", host)
+		}
+		args := []string{"ecr", "get-login-password", "--region", region}
+		if auth.Profile != "" {
+			args = append(args, "--profile", auth.Profile)
+		}
+		password, err := helperOutput("aws", args...)
+		if err != nil {
+			return nil, err
+		}
+		return &config.DockerRegistry{URL: host, Username: "AWS", Password: password}, nil
+
+	case config.RegistryHelperGHCR:
+		token := githubToken
+		if token == "" {
+			token = os.Getenv("GITHUB_TOKEN")
+		}
+		if token == "" {
+			return nil, fmt.Errorf("the ghcr registry helper needs a GitHub token: run 'login' or set %s", config.EnvGitHubToken)
+		}
+		// GHCR takes any user name with a token; the image's owner reads best in logs
+		user := os.Getenv("GITHUB_ACTOR")
+		if user == "" {
+			user = strings.Split(strings.TrimPrefix(image, host+"/"), "/")[0]
+		}
+		return &config.DockerRegistry{URL: host, Username: user, Password: token}, nil
+
+	case config.RegistryHelperACR:
+		name, _, _ := strings.Cut(host, ".")
+		token, err := helperOutput("az", "acr", "login", "--name", name, "--expose-token", "--output", "tsv", "--query", "accessToken")
+		if err != nil {
+			return nil, err
+		}
+		return &config.DockerRegistry{URL: host, Username: acrTokenUser, Password: token}, nil
+	}
+	return nil, fmt.Errorf("unknown registry helper %q", auth.Helper)
+}
+
+// helperOutput runs a cloud CLI and returns its output, the credential
+func helperOutput(name string, args ...string) (string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", fmt.Errorf("%s is not installed: it gets the registry credentials", name)
+	}
+	cmd := exec.Command(name, args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s %s failed: %s", name, strings.Join(args[:2], " "), strings.TrimSpace(stderr.String()))
+	}
+	credential := strings.TrimSpace(string(out))
+	if credential == "" {
+		return "", fmt.Errorf("%s %s returned no credentials", name, strings.Join(args[:2], " "))
+	}
+	return credential, nil
+}
@@ -27,6 +27,8 @@ func GenerateDockerfile(framework *detect.FrameworkInfo) string {
 		return generatePythonDockerfile(framework)
 	case "Node.js":
 		return generateNodeDockerfile(framework)
+	case "ASP.NET Core", ".NET":
+		return generateDotNetDockerfile(framework)
 	case "Static Site":
 		return generatePureStaticDockerfile(framework)
 	default:
@@ -35,7 +37,7 @@ func GenerateDockerfile(framework *detect.FrameworkInfo) string {
 }
 
 func generateNextJSDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM node:20-alpine AS base
+	return fmt.Sprintf(`FROM %[1]s AS base
 
 FROM base AS deps
 RUN apk add --no-cache libc6-compat
@@ -63,11 +65,11 @@ ENV PORT 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD wget -qO- http://localhost:3000/ || exit 1
 CMD ["node", "server.js"]
-`
+`, nodeImage(f))
 }
 
 func generateStaticDockerfile(f *detect.FrameworkInfo, outputDir string) string {
-	return fmt.Sprintf(`FROM node:20-alpine AS builder
+	return fmt.Sprintf(`FROM %[1]s AS builder
 WORKDIR /app
 COPY package.json package-lock.json* ./
 RUN npm ci
@@ -75,41 +77,41 @@ COPY . .
 RUN npm run build
 
 FROM nginx:alpine
-COPY --from=builder /app/%s /usr/share/nginx/html
+COPY --from=builder /app/%[2]s /usr/share/nginx/html
 EXPOSE 80
 HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \
   CMD wget -qO- http://localhost:80/ || exit 1
 CMD ["nginx", "-g", "daemon off;"]
-`, outputDir)
+`, nodeImage(f), outputDir)
 }
 
 func generateNuxtDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM node:20-alpine AS builder
+	return fmt.Sprintf(`FROM %[1]s AS builder
 WORKDIR /app
 COPY package.json package-lock.json* ./
 RUN npm ci
 COPY . .
 RUN npm run build
 
-FROM node:20-alpine
+FROM %[1]s
 WORKDIR /app
 COPY --from=builder /app/.output ./
 EXPOSE 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD wget -qO- http://localhost:3000/ || exit 1
 CMD ["node", ".output/server/index.mjs"]
-`
+`, nodeImage(f))
 }
 
 func generateSvelteKitDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM node:20-alpine AS builder
+	return fmt.Sprintf(`FROM %[1]s AS builder
 WORKDIR /app
 COPY package.json package-lock.json* ./
 RUN npm ci
 COPY . .
 RUN npm run build
 
-FROM node:20-alpine
+FROM %[1]s
 WORKDIR /app
 COPY --from=builder /app/build ./build
 COPY --from=builder /app/package.json ./
@@ -118,7 +120,7 @@ EXPOSE 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD wget -qO- http://localhost:3000/ || exit 1
 CMD ["node", "build"]
-`
+`, nodeImage(f))
 }
 
 func generateHugoDockerfile(f *detect.FrameworkInfo) string {
@@ -137,7 +139,7 @@ CMD ["nginx", "-g", "daemon off;"]
 }
 
 func generateGoDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM golang:1.22-alpine AS builder
+	return fmt.Sprintf(`FROM %[1]s AS builder
 WORKDIR /app
 COPY go.mod go.sum* ./
 RUN go mod download
@@ -151,11 +153,11 @@ EXPOSE 8080
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD wget -qO- http://localhost:8080/ || exit 1
 CMD ["./app"]
-`
+`, goImage(f))
 }
 
 func generatePythonDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM python:3.12-slim
+	return fmt.Sprintf(`FROM %[1]s
 WORKDIR /app
 COPY requirements.txt* pyproject.toml* ./
 RUN pip install --no-cache-dir -r requirements.txt 2>/dev/null || pip install --no-cache-dir .
@@ -164,7 +166,7 @@ EXPOSE 8000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD python -c "import urllib.request; urllib.request.urlopen('http://localhost:8000/')" || exit 1
 CMD ["python", "-m", "uvicorn", "main:app", "--host", "0.0.0.0", "--port", "8000"]
-`
+`, pythonImage(f))
 }
 
 func generateNodeDockerfile(f *detect.FrameworkInfo) string {
@@ -173,7 +175,7 @@ func generateNodeDockerfile(f *detect.FrameworkInfo) string {
 	if startCmd == "" {
 		startCmd = "npm start"
 	}
-	return fmt.Sprintf(`FROM node:20-alpine
+	return fmt.Sprintf(`FROM %[1]s
 WORKDIR /app
 COPY package.json package-lock.json* ./
 RUN npm ci --production
@@ -181,8 +183,45 @@ COPY . .
 EXPOSE 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD wget -qO- http://localhost:3000/ || exit 1
-CMD %s
-`, startCmd)
+CMD %[2]s
+`, nodeImage(f), startCmd)
+}
+
+// generateDotNetDockerfile publishes with the SDK image and runs on the much
+// smaller runtime image
+func generateDotNetDockerfile(f *detect.FrameworkInfo) string {
+	version := f.RuntimeVersion
+	if version == "" {
+		version = "8.0"
+	}
+	runtime := "runtime"
+	if f.Name == "ASP.NET Core" {
+		runtime = "aspnet"
+	}
+	buildCmd := f.BuildCommand
+	if buildCmd == "" {
+		buildCmd = "dotnet publish -c Release -o out"
+	}
+	startCmd := f.StartCommand
+	if startCmd == "" {
+		// The entry assembly is the only one with a runtimeconfig.json
+		startCmd = "dotnet out/$(basename out/*.runtimeconfig.json .runtimeconfig.json).dll"
+	}
+
+	expose := ""
+	if f.Port != "" {
+		expose = "EXPOSE " + f.Port + "\n"
+	}
+	return fmt.Sprintf(`FROM mcr.microsoft.com/dotnet/sdk:%[1]s AS build
+WORKDIR /src
+COPY . .
+RUN %[3]s
+
+FROM mcr.microsoft.com/dotnet/%[2]s:%[1]s
+WORKDIR /app
+COPY --from=build /src/out ./out
+%[4]sCMD %[5]s
+`, version, runtime, buildCmd, expose, startCmd)
 }
 
 func generatePureStaticDockerfile(f *detect.FrameworkInfo) string {
@@ -196,7 +235,7 @@ CMD ["nginx", "-g", "daemon off;"]
 }
 
 func generateGenericDockerfile(f *detect.FrameworkInfo) string {
-	return `FROM node:20-alpine
+	return fmt.Sprintf(`FROM %[1]s
 WORKDIR /app
 COPY . .
 RUN npm install 2>/dev/null || true
@@ -204,5 +243,31 @@ EXPOSE 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \
   CMD wget -qO- http://localhost:3000/ || exit 1
 CMD ["npm", "start"]
-`
+`, nodeImage(f))
+}
+
+// nodeImage returns the Node.js image for the version the project asks for
+func nodeImage(f *detect.FrameworkInfo) string {
+	return runtimeImage(f, detect.RuntimeNode, "node", "20", "-alpine")
+}
+
+// goImage returns the Go image for the go.mod version, which must be at
+// least the one the module declares
+func goImage(f *detect.FrameworkInfo) string {
+	return runtimeImage(f, detect.RuntimeGo, "golang", "1.22", "-alpine")
+}
+
+// pythonImage returns the Python image for the version the project asks for
+func pythonImage(f *detect.FrameworkInfo) string {
+	return runtimeImage(f, detect.RuntimePython, "python", "3.12", "-slim")
+}
+
+// runtimeImage tags repo with the project's runtime version, or with
+// fallback when it doesn't ask for one
+func runtimeImage(f *detect.FrameworkInfo, runtime, repo, fallback, variant string) string {
+	version := fallback
+	if f.Runtime == runtime && f.RuntimeVersion != "" {
+		version = f.RuntimeVersion
+	}
+	return repo + ":" + version + variant
 }
@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Layer is a layer of an image, with the build step that created it
+type Layer struct {
+	CreatedBy string
+	Size      int64
+}
+
+// Sizes above which the size report warns
+const (
+	largeCopyLayer = 300 << 20
+	largeImage     = 1 << 30
+)
+
+// bloatRules are build steps that leave files the image doesn't need: a
+// step matching a rule's command but none of its fixes gets its warning
+var bloatRules = []struct {
+	command []string
+	fixes   []string
+	warning string
+}{
+	{[]string{"apt-get install", "apt install"}, []string{"/var/lib/apt/lists"}, "apt package lists are kept: add 'rm -rf /var/lib/apt/lists/*' to the same RUN"},
+	{[]string{"apk add"}, []string{"--no-cache"}, "the apk cache is kept: use 'apk add --no-cache'"},
+	{[]string{"pip install"}, []string{"--no-cache-dir", "pip_no_cache_dir"}, "the pip cache is kept: use 'pip install --no-cache-dir'"},
+	{[]string{"npm install", "npm ci"}, []string{"--omit=dev", "--production", "--only=prod", "node_env=production"}, "npm installs dev dependencies in the final image: use 'npm ci --omit=dev', or build in a separate stage"},
+	{[]string{"yarn install"}, []string{"--production", "node_env=production"}, "yarn installs dev dependencies in the final image: use 'yarn install --production', or build in a separate stage"},
+	{[]string{"pnpm install"}, []string{"--prod", "node_env=production"}, "pnpm installs dev dependencies in the final image: use 'pnpm install --prod', or build in a separate stage"},
+}
+
+// History returns the layers of a local image, oldest first
+func History(imageName, tag string) ([]Layer, error) {
+	image := fmt.Sprintf("%s:%s", imageName, tag)
+	out, err := exec.Command("docker", "history", "--no-trunc", "--human=false", "--format", "{{json .}}", image).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the history of %s: %w", image, err)
+	}
+
+	var layers []Layer
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			CreatedBy string `json:"CreatedBy"`
+			Size      string `json:"Size"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse the history of %s: %w", image, err)
+		}
+		size, _ := strconv.ParseInt(entry.Size, 10, 64)
+		layers = append(layers, Layer{CreatedBy: cleanCreatedBy(entry.CreatedBy), Size: size})
+	}
+	// docker history lists the newest layer first
+	for i, j := 0, len(layers)-1; i < j; i, j = i+1, j-1 {
+		layers[i], layers[j] = layers[j], layers[i]
+	}
+	return layers, scanner.Err()
+}
+
+// cleanCreatedBy strips the shell and builder noise from a build step
+func cleanCreatedBy(step string) string {
+	step = strings.TrimSuffix(strings.TrimSpace(step), "# buildkit")
+	switch {
+	case strings.HasPrefix(step, "/bin/sh -c #(nop) "):
+		step = strings.TrimPrefix(step, "/bin/sh -c #(nop) ")
+	case strings.HasPrefix(step, "/bin/sh -c "):
+		step = "RUN " + strings.TrimPrefix(step, "/bin/sh -c ")
+	case strings.HasPrefix(step, "RUN /bin/sh -c "):
+		step = "RUN " + strings.TrimPrefix(step, "RUN /bin/sh -c ")
+	}
+	return strings.Join(strings.Fields(step), " ")
+}
+
+// TotalSize returns the sum of the layers' sizes
+func TotalSize(layers []Layer) int64 {
+	var total int64
+	for _, l := range layers {
+		total += l.Size
+	}
+	return total
+}
+
+// BloatWarnings points out the build steps and layers likely to make the
+// image bigger than it needs to be
+func BloatWarnings(layers []Layer) []string {
+	var warnings []string
+	seen := map[string]bool{}
+	for _, l := range layers {
+		step := strings.ToLower(l.CreatedBy)
+		for _, rule := range bloatRules {
+			if containsAny(step, rule.command) && !containsAny(step, rule.fixes) && !seen[rule.warning] {
+				seen[rule.warning] = true
+				warnings = append(warnings, rule.warning)
+			}
+		}
+		if strings.HasPrefix(step, "copy") && l.Size > largeCopyLayer {
+			warnings = append(warnings, fmt.Sprintf("%s copies %s: check that .dockerignore leaves out node_modules, .git and build output", TruncateStep(l.CreatedBy, 40), FormatSize(l.Size)))
+		}
+	}
+	if total := TotalSize(layers); total > largeImage {
+		warnings = append(warnings, fmt.Sprintf("the image is %s: a slim or distroless base image and a multi-stage build keep deployments fast on small servers", FormatSize(total)))
+	}
+	return warnings
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// TruncateStep shortens a build step to n characters, for tables
+func TruncateStep(step string, n int) string {
+	if len(step) <= n {
+		return step
+	}
+	return step[:n-3] + "..."
+}
+
+// FormatSize formats a size in bytes for people
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,83 @@
+package docker
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/ui"
+)
+
+// PackOptions contains options for building an image with buildpacks
+type PackOptions struct {
+	Dir       string
+	ImageName string
+	Tag       string
+	Config    *config.BuildpacksConfig // may be nil
+	Verbose   bool                     // Show full output instead of hiding it
+}
+
+// IsPackAvailable checks if the pack CLI is installed
+func IsPackAvailable() bool {
+	return exec.Command("pack", "version").Run() == nil
+}
+
+// BuildPack builds an image from the project's source with Cloud Native
+// Buildpacks, into the local docker like Build
+func BuildPack(opts *PackOptions) error {
+	if _, err := exec.LookPath("pack"); err != nil {
+		return fmt.Errorf("pack is not installed: install it from https://buildpacks.io/docs/tools/pack/")
+	}
+	args := []string{"build", fmt.Sprintf("%s:%s", opts.ImageName, opts.Tag), "--path", opts.Dir, "--builder", opts.Config.BuilderImage(), "--trust-builder"}
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = &config.BuildpacksConfig{}
+	}
+	for _, bp := range cfg.Buildpacks {
+		args = append(args, "--buildpack", bp)
+	}
+	keys := make([]string, 0, len(cfg.Env))
+	for k := range cfg.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--env", k+"="+cfg.Env[k])
+	}
+
+	cmd := exec.Command("pack", args...)
+	cmd.Dir = opts.Dir
+	if !opts.Verbose {
+		// In normal mode, capture output (only shown on error via CDP_DEBUG)
+		cmdOut := ui.NewCmdOutput()
+		cmd.Stdout = cmdOut
+		cmd.Stderr = cmdOut
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("pack build failed: %w", err)
+		}
+		return nil
+	}
+
+	// In verbose mode, stream output with dim styling like deployment logs
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("pack build failed to start: %w", err)
+	}
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			fmt.Println(ui.DimStyle.Render("  " + line))
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("pack build failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Severities are the vulnerability severities Trivy reports, lowest first
+var Severities = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// Vulnerability is a vulnerable package found in an image
+type Vulnerability struct {
+	ID               string `json:"VulnerabilityID"`
+	Package          string `json:"PkgName"`
+	InstalledVersion string `json:"InstalledVersion"`
+	FixedVersion     string `json:"FixedVersion"`
+	Severity         string `json:"Severity"`
+	Title            string `json:"Title"`
+}
+
+// ScanReport lists the vulnerabilities found at or above the scan's
+// severity, most severe first
+type ScanReport struct {
+	Vulnerabilities []Vulnerability
+}
+
+// ScanOptions contains options for scanning an image
+type ScanOptions struct {
+	ImageName     string
+	Tag           string
+	Severity      string // lowest reported, default CRITICAL
+	IgnoreUnfixed bool
+}
+
+// Scan scans a local image for known vulnerabilities with the trivy binary
+func Scan(opts *ScanOptions) (*ScanReport, error) {
+	if _, err := exec.LookPath("trivy"); err != nil {
+		return nil, fmt.Errorf("trivy is not installed: install it from https://trivy.dev or turn off the image scan")
+	}
+	severities, err := severitiesFrom(opts.Severity)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"image", "--quiet", "--format", "json", "--scanners", "vuln", "--severity", strings.Join(severities, ",")}
+	if opts.IgnoreUnfixed {
+		args = append(args, "--ignore-unfixed")
+	}
+	args = append(args, fmt.Sprintf("%s:%s", opts.ImageName, opts.Tag))
+
+	cmd := exec.Command("trivy", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("trivy failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return parseTrivyReport(out)
+}
+
+// severitiesFrom returns the severities at or above lowest
+func severitiesFrom(lowest string) ([]string, error) {
+	if lowest == "" {
+		lowest = "CRITICAL"
+	}
+	for i, s := range Severities {
+		if strings.EqualFold(s, lowest) {
+			return Severities[i:], nil
+		}
+	}
+	return nil, fmt.Errorf("invalid severity %q: use %s", lowest, strings.Join(Severities, ", "))
+}
+
+func parseTrivyReport(data []byte) (*ScanReport, error) {
+	var report struct {
+		Results []struct {
+			Vulnerabilities []Vulnerability `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy report: %w", err)
+	}
+
+	// The same vulnerability is reported once per file of its package
+	seen := map[string]bool{}
+	result := &ScanReport{}
+	for _, r := range report.Results {
+		for _, v := range r.Vulnerabilities {
+			key := v.ID + " " + v.Package + " " + v.InstalledVersion
+			if !seen[key] {
+				seen[key] = true
+				result.Vulnerabilities = append(result.Vulnerabilities, v)
+			}
+		}
+	}
+	sort.SliceStable(result.Vulnerabilities, func(i, j int) bool {
+		return severityRank(result.Vulnerabilities[i].Severity) > severityRank(result.Vulnerabilities[j].Severity)
+	})
+	return result, nil
+}
+
+func severityRank(severity string) int {
+	for i, s := range Severities {
+		if s == strings.ToUpper(severity) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Summary counts the report's vulnerabilities by severity, like
+// "2 critical, 5 high"
+func (r *ScanReport) Summary() string {
+	counts := map[string]int{}
+	for _, v := range r.Vulnerabilities {
+		counts[strings.ToUpper(v.Severity)]++
+	}
+	var parts []string
+	for i := len(Severities) - 1; i >= 0; i-- {
+		if n := counts[Severities[i]]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, strings.ToLower(Severities[i])))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
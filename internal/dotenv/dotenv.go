@@ -0,0 +1,164 @@
+// Package dotenv reads and writes .env files.
+package dotenv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Var is a single KEY=value entry
+type Var struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// keyPattern matches valid variable names
+var keyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*$`)
+
+// ValidKey reports whether key can be the name of a variable
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
+
+// ParseFile reads and parses a .env file
+func ParseFile(path string) ([]Var, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads dotenv entries from r. It supports comments, an optional
+// "export " prefix, unquoted values with trailing " #" comments, single
+// quoted literal values and double quoted values with escapes. Quoted values
+// may span multiple lines. Later entries override earlier ones with the same key.
+func Parse(r io.Reader) ([]Var, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var vars []Var
+	index := make(map[string]int)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		start := lineNum
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		key, rest, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !keyPattern.MatchString(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=value", start)
+		}
+		rest = strings.TrimLeft(rest, " \t")
+
+		var value string
+		if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+			quote := rest[0]
+			body := rest[1:]
+
+			// Keep reading lines until the closing quote
+			for {
+				if end := closingQuote(body, quote); end >= 0 {
+					if trailing := strings.TrimSpace(body[end+1:]); trailing != "" && !strings.HasPrefix(trailing, "#") {
+						return nil, fmt.Errorf("line %d: unexpected characters after closing quote", lineNum)
+					}
+					body = body[:end]
+					break
+				}
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("line %d: unterminated quoted value for %s", start, key)
+				}
+				lineNum++
+				body += "\n" + scanner.Text()
+			}
+
+			value = body
+			if quote == '"' {
+				value = unescape(body)
+			}
+		} else {
+			value = stripInlineComment(rest)
+		}
+
+		if i, exists := index[key]; exists {
+			vars[i] = Var{Key: key, Value: value, Line: start}
+			continue
+		}
+		index[key] = len(vars)
+		vars = append(vars, Var{Key: key, Value: value, Line: start})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// closingQuote returns the index of the unescaped closing quote in s, or -1
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if quote == '"' && s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// unescape expands escapes in double quoted values
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(s)
+}
+
+// stripInlineComment removes a " #" comment from an unquoted value
+func stripInlineComment(s string) string {
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// Format renders a single entry, quoting the value when needed so that Parse
+// returns it unchanged
+func Format(key, value string) string {
+	if value == "" || !strings.ContainsAny(value, " \t\n\r\"'#\\$`") {
+		return key + "=" + value
+	}
+	if !strings.ContainsAny(value, "'\r") {
+		return key + "='" + value + "'"
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", `\r`).Replace(value)
+	return key + `="` + escaped + `"`
+}
+
+// Write renders vars to w, one entry per line, after an optional header comment
+func Write(w io.Writer, header string, vars []Var) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range strings.Split(header, "\n") {
+		if line != "" {
+			fmt.Fprintf(bw, "# %s\n", line)
+		}
+	}
+	if header != "" {
+		bw.WriteString("\n")
+	}
+	for _, v := range vars {
+		bw.WriteString(Format(v.Key, v.Value) + "\n")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,98 @@
+package dotenv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `# comment
+export PLAIN=value
+SPACED = hello world # trailing comment
+EMPTY=
+SINGLE='literal $HOME \n'
+DOUBLE="line1\nline2 \"quoted\""
+MULTI="-----BEGIN KEY-----
+abc
+-----END KEY-----"
+PLAIN=override
+`
+	vars, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"PLAIN":  "override",
+		"SPACED": "hello world",
+		"EMPTY":  "",
+		"SINGLE": `literal $HOME \n`,
+		"DOUBLE": "line1\nline2 \"quoted\"",
+		"MULTI":  "-----BEGIN KEY-----\nabc\n-----END KEY-----",
+	}
+	if len(vars) != len(want) {
+		t.Fatalf("got %d vars, want %d: %+v", len(vars), len(want), vars)
+	}
+	for _, v := range vars {
+		if v.Value != want[v.Key] {
+			t.Errorf("%s = %q, want %q", v.Key, v.Value, want[v.Key])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"NOEQUALS",
+		"1BAD=x",
+		`OPEN="never closed`,
+		`JUNK="x" y`,
+	} {
+		if _, err := Parse(strings.NewReader(input)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	vars := []Var{
+		{Key: "A", Value: "simple"},
+		{Key: "B", Value: "with space"},
+		{Key: "C", Value: "it's \"mixed\"\nand \\n multiline"},
+		{Key: "D", Value: "#not-a-comment"},
+		{Key: "E", Value: ""},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "header", vars); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(vars) {
+		t.Fatalf("got %d vars, want %d", len(parsed), len(vars))
+	}
+	for i, v := range parsed {
+		if v.Key != vars[i].Key || v.Value != vars[i].Value {
+			t.Errorf("round trip: got %s=%q, want %s=%q", v.Key, v.Value, vars[i].Key, vars[i].Value)
+		}
+	}
+}
+
+func TestValidKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"DATABASE_URL": true,
+		"_private":     true,
+		"app.name":     true,
+		"1BAD":         false,
+		"HAS SPACE":    false,
+		"":             false,
+	} {
+		if got := ValidKey(key); got != want {
+			t.Errorf("ValidKey(%q) = %t, want %t", key, got, want)
+		}
+	}
+}
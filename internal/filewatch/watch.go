@@ -0,0 +1,201 @@
+// Package filewatch reports batches of changed files in a git working tree,
+// or in any directory.
+package filewatch
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long the tree must be quiet before changes are reported
+const DefaultDebounce = 2 * time.Second
+
+// Watcher watches the non-ignored directories of a git working tree. Outside
+// one, it watches every directory and nothing is ignored.
+type Watcher struct {
+	// Dir is the root of the working tree, or any directory
+	Dir string
+
+	// Debounce is how long to wait after the last change (default DefaultDebounce)
+	Debounce time.Duration
+
+	// Filter reports whether a change to file (slash-separated, relative to
+	// Dir) should be reported. Nil reports every non-ignored file.
+	Filter func(file string) bool
+}
+
+// Run watches until ctx is done, calling onChange with each batch of changed
+// files. Changes made while onChange runs are reported in the next batch.
+func (w *Watcher) Run(ctx context.Context, onChange func(files []string)) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fsw.Close()
+
+	dirs, err := w.watchedDirs()
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := fsw.Add(filepath.Join(w.Dir, filepath.FromSlash(dir))); err != nil {
+			return err
+		}
+	}
+
+	debounce := w.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	pending := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+
+		case ev, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			rel, ok := w.relative(ev.Name)
+			if !ok {
+				continue
+			}
+			if ev.Has(fsnotify.Create) {
+				w.addNewDir(fsw, rel)
+			}
+			pending[rel] = true
+			timer.Reset(debounce)
+
+		case <-timer.C:
+			files, err := w.reportable(pending)
+			if err != nil {
+				return err
+			}
+			pending = make(map[string]bool)
+			if len(files) > 0 {
+				onChange(files)
+			}
+		}
+	}
+}
+
+// watchedDirs returns the directories that contain files git would push, or
+// all of them outside a working tree
+func (w *Watcher) watchedDirs() ([]string, error) {
+	if !git.InWorkTree(w.Dir) {
+		var dirs []string
+		err := filepath.WalkDir(w.Dir, func(p string, d os.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return err
+			}
+			rel, ok := w.relative(p)
+			if !ok {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, rel)
+			return nil
+		})
+		return dirs, err
+	}
+
+	files, err := git.ListPushableFiles(w.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{".": true}
+	for _, f := range files {
+		for dir := path.Dir(f); !seen[dir]; dir = path.Dir(dir) {
+			seen[dir] = true
+		}
+	}
+
+	dirs := make([]string, 0, len(seen))
+	for dir := range seen {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// addNewDir starts watching a directory created after Run started, and any
+// directories already inside it, unless git ignores them
+func (w *Watcher) addNewDir(fsw *fsnotify.Watcher, rel string) {
+	full := filepath.Join(w.Dir, filepath.FromSlash(rel))
+	if info, err := os.Stat(full); err != nil || !info.IsDir() {
+		return
+	}
+
+	_ = filepath.WalkDir(full, func(p string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		r, ok := w.relative(p)
+		if !ok {
+			return filepath.SkipDir
+		}
+		if ignored, err := git.IgnoredFiles(w.Dir, []string{r + "/"}); err == nil && ignored[r+"/"] {
+			return filepath.SkipDir
+		}
+		_ = fsw.Add(p)
+		return nil
+	})
+}
+
+// relative converts an event path to a slash-separated path relative to Dir.
+// Paths inside .git are not reported.
+func (w *Watcher) relative(name string) (string, bool) {
+	rel, err := filepath.Rel(w.Dir, name)
+	if err != nil {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".git" || strings.HasPrefix(rel, ".git/") || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return rel, true
+}
+
+// reportable drops ignored and filtered files from a batch and sorts the rest
+func (w *Watcher) reportable(pending map[string]bool) ([]string, error) {
+	candidates := make([]string, 0, len(pending))
+	for f := range pending {
+		candidates = append(candidates, f)
+	}
+
+	ignored, err := git.IgnoredFiles(w.Dir, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(candidates))
+	for _, f := range candidates {
+		if ignored[f] || (w.Filter != nil && !w.Filter(f)) {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(w.Dir, filepath.FromSlash(f))); err == nil && info.IsDir() {
+			continue
+		}
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files, nil
+}
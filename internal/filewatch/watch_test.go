@@ -0,0 +1,114 @@
+package filewatch
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatcherReportsNonIgnoredChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	for name, content := range map[string]string{
+		".gitignore":     "ignored/\n",
+		"src/main.go":    "package main\n",
+		"ignored/keep":   "x\n",
+		"docs/README.md": "docs\n",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	batches := make(chan []string, 1)
+	w := &Watcher{
+		Dir:      dir,
+		Debounce: 100 * time.Millisecond,
+		Filter:   func(file string) bool { return file != "docs/README.md" },
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx, func(files []string) {
+			batches <- files
+			cancel()
+		})
+	}()
+
+	// Give the watcher time to add its directories
+	time.Sleep(200 * time.Millisecond)
+	for _, name := range []string{"src/main.go", "ignored/keep", "docs/README.md"} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte("changed\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case files := <-batches:
+		if want := []string{"src/main.go"}; !reflect.DeepEqual(files, want) {
+			t.Errorf("got %v, want %v", files, want)
+		}
+	case <-ctx.Done():
+		t.Fatal("no changes reported")
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherOutsideRepository(t *testing.T) {
+	// Without git, and in a directory that isn't a repository
+	t.Setenv("PATH", "")
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	batches := make(chan []string, 1)
+	w := &Watcher{Dir: dir, Debounce: 100 * time.Millisecond}
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx, func(files []string) {
+			batches <- files
+			cancel()
+		})
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	for _, name := range []string{"Dockerfile", "src/main.go"} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte("changed\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case files := <-batches:
+		if want := []string{"Dockerfile", "src/main.go"}; !reflect.DeepEqual(files, want) {
+			t.Errorf("got %v, want %v", files, want)
+		}
+	case <-ctx.Done():
+		t.Fatal("no changes reported")
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,145 @@
+// Package fuzzy matches what is typed into a picker against its options: the
+// letters of the query have to appear in order, and matches that start words
+// or run together rank first.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Scores of a match. Each matched letter scores, more when it follows the
+// last one or starts a word, and letters skipped in between cost a little.
+const (
+	scoreMatch       = 16
+	bonusConsecutive = 12
+	bonusWordStart   = 8
+	bonusFirstLetter = 6
+	penaltyGap       = 1
+	maxGapPenalty    = 12
+)
+
+// Result is an option that matches a query
+type Result struct {
+	Index     int   // of the option in the list given to Filter
+	Score     int   // higher is better
+	Positions []int // of the matched runes in the option, sorted
+}
+
+// Filter returns the options that match query, best first. Options that
+// score the same stay in the order they were given, so a list ordered by
+// how recently each option was used keeps that order among equal matches.
+// An empty query matches every option.
+func Filter(query string, options []string) []Result {
+	results := make([]Result, 0, len(options))
+	for i, option := range options {
+		if score, positions, ok := Match(query, option); ok {
+			results = append(results, Result{Index: i, Score: score, Positions: positions})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// Match reports whether text matches query, ignoring case. Words of the
+// query separated by spaces each have to match, in any order.
+func Match(query, text string) (score int, positions []int, ok bool) {
+	original := []rune(text)
+	runes := make([]rune, len(original))
+	for i, r := range original {
+		runes[i] = unicode.ToLower(r)
+	}
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		s, p, ok := matchTerm([]rune(term), runes, original)
+		if !ok {
+			return 0, nil, false
+		}
+		score += s
+		positions = append(positions, p...)
+	}
+	sort.Ints(positions)
+	return score, dedupe(positions), true
+}
+
+// matchTerm finds term in text as a subsequence. The first match found from
+// the left is tightened from its end back, so "ab" in "a-xab" matches the
+// last two letters rather than spreading over the whole text.
+func matchTerm(term, text, original []rune) (int, []int, bool) {
+	if len(term) == 0 {
+		return 0, nil, true
+	}
+
+	// Forward: where the first complete match ends
+	t := 0
+	end := -1
+	for i, r := range text {
+		if r == term[t] {
+			t++
+			if t == len(term) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	// Backward: the latest start that still matches before end
+	positions := make([]int, len(term))
+	t = len(term) - 1
+	for i := end; i >= 0 && t >= 0; i-- {
+		if text[i] == term[t] {
+			positions[t] = i
+			t--
+		}
+	}
+
+	score := 0
+	for n, pos := range positions {
+		score += scoreMatch
+		if wordStart(original, pos) {
+			score += bonusWordStart
+		}
+		if pos == 0 {
+			score += bonusFirstLetter
+		}
+		if n > 0 {
+			if gap := pos - positions[n-1] - 1; gap == 0 {
+				score += bonusConsecutive
+			} else {
+				score -= min(gap*penaltyGap, maxGapPenalty)
+			}
+		}
+	}
+	return score, positions, true
+}
+
+// wordStart reports whether the rune at i starts a word: the start of the
+// text, the rune after a separator, or a capital after a lower-case letter
+func wordStart(text []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := text[i-1], text[i]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+func dedupe(sorted []int) []int {
+	if len(sorted) < 2 {
+		return sorted
+	}
+	out := sorted[:1]
+	for _, n := range sorted[1:] {
+		if n != out[len(out)-1] {
+			out = append(out, n)
+		}
+	}
+	return out
+}
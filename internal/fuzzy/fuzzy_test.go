@@ -0,0 +1,61 @@
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		query, text string
+		ok          bool
+		positions   []int
+	}{
+		{"", "anything", true, nil},
+		{"web", "marketing-web", true, []int{10, 11, 12}},
+		{"mw", "marketing-web", true, []int{0, 10}},
+		{"WEB", "marketing-web", true, []int{10, 11, 12}},
+		{"bew", "marketing-web", false, nil},
+		{"ab", "a-xab", true, []int{3, 4}},
+		{"web prod", "production web", true, []int{0, 1, 2, 3, 11, 12, 13}},
+		{"web staging", "production web", false, nil},
+	} {
+		_, positions, ok := Match(tc.query, tc.text)
+		if ok != tc.ok || !reflect.DeepEqual(positions, tc.positions) {
+			t.Errorf("Match(%q, %q) = %v, %t, want %v, %t", tc.query, tc.text, positions, ok, tc.positions, tc.ok)
+		}
+	}
+}
+
+func TestFilterRanksWordStartsFirst(t *testing.T) {
+	options := []string{"api-gateway", "staging-api", "rapid"}
+	var got []string
+	for _, r := range Filter("api", options) {
+		got = append(got, options[r.Index])
+	}
+	want := []string{"api-gateway", "staging-api", "rapid"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter = %v, want %v", got, want)
+	}
+
+	// Scattered letters rank below a run of them
+	options = []string{"a-p-i worker", "my api"}
+	if r := Filter("api", options); len(r) != 2 || options[r[0].Index] != "my api" {
+		t.Errorf("Filter = %+v", r)
+	}
+}
+
+func TestFilterKeepsOrderOfEqualMatches(t *testing.T) {
+	// As given, most recently used first
+	options := []string{"shop-web", "blog-web", "docs-web"}
+	var got []int
+	for _, r := range Filter("web", options) {
+		got = append(got, r.Index)
+	}
+	if !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("Filter order = %v", got)
+	}
+	if r := Filter("", options); len(r) != 3 || r[2].Index != 2 {
+		t.Errorf("empty query = %+v", r)
+	}
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BitbucketAPI is the Bitbucket Cloud API
+const BitbucketAPI = "https://api.bitbucket.org/2.0"
+
+// BitbucketClient is a simple Bitbucket Cloud API client, logged in with a
+// username and app password
+type BitbucketClient struct {
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewBitbucketClient creates a new Bitbucket client
+func NewBitbucketClient(username, password string) *BitbucketClient {
+	return &BitbucketClient{
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// BitbucketUser represents a Bitbucket user
+type BitbucketUser struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+}
+
+// BitbucketRepo represents a Bitbucket repository
+type BitbucketRepo struct {
+	Name      string `json:"name"`
+	FullName  string `json:"full_name"`
+	IsPrivate bool   `json:"is_private"`
+	Links     struct {
+		Clone []struct {
+			Name string `json:"name"`
+			Href string `json:"href"`
+		} `json:"clone"`
+	} `json:"links"`
+}
+
+// CloneURL returns the repository's clone URL over proto, "https" or "ssh"
+func (r *BitbucketRepo) CloneURL(proto string) string {
+	for _, link := range r.Links.Clone {
+		if link.Name == proto {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// GetUser returns the authenticated user
+func (c *BitbucketClient) GetUser() (*BitbucketUser, error) {
+	var user BitbucketUser
+	err := c.request("GET", "/user", nil, &user)
+	return &user, err
+}
+
+// GetRepo gets a repository by workspace and slug
+func (c *BitbucketClient) GetRepo(workspace, slug string) (*BitbucketRepo, error) {
+	var repo BitbucketRepo
+	err := c.request("GET", bitbucketRepoPath(workspace, slug), nil, &repo)
+	return &repo, err
+}
+
+// CreateRepo creates a repository in workspace
+func (c *BitbucketClient) CreateRepo(workspace, slug, description string, private bool) (*BitbucketRepo, error) {
+	req := map[string]interface{}{
+		"scm":         "git",
+		"description": description,
+		"is_private":  private,
+	}
+	var repo BitbucketRepo
+	err := c.request("POST", bitbucketRepoPath(workspace, slug), req, &repo)
+	return &repo, err
+}
+
+// AddDeployKey lets the holder of the private half of key clone the
+// repository; Bitbucket deploy keys are always read-only
+func (c *BitbucketClient) AddDeployKey(workspace, slug, label, key string) error {
+	req := map[string]interface{}{
+		"label": label,
+		"key":   key,
+	}
+	return c.request("POST", bitbucketRepoPath(workspace, slug)+"/deploy-keys", req, nil)
+}
+
+// AddPushWebhook calls hookURL on every push to the repository, signed with
+// secret in the X-Hub-Signature header
+func (c *BitbucketClient) AddPushWebhook(workspace, slug, hookURL, secret string) error {
+	req := map[string]interface{}{
+		"description": "Coolify",
+		"url":         hookURL,
+		"active":      true,
+		"secret":      secret,
+		"events":      []string{"repo:push"},
+	}
+	return c.request("POST", bitbucketRepoPath(workspace, slug)+"/hooks", req, nil)
+}
+
+// WebhookURLs returns the URLs the repository's webhooks call, from the
+// first page of them
+func (c *BitbucketClient) WebhookURLs(workspace, slug string) ([]string, error) {
+	var page struct {
+		Values []struct {
+			URL string `json:"url"`
+		} `json:"values"`
+	}
+	if err := c.request("GET", bitbucketRepoPath(workspace, slug)+"/hooks?pagelen=100", nil, &page); err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(page.Values))
+	for i, h := range page.Values {
+		urls[i] = h.URL
+	}
+	return urls, nil
+}
+
+func (c *BitbucketClient) request(method, path string, body interface{}, result interface{}) error {
+	login := base64.StdEncoding.EncodeToString([]byte(c.username + ":" + c.password))
+	auth := http.Header{"Authorization": {"Basic " + login}}
+	return hostRequest(c.httpClient, "Bitbucket", method, BitbucketAPI+path, auth, body, result)
+}
+
+// bitbucketRepoPath returns the API path of a repository; slugs are
+// lowercase names
+func bitbucketRepoPath(workspace, slug string) string {
+	return fmt.Sprintf("/repositories/%s/%s", workspace, strings.ToLower(slug))
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// shell runs the operations go-git implements with the git CLI, see SetShell
+var shell bool
+
+// SetShell runs init, add, commit, branch detection, remotes, pushes,
+// commit history, diffs and ignore checks with the git CLI instead of
+// go-git, for repositories or git config that go-git doesn't handle
+func SetShell(on bool) {
+	shell = on
+}
+
+// Check returns an error that says how to get git when the CLI is used for
+// the core operations and isn't installed. go-git needs no git.
+func Check() error {
+	if !shell {
+		return nil
+	}
+	return CheckCLI()
+}
+
+// CheckCLI returns an error that says how to get git when it isn't
+// installed, for the operations only the CLI implements
+func CheckCLI() error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return errors.New("git is not installed: get it from https://git-scm.com/downloads and try again")
+	}
+	return nil
+}
+
+// command returns git with args, run in dir. Prompts are off, so a missing
+// login fails instead of waiting for input behind a spinner.
+func command(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return cmd
+}
+
+// The author of our automatic commits when git has none, as on a fresh machine
+const (
+	fallbackName  = "cool-kit"
+	fallbackEmail = "cool-kit@localhost"
+)
+
+// commitArgs returns the config our automatic commits need whatever the
+// user's git config says: no signing, which can prompt for a passphrase, and
+// an author when none is set
+func commitArgs(dir string) []string {
+	args := []string{"-c", "commit.gpgsign=false"}
+	if out, err := command(dir, "config", "user.email").Output(); err != nil || strings.TrimSpace(string(out)) == "" {
+		args = append(args, "-c", "user.name="+fallbackName, "-c", "user.email="+fallbackEmail)
+	}
+	return args
+}
+
+// pushArgs returns a push with args that keeps credential helpers from
+// saving the token pushes put in the remote URL
+func pushArgs(args ...string) []string {
+	return append([]string{"-c", "credential.helper=", "push"}, args...)
+}
@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -73,7 +72,7 @@ func (m *Manager) cloneFresh(workDir, repoURL, branch string) error {
 	}
 
 	// Use git CLI - much more reliable than go-git
-	cmd := exec.Command("git", "clone",
+	cmd := command("", "clone",
 		"--depth", "1",
 		"--branch", branch,
 		"--single-branch",
@@ -97,8 +96,7 @@ func (m *Manager) cloneFresh(workDir, repoURL, branch string) error {
 // pullLatest pulls the latest changes
 func (m *Manager) pullLatest(workDir, branch string) error {
 	// Try to pull
-	cmd := exec.Command("git", "pull", "--ff-only", "origin", branch)
-	cmd.Dir = workDir
+	cmd := command(workDir, "pull", "--ff-only", "origin", branch)
 
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
@@ -122,8 +120,7 @@ func (m *Manager) GetLatestCommitInfo() (*CommitInfo, error) {
 	}
 
 	// Get commit hash
-	hashCmd := exec.Command("git", "rev-parse", "HEAD")
-	hashCmd.Dir = absWorkDir
+	hashCmd := command(absWorkDir, "rev-parse", "HEAD")
 	hashOutput, err := hashCmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit hash: %w", err)
@@ -131,8 +128,7 @@ func (m *Manager) GetLatestCommitInfo() (*CommitInfo, error) {
 	hash := strings.TrimSpace(string(hashOutput))
 
 	// Get commit message
-	msgCmd := exec.Command("git", "log", "-1", "--format=%s")
-	msgCmd.Dir = absWorkDir
+	msgCmd := command(absWorkDir, "log", "-1", "--format=%s")
 	msgOutput, err := msgCmd.Output()
 	var message string
 	if err == nil {
@@ -140,8 +136,7 @@ func (m *Manager) GetLatestCommitInfo() (*CommitInfo, error) {
 	}
 
 	// Get author
-	authorCmd := exec.Command("git", "log", "-1", "--format=%an")
-	authorCmd.Dir = absWorkDir
+	authorCmd := command(absWorkDir, "log", "-1", "--format=%an")
 	authorOutput, err := authorCmd.Output()
 	var author string
 	if err == nil {
@@ -0,0 +1,125 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GiteaClient is a simple client for the API of a Gitea or Forgejo instance,
+// which share it
+type GiteaClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewGiteaClient creates a new Gitea client for the instance at baseURL
+func NewGiteaClient(baseURL, token string) *GiteaClient {
+	return &GiteaClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GiteaUser represents a Gitea user
+type GiteaUser struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+}
+
+// GiteaRepo represents a Gitea repository
+type GiteaRepo struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	CloneURL string `json:"clone_url"`
+	SSHURL   string `json:"ssh_url"`
+	HTMLURL  string `json:"html_url"`
+	Private  bool   `json:"private"`
+}
+
+// GetUser returns the authenticated user
+func (c *GiteaClient) GetUser() (*GiteaUser, error) {
+	var user GiteaUser
+	err := c.request("GET", "/user", nil, &user)
+	return &user, err
+}
+
+// GetRepo gets a repository by owner and name
+func (c *GiteaClient) GetRepo(owner, name string) (*GiteaRepo, error) {
+	var repo GiteaRepo
+	err := c.request("GET", repoPath(owner, name), nil, &repo)
+	return &repo, err
+}
+
+// CreateRepo creates a repository for the authenticated user
+func (c *GiteaClient) CreateRepo(name, description string, private bool) (*GiteaRepo, error) {
+	req := map[string]interface{}{
+		"name":        name,
+		"description": description,
+		"private":     private,
+	}
+	var repo GiteaRepo
+	err := c.request("POST", "/user/repos", req, &repo)
+	return &repo, err
+}
+
+// AddDeployKey lets the holder of the private half of key clone the
+// repository, read-only
+func (c *GiteaClient) AddDeployKey(owner, name, title, key string) error {
+	req := map[string]interface{}{
+		"title":     title,
+		"key":       key,
+		"read_only": true,
+	}
+	return c.request("POST", repoPath(owner, name)+"/keys", req, nil)
+}
+
+// AddPushWebhook calls hookURL on every push to the repository, signed with
+// secret in the X-Hub-Signature-256 header
+func (c *GiteaClient) AddPushWebhook(owner, name, hookURL, secret string) error {
+	req := map[string]interface{}{
+		"type":   "gitea",
+		"active": true,
+		"events": []string{"push"},
+		"config": map[string]string{
+			"url":          hookURL,
+			"content_type": "json",
+			"secret":       secret,
+		},
+	}
+	return c.request("POST", repoPath(owner, name)+"/hooks", req, nil)
+}
+
+// WebhookURLs returns the URLs the repository's webhooks call, from the
+// first page of them
+func (c *GiteaClient) WebhookURLs(owner, name string) ([]string, error) {
+	var hooks []struct {
+		Config struct {
+			URL string `json:"url"`
+		} `json:"config"`
+	}
+	if err := c.request("GET", repoPath(owner, name)+"/hooks?limit=50", nil, &hooks); err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(hooks))
+	for i, h := range hooks {
+		urls[i] = h.Config.URL
+	}
+	return urls, nil
+}
+
+func (c *GiteaClient) request(method, path string, body interface{}, result interface{}) error {
+	auth := http.Header{"Authorization": {"token " + c.token}}
+	return hostRequest(c.httpClient, "Gitea", method, c.baseURL+"/api/v1"+path, auth, body, result)
+}
+
+func repoPath(owner, name string) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(name))
+}
@@ -86,6 +86,140 @@ func (c *GitHubClient) RepoExists(owner, name string) bool {
 	return err == nil
 }
 
+// AddDeployKey lets the holder of the private half of key clone the
+// repository, read-only
+func (c *GitHubClient) AddDeployKey(owner, name, title, key string) error {
+	req := map[string]interface{}{
+		"title":     title,
+		"key":       key,
+		"read_only": true,
+	}
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/keys", owner, name)
+	return c.request("POST", url, req, nil)
+}
+
+// AddPushWebhook calls hookURL on every push to the repository, signed with
+// secret in the X-Hub-Signature-256 header
+func (c *GitHubClient) AddPushWebhook(owner, name, hookURL, secret string) error {
+	req := map[string]interface{}{
+		"name":   "web",
+		"active": true,
+		"events": []string{"push", "pull_request"},
+		"config": map[string]string{
+			"url":          hookURL,
+			"content_type": "json",
+			"secret":       secret,
+		},
+	}
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/hooks", owner, name)
+	return c.request("POST", url, req, nil)
+}
+
+// WebhookURLs returns the URLs the repository's webhooks call
+func (c *GitHubClient) WebhookURLs(owner, name string) ([]string, error) {
+	var hooks []struct {
+		Config struct {
+			URL string `json:"url"`
+		} `json:"config"`
+	}
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/hooks?per_page=100", owner, name)
+	if err := c.request("GET", url, nil, &hooks); err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(hooks))
+	for i, h := range hooks {
+		urls[i] = h.Config.URL
+	}
+	return urls, nil
+}
+
+// DeploymentRequest is the request body for creating a GitHub deployment
+type DeploymentRequest struct {
+	Ref         string `json:"ref"`
+	Environment string `json:"environment"`
+	Description string `json:"description,omitempty"`
+	// Transient environments, like pull request previews, go away later
+	Transient  bool `json:"transient_environment"`
+	Production bool `json:"production_environment"`
+	AutoMerge  bool `json:"auto_merge"`
+	// RequiredContexts is empty so checks still running don't block it
+	RequiredContexts []string `json:"required_contexts"`
+}
+
+// Deployment represents a GitHub deployment
+type Deployment struct {
+	ID int64 `json:"id"`
+}
+
+// CreateDeployment records a deployment of req.Ref, shown on the commit,
+// its branch and its pull requests
+func (c *GitHubClient) CreateDeployment(owner, name string, req *DeploymentRequest) (*Deployment, error) {
+	if req.RequiredContexts == nil {
+		req.RequiredContexts = []string{}
+	}
+	var deployment Deployment
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/deployments", owner, name)
+	err := c.request("POST", url, req, &deployment)
+	return &deployment, err
+}
+
+// CreateDeploymentStatus sets a deployment's state: queued, in_progress,
+// success, failure or error. environmentURL is where it can be seen.
+func (c *GitHubClient) CreateDeploymentStatus(owner, name string, id int64, state, environmentURL, description string) error {
+	req := map[string]interface{}{
+		"state":       state,
+		"description": description,
+	}
+	if environmentURL != "" {
+		req["environment_url"] = environmentURL
+	}
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/deployments/%d/statuses", owner, name, id)
+	return c.request("POST", url, req, nil)
+}
+
+// CreateCommitStatus sets the status named context on commit sha: pending,
+// success, failure or error
+func (c *GitHubClient) CreateCommitStatus(owner, name, sha, state, targetURL, description, context string) error {
+	req := map[string]interface{}{
+		"state":       state,
+		"description": description,
+		"context":     context,
+	}
+	if targetURL != "" {
+		req["target_url"] = targetURL
+	}
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/statuses/%s", owner, name, sha)
+	return c.request("POST", url, req, nil)
+}
+
+// IssueComment represents a comment on an issue or pull request
+type IssueComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// ListIssueComments returns the first comments on an issue or pull request
+func (c *GitHubClient) ListIssueComments(owner, name string, number int) ([]IssueComment, error) {
+	var comments []IssueComment
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/comments?per_page=100", owner, name, number)
+	err := c.request("GET", url, nil, &comments)
+	return comments, err
+}
+
+// CreateIssueComment comments on an issue or pull request
+func (c *GitHubClient) CreateIssueComment(owner, name string, number int, body string) (*IssueComment, error) {
+	var comment IssueComment
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/comments", owner, name, number)
+	err := c.request("POST", url, map[string]string{"body": body}, &comment)
+	return &comment, err
+}
+
+// UpdateIssueComment replaces the body of a comment
+func (c *GitHubClient) UpdateIssueComment(owner, name string, id int64, body string) error {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/comments/%d", owner, name, id)
+	return c.request("PATCH", url, map[string]string{"body": body}, nil)
+}
+
 // DeleteRepo deletes a repository
 func (c *GitHubClient) DeleteRepo(owner, name string) error {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, name)
@@ -148,7 +282,7 @@ func (c *GitHubClient) request(method, url string, body interface{}, result inte
 	}
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(respBody))
+		return &HostError{Host: "GitHub", StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	if result != nil && len(respBody) > 0 {
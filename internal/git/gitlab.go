@@ -0,0 +1,120 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitLabClient is a simple GitLab API client, for gitlab.com or a
+// self-hosted instance
+type GitLabClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewGitLabClient creates a new GitLab client for the instance at baseURL
+func NewGitLabClient(baseURL, token string) *GitLabClient {
+	return &GitLabClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GitLabUser represents a GitLab user
+type GitLabUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+// GitLabProject represents a GitLab project, GitLab's name for a repository
+type GitLabProject struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	HTTPURLToRepo     string `json:"http_url_to_repo"`
+	SSHURLToRepo      string `json:"ssh_url_to_repo"`
+	WebURL            string `json:"web_url"`
+	Visibility        string `json:"visibility"`
+}
+
+// GetUser returns the authenticated user
+func (c *GitLabClient) GetUser() (*GitLabUser, error) {
+	var user GitLabUser
+	err := c.request("GET", "/user", nil, &user)
+	return &user, err
+}
+
+// GetProject gets a project by its path, like group/name
+func (c *GitLabClient) GetProject(path string) (*GitLabProject, error) {
+	var project GitLabProject
+	err := c.request("GET", "/projects/"+url.PathEscape(path), nil, &project)
+	return &project, err
+}
+
+// CreateProject creates a project in the user's namespace
+func (c *GitLabClient) CreateProject(name, description string, private bool) (*GitLabProject, error) {
+	visibility := "public"
+	if private {
+		visibility = "private"
+	}
+	req := map[string]interface{}{
+		"name":        name,
+		"path":        name,
+		"description": description,
+		"visibility":  visibility,
+	}
+	var project GitLabProject
+	err := c.request("POST", "/projects", req, &project)
+	return &project, err
+}
+
+// AddDeployKey lets the holder of the private half of key clone the
+// project, read-only
+func (c *GitLabClient) AddDeployKey(projectID int, title, key string) error {
+	req := map[string]interface{}{
+		"title":    title,
+		"key":      key,
+		"can_push": false,
+	}
+	return c.request("POST", fmt.Sprintf("/projects/%d/deploy_keys", projectID), req, nil)
+}
+
+// AddPushWebhook calls hookURL on every push to the project, with secret
+// in the X-Gitlab-Token header
+func (c *GitLabClient) AddPushWebhook(projectID int, hookURL, secret string) error {
+	req := map[string]interface{}{
+		"url":                     hookURL,
+		"token":                   secret,
+		"push_events":             true,
+		"merge_requests_events":   true,
+		"enable_ssl_verification": strings.HasPrefix(hookURL, "https://"),
+	}
+	return c.request("POST", fmt.Sprintf("/projects/%d/hooks", projectID), req, nil)
+}
+
+// WebhookURLs returns the URLs the project's webhooks call
+func (c *GitLabClient) WebhookURLs(projectID int) ([]string, error) {
+	var hooks []struct {
+		URL string `json:"url"`
+	}
+	if err := c.request("GET", fmt.Sprintf("/projects/%d/hooks", projectID), nil, &hooks); err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(hooks))
+	for i, h := range hooks {
+		urls[i] = h.URL
+	}
+	return urls, nil
+}
+
+func (c *GitLabClient) request(method, path string, body interface{}, result interface{}) error {
+	auth := http.Header{"Private-Token": {c.token}}
+	return hostRequest(c.httpClient, "GitLab", method, c.baseURL+"/api/v4"+path, auth, body, result)
+}
@@ -0,0 +1,474 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/ui"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// The operations below are those of repo.go, run with go-git so deploys
+// work without git installed; SetShell runs the git CLI instead.
+
+// openRepo opens the repository dir is in
+func openRepo(dir string) (*gogit.Repository, error) {
+	return gogit.PlainOpenWithOptions(dir, &gogit.PlainOpenOptions{DetectDotGit: true})
+}
+
+// openWorktree opens the working tree dir is in. Like the CLI, it ignores
+// what the system and global excludes files ignore, besides .gitignore.
+func openWorktree(dir string) (*gogit.Repository, *gogit.Worktree, error) {
+	repo, err := openRepo(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := osfs.New("/")
+	for _, load := range []func(billy.Filesystem) ([]gitignore.Pattern, error){gitignore.LoadSystemPatterns, gitignore.LoadGlobalPatterns} {
+		if patterns, err := load(root); err == nil {
+			wt.Excludes = append(wt.Excludes, patterns...)
+		}
+	}
+	if patterns, err := gitignore.ReadPatterns(wt.Filesystem, nil); err == nil {
+		wt.Excludes = append(wt.Excludes, patterns...)
+	}
+	return repo, wt, nil
+}
+
+// goInit creates a repository in dir on the branch init.defaultBranch names
+func goInit(dir string) error {
+	opts := &gogit.PlainInitOptions{}
+	if cfg, err := gitconfig.LoadConfig(gitconfig.GlobalScope); err == nil && cfg.Init.DefaultBranch != "" {
+		opts.InitOptions.DefaultBranch = plumbing.NewBranchReferenceName(cfg.Init.DefaultBranch)
+	}
+	_, err := gogit.PlainInitWithOptions(dir, opts)
+	return err
+}
+
+func goRemoteURL(dir, remoteName string) (string, error) {
+	repo, err := openRepo(dir)
+	if err != nil {
+		return "", err
+	}
+	remote, err := repo.Remote(remoteName)
+	if err != nil {
+		return "", err
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", fmt.Errorf("remote %s has no URL", remoteName)
+	}
+	return urls[0], nil
+}
+
+func goSetRemote(dir, remoteName, url string) error {
+	repo, err := openRepo(dir)
+	if err != nil {
+		return err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	if remote, ok := cfg.Remotes[remoteName]; ok {
+		remote.URLs = []string{url}
+		return repo.SetConfig(cfg)
+	}
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: remoteName, URLs: []string{url}})
+	return err
+}
+
+// goCurrentBranch returns the branch HEAD is on, also before its first
+// commit, or "" when HEAD is detached
+func goCurrentBranch(dir string) (string, error) {
+	repo, err := openRepo(dir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return "", err
+	}
+	if head.Type() != plumbing.SymbolicReference {
+		return "", nil
+	}
+	return head.Target().Short(), nil
+}
+
+func goHasChanges(dir string) (bool, error) {
+	_, wt, err := openWorktree(dir)
+	if err != nil {
+		return false, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return false, err
+	}
+	return !status.IsClean(), nil
+}
+
+// goPushableFiles returns the files in the index and the untracked,
+// non-ignored ones under dir, relative to it like 'git ls-files'
+func goPushableFiles(dir string) ([]string, error) {
+	repo, wt, err := openWorktree(dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := worktreePrefix(wt, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	add := func(name string) {
+		name, ok := strings.CutPrefix(name, prefix)
+		if ok && !seen[name] {
+			seen[name] = true
+			files = append(files, name)
+		}
+	}
+	for _, e := range idx.Entries {
+		add(e.Name)
+	}
+	for name, s := range status {
+		if s.Worktree == gogit.Untracked {
+			add(name)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// worktreePrefix returns the path of dir in the working tree, with a
+// trailing slash, or "" at its root
+func worktreePrefix(wt *gogit.Worktree, dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	// Resolve links like macOS's /tmp on both sides
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	root := wt.Filesystem.Root()
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == "." {
+		return "", err
+	}
+	return filepath.ToSlash(rel) + "/", nil
+}
+
+func goAddAll(dir string) error {
+	_, wt, err := openWorktree(dir)
+	if err != nil {
+		return err
+	}
+	return wt.AddWithOptions(&gogit.AddOptions{All: true})
+}
+
+// goCommit commits the index. Like commitArgs, it never signs, and it
+// falls back to the cool-kit author when git has none configured.
+func goCommit(dir, message string, verbose bool) error {
+	repo, wt, err := openWorktree(dir)
+	if err != nil {
+		return err
+	}
+
+	opts := &gogit.CommitOptions{}
+	if cfg, err := repo.ConfigScoped(gitconfig.SystemScope); err != nil || cfg.User.Email == "" || cfg.User.Name == "" {
+		opts.Author = &object.Signature{Name: fallbackName, Email: fallbackEmail, When: time.Now()}
+	}
+	hash, err := wt.Commit(message, opts)
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		branch, _ := goCurrentBranch(dir)
+		fmt.Println(ui.DimStyle.Render(fmt.Sprintf("  [%s %s] %s", branch, hash.String()[:7], firstLine(message))))
+	}
+	return nil
+}
+
+// goPush pushes refspec to remoteName with auth, nil for none. A branch of
+// its own is pushed to the branch of the same name and tracks it, like
+// 'git push -u'.
+func goPush(dir, remoteName, refspec string, auth transport.AuthMethod, verbose bool) error {
+	repo, err := openRepo(dir)
+	if err != nil {
+		return err
+	}
+
+	spec := gitconfig.RefSpec(refspec)
+	branch := ""
+	if !strings.Contains(refspec, ":") {
+		branch = refspec
+		ref := plumbing.NewBranchReferenceName(branch)
+		spec = gitconfig.RefSpec(fmt.Sprintf("%s:%s", ref, ref))
+	}
+
+	opts := &gogit.PushOptions{
+		RemoteName: remoteName,
+		RefSpecs:   []gitconfig.RefSpec{spec},
+		Auth:       auth,
+	}
+	if verbose {
+		progress := &dimWriter{}
+		defer progress.Flush()
+		opts.Progress = progress
+	}
+	if err := repo.Push(opts); err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return err
+	}
+
+	if branch == "" {
+		return nil
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	cfg.Branches[branch] = &gitconfig.Branch{
+		Name:   branch,
+		Remote: remoteName,
+		Merge:  plumbing.NewBranchReferenceName(branch),
+	}
+	return repo.SetConfig(cfg)
+}
+
+// goChangedFiles returns the files that differ between rev and the working
+// tree: those changed in the commits since rev, and the staged, unstaged and
+// untracked (non-ignored) ones, relative to the root like 'git diff'
+func goChangedFiles(dir, rev string) ([]string, error) {
+	repo, wt, err := openWorktree(dir)
+	if err != nil {
+		return nil, err
+	}
+	base, err := goResolveCommit(repo, rev)
+	if err != nil {
+		return nil, err
+	}
+	head, err := goResolveCommit(repo, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	baseTree, err := base.Tree()
+	if err != nil {
+		return nil, err
+	}
+	headTree, err := head.Tree()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(baseTree, headTree)
+	if err != nil {
+		return nil, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			files = append(files, name)
+		}
+	}
+	for _, c := range changes {
+		add(c.From.Name)
+		add(c.To.Name)
+	}
+	for name, s := range status {
+		if s.Staging != gogit.Unmodified || s.Worktree != gogit.Unmodified {
+			add(name)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// goIgnoredFiles matches files (relative to dir, with a trailing slash for
+// directories) against the excludes. Like 'git check-ignore', tracked files
+// are never ignored.
+func goIgnoredFiles(dir string, files []string) (map[string]bool, error) {
+	repo, wt, err := openWorktree(dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := worktreePrefix(wt, dir)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	tracked := make(map[string]bool, len(idx.Entries))
+	for _, e := range idx.Entries {
+		tracked[e.Name] = true
+	}
+
+	matcher := gitignore.NewMatcher(wt.Excludes)
+	ignored := make(map[string]bool)
+	for _, f := range files {
+		name := prefix + strings.TrimSuffix(filepath.ToSlash(f), "/")
+		if !tracked[name] && matcher.Match(strings.Split(name, "/"), strings.HasSuffix(f, "/")) {
+			ignored[f] = true
+		}
+	}
+	return ignored, nil
+}
+
+// goResolveCommit returns the commit rev names, like HEAD, a branch or a
+// (short) hash
+func goResolveCommit(repo *gogit.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("commit %s not found: %w", rev, err)
+	}
+	return repo.CommitObject(*hash)
+}
+
+// goCommitInfo returns the commit rev names
+func goCommitInfo(dir, rev string) (*CommitInfo, error) {
+	repo, err := openRepo(dir)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := goResolveCommit(repo, rev)
+	if err != nil {
+		return nil, err
+	}
+	info := commitInfo(commit)
+	return &info, nil
+}
+
+// goLogRange returns the commits reachable from to but not from, newest
+// first like 'git log from..to'
+func goLogRange(dir, from, to string) ([]CommitInfo, error) {
+	repo, err := openRepo(dir)
+	if err != nil {
+		return nil, err
+	}
+	fromCommit, err := goResolveCommit(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	toCommit, err := goResolveCommit(repo, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Everything from has is left out
+	hidden := make(map[plumbing.Hash]bool)
+	err = object.NewCommitPreorderIter(fromCommit, nil, nil).ForEach(func(c *object.Commit) error {
+		hidden[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []CommitInfo
+	err = object.NewCommitIterCTime(toCommit, hidden, nil).ForEach(func(c *object.Commit) error {
+		commits = append(commits, commitInfo(c))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+func commitInfo(c *object.Commit) CommitInfo {
+	hash := c.Hash.String()
+	return CommitInfo{
+		Hash:      hash,
+		ShortHash: hash[:7],
+		Author:    c.Author.Name,
+		Email:     c.Author.Email,
+		Date:      c.Author.When,
+		Message:   strings.TrimSpace(firstLine(c.Message)),
+	}
+}
+
+// basicAuth logs in to an https remote as username with password
+func basicAuth(username, password string) transport.AuthMethod {
+	return &http.BasicAuth{Username: username, Password: password}
+}
+
+// dimWriter prints the lines written to it with dim styling like
+// deployment logs. Progress redrawn with carriage returns shows as its
+// latest state.
+type dimWriter struct {
+	buf []byte
+}
+
+func (w *dimWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.print(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush prints what's left after the last newline
+func (w *dimWriter) Flush() {
+	w.print(string(w.buf))
+	w.buf = nil
+}
+
+func (w *dimWriter) print(line string) {
+	if i := strings.LastIndexByte(strings.TrimRight(line, "\r"), '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	// Only print non-empty lines
+	if line = strings.TrimSpace(line); line != "" {
+		fmt.Println(ui.DimStyle.Render("  " + line))
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
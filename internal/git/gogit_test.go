@@ -0,0 +1,204 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+)
+
+// withoutGit runs the test without the git CLI or the user's git config
+func withoutGit(t *testing.T) {
+	t.Helper()
+	t.Setenv("PATH", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	saved := shell
+	t.Cleanup(func() { shell = saved })
+	shell = false
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCommitAndPushWithoutGit(t *testing.T) {
+	withoutGit(t)
+	dir := t.TempDir()
+	if err := Check(); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	if err := Init(dir); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if branch, err := GetCurrentBranch(dir); err != nil || branch != "master" {
+		t.Errorf("GetCurrentBranch = %q, %v, want master before the first commit", branch, err)
+	}
+
+	writeFile(t, filepath.Join(dir, ".gitignore"), "*.lock\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	writeFile(t, filepath.Join(dir, "app", "config.json.lock"), "")
+	if !HasChanges(dir) {
+		t.Fatal("HasChanges is false with untracked files")
+	}
+	files, err := ListPushableFiles(dir)
+	if err != nil || !reflect.DeepEqual(files, []string{".gitignore", "main.go"}) {
+		t.Errorf("ListPushableFiles = %v, %v", files, err)
+	}
+	if err := AutoCommit(dir); err != nil {
+		t.Fatalf("AutoCommit: %v", err)
+	}
+	if HasChanges(dir) {
+		t.Error("HasChanges after committing")
+	}
+
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Author.Email != fallbackEmail || commit.Message != "Deploy via cdp" {
+		t.Errorf("commit by %s: %q", commit.Author.Email, commit.Message)
+	}
+	tree, _ := commit.Tree()
+	if _, err := tree.File("app/config.json.lock"); err == nil {
+		t.Error("ignored file committed")
+	}
+
+	// Local remotes run git-receive-pack unless served in-process
+	client.InstallProtocol("file", server.NewClient(server.DefaultLoader))
+	remote := t.TempDir()
+	if _, err := gogit.PlainInit(remote, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetRemote(dir, "origin", "https://example.com/a.git"); err != nil {
+		t.Fatalf("SetRemote: %v", err)
+	}
+	if err := SetRemote(dir, "origin", remote); err != nil {
+		t.Fatalf("SetRemote again: %v", err)
+	}
+	if url, err := GetRemoteURL(dir, "origin"); err != nil || url != remote {
+		t.Errorf("GetRemoteURL = %q, %v", url, err)
+	}
+	if err := Push(dir, "origin", "master"); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	pushed, err := gogit.PlainOpen(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref, err := pushed.Reference("refs/heads/master", false); err != nil || ref.Hash() != head.Hash() {
+		t.Errorf("remote master = %v, %v, want %s", ref, err, head.Hash())
+	}
+	cfg, _ := repo.Config()
+	if b := cfg.Branches["master"]; b == nil || b.Remote != "origin" {
+		t.Errorf("master doesn't track origin: %+v", b)
+	}
+}
+
+func TestPushArgs(t *testing.T) {
+	a := pushArgs("-u", "origin", "main")
+	b := pushArgs("-u", "origin", "dev")
+	if a[len(a)-1] != "main" || b[len(b)-1] != "dev" {
+		t.Errorf("pushArgs share their backing array: %v, %v", a, b)
+	}
+}
+
+func TestHistoryWithoutGit(t *testing.T) {
+	withoutGit(t)
+	dir := t.TempDir()
+	if err := Init(dir); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	commit := func(name, message string) string {
+		t.Helper()
+		writeFile(t, filepath.Join(dir, name), message+"\n")
+		if err := AddAll(dir); err != nil {
+			t.Fatal(err)
+		}
+		if err := Commit(dir, message+"\n\nBody"); err != nil {
+			t.Fatal(err)
+		}
+		info, err := GetCommitInfo(dir, "HEAD")
+		if err != nil {
+			t.Fatalf("GetCommitInfo: %v", err)
+		}
+		return info.Hash
+	}
+	writeFile(t, filepath.Join(dir, ".gitignore"), "dist/\n")
+	first := commit("a.txt", "first")
+	second := commit("b.txt", "second")
+	third := commit("c.txt", "third")
+
+	info, err := GetCommitInfo(dir, second[:7])
+	if err != nil {
+		t.Fatalf("GetCommitInfo by short hash: %v", err)
+	}
+	if info.Hash != second || info.ShortHash != second[:7] || info.Message != "second" || info.Email != fallbackEmail {
+		t.Errorf("GetCommitInfo = %+v", info)
+	}
+	if hash, err := GetLatestCommitHash(dir); err != nil || hash != third[:7] {
+		t.Errorf("GetLatestCommitHash = %q, %v, want %s", hash, err, third[:7])
+	}
+	if _, err := GetCommitInfo(dir, "0000000"); err == nil {
+		t.Error("GetCommitInfo of a missing commit succeeded")
+	}
+
+	commits, err := LogRange(dir, first, third)
+	if err != nil {
+		t.Fatalf("LogRange: %v", err)
+	}
+	var hashes []string
+	for _, c := range commits {
+		hashes = append(hashes, c.Hash)
+	}
+	if want := []string{third, second}; !reflect.DeepEqual(hashes, want) {
+		t.Errorf("LogRange = %v, want %v", hashes, want)
+	}
+	if commits, err := LogRange(dir, third, first); err != nil || len(commits) != 0 {
+		t.Errorf("LogRange backwards = %v, %v", commits, err)
+	}
+
+	writeFile(t, filepath.Join(dir, "a.txt"), "edited\n")
+	writeFile(t, filepath.Join(dir, "new.txt"), "")
+	writeFile(t, filepath.Join(dir, "dist", "app.js"), "")
+	files, err := ChangedFiles(dir, second)
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	if want := []string{"a.txt", "c.txt", "new.txt"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("ChangedFiles = %v, want %v", files, want)
+	}
+
+	ignored, err := IgnoredFiles(filepath.Join(dir, "dist"), []string{"app.js"})
+	if err != nil || !ignored["app.js"] {
+		t.Errorf("IgnoredFiles in dist = %v, %v", ignored, err)
+	}
+	ignored, err = IgnoredFiles(dir, []string{"dist/", "dist/app.js", ".gitignore", "new.txt"})
+	if err != nil {
+		t.Fatalf("IgnoredFiles: %v", err)
+	}
+	if want := map[string]bool{"dist/": true, "dist/app.js": true}; !reflect.DeepEqual(ignored, want) {
+		t.Errorf("IgnoredFiles = %v, want %v", ignored, want)
+	}
+}
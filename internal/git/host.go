@@ -0,0 +1,79 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// HostError is an error response of a git host's API
+type HostError struct {
+	Host       string
+	StatusCode int
+	Body       string
+}
+
+func (e *HostError) Error() string {
+	return fmt.Sprintf("%s API error (status %d): %s", e.Host, e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is a 404 of a git host's API
+func IsNotFound(err error) bool {
+	e, ok := err.(*HostError)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// hostRequest sends a JSON request to a git host's API, authenticated with
+// auth, and decodes the response into result
+func hostRequest(httpClient *http.Client, host, method, endpoint string, auth http.Header, body interface{}, result interface{}) error {
+	debug := os.Getenv("CDP_DEBUG") != ""
+	if debug {
+		fmt.Fprintf(os.Stderr, "[DEBUG] %s API: %s %s\n", host, method, endpoint)
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequest(method, endpoint, bodyReader)
+	if err != nil {
+		return err
+	}
+	for key, values := range auth {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if debug {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Response status: %d\n", resp.StatusCode)
+	}
+
+	if resp.StatusCode >= 400 {
+		return &HostError{Host: host, StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	if result != nil && len(respBody) > 0 {
+		return json.Unmarshal(respBody, result)
+	}
+	return nil
+}
@@ -3,10 +3,12 @@ package git
 import (
 	"bufio"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/ui"
@@ -22,17 +24,28 @@ func IsRepo(dir string) bool {
 	return info.IsDir()
 }
 
+// InWorkTree reports whether dir is in a git working tree, at its root or
+// below
+func InWorkTree(dir string) bool {
+	_, err := openRepo(dir)
+	return err == nil
+}
+
 // Init initializes a new git repository
 func Init(dir string) error {
-	cmd := exec.Command("git", "init")
-	cmd.Dir = dir
+	if !shell {
+		return goInit(dir)
+	}
+	cmd := command(dir, "init")
 	return cmd.Run()
 }
 
 // GetRemoteURL returns the remote URL for the given remote name
 func GetRemoteURL(dir, remoteName string) (string, error) {
-	cmd := exec.Command("git", "remote", "get-url", remoteName)
-	cmd.Dir = dir
+	if !shell {
+		return goRemoteURL(dir, remoteName)
+	}
+	cmd := command(dir, "remote", "get-url", remoteName)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -42,12 +55,13 @@ func GetRemoteURL(dir, remoteName string) (string, error) {
 
 // SetRemote sets or updates a remote URL
 func SetRemote(dir, remoteName, url string) error {
+	if !shell {
+		return goSetRemote(dir, remoteName, url)
+	}
 	// Try to add first, if it fails, update
-	cmd := exec.Command("git", "remote", "add", remoteName, url)
-	cmd.Dir = dir
+	cmd := command(dir, "remote", "add", remoteName, url)
 	if err := cmd.Run(); err != nil {
-		cmd = exec.Command("git", "remote", "set-url", remoteName, url)
-		cmd.Dir = dir
+		cmd = command(dir, "remote", "set-url", remoteName, url)
 		return cmd.Run()
 	}
 	return nil
@@ -55,13 +69,20 @@ func SetRemote(dir, remoteName, url string) error {
 
 // GetCurrentBranch returns the current branch name
 func GetCurrentBranch(dir string) (string, error) {
-	cmd := exec.Command("git", "branch", "--show-current")
-	cmd.Dir = dir
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
+	var branch string
+	if shell {
+		cmd := command(dir, "branch", "--show-current")
+		output, err := cmd.Output()
+		if err != nil {
+			return "", err
+		}
+		branch = strings.TrimSpace(string(output))
+	} else {
+		var err error
+		if branch, err = goCurrentBranch(dir); err != nil {
+			return "", err
+		}
 	}
-	branch := strings.TrimSpace(string(output))
 	if branch == "" {
 		return config.DefaultBranch, nil
 	}
@@ -70,8 +91,11 @@ func GetCurrentBranch(dir string) (string, error) {
 
 // HasChanges checks if there are uncommitted changes
 func HasChanges(dir string) bool {
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = dir
+	if !shell {
+		changed, err := goHasChanges(dir)
+		return err == nil && changed
+	}
+	cmd := command(dir, "status", "--porcelain")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -79,10 +103,37 @@ func HasChanges(dir string) bool {
 	return len(strings.TrimSpace(string(output))) > 0
 }
 
+// ListPushableFiles returns tracked and untracked, non-ignored files, i.e.
+// everything AddAll would stage
+func ListPushableFiles(dir string) ([]string, error) {
+	if !shell {
+		files, err := goPushableFiles(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+		return files, nil
+	}
+	cmd := command(dir, "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var files []string
+	for _, f := range strings.Split(string(output), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
 // AddAll stages all changes
 func AddAll(dir string) error {
-	cmd := exec.Command("git", "add", "-A")
-	cmd.Dir = dir
+	if !shell {
+		return goAddAll(dir)
+	}
+	cmd := command(dir, "add", "-A")
 	return cmd.Run()
 }
 
@@ -93,8 +144,10 @@ func Commit(dir, message string) error {
 
 // CommitVerbose creates a commit with optional output
 func CommitVerbose(dir, message string, verbose bool) error {
-	cmd := exec.Command("git", "commit", "-m", message)
-	cmd.Dir = dir
+	if !shell {
+		return goCommit(dir, message, verbose)
+	}
+	cmd := command(dir, append(commitArgs(dir), "commit", "-m", message)...)
 	if verbose {
 		// Stream output with dim styling like deployment logs
 		stdout, err := cmd.StdoutPipe()
@@ -146,10 +199,106 @@ func CommitVerbose(dir, message string, verbose bool) error {
 	return cmd.Run()
 }
 
+// ChangedFiles returns the files that differ from rev in the working tree,
+// including uncommitted and untracked (non-ignored) files
+func ChangedFiles(dir, rev string) ([]string, error) {
+	if !shell {
+		files, err := goChangedFiles(dir, rev)
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff against %s: %w", rev, err)
+		}
+		return files, nil
+	}
+	diff := command(dir, "diff", "--name-only", "-z", rev)
+	output, err := diff.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff against %s: %w", rev, err)
+	}
+
+	untracked := command(dir, "ls-files", "-z", "--others", "--exclude-standard")
+	more, err := untracked.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	var files []string
+	for _, f := range strings.Split(string(output)+string(more), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// IgnoredFiles returns which of files (relative to dir) are ignored by git.
+// Outside a working tree, or in shell mode without git, none are.
+func IgnoredFiles(dir string, files []string) (map[string]bool, error) {
+	ignored := make(map[string]bool)
+	if len(files) == 0 || !InWorkTree(dir) {
+		return ignored, nil
+	}
+	if !shell {
+		return goIgnoredFiles(dir, files)
+	}
+	if CheckCLI() != nil {
+		return ignored, nil
+	}
+
+	cmd := command(dir, "check-ignore", "-z", "--stdin")
+	cmd.Stdin = strings.NewReader(strings.Join(files, "\x00") + "\x00")
+	output, err := cmd.Output()
+	if err != nil {
+		// Exit status 1 means none of the files are ignored
+		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("git check-ignore failed: %w", err)
+		}
+	}
+
+	for _, f := range strings.Split(string(output), "\x00") {
+		if f != "" {
+			ignored[f] = true
+		}
+	}
+	return ignored, nil
+}
+
+// SnapshotCommit commits the working tree (tracked and untracked, non-ignored
+// files) as a parentless commit and returns its hash. A temporary index is
+// used, so the current branch, index and working tree are left untouched.
+func SnapshotCommit(dir, message string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "cdp-snapshot-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	index := "GIT_INDEX_FILE=" + filepath.Join(tmpDir, "index")
+	run := func(config []string, args ...string) (string, error) {
+		cmd := command(dir, append(config, args...)...)
+		cmd.Env = append(cmd.Env, index)
+		output, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("git %s failed: %w", args[0], err)
+		}
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	if _, err := run(nil, "add", "-A"); err != nil {
+		return "", err
+	}
+	tree, err := run(nil, "write-tree")
+	if err != nil {
+		return "", err
+	}
+	return run(commitArgs(dir), "commit-tree", tree, "-m", message)
+}
+
 // Push pushes to the remote
 func Push(dir, remoteName, branch string) error {
-	cmd := exec.Command("git", "push", "-u", remoteName, branch)
-	cmd.Dir = dir
+	if !shell {
+		return goPush(dir, remoteName, branch, nil, false)
+	}
+	cmd := command(dir, pushArgs("-u", remoteName, branch)...)
 	// Silence output during deployment
 	cmd.Stdout = nil
 	cmd.Stderr = nil
@@ -169,16 +318,45 @@ func PushWithTokenVerbose(dir, remoteName, branch, token string, verbose bool) e
 		return fmt.Errorf("failed to get remote URL: %w", err)
 	}
 
+	if !strings.HasPrefix(currentURL, "https://github.com/") {
+		return fmt.Errorf("unsupported remote URL format: %s", currentURL)
+	}
+	if !shell {
+		// GitHub takes the token as the password of any user
+		return goPush(dir, remoteName, branch, basicAuth("x-access-token", token), verbose)
+	}
+
 	// Inject token into URL temporarily
-	var urlWithToken string
-	if strings.HasPrefix(currentURL, "https://github.com/") {
-		urlWithToken = strings.Replace(currentURL, "https://github.com/", fmt.Sprintf("https://%s@github.com/", token), 1)
-	} else {
+	urlWithToken := strings.Replace(currentURL, "https://github.com/", fmt.Sprintf("https://%s@github.com/", token), 1)
+
+	return pushTo(dir, remoteName, branch, currentURL, urlWithToken, verbose)
+}
+
+// PushWithLoginVerbose pushes to an https remote on any host, logging in as
+// username with password, like GitLab's oauth2 user with a token
+func PushWithLoginVerbose(dir, remoteName, branch, username, password string, verbose bool) error {
+	currentURL, err := GetRemoteURL(dir, remoteName)
+	if err != nil {
+		return fmt.Errorf("failed to get remote URL: %w", err)
+	}
+
+	u, err := url.Parse(currentURL)
+	if err != nil || u.Scheme != "https" {
 		return fmt.Errorf("unsupported remote URL format: %s", currentURL)
 	}
+	if !shell {
+		return goPush(dir, remoteName, branch, basicAuth(username, password), verbose)
+	}
+	u.User = url.UserPassword(username, password)
 
+	return pushTo(dir, remoteName, branch, currentURL, u.String(), verbose)
+}
+
+// pushTo pushes branch through authURL, restoring the remote to currentURL
+// afterwards so the credentials are not left in .git/config
+func pushTo(dir, remoteName, branch, currentURL, authURL string, verbose bool) error {
 	// Temporarily update remote URL
-	if err := SetRemote(dir, remoteName, urlWithToken); err != nil {
+	if err := SetRemote(dir, remoteName, authURL); err != nil {
 		return fmt.Errorf("failed to set remote URL: %w", err)
 	}
 
@@ -186,8 +364,7 @@ func PushWithTokenVerbose(dir, remoteName, branch, token string, verbose bool) e
 	defer SetRemote(dir, remoteName, currentURL)
 
 	// Push
-	cmd := exec.Command("git", "push", "-u", remoteName, branch)
-	cmd.Dir = dir
+	cmd := command(dir, pushArgs("-u", remoteName, branch)...)
 
 	if verbose {
 		// Stream output with dim styling like deployment logs
@@ -243,8 +420,14 @@ func PushWithTokenVerbose(dir, remoteName, branch, token string, verbose bool) e
 
 // GetLatestCommitHash returns the latest commit hash
 func GetLatestCommitHash(dir string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--short", "HEAD")
-	cmd.Dir = dir
+	if !shell {
+		info, err := goCommitInfo(dir, "HEAD")
+		if err != nil {
+			return "", err
+		}
+		return info.ShortHash, nil
+	}
+	cmd := command(dir, "rev-parse", "--short", "HEAD")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -252,6 +435,59 @@ func GetLatestCommitHash(dir string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// commitLogFormat separates CommitInfo fields with the ASCII unit separator
+const commitLogFormat = "--format=%H%x1f%h%x1f%an%x1f%ae%x1f%aI%x1f%s"
+
+// GetCommitInfo returns information about a single commit
+func GetCommitInfo(dir, rev string) (*CommitInfo, error) {
+	if !shell {
+		return goCommitInfo(dir, rev)
+	}
+	commits, err := gitLog(dir, "-1", rev)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("commit %s not found", rev)
+	}
+	return &commits[0], nil
+}
+
+// LogRange returns the commits reachable from to but not from, newest first
+func LogRange(dir, from, to string) ([]CommitInfo, error) {
+	if !shell {
+		return goLogRange(dir, from, to)
+	}
+	return gitLog(dir, from+".."+to)
+}
+
+// gitLog runs git log and parses each commit
+func gitLog(dir string, args ...string) ([]CommitInfo, error) {
+	cmd := command(dir, append([]string{"log", commitLogFormat}, args...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	var commits []CommitInfo
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\x1f", 6)
+		if len(fields) != 6 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[4])
+		commits = append(commits, CommitInfo{
+			Hash:      fields[0],
+			ShortHash: fields[1],
+			Author:    fields[2],
+			Email:     fields[3],
+			Date:      date,
+			Message:   fields[5],
+		})
+	}
+	return commits, nil
+}
+
 // AutoCommit stages all changes and creates a commit
 func AutoCommit(dir string) error {
 	return AutoCommitVerbose(dir, false)
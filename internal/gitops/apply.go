@@ -0,0 +1,290 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+)
+
+// Result lists the applications an apply created or updated, which need a
+// deployment for the changes to take effect
+type Result struct {
+	Applications []string
+}
+
+// Apply executes a plan against the live state it was computed from. It
+// stops at the first failing change; changes before it stay applied.
+func Apply(client *api.Client, state *State, changes []Change, progress func(Change)) (*Result, error) {
+	a := &applier{
+		client:       client,
+		projects:     map[string]string{},
+		environments: map[string]string{},
+		result:       &Result{},
+	}
+	for _, p := range state.Projects {
+		a.projects[strings.ToLower(p.Name)] = p.UUID
+		for _, e := range p.Environments {
+			a.environments[envKey(p.Name, e.Name)] = e.UUID
+		}
+	}
+
+	for _, c := range changes {
+		if progress != nil {
+			progress(c)
+		}
+		if err := a.apply(c); err != nil {
+			return a.result, fmt.Errorf("failed to %s %s %s: %w", c.Action, c.Kind, c.Path, err)
+		}
+	}
+	return a.result, nil
+}
+
+type applier struct {
+	client       *api.Client
+	projects     map[string]string
+	environments map[string]string
+	result       *Result
+}
+
+func envKey(project, environment string) string {
+	return strings.ToLower(project) + "/" + strings.ToLower(environment)
+}
+
+func (a *applier) apply(c Change) error {
+	switch c.Kind {
+	case KindProject:
+		return a.createProject(c)
+	case KindEnvironment:
+		return a.createEnvironment(c)
+	case KindDatabase:
+		switch c.Action {
+		case ActionCreate:
+			return a.createDatabase(c)
+		case ActionUpdate:
+			return a.client.UpdateDatabase(c.uuid, c.dbPatch)
+		default:
+			return a.client.DeleteDatabase(c.uuid)
+		}
+	case KindApplication:
+		switch c.Action {
+		case ActionCreate:
+			return a.createApplication(c)
+		case ActionUpdate:
+			return a.updateApplication(c)
+		default:
+			return a.client.DeleteApplication(c.uuid)
+		}
+	}
+	return fmt.Errorf("unknown change kind %s", c.Kind)
+}
+
+func (a *applier) createProject(c Change) error {
+	project, err := a.client.CreateProject(c.project, c.projectDesc)
+	if err != nil {
+		return err
+	}
+	a.projects[strings.ToLower(c.project)] = project.UUID
+
+	// Coolify creates a default environment with every project
+	if created, err := a.client.GetProject(project.UUID); err == nil {
+		for _, e := range created.Environments {
+			a.environments[envKey(c.project, e.Name)] = e.UUID
+		}
+	}
+	return nil
+}
+
+func (a *applier) createEnvironment(c Change) error {
+	key := envKey(c.project, c.environment)
+	if a.environments[key] != "" {
+		return nil
+	}
+	projectUUID, err := a.projectUUID(c.project)
+	if err != nil {
+		return err
+	}
+	env, err := a.client.CreateEnvironment(projectUUID, c.environment)
+	if err != nil {
+		return err
+	}
+	a.environments[key] = env.UUID
+	return nil
+}
+
+func (a *applier) projectUUID(name string) (string, error) {
+	uuid := a.projects[strings.ToLower(name)]
+	if uuid == "" {
+		return "", fmt.Errorf("project %s doesn't exist", name)
+	}
+	return uuid, nil
+}
+
+func (a *applier) environmentUUID(project, environment string) (string, error) {
+	uuid := a.environments[envKey(project, environment)]
+	if uuid == "" {
+		return "", fmt.Errorf("environment %s/%s doesn't exist", project, environment)
+	}
+	return uuid, nil
+}
+
+func (a *applier) createDatabase(c Change) error {
+	projectUUID, err := a.projectUUID(c.project)
+	if err != nil {
+		return err
+	}
+	envUUID, err := a.environmentUUID(c.project, c.environment)
+	if err != nil {
+		return err
+	}
+
+	d := c.db
+	req, err := api.NewDatabaseRequest(d.Engine, api.CreateDatabaseRequest{
+		ProjectUUID:     projectUUID,
+		ServerUUID:      c.serverUUID,
+		EnvironmentUUID: envUUID,
+		Name:            d.Name,
+		Image:           d.Image,
+		IsPublic:        d.Public,
+		PublicPort:      d.PublicPort,
+		LimitsMemory:    d.Memory,
+		LimitsCPUs:      d.CPUs,
+		InstantDeploy:   true,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = a.client.CreateDatabase(req)
+	return err
+}
+
+func (a *applier) createApplication(c Change) error {
+	projectUUID, err := a.projectUUID(c.project)
+	if err != nil {
+		return err
+	}
+	envUUID, err := a.environmentUUID(c.project, c.environment)
+	if err != nil {
+		return err
+	}
+
+	app := c.app
+	domains := joinDomains(app.Domains)
+	baseDir := ""
+	if app.BaseDirectory != "" {
+		baseDir = "/" + strings.Trim(app.BaseDirectory, "/")
+	}
+
+	var uuid string
+	switch {
+	case app.Image != "":
+		name, tag := splitImage(app.Image)
+		resp, err := a.client.CreateDockerImageApp(&api.CreateDockerImageAppRequest{
+			ProjectUUID:             projectUUID,
+			ServerUUID:              c.serverUUID,
+			EnvironmentUUID:         envUUID,
+			Name:                    app.Name,
+			Domains:                 domains,
+			DockerRegistryImageName: name,
+			DockerRegistryImageTag:  tag,
+			PortsExposes:            app.Port,
+		})
+		if err != nil {
+			return err
+		}
+		uuid = resp.UUID
+	case c.githubApp != "":
+		resp, err := a.client.CreatePrivateGitHubApp(&api.CreatePrivateGitHubAppRequest{
+			ProjectUUID:        projectUUID,
+			ServerUUID:         c.serverUUID,
+			EnvironmentUUID:    envUUID,
+			GitHubAppUUID:      c.githubApp,
+			GitRepository:      normalizeRepository(app.Repository),
+			GitBranch:          app.Branch,
+			Name:               app.Name,
+			BuildPack:          app.BuildPack,
+			Domains:            domains,
+			InstallCommand:     app.InstallCommand,
+			BuildCommand:       app.BuildCommand,
+			StartCommand:       app.StartCommand,
+			PortsExposes:       app.Port,
+			PublishDirectory:   app.PublishDirectory,
+			BaseDirectory:      baseDir,
+			HealthCheckEnabled: app.HealthCheckPath != "",
+			HealthCheckPath:    app.HealthCheckPath,
+		})
+		if err != nil {
+			return err
+		}
+		uuid = resp.UUID
+	default:
+		resp, err := a.client.CreatePublicApp(&api.CreatePublicAppRequest{
+			ProjectUUID:      projectUUID,
+			ServerUUID:       c.serverUUID,
+			EnvironmentUUID:  envUUID,
+			GitRepository:    app.Repository,
+			GitBranch:        app.Branch,
+			BuildPack:        app.BuildPack,
+			Name:             app.Name,
+			Domains:          domains,
+			InstallCommand:   app.InstallCommand,
+			BuildCommand:     app.BuildCommand,
+			StartCommand:     app.StartCommand,
+			PortsExposes:     app.Port,
+			PublishDirectory: app.PublishDirectory,
+			BaseDirectory:    baseDir,
+		})
+		if err != nil {
+			return err
+		}
+		uuid = resp.UUID
+	}
+	a.result.Applications = append(a.result.Applications, uuid)
+
+	// Settings the create endpoints don't take
+	patch := map[string]interface{}{}
+	if app.HealthCheckPath != "" && c.githubApp == "" {
+		patch["health_check_enabled"] = true
+		patch["health_check_path"] = app.HealthCheckPath
+	}
+	if app.Memory != "" {
+		patch["limits_memory"] = app.Memory
+	}
+	if app.CPUs != "" {
+		patch["limits_cpus"] = app.CPUs
+	}
+	if len(patch) > 0 {
+		if err := a.client.UpdateApplication(uuid, patch); err != nil {
+			return fmt.Errorf("failed to update settings: %w", err)
+		}
+	}
+	return a.setEnv(uuid, c)
+}
+
+func (a *applier) updateApplication(c Change) error {
+	if len(c.appPatch) > 0 {
+		if err := a.client.UpdateApplication(c.uuid, c.appPatch); err != nil {
+			return err
+		}
+	}
+	if err := a.setEnv(c.uuid, c); err != nil {
+		return err
+	}
+	a.result.Applications = append(a.result.Applications, c.uuid)
+	return nil
+}
+
+func (a *applier) setEnv(appUUID string, c Change) error {
+	if len(c.envSet) > 0 {
+		if _, err := a.client.UpdateApplicationEnvsBulk(context.Background(), appUUID, c.envSet); err != nil {
+			return fmt.Errorf("failed to set environment variables: %w", err)
+		}
+	}
+	for _, envUUID := range c.envDelete {
+		if err := a.client.DeleteApplicationEnvVar(appUUID, envUUID); err != nil {
+			return fmt.Errorf("failed to delete environment variable: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,431 @@
+package gitops
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+)
+
+// Action is what a change does to a resource
+type Action string
+
+// Change actions
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Kind is the type of resource a change applies to
+type Kind string
+
+// Resource kinds
+const (
+	KindProject     Kind = "project"
+	KindEnvironment Kind = "environment"
+	KindApplication Kind = "application"
+	KindDatabase    Kind = "database"
+)
+
+// Change is one step of a plan
+type Change struct {
+	Action Action
+	Kind   Kind
+	// Path is project, project/environment or project/environment/name
+	Path   string
+	Fields []FieldChange
+
+	project     string
+	environment string
+	uuid        string
+
+	projectDesc string
+	app         *Application
+	db          *Database
+	serverUUID  string
+	githubApp   string
+
+	appPatch  map[string]interface{}
+	dbPatch   *api.UpdateDatabaseRequest
+	envSet    []api.EnvironmentVariable
+	envDelete []string
+}
+
+// FieldChange is a setting that differs between the spec and the live resource
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// PlanOptions controls what a plan may do
+type PlanOptions struct {
+	// Prune deletes applications, databases and env vars that are missing
+	// from the spec. Only environments declared in the spec are pruned.
+	Prune bool
+}
+
+// Plan compares the spec with the live state and returns the changes that
+// reconcile them: creations first, then updates, then deletions
+func Plan(spec *Spec, state *State, opts PlanOptions) ([]Change, error) {
+	var creates, updates, deletes []Change
+
+	for pi := range spec.Projects {
+		p := &spec.Projects[pi]
+		lp := state.project(p.Name)
+		if lp == nil {
+			creates = append(creates, Change{
+				Action: ActionCreate, Kind: KindProject, Path: p.Name,
+				project: p.Name, projectDesc: p.Description,
+			})
+		}
+
+		for ei := range p.Environments {
+			e := &p.Environments[ei]
+			path := p.Name + "/" + e.Name
+
+			var le *LiveEnvironment
+			if lp != nil {
+				le = lp.environment(e.Name)
+			}
+			if le == nil {
+				creates = append(creates, Change{
+					Action: ActionCreate, Kind: KindEnvironment, Path: path,
+					project: p.Name, environment: e.Name,
+				})
+			}
+
+			for di := range e.Databases {
+				d := &e.Databases[di]
+				var live *api.Database
+				if le != nil {
+					live = le.database(d.Name)
+				}
+				c, err := planDatabase(state, p.Name, e.Name, d, live)
+				if err != nil {
+					return nil, err
+				}
+				switch {
+				case c == nil:
+				case c.Action == ActionCreate:
+					creates = append(creates, *c)
+				default:
+					updates = append(updates, *c)
+				}
+			}
+
+			for ai := range e.Applications {
+				a := &e.Applications[ai]
+				var live *LiveApplication
+				if le != nil {
+					live = le.application(a.Name)
+				}
+				c, err := planApplication(state, p.Name, e.Name, a, live, opts)
+				if err != nil {
+					return nil, err
+				}
+				switch {
+				case c == nil:
+				case c.Action == ActionCreate:
+					creates = append(creates, *c)
+				default:
+					updates = append(updates, *c)
+				}
+			}
+
+			if !opts.Prune || le == nil {
+				continue
+			}
+			for _, la := range le.Applications {
+				if !e.hasResource(la.App.Name) {
+					deletes = append(deletes, Change{
+						Action: ActionDelete, Kind: KindApplication, Path: path + "/" + la.App.Name,
+						project: p.Name, environment: e.Name, uuid: la.App.UUID,
+					})
+				}
+			}
+			for _, ld := range le.Databases {
+				if !e.hasResource(ld.Name) {
+					deletes = append(deletes, Change{
+						Action: ActionDelete, Kind: KindDatabase, Path: path + "/" + ld.Name,
+						project: p.Name, environment: e.Name, uuid: ld.UUID,
+					})
+				}
+			}
+		}
+	}
+
+	changes := append(creates, updates...)
+	return append(changes, deletes...), nil
+}
+
+func (e *Environment) hasResource(name string) bool {
+	for _, a := range e.Applications {
+		if a.Name == name {
+			return true
+		}
+	}
+	for _, d := range e.Databases {
+		if d.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func planDatabase(state *State, project, environment string, d *Database, live *api.Database) (*Change, error) {
+	path := project + "/" + environment + "/" + d.Name
+	if live == nil {
+		serverUUID, err := state.resolveServer(d.Server)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return &Change{
+			Action: ActionCreate, Kind: KindDatabase, Path: path,
+			Fields:  []FieldChange{{Field: "engine", New: d.Engine}},
+			project: project, environment: environment, db: d, serverUUID: serverUUID,
+		}, nil
+	}
+
+	if live.Engine() != d.Engine {
+		return nil, fmt.Errorf("%s: engine can't be changed from %s to %s", path, live.Engine(), d.Engine)
+	}
+
+	c := &Change{
+		Action: ActionUpdate, Kind: KindDatabase, Path: path,
+		project: project, environment: environment, uuid: live.UUID,
+		dbPatch: &api.UpdateDatabaseRequest{},
+	}
+	if d.Image != "" && d.Image != live.Image {
+		c.Fields = append(c.Fields, FieldChange{"image", live.Image, d.Image})
+		c.dbPatch.Image = &d.Image
+	}
+	if d.Public != live.IsPublic {
+		c.Fields = append(c.Fields, FieldChange{"public", strconv.FormatBool(live.IsPublic), strconv.FormatBool(d.Public)})
+		c.dbPatch.IsPublic = &d.Public
+	}
+	if d.Public && d.PublicPort != 0 && d.PublicPort != live.PublicPort {
+		c.Fields = append(c.Fields, FieldChange{"public_port", strconv.Itoa(live.PublicPort), strconv.Itoa(d.PublicPort)})
+		c.dbPatch.PublicPort = &d.PublicPort
+	}
+	if d.Memory != "" && d.Memory != live.LimitsMemory {
+		c.Fields = append(c.Fields, FieldChange{"memory", live.LimitsMemory, d.Memory})
+		c.dbPatch.LimitsMemory = &d.Memory
+	}
+	if d.CPUs != "" && d.CPUs != live.LimitsCPUs {
+		c.Fields = append(c.Fields, FieldChange{"cpus", live.LimitsCPUs, d.CPUs})
+		c.dbPatch.LimitsCPUs = &d.CPUs
+	}
+	if len(c.Fields) == 0 {
+		return nil, nil
+	}
+	return c, nil
+}
+
+func planApplication(state *State, project, environment string, a *Application, live *LiveApplication, opts PlanOptions) (*Change, error) {
+	path := project + "/" + environment + "/" + a.Name
+
+	serverUUID, err := state.resolveServer(a.Server)
+	if err != nil && (live == nil || a.Server != "") {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var githubApp string
+	if a.GitHubApp != "" {
+		if githubApp, err = state.resolveGitHubApp(a.GitHubApp); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if live == nil {
+		source := a.Image
+		if source == "" {
+			source = a.Repository
+		}
+		c := &Change{
+			Action: ActionCreate, Kind: KindApplication, Path: path,
+			Fields:  []FieldChange{{Field: "source", New: source}},
+			project: project, environment: environment,
+			app: a, serverUUID: serverUUID, githubApp: githubApp,
+		}
+		if len(a.Domains) > 0 {
+			c.Fields = append(c.Fields, FieldChange{Field: "domains", New: joinDomains(a.Domains)})
+		}
+		for _, key := range sortedKeys(a.Env) {
+			c.envSet = append(c.envSet, api.EnvironmentVariable{Key: key, Value: a.Env[key]})
+		}
+		if len(a.Env) > 0 {
+			c.Fields = append(c.Fields, FieldChange{Field: "env", New: fmt.Sprintf("%d variable(s)", len(a.Env))})
+		}
+		return c, nil
+	}
+
+	if a.Server != "" {
+		if liveServer := state.serverUUID(live.App.DestinationID); liveServer != "" && liveServer != serverUUID {
+			return nil, fmt.Errorf("%s: moving applications between servers isn't supported", path)
+		}
+	}
+
+	current, _ := state.specApplication(live)
+	c := &Change{
+		Action: ActionUpdate, Kind: KindApplication, Path: path,
+		project: project, environment: environment, uuid: live.App.UUID,
+		appPatch: map[string]interface{}{},
+	}
+	set := func(field, key, old, want string) {
+		if want != "" && want != old {
+			c.Fields = append(c.Fields, FieldChange{field, old, want})
+			c.appPatch[key] = want
+		}
+	}
+
+	if a.Image != "" {
+		wantName, wantTag := splitImage(a.Image)
+		oldName, oldTag := splitImage(current.Image)
+		set("image", "docker_registry_image_name", oldName, wantName)
+		set("image_tag", "docker_registry_image_tag", oldTag, wantTag)
+	}
+	if a.Repository != "" && normalizeRepository(a.Repository) != normalizeRepository(current.Repository) {
+		set("repository", "git_repository", current.Repository, a.Repository)
+	}
+	set("branch", "git_branch", current.Branch, a.Branch)
+	set("build_pack", "build_pack", current.BuildPack, a.BuildPack)
+	set("install_command", "install_command", current.InstallCommand, a.InstallCommand)
+	set("build_command", "build_command", current.BuildCommand, a.BuildCommand)
+	set("start_command", "start_command", current.StartCommand, a.StartCommand)
+	set("publish_directory", "publish_directory", current.PublishDirectory, a.PublishDirectory)
+	if a.BaseDirectory != "" && strings.Trim(a.BaseDirectory, "/") != strings.Trim(current.BaseDirectory, "/") {
+		set("base_directory", "base_directory", current.BaseDirectory, "/"+strings.Trim(a.BaseDirectory, "/"))
+	}
+	set("port", "ports_exposes", current.Port, a.Port)
+	set("health_check_path", "health_check_path", current.HealthCheckPath, a.HealthCheckPath)
+	if _, ok := c.appPatch["health_check_path"]; ok {
+		c.appPatch["health_check_enabled"] = true
+	}
+	set("memory", "limits_memory", current.Memory, a.Memory)
+	set("cpus", "limits_cpus", current.CPUs, a.CPUs)
+	if a.Domains != nil && !sameDomains(a.Domains, current.Domains) {
+		set("domains", "domains", joinDomains(current.Domains), joinDomains(a.Domains))
+	}
+
+	planEnv(c, a, live, opts)
+
+	if len(c.Fields) == 0 {
+		return nil, nil
+	}
+	return c, nil
+}
+
+// planEnv adds the env var changes of an application. Shown-once values
+// can't be read back, so they are only set when missing.
+func planEnv(c *Change, a *Application, live *LiveApplication, opts PlanOptions) {
+	if a.Env == nil {
+		return
+	}
+
+	existing := map[string]api.EnvironmentVariable{}
+	for _, e := range live.Env {
+		if !e.IsPreview {
+			existing[e.Key] = e
+		}
+	}
+
+	for _, key := range sortedKeys(a.Env) {
+		want := a.Env[key]
+		cur, ok := existing[key]
+		switch {
+		case !ok:
+			c.Fields = append(c.Fields, FieldChange{"env." + key, "", "(set)"})
+		case cur.IsShownOnce || cur.Value == want:
+			continue
+		default:
+			c.Fields = append(c.Fields, FieldChange{"env." + key, "(old value)", "(new value)"})
+		}
+		c.envSet = append(c.envSet, api.EnvironmentVariable{Key: key, Value: want})
+	}
+
+	if !opts.Prune {
+		return
+	}
+	var removed []string
+	for key := range existing {
+		if _, ok := a.Env[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		c.Fields = append(c.Fields, FieldChange{"env." + key, "(set)", ""})
+		c.envDelete = append(c.envDelete, existing[key].UUID)
+	}
+}
+
+// serverUUID returns the server behind a destination
+func (s *State) serverUUID(destinationID int) string {
+	for _, d := range s.Destinations {
+		if d.ID == destinationID {
+			return d.ServerUUID
+		}
+	}
+	return ""
+}
+
+// splitImage splits an image reference into name and tag, defaulting to "latest"
+func splitImage(image string) (string, string) {
+	if image == "" {
+		return "", ""
+	}
+	// A colon before the last slash belongs to a registry port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
+
+// normalizeRepository reduces a repository URL to owner/repo for comparison
+func normalizeRepository(repo string) string {
+	repo = strings.TrimSuffix(strings.TrimSpace(repo), ".git")
+	for _, prefix := range []string{"https://github.com/", "http://github.com/", "git@github.com:"} {
+		repo = strings.TrimPrefix(repo, prefix)
+	}
+	return strings.Trim(repo, "/")
+}
+
+// normalizeDomain adds the https scheme to bare hosts
+func normalizeDomain(domain string) string {
+	domain = strings.TrimSpace(domain)
+	if domain != "" && !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	return strings.TrimSuffix(domain, "/")
+}
+
+func joinDomains(domains []string) string {
+	normalized := make([]string, 0, len(domains))
+	for _, d := range domains {
+		if d = normalizeDomain(d); d != "" {
+			normalized = append(normalized, d)
+		}
+	}
+	return strings.Join(normalized, ",")
+}
+
+func sameDomains(a, b []string) bool {
+	set := func(domains []string) string {
+		normalized := strings.Split(joinDomains(domains), ",")
+		sort.Strings(normalized)
+		return strings.Join(normalized, ",")
+	}
+	return set(a) == set(b)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,179 @@
+package gitops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+)
+
+func strPtr(s string) *string { return &s }
+
+func testState() *State {
+	return &State{
+		Servers:      []api.Server{{UUID: "srv-1", Name: "main"}},
+		Destinations: []api.Destination{{ID: 7, UUID: "dst-1", ServerUUID: "srv-1"}},
+		Projects: []LiveProject{{
+			UUID: "prj-1",
+			Name: "shop",
+			Environments: []LiveEnvironment{{
+				ID:   1,
+				UUID: "env-1",
+				Name: "production",
+				Applications: []LiveApplication{
+					{
+						App: api.Application{
+							UUID:                    "app-web",
+							Name:                    "web",
+							DestinationID:           7,
+							DockerRegistryImageName: strPtr("ghcr.io/acme/web"),
+							DockerRegistryImageTag:  strPtr("1.0"),
+							PortsExposes:            "3000",
+							Fqdn:                    strPtr("https://shop.example.com"),
+						},
+						Env: []api.EnvironmentVariable{
+							{UUID: "e1", Key: "MODE", Value: "prod"},
+							{UUID: "e2", Key: "TOKEN", IsShownOnce: true},
+							{UUID: "e3", Key: "OLD", Value: "x"},
+						},
+					},
+					{App: api.Application{UUID: "app-legacy", Name: "legacy", DestinationID: 7}},
+				},
+				Databases: []api.Database{{UUID: "db-1", Name: "db", Type: "standalone-postgresql"}},
+			}},
+		}},
+	}
+}
+
+func testSpec() *Spec {
+	return &Spec{
+		Version: SpecVersion,
+		Projects: []Project{
+			{
+				Name: "shop",
+				Environments: []Environment{
+					{
+						Name: "production",
+						Applications: []Application{{
+							Name:    "web",
+							Image:   "ghcr.io/acme/web:1.1",
+							Port:    "3000",
+							Domains: []string{"shop.example.com"},
+							Env:     map[string]string{"MODE": "prod", "TOKEN": "t", "NEW": "1"},
+						}},
+						Databases: []Database{{Name: "db", Engine: "postgresql", Memory: "512m"}},
+					},
+					{
+						Name:      "staging",
+						Databases: []Database{{Name: "cache", Engine: "redis"}},
+					},
+				},
+			},
+			{Name: "blog"},
+		},
+	}
+}
+
+func summarize(changes []Change) []string {
+	var out []string
+	for _, c := range changes {
+		out = append(out, string(c.Action)+" "+string(c.Kind)+" "+c.Path)
+	}
+	return out
+}
+
+func TestPlan(t *testing.T) {
+	changes, err := Plan(testSpec(), testState(), PlanOptions{})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+
+	want := []string{
+		"create environment shop/staging",
+		"create database shop/staging/cache",
+		"create project blog",
+		"update database shop/production/db",
+		"update application shop/production/web",
+	}
+	if got := summarize(changes); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	web := changes[4]
+	if web.appPatch["docker_registry_image_tag"] != "1.1" || len(web.appPatch) != 1 {
+		t.Errorf("web patch = %v, want only the image tag", web.appPatch)
+	}
+	// TOKEN is shown-once so it can't be compared; only NEW is missing
+	if len(web.envSet) != 1 || web.envSet[0].Key != "NEW" {
+		t.Errorf("web env = %+v, want only NEW", web.envSet)
+	}
+	if len(web.envDelete) != 0 {
+		t.Errorf("env deleted without prune: %v", web.envDelete)
+	}
+	if db := changes[3]; db.dbPatch.LimitsMemory == nil || *db.dbPatch.LimitsMemory != "512m" {
+		t.Errorf("db patch = %+v, want the memory limit", db.dbPatch)
+	}
+}
+
+func TestPlanPrune(t *testing.T) {
+	changes, err := Plan(testSpec(), testState(), PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+
+	last := changes[len(changes)-1]
+	if last.Action != ActionDelete || last.uuid != "app-legacy" {
+		t.Errorf("last change = %s %s, want the legacy app deleted", last.Action, last.Path)
+	}
+	for _, c := range changes {
+		if c.Path == "shop/production/web" && (len(c.envDelete) != 1 || c.envDelete[0] != "e3") {
+			t.Errorf("web env deletes = %v, want OLD", c.envDelete)
+		}
+	}
+}
+
+func TestPlanInSync(t *testing.T) {
+	spec := &Spec{Projects: []Project{{
+		Name: "shop",
+		Environments: []Environment{{
+			Name:         "production",
+			Applications: []Application{{Name: "web", Image: "ghcr.io/acme/web:1.0", Domains: []string{"https://shop.example.com/"}}},
+		}},
+	}}}
+
+	changes, err := Plan(spec, testState(), PlanOptions{})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("changes = %v, want none", summarize(changes))
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+	spec := testSpec()
+	spec.Projects[0].Environments[0].Databases[0].Engine = "mysql"
+	if _, err := Plan(spec, testState(), PlanOptions{}); err == nil || !strings.Contains(err.Error(), "engine") {
+		t.Errorf("engine change: error = %v", err)
+	}
+
+	spec = testSpec()
+	spec.Projects[0].Environments[1].Databases[0].Server = "other"
+	if _, err := Plan(spec, testState(), PlanOptions{}); err == nil || !strings.Contains(err.Error(), "server not found") {
+		t.Errorf("unknown server: error = %v", err)
+	}
+}
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct{ image, name, tag string }{
+		{"nginx", "nginx", "latest"},
+		{"nginx:1.27", "nginx", "1.27"},
+		{"registry.local:5000/acme/web", "registry.local:5000/acme/web", "latest"},
+		{"registry.local:5000/acme/web:v2", "registry.local:5000/acme/web", "v2"},
+	}
+	for _, tt := range tests {
+		if name, tag := splitImage(tt.image); name != tt.name || tag != tt.tag {
+			t.Errorf("splitImage(%q) = %q, %q, want %q, %q", tt.image, name, tag, tt.name, tt.tag)
+		}
+	}
+}
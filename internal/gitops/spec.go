@@ -0,0 +1,238 @@
+// Package gitops reconciles live Coolify resources with a declarative spec.
+package gitops
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+	"go.yaml.in/yaml/v3"
+)
+
+// SpecVersion is the spec format version this package reads and writes
+const SpecVersion = 1
+
+// Spec declares projects and the resources in their environments
+type Spec struct {
+	Version  int       `yaml:"version"`
+	Projects []Project `yaml:"projects"`
+}
+
+// Project is a Coolify project
+type Project struct {
+	Name         string        `yaml:"name"`
+	Description  string        `yaml:"description,omitempty"`
+	Environments []Environment `yaml:"environments"`
+}
+
+// Environment is an environment of a project and the resources it holds
+type Environment struct {
+	Name         string        `yaml:"name"`
+	Applications []Application `yaml:"applications,omitempty"`
+	Databases    []Database    `yaml:"databases,omitempty"`
+}
+
+// Application is an application deployed from a registry image or a Git
+// repository. Empty fields are not managed and keep their live value.
+type Application struct {
+	Name string `yaml:"name"`
+	// Server is a server name or UUID; it may be left out if there is only one
+	Server string `yaml:"server,omitempty"`
+
+	// Image is a registry image with an optional tag. Exactly one of Image
+	// and Repository is set.
+	Image string `yaml:"image,omitempty"`
+	// Repository is a public repository URL, or owner/repo with GitHubApp
+	Repository string `yaml:"repository,omitempty"`
+	// GitHubApp is the name or UUID of the Coolify GitHub App for private repositories
+	GitHubApp string `yaml:"github_app,omitempty"`
+	Branch    string `yaml:"branch,omitempty"`
+
+	BuildPack        string `yaml:"build_pack,omitempty"`
+	InstallCommand   string `yaml:"install_command,omitempty"`
+	BuildCommand     string `yaml:"build_command,omitempty"`
+	StartCommand     string `yaml:"start_command,omitempty"`
+	PublishDirectory string `yaml:"publish_directory,omitempty"`
+	BaseDirectory    string `yaml:"base_directory,omitempty"`
+	Port             string `yaml:"port,omitempty"`
+	HealthCheckPath  string `yaml:"health_check_path,omitempty"`
+	Memory           string `yaml:"memory,omitempty"`
+	CPUs             string `yaml:"cpus,omitempty"`
+
+	Domains []string `yaml:"domains,omitempty"`
+	// Env values may reference local environment variables as ${NAME}
+	Env map[string]string `yaml:"env,omitempty"`
+}
+
+// Database is a standalone database
+type Database struct {
+	Name       string `yaml:"name"`
+	Engine     string `yaml:"engine"`
+	Server     string `yaml:"server,omitempty"`
+	Image      string `yaml:"image,omitempty"`
+	Public     bool   `yaml:"public,omitempty"`
+	PublicPort int    `yaml:"public_port,omitempty"`
+	Memory     string `yaml:"memory,omitempty"`
+	CPUs       string `yaml:"cpus,omitempty"`
+}
+
+// envReference matches ${NAME} references in env values. $${NAME} is an
+// escaped, literal ${NAME}.
+var envReference = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Load reads a spec from path, or from stdin if path is "-", and resolves
+// env references against the local environment
+func Load(path string) (*Spec, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	return Parse(data, os.LookupEnv)
+}
+
+// Parse decodes and validates a spec. Env references are resolved with lookup.
+func Parse(data []byte, lookup func(string) (string, bool)) (*Spec, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var spec Spec
+	if err := dec.Decode(&spec); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("spec is empty")
+		}
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	if spec.Version == 0 {
+		spec.Version = SpecVersion
+	}
+	if spec.Version != SpecVersion {
+		return nil, fmt.Errorf("unsupported spec version %d (expected %d)", spec.Version, SpecVersion)
+	}
+
+	if err := spec.resolveEnv(lookup); err != nil {
+		return nil, err
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Marshal encodes a spec as YAML
+func Marshal(spec *Spec) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(spec); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Spec) resolveEnv(lookup func(string) (string, bool)) error {
+	for pi := range s.Projects {
+		for ei := range s.Projects[pi].Environments {
+			env := &s.Projects[pi].Environments[ei]
+			for ai := range env.Applications {
+				app := &env.Applications[ai]
+				for key, value := range app.Env {
+					var missing []string
+					resolved := envReference.ReplaceAllStringFunc(value, func(ref string) string {
+						if strings.HasPrefix(ref, "$$") {
+							return ref[1:]
+						}
+						name := envReference.FindStringSubmatch(ref)[1]
+						v, ok := lookup(name)
+						if !ok {
+							missing = append(missing, name)
+						}
+						return v
+					})
+					if len(missing) > 0 {
+						return fmt.Errorf("%s: env %s references unset variable %s", app.Name, key, strings.Join(missing, ", "))
+					}
+					app.Env[key] = resolved
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks that names are set and unique and that every resource is complete
+func (s *Spec) Validate() error {
+	projects := map[string]bool{}
+	for _, p := range s.Projects {
+		if p.Name == "" {
+			return fmt.Errorf("every project needs a name")
+		}
+		if projects[strings.ToLower(p.Name)] {
+			return fmt.Errorf("project %s is declared twice", p.Name)
+		}
+		projects[strings.ToLower(p.Name)] = true
+
+		envs := map[string]bool{}
+		for _, e := range p.Environments {
+			if e.Name == "" {
+				return fmt.Errorf("%s: every environment needs a name", p.Name)
+			}
+			if envs[strings.ToLower(e.Name)] {
+				return fmt.Errorf("%s: environment %s is declared twice", p.Name, e.Name)
+			}
+			envs[strings.ToLower(e.Name)] = true
+
+			where := p.Name + "/" + e.Name
+			names := map[string]bool{}
+			for _, a := range e.Applications {
+				if a.Name == "" {
+					return fmt.Errorf("%s: every application needs a name", where)
+				}
+				if names[a.Name] {
+					return fmt.Errorf("%s: %s is declared twice", where, a.Name)
+				}
+				names[a.Name] = true
+				if (a.Image == "") == (a.Repository == "") {
+					return fmt.Errorf("%s/%s: set exactly one of image and repository", where, a.Name)
+				}
+				if a.GitHubApp != "" && a.Repository == "" {
+					return fmt.Errorf("%s/%s: github_app needs a repository", where, a.Name)
+				}
+			}
+			for _, d := range e.Databases {
+				if d.Name == "" {
+					return fmt.Errorf("%s: every database needs a name", where)
+				}
+				if names[d.Name] {
+					return fmt.Errorf("%s: %s is declared twice", where, d.Name)
+				}
+				names[d.Name] = true
+				if !isDatabaseEngine(d.Engine) {
+					return fmt.Errorf("%s/%s: unknown engine %q (use one of %s)", where, d.Name, d.Engine, strings.Join(api.DatabaseEngines, ", "))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isDatabaseEngine(engine string) bool {
+	for _, e := range api.DatabaseEngines {
+		if e == engine {
+			return true
+		}
+	}
+	return false
+}
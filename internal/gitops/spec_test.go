@@ -0,0 +1,101 @@
+package gitops
+
+import (
+	"strings"
+	"testing"
+)
+
+func lookupFrom(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestParse(t *testing.T) {
+	data := []byte(`
+projects:
+  - name: shop
+    environments:
+      - name: production
+        applications:
+          - name: web
+            image: ghcr.io/acme/web:1.2
+            domains: [shop.example.com]
+            env:
+              API_KEY: ${SHOP_API_KEY}
+              GREETING: "hello $USER"
+              LITERAL: "$${HOME}/data"
+        databases:
+          - name: db
+            engine: postgresql
+`)
+
+	spec, err := Parse(data, lookupFrom(map[string]string{"SHOP_API_KEY": "s3cret"}))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if spec.Version != SpecVersion {
+		t.Errorf("version = %d, want %d", spec.Version, SpecVersion)
+	}
+	app := spec.Projects[0].Environments[0].Applications[0]
+	if app.Env["API_KEY"] != "s3cret" {
+		t.Errorf("API_KEY = %q, want the referenced value", app.Env["API_KEY"])
+	}
+	if app.Env["GREETING"] != "hello $USER" {
+		t.Errorf("GREETING = %q, bare $ should be left alone", app.Env["GREETING"])
+	}
+	if app.Env["LITERAL"] != "${HOME}/data" {
+		t.Errorf("LITERAL = %q, want the escaped reference kept", app.Env["LITERAL"])
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{"empty", ``, "empty"},
+		{"unknown field", "projects:\n  - name: a\n    colour: red\n", "colour"},
+		{"version", "version: 2\n", "unsupported spec version"},
+		{"unset reference", "projects:\n  - name: a\n    environments:\n      - name: e\n        applications:\n          - name: x\n            image: nginx\n            env: {K: '${MISSING}'}\n", "MISSING"},
+		{"no source", "projects:\n  - name: a\n    environments:\n      - name: e\n        applications:\n          - name: x\n", "exactly one of image and repository"},
+		{"two sources", "projects:\n  - name: a\n    environments:\n      - name: e\n        applications:\n          - name: x\n            image: nginx\n            repository: https://github.com/a/b\n", "exactly one of image and repository"},
+		{"duplicate name", "projects:\n  - name: a\n    environments:\n      - name: e\n        applications:\n          - {name: x, image: nginx}\n        databases:\n          - {name: x, engine: redis}\n", "declared twice"},
+		{"engine", "projects:\n  - name: a\n    environments:\n      - name: e\n        databases:\n          - {name: x, engine: oracle}\n", "unknown engine"},
+		{"duplicate project", "projects:\n  - name: a\n  - name: A\n", "declared twice"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.spec), lookupFrom(nil))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	spec := &Spec{
+		Version: SpecVersion,
+		Projects: []Project{{
+			Name: "shop",
+			Environments: []Environment{{
+				Name:         "staging",
+				Applications: []Application{{Name: "web", Repository: "acme/web", GitHubApp: "acme", Env: map[string]string{"A": "1"}}},
+			}},
+		}},
+	}
+
+	data, err := Marshal(spec)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	parsed, err := Parse(data, lookupFrom(nil))
+	if err != nil {
+		t.Fatalf("Parse(Marshal()): %v\n%s", err, data)
+	}
+	if got := parsed.Projects[0].Environments[0].Applications[0]; got.GitHubApp != "acme" || got.Env["A"] != "1" {
+		t.Errorf("round trip lost fields: %+v", got)
+	}
+}
@@ -0,0 +1,348 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+)
+
+// State is a snapshot of the live resources a spec is compared against
+type State struct {
+	Projects     []LiveProject
+	Servers      []api.Server
+	Destinations []api.Destination
+	GitHubApps   []api.GitHubApp
+}
+
+// LiveProject is a project and its environments
+type LiveProject struct {
+	UUID         string
+	Name         string
+	Description  string
+	Environments []LiveEnvironment
+}
+
+// LiveEnvironment is an environment and the resources in it
+type LiveEnvironment struct {
+	ID           int
+	UUID         string
+	Name         string
+	Applications []LiveApplication
+	Databases    []api.Database
+}
+
+// LiveApplication is an application and its environment variables
+type LiveApplication struct {
+	App api.Application
+	Env []api.EnvironmentVariable
+}
+
+// FetchState loads every project with its applications, databases and
+// environment variables, plus the servers and GitHub Apps specs refer to
+func FetchState(client *api.Client) (*State, error) {
+	projects, err := client.ListProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	apps, err := client.ListApplications()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	databases, err := client.ListDatabases()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+
+	state, err := fetchInfrastructure(client)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		// Listed projects don't include their environments
+		project, err := client.GetProject(p.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project %s: %w", p.Name, err)
+		}
+
+		live := LiveProject{UUID: project.UUID, Name: project.Name, Description: project.Description}
+		for _, e := range project.Environments {
+			env, err := fetchEnvironment(client, e, apps, databases)
+			if err != nil {
+				return nil, err
+			}
+			live.Environments = append(live.Environments, *env)
+		}
+		state.Projects = append(state.Projects, live)
+	}
+	return state, nil
+}
+
+// FetchApplicationState loads only the project environment an application
+// belongs to, with its applications, databases and environment variables
+func FetchApplicationState(client *api.Client, appUUID string) (*State, error) {
+	app, err := client.GetApplication(appUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	projects, err := client.ListProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	for _, p := range projects {
+		project, err := client.GetProject(p.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project %s: %w", p.Name, err)
+		}
+		for _, e := range project.Environments {
+			if e.ID != app.EnvironmentID {
+				continue
+			}
+
+			apps, err := client.ListApplications()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list applications: %w", err)
+			}
+			databases, err := client.ListDatabases()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list databases: %w", err)
+			}
+			state, err := fetchInfrastructure(client)
+			if err != nil {
+				return nil, err
+			}
+			env, err := fetchEnvironment(client, e, apps, databases)
+			if err != nil {
+				return nil, err
+			}
+			state.Projects = []LiveProject{{
+				UUID:         project.UUID,
+				Name:         project.Name,
+				Description:  project.Description,
+				Environments: []LiveEnvironment{*env},
+			}}
+			return state, nil
+		}
+	}
+	return nil, fmt.Errorf("can't tell which project %s belongs to", app.Name)
+}
+
+// fetchInfrastructure loads the servers, destinations and GitHub Apps
+// resources refer to
+func fetchInfrastructure(client *api.Client) (*State, error) {
+	state := &State{}
+	var err error
+	if state.Servers, err = client.ListServers(); err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+	if state.Destinations, err = client.ListDestinations(); err != nil {
+		return nil, fmt.Errorf("failed to list destinations: %w", err)
+	}
+	// GitHub Apps are only needed for private repositories
+	state.GitHubApps, _ = client.ListGitHubApps()
+	return state, nil
+}
+
+// fetchEnvironment picks the environment's resources out of the instance-wide
+// lists and loads the environment variables of its applications
+func fetchEnvironment(client *api.Client, e api.Environment, apps []api.Application, databases []api.Database) (*LiveEnvironment, error) {
+	env := &LiveEnvironment{ID: e.ID, UUID: e.UUID, Name: e.Name}
+	for _, a := range apps {
+		if a.EnvironmentID != e.ID {
+			continue
+		}
+		vars, err := client.ListApplicationEnvs(context.Background(), a.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list environment variables of %s: %w", a.Name, err)
+		}
+		env.Applications = append(env.Applications, LiveApplication{App: a, Env: vars})
+	}
+	for _, d := range databases {
+		if d.EnvironmentID == e.ID {
+			env.Databases = append(env.Databases, d)
+		}
+	}
+	return env, nil
+}
+
+// project returns the live project with the given name
+func (s *State) project(name string) *LiveProject {
+	for i := range s.Projects {
+		if strings.EqualFold(s.Projects[i].Name, name) {
+			return &s.Projects[i]
+		}
+	}
+	return nil
+}
+
+// environment returns the project's environment with the given name
+func (p *LiveProject) environment(name string) *LiveEnvironment {
+	for i := range p.Environments {
+		if strings.EqualFold(p.Environments[i].Name, name) {
+			return &p.Environments[i]
+		}
+	}
+	return nil
+}
+
+func (e *LiveEnvironment) application(name string) *LiveApplication {
+	for i := range e.Applications {
+		if e.Applications[i].App.Name == name {
+			return &e.Applications[i]
+		}
+	}
+	return nil
+}
+
+func (e *LiveEnvironment) database(name string) *api.Database {
+	for i := range e.Databases {
+		if e.Databases[i].Name == name {
+			return &e.Databases[i]
+		}
+	}
+	return nil
+}
+
+// resolveServer returns the UUID of the server matching a name or UUID. An
+// empty reference picks the only server.
+func (s *State) resolveServer(ref string) (string, error) {
+	if ref == "" {
+		if len(s.Servers) == 1 {
+			return s.Servers[0].UUID, nil
+		}
+		return "", fmt.Errorf("there are %d servers: set server", len(s.Servers))
+	}
+	for _, srv := range s.Servers {
+		if srv.UUID == ref || srv.Name == ref {
+			return srv.UUID, nil
+		}
+	}
+	return "", fmt.Errorf("server not found: %s", ref)
+}
+
+// resolveGitHubApp returns the UUID of the GitHub App matching a name or UUID
+func (s *State) resolveGitHubApp(ref string) (string, error) {
+	for _, gh := range s.GitHubApps {
+		if gh.UUID == ref || gh.Name == ref {
+			return gh.UUID, nil
+		}
+	}
+	return "", fmt.Errorf("GitHub App not found: %s", ref)
+}
+
+// serverName returns the name of the server behind a destination
+func (s *State) serverName(destinationID int) string {
+	for _, d := range s.Destinations {
+		if d.ID != destinationID {
+			continue
+		}
+		for _, srv := range s.Servers {
+			if srv.UUID == d.ServerUUID {
+				return srv.Name
+			}
+		}
+	}
+	return ""
+}
+
+// githubAppName returns the name of the GitHub App with the given ID
+func (s *State) githubAppName(id int) string {
+	for _, gh := range s.GitHubApps {
+		if gh.ID == id {
+			return gh.Name
+		}
+	}
+	return ""
+}
+
+// specApplication describes a live application in spec form. Variables
+// flagged shown-once, whose values aren't readable, are returned separately.
+func (s *State) specApplication(live *LiveApplication) (Application, []string) {
+	app := &live.App
+	a := Application{
+		Name:             app.Name,
+		Server:           s.serverName(app.DestinationID),
+		Branch:           app.GitBranch,
+		BuildPack:        app.BuildPack,
+		InstallCommand:   app.InstallCommand,
+		BuildCommand:     app.BuildCommand,
+		StartCommand:     app.StartCommand,
+		PublishDirectory: app.PublishDirectory,
+		BaseDirectory:    app.BaseDirectory,
+		Port:             app.PortsExposes,
+		Memory:           app.LimitsMemory,
+		CPUs:             app.LimitsCPUs,
+		Domains:          splitDomains(app.GetFqdn()),
+	}
+	if app.BaseDirectory == "/" {
+		a.BaseDirectory = ""
+	}
+	if app.HealthCheckEnabled {
+		a.HealthCheckPath = app.HealthCheckPath
+	}
+
+	if image := app.GetDockerRegistryImageName(); image != "" {
+		a.Image = image
+		if tag := app.GetDockerRegistryImageTag(); tag != "" {
+			a.Image += ":" + tag
+		}
+		a.Branch = ""
+		a.BuildPack = ""
+	} else {
+		a.Repository = app.GitRepository
+		if id := app.GetSourceID(); id != 0 {
+			a.GitHubApp = s.githubAppName(id)
+		}
+		if a.GitHubApp == "" && app.GetGitFullURL() != "" {
+			a.Repository = app.GetGitFullURL()
+		}
+	}
+
+	var hidden []string
+	for _, e := range live.Env {
+		if e.IsPreview {
+			continue
+		}
+		if e.IsShownOnce {
+			hidden = append(hidden, e.Key)
+			continue
+		}
+		if a.Env == nil {
+			a.Env = map[string]string{}
+		}
+		a.Env[e.Key] = e.Value
+	}
+	sort.Strings(hidden)
+	return a, hidden
+}
+
+// specDatabase describes a live database in spec form
+func (s *State) specDatabase(db *api.Database) Database {
+	d := Database{
+		Name:   db.Name,
+		Engine: db.Engine(),
+		Server: s.serverName(db.DestinationID),
+		Image:  db.Image,
+		Public: db.IsPublic,
+		Memory: db.LimitsMemory,
+		CPUs:   db.LimitsCPUs,
+	}
+	if db.IsPublic {
+		d.PublicPort = db.PublicPort
+	}
+	return d
+}
+
+// splitDomains splits Coolify's comma-separated domain list
+func splitDomains(fqdn string) []string {
+	var domains []string
+	for _, d := range strings.Split(fqdn, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
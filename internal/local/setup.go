@@ -288,11 +288,11 @@ func Reset(force bool) error {
 
 	if !force {
 		ui.Warning("This will delete all data and configuration")
-		confirm, err := ui.InputWithDefault("Are you sure? (yes/no)", "no")
+		confirmed, err := ui.TypeToConfirm("Are you sure?", "yes")
 		if err != nil {
 			return err
 		}
-		if confirm != "yes" {
+		if !confirmed {
 			ui.Info("Reset cancelled")
 			return nil
 		}
@@ -0,0 +1,246 @@
+// Package logview reads log lines for the log viewer and the MCP tools:
+// their level, their timestamp and whether they match a filter.
+package logview
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Level is the severity of a log line
+type Level int
+
+const (
+	LevelNone Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return ""
+}
+
+// ParseLevel reads a level name, like warn or ERROR
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug", "trace":
+		return LevelDebug, nil
+	case "info", "notice":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error", "err", "fatal", "panic", "critical":
+		return LevelError, nil
+	}
+	return LevelNone, fmt.Errorf("unknown level %q: use debug, info, warn or error", s)
+}
+
+// levelWords are the words that mark the level of a line, as a word of their
+// own like "ERROR" or "[warn]", or as a key like level=error
+var levelWords = regexp.MustCompile(`(?i)\b(trace|debug|info|notice|warn|warning|error|err|fatal|panic|critical|exception)\b`)
+
+// DetectLevel returns the level a line is logged at, from the first level
+// word in it, or LevelNone
+func DetectLevel(line string) Level {
+	for _, loc := range levelWords.FindAllStringIndex(line, 3) {
+		word := line[loc[0]:loc[1]]
+		lower := strings.ToLower(word)
+		if lower == "exception" {
+			return LevelError
+		}
+		// "info" and "error" are common in messages too, so lower case
+		// words count only when they look like a level field
+		if word != strings.ToUpper(word) && !looksLikeField(line[:loc[0]], line[loc[1]:]) {
+			continue
+		}
+		if level, err := ParseLevel(lower); err == nil {
+			return level
+		}
+	}
+	return LevelNone
+}
+
+// looksLikeField reports a level word between before and after written as
+// [warn], level=warn, "level":"warn" or warn: at the start of the line
+func looksLikeField(before, after string) bool {
+	before = strings.TrimRight(before, `"`)
+	switch {
+	case strings.HasSuffix(before, "[") && strings.HasPrefix(after, "]"):
+		return true
+	case strings.HasSuffix(before, "=") || strings.HasSuffix(before, ":"):
+		return true
+	case strings.TrimSpace(before) == "" && strings.HasPrefix(after, ":"):
+		return true
+	}
+	return false
+}
+
+// timestampLayouts are the layouts of the timestamps lines start with
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+}
+
+var leadingTimestamp = regexp.MustCompile(`^\[?(\d{4}[-/]\d{2}[-/]\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?)\]?`)
+
+// Timestamp returns the time a line starts with, if any
+func Timestamp(line string) (time.Time, bool) {
+	m := leadingTimestamp.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return time.Time{}, false
+	}
+	value := strings.Replace(m[1], ",", ".", 1)
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ParseTime reads a time to jump to: a full timestamp, a date and time, or
+// only a time of day like 14:05, which is taken on the day of ref
+func ParseTime(s string, ref time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, ok := Timestamp(s); ok {
+		return t, nil
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			y, mo, d := ref.Date()
+			return time.Date(y, mo, d, t.Hour(), t.Minute(), t.Second(), 0, ref.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't read %q as a time: use 14:05, 14:05:30 or 2006-01-02T14:05:30Z", s)
+}
+
+// FirstAt returns the index of the first line logged at or after t, or -1
+// if every line is older
+func FirstAt(lines []string, t time.Time) int {
+	for i, line := range lines {
+		if ts, ok := Timestamp(line); ok && !ts.Before(t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastTimestamp returns the time of the last line that has one
+func LastTimestamp(lines []string) (time.Time, bool) {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if ts, ok := Timestamp(lines[i]); ok {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Filter selects log lines
+type Filter struct {
+	Pattern  *regexp.Regexp // nil matches every line
+	MinLevel Level          // LevelNone matches every line
+	Since    time.Time      // zero matches every line
+}
+
+// CompilePattern compiles a search pattern. A pattern without upper case
+// letters ignores case.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if strings.ToLower(pattern) == pattern {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
+// Levels returns the level of each line. A line without a level or a
+// timestamp of its own takes the line above's, so the lines of a stack trace
+// share the level of its message.
+func Levels(lines []string) []Level {
+	levels := make([]Level, len(lines))
+	level := LevelNone
+	for i, line := range lines {
+		if l := DetectLevel(line); l != LevelNone || leadingTimestamp.MatchString(strings.TrimSpace(line)) {
+			level = l
+		}
+		levels[i] = level
+	}
+	return levels
+}
+
+// Apply returns the lines that match the filter. Like levels, a line without
+// a timestamp takes the time of the line above.
+func (f Filter) Apply(lines []string) []string {
+	var matched []string
+	levels := Levels(lines)
+	sinceOK := f.Since.IsZero()
+	for i, line := range lines {
+		if ts, ok := Timestamp(line); ok && !f.Since.IsZero() {
+			sinceOK = !ts.Before(f.Since)
+		}
+		if !sinceOK || levels[i] < f.MinLevel {
+			continue
+		}
+		if f.Pattern != nil && !f.Pattern.MatchString(line) {
+			continue
+		}
+		matched = append(matched, line)
+	}
+	return matched
+}
+
+// Matches returns the indexes of the lines the pattern matches, in order
+func Matches(lines []string, re *regexp.Regexp) []int {
+	if re == nil {
+		return nil
+	}
+	var matches []int
+	for i, line := range lines {
+		if re.MatchString(line) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// NextMatch returns the first of matches after line, wrapping around, or
+// the last one before it when backward. It returns -1 without matches.
+func NextMatch(matches []int, line int, backward bool) int {
+	if len(matches) == 0 {
+		return -1
+	}
+	if backward {
+		i := sort.SearchInts(matches, line) - 1
+		if i < 0 {
+			i = len(matches) - 1
+		}
+		return matches[i]
+	}
+	i := sort.SearchInts(matches, line+1)
+	if i == len(matches) {
+		i = 0
+	}
+	return matches[i]
+}
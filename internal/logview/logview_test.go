@@ -0,0 +1,117 @@
+package logview
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectLevel(t *testing.T) {
+	for line, want := range map[string]Level{
+		"2024-05-01T10:00:00Z ERROR connection refused": LevelError,
+		"[warn] disk almost full":                       LevelWarn,
+		`{"level":"info","msg":"listening"}`:            LevelInfo,
+		"level=debug msg=tick":                          LevelDebug,
+		"error: build failed":                           LevelError,
+		"npm WARN deprecated inflight@1.0.6":            LevelWarn,
+		"Unhandled exception in worker":                 LevelError,
+		"No errors found, some info for you":            LevelNone,
+		"Sent 0 error reports":                          LevelNone,
+	} {
+		if got := DetectLevel(line); got != want {
+			t.Errorf("DetectLevel(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	want := time.Date(2024, 5, 1, 10, 15, 30, 0, time.UTC)
+	for _, line := range []string{
+		"2024-05-01T10:15:30Z started",
+		"2024-05-01T10:15:30.000000000Z started",
+		"[2024-05-01 10:15:30] started",
+		"2024/05/01 10:15:30 started",
+		"2024-05-01 10:15:30,000 started",
+	} {
+		got, ok := Timestamp(line)
+		if !ok || !got.Equal(want) {
+			t.Errorf("Timestamp(%q) = %v, %t", line, got, ok)
+		}
+	}
+	if _, ok := Timestamp("started at 2024-05-01T10:15:30Z"); ok {
+		t.Error("read a timestamp that doesn't start the line")
+	}
+}
+
+func TestParseTimeAndFirstAt(t *testing.T) {
+	lines := []string{
+		"2024-05-01T10:00:00Z boot",
+		"  continued",
+		"2024-05-01T10:05:00Z ready",
+		"2024-05-01T10:10:00Z request",
+	}
+	ref, _ := LastTimestamp(lines)
+	at, err := ParseTime("10:04", ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i := FirstAt(lines, at); i != 2 {
+		t.Errorf("FirstAt(10:04) = %d, want 2", i)
+	}
+	if i := FirstAt(lines, at.Add(time.Hour)); i != -1 {
+		t.Errorf("FirstAt(11:04) = %d, want -1", i)
+	}
+	if _, err := ParseTime("soon", ref); err == nil {
+		t.Error("read soon as a time")
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	lines := []string{
+		"2024-05-01T10:00:00Z INFO boot",
+		"2024-05-01T10:01:00Z ERROR panic in handler",
+		"  at handler.go:12",
+		"2024-05-01T10:02:00Z INFO request /health",
+		"2024-05-01T10:03:00Z WARN slow request /api",
+	}
+
+	got := Filter{MinLevel: LevelWarn}.Apply(lines)
+	if len(got) != 3 || !strings.Contains(got[1], "handler.go") {
+		t.Errorf("MinLevel warn = %q", got)
+	}
+
+	since, _ := ParseTime("10:02", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	if got := (Filter{Since: since}).Apply(lines); len(got) != 2 {
+		t.Errorf("Since 10:02 = %q", got)
+	}
+
+	re, err := CompilePattern("request /(health|api)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := (Filter{Pattern: re}).Apply(lines); len(got) != 2 {
+		t.Errorf("Pattern = %q", got)
+	}
+	if _, err := CompilePattern("("); err == nil {
+		t.Error("compiled an invalid pattern")
+	}
+}
+
+func TestNextMatch(t *testing.T) {
+	matches := []int{2, 5, 9}
+	for _, tt := range []struct {
+		line     int
+		backward bool
+		want     int
+	}{
+		{0, false, 2}, {2, false, 5}, {9, false, 2},
+		{5, true, 2}, {2, true, 9}, {10, true, 9},
+	} {
+		if got := NextMatch(matches, tt.line, tt.backward); got != tt.want {
+			t.Errorf("NextMatch(%d, %t) = %d, want %d", tt.line, tt.backward, got, tt.want)
+		}
+	}
+	if NextMatch(nil, 0, false) != -1 {
+		t.Error("found a match in none")
+	}
+}
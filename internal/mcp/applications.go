@@ -0,0 +1,330 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+)
+
+// applicationTools are the tools for applications and their deployments
+func applicationTools() []*tool {
+	return []*tool{
+		{
+			Name:        "list_applications",
+			Description: "List all Coolify applications with their UUIDs and names",
+			InputSchema: objectSchema(map[string]interface{}{}),
+			call:        (*MCPServer).listApplications,
+		},
+		{
+			Name:        "get_application",
+			Description: "Get details of a specific Coolify application by UUID",
+			InputSchema: uuidSchema("Application UUID"),
+			call:        (*MCPServer).getApplication,
+		},
+		{
+			Name:        "get_application_logs",
+			Description: "Retrieve logs for a Coolify application for debugging purposes. Large logs are paged: when next_cursor is set, call again with it as cursor for the following page.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"uuid":      property("string", "Application UUID"),
+				"lines":     property("integer", "Number of recent lines to retrieve from Coolify (default: 100)"),
+				"grep_text": property("string", "Text to filter/grep in logs, applied before paging"),
+				"level":     property("string", "Only lines at this level or above: debug, info, warn or error. Lines of a stack trace keep the level of its message"),
+				"since":     property("string", "Only lines logged at or after this time, as 14:05, 14:05:30 or an RFC 3339 timestamp"),
+				"max_lines": property("integer", "Maximum number of lines per page (default: no limit)"),
+				"max_bytes": property("integer", fmt.Sprintf("Maximum size of a page in bytes (default: %d)", defaultLogBytes)),
+				"from":      property("string", `"tail" to page from the newest lines (default) or "head" from the oldest`),
+				"cursor":    property("string", "next_cursor of the previous page"),
+			}, "uuid"),
+			call: (*MCPServer).getApplicationLogs,
+		},
+		{
+			Name:        "start_application",
+			Description: "Start a Coolify application",
+			InputSchema: uuidSchema("Application UUID"),
+			Mutating:    true,
+			call:        (*MCPServer).startApplication,
+		},
+		{
+			Name:        "stop_application",
+			Description: "Stop a Coolify application",
+			InputSchema: uuidSchema("Application UUID"),
+			Mutating:    true,
+			call:        (*MCPServer).stopApplication,
+		},
+		{
+			Name:        "restart_application",
+			Description: "Restart a Coolify application",
+			InputSchema: uuidSchema("Application UUID"),
+			Mutating:    true,
+			call:        (*MCPServer).restartApplication,
+		},
+		{
+			Name:        "deploy_application",
+			Description: "Trigger a deployment for a Coolify application",
+			InputSchema: objectSchema(map[string]interface{}{
+				"uuid":  property("string", "Application UUID"),
+				"force": property("boolean", "Force rebuild without cache"),
+			}, "uuid"),
+			Mutating: true,
+			call:     (*MCPServer).deployApplication,
+		},
+		{
+			Name:        "list_deployments",
+			Description: "List deployments for a Coolify application",
+			InputSchema: objectSchema(map[string]interface{}{
+				"app_uuid": property("string", "Application UUID"),
+			}, "app_uuid"),
+			call: (*MCPServer).listDeployments,
+		},
+		{
+			Name:        "get_deployment",
+			Description: "Get details and logs of a specific deployment",
+			InputSchema: objectSchema(map[string]interface{}{
+				"deployment_uuid": property("string", "Deployment UUID"),
+			}, "deployment_uuid"),
+			call: (*MCPServer).getDeployment,
+		},
+	}
+}
+
+func (s *MCPServer) listApplications(args json.RawMessage) (interface{}, error) {
+	apps, err := s.client.ListApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, app := range apps {
+		var fqdn string
+		if app.Fqdn != nil {
+			fqdn = *app.Fqdn
+		}
+		result = append(result, map[string]interface{}{
+			"uuid":   app.UUID,
+			"name":   app.Name,
+			"status": app.Status,
+			"fqdn":   fqdn,
+		})
+	}
+	return result, nil
+}
+
+func (s *MCPServer) getApplication(args json.RawMessage) (interface{}, error) {
+	var a uuidArgs
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+
+	app, err := s.client.GetApplication(a.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fqdn string
+	if app.Fqdn != nil {
+		fqdn = *app.Fqdn
+	}
+
+	return map[string]interface{}{
+		"uuid":       app.UUID,
+		"name":       app.Name,
+		"status":     app.Status,
+		"fqdn":       fqdn,
+		"git_repo":   app.GitRepository,
+		"git_branch": app.GitBranch,
+	}, nil
+}
+
+func (s *MCPServer) getApplicationLogs(args json.RawMessage) (interface{}, error) {
+	var a struct {
+		UUID     string `json:"uuid"`
+		Lines    int    `json:"lines"`
+		GrepText string `json:"grep_text"`
+		Level    string `json:"level"`
+		Since    string `json:"since"`
+		MaxLines int    `json:"max_lines"`
+		MaxBytes int    `json:"max_bytes"`
+		From     string `json:"from"`
+		Cursor   string `json:"cursor"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if a.Lines <= 0 {
+		a.Lines = 100
+	}
+	pager, err := newLogPager(a.From, a.Cursor, a.MaxLines, a.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.client.GetApplicationLogs(context.Background(), a.UUID, a.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(logs.Logs, "\n"), "\n")
+	if logs.Logs == "" {
+		lines = nil
+	}
+	// Filter first so pages only hold matching lines
+	lines, err = filterLogs(lines, a.Level, a.Since)
+	if err != nil {
+		return nil, err
+	}
+	if a.GrepText != "" {
+		lines = grepLines(lines, a.GrepText)
+	}
+	page, next := pager.page(lines)
+
+	result := map[string]interface{}{
+		"uuid":           a.UUID,
+		"logs":           strings.Join(page, "\n"),
+		"total_lines":    len(lines),
+		"returned_lines": len(page),
+		"truncated":      next != "",
+	}
+	if next != "" {
+		result["next_cursor"] = next
+	}
+	return result, nil
+}
+
+func (s *MCPServer) startApplication(args json.RawMessage) (interface{}, error) {
+	var a uuidArgs
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.StartApplication(context.Background(), a.UUID, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message":         resp.Message,
+		"deployment_uuid": resp.DeploymentUUID,
+	}, nil
+}
+
+func (s *MCPServer) stopApplication(args json.RawMessage) (interface{}, error) {
+	var a uuidArgs
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.StopApplication(context.Background(), a.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message": resp.Message,
+	}, nil
+}
+
+func (s *MCPServer) restartApplication(args json.RawMessage) (interface{}, error) {
+	var a uuidArgs
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.RestartApplication(context.Background(), a.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message":         resp.Message,
+		"deployment_uuid": resp.DeploymentUUID,
+	}, nil
+}
+
+func (s *MCPServer) deployApplication(args json.RawMessage) (interface{}, error) {
+	var a struct {
+		UUID  string `json:"uuid"`
+		Force bool   `json:"force"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+
+	// Deploy the branch, not the commit a rollback pinned
+	if err := s.client.UnpinCommit(a.UUID); err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Deploy(a.UUID, a.Force, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// DeployResponse contains array of deployments
+	var message, deploymentUUID string
+	if len(resp.Deployments) > 0 {
+		message = resp.Deployments[0].Message
+		deploymentUUID = resp.Deployments[0].DeploymentUUID
+	}
+
+	return map[string]interface{}{
+		"message":         message,
+		"deployment_uuid": deploymentUUID,
+	}, nil
+}
+
+func (s *MCPServer) listDeployments(args json.RawMessage) (interface{}, error) {
+	var a struct {
+		AppUUID string `json:"app_uuid"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+
+	deployments, err := s.client.ListDeployments(a.AppUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, d := range deployments {
+		result = append(result, map[string]interface{}{
+			"uuid":            d.UUID,
+			"deployment_uuid": d.DeploymentUUID,
+			"status":          d.Status,
+			"commit":          d.Commit,
+			"commit_message":  d.CommitMessage,
+			"created_at":      d.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *MCPServer) getDeployment(args json.RawMessage) (interface{}, error) {
+	var a struct {
+		DeploymentUUID string `json:"deployment_uuid"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+
+	deployment, err := s.client.GetDeployment(a.DeploymentUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse logs for readability
+	parsedLogs := api.ParseLogs(deployment.Logs)
+
+	return map[string]interface{}{
+		"deployment_uuid":  deployment.DeploymentUUID,
+		"status":           deployment.Status,
+		"application_name": deployment.ApplicationName,
+		"server_name":      deployment.ServerName,
+		"commit":           deployment.Commit,
+		"commit_message":   deployment.CommitMessage,
+		"created_at":       deployment.CreatedAt,
+		"logs":             parsedLogs,
+	}, nil
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+
+	// forbiddenCode is the error code of calls the policy forbids
+	forbiddenCode = -32001
+)
+
+// request is a JSON-RPC 2.0 request, or a notification when ID is empty
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// isNotification reports whether the client expects no response
+func (r *request) isNotification() bool {
+	return len(r.ID) == 0
+}
+
+// response is a JSON-RPC 2.0 response. ID is the request's ID as it was
+// sent, a number or a string, or null if it couldn't be read.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object. Handlers return it to fail the
+// request itself rather than report a tool error.
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// invalidParams returns an "Invalid params" error explaining what's wrong
+func invalidParams(format string, args ...interface{}) *rpcError {
+	return &rpcError{Code: codeInvalidParams, Message: "Invalid params: " + fmt.Sprintf(format, args...)}
+}
+
+var nullID = json.RawMessage("null")
+
+// parseRequest validates a single JSON-RPC message
+func parseRequest(raw json.RawMessage) (*request, *rpcError) {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, &rpcError{Code: codeInvalidRequest, Message: "Invalid Request"}
+	}
+	if req.JSONRPC != "2.0" {
+		return &req, &rpcError{Code: codeInvalidRequest, Message: `Invalid Request: jsonrpc must be "2.0"`}
+	}
+	if req.Method == "" {
+		return &req, &rpcError{Code: codeInvalidRequest, Message: "Invalid Request: method is required"}
+	}
+	if !req.isNotification() && !validID(req.ID) {
+		return &req, &rpcError{Code: codeInvalidRequest, Message: "Invalid Request: id must be a string or a number"}
+	}
+	return &req, nil
+}
+
+// validID reports whether id is a string or a number
+func validID(id json.RawMessage) bool {
+	var v interface{}
+	if err := json.Unmarshal(id, &v); err != nil {
+		return false
+	}
+	switch v.(type) {
+	case string, float64:
+		return true
+	}
+	return false
+}
+
+// isBatch reports whether a message is a JSON array of requests
+func isBatch(msg []byte) bool {
+	trimmed := bytes.TrimLeft(msg, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// decodeParams unmarshals params into v, treating missing params as an
+// empty object
+func decodeParams(params json.RawMessage, v interface{}) *rpcError {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return invalidParams("%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/logview"
+)
+
+// defaultLogBytes keeps a page of logs well inside an assistant's context
+const defaultLogBytes = 32 * 1024
+
+// logPager picks a page of log lines that fits a line and byte budget,
+// reading from the start (head) or the end (tail) of the logs
+type logPager struct {
+	FromTail bool
+	Offset   int // lines already returned by previous pages
+	MaxLines int // 0 for no line limit
+	MaxBytes int
+}
+
+// newLogPager reads the paging arguments of get_application_logs. A cursor
+// from a previous page takes over its direction and offset.
+func newLogPager(from, cursor string, maxLines, maxBytes int) (*logPager, error) {
+	p := &logPager{FromTail: true, MaxLines: maxLines, MaxBytes: maxBytes}
+	if p.MaxBytes <= 0 {
+		p.MaxBytes = defaultLogBytes
+	}
+	if p.MaxLines < 0 {
+		return nil, invalidParams("max_lines can't be negative")
+	}
+
+	switch from {
+	case "", "tail":
+	case "head":
+		p.FromTail = false
+	default:
+		return nil, invalidParams(`from must be "head" or "tail"`)
+	}
+
+	if cursor != "" {
+		direction, offset, ok := strings.Cut(cursor, ":")
+		n, err := strconv.Atoi(offset)
+		if !ok || err != nil || n < 0 || (direction != "head" && direction != "tail") {
+			return nil, invalidParams("invalid cursor %q", cursor)
+		}
+		p.FromTail = direction == "tail"
+		p.Offset = n
+	}
+	return p, nil
+}
+
+// page returns the lines of the page in log order, and the cursor of the
+// next page, or "" if this is the last one
+func (p *logPager) page(lines []string) ([]string, string) {
+	remaining := len(lines) - p.Offset
+	if remaining <= 0 {
+		return nil, ""
+	}
+
+	var picked []string
+	size := 0
+	for i := 0; i < remaining; i++ {
+		if p.MaxLines > 0 && len(picked) == p.MaxLines {
+			break
+		}
+		line := lines[p.Offset+i]
+		if p.FromTail {
+			line = lines[remaining-1-i]
+		}
+		if size+len(line)+1 > p.MaxBytes {
+			if len(picked) > 0 {
+				break
+			}
+			// A single line over the budget is cut rather than never shown
+			line = strings.ToValidUTF8(line[:max(p.MaxBytes-len(truncatedMarker), 0)], "") + truncatedMarker
+		}
+		picked = append(picked, line)
+		size += len(line) + 1
+	}
+
+	if p.FromTail {
+		for i, j := 0, len(picked)-1; i < j; i, j = i+1, j-1 {
+			picked[i], picked[j] = picked[j], picked[i]
+		}
+	}
+
+	next := ""
+	if p.Offset+len(picked) < len(lines) {
+		direction := "head"
+		if p.FromTail {
+			direction = "tail"
+		}
+		next = fmt.Sprintf("%s:%d", direction, p.Offset+len(picked))
+	}
+	return picked, next
+}
+
+// truncatedMarker ends a line cut to fit the byte budget
+const truncatedMarker = " …[truncated]"
+
+// grepLines keeps the lines containing text
+func grepLines(lines []string, text string) []string {
+	var matched []string
+	for _, line := range lines {
+		if strings.Contains(line, text) {
+			matched = append(matched, line)
+		}
+	}
+	return matched
+}
+
+// filterLogs keeps the lines at level or above logged since the given time,
+// either of which may be empty. A since of only a time of day, like 14:05,
+// is taken on the day of the last line.
+func filterLogs(lines []string, level, since string) ([]string, error) {
+	var filter logview.Filter
+	if level != "" {
+		l, err := logview.ParseLevel(level)
+		if err != nil {
+			return nil, invalidParams("%v", err)
+		}
+		filter.MinLevel = l
+	}
+	if since != "" {
+		ref, ok := logview.LastTimestamp(lines)
+		if !ok {
+			ref = time.Now()
+		}
+		t, err := logview.ParseTime(since, ref)
+		if err != nil {
+			return nil, invalidParams("%v", err)
+		}
+		filter.Since = t
+	}
+	if filter.MinLevel == logview.LevelNone && filter.Since.IsZero() {
+		return lines, nil
+	}
+	return filter.Apply(lines), nil
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogPagerTail(t *testing.T) {
+	lines := []string{"one", "two", "three", "four", "five"}
+
+	pager, err := newLogPager("", "", 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, next := pager.page(lines)
+	if got := strings.Join(page, ","); got != "four,five" || next != "tail:2" {
+		t.Fatalf("first page = %q, %q", got, next)
+	}
+
+	var all []string
+	all = append(page, all...)
+	for next != "" {
+		pager, err = newLogPager("", next, 2, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		page, next = pager.page(lines)
+		all = append(page, all...)
+	}
+	if got := strings.Join(all, ","); got != strings.Join(lines, ",") {
+		t.Errorf("pages put together = %q", got)
+	}
+}
+
+func TestLogPagerHeadBytes(t *testing.T) {
+	lines := []string{"aaaa", "bbbb", "cccc"}
+
+	// Each line takes five bytes with its newline
+	pager, err := newLogPager("head", "", 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, next := pager.page(lines)
+	if got := strings.Join(page, ","); got != "aaaa,bbbb" || next != "head:2" {
+		t.Fatalf("first page = %q, %q", got, next)
+	}
+
+	pager, _ = newLogPager("tail", next, 0, 10)
+	page, next = pager.page(lines)
+	if got := strings.Join(page, ","); got != "cccc" || next != "" {
+		t.Errorf("cursor didn't keep paging from the head: %q, %q", got, next)
+	}
+}
+
+func TestLogPagerLongLine(t *testing.T) {
+	pager, _ := newLogPager("", "", 0, 20)
+	page, next := pager.page([]string{strings.Repeat("x", 100)})
+	if len(page) != 1 || len(page[0]) > 20 || !strings.HasSuffix(page[0], truncatedMarker) || next != "" {
+		t.Errorf("long line page = %q, %q", page, next)
+	}
+}
+
+func TestLogPagerInvalid(t *testing.T) {
+	for _, args := range [][]string{{"middle", ""}, {"", "tail"}, {"", "up:3"}, {"", "head:-1"}} {
+		if _, err := newLogPager(args[0], args[1], 0, 0); err == nil {
+			t.Errorf("from %q, cursor %q accepted", args[0], args[1])
+		}
+	}
+}
+
+func TestFilterLogs(t *testing.T) {
+	lines := []string{
+		"2024-05-01T10:00:00Z INFO listening on 3000",
+		"2024-05-01T10:01:00Z ERROR query failed",
+		"    at db.go:42",
+		"2024-05-01T10:02:00Z WARN slow request",
+	}
+
+	got, err := filterLogs(lines, "warn", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, "\n") != strings.Join(lines[1:], "\n") {
+		t.Errorf("level warn = %q", got)
+	}
+
+	got, _ = filterLogs(lines, "", "10:02")
+	if len(got) != 1 || !strings.Contains(got[0], "slow request") {
+		t.Errorf("since 10:02 = %q", got)
+	}
+
+	if got, _ := filterLogs(lines, "", ""); len(got) != len(lines) {
+		t.Errorf("no filter dropped lines: %q", got)
+	}
+	if _, err := filterLogs(lines, "loud", ""); err == nil {
+		t.Error("unknown level accepted")
+	}
+	if _, err := filterLogs(lines, "", "yesterday"); err == nil {
+		t.Error("unreadable since accepted")
+	}
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Policy limits the tools the server offers. The zero value allows every
+// tool.
+type Policy struct {
+	// ReadOnly forbids the tools that change anything
+	ReadOnly bool
+	// AllowedTools are the only tools allowed when set
+	AllowedTools []string
+}
+
+// Validate reports tool names in the allowlist that don't exist
+func (p Policy) Validate() error {
+	known := toolNames()
+	var unknown []string
+	for _, name := range p.AllowedTools {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown MCP tools in allowlist: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// allows returns why the policy forbids the tool, or nil
+func (p Policy) allows(t *tool) error {
+	name := t.Name
+	if p.ReadOnly && t.Mutating {
+		return fmt.Errorf("tool %s is forbidden by policy: the server is read-only", name)
+	}
+	if len(p.AllowedTools) == 0 {
+		return nil
+	}
+	for _, allowed := range p.AllowedTools {
+		if allowed == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("tool %s is forbidden by policy: it isn't in the allowlist", name)
+}
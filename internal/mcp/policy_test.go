@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPolicyAllows(t *testing.T) {
+	readOnly := Policy{ReadOnly: true}
+	if err := readOnly.allows(findTool("get_application_logs")); err != nil {
+		t.Errorf("read-only policy forbids get_application_logs: %v", err)
+	}
+	if err := readOnly.allows(findTool("deploy_application")); err == nil {
+		t.Error("read-only policy allows deploy_application")
+	}
+
+	allowlist := Policy{ReadOnly: true, AllowedTools: []string{"list_applications", "restart_service"}}
+	if err := allowlist.allows(findTool("list_applications")); err != nil {
+		t.Errorf("allowlisted tool forbidden: %v", err)
+	}
+	if err := allowlist.allows(findTool("get_application")); err == nil {
+		t.Error("tool outside the allowlist allowed")
+	}
+	if err := allowlist.allows(findTool("restart_service")); err == nil {
+		t.Error("read-only allowed an allowlisted mutating tool")
+	}
+
+	for _, tool := range allTools() {
+		if err := (Policy{}).allows(tool); err != nil {
+			t.Errorf("zero policy forbids %s: %v", tool.Name, err)
+		}
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	if err := (Policy{AllowedTools: []string{"list_databases", "get_deployment"}}).Validate(); err != nil {
+		t.Errorf("valid allowlist rejected: %v", err)
+	}
+	if err := (Policy{AllowedTools: []string{"list_databases", "drop_database"}}).Validate(); err == nil {
+		t.Error("unknown tool accepted")
+	}
+}
+
+func TestReadOnlyTools(t *testing.T) {
+	for _, tool := range allTools() {
+		readOnly := strings.HasPrefix(tool.Name, "list_") || strings.HasPrefix(tool.Name, "get_") || strings.HasPrefix(tool.Name, "search_")
+		if tool.Mutating == readOnly {
+			t.Errorf("%s: Mutating = %v", tool.Name, tool.Mutating)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+)
+
+// resourceTools are the tools for databases and services, the backing
+// resources applications depend on
+func resourceTools() []*tool {
+	return []*tool{
+		{
+			Name:        "list_databases",
+			Description: "List all Coolify databases with their engine, image and status",
+			InputSchema: objectSchema(map[string]interface{}{}),
+			call:        (*MCPServer).listDatabases,
+		},
+		{
+			Name:        "get_database",
+			Description: "Get details of a specific Coolify database by UUID, including its resource limits and public exposure",
+			InputSchema: uuidSchema("Database UUID"),
+			call:        (*MCPServer).getDatabase,
+		},
+		{
+			Name:        "start_database",
+			Description: "Start a Coolify database",
+			InputSchema: uuidSchema("Database UUID"),
+			Mutating:    true,
+			call:        resourceAction((*api.Client).StartDatabase),
+		},
+		{
+			Name:        "stop_database",
+			Description: "Stop a Coolify database",
+			InputSchema: uuidSchema("Database UUID"),
+			Mutating:    true,
+			call:        resourceAction((*api.Client).StopDatabase),
+		},
+		{
+			Name:        "restart_database",
+			Description: "Restart a Coolify database",
+			InputSchema: uuidSchema("Database UUID"),
+			Mutating:    true,
+			call:        resourceAction((*api.Client).RestartDatabase),
+		},
+		{
+			Name:        "list_services",
+			Description: "List all Coolify services (like Redis, MinIO or RabbitMQ) with the status of each of their containers",
+			InputSchema: objectSchema(map[string]interface{}{}),
+			call:        (*MCPServer).listServices,
+		},
+		{
+			Name:        "restart_service",
+			Description: "Restart a Coolify service and all of its containers",
+			InputSchema: uuidSchema("Service UUID"),
+			Mutating:    true,
+			call:        resourceAction((*api.Client).RestartService),
+		},
+	}
+}
+
+func (s *MCPServer) listDatabases(args json.RawMessage) (interface{}, error) {
+	databases, err := s.client.ListDatabases()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, db := range databases {
+		result = append(result, map[string]interface{}{
+			"uuid":   db.UUID,
+			"name":   db.Name,
+			"engine": db.Engine(),
+			"image":  db.Image,
+			"status": db.Status,
+		})
+	}
+	return result, nil
+}
+
+func (s *MCPServer) getDatabase(args json.RawMessage) (interface{}, error) {
+	var a uuidArgs
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+
+	db, err := s.client.GetDatabase(a.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The connection URLs hold the database's credentials, so they're left out
+	return map[string]interface{}{
+		"uuid":          db.UUID,
+		"name":          db.Name,
+		"description":   db.Description,
+		"engine":        db.Engine(),
+		"image":         db.Image,
+		"status":        db.Status,
+		"is_public":     db.IsPublic,
+		"public_port":   db.PublicPort,
+		"limits_memory": db.LimitsMemory,
+		"limits_cpus":   db.LimitsCPUs,
+	}, nil
+}
+
+// resourceAction is the handler of a start, stop or restart call on the
+// resource in the uuid argument
+func resourceAction(action func(c *api.Client, uuid string) (*api.MessageResponse, error)) func(*MCPServer, json.RawMessage) (interface{}, error) {
+	return func(s *MCPServer, args json.RawMessage) (interface{}, error) {
+		var a uuidArgs
+		if err := decodeArgs(args, &a); err != nil {
+			return nil, err
+		}
+
+		resp, err := action(s.client, a.UUID)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{
+			"message": resp.Message,
+		}, nil
+	}
+}
+
+func (s *MCPServer) listServices(args json.RawMessage) (interface{}, error) {
+	services, err := s.client.ListServices()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, svc := range services {
+		var containers []map[string]interface{}
+		for _, app := range svc.Applications {
+			containers = append(containers, map[string]interface{}{
+				"name":   app.Name,
+				"image":  app.Image,
+				"status": app.Status,
+			})
+		}
+		for _, db := range svc.Databases {
+			containers = append(containers, map[string]interface{}{
+				"name":   db.Name,
+				"image":  db.Image,
+				"status": db.Status,
+			})
+		}
+		result = append(result, map[string]interface{}{
+			"uuid":       svc.UUID,
+			"name":       svc.Name,
+			"type":       svc.Type,
+			"status":     svc.Status,
+			"containers": containers,
+		})
+	}
+	return result, nil
+}
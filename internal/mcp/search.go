@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+)
+
+const (
+	// searchParallel is how many applications' logs are fetched at once
+	searchParallel = 5
+	// defaultSearchMatches keeps a search's result inside an assistant's context
+	defaultSearchMatches = 100
+)
+
+// searchTool greps the logs of many applications at once
+func searchTool() *tool {
+	return &tool{
+		Name:        "search_logs",
+		Description: "Search the recent logs of all running Coolify applications (or some of them) for a pattern and return the matching lines with their application, to find where an error comes from across services",
+		InputSchema: objectSchema(map[string]interface{}{
+			"pattern": property("string", "Text to look for, or a regular expression with regex"),
+			"regex":   property("boolean", "Treat pattern as a regular expression (RE2 syntax)"),
+			"uuids": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Only search these applications",
+			},
+			"name":        property("string", "Only search applications whose name contains this text"),
+			"lines":       property("integer", "Number of recent lines to search per application (default: 200)"),
+			"max_matches": property("integer", "Maximum number of matching lines to return (default: 100)"),
+		}, "pattern"),
+		call: (*MCPServer).searchLogs,
+	}
+}
+
+// logMatch is a log line that matched a search
+type logMatch struct {
+	AppUUID string `json:"app_uuid"`
+	AppName string `json:"app_name"`
+	Line    string `json:"line"`
+}
+
+// searchFailure is an application whose logs couldn't be searched
+type searchFailure struct {
+	AppUUID string `json:"app_uuid"`
+	AppName string `json:"app_name"`
+	Error   string `json:"error"`
+}
+
+func (s *MCPServer) searchLogs(args json.RawMessage) (interface{}, error) {
+	var a struct {
+		Pattern    string   `json:"pattern"`
+		Regex      bool     `json:"regex"`
+		UUIDs      []string `json:"uuids"`
+		Name       string   `json:"name"`
+		Lines      int      `json:"lines"`
+		MaxMatches int      `json:"max_matches"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if a.Lines <= 0 {
+		a.Lines = 200
+	}
+	if a.MaxMatches <= 0 {
+		a.MaxMatches = defaultSearchMatches
+	}
+
+	match := func(line string) bool { return strings.Contains(line, a.Pattern) }
+	if a.Regex {
+		re, err := regexp.Compile(a.Pattern)
+		if err != nil {
+			return nil, invalidParams("pattern: %v", err)
+		}
+		match = re.MatchString
+	}
+
+	apps, err := s.client.ListApplications()
+	if err != nil {
+		return nil, err
+	}
+	apps, skipped := searchTargets(apps, a.UUIDs, a.Name)
+
+	// Each application's matches are kept apart so the result follows the
+	// order of the applications, not of the responses
+	matches := make([][]logMatch, len(apps))
+	failures := make([]*searchFailure, len(apps))
+	g := new(errgroup.Group)
+	g.SetLimit(searchParallel)
+	for i, app := range apps {
+		g.Go(func() error {
+			logs, err := s.client.GetApplicationLogs(context.Background(), app.UUID, a.Lines)
+			if err != nil {
+				failures[i] = &searchFailure{AppUUID: app.UUID, AppName: app.Name, Error: err.Error()}
+				return nil
+			}
+			for _, line := range strings.Split(logs.Logs, "\n") {
+				if line != "" && match(line) {
+					matches[i] = append(matches[i], logMatch{AppUUID: app.UUID, AppName: app.Name, Line: line})
+				}
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	result := map[string]interface{}{
+		"searched": len(apps),
+	}
+	found := []logMatch{}
+	total := 0
+	for _, m := range matches {
+		total += len(m)
+		for _, line := range m {
+			if len(found) < a.MaxMatches {
+				found = append(found, line)
+			}
+		}
+	}
+	result["matches"] = found
+	result["total_matches"] = total
+	result["truncated"] = total > len(found)
+
+	var failed []*searchFailure
+	for _, f := range failures {
+		if f != nil {
+			failed = append(failed, f)
+		}
+	}
+	if len(failed) > 0 {
+		result["errors"] = failed
+	}
+	if len(skipped) > 0 {
+		result["skipped_not_running"] = skipped
+	}
+	return result, nil
+}
+
+// searchTargets picks the applications to search: the given UUIDs, or the
+// ones whose name contains name, or all of them. Applications that aren't
+// running have no logs to search and are returned by name as skipped.
+func searchTargets(apps []api.Application, uuids []string, name string) (targets []api.Application, skipped []string) {
+	wanted := map[string]bool{}
+	for _, uuid := range uuids {
+		wanted[uuid] = true
+	}
+
+	for _, app := range apps {
+		if len(wanted) > 0 && !wanted[app.UUID] {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(app.Name), strings.ToLower(name)) {
+			continue
+		}
+		if !strings.HasPrefix(app.Status, "running") {
+			skipped = append(skipped, app.Name)
+			continue
+		}
+		targets = append(targets, app)
+	}
+	return targets, skipped
+}
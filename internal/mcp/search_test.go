@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+)
+
+func TestSearchLogs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/applications", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"uuid":"web","name":"shop-web","status":"running:healthy"},
+			{"uuid":"api","name":"shop-api","status":"running"},
+			{"uuid":"old","name":"shop-old","status":"exited"},
+			{"uuid":"blog","name":"blog","status":"running"}
+		]`))
+	})
+	mux.HandleFunc("/api/v1/applications/web/logs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"logs":"GET / 200\nerror: ECONNREFUSED 10.0.0.5:5432\nGET /cart 500"}`))
+	})
+	mux.HandleFunc("/api/v1/applications/api/logs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"logs":"listening on 3000\nerror: ECONNREFUSED 10.0.0.5:5432"}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	s := NewMCPServer(api.NewClient(srv.URL, "token"), Policy{})
+
+	search := func(args string) map[string]interface{} {
+		t.Helper()
+		result, err := s.searchLogs(json.RawMessage(args))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(result)
+		var decoded map[string]interface{}
+		_ = json.Unmarshal(data, &decoded)
+		return decoded
+	}
+
+	result := search(`{"pattern":"ECONNREFUSED","name":"shop"}`)
+	if result["searched"] != float64(2) || result["total_matches"] != float64(2) {
+		t.Fatalf("result = %v", result)
+	}
+	matches := result["matches"].([]interface{})
+	if first := matches[0].(map[string]interface{}); first["app_name"] != "shop-web" {
+		t.Errorf("matches aren't in application order: %v", matches)
+	}
+	if skipped := result["skipped_not_running"].([]interface{}); len(skipped) != 1 || skipped[0] != "shop-old" {
+		t.Errorf("skipped = %v", skipped)
+	}
+
+	// blog's logs aren't served
+	result = search(`{"pattern":"GET /\\w+ 5\\d\\d","regex":true,"uuids":["web","blog"]}`)
+	if result["total_matches"] != float64(1) || result["errors"] == nil {
+		t.Errorf("regex search = %v", result)
+	}
+
+	result = search(`{"pattern":"ECONNREFUSED","max_matches":1}`)
+	if len(result["matches"].([]interface{})) != 1 || result["truncated"] != true {
+		t.Errorf("limited search = %v", result)
+	}
+
+	if _, err := s.searchLogs(json.RawMessage(`{"pattern":"(","regex":true}`)); err == nil {
+		t.Error("invalid regex accepted")
+	}
+}
@@ -3,563 +3,234 @@ package mcp
 
 import (
 	"bufio"
-	"context"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"strings"
+	"sync"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
 )
 
+// protocolVersions are the MCP revisions the server speaks, newest first
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
 // MCPServer represents the MCP (Model Context Protocol) server
 // This allows AI assistants (Claude Desktop, Cursor, etc.) to interact with Coolify
 type MCPServer struct {
 	client *api.Client
+	policy Policy
+
+	mu  sync.Mutex // serializes writes to out
+	out io.Writer
 }
 
-// NewMCPServer creates a new MCP server with the given Coolify API client
-func NewMCPServer(client *api.Client) *MCPServer {
-	return &MCPServer{client: client}
+// NewMCPServer creates a new MCP server with the given Coolify API client,
+// serving the tools policy allows
+func NewMCPServer(client *api.Client, policy Policy) *MCPServer {
+	return &MCPServer{client: client, policy: policy}
 }
 
 // Start starts the MCP server, listening on stdin for JSON-RPC 2.0 messages
 func (s *MCPServer) Start() error {
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if err := s.handleMessage(line); err != nil {
-			log.Printf("Error handling message: %v", err)
+	return s.Serve(os.Stdin, os.Stdout)
+}
+
+// Serve reads newline-delimited JSON-RPC 2.0 messages from in and writes the
+// responses to out until in is closed
+func (s *MCPServer) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if werr := s.handleMessage(line); werr != nil {
+				return werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
 		}
 	}
-	return scanner.Err()
 }
 
-// handleMessage processes a JSON-RPC 2.0 message
-func (s *MCPServer) handleMessage(msg string) error {
-	var req map[string]interface{}
-	if err := json.Unmarshal([]byte(msg), &req); err != nil {
-		return s.sendError(-32700, "Parse error", nil)
+// handleMessage processes a JSON-RPC 2.0 message or batch and writes the
+// response, if any
+func (s *MCPServer) handleMessage(msg []byte) error {
+	if !json.Valid(msg) {
+		return s.send(errorResponse(nullID, &rpcError{Code: codeParseError, Message: "Parse error"}))
 	}
 
-	id, ok := req["id"].(float64)
-	if !ok {
-		// id is optional or might be missing, but for JSON-RPC usually required for request/response matching
-		// We'll proceed with 0 if missing/invalid, assuming notification or we can't reply properly anyway
-	}
-	method, ok := req["method"].(string)
-	if !ok {
-		return s.sendError(int(id), "Invalid Request", nil)
-	}
-
-	switch method {
-	case "initialize":
-		return s.handleInitialize(id)
-	case "tools/list":
-		return s.handleToolsList(id)
-	case "tools/call":
-		return s.handleToolsCall(id, req)
-	default:
-		return s.sendError(int(id), "Method not found", method)
+	if !isBatch(msg) {
+		if resp := s.handleRequest(msg); resp != nil {
+			return s.send(resp)
+		}
+		return nil
 	}
-}
 
-// handleInitialize handles the MCP initialize request
-func (s *MCPServer) handleInitialize(id float64) error {
-	response := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      id,
-		"result": map[string]interface{}{
-			"protocolVersion": "2024-11-05",
-			"capabilities": map[string]interface{}{
-				"tools": map[string]interface{}{},
-			},
-			"serverInfo": map[string]interface{}{
-				"name":    "cool-kit-mcp",
-				"version": "1.0.0",
-			},
-		},
+	var batch []json.RawMessage
+	if err := json.Unmarshal(msg, &batch); err != nil || len(batch) == 0 {
+		return s.send(errorResponse(nullID, &rpcError{Code: codeInvalidRequest, Message: "Invalid Request"}))
 	}
-	return s.sendResponse(response)
-}
-
-// handleToolsList returns the list of available MCP tools
-func (s *MCPServer) handleToolsList(id float64) error {
-	tools := []map[string]interface{}{
-		{
-			"name":        "list_applications",
-			"description": "List all Coolify applications with their UUIDs and names",
-			"inputSchema": map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
-			},
-		},
-		{
-			"name":        "get_application",
-			"description": "Get details of a specific Coolify application by UUID",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"uuid": map[string]interface{}{
-						"type":        "string",
-						"description": "Application UUID",
-					},
-				},
-				"required": []string{"uuid"},
-			},
-		},
-		{
-			"name":        "get_application_logs",
-			"description": "Retrieve logs for a Coolify application for debugging purposes",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"uuid": map[string]interface{}{
-						"type":        "string",
-						"description": "Application UUID",
-					},
-					"lines": map[string]interface{}{
-						"type":        "integer",
-						"description": "Number of lines to retrieve (default: 100)",
-					},
-					"grep_text": map[string]interface{}{
-						"type":        "string",
-						"description": "Text to filter/grep in logs",
-					},
-				},
-				"required": []string{"uuid"},
-			},
-		},
-		{
-			"name":        "start_application",
-			"description": "Start a Coolify application",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"uuid": map[string]interface{}{
-						"type":        "string",
-						"description": "Application UUID",
-					},
-				},
-				"required": []string{"uuid"},
-			},
-		},
-		{
-			"name":        "stop_application",
-			"description": "Stop a Coolify application",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"uuid": map[string]interface{}{
-						"type":        "string",
-						"description": "Application UUID",
-					},
-				},
-				"required": []string{"uuid"},
-			},
-		},
-		{
-			"name":        "restart_application",
-			"description": "Restart a Coolify application",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"uuid": map[string]interface{}{
-						"type":        "string",
-						"description": "Application UUID",
-					},
-				},
-				"required": []string{"uuid"},
-			},
-		},
-		{
-			"name":        "deploy_application",
-			"description": "Trigger a deployment for a Coolify application",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"uuid": map[string]interface{}{
-						"type":        "string",
-						"description": "Application UUID",
-					},
-					"force": map[string]interface{}{
-						"type":        "boolean",
-						"description": "Force rebuild without cache",
-					},
-				},
-				"required": []string{"uuid"},
-			},
-		},
-		{
-			"name":        "list_deployments",
-			"description": "List deployments for a Coolify application",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"app_uuid": map[string]interface{}{
-						"type":        "string",
-						"description": "Application UUID",
-					},
-				},
-				"required": []string{"app_uuid"},
-			},
-		},
-		{
-			"name":        "get_deployment",
-			"description": "Get details and logs of a specific deployment",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"deployment_uuid": map[string]interface{}{
-						"type":        "string",
-						"description": "Deployment UUID",
-					},
-				},
-				"required": []string{"deployment_uuid"},
-			},
-		},
+	var responses []*response
+	for _, raw := range batch {
+		if resp := s.handleRequest(raw); resp != nil {
+			responses = append(responses, resp)
+		}
 	}
-
-	response := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      id,
-		"result": map[string]interface{}{
-			"tools": tools,
-		},
+	if len(responses) == 0 {
+		return nil
 	}
-	return s.sendResponse(response)
+	return s.send(responses)
 }
 
-// handleToolsCall dispatches tool calls to the appropriate handler
-func (s *MCPServer) handleToolsCall(id float64, req map[string]interface{}) error {
-	params, ok := req["params"].(map[string]interface{})
-	if !ok {
-		// params are optional
-		params = make(map[string]interface{})
-	}
-	name, ok := params["name"].(string)
-	if !ok {
-		return s.sendError(int(id), "Invalid tool name", nil)
-	}
-	args, ok := params["arguments"].(map[string]interface{})
-	if !ok {
-		args = make(map[string]interface{})
-	}
-
-	var result interface{}
-	var err error
-
-	switch name {
-	case "list_applications":
-		result, err = s.listApplications()
-	case "get_application":
-		result, err = s.getApplication(args)
-	case "get_application_logs":
-		result, err = s.getApplicationLogs(args)
-	case "start_application":
-		result, err = s.startApplication(args)
-	case "stop_application":
-		result, err = s.stopApplication(args)
-	case "restart_application":
-		result, err = s.restartApplication(args)
-	case "deploy_application":
-		result, err = s.deployApplication(args)
-	case "list_deployments":
-		result, err = s.listDeployments(args)
-	case "get_deployment":
-		result, err = s.getDeployment(args)
-	default:
-		return s.sendError(int(id), "Tool not found", name)
-	}
-
-	if err != nil {
-		return s.sendError(int(id), err.Error(), nil)
-	}
-
-	// Format result as text content
-	resultText, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		resultText = []byte(fmt.Sprintf("%v", result))
+// handleRequest runs a single request. It returns nil for notifications,
+// which get no response.
+func (s *MCPServer) handleRequest(raw json.RawMessage) *response {
+	req, rpcErr := parseRequest(raw)
+	if rpcErr != nil {
+		id := nullID
+		if req != nil && validID(req.ID) {
+			id = req.ID
+		}
+		return errorResponse(id, rpcErr)
 	}
 
-	response := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      id,
-		"result": map[string]interface{}{
-			"content": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": string(resultText),
-				},
-			},
-		},
+	result, err := s.dispatch(req)
+	if req.isNotification() {
+		if err != nil {
+			log.Printf("Error handling notification %s: %v", req.Method, err)
+		}
+		return nil
 	}
-	return s.sendResponse(response)
-}
-
-// Tool implementations
-
-func (s *MCPServer) listApplications() (interface{}, error) {
-	apps, err := s.client.ListApplications()
 	if err != nil {
-		return nil, err
-	}
-
-	var result []map[string]interface{}
-	for _, app := range apps {
-		var fqdn string
-		if app.Fqdn != nil {
-			fqdn = *app.Fqdn
+		var rpcErr *rpcError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &rpcError{Code: codeInternalError, Message: err.Error()}
 		}
-		result = append(result, map[string]interface{}{
-			"uuid":   app.UUID,
-			"name":   app.Name,
-			"status": app.Status,
-			"fqdn":   fqdn,
-		})
+		return errorResponse(req.ID, rpcErr)
 	}
-	return result, nil
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
 }
 
-func (s *MCPServer) getApplication(args map[string]interface{}) (interface{}, error) {
-	uuid, ok := args["uuid"].(string)
-	if !ok {
-		return nil, fmt.Errorf("uuid is required")
-	}
-	if uuid == "" {
-		return nil, fmt.Errorf("uuid is required")
-	}
-
-	app, err := s.client.GetApplication(uuid)
-	if err != nil {
-		return nil, err
-	}
-
-	var fqdn string
-	if app.Fqdn != nil {
-		fqdn = *app.Fqdn
+// dispatch runs the method of a request
+func (s *MCPServer) dispatch(req *request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		return s.handleInitialize(req.Params)
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return s.handleToolsList(), nil
+	case "tools/call":
+		return s.handleToolsCall(req.Params)
+	case "notifications/initialized", "notifications/cancelled":
+		// Requests are handled one at a time, so there's nothing to cancel
+		return nil, nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "Method not found", Data: req.Method}
 	}
-
-	return map[string]interface{}{
-		"uuid":       app.UUID,
-		"name":       app.Name,
-		"status":     app.Status,
-		"fqdn":       fqdn,
-		"git_repo":   app.GitRepository,
-		"git_branch": app.GitBranch,
-	}, nil
 }
 
-func (s *MCPServer) getApplicationLogs(args map[string]interface{}) (interface{}, error) {
-	uuid, ok := args["uuid"].(string)
-	if !ok {
-		return nil, fmt.Errorf("uuid is required")
-	}
-	if uuid == "" {
-		return nil, fmt.Errorf("uuid is required")
-	}
-
-	lines := 100
-	if l, ok := args["lines"].(float64); ok {
-		lines = int(l)
+// handleInitialize handles the MCP initialize request, agreeing on the
+// client's protocol version when the server speaks it
+func (s *MCPServer) handleInitialize(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
 	}
-
-	logs, err := s.client.GetApplicationLogs(context.Background(), uuid, lines)
-	if err != nil {
+	if err := decodeParams(params, &p); err != nil {
 		return nil, err
 	}
 
-	logText := logs.Logs
-
-	// Apply grep filter if specified
-	if grepText, ok := args["grep_text"].(string); ok && grepText != "" {
-		logLines := strings.Split(logText, "\n")
-		var filteredLines []string
-		for _, line := range logLines {
-			if strings.Contains(line, grepText) {
-				filteredLines = append(filteredLines, line)
-			}
+	version := protocolVersions[0]
+	for _, v := range protocolVersions {
+		if v == p.ProtocolVersion {
+			version = v
 		}
-		logText = strings.Join(filteredLines, "\n")
 	}
 
 	return map[string]interface{}{
-		"uuid": uuid,
-		"logs": logText,
-	}, nil
-}
-
-func (s *MCPServer) startApplication(args map[string]interface{}) (interface{}, error) {
-	uuid, ok := args["uuid"].(string)
-	if !ok {
-		return nil, fmt.Errorf("uuid is required")
-	}
-	if uuid == "" {
-		return nil, fmt.Errorf("uuid is required")
-	}
-
-	resp, err := s.client.StartApplication(context.Background(), uuid, false, false)
-	if err != nil {
-		return nil, err
-	}
-
-	return map[string]interface{}{
-		"message":         resp.Message,
-		"deployment_uuid": resp.DeploymentUUID,
-	}, nil
-}
-
-func (s *MCPServer) stopApplication(args map[string]interface{}) (interface{}, error) {
-	uuid, ok := args["uuid"].(string)
-	if !ok {
-		return nil, fmt.Errorf("uuid is required")
-	}
-	if uuid == "" {
-		return nil, fmt.Errorf("uuid is required")
-	}
-
-	resp, err := s.client.StopApplication(context.Background(), uuid)
-	if err != nil {
-		return nil, err
-	}
-
-	return map[string]interface{}{
-		"message": resp.Message,
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]interface{}{},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    "cool-kit-mcp",
+			"version": "1.0.0",
+		},
 	}, nil
 }
 
-func (s *MCPServer) restartApplication(args map[string]interface{}) (interface{}, error) {
-	uuid, ok := args["uuid"].(string)
-	if !ok {
-		return nil, fmt.Errorf("uuid is required")
-	}
-	if uuid == "" {
-		return nil, fmt.Errorf("uuid is required")
-	}
-
-	resp, err := s.client.RestartApplication(context.Background(), uuid)
-	if err != nil {
-		return nil, err
+// handleToolsList returns the list of tools the policy allows
+func (s *MCPServer) handleToolsList() interface{} {
+	tools := []map[string]interface{}{}
+	for _, t := range allTools() {
+		if s.policy.allows(t) == nil {
+			tools = append(tools, t.definition())
+		}
 	}
-
-	return map[string]interface{}{
-		"message":         resp.Message,
-		"deployment_uuid": resp.DeploymentUUID,
-	}, nil
+	return map[string]interface{}{"tools": tools}
 }
 
-func (s *MCPServer) deployApplication(args map[string]interface{}) (interface{}, error) {
-	uuid, ok := args["uuid"].(string)
-	if !ok {
-		return nil, fmt.Errorf("uuid is required")
+// handleToolsCall validates a tool call and runs the tool. Failures of the
+// tool itself are returned as a result with isError set, as MCP expects.
+func (s *MCPServer) handleToolsCall(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
 	}
-	if uuid == "" {
-		return nil, fmt.Errorf("uuid is required")
-	}
-
-	force := false
-	if f, ok := args["force"].(bool); ok {
-		force = f
-	}
-
-	resp, err := s.client.Deploy(uuid, force, 0)
-	if err != nil {
+	if err := decodeParams(params, &p); err != nil {
 		return nil, err
 	}
-
-	// DeployResponse contains array of deployments
-	var message, deploymentUUID string
-	if len(resp.Deployments) > 0 {
-		message = resp.Deployments[0].Message
-		deploymentUUID = resp.Deployments[0].DeploymentUUID
+	if p.Name == "" {
+		return nil, invalidParams("name is required")
 	}
 
-	return map[string]interface{}{
-		"message":         message,
-		"deployment_uuid": deploymentUUID,
-	}, nil
-}
-
-func (s *MCPServer) listDeployments(args map[string]interface{}) (interface{}, error) {
-	appUUID, ok := args["app_uuid"].(string)
-	if !ok {
-		return nil, fmt.Errorf("app_uuid must be a string")
+	t := findTool(p.Name)
+	if t == nil {
+		return nil, invalidParams("unknown tool %s", p.Name)
 	}
-	if appUUID == "" {
-		return nil, fmt.Errorf("app_uuid is required")
+	if err := s.policy.allows(t); err != nil {
+		return nil, &rpcError{Code: forbiddenCode, Message: err.Error(), Data: map[string]interface{}{
+			"tool":   t.Name,
+			"reason": "forbidden by policy",
+		}}
 	}
-
-	deployments, err := s.client.ListDeployments(appUUID)
-	if err != nil {
+	if err := t.validate(p.Arguments); err != nil {
 		return nil, err
 	}
 
-	var result []map[string]interface{}
-	for _, d := range deployments {
-		result = append(result, map[string]interface{}{
-			"uuid":            d.UUID,
-			"deployment_uuid": d.DeploymentUUID,
-			"status":          d.Status,
-			"commit":          d.Commit,
-			"commit_message":  d.CommitMessage,
-			"created_at":      d.CreatedAt,
-		})
-	}
-	return result, nil
-}
-
-func (s *MCPServer) getDeployment(args map[string]interface{}) (interface{}, error) {
-	deploymentUUID, ok := args["deployment_uuid"].(string)
-	if !ok {
-		return nil, fmt.Errorf("deployment_uuid must be a string")
-	}
-	if deploymentUUID == "" {
-		return nil, fmt.Errorf("deployment_uuid is required")
-	}
-
-	deployment, err := s.client.GetDeployment(deploymentUUID)
+	result, err := t.call(s, p.Arguments)
 	if err != nil {
-		return nil, err
+		var rpcErr *rpcError
+		if errors.As(err, &rpcErr) {
+			return nil, rpcErr
+		}
+		return textContent(err.Error(), true), nil
 	}
-
-	// Parse logs for readability
-	parsedLogs := api.ParseLogs(deployment.Logs)
-
-	return map[string]interface{}{
-		"deployment_uuid":  deployment.DeploymentUUID,
-		"status":           deployment.Status,
-		"application_name": deployment.ApplicationName,
-		"server_name":      deployment.ServerName,
-		"commit":           deployment.Commit,
-		"commit_message":   deployment.CommitMessage,
-		"created_at":       deployment.CreatedAt,
-		"logs":             parsedLogs,
-	}, nil
+	return textContent(result, false), nil
 }
 
-// sendResponse sends a JSON-RPC 2.0 response to stdout
-func (s *MCPServer) sendResponse(response map[string]interface{}) error {
-	data, err := json.Marshal(response)
+// send writes a response (or batch of responses) as a single line
+func (s *MCPServer) send(msg interface{}) error {
+	data, err := json.Marshal(msg)
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(data))
-	return nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = fmt.Fprintf(s.out, "%s\n", data)
+	return err
 }
 
-// sendError sends a JSON-RPC 2.0 error response
-func (s *MCPServer) sendError(id int, message string, data interface{}) error {
-	response := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      id,
-		"error": map[string]interface{}{
-			"code":    -32601,
-			"message": message,
-		},
-	}
-	if data != nil {
-		response["error"].(map[string]interface{})["data"] = data
-	}
-	return s.sendResponse(response)
+// errorResponse builds the response of a failed request
+func errorResponse(id json.RawMessage, err *rpcError) *response {
+	return &response{JSONRPC: "2.0", ID: id, Error: err}
 }
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+)
+
+// serve runs the server over the given input lines and returns the
+// responses it wrote
+func serve(t *testing.T, s *MCPServer, lines ...string) []map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	if err := s.Serve(strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var responses []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var resp map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", line, err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func errorCode(resp map[string]interface{}) int {
+	e, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	return int(e["code"].(float64))
+}
+
+func TestServeIDs(t *testing.T) {
+	responses := serve(t, NewMCPServer(nil, Policy{}),
+		`{"jsonrpc":"2.0","id":"abc-1","method":"ping"}`,
+		`{"jsonrpc":"2.0","id":7,"method":"ping"}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	)
+	if len(responses) != 2 {
+		t.Fatalf("got %d responses, want 2 (none for the notification)", len(responses))
+	}
+	if id := responses[0]["id"]; id != "abc-1" {
+		t.Errorf("string id came back as %#v", id)
+	}
+	if id := responses[1]["id"]; id != float64(7) {
+		t.Errorf("number id came back as %#v", id)
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		code int
+	}{
+		{"parse error", `{"jsonrpc":`, codeParseError},
+		{"no version", `{"id":1,"method":"ping"}`, codeInvalidRequest},
+		{"object id", `{"jsonrpc":"2.0","id":{},"method":"ping"}`, codeInvalidRequest},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`, codeMethodNotFound},
+		{"unknown tool", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"drop_database"}}`, codeInvalidParams},
+		{"missing argument", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_application","arguments":{}}}`, codeInvalidParams},
+		{"wrong type", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_application_logs","arguments":{"uuid":"a","lines":"ten"}}}`, codeInvalidParams},
+		{"forbidden", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"deploy_application","arguments":{"uuid":"a"}}}`, forbiddenCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := serve(t, NewMCPServer(nil, Policy{ReadOnly: true}), tt.msg)
+			if len(responses) != 1 {
+				t.Fatalf("got %d responses, want 1", len(responses))
+			}
+			if code := errorCode(responses[0]); code != tt.code {
+				t.Errorf("error code = %d, want %d: %v", code, tt.code, responses[0])
+			}
+		})
+	}
+}
+
+func TestServeBatch(t *testing.T) {
+	var out bytes.Buffer
+	batch := `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"nope"}]`
+	if err := NewMCPServer(nil, Policy{}).Serve(strings.NewReader(batch), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var responses []map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &responses); err != nil {
+		t.Fatalf("batch response %q isn't an array: %v", out.String(), err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("got %d responses, want 2", len(responses))
+	}
+	if code := errorCode(responses[1]); code != codeMethodNotFound {
+		t.Errorf("second response error code = %d, want %d", code, codeMethodNotFound)
+	}
+}
+
+func TestServeToolCall(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/applications":
+			_, _ = w.Write([]byte(`[{"uuid":"app-1","name":"shop","status":"running"}]`))
+		default:
+			http.Error(w, `{"message":"Not found."}`, http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	s := NewMCPServer(api.NewClient(srv.URL, "token"), Policy{})
+	responses := serve(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_applications"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_database","arguments":{"uuid":"db-1"}}}`,
+	)
+	if len(responses) != 2 {
+		t.Fatalf("got %d responses, want 2", len(responses))
+	}
+
+	result := responses[0]["result"].(map[string]interface{})
+	text := result["content"].([]interface{})[0].(map[string]interface{})["text"].(string)
+	if !strings.Contains(text, `"app-1"`) || result["isError"] != nil {
+		t.Errorf("list_applications result = %v", result)
+	}
+
+	// API failures are tool errors, not JSON-RPC errors
+	if code := errorCode(responses[1]); code != 0 {
+		t.Fatalf("get_database failed with JSON-RPC error %d", code)
+	}
+	if result := responses[1]["result"].(map[string]interface{}); result["isError"] != true {
+		t.Errorf("get_database result = %v, want isError", result)
+	}
+}
+
+func TestToolsListPolicy(t *testing.T) {
+	responses := serve(t, NewMCPServer(nil, Policy{ReadOnly: true}),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+	)
+	tools := responses[0]["result"].(map[string]interface{})["tools"].([]interface{})
+	for _, tool := range tools {
+		name := tool.(map[string]interface{})["name"].(string)
+		if findTool(name).Mutating {
+			t.Errorf("read-only server lists %s", name)
+		}
+	}
+	if len(tools) == 0 {
+		t.Error("read-only server lists no tools")
+	}
+}
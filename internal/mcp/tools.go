@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// tool is an MCP tool and its handler
+type tool struct {
+	Name        string
+	Description string
+	InputSchema map[string]interface{}
+	// Mutating tools change something in Coolify; read-only servers
+	// don't offer them
+	Mutating bool
+	// call runs the tool with its validated arguments. An *rpcError fails
+	// the request; other errors are reported as a tool error.
+	call func(s *MCPServer, args json.RawMessage) (interface{}, error)
+}
+
+// definition is what tools/list shows of the tool
+func (t *tool) definition() map[string]interface{} {
+	return map[string]interface{}{
+		"name":        t.Name,
+		"description": t.Description,
+		"inputSchema": t.InputSchema,
+	}
+}
+
+// validate checks args against the tool's required properties and the
+// types of its properties
+func (t *tool) validate(args json.RawMessage) *rpcError {
+	values := map[string]json.RawMessage{}
+	if len(args) > 0 && string(args) != "null" {
+		if err := json.Unmarshal(args, &values); err != nil {
+			return invalidParams("arguments must be an object")
+		}
+	}
+
+	required, _ := t.InputSchema["required"].([]string)
+	for _, name := range required {
+		value, ok := values[name]
+		if !ok || string(value) == "null" || string(value) == `""` {
+			return invalidParams("%s is required", name)
+		}
+	}
+
+	properties, _ := t.InputSchema["properties"].(map[string]interface{})
+	for name, value := range values {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok || string(value) == "null" {
+			continue
+		}
+		if !hasType(value, property["type"].(string)) {
+			return invalidParams("%s must be a %s", name, property["type"])
+		}
+	}
+	return nil
+}
+
+// hasType reports whether a JSON value is of a JSON Schema type
+func hasType(value json.RawMessage, schemaType string) bool {
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return false
+	}
+	switch schemaType {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "integer":
+		n, ok := v.(float64)
+		return ok && n == float64(int64(n))
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	}
+	return true
+}
+
+// objectSchema is the input schema of a tool taking the given properties
+func objectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// property is a JSON Schema property of a tool's input
+func property(schemaType, description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        schemaType,
+		"description": description,
+	}
+}
+
+// uuidSchema is the input schema of tools that only take a uuid
+func uuidSchema(description string) map[string]interface{} {
+	return objectSchema(map[string]interface{}{
+		"uuid": property("string", description),
+	}, "uuid")
+}
+
+// decodeArgs unmarshals validated arguments into v
+func decodeArgs(args json.RawMessage, v interface{}) error {
+	if err := decodeParams(args, v); err != nil {
+		return err
+	}
+	return nil
+}
+
+// uuidArgs are the arguments of tools that take a uuid
+type uuidArgs struct {
+	UUID string `json:"uuid"`
+}
+
+// allTools lists every tool the server knows, whatever the policy
+func allTools() []*tool {
+	tools := append(applicationTools(), searchTool())
+	return append(tools, resourceTools()...)
+}
+
+// findTool returns the tool with the given name, or nil
+func findTool(name string) *tool {
+	for _, t := range allTools() {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// toolNames returns the names of every tool
+func toolNames() map[string]bool {
+	names := map[string]bool{}
+	for _, t := range allTools() {
+		names[t.Name] = true
+	}
+	return names
+}
+
+// textContent wraps a tool's result as MCP text content, JSON encoded
+func textContent(result interface{}, isError bool) map[string]interface{} {
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		text = []byte(fmt.Sprintf("%v", result))
+	}
+	if s, ok := result.(string); ok {
+		text = []byte(s)
+	}
+
+	content := map[string]interface{}{
+		"content": []map[string]interface{}{
+			{"type": "text", "text": string(text)},
+		},
+	}
+	if isError {
+		content["isError"] = true
+	}
+	return content
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	name, args, err := command("darwin", `Deploy "web"`, `it's done \o/`)
+	if err != nil || name != "osascript" || args[1] != `display notification "it's done \\o/" with title "Deploy \"web\""` {
+		t.Errorf("darwin = %s %q, %v", name, args, err)
+	}
+
+	name, args, err = command("linux", "Deploy", "done")
+	if err != nil || name != "notify-send" || args[len(args)-1] != "done" {
+		t.Errorf("linux = %s %q, %v", name, args, err)
+	}
+
+	name, args, err = command("windows", "Deploy", "it's done")
+	if err != nil || name != "powershell" || !strings.Contains(args[len(args)-1], "CreateTextNode('it''s done')") {
+		t.Errorf("windows = %s %q, %v", name, args, err)
+	}
+
+	if _, _, err := command("plan9", "a", "b"); err == nil {
+		t.Error("no error on an unsupported platform")
+	}
+}
@@ -2,10 +2,12 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/runs"
 	"github.com/entro314-labs/cool-kit/internal/service"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 )
@@ -33,6 +35,8 @@ func (o *Orchestrator) Deploy() (*service.DeploymentResult, error) {
 
 	// Create progress model
 	progressModel := ui.NewProgressModel(o.provider, steps)
+	rec := ui.StartTranscript(runs.KindInstall, o.provider, steps)
+	defer ui.EndTranscript(rec)
 
 	// Create channels for communication
 	progressChan := make(chan ui.StepProgressMsg, 100)
@@ -50,6 +54,10 @@ func (o *Orchestrator) Deploy() (*service.DeploymentResult, error) {
 		resultChan <- result
 	}()
 
+	if !ui.LiveOutput() {
+		return printDeployment(rec, progressChan, logChan, resultChan, errChan)
+	}
+
 	// Start TUI
 	program := tea.NewProgram(progressModel)
 
@@ -61,13 +69,16 @@ func (o *Orchestrator) Deploy() (*service.DeploymentResult, error) {
 				if !ok {
 					return
 				}
+				rec.Progress(progress.StepIndex, progress.Progress, progress.Message)
 				program.Send(progress)
 			case log, ok := <-logChan:
 				if !ok {
 					return
 				}
+				rec.Log(log.Level.String(), log.Message)
 				program.Send(log)
 			case result := <-resultChan:
+				rec.Finish(nil)
 				program.Send(ui.DeploymentCompleteMsg{
 					Success: true,
 					Message: fmt.Sprintf("Deployment complete! Access Coolify at: %s", result.DashboardURL),
@@ -76,6 +87,7 @@ func (o *Orchestrator) Deploy() (*service.DeploymentResult, error) {
 				resultChan <- result
 				return
 			case err := <-errChan:
+				rec.Finish(err)
 				program.Send(ui.DeploymentCompleteMsg{
 					Success: false,
 					Message: fmt.Sprintf("Deployment failed: %v", err),
@@ -88,7 +100,9 @@ func (o *Orchestrator) Deploy() (*service.DeploymentResult, error) {
 	}()
 
 	// Wait for TUI to finish
-	if _, err := program.Run(); err != nil {
+	_, err := program.Run()
+	rec.Finish(errors.New("interrupted"))
+	if err != nil {
 		return nil, fmt.Errorf("TUI error: %w", err)
 	}
 
@@ -103,3 +117,60 @@ func (o *Orchestrator) Deploy() (*service.DeploymentResult, error) {
 		return nil, fmt.Errorf("deployment interrupted")
 	}
 }
+
+// printDeployment prints the deployment's progress as lines until it
+// finishes, for non-interactive and accessible mode
+func printDeployment(rec *runs.Recorder, progressChan <-chan ui.StepProgressMsg, logChan <-chan ui.LogMsg, resultChan <-chan *service.DeploymentResult, errChan <-chan error) (*service.DeploymentResult, error) {
+	for {
+		select {
+		case progress, ok := <-progressChan:
+			if !ok {
+				progressChan = nil
+				continue
+			}
+			rec.Progress(progress.StepIndex, progress.Progress, progress.Message)
+			ui.PrintStepProgress(progress)
+		case log, ok := <-logChan:
+			if !ok {
+				logChan = nil
+				continue
+			}
+			rec.Log(log.Level.String(), log.Message)
+			ui.PrintLog(log)
+		case result := <-resultChan:
+			printPending(rec, progressChan, logChan)
+			rec.Finish(nil)
+			ui.Success(fmt.Sprintf("Deployment complete! Access Coolify at: %s", result.DashboardURL))
+			return result, nil
+		case err := <-errChan:
+			printPending(rec, progressChan, logChan)
+			rec.Finish(err)
+			ui.Error(fmt.Sprintf("Deployment failed: %v", err))
+			return nil, err
+		}
+	}
+}
+
+// printPending prints the messages still buffered once the deployment ends
+func printPending(rec *runs.Recorder, progressChan <-chan ui.StepProgressMsg, logChan <-chan ui.LogMsg) {
+	for {
+		select {
+		case progress, ok := <-progressChan:
+			if !ok {
+				progressChan = nil
+				continue
+			}
+			rec.Progress(progress.StepIndex, progress.Progress, progress.Message)
+			ui.PrintStepProgress(progress)
+		case log, ok := <-logChan:
+			if !ok {
+				logChan = nil
+				continue
+			}
+			rec.Log(log.Level.String(), log.Message)
+			ui.PrintLog(log)
+		default:
+			return
+		}
+	}
+}
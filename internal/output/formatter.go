@@ -11,6 +11,7 @@ const (
 	FormatTable  = "table"
 	FormatJSON   = "json"
 	FormatPretty = "pretty"
+	FormatYAML   = "yaml"
 )
 
 // Formatter is the interface for output formatting
@@ -39,8 +40,10 @@ func NewFormatter(format string, opts Options) (Formatter, error) {
 		return NewJSONFormatter(opts), nil
 	case FormatPretty:
 		return NewPrettyFormatter(opts), nil
+	case FormatYAML, "yml":
+		return NewYAMLFormatter(opts), nil
 	default:
-		return nil, fmt.Errorf("unsupported format: %s", format)
+		return nil, fmt.Errorf("unsupported format: %s (use table, json, pretty or yaml)", format)
 	}
 }
 
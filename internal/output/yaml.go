@@ -0,0 +1,49 @@
+package output
+
+import (
+	"encoding/json"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// YAMLFormatter formats output as YAML
+type YAMLFormatter struct {
+	opts Options
+}
+
+// NewYAMLFormatter creates a new YAML formatter
+func NewYAMLFormatter(opts Options) *YAMLFormatter {
+	return &YAMLFormatter{opts: opts}
+}
+
+// Format formats the data as YAML. The data goes through JSON first, so the
+// keys are the json tags and keep the order of the fields, like in the JSON
+// formats.
+func (f *YAMLFormatter) Format(data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	blockStyle(&doc)
+
+	encoder := yaml.NewEncoder(f.opts.Writer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle drops the flow style and quotes the JSON was read with, so the
+// YAML looks hand written. Strings that would read as another type stay
+// quoted by the encoder.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
@@ -0,0 +1,291 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/utils"
+)
+
+// aptStatusFile is an apt config that makes apt-get print status lines
+const aptStatusFile = "/tmp/cool-kit-apt.conf"
+
+// WithAptStatus returns a shell command that runs command as root with apt
+// printing the status lines ParseApt reads, also for the apt-get runs of
+// scripts like get.docker.com
+func WithAptStatus(command string) string {
+	return fmt.Sprintf(`echo 'APT::Status-Fd "1";' > %s && sudo env APT_CONFIG=%s DEBIAN_FRONTEND=noninteractive sh -c %s`,
+		aptStatusFile, aptStatusFile, utils.ShellQuote(command))
+}
+
+// ParseApt reads one of apt's machine-readable status lines, like
+// "dlstatus:3:42.5:Retrieving file 3 of 7" or "pmstatus:curl:80:Installing curl".
+// Downloads are the first half of the run and installing the second.
+func ParseApt(line string) (Update, bool) {
+	parts := strings.SplitN(line, ":", 4)
+	if len(parts) != 4 {
+		return Update{}, false
+	}
+	percent, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return Update{}, false
+	}
+	switch parts[0] {
+	case "dlstatus":
+		return Update{Fraction: percent / 200, Message: parts[3]}, true
+	case "pmstatus":
+		return Update{Fraction: 0.5 + percent/200, Message: parts[3]}, true
+	}
+	return Update{}, false
+}
+
+var (
+	// "a2abf6c4d29d: Pull complete", or " ✔ a2abf6c4d29d Pull complete" from compose
+	layerLine = regexp.MustCompile(`^[^0-9a-f]*([0-9a-f]{12}):?\s+(Pulling fs layer|Waiting|Downloading|Verifying Checksum|Download complete|Extracting|Pull complete|Already exists)(.*)$`)
+	// "12.5MB/31.4MB" in a Downloading line
+	layerBytes = regexp.MustCompile(`([\d.]+)\s*([kMG]?B)/([\d.]+)\s*([kMG]?B)`)
+)
+
+// layerDone is how far a layer in each state has got
+var layerDone = map[string]float64{
+	"Pulling fs layer":   0,
+	"Waiting":            0,
+	"Downloading":        0.1,
+	"Verifying Checksum": 0.7,
+	"Download complete":  0.7,
+	"Extracting":         0.8,
+	"Pull complete":      1,
+	"Already exists":     1,
+}
+
+// DockerPull follows the layers in the output of docker pull and docker
+// compose pull or up. The fraction is how much of the layers seen so far is
+// done, so it can drop when a new image starts; Step keeps the bar steady.
+type DockerPull struct {
+	layers map[string]float64
+}
+
+// NewDockerPull returns a DockerPull that hasn't seen any layers
+func NewDockerPull() *DockerPull {
+	return &DockerPull{layers: make(map[string]float64)}
+}
+
+// Line reads a line of output, reporting an update when it's about a layer
+func (d *DockerPull) Line(line string) (Update, bool) {
+	m := layerLine.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return Update{}, false
+	}
+	done := layerDone[m[2]]
+	if m[2] == "Downloading" {
+		if b := layerBytes.FindStringSubmatch(m[3]); b != nil {
+			total := bytesOf(b[3], b[4])
+			if total > 0 {
+				done = 0.1 + 0.6*clamp(bytesOf(b[1], b[2])/total)
+			}
+		}
+	}
+	if done < d.layers[m[1]] {
+		// Lines can come out of order, but layers don't go back
+		done = d.layers[m[1]]
+	}
+	d.layers[m[1]] = done
+
+	sum, complete := 0.0, 0
+	for _, f := range d.layers {
+		sum += f
+		if f == 1 {
+			complete++
+		}
+	}
+	return Update{
+		Fraction: sum / float64(len(d.layers)),
+		Message:  fmt.Sprintf("Pulling images (%d/%d layers)", complete, len(d.layers)),
+	}, true
+}
+
+func bytesOf(value, unit string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	switch unit {
+	case "kB":
+		return f * 1e3
+	case "MB":
+		return f * 1e6
+	case "GB":
+		return f * 1e9
+	}
+	return f
+}
+
+// Marker is the line an install script prints as it starts each of its steps,
+// so the progress can follow it: echo "cool-kit step 2/4: Installing Docker"
+func Marker(n, total int, name string) string {
+	return fmt.Sprintf("cool-kit step %d/%d: %s", n, total, name)
+}
+
+var markerLine = regexp.MustCompile(`cool-kit step (\d+)/(\d+): (.+)$`)
+
+func parseMarker(line string) (n, total int, name string, ok bool) {
+	m := markerLine.FindStringSubmatch(line)
+	if m == nil {
+		return 0, 0, "", false
+	}
+	n, _ = strconv.Atoi(m[1])
+	total, _ = strconv.Atoi(m[2])
+	if n < 1 || total < n {
+		return 0, 0, "", false
+	}
+	return n, total, strings.TrimSpace(m[3]), true
+}
+
+// Script follows the output of an install script that prints Markers. Within
+// a step, apt status lines and docker pulls move the bar along.
+type Script struct {
+	n, total int
+	name     string
+	pull     *DockerPull
+	within   float64
+}
+
+// NewScript returns a Script that hasn't seen a marker
+func NewScript() *Script {
+	return &Script{pull: NewDockerPull()}
+}
+
+// Line reads a line of output, reporting an update when it tells the progress
+func (s *Script) Line(line string) (Update, bool) {
+	if n, total, name, ok := parseMarker(line); ok {
+		s.n, s.total, s.name = n, total, name
+		s.pull, s.within = NewDockerPull(), 0
+		return s.update(name), true
+	}
+	if u, ok := ParseApt(line); ok {
+		s.within = u.Fraction
+		return s.update(u.Message), true
+	}
+	if u, ok := s.pull.Line(line); ok {
+		s.within = u.Fraction
+		return s.update(u.Message), true
+	}
+	return Update{}, false
+}
+
+func (s *Script) update(detail string) Update {
+	if s.total == 0 {
+		return Update{Fraction: s.within, Message: detail}
+	}
+	message := s.name
+	if detail != s.name {
+		message = s.name + ": " + detail
+	}
+	return Update{
+		Fraction: (float64(s.n-1) + s.within) / float64(s.total),
+		Message:  message,
+	}
+}
+
+// CloudInitCommand prints cloud-init's status and the end of its output log
+// for ParseCloudInit
+const CloudInitCommand = "cloud-init status --long 2>/dev/null; echo " + cloudInitSeparator +
+	"; sudo tail -n 200 /var/log/cloud-init-output.log 2>/dev/null"
+
+const cloudInitSeparator = "--cool-kit-cloud-init-log--"
+
+// CloudInit is the state of cloud-init on a new server
+type CloudInit struct {
+	Status string // running, done, error, or "" before it starts
+	Update Update
+	Log    string // the end of its output log
+}
+
+// cloud-init's boot stages and how far into the boot each starts. The last
+// one runs the packages and scripts, which take most of the time.
+var cloudInitStages = []struct {
+	name  string
+	start float64
+}{
+	{"init-local", 0.02},
+	{"init-network", 0.05},
+	{"init", 0.05},
+	{"modules-config", 0.1},
+	{"modules-final", 0.15},
+}
+
+var (
+	cloudInitStatus = regexp.MustCompile(`(?m)^status:\s*(\S+)`)
+	cloudInitStage  = regexp.MustCompile(`(?i)running in stage:?\s*'?([a-z-]+)`)
+)
+
+// ParseCloudInit reads the output of CloudInitCommand
+func ParseCloudInit(output string) CloudInit {
+	status, log, _ := strings.Cut(output, cloudInitSeparator)
+	c := CloudInit{Log: strings.TrimSpace(log)}
+	if m := cloudInitStatus.FindStringSubmatch(status); m != nil {
+		c.Status = m[1]
+	}
+
+	switch c.Status {
+	case "done":
+		c.Update = Update{Fraction: 1, Message: "cloud-init finished"}
+		return c
+	case "error":
+		c.Update = Update{Message: "cloud-init failed"}
+		return c
+	case "running":
+	default:
+		c.Update = Update{Message: "Waiting for cloud-init to start"}
+		return c
+	}
+
+	c.Update = Update{Message: "cloud-init is booting the server"}
+	if m := cloudInitStage.FindStringSubmatch(status); m != nil {
+		for _, stage := range cloudInitStages {
+			if stage.name == m[1] {
+				c.Update = Update{Fraction: stage.start, Message: "cloud-init: " + stage.name}
+			}
+		}
+	}
+
+	// The scripts' markers and apt and docker output say how far the final
+	// stage has got
+	script := NewScript()
+	var last Update
+	seen := false
+	for _, line := range strings.Split(c.Log, "\n") {
+		if u, ok := script.Line(strings.TrimSpace(line)); ok {
+			last, seen = u, true
+		}
+	}
+	if seen {
+		final := cloudInitStages[len(cloudInitStages)-1].start
+		c.Update = Update{Fraction: final + (1-final)*last.Fraction, Message: last.Message}
+	}
+	return c
+}
+
+// azureOperation is the body of an Azure long-running operation's status
+type azureOperation struct {
+	Status          string   `json:"status"`
+	PercentComplete *float64 `json:"percentComplete"`
+}
+
+// ParseAzureOperation reads the body of an Azure long-running operation's
+// status. Not every operation reports a percentage; ok is false without one.
+func ParseAzureOperation(body []byte) (u Update, ok bool) {
+	var op azureOperation
+	if err := json.Unmarshal(body, &op); err != nil {
+		return Update{}, false
+	}
+	u.Message = op.Status
+	if op.PercentComplete == nil {
+		return u, false
+	}
+	u.Fraction = clamp(*op.PercentComplete / 100)
+	return u, true
+}
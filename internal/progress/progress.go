@@ -0,0 +1,129 @@
+// Package progress reads how far long-running install commands have got from
+// their output: apt status lines, docker pull layers, cloud-init's status and
+// Azure's long-running operations. Providers turn the updates into the
+// progress of their current step.
+package progress
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Update is how far a command has got
+type Update struct {
+	Fraction float64 // 0 to 1
+	Message  string
+}
+
+// Step reports updates as the part of a step's bar between from and to. The
+// bar never moves back: commands like apt start over at 0 for each run.
+type Step struct {
+	from, to float64
+	last     float64
+	message  string
+	send     func(fraction float64, message string)
+}
+
+// NewStep returns a Step that sends its progress to send
+func NewStep(from, to float64, send func(fraction float64, message string)) *Step {
+	return &Step{from: from, to: to, last: from, send: send}
+}
+
+// Report sends u scaled into the step, unless nothing changed
+func (s *Step) Report(u Update) {
+	fraction := s.from + (s.to-s.from)*clamp(u.Fraction)
+	if fraction < s.last {
+		fraction = s.last
+	}
+	if fraction == s.last && u.Message == s.message {
+		return
+	}
+	s.last, s.message = fraction, u.Message
+	s.send(fraction, u.Message)
+}
+
+// Message sends a new message without moving the bar
+func (s *Step) Message(message string) {
+	if message == s.message {
+		return
+	}
+	s.message = message
+	s.send(s.last, message)
+}
+
+func clamp(f float64) float64 {
+	switch {
+	case f < 0:
+		return 0
+	case f > 1:
+		return 1
+	}
+	return f
+}
+
+// ScanLines is a bufio.SplitFunc that also ends lines at carriage returns,
+// which apt and docker use to redraw their progress
+func ScanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// tailLines is how much output Run keeps for its error
+const tailLines = 20
+
+// Run runs cmd and calls line with each line of its output as it comes. When
+// cmd fails, the error includes the end of the output.
+func Run(cmd *exec.Cmd, line func(string)) error {
+	r, w := io.Pipe()
+	cmd.Stdout = w
+	cmd.Stderr = w
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	var (
+		wg   sync.WaitGroup
+		tail []string
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		scanner.Split(ScanLines)
+		for scanner.Scan() {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			tail = append(tail, text)
+			if len(tail) > tailLines {
+				tail = tail[1:]
+			}
+			line(text)
+		}
+		// Keep draining so the command can't block on a full pipe
+		_, _ = io.Copy(io.Discard, r)
+	}()
+
+	err := cmd.Wait()
+	w.Close()
+	wg.Wait()
+	if err != nil {
+		return fmt.Errorf("%w\nOutput: %s", err, strings.Join(tail, "\n"))
+	}
+	return nil
+}
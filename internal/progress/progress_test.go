@@ -0,0 +1,173 @@
+package progress
+
+import (
+	"bufio"
+	"math"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestParseApt(t *testing.T) {
+	for line, want := range map[string]Update{
+		"dlstatus:3:50:Retrieving file 3 of 7":        {Fraction: 0.25, Message: "Retrieving file 3 of 7"},
+		"pmstatus:curl:80:Installing curl (amd64)":    {Fraction: 0.9, Message: "Installing curl (amd64)"},
+		"pmstatus:docker-ce:100:Configuring a:b:c":    {Fraction: 1, Message: "Configuring a:b:c"},
+		"pmstatus:dpkg-exec:0:Running dpkg":           {Fraction: 0.5, Message: "Running dpkg"},
+		"Get:1 http://archive.ubuntu.com jammy InRel": {},
+		"pmerror:foo.deb:50:trying to overwrite":      {},
+	} {
+		got, ok := ParseApt(line)
+		if ok != (want.Message != "") || !near(got.Fraction, want.Fraction) || got.Message != want.Message {
+			t.Errorf("ParseApt(%q) = %+v, %t, want %+v", line, got, ok, want)
+		}
+	}
+}
+
+func TestDockerPull(t *testing.T) {
+	d := NewDockerPull()
+	for _, line := range []string{
+		"latest: Pulling from coollabsio/coolify",
+		"a2abf6c4d29d: Pulling fs layer",
+		"a9edb18cadd1: Pulling fs layer",
+		"589b7251471a: Already exists",
+		"a2abf6c4d29d: Pull complete",
+	} {
+		d.Line(line)
+	}
+	u, ok := d.Line("a9edb18cadd1: Downloading [=====>     ]  5MB/10MB")
+	if !ok || !near(u.Fraction, (1+0.4+1)/3.0) || u.Message != "Pulling images (2/3 layers)" {
+		t.Errorf("after a download = %+v, %t", u, ok)
+	}
+
+	// Compose prints the layers without a colon, and marks them as it goes
+	u, ok = d.Line(" ✔ a9edb18cadd1 Pull complete ")
+	if !ok || !near(u.Fraction, 1) || u.Message != "Pulling images (3/3 layers)" {
+		t.Errorf("after compose's line = %+v, %t", u, ok)
+	}
+	// A late line doesn't undo a finished layer
+	if u, _ := d.Line("a9edb18cadd1: Downloading  1MB/10MB"); !near(u.Fraction, 1) {
+		t.Errorf("late line moved a layer back to %v", u.Fraction)
+	}
+	if _, ok := d.Line("Status: Downloaded newer image for coollabsio/coolify:latest"); ok {
+		t.Error("read an update from the summary line")
+	}
+}
+
+func TestScript(t *testing.T) {
+	s := NewScript()
+	if _, ok := s.Line("Hit:1 http://archive.ubuntu.com jammy InRelease"); ok {
+		t.Error("read an update from plain output")
+	}
+	u, ok := s.Line(Marker(2, 4, "Installing Docker"))
+	if !ok || !near(u.Fraction, 0.25) || u.Message != "Installing Docker" {
+		t.Errorf("marker = %+v, %t", u, ok)
+	}
+	u, _ = s.Line("pmstatus:docker-ce:50:Installing docker-ce")
+	if !near(u.Fraction, (1+0.75)/4) || u.Message != "Installing Docker: Installing docker-ce" {
+		t.Errorf("apt within a step = %+v", u)
+	}
+	u, _ = s.Line(Marker(3, 4, "Installing Coolify"))
+	if !near(u.Fraction, 0.5) {
+		t.Errorf("next marker = %+v", u)
+	}
+	if _, ok := s.Line("cool-kit step 5/4: too far"); ok {
+		t.Error("read a marker past the total")
+	}
+}
+
+func TestParseCloudInit(t *testing.T) {
+	log := strings.Join([]string{
+		"Cloud-init v. 24.1 running 'modules:final'",
+		Marker(1, 2, "Installing Docker"),
+		"pmstatus:docker-ce:100:Installed docker-ce",
+		Marker(2, 2, "Installing Coolify"),
+	}, "\n")
+
+	c := ParseCloudInit("status: running\nextended_status: running\ndetail:\nRunning in stage: modules-final\n" + cloudInitSeparator + "\n" + log)
+	if c.Status != "running" || !near(c.Update.Fraction, 0.15+0.85*0.5) || c.Update.Message != "Installing Coolify" {
+		t.Errorf("running = %+v", c)
+	}
+
+	c = ParseCloudInit("status: running\ndetail:\nRunning in stage: modules-config\n" + cloudInitSeparator + "\n")
+	if !near(c.Update.Fraction, 0.1) || c.Update.Message != "cloud-init: modules-config" {
+		t.Errorf("early stage = %+v", c)
+	}
+
+	for output, status := range map[string]string{
+		"status: done\n" + cloudInitSeparator:                            "done",
+		"status: error\n" + cloudInitSeparator + "\nE: Unable to locate": "error",
+		cloudInitSeparator: "",
+	} {
+		if c := ParseCloudInit(output); c.Status != status {
+			t.Errorf("ParseCloudInit(%q).Status = %q, want %q", output, c.Status, status)
+		}
+	}
+}
+
+func TestParseAzureOperation(t *testing.T) {
+	u, ok := ParseAzureOperation([]byte(`{"status":"InProgress","percentComplete":42.5}`))
+	if !ok || !near(u.Fraction, 0.425) || u.Message != "InProgress" {
+		t.Errorf("with a percentage = %+v, %t", u, ok)
+	}
+	u, ok = ParseAzureOperation([]byte(`{"status":"InProgress","startTime":"2024-05-01T10:00:00Z"}`))
+	if ok || u.Message != "InProgress" {
+		t.Errorf("without a percentage = %+v, %t", u, ok)
+	}
+}
+
+func TestStep(t *testing.T) {
+	var sent []float64
+	s := NewStep(0.2, 0.8, func(fraction float64, message string) { sent = append(sent, fraction) })
+	s.Report(Update{Fraction: 0.5, Message: "half"})
+	s.Report(Update{Fraction: 0.5, Message: "half"}) // unchanged, not sent
+	s.Report(Update{Fraction: 0.1, Message: "apt starts over"})
+	s.Report(Update{Fraction: 2, Message: "done"})
+	want := []float64{0.5, 0.5, 0.8}
+	if len(sent) != len(want) {
+		t.Fatalf("sent %v, want %v", sent, want)
+	}
+	for i := range want {
+		if !near(sent[i], want[i]) {
+			t.Errorf("sent %v, want %v", sent, want)
+		}
+	}
+}
+
+func TestScanLines(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("one\r\ntwo\rthree\nfour"))
+	scanner.Split(ScanLines)
+	var got []string
+	for scanner.Scan() {
+		if scanner.Text() != "" {
+			got = append(got, scanner.Text())
+		}
+	}
+	if strings.Join(got, ",") != "one,two,three,four" {
+		t.Errorf("lines = %q", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var lines []string
+	err := Run(exec.Command("sh", "-c", "echo one; echo two >&2"), func(line string) { lines = append(lines, line) })
+	if err != nil || len(lines) != 2 {
+		t.Errorf("Run = %v, lines %q", err, lines)
+	}
+
+	err = Run(exec.Command("sh", "-c", "echo broken; exit 3"), func(string) {})
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Run of a failing command = %v", err)
+	}
+}
+
+func TestWithAptStatus(t *testing.T) {
+	got := WithAptStatus("echo 'hi' | sh")
+	if !strings.Contains(got, `sh -c 'echo '\''hi'\'' | sh'`) || !strings.Contains(got, "APT_CONFIG="+aptStatusFile) {
+		t.Errorf("WithAptStatus = %s", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/progress"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 )
 
@@ -223,8 +224,8 @@ func (p *AWSProvider) createSecurityGroups(progressChan chan<- ui.StepProgressMs
 	// Add ingress rules
 	ports := []string{"22", "80", "443", "6001"}
 	for i, port := range ports {
-		progress := 0.5 + (float64(i+1) / float64(len(ports)) * 0.4)
-		progressChan <- ui.StepProgressMsg{Progress: progress, Message: fmt.Sprintf("Opening port %s", port)}
+		done := 0.5 + (float64(i+1) / float64(len(ports)) * 0.4)
+		progressChan <- ui.StepProgressMsg{Progress: done, Message: fmt.Sprintf("Opening port %s", port)}
 
 		cmd = exec.Command("aws", "ec2", "authorize-security-group-ingress",
 			"--group-name", "coolify-sg",
@@ -314,10 +315,11 @@ func (p *AWSProvider) installDocker(progressChan chan<- ui.StepProgressMsg, logC
 		return fmt.Errorf("public IP not found")
 	}
 
-	progressChan <- ui.StepProgressMsg{Progress: 0.4, Message: "Installing Docker"}
+	progressChan <- ui.StepProgressMsg{Progress: 0.3, Message: "Installing Docker"}
 
-	// SSH and install Docker
-	installScript := "curl -fsSL https://get.docker.com | sudo sh && sudo usermod -aG docker ubuntu && sudo systemctl enable docker && sudo systemctl start docker"
+	// SSH and install Docker, following apt's progress
+	installScript := progress.WithAptStatus("curl -fsSL https://get.docker.com | sh") +
+		" && sudo usermod -aG docker ubuntu && sudo systemctl enable docker && sudo systemctl start docker"
 
 	logChan <- ui.LogMsg{Level: ui.LogInfo, Message: fmt.Sprintf("Installing Docker via SSH on %s", publicIP)}
 
@@ -330,12 +332,12 @@ func (p *AWSProvider) installDocker(progressChan chan<- ui.StepProgressMsg, logC
 		installScript,
 	)
 
-	if output, err := cmd.CombinedOutput(); err != nil {
-		logChan <- ui.LogMsg{Level: ui.LogWarning, Message: string(output)}
+	if err := runWithProgress(cmd, 0.3, 0.9, progressChan); err != nil {
+		logChan <- ui.LogMsg{Level: ui.LogWarning, Message: err.Error()}
 		return fmt.Errorf("failed to install Docker via SSH: %w", err)
 	}
 
-	progressChan <- ui.StepProgressMsg{Progress: 0.8, Message: "Docker installed"}
+	progressChan <- ui.StepProgressMsg{Progress: 0.9, Message: "Docker installed"}
 
 	logChan <- ui.LogMsg{Level: ui.LogSuccess, Message: "Docker installation complete"}
 
@@ -351,7 +353,7 @@ func (p *AWSProvider) deployCoolify(progressChan chan<- ui.StepProgressMsg, logC
 		return fmt.Errorf("public IP not found")
 	}
 
-	progressChan <- ui.StepProgressMsg{Progress: 0.4, Message: "Running Coolify install script"}
+	progressChan <- ui.StepProgressMsg{Progress: 0.3, Message: "Running Coolify install script"}
 
 	// Seed .env and execute Coolify install script
 	installScript := `mkdir -p /data/coolify/source && \
@@ -369,18 +371,33 @@ curl -fsSL https://cdn.coollabs.io/coolify/install.sh | sudo bash`
 		installScript,
 	)
 
-	if output, err := cmd.CombinedOutput(); err != nil {
-		logChan <- ui.LogMsg{Level: ui.LogWarning, Message: string(output)}
+	// The install script's image pulls take most of the time
+	if err := runWithProgress(cmd, 0.3, 0.9, progressChan); err != nil {
+		logChan <- ui.LogMsg{Level: ui.LogWarning, Message: err.Error()}
 		return fmt.Errorf("failed to deploy Coolify via SSH: %w", err)
 	}
 
-	progressChan <- ui.StepProgressMsg{Progress: 0.8, Message: "Coolify deployed"}
+	progressChan <- ui.StepProgressMsg{Progress: 0.9, Message: "Coolify deployed"}
 
 	logChan <- ui.LogMsg{Level: ui.LogSuccess, Message: "Coolify deployment complete"}
 
 	return nil
 }
 
+// runWithProgress runs cmd, moving the step's bar between from and to as its
+// apt and docker output comes in
+func runWithProgress(cmd *exec.Cmd, from, to float64, progressChan chan<- ui.StepProgressMsg) error {
+	step := progress.NewStep(from, to, func(fraction float64, message string) {
+		progressChan <- ui.StepProgressMsg{Progress: fraction, Message: message}
+	})
+	script := progress.NewScript()
+	return progress.Run(cmd, func(line string) {
+		if u, ok := script.Line(line); ok {
+			step.Report(u)
+		}
+	})
+}
+
 // configureSSL configures SSL
 func (p *AWSProvider) configureSSL(progressChan chan<- ui.StepProgressMsg, logChan chan<- ui.LogMsg) error {
 	progressChan <- ui.StepProgressMsg{Progress: 0.3, Message: "Setting up Let's Encrypt"}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/progress"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 )
 
@@ -296,8 +297,8 @@ func (p *AzureProvider) createNetworkResources(progressChan chan<- ui.StepProgre
 
 		// Add rules
 		for i, port := range requiredPorts {
-			progress := 0.3 + (float64(i+1) / float64(len(requiredPorts)) * 0.5)
-			progressChan <- ui.StepProgressMsg{Progress: progress, Message: fmt.Sprintf("Opening port %d", port)}
+			done := 0.3 + (float64(i+1) / float64(len(requiredPorts)) * 0.5)
+			progressChan <- ui.StepProgressMsg{Progress: done, Message: fmt.Sprintf("Opening port %d", port)}
 			p.runAzCommand("network", "nsg", "rule", "create",
 				"--resource-group", p.config.Azure.ResourceGroup,
 				"--nsg-name", nsgName,
@@ -342,8 +343,14 @@ func (p *AzureProvider) createVirtualMachine(progressChan chan<- ui.StepProgress
 			return ui.NewDeploymentError("azure", "Create VM", fmt.Errorf("network interface not found - network setup may have failed"))
 		}
 
-		progressChan <- ui.StepProgressMsg{Progress: 0.5, Message: "Creating VM via SDK"}
+		progressChan <- ui.StepProgressMsg{Progress: 0.3, Message: "Creating VM via SDK"}
 
+		// Azure reports a percentage for some operations; otherwise the
+		// message shows its status and how long it has taken
+		step := progress.NewStep(0.3, 0.9, func(fraction float64, message string) {
+			progressChan <- ui.StepProgressMsg{Progress: fraction, Message: message}
+		})
+		start := time.Now()
 		_, err = p.sdkClient.CreateVM(VMCreateOpts{
 			Name:          vmName,
 			Size:          p.config.Azure.VMSize,
@@ -351,6 +358,10 @@ func (p *AzureProvider) createVirtualMachine(progressChan chan<- ui.StepProgress
 			SSHPublicKey:  sshKey,
 			NicID:         nicID,
 			CustomData:    cloudInit,
+			Progress: func(u progress.Update) {
+				u.Message = fmt.Sprintf("Creating VM via SDK (%s, %s)", orStatus(u.Message), time.Since(start).Round(time.Second))
+				step.Report(u)
+			},
 		})
 		if err != nil {
 			return ui.NewDeploymentError("azure", "Create VM", err)
@@ -445,45 +456,92 @@ func (p *AzureProvider) deployCoolify(progressChan chan<- ui.StepProgressMsg, lo
 		return fmt.Errorf("public IP not found")
 	}
 
-	progressChan <- ui.StepProgressMsg{Progress: 0.1, Message: "Waiting for cloud-init"}
+	progressChan <- ui.StepProgressMsg{Progress: 0.05, Message: "Waiting for cloud-init"}
 	logChan <- ui.LogMsg{Level: ui.LogInfo, Message: "Cloud-init is installing Docker and Coolify (this takes 5-10 min)..."}
 
-	// Wait for Docker to be ready (cloud-init completion indicator)
-	// Cloud-init typically takes 8-10 minutes for: package updates + docker install + Coolify install
-	maxWait := 10 * time.Minute
+	// Follow cloud-init's stage and the markers, apt and docker output in its log.
+	// It typically takes 8-10 minutes for: package updates + docker install + Coolify install
+	step := progress.NewStep(0.05, 0.95, func(fraction float64, message string) {
+		progressChan <- ui.StepProgressMsg{Progress: fraction, Message: message}
+	})
+	maxWait := 15 * time.Minute
 	start := time.Now()
 	attempt := 0
 
 	for time.Since(start) < maxWait {
 		attempt++
 		elapsed := time.Since(start).Round(time.Second)
-		progress := 0.1 + (float64(elapsed.Seconds()) / maxWait.Seconds() * 0.8)
-		progressChan <- ui.StepProgressMsg{Progress: progress, Message: fmt.Sprintf("Checking Docker... (%s elapsed)", elapsed)}
-
-		cmd := exec.Command("ssh",
-			"-o", "StrictHostKeyChecking=no",
-			"-o", "ConnectTimeout=10",
-			"-o", "BatchMode=yes",
-			"-o", "UserKnownHostsFile=/dev/null",
-			"-o", "LogLevel=ERROR",
-			fmt.Sprintf("%s@%s", p.config.Azure.AdminUsername, publicIP),
-			"sudo docker ps 2>/dev/null")
-
-		if err := cmd.Run(); err == nil {
-			logChan <- ui.LogMsg{Level: ui.LogSuccess, Message: fmt.Sprintf("Docker is ready (attempt %d, %s)", attempt, elapsed)}
-			progressChan <- ui.StepProgressMsg{Progress: 0.95, Message: "Coolify deployed"}
-			return nil
+
+		output, err := p.sshOutput(publicIP, progress.CloudInitCommand)
+		if err != nil {
+			step.Message(fmt.Sprintf("Waiting for SSH... (%s elapsed)", elapsed))
+		} else {
+			state := progress.ParseCloudInit(output)
+			switch state.Status {
+			case "error":
+				return fmt.Errorf("cloud-init failed on %s:\n%s", publicIP, lastLines(state.Log, 20))
+			case "done":
+				if err := p.sshRun(publicIP, "sudo docker ps"); err == nil {
+					logChan <- ui.LogMsg{Level: ui.LogSuccess, Message: fmt.Sprintf("Docker is ready (attempt %d, %s)", attempt, elapsed)}
+					step.Report(progress.Update{Fraction: 1, Message: "Coolify deployed"})
+					return nil
+				}
+				step.Message("cloud-init finished, waiting for Docker")
+			default:
+				step.Report(state.Update)
+			}
 		}
 
-		// Log status every 5 attempts (every 2.5 min)
-		if attempt%5 == 0 {
+		// Log status every 10 attempts (every 2.5 min)
+		if attempt%10 == 0 {
 			logChan <- ui.LogMsg{Level: ui.LogDebug, Message: fmt.Sprintf("Still waiting... attempt %d (%s elapsed)", attempt, elapsed)}
 		}
 
-		time.Sleep(30 * time.Second)
+		time.Sleep(15 * time.Second)
+	}
+
+	return fmt.Errorf("timeout waiting for Docker/Coolify after %s (SSH to %s@%s may not be ready)", maxWait, p.config.Azure.AdminUsername, publicIP)
+}
+
+// sshArgs returns the ssh arguments to run command on the VM
+func (p *AzureProvider) sshArgs(publicIP, command string) []string {
+	return []string{
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "ConnectTimeout=10",
+		"-o", "BatchMode=yes",
+		"-o", "UserKnownHostsFile=/dev/null",
+		"-o", "LogLevel=ERROR",
+		fmt.Sprintf("%s@%s", p.config.Azure.AdminUsername, publicIP),
+		command,
 	}
+}
+
+// sshOutput runs command on the VM and returns its output
+func (p *AzureProvider) sshOutput(publicIP, command string) (string, error) {
+	output, err := exec.Command("ssh", p.sshArgs(publicIP, command)...).Output()
+	return string(output), err
+}
+
+// sshRun runs command on the VM
+func (p *AzureProvider) sshRun(publicIP, command string) error {
+	return exec.Command("ssh", p.sshArgs(publicIP, command)...).Run()
+}
 
-	return fmt.Errorf("timeout waiting for Docker/Coolify after 10 minutes (SSH to %s@%s may not be ready)", p.config.Azure.AdminUsername, publicIP)
+// orStatus returns an Azure operation status for display
+func orStatus(status string) string {
+	if status == "" || status == "InProgress" {
+		return "in progress"
+	}
+	return strings.ToLower(status)
+}
+
+// lastLines returns the last n lines of s
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
 }
 
 // runHealthChecks performs health checks
@@ -512,26 +570,36 @@ func (p *AzureProvider) runHealthChecks(progressChan chan<- ui.StepProgressMsg,
 	return nil
 }
 
-// getCloudInit returns the cloud-init script
+// getCloudInit returns the cloud-init script. Its steps print markers and
+// apt status lines so deployCoolify can follow them in cloud-init's log.
 func (p *AzureProvider) getCloudInit() string {
-	return `#cloud-config
+	return fmt.Sprintf(`#cloud-config
 package_update: true
-package_upgrade: true
 
 packages:
   - curl
   - git
 
 runcmd:
-  - curl -fsSL https://get.docker.com | sh
+  - %q
+  - %q
+  - %q
+  - %q
   - systemctl enable docker
   - systemctl start docker
   - usermod -aG docker ubuntu
+  - %q
   - mkdir -p /data/coolify/source
   - echo "COOLIFY_POSTGRES_VERSION=17-trixie" >> /data/coolify/source/.env
   - echo "COOLIFY_REDIS_VERSION=8.4.0-bookworm" >> /data/coolify/source/.env
   - curl -fsSL https://cdn.coollabs.io/coolify/install.sh | bash
-`
+`,
+		"echo '"+progress.Marker(1, 3, "Upgrading packages")+"'",
+		progress.WithAptStatus("apt-get upgrade -y"),
+		"echo '"+progress.Marker(2, 3, "Installing Docker")+"'",
+		progress.WithAptStatus("curl -fsSL https://get.docker.com | sh"),
+		"echo '"+progress.Marker(3, 3, "Installing Coolify")+"'",
+	)
 }
 
 // getSSHPublicKey reads the SSH public key
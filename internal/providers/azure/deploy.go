@@ -2,21 +2,29 @@ package azure
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"time"
 
-	"github.com/entro314-labs/cool-kit/internal/azureconfig"
+	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 )
 
+// DeployOptions are the answers to Deploy's questions given up front. Empty
+// fields are asked for, or taken from the azure config in non-interactive
+// mode; the admin password has no default.
+type DeployOptions struct {
+	ResourceGroup string
+	VMName        string
+	AdminEmail    string
+	AdminPassword string
+}
+
 // Deploy provisions Azure VM and deploys Coolify
-func Deploy() error {
+func Deploy(opts DeployOptions) error {
 	ui.Section("Azure Coolify Deployment")
 	ui.Dim("Deploying Coolify to Microsoft Azure")
 
 	// Load or create configuration
-	ctx, err := loadDeploymentContext()
+	ctx, err := loadDeploymentContext(opts)
 	if err != nil {
 		return err
 	}
@@ -61,19 +69,12 @@ func Deploy() error {
 }
 
 // loadDeploymentContext loads or creates deployment configuration
-func loadDeploymentContext() (*DeploymentContext, error) {
+func loadDeploymentContext(opts DeployOptions) (*DeploymentContext, error) {
 	ui.Info("Loading configuration")
 
-	// Check for existing config
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	configPath := filepath.Join(homeDir, ".coolify", "azure-config.json")
-	cfg, err := azureconfig.LoadOrDefault(configPath)
+	cfg, err := azureSettings()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load configuration: %w", err)
+		return nil, err
 	}
 
 	// Validate configuration
@@ -83,30 +84,35 @@ func loadDeploymentContext() (*DeploymentContext, error) {
 	}
 
 	// Collect deployment information
-	resourceGroup, err := ui.InputWithDefault("Resource Group name", "coolify-rg")
-	if err != nil {
-		return nil, err
-	}
-
-	vmName, err := ui.InputWithDefault("VM name", "coolify-vm")
-	if err != nil {
-		return nil, err
+	resourceGroup := opts.ResourceGroup
+	if resourceGroup == "" {
+		if resourceGroup, err = ui.InputWithDefault("Resource Group name", cfg.ResourceGroup); err != nil {
+			return nil, err
+		}
 	}
 
-	adminEmail, err := ui.Input("Admin email", "")
-	if err != nil {
-		return nil, err
+	vmName := opts.VMName
+	if vmName == "" {
+		if vmName, err = ui.InputWithDefault("VM name", cfg.VMName); err != nil {
+			return nil, err
+		}
 	}
 
-	adminPassword, err := ui.Password("Admin password")
-	if err != nil {
-		return nil, err
+	adminEmail := opts.AdminEmail
+	if adminEmail == "" {
+		if adminEmail, err = ui.InputWithDefault("Admin email", cfg.Coolify.DefaultAdminEmail); err != nil {
+			return nil, err
+		}
 	}
 
-	// Expand SSH key path
-	sshKeyPath := cfg.Infrastructure.SSHPublicKeyPath
-	if sshKeyPath[:2] == "~/" {
-		sshKeyPath = filepath.Join(homeDir, sshKeyPath[2:])
+	adminPassword := opts.AdminPassword
+	if adminPassword == "" {
+		if !ui.Interactive() {
+			return nil, &ui.NonInteractiveError{What: "Admin password", Hint: "set " + config.EnvAdminPassword}
+		}
+		if adminPassword, err = ui.Password("Admin password"); err != nil {
+			return nil, err
+		}
 	}
 
 	// Create deployment context
@@ -114,15 +120,15 @@ func loadDeploymentContext() (*DeploymentContext, error) {
 		Config:        cfg,
 		ResourceGroup: resourceGroup,
 		VMName:        vmName,
-		Location:      cfg.Infrastructure.Location,
-		VMSize:        cfg.Infrastructure.VMSize,
+		Location:      cfg.Location,
+		VMSize:        cfg.VMSize,
 		VNetName:      fmt.Sprintf("%s-vnet", resourceGroup),
 		SubnetName:    fmt.Sprintf("%s-subnet", resourceGroup),
 		NSGName:       fmt.Sprintf("%s-nsg", resourceGroup),
 		PublicIPName:  fmt.Sprintf("%s-ip", resourceGroup),
 		NICName:       fmt.Sprintf("%s-nic", resourceGroup),
-		AdminUsername: cfg.Infrastructure.AdminUsername,
-		SSHKeyPath:    sshKeyPath,
+		AdminUsername: cfg.AdminUsername,
+		SSHKeyPath:    config.ExpandHome(cfg.SSHKeyPath),
 		AdminEmail:    adminEmail,
 		AdminPassword: adminPassword,
 	}
@@ -187,7 +193,7 @@ func Update() error {
 	sshClient := NewSSHClient(ctx.PublicIP, ctx.AdminUsername, ctx.SSHKeyPath)
 
 	// Ask about auto-rollback
-	autoRollback, err := ui.Confirm("Enable automatic rollback on failure?")
+	autoRollback, err := ui.Ask("Enable automatic rollback on failure?", false)
 	if err != nil {
 		return err
 	}
@@ -336,17 +342,15 @@ func Rollback() error {
 func ManageConfig() error {
 	ui.Section("Azure Configuration Management")
 
-	homeDir, err := os.UserHomeDir()
+	cfg, err := azureSettings()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	configPath := filepath.Join(homeDir, ".coolify", "azure-config.json")
-
 	// Show menu
 	ui.Info("Configuration Options")
 	ui.Dim("1. View current configuration")
-	ui.Dim("2. Edit configuration file")
+	ui.Dim("2. Change settings")
 	ui.Dim("3. Validate configuration")
 	ui.Dim("4. Reset to defaults")
 	ui.Dim("5. Show config file path")
@@ -360,30 +364,26 @@ func ManageConfig() error {
 	switch choice {
 	case "1":
 		// View configuration
-		cfg, err := azureconfig.LoadOrDefault(configPath)
-		if err != nil {
-			return fmt.Errorf("failed to load configuration: %w", err)
-		}
-
 		ui.Info("Current Configuration")
-		ui.Dim(fmt.Sprintf("Location: %s", cfg.Infrastructure.Location))
-		ui.Dim(fmt.Sprintf("VM Size: %s", cfg.Infrastructure.VMSize))
-		ui.Dim(fmt.Sprintf("Admin Username: %s", cfg.Infrastructure.AdminUsername))
+		ui.Dim(fmt.Sprintf("Location: %s", cfg.Location))
+		ui.Dim(fmt.Sprintf("Resource Group: %s", cfg.ResourceGroup))
+		ui.Dim(fmt.Sprintf("VM Name: %s", cfg.VMName))
+		ui.Dim(fmt.Sprintf("VM Size: %s", cfg.VMSize))
+		ui.Dim(fmt.Sprintf("Admin Username: %s", cfg.AdminUsername))
 		ui.Dim(fmt.Sprintf("VNet Prefix: %s", cfg.Networking.VNetAddressPrefix))
 		ui.Dim(fmt.Sprintf("Subnet Prefix: %s", cfg.Networking.SubnetAddressPrefix))
 
 	case "2":
-		// Edit configuration
-		ui.Info(fmt.Sprintf("Edit configuration at: %s", configPath))
-		ui.Dim("After editing, run 'cool-kit azure config' and choose option 3 to validate")
+		// Change settings
+		ui.Info("Change the Azure settings with 'cool-kit config set', which checks each value:")
+		ui.Dim("  cool-kit config set azure.vm_size Standard_D2s_v3")
+		ui.Dim("  cool-kit config set azure.location westeurope")
+		ui.Dim("  cool-kit config set azure.networking.vnet_address_prefix 10.1.0.0/16")
+		ui.Dim("  cool-kit config unset azure.resource_group")
+		ui.Dim("Run 'cool-kit config get azure' to see them all")
 
 	case "3":
 		// Validate configuration
-		cfg, err := azureconfig.Load(configPath)
-		if err != nil {
-			return fmt.Errorf("failed to load configuration: %w", err)
-		}
-
 		if err := cfg.Validate(); err != nil {
 			ui.Error("Configuration validation failed")
 			return err
@@ -402,8 +402,7 @@ func ManageConfig() error {
 			return nil
 		}
 
-		cfg := azureconfig.DefaultConfig()
-		if err := azureconfig.Save(cfg, configPath); err != nil {
+		if err := config.UnsetValue("azure"); err != nil {
 			return fmt.Errorf("failed to save configuration: %w", err)
 		}
 
@@ -411,7 +410,7 @@ func ManageConfig() error {
 
 	case "5":
 		// Show config path
-		ui.Info(fmt.Sprintf("Configuration file: %s", configPath))
+		ui.Info(fmt.Sprintf("Configuration file: %s (azure section)", config.Path()))
 
 	default:
 		ui.Warning("Invalid option")
@@ -420,6 +419,14 @@ func ManageConfig() error {
 	return nil
 }
 
+// azureSettings returns the azure section of the CLI config
+func azureSettings() (*config.AzureConfig, error) {
+	if err := config.Initialize(); err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return &config.Get().Azure, nil
+}
+
 // SSH opens an SSH connection to the Azure instance
 func SSH() error {
 	ui.Section("Azure SSH Connection")
@@ -444,44 +451,31 @@ func SSH() error {
 func loadExistingDeploymentContext() (*DeploymentContext, error) {
 	ui.Info("Loading deployment configuration")
 
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	configPath := filepath.Join(homeDir, ".coolify", "azure-config.json")
-	cfg, err := azureconfig.Load(configPath)
+	cfg, err := azureSettings()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load configuration: %w\nRun 'cool-kit azure deploy' first", err)
+		return nil, err
 	}
 
 	// Get resource group and VM name from user or config
-	resourceGroup, err := ui.InputWithDefault("Resource Group name", "coolify-rg")
+	resourceGroup, err := ui.InputWithDefault("Resource Group name", cfg.ResourceGroup)
 	if err != nil {
 		return nil, err
 	}
 
-	vmName, err := ui.InputWithDefault("VM name", "coolify-vm")
+	vmName, err := ui.InputWithDefault("VM name", cfg.VMName)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get public IP from Azure
 	ctx := &DeploymentContext{
 		Config:        cfg,
 		ResourceGroup: resourceGroup,
 		VMName:        vmName,
-		Location:      cfg.Infrastructure.Location,
-		VMSize:        cfg.Infrastructure.VMSize,
-		AdminUsername: cfg.Infrastructure.AdminUsername,
-	}
-
-	// Expand SSH key path
-	sshKeyPath := cfg.Infrastructure.SSHPublicKeyPath
-	if len(sshKeyPath) >= 2 && sshKeyPath[:2] == "~/" {
-		sshKeyPath = filepath.Join(homeDir, sshKeyPath[2:])
+		Location:      cfg.Location,
+		VMSize:        cfg.VMSize,
+		AdminUsername: cfg.AdminUsername,
+		SSHKeyPath:    config.ExpandHome(cfg.SSHKeyPath),
 	}
-	ctx.SSHKeyPath = sshKeyPath
 
 	// Get public IP from Azure
 	provisioner := NewProvisioner(ctx)
@@ -247,10 +247,10 @@ func (p *Provisioner) createVM() error {
 		"--location", p.ctx.Location,
 		"--nics", p.ctx.NICName,
 		"--size", p.ctx.VMSize,
-		"--image", p.ctx.Config.Infrastructure.OSImage,
+		"--image", p.ctx.Config.OSImage,
 		"--admin-username", p.ctx.AdminUsername,
 		"--ssh-key-values", string(sshKeyData),
-		"--os-disk-size-gb", fmt.Sprintf("%d", p.ctx.Config.Infrastructure.OSDiskSizeGB),
+		"--os-disk-size-gb", fmt.Sprintf("%d", p.ctx.Config.OSDiskSizeGB),
 		"--output", "none")
 
 	if err := cmd.Run(); err != nil {
@@ -8,11 +8,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v5"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/entro314-labs/cool-kit/internal/progress"
 )
 
 // ErrVMNotFound is returned when a VM cannot be found
@@ -336,6 +338,10 @@ type VMCreateOpts struct {
 	SSHPublicKey  string
 	NicID         string
 	CustomData    string // cloud-init script
+
+	// Progress, when set, is called with each status Azure reports while
+	// the VM is created
+	Progress func(progress.Update)
 }
 
 // VMInfo contains information about a VM
@@ -423,7 +429,7 @@ func (c *SDKClient) CreateVM(opts VMCreateOpts) (*VMInfo, error) {
 		return nil, fmt.Errorf("failed to create VM: %w", err)
 	}
 
-	resp, err := poller.PollUntilDone(c.ctx, nil)
+	resp, err := pollWithProgress(c.ctx, poller, opts.Progress)
 	if err != nil {
 		return nil, fmt.Errorf("failed waiting for VM creation: %w", err)
 	}
@@ -436,6 +442,35 @@ func (c *SDKClient) CreateVM(opts VMCreateOpts) (*VMInfo, error) {
 	}, nil
 }
 
+// pollWithProgress waits for poller like PollUntilDone, passing each
+// operation status Azure returns to report
+func pollWithProgress[T any](ctx context.Context, poller *runtime.Poller[T], report func(progress.Update)) (T, error) {
+	if report == nil {
+		return poller.PollUntilDone(ctx, nil)
+	}
+	for !poller.Done() {
+		resp, err := poller.Poll(ctx)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		if body, err := runtime.Payload(resp); err == nil {
+			u, _ := progress.ParseAzureOperation(body)
+			report(u)
+		}
+		if poller.Done() {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+	return poller.Result(ctx)
+}
+
 // GetVM retrieves VM information
 func (c *SDKClient) GetVM(name string) (*VMInfo, error) {
 	if c.computeClient == nil {
@@ -1,12 +1,12 @@
 package azure
 
 import (
-	"github.com/entro314-labs/cool-kit/internal/azureconfig"
+	"github.com/entro314-labs/cool-kit/internal/config"
 )
 
 // DeploymentContext holds all information needed for Azure deployment
 type DeploymentContext struct {
-	Config *azureconfig.Config
+	Config *config.AzureConfig
 
 	// Resource identifiers
 	ResourceGroup string
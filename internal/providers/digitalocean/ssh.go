@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"strings"
 	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/ui"
 )
 
 // SSHClient handles SSH connections to DigitalOcean droplets
@@ -138,12 +139,11 @@ func DestroyInstance() error {
 		return fmt.Errorf("no Coolify droplet found")
 	}
 
-	fmt.Printf("This will delete droplet: %s (%s)\n", droplet.Name, droplet.PublicIP)
-	fmt.Print("Type 'yes' to confirm: ")
-
-	var confirm string
-	fmt.Scanln(&confirm)
-	if strings.ToLower(confirm) != "yes" {
+	confirmed, err := ui.TypeToConfirm(fmt.Sprintf("This will delete droplet: %s (%s)", droplet.Name, droplet.PublicIP), "yes")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
 		return fmt.Errorf("deletion cancelled")
 	}
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/entro314-labs/cool-kit/internal/config"
 	"github.com/entro314-labs/cool-kit/internal/git"
+	"github.com/entro314-labs/cool-kit/internal/progress"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 	"github.com/entro314-labs/cool-kit/internal/utils"
 )
@@ -189,10 +190,18 @@ func (p *DockerProvider) pullImages(progressChan chan<- ui.StepProgressMsg, logC
 
 	cmd := exec.Command("docker-compose", "-f", composeFile, "pull")
 	cmd.Dir = workDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 
-	if err := cmd.Run(); err != nil {
+	// Move the bar as the layers come in instead of printing over the TUI
+	step := progress.NewStep(0.2, 0.9, func(fraction float64, message string) {
+		progressChan <- ui.StepProgressMsg{Progress: fraction, Message: message}
+	})
+	pull := progress.NewDockerPull()
+	err := progress.Run(cmd, func(line string) {
+		if u, ok := pull.Line(line); ok {
+			step.Report(u)
+		}
+	})
+	if err != nil {
 		return fmt.Errorf("failed to pull images: %w", err)
 	}
 
@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"strings"
 	"time"
 
 	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/ui"
 )
 
 // SSHClient handles SSH connections to Hetzner servers
@@ -168,12 +168,11 @@ func DestroyInstance() error {
 	}
 
 	// Confirm deletion
-	fmt.Printf("This will delete server: %s (%s)\n", server.Name, server.PublicIPv4)
-	fmt.Print("Type 'yes' to confirm: ")
-
-	var confirm string
-	fmt.Scanln(&confirm)
-	if strings.ToLower(confirm) != "yes" {
+	confirmed, err := ui.TypeToConfirm(fmt.Sprintf("This will delete server: %s (%s)", server.Name, server.PublicIPv4), "yes")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
 		return fmt.Errorf("deletion cancelled")
 	}
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/progress"
 	"github.com/entro314-labs/cool-kit/internal/ui"
 )
 
@@ -217,10 +218,18 @@ docker compose -f docker-compose.prod.yml up -d
 `
 	args := p.buildSSHArgs(deployCmd)
 	cmd := exec.Command("ssh", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 
-	if err := cmd.Run(); err != nil {
+	// Move the bar as the layers come in instead of printing over the TUI
+	step := progress.NewStep(0.2, 0.7, func(fraction float64, message string) {
+		progressChan <- ui.StepProgressMsg{Progress: fraction, Message: message}
+	})
+	pull := progress.NewDockerPull()
+	err := progress.Run(cmd, func(line string) {
+		if u, ok := pull.Line(line); ok {
+			step.Report(u)
+		}
+	})
+	if err != nil {
 		// Try with docker-compose (v1) as fallback
 		deployCmd = `
 cd /data/coolify/source
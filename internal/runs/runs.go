@@ -0,0 +1,403 @@
+// Package runs saves a transcript of each install and app deployment: every
+// step, progress message and log line goes to ~/.cool-kit/runs/<id>.log, with
+// a JSON summary next to it, so past runs can be reviewed after the TUI,
+// which only shows the last lines, has gone.
+package runs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of run
+const (
+	KindInstall = "install"
+	KindDeploy  = "deploy"
+)
+
+// Statuses of a run and its steps
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// keepRuns is how many runs are kept; older ones are removed as new ones start
+const keepRuns = 100
+
+// idLayout names the files of a run after the time it started
+const idLayout = "2006-01-02T15-04-05"
+
+// ErrNotFound is returned for a run that doesn't exist
+var ErrNotFound = errors.New("run not found")
+
+// Run is the summary of a run. A run that is still running when it's read
+// was interrupted, unless it's the current one.
+type Run struct {
+	ID         string            `json:"id"`
+	Kind       string            `json:"kind"`
+	Target     string            `json:"target"`
+	Status     string            `json:"status"`
+	Error      string            `json:"error"`
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt *time.Time        `json:"finished_at"`
+	Details    map[string]string `json:"details"`
+	Steps      []Step            `json:"steps"`
+	Log        string            `json:"log"`
+}
+
+// Step is a step of a run
+type Step struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// Duration returns how long the run took, or has taken so far
+func (r *Run) Duration() time.Duration {
+	if r.FinishedAt != nil {
+		return r.FinishedAt.Sub(r.StartedAt)
+	}
+	return time.Since(r.StartedAt)
+}
+
+// Dir returns the directory runs are saved in
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".cool-kit", "runs"), nil
+}
+
+// Recorder writes the transcript of a run as it goes. Its methods do
+// nothing on a nil Recorder, so a run goes on when its transcript can't be
+// saved.
+type Recorder struct {
+	mu      sync.Mutex
+	run     Run
+	dir     string
+	log     *os.File
+	closed  bool
+	current int
+	last    string
+}
+
+// Start begins the transcript of a run of kind for target, with the names
+// of its steps if it has them
+func Start(kind, target string, steps []string) (*Recorder, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	now := time.Now()
+	id := now.Format(idLayout)
+	for n := 2; exists(filepath.Join(dir, id+".json")); n++ {
+		id = fmt.Sprintf("%s-%d", now.Format(idLayout), n)
+	}
+
+	logPath := filepath.Join(dir, id+".log")
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create run log: %w", err)
+	}
+
+	r := &Recorder{
+		dir: dir,
+		log: f,
+		run: Run{
+			ID:        id,
+			Kind:      kind,
+			Target:    target,
+			Status:    StatusRunning,
+			StartedAt: now,
+			Details:   map[string]string{},
+			Steps:     make([]Step, 0, len(steps)),
+			Log:       logPath,
+		},
+	}
+	for _, name := range steps {
+		r.run.Steps = append(r.run.Steps, Step{Name: name, Status: StatusPending})
+	}
+	r.writeLine(now, "INFO", fmt.Sprintf("Started %s: %s", kind, target))
+	if len(r.run.Steps) > 0 {
+		r.startStep(0, now)
+	}
+	if err := r.save(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	prune(dir, id)
+	return r, nil
+}
+
+// ID returns the run's ID
+func (r *Recorder) ID() string {
+	if r == nil {
+		return ""
+	}
+	return r.run.ID
+}
+
+// LogPath returns the path of the run's log
+func (r *Recorder) LogPath() string {
+	if r == nil {
+		return ""
+	}
+	return r.run.Log
+}
+
+// Summary returns the run as it stands
+func (r *Recorder) Summary() Run {
+	if r == nil {
+		return Run{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run := r.run
+	run.Details = make(map[string]string, len(r.run.Details))
+	for k, v := range r.run.Details {
+		run.Details[k] = v
+	}
+	run.Steps = append([]Step(nil), r.run.Steps...)
+	return run
+}
+
+// Detail records a fact about the run, like the project it deploys
+func (r *Recorder) Detail(key, value string) {
+	if r == nil || value == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Details[key] = value
+	r.writeLine(time.Now(), "INFO", key+": "+value)
+	_ = r.save()
+}
+
+// Progress records the progress of a step. Like the progress TUI, progress
+// below 1 is about the running step and progress of 1 finishes step index
+// and starts the next one.
+func (r *Recorder) Progress(index int, fraction float64, message string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if fraction < 1 {
+		index = r.current
+	}
+	name := ""
+	if index < len(r.run.Steps) {
+		name = r.run.Steps[index].Name
+	}
+	if message != "" && message != r.last {
+		r.last = message
+		r.writeLine(now, "STEP", fmt.Sprintf("[%s] %3.0f%% %s", r.stepLabel(index, name), fraction*100, message))
+	}
+	if fraction >= 1 && index < len(r.run.Steps) && r.run.Steps[index].Status != StatusSucceeded {
+		r.run.Steps[index].Status = StatusSucceeded
+		r.run.Steps[index].FinishedAt = &now
+		if index+1 < len(r.run.Steps) {
+			r.startStep(index+1, now)
+		}
+		_ = r.save()
+	}
+}
+
+// Log records a log line at a level like info, warning or error
+func (r *Recorder) Log(level, message string) {
+	if r == nil {
+		return
+	}
+	level = strings.ToUpper(level)
+	if level == "WARNING" {
+		level = "WARN"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(message, "\n"), "\n") {
+		r.writeLine(time.Now(), level, line)
+	}
+}
+
+// Finish ends the run, failed when err isn't nil. Only the first call counts.
+func (r *Recorder) Finish(err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.run.FinishedAt != nil {
+		return
+	}
+
+	now := time.Now()
+	r.run.FinishedAt = &now
+	r.run.Status = StatusSucceeded
+	if err != nil {
+		r.run.Status = StatusFailed
+		r.run.Error = err.Error()
+	}
+	for i := range r.run.Steps {
+		if r.run.Steps[i].Status == StatusRunning {
+			r.run.Steps[i].Status = r.run.Status
+			r.run.Steps[i].FinishedAt = &now
+		}
+	}
+
+	if err != nil {
+		r.writeLine(now, "ERROR", "Failed: "+err.Error())
+	} else {
+		r.writeLine(now, "INFO", "Finished in "+now.Sub(r.run.StartedAt).Round(time.Second).String())
+	}
+	_ = r.save()
+	r.log.Close()
+	r.closed = true
+}
+
+func (r *Recorder) startStep(index int, now time.Time) {
+	r.current = index
+	r.run.Steps[index].Status = StatusRunning
+	r.run.Steps[index].StartedAt = &now
+	r.writeLine(now, "INFO", fmt.Sprintf("[%s] started", r.stepLabel(index, r.run.Steps[index].Name)))
+}
+
+func (r *Recorder) stepLabel(index int, name string) string {
+	if name == "" {
+		return fmt.Sprintf("step %d", index+1)
+	}
+	return fmt.Sprintf("%d/%d %s", index+1, len(r.run.Steps), name)
+}
+
+// writeLine appends a line to the log. The timestamp leads, so the log
+// viewer can jump through it.
+func (r *Recorder) writeLine(t time.Time, level, text string) {
+	if r.closed {
+		return
+	}
+	fmt.Fprintf(r.log, "%s %-5s %s\n", t.UTC().Format(time.RFC3339), level, text)
+}
+
+// save writes the summary, replacing the last one
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.run, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(r.dir, r.run.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to save run summary: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// List returns the saved runs, newest first
+func List() ([]Run, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]Run, 0, len(paths))
+	for _, path := range paths {
+		run, err := load(path)
+		if err != nil {
+			// A summary that can't be read doesn't hide the others
+			continue
+		}
+		runs = append(runs, *run)
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+	return runs, nil
+}
+
+// Find returns the run with an ID, or that starts with it, or the latest
+// run for "" or "latest"
+func Find(id string) (*Run, error) {
+	runs, err := List()
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, ErrNotFound
+	}
+	if id == "" || id == "latest" {
+		return &runs[0], nil
+	}
+
+	var matches []*Run
+	for i := range runs {
+		if runs[i].ID == id {
+			return &runs[i], nil
+		}
+		if strings.HasPrefix(runs[i].ID, id) {
+			matches = append(matches, &runs[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("%d runs start with %s: use more of the ID", len(matches), id)
+}
+
+func load(path string) (*Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return &run, nil
+}
+
+// prune removes the oldest runs beyond keepRuns, never the current one
+func prune(dir, current string) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(paths) <= keepRuns {
+		return
+	}
+	// IDs are timestamps, so they sort oldest first
+	sort.Strings(paths)
+	for _, path := range paths[:len(paths)-keepRuns] {
+		id := strings.TrimSuffix(filepath.Base(path), ".json")
+		if id == current {
+			continue
+		}
+		os.Remove(path)
+		os.Remove(filepath.Join(dir, id+".log"))
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
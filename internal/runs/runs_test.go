@@ -0,0 +1,116 @@
+package runs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorderWritesLogAndSummary(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	r, err := Start(KindInstall, "AWS", []string{"Launch instance", "Install Docker"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Progress(0, 0.5, "Creating VM")
+	r.Progress(0, 0.5, "Creating VM") // repeated, logged once
+	r.Log("warning", "slow region")
+	r.Progress(0, 1, "Completed: Launch instance")
+	r.Progress(0, 0.3, "Installing Docker")
+	r.Finish(errors.New("apt failed"))
+	r.Finish(nil) // only the first call counts
+	r.Log("info", "after the end")
+
+	run, err := Find("latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.ID != r.ID() || run.Status != StatusFailed || run.Error != "apt failed" || run.FinishedAt == nil {
+		t.Errorf("summary = %+v", run)
+	}
+	if run.Steps[0].Status != StatusSucceeded || run.Steps[1].Status != StatusFailed {
+		t.Errorf("steps = %+v", run.Steps)
+	}
+
+	data, err := os.ReadFile(run.Log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+	for _, want := range []string{
+		"INFO  Started install: AWS",
+		"STEP  [1/2 Launch instance]  50% Creating VM",
+		"WARN  slow region",
+		"INFO  [2/2 Install Docker] started",
+		"STEP  [2/2 Install Docker]  30% Installing Docker",
+		"ERROR Failed: apt failed",
+	} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log is missing %q:\n%s", want, log)
+		}
+	}
+	if strings.Count(log, "Creating VM") != 1 || strings.Contains(log, "after the end") {
+		t.Errorf("log =\n%s", log)
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, err := Find(""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find with no runs = %v", err)
+	}
+
+	first, _ := Start(KindDeploy, "web", nil)
+	first.Finish(nil)
+	second, _ := Start(KindDeploy, "api", nil)
+	second.Finish(nil)
+	if first.ID() == second.ID() {
+		t.Fatal("two runs in the same second got the same ID")
+	}
+
+	runs, err := List()
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("List = %v, %v", runs, err)
+	}
+	if got, err := Find(first.ID()); err != nil || got.Target != "web" {
+		t.Errorf("Find(%s) = %+v, %v", first.ID(), got, err)
+	}
+	if _, err := Find("1999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find of a missing run = %v", err)
+	}
+	if _, err := Find(first.ID()[:4]); err == nil {
+		t.Error("an ambiguous prefix found a run")
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.Detail("project", "web")
+	r.Progress(0, 1, "done")
+	r.Log("info", "hello")
+	r.Finish(nil)
+	if r.ID() != "" || r.LogPath() != "" {
+		t.Error("nil recorder has an ID")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < keepRuns+3; i++ {
+		id := filepath.Join(dir, "2024-01-01T00-00-00-"+string(rune('a'+i/26))+string(rune('a'+i%26)))
+		os.WriteFile(id+".json", []byte("{}"), 0600)
+		os.WriteFile(id+".log", nil, 0600)
+	}
+	prune(dir, "")
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	logs, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(paths) != keepRuns || len(logs) != keepRuns {
+		t.Errorf("kept %d summaries and %d logs, want %d", len(paths), len(logs), keepRuns)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2024-01-01T00-00-00-aa.json")); !os.IsNotExist(err) {
+		t.Error("the oldest run wasn't removed")
+	}
+}
@@ -0,0 +1,217 @@
+// Package secretscan detects credentials in files before they are pushed.
+package secretscan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// AllowMarker skips a line when it appears anywhere on it
+const AllowMarker = "coolkit:allow-secret"
+
+// maxFileSize is the largest file that is scanned; bigger files are assumed to be assets
+const maxFileSize = 1 << 20
+
+// Rule matches a kind of secret in file contents
+type Rule struct {
+	ID          string
+	Description string
+	Pattern     *regexp.Regexp
+}
+
+// NewRule compiles a custom rule
+func NewRule(id, description, pattern string) (Rule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid pattern for rule %q: %w", id, err)
+	}
+	if description == "" {
+		description = id
+	}
+	return Rule{ID: id, Description: description, Pattern: re}, nil
+}
+
+// DefaultRules returns the built-in rules
+func DefaultRules() []Rule {
+	return []Rule{
+		{"aws-access-key-id", "AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+		{"aws-secret-access-key", "AWS secret access key", regexp.MustCompile(`(?i)aws.{0,20}(secret|private).{0,20}['"=:]\s*['"]?[0-9a-zA-Z/+]{40}\b`)},
+		{"private-key", "Private key", regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`)},
+		{"github-token", "GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+		{"gitlab-token", "GitLab personal access token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20,}`)},
+		{"slack-token", "Slack token", regexp.MustCompile(`\bxox[baprs]-[0-9A-Za-z\-]{10,}`)},
+		{"slack-webhook", "Slack webhook URL", regexp.MustCompile(`https://hooks\.slack\.com/services/T[A-Z0-9]+/B[A-Z0-9]+/[A-Za-z0-9]+`)},
+		{"stripe-live-key", "Stripe live secret key", regexp.MustCompile(`\b[sr]k_live_[0-9a-zA-Z]{24,}\b`)},
+		{"google-api-key", "Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+		{"openai-api-key", "OpenAI API key", regexp.MustCompile(`\bsk-(proj-)?[A-Za-z0-9_\-]{32,}\b`)},
+		{"npm-token", "npm access token", regexp.MustCompile(`\bnpm_[A-Za-z0-9]{36}\b`)},
+	}
+}
+
+// envFileRuleID reports committed dotenv files, which usually hold secrets
+const envFileRuleID = "env-file"
+
+// envFileTemplates are dotenv files meant to be committed
+var envFileTemplates = map[string]bool{
+	".env.example":  true,
+	".env.sample":   true,
+	".env.template": true,
+	".env.dist":     true,
+}
+
+// Finding is a potential secret in a file
+type Finding struct {
+	File        string
+	Line        int
+	RuleID      string
+	Description string
+	Match       string
+}
+
+// String formats the finding as file:line description (match)
+func (f Finding) String() string {
+	if f.Line == 0 {
+		return fmt.Sprintf("%s: %s", f.File, f.Description)
+	}
+	return fmt.Sprintf("%s:%d: %s (%s)", f.File, f.Line, f.Description, f.Match)
+}
+
+// Scanner checks files against a set of rules
+type Scanner struct {
+	Rules []Rule
+
+	// Allow lists path patterns that are never reported. Patterns match the
+	// slash-separated path or its base name; a trailing "/" matches a directory.
+	Allow []string
+
+	// Disabled lists rule IDs to skip, including "env-file"
+	Disabled []string
+}
+
+// New returns a scanner with the default rules
+func New() *Scanner {
+	return &Scanner{Rules: DefaultRules()}
+}
+
+// Scan checks files (relative to dir) and returns all findings
+func (s *Scanner) Scan(dir string, files []string) ([]Finding, error) {
+	disabled := make(map[string]bool, len(s.Disabled))
+	for _, id := range s.Disabled {
+		disabled[id] = true
+	}
+
+	rules := make([]Rule, 0, len(s.Rules))
+	for _, r := range s.Rules {
+		if !disabled[r.ID] {
+			rules = append(rules, r)
+		}
+	}
+
+	var findings []Finding
+	for _, file := range files {
+		file = filepath.ToSlash(file)
+		if s.allowed(file) {
+			continue
+		}
+
+		if !disabled[envFileRuleID] && isEnvFile(file) {
+			findings = append(findings, Finding{File: file, RuleID: envFileRuleID, Description: "Environment file"})
+		}
+
+		fileFindings, err := scanFile(filepath.Join(dir, filepath.FromSlash(file)), file, rules)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, fileFindings...)
+	}
+	return findings, nil
+}
+
+// scanFile checks a single file. Missing, large and binary files are skipped.
+func scanFile(fullPath, name string, rules []Rule) ([]Finding, error) {
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !info.Mode().IsRegular() || info.Size() > maxFileSize {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil, nil
+	}
+
+	var findings []Finding
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if strings.Contains(line, AllowMarker) {
+			continue
+		}
+		for _, r := range rules {
+			if m := r.Pattern.FindString(line); m != "" {
+				findings = append(findings, Finding{
+					File:        name,
+					Line:        lineNum,
+					RuleID:      r.ID,
+					Description: r.Description,
+					Match:       redact(m),
+				})
+			}
+		}
+	}
+	return findings, scanner.Err()
+}
+
+// allowed reports whether file matches an allow pattern
+func (s *Scanner) allowed(file string) bool {
+	for _, pattern := range s.Allow {
+		pattern = filepath.ToSlash(pattern)
+		if strings.HasSuffix(pattern, "/") {
+			if strings.HasPrefix(file, pattern) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, file); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(file)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isEnvFile reports whether file is a dotenv file that is not a template
+func isEnvFile(file string) bool {
+	base := path.Base(file)
+	if base != ".env" && !strings.HasPrefix(base, ".env.") {
+		return false
+	}
+	return !envFileTemplates[base]
+}
+
+// redact keeps a short prefix of a match so it can be recognised without leaking it
+func redact(s string) string {
+	if len(s) <= 8 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:6] + strings.Repeat("*", min(len(s)-6, 10))
+}
@@ -0,0 +1,209 @@
+// Package sentinel reads a server's resource usage from Coolify's Sentinel
+// agent and the state of an application's containers, with a script run on
+// the server over ssh.
+package sentinel
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/utils"
+)
+
+// Sentinel runs in this container on every server it's enabled on, and
+// serves its metrics only there
+const (
+	containerName = "coolify-sentinel"
+	apiURL        = "http://localhost:8888/api"
+)
+
+// Snapshot is a server's resource usage and the containers of an
+// application on it
+type Snapshot struct {
+	CPU    *Metric // nil when Sentinel didn't answer, see SentinelError
+	Memory *Metric
+	Disk   *Disk // of the root filesystem
+	// SentinelError is why CPU and memory are missing, if Sentinel was asked
+	SentinelError string
+	Containers    []Container // sorted by name
+}
+
+// Metric is a usage percentage Sentinel sampled
+type Metric struct {
+	Samples []float64 // oldest first
+}
+
+// Current returns the latest sample
+func (m *Metric) Current() float64 {
+	return m.Samples[len(m.Samples)-1]
+}
+
+// Average returns the mean of the samples
+func (m *Metric) Average() float64 {
+	var sum float64
+	for _, s := range m.Samples {
+		sum += s
+	}
+	return sum / float64(len(m.Samples))
+}
+
+// Peak returns the highest sample
+func (m *Metric) Peak() float64 {
+	peak := m.Samples[0]
+	for _, s := range m.Samples[1:] {
+		peak = max(peak, s)
+	}
+	return peak
+}
+
+// Disk is the usage of a filesystem, in bytes
+type Disk struct {
+	Used  int64
+	Total int64
+}
+
+// Percent returns the used share of the disk
+func (d *Disk) Percent() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+	return float64(d.Used) / float64(d.Total) * 100
+}
+
+// Container is the state of one of the application's containers
+type Container struct {
+	Name      string
+	State     string // running, exited, restarting, ...
+	Health    string // healthy, unhealthy or starting, "" without a health check
+	Restarts  int
+	StartedAt time.Time
+}
+
+// Script returns the shell script that prints the server's snapshot for
+// Parse. The containers are the application's, except previews'; token is
+// the server's Sentinel token, "" to skip CPU and memory. Sentinel is asked
+// for the samples since since.
+func Script(appUUID, token string, since time.Time) string {
+	var b strings.Builder
+	if token != "" {
+		from := since.UTC().Format("2006-01-02T15:04:05Z")
+		for _, metric := range []string{"cpu", "memory"} {
+			curl := fmt.Sprintf(`curl -fsS -H "Authorization: Bearer %s" "%s/%s/history?from=%s"`, token, apiURL, metric, from)
+			fmt.Fprintf(&b, "echo '::%s'; docker exec %s sh -c %s 2>&1; echo\n", metric, containerName, utils.ShellQuote(curl))
+		}
+	}
+	b.WriteString("echo '::disk'; df -Pk / | tail -n 1\n")
+	// Coolify names application containers after the application UUID
+	fmt.Fprintf(&b, "echo '::containers'; docker ps -aq --filter name=%s | xargs -r docker inspect --format %s\n",
+		appUUID, utils.ShellQuote(`{{.Name}}|{{.State.Status}}|{{.RestartCount}}|{{.State.StartedAt}}|{{if .State.Health}}{{.State.Health.Status}}{{end}}`))
+	return b.String()
+}
+
+// Parse reads the output of Script
+func Parse(out string) (*Snapshot, error) {
+	sections := map[string][]string{}
+	current := ""
+	for _, line := range strings.Split(out, "\n") {
+		if name, ok := strings.CutPrefix(line, "::"); ok {
+			current = name
+			sections[current] = []string{}
+			continue
+		}
+		if current != "" && strings.TrimSpace(line) != "" {
+			sections[current] = append(sections[current], line)
+		}
+	}
+	if _, ok := sections["containers"]; !ok {
+		return nil, fmt.Errorf("unexpected output from the server: %s", firstLine(out))
+	}
+
+	snapshot := &Snapshot{}
+	if lines, ok := sections["cpu"]; ok {
+		var err error
+		if snapshot.CPU, err = parseMetric(lines, "percent"); err == nil {
+			snapshot.Memory, err = parseMetric(sections["memory"], "usedPercent")
+		}
+		if err != nil {
+			snapshot.CPU, snapshot.Memory = nil, nil
+			snapshot.SentinelError = err.Error()
+		}
+	}
+	if lines := sections["disk"]; len(lines) > 0 {
+		snapshot.Disk = parseDisk(lines[0])
+	}
+	for _, line := range sections["containers"] {
+		if c, ok := parseContainer(line); ok && !strings.Contains(c.Name, "-pr-") {
+			snapshot.Containers = append(snapshot.Containers, c)
+		}
+	}
+	sort.Slice(snapshot.Containers, func(i, j int) bool {
+		return snapshot.Containers[i].Name < snapshot.Containers[j].Name
+	})
+	return snapshot, nil
+}
+
+// parseMetric reads Sentinel's history of a metric, samples with their
+// value in field
+func parseMetric(lines []string, field string) (*Metric, error) {
+	data := strings.Join(lines, "\n")
+	var samples []map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &samples); err != nil {
+		if strings.Contains(data, "No such container") {
+			return nil, fmt.Errorf("Sentinel isn't running on the server")
+		}
+		return nil, fmt.Errorf("Sentinel didn't answer: %s", firstLine(data))
+	}
+
+	metric := &Metric{}
+	for _, s := range samples {
+		// Values are numbers or numeric strings, depending on the version
+		raw := strings.Trim(string(s[field]), `"`)
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			metric.Samples = append(metric.Samples, v)
+		}
+	}
+	if len(metric.Samples) == 0 {
+		return nil, fmt.Errorf("Sentinel has no recent samples")
+	}
+	return metric, nil
+}
+
+// parseDisk reads a line of 'df -Pk': filesystem, size, used, available,
+// capacity and mount point, in KiB
+func parseDisk(line string) *Disk {
+	fields := strings.Fields(line)
+	if len(fields) < 4 {
+		return nil
+	}
+	total, err1 := strconv.ParseInt(fields[1], 10, 64)
+	used, err2 := strconv.ParseInt(fields[2], 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil
+	}
+	return &Disk{Used: used * 1024, Total: total * 1024}
+}
+
+func parseContainer(line string) (Container, bool) {
+	fields := strings.Split(strings.TrimSpace(line), "|")
+	if len(fields) != 5 {
+		return Container{}, false
+	}
+	restarts, _ := strconv.Atoi(fields[2])
+	started, _ := time.Parse(time.RFC3339Nano, fields[3])
+	return Container{
+		Name:      strings.TrimPrefix(fields[0], "/"),
+		State:     fields[1],
+		Restarts:  restarts,
+		StartedAt: started,
+		Health:    fields[4],
+	}, true
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
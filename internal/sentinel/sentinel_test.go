@@ -0,0 +1,91 @@
+package sentinel
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScript(t *testing.T) {
+	since := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	script := Script("abc123", "tok", since)
+	for _, want := range []string{
+		`docker exec coolify-sentinel sh -c 'curl -fsS -H "Authorization: Bearer tok" "http://localhost:8888/api/cpu/history?from=2024-05-01T10:00:00Z"'`,
+		"/api/memory/history?from=2024-05-01T10:00:00Z",
+		"df -Pk /",
+		"docker ps -aq --filter name=abc123",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script doesn't contain %q:\n%s", want, script)
+		}
+	}
+
+	if script := Script("abc123", "", since); strings.Contains(script, "sentinel") {
+		t.Errorf("script without a token asks Sentinel:\n%s", script)
+	}
+}
+
+func TestParse(t *testing.T) {
+	out := `::cpu
+[{"time":1714557600000,"percent":"10"},{"time":1714557660000,"percent":30},{"time":1714557720000,"percent":"20"}]
+::memory
+[{"time":1714557600000,"usedPercent":41.25}]
+::disk
+/dev/sda1 83886080 52428800 31457280 63% /
+::containers
+/abc123-104512|running|2|2024-05-01T09:45:12.123456789Z|healthy
+/abc123-pr-4-101010|running|0|2024-05-01T08:00:00Z|
+/db-abc123|exited|5|2024-04-30T08:00:00Z|
+`
+	s, err := Parse(out)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if s.CPU == nil || s.CPU.Current() != 20 || s.CPU.Peak() != 30 || s.CPU.Average() != 20 {
+		t.Errorf("cpu = %+v", s.CPU)
+	}
+	if s.Memory == nil || s.Memory.Current() != 41.25 {
+		t.Errorf("memory = %+v", s.Memory)
+	}
+	if s.Disk == nil || s.Disk.Total != 80<<30 || s.Disk.Percent() != 62.5 {
+		t.Errorf("disk = %+v", s.Disk)
+	}
+
+	if len(s.Containers) != 2 {
+		t.Fatalf("containers = %+v, want the two production ones", s.Containers)
+	}
+	app, db := s.Containers[0], s.Containers[1]
+	if app.Name != "abc123-104512" || app.State != "running" || app.Restarts != 2 || app.Health != "healthy" || app.StartedAt.Hour() != 9 {
+		t.Errorf("app container = %+v", app)
+	}
+	if db.State != "exited" || db.Restarts != 5 || db.Health != "" {
+		t.Errorf("db container = %+v", db)
+	}
+}
+
+func TestParseWithoutSentinel(t *testing.T) {
+	out := `::cpu
+Error response from daemon: No such container: coolify-sentinel
+
+::memory
+Error response from daemon: No such container: coolify-sentinel
+
+::disk
+/dev/sda1 100 50 50 50% /
+::containers
+`
+	s, err := Parse(out)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if s.CPU != nil || s.Memory != nil || s.SentinelError != "Sentinel isn't running on the server" {
+		t.Errorf("cpu = %+v, memory = %+v, error = %q", s.CPU, s.Memory, s.SentinelError)
+	}
+	if s.Disk == nil || len(s.Containers) != 0 {
+		t.Errorf("disk = %+v, containers = %+v", s.Disk, s.Containers)
+	}
+
+	if _, err := Parse("Permission denied (publickey).\n"); err == nil {
+		t.Error("Parse of an ssh error passed")
+	}
+}
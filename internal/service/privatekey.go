@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"fmt"
+	"strings"
 
 	"github.com/entro314-labs/cool-kit/internal/api"
+	"golang.org/x/crypto/ssh"
 )
 
 // PrivateKeyService handles private key-related operations
@@ -13,17 +18,7 @@ type PrivateKeyService struct {
 }
 
 // PrivateKey represents a Coolify private key
-type PrivateKey struct {
-	ID          int    `json:"id,omitempty"`
-	UUID        string `json:"uuid,omitempty"`
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
-	PrivateKey  string `json:"private_key,omitempty"`
-	IsGitKey    bool   `json:"is_git_key,omitempty"`
-	TeamID      int    `json:"team_id,omitempty"`
-	CreatedAt   string `json:"created_at,omitempty"`
-	UpdatedAt   string `json:"updated_at,omitempty"`
-}
+type PrivateKey = api.PrivateKey
 
 // PrivateKeyCreateRequest is the request body for creating a private key
 type PrivateKeyCreateRequest struct {
@@ -77,3 +72,49 @@ func (s *PrivateKeyService) Delete(ctx context.Context, uuid string) error {
 	}
 	return nil
 }
+
+// AssignToServer makes a server use the given private key for SSH access
+func (s *PrivateKeyService) AssignToServer(ctx context.Context, keyUUID, serverUUID string) error {
+	req := map[string]string{"private_key_uuid": keyUUID}
+	err := s.client.PatchWithContext(ctx, fmt.Sprintf("servers/%s", serverUUID), req, nil)
+	if err != nil {
+		return fmt.Errorf("failed to assign private key to server %s: %w", serverUUID, err)
+	}
+	return nil
+}
+
+// GeneratedKey is a locally generated SSH keypair
+type GeneratedKey struct {
+	PrivateKey  string // OpenSSH PEM-encoded private key
+	PublicKey   string // authorized_keys line
+	Fingerprint string // SHA256 fingerprint
+}
+
+// GenerateED25519Key creates a new ed25519 keypair in OpenSSH format
+func GenerateED25519Key(comment string) (*GeneratedKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ed25519 key: %w", err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+	if comment != "" {
+		authorized += " " + comment
+	}
+
+	return &GeneratedKey{
+		PrivateKey:  string(pem.EncodeToMemory(block)),
+		PublicKey:   authorized,
+		Fingerprint: ssh.FingerprintSHA256(sshPub),
+	}, nil
+}
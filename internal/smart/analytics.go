@@ -0,0 +1,120 @@
+package smart
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// clickHouseClients are the ClickHouse client libraries of each language
+var clickHouseClients = []dependencyHint{
+	{"package.json", []string{`"@clickhouse/client"`, `"@clickhouse/client-web"`, `"clickhouse"`}},
+	{"requirements.txt", []string{"clickhouse-connect", "clickhouse-driver", "clickhouse-sqlalchemy"}},
+	{"pyproject.toml", []string{"clickhouse-connect", "clickhouse-driver", "clickhouse-sqlalchemy"}},
+	{"Gemfile", []string{"click_house", "clickhouse-activerecord"}},
+	{"go.mod", []string{"github.com/clickhouse/clickhouse-go"}},
+	{"pom.xml", []string{"clickhouse-jdbc", "clickhouse-java"}},
+}
+
+// clickHouseConnectionVars are what ClickHouse clients are usually
+// configured with besides the URL
+var clickHouseConnectionVars = map[string]string{
+	"CLICKHOUSE_USER":     "user",
+	"CLICKHOUSE_PASSWORD": "password",
+	"CLICKHOUSE_DATABASE": "database",
+}
+
+// timescaleClients are the libraries that add TimescaleDB features to an ORM
+var timescaleClients = []dependencyHint{
+	{"package.json", []string{`"@timescaledb/typeorm"`, `"@timescaledb/core"`}},
+	{"requirements.txt", []string{"django-timescaledb", "sqlalchemy-timescaledb", "timescale-vector"}},
+	{"pyproject.toml", []string{"django-timescaledb", "sqlalchemy-timescaledb", "timescale-vector"}},
+	{"Gemfile", []string{"timescaledb"}},
+}
+
+// migrationDirs are where ORMs keep the migrations that would create
+// hypertables
+var migrationDirs = []string{"prisma/migrations", "drizzle", "migrations", "db/migrate", "alembic/versions", "database/migrations"}
+
+// detectClickHouse detects a ClickHouse client. ClickHouse holds analytics
+// beside the main database, so it doesn't replace it.
+func (sd *SmartDetector) detectClickHouse() *RequiredService {
+	file, library := sd.findDependency(clickHouseClients)
+	if file == "" {
+		return nil
+	}
+
+	service := &RequiredService{
+		Type:       "clickhouse",
+		Version:    serviceDefaults["clickhouse"].Version,
+		Reason:     library + " found in " + file,
+		EnvVarName: serviceDefaults["clickhouse"].EnvVarName,
+		Required:   true,
+	}
+	for key, component := range clickHouseConnectionVars {
+		service.addConnectionVar(key, component)
+	}
+	return service
+}
+
+// usesTimescale reports whether the project needs the TimescaleDB
+// extension, and why: a Timescale library, or migrations creating it or
+// a hypertable
+func (sd *SmartDetector) usesTimescale() (bool, string) {
+	if file, library := sd.findDependency(timescaleClients); file != "" {
+		return true, library + " found in " + file
+	}
+	if strings.Contains(sd.readFirst("prisma/schema.prisma"), "timescaledb") {
+		return true, "timescaledb extension in prisma/schema.prisma"
+	}
+
+	for _, dir := range migrationDirs {
+		found := ""
+		root := filepath.Join(sd.projectPath, dir)
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			content := strings.ToLower(string(data))
+			if strings.Contains(content, "create_hypertable") || strings.Contains(content, "extension if not exists timescaledb") {
+				found = path
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if found != "" {
+			rel, _ := filepath.Rel(sd.projectPath, found)
+			return true, "hypertable in " + filepath.ToSlash(rel)
+		}
+	}
+	return false, ""
+}
+
+// timescale turns a PostgreSQL service into a TimescaleDB one if the
+// project uses the extension
+func (sd *SmartDetector) timescale(service *RequiredService) {
+	if service == nil || service.Type != "postgresql" {
+		return
+	}
+	ok, reason := sd.usesTimescale()
+	if !ok {
+		return
+	}
+	service.Type = "timescaledb"
+	service.Version = timescaleVersion(service.Version)
+	service.Reason += "; " + reason
+}
+
+// timescaleVersion returns the timescale/timescaledb tag for a PostgreSQL
+// major version
+func timescaleVersion(postgres string) string {
+	if postgres == "" || strings.ContainsAny(postgres, ".-") {
+		postgres = serviceDefaults["postgresql"].Version
+	}
+	return "latest-pg" + postgres
+}
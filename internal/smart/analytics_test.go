@@ -0,0 +1,90 @@
+package smart
+
+import "testing"
+
+func TestDetectClickHouse(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json": `{"dependencies": {"@nestjs/core": "^10.0.0", "pg": "^8.11.0", "@clickhouse/client": "^1.0.0"}}`,
+	})
+	config, err := NewSmartDetector(dir).Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := map[string]RequiredService{}
+	for _, s := range config.Services {
+		types[s.Type] = s
+	}
+	if _, ok := types["postgresql"]; !ok {
+		t.Errorf("services = %+v, want postgresql beside clickhouse", config.Services)
+	}
+	clickhouse, ok := types["clickhouse"]
+	if !ok {
+		t.Fatalf("services = %+v, want clickhouse", config.Services)
+	}
+	if clickhouse.EnvVarName != "CLICKHOUSE_URL" || clickhouse.ConnectionVars["CLICKHOUSE_PASSWORD"] != "password" {
+		t.Errorf("clickhouse = %+v", clickhouse)
+	}
+}
+
+func TestDetectTimescale(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		want   string
+		reason string
+	}{
+		{
+			name: "hypertable migration",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"@nestjs/core": "^10.0.0", "pg": "^8.11.0"}}`,
+				"migrations/001_metrics.sql": "CREATE TABLE metrics (time timestamptz);\n" +
+					"SELECT create_hypertable('metrics', 'time');\n",
+			},
+			want:   "timescaledb",
+			reason: "hypertable in migrations/001_metrics.sql",
+		},
+		{
+			name: "django library",
+			files: map[string]string{
+				"manage.py":        "",
+				"requirements.txt": "django\npsycopg2\ndjango-timescaledb\n",
+				"app/settings.py":  `DATABASES = {"default": {"ENGINE": "django.db.backends.postgresql"}}`,
+			},
+			want:   "timescaledb",
+			reason: "django-timescaledb found in requirements.txt",
+		},
+		{
+			name: "plain postgres",
+			files: map[string]string{
+				"package.json":             `{"dependencies": {"@nestjs/core": "^10.0.0", "pg": "^8.11.0"}}`,
+				"migrations/001_users.sql": "CREATE TABLE users (id serial);\n",
+			},
+			want: "postgresql",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd := NewSmartDetector(writeFiles(t, tt.files))
+			config, err := sd.Detect()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(config.Services) == 0 {
+				t.Fatal("no services detected")
+			}
+			db := config.Services[0]
+			if db.Type != tt.want {
+				t.Fatalf("Type = %q, want %q", db.Type, tt.want)
+			}
+			if tt.want == "timescaledb" {
+				if db.Version != "latest-pg17" {
+					t.Errorf("Version = %q, want latest-pg17", db.Version)
+				}
+				if _, reason := sd.usesTimescale(); reason != tt.reason {
+					t.Errorf("reason = %q, want %q", reason, tt.reason)
+				}
+			}
+		})
+	}
+}
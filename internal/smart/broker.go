@@ -0,0 +1,103 @@
+package smart
+
+import "strings"
+
+// dependencyHint is a set of libraries that show up in a dependency file
+type dependencyHint struct {
+	file    string
+	needles []string // lowercase, quoted where a bare name would match others
+}
+
+// findDependency returns the first file and library of hints the project
+// depends on, or ""
+func (sd *SmartDetector) findDependency(hints []dependencyHint) (file, library string) {
+	for _, hint := range hints {
+		content := strings.ToLower(sd.readFirst(hint.file))
+		if content == "" {
+			continue
+		}
+		for _, needle := range hint.needles {
+			if strings.Contains(content, needle) {
+				return hint.file, strings.Trim(needle, `"`)
+			}
+		}
+	}
+	return "", ""
+}
+
+// rabbitMQClients are the AMQP client libraries of each language
+var rabbitMQClients = []dependencyHint{
+	{"package.json", []string{`"amqplib"`, `"amqp-connection-manager"`, `"@golevelup/nestjs-rabbitmq"`}},
+	{"requirements.txt", []string{"pika", "aio-pika", "aio_pika"}},
+	{"pyproject.toml", []string{"pika", "aio-pika", "aio_pika"}},
+	{"composer.json", []string{`"php-amqplib/php-amqplib"`}},
+	{"Gemfile", []string{`"bunny"`, `'bunny'`}},
+	{"go.mod", []string{"github.com/rabbitmq/amqp091-go", "github.com/streadway/amqp"}},
+	{"pom.xml", []string{"spring-boot-starter-amqp", "amqp-client"}},
+}
+
+// kafkaClients are the Kafka client libraries of each language
+var kafkaClients = []dependencyHint{
+	{"package.json", []string{`"kafkajs"`, `"@confluentinc/kafka-javascript"`, `"node-rdkafka"`}},
+	{"requirements.txt", []string{"kafka-python", "confluent-kafka", "aiokafka"}},
+	{"pyproject.toml", []string{"kafka-python", "confluent-kafka", "aiokafka"}},
+	{"Gemfile", []string{"ruby-kafka", "rdkafka", "karafka"}},
+	{"go.mod", []string{"github.com/ibm/sarama", "github.com/shopify/sarama", "github.com/segmentio/kafka-go", "github.com/confluentinc/confluent-kafka-go"}},
+	{"pom.xml", []string{"spring-kafka", "kafka-clients"}},
+}
+
+// brokerURLVars are the names apps commonly read a broker's URL from; the
+// first one .env.example lists is used instead of the default
+var brokerURLVars = map[string][]string{
+	"rabbitmq": {"RABBITMQ_URL", "AMQP_URL", "CLOUDAMQP_URL", "RABBIT_URL"},
+	"kafka":    {"KAFKA_BROKERS", "KAFKA_BOOTSTRAP_SERVERS", "KAFKA_BROKER", "KAFKA_URL"},
+}
+
+// kafkaConnectionVars configure a client for the SASL/PLAIN listener the
+// provisioned broker has
+var kafkaConnectionVars = map[string]string{
+	"KAFKA_USERNAME":       "user",
+	"KAFKA_PASSWORD":       "password",
+	"KAFKA_SASL_MECHANISM": "=PLAIN",
+}
+
+// detectBrokers detects the message brokers the project's client
+// libraries connect to
+func (sd *SmartDetector) detectBrokers() []RequiredService {
+	var services []RequiredService
+	if file, library := sd.findDependency(rabbitMQClients); file != "" {
+		services = append(services, RequiredService{
+			Type:       "rabbitmq",
+			Version:    serviceDefaults["rabbitmq"].Version,
+			Reason:     library + " found in " + file,
+			EnvVarName: sd.brokerURLVar("rabbitmq"),
+			Required:   true,
+		})
+	}
+	if file, library := sd.findDependency(kafkaClients); file != "" {
+		service := RequiredService{
+			Type:       "kafka",
+			Version:    serviceDefaults["kafka"].Version,
+			Reason:     library + " found in " + file,
+			EnvVarName: sd.brokerURLVar("kafka"),
+			Required:   true,
+		}
+		for key, component := range kafkaConnectionVars {
+			service.addConnectionVar(key, component)
+		}
+		services = append(services, service)
+	}
+	return services
+}
+
+// brokerURLVar returns the variable .env.example names the broker's URL
+// with, or the default
+func (sd *SmartDetector) brokerURLVar(broker string) string {
+	env := sd.readEnvExample()
+	for _, key := range brokerURLVars[broker] {
+		if _, ok := env[key]; ok {
+			return key
+		}
+	}
+	return serviceDefaults[broker].EnvVarName
+}
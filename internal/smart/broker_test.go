@@ -0,0 +1,67 @@
+package smart
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDetectBrokers(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json": `{"dependencies": {"express": "^4.18.0", "amqplib": "^0.10.0", "kafkajs": "^2.2.0"}}`,
+		".env.example": "AMQP_URL=amqp://localhost\n",
+	})
+	config, err := NewSmartDetector(dir).Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	services := map[string]RequiredService{}
+	for _, s := range config.Services {
+		services[s.Type] = s
+	}
+	rabbit, ok := services["rabbitmq"]
+	if !ok {
+		t.Fatalf("services = %+v, want rabbitmq", config.Services)
+	}
+	if rabbit.EnvVarName != "AMQP_URL" || !rabbit.Required || rabbit.Reason != "amqplib found in package.json" {
+		t.Errorf("rabbitmq = %+v, want a required service setting AMQP_URL", rabbit)
+	}
+
+	kafka, ok := services["kafka"]
+	if !ok {
+		t.Fatalf("services = %+v, want kafka", config.Services)
+	}
+	if kafka.EnvVarName != "KAFKA_BROKERS" {
+		t.Errorf("kafka EnvVarName = %q, want KAFKA_BROKERS", kafka.EnvVarName)
+	}
+	for key, want := range kafkaConnectionVars {
+		if got := kafka.ConnectionVars[key]; got != want {
+			t.Errorf("ConnectionVars[%s] = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestDetectBrokersPython(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"requirements.txt": "fastapi\naio-pika==9.4\n",
+		"main.py":          "",
+	})
+	services := NewSmartDetector(dir).detectBrokers()
+	if len(services) != 1 || services[0].Type != "rabbitmq" || services[0].EnvVarName != "RABBITMQ_URL" {
+		t.Errorf("detectBrokers() = %+v, want rabbitmq with RABBITMQ_URL", services)
+	}
+}
+
+func TestKafkaCompose(t *testing.T) {
+	compose := fmt.Sprintf(kafkaCompose, "3.8.0", "shop-kafka", "shop", "secret")
+	for _, want := range []string{
+		"image: apache/kafka:3.8.0",
+		"SASL_PLAINTEXT://shop-kafka:9092",
+		`user_shop="secret"`,
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("compose has no %q:\n%s", want, compose)
+		}
+	}
+}
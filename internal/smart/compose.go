@@ -0,0 +1,115 @@
+package smart
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/detect"
+)
+
+// serviceDefaults are the version and connection variable used for a
+// service when the project doesn't say which, like a compose image without
+// a tag or an ORM configuration that doesn't name its variable
+var serviceDefaults = map[string]RequiredService{
+	"postgresql":    {Version: "17", EnvVarName: "DATABASE_URL"},
+	"timescaledb":   {Version: "latest-pg17", EnvVarName: "DATABASE_URL"},
+	"mysql":         {Version: "8.4", EnvVarName: "DATABASE_URL"},
+	"mongodb":       {Version: "8.0", EnvVarName: "MONGODB_URL"},
+	"clickhouse":    {Version: "24.8", EnvVarName: "CLICKHOUSE_URL"},
+	"redis":         {Version: "7", EnvVarName: "REDIS_URL"},
+	"meilisearch":   {Version: "latest", EnvVarName: "MEILISEARCH_URL"},
+	"elasticsearch": {Version: "8", EnvVarName: "ELASTICSEARCH_URL"},
+	"minio":         {Version: "latest", EnvVarName: "S3_ENDPOINT"},
+	"rabbitmq":      {Version: "3", EnvVarName: "RABBITMQ_URL"},
+	"kafka":         {Version: "3.8.0", EnvVarName: "KAFKA_BROKERS"},
+}
+
+// ComposeAppCandidates returns the compose services that can become the
+// Coolify application: those built from the Dockerfile at the repository
+// root, which is what the Dockerfile build pack builds
+func ComposeAppCandidates(compose *detect.ComposeFile) []detect.ComposeService {
+	var candidates []detect.ComposeService
+	for _, s := range compose.Services {
+		if s.Build == nil {
+			continue
+		}
+		context := strings.TrimPrefix(s.Build.Context, "./")
+		if (context == "" || context == ".") && (s.Build.Dockerfile == "" || s.Build.Dockerfile == "Dockerfile") {
+			candidates = append(candidates, s)
+		}
+	}
+	return candidates
+}
+
+// ServicesFromCompose maps every compose service except app onto a service
+// the provisioner can create. It fails if a service has no Coolify
+// equivalent, since the compose file then has to be deployed as a whole.
+func ServicesFromCompose(compose *detect.ComposeFile, app string) ([]RequiredService, error) {
+	var appService *detect.ComposeService
+	for i := range compose.Services {
+		if compose.Services[i].Name == app {
+			appService = &compose.Services[i]
+		}
+	}
+	if appService == nil {
+		return nil, fmt.Errorf("service %s not found in the compose file", app)
+	}
+
+	var services []RequiredService
+	used := map[string]bool{}
+	for _, s := range compose.Services {
+		if s.Name == app {
+			continue
+		}
+		engine, version := s.Engine()
+		if engine == "" {
+			return nil, fmt.Errorf("service %s (%s) has no Coolify equivalent", s.Name, s.Image)
+		}
+		defaults := serviceDefaults[engine]
+
+		envVar := composeConnectionVar(appService.Environment, s.Name)
+		if envVar == "" || used[envVar] {
+			envVar = defaults.EnvVarName
+		}
+		used[envVar] = true
+
+		service := RequiredService{
+			Type:       engine,
+			Version:    version,
+			Reason:     fmt.Sprintf("Service %s in %s", s.Name, composeFileName(compose)),
+			EnvVarName: envVar,
+			Required:   true,
+		}
+		pinVersion(&service)
+		services = append(services, service)
+	}
+	return services, nil
+}
+
+// composeConnectionVar returns the app variable whose URL points at the
+// given compose service, e.g. DATABASE_URL=postgres://user:pw@db:5432/app
+func composeConnectionVar(env map[string]string, service string) string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		u, err := url.Parse(env[key])
+		if err == nil && u.Scheme != "" && u.Hostname() == service {
+			return key
+		}
+	}
+	return ""
+}
+
+func composeFileName(compose *detect.ComposeFile) string {
+	if compose.Path == "" {
+		return "the compose file"
+	}
+	return filepath.Base(compose.Path)
+}
@@ -16,6 +16,13 @@ type DeploymentConfig struct {
 	Environment    []EnvironmentVariable
 	DeploymentMode string // "production" or "preview"
 	AutoGenerated  bool
+	Migration      *Migration // nil if the project has no migrations to run
+	Workers        []Worker
+	ScheduledTasks []ScheduledTask
+
+	// ConnectionTemplates override the connection variables of services
+	// by type, from the project's ConnectionTemplatesFile
+	ConnectionTemplates map[string]ConnectionTemplate
 }
 
 // RequiredService represents a service needed by the application
@@ -25,6 +32,20 @@ type RequiredService struct {
 	Reason     string // Why this service is needed
 	EnvVarName string // Environment variable name for connection
 	Required   bool   // Is this absolutely required or optional?
+
+	// ConnectionVars maps more variables to the part of the connection
+	// they hold ("url", "host", "port", "user", "password" or "database"),
+	// for apps configured with DB_HOST style variables. A part starting
+	// with "=" is the value itself.
+	ConnectionVars map[string]string
+
+	// Prompt is asked at setup before provisioning the service, for
+	// services the app may get elsewhere; "" provisions it without asking
+	Prompt string
+
+	// VersionWarning says why the version the project asked for was
+	// replaced, or ""
+	VersionWarning string
 }
 
 // EnvironmentVariable represents an env var that needs to be set
@@ -40,6 +61,7 @@ type EnvironmentVariable struct {
 type SmartDetector struct {
 	projectPath string
 	framework   *detect.FrameworkInfo
+	orm         *ormInfo
 }
 
 // NewSmartDetector creates a new smart detector
@@ -66,12 +88,39 @@ func (sd *SmartDetector) Detect() (*DeploymentConfig, error) {
 		AutoGenerated:  true,
 	}
 
+	// ORM configuration says more about the database than dependencies do
+	sd.orm = sd.detectORM()
+	if sd.orm != nil && sd.orm.migration != "" {
+		config.Migration = &Migration{Tool: sd.orm.tool, Command: sd.orm.migration, Seed: sd.orm.seed}
+	}
+	// A Procfile's release process is what the project runs before each release
+	if release := detect.ReadProcfile(sd.projectPath)["release"]; release != "" {
+		config.Migration = &Migration{Tool: "procfile", Command: release}
+		if sd.orm != nil {
+			config.Migration.Seed = sd.orm.seed
+		}
+	}
+
 	// Detect required services
 	services, err := sd.detectServices()
 	if err == nil {
 		config.Services = services
 	}
 
+	// Workers run beside the web server and need Redis for their queues
+	config.Workers = sd.detectWorkers()
+	config.Services = requireRedis(config.Services, config.Workers)
+	for i := range config.Services {
+		pinVersion(&config.Services[i])
+	}
+	config.ScheduledTasks = sd.detectScheduledTasks()
+
+	templates, err := sd.loadConnectionTemplates()
+	if err != nil {
+		return nil, err
+	}
+	config.ConnectionTemplates = templates
+
 	// Detect environment variables
 	envVars, err := sd.detectEnvironmentVariables()
 	if err == nil {
@@ -90,6 +139,11 @@ func (sd *SmartDetector) detectServices() ([]RequiredService, error) {
 		services = append(services, *dbService)
 	}
 
+	// Check for an analytics database
+	if analyticsService := sd.detectClickHouse(); analyticsService != nil {
+		services = append(services, *analyticsService)
+	}
+
 	// Check for cache/Redis
 	if cacheService := sd.detectCache(); cacheService != nil {
 		services = append(services, *cacheService)
@@ -105,11 +159,36 @@ func (sd *SmartDetector) detectServices() ([]RequiredService, error) {
 		services = append(services, *searchService)
 	}
 
+	// Check for message brokers (RabbitMQ, Kafka)
+	services = append(services, sd.detectBrokers()...)
+
+	// Check for S3-compatible object storage
+	if storageService := sd.detectStorage(); storageService != nil {
+		services = append(services, *storageService)
+	}
+
 	return services, nil
 }
 
 // detectDatabase detects database requirements
 func (sd *SmartDetector) detectDatabase() *RequiredService {
+	var service *RequiredService
+	if sd.orm != nil && sd.orm.engine != "" {
+		service = sd.orm.service()
+	} else {
+		service = sd.detectDatabaseDependency()
+		if service != nil && sd.orm != nil {
+			sd.orm.connect(service)
+		}
+	}
+	// Timescale is Postgres with an extension, so it needs its image
+	sd.timescale(service)
+	return service
+}
+
+// detectDatabaseDependency detects a database from client libraries and
+// configuration files
+func (sd *SmartDetector) detectDatabaseDependency() *RequiredService {
 	// Check package.json for database packages
 	if sd.framework.Name == "Next.js" || sd.framework.Name == "Node.js" ||
 		sd.framework.Name == "NestJS" || sd.framework.Name == "Express" {
@@ -125,7 +204,7 @@ func (sd *SmartDetector) detectDatabase() *RequiredService {
 				strings.Contains(contentStr, "\"drizzle-orm\"") {
 				return &RequiredService{
 					Type:       "postgresql",
-					Version:    "16",
+					Version:    serviceDefaults["postgresql"].Version,
 					Reason:     "Detected PostgreSQL client in dependencies",
 					EnvVarName: "DATABASE_URL",
 					Required:   true,
@@ -137,7 +216,7 @@ func (sd *SmartDetector) detectDatabase() *RequiredService {
 				strings.Contains(contentStr, "\"mysql2\"") {
 				return &RequiredService{
 					Type:       "mysql",
-					Version:    "8",
+					Version:    serviceDefaults["mysql"].Version,
 					Reason:     "Detected MySQL client in dependencies",
 					EnvVarName: "DATABASE_URL",
 					Required:   true,
@@ -149,7 +228,7 @@ func (sd *SmartDetector) detectDatabase() *RequiredService {
 				strings.Contains(contentStr, "\"mongoose\"") {
 				return &RequiredService{
 					Type:       "mongodb",
-					Version:    "7",
+					Version:    serviceDefaults["mongodb"].Version,
 					Reason:     "Detected MongoDB client in dependencies",
 					EnvVarName: "MONGODB_URL",
 					Required:   true,
@@ -165,7 +244,7 @@ func (sd *SmartDetector) detectDatabase() *RequiredService {
 			// Laravel typically uses MySQL or PostgreSQL
 			return &RequiredService{
 				Type:       "postgresql", // Default to PostgreSQL
-				Version:    "16",
+				Version:    serviceDefaults["postgresql"].Version,
 				Reason:     "Laravel application detected",
 				EnvVarName: "DATABASE_URL",
 				Required:   true,
@@ -181,7 +260,7 @@ func (sd *SmartDetector) detectDatabase() *RequiredService {
 			// Default to PostgreSQL if we see database env vars
 			return &RequiredService{
 				Type:       "postgresql",
-				Version:    "16",
+				Version:    serviceDefaults["postgresql"].Version,
 				Reason:     "Database configuration found in .env.example",
 				EnvVarName: "DATABASE_URL",
 				Required:   true,
@@ -204,7 +283,7 @@ func (sd *SmartDetector) detectCache() *RequiredService {
 				strings.Contains(contentStr, "@upstash/redis") {
 				return &RequiredService{
 					Type:       "redis",
-					Version:    "7",
+					Version:    serviceDefaults["redis"].Version,
 					Reason:     "Redis client found in dependencies",
 					EnvVarName: "REDIS_URL",
 					Required:   false,
@@ -220,7 +299,7 @@ func (sd *SmartDetector) detectCache() *RequiredService {
 			if strings.Contains(string(content), "redis") {
 				return &RequiredService{
 					Type:       "redis",
-					Version:    "7",
+					Version:    serviceDefaults["redis"].Version,
 					Reason:     "Redis cache configured in Laravel",
 					EnvVarName: "REDIS_URL",
 					Required:   false,
@@ -240,7 +319,7 @@ func (sd *SmartDetector) detectQueue() *RequiredService {
 		if _, err := os.Stat(configQueue); err == nil {
 			return &RequiredService{
 				Type:       "redis",
-				Version:    "7",
+				Version:    serviceDefaults["redis"].Version,
 				Reason:     "Laravel queue system detected",
 				EnvVarName: "QUEUE_URL",
 				Required:   false,
@@ -256,7 +335,7 @@ func (sd *SmartDetector) detectQueue() *RequiredService {
 			strings.Contains(contentStr, "\"bull\"") {
 			return &RequiredService{
 				Type:       "redis",
-				Version:    "7",
+				Version:    serviceDefaults["redis"].Version,
 				Reason:     "Queue system (BullMQ/Bull) detected",
 				EnvVarName: "QUEUE_URL",
 				Required:   false,
@@ -277,7 +356,7 @@ func (sd *SmartDetector) detectSearch() *RequiredService {
 		if strings.Contains(contentStr, "meilisearch") {
 			return &RequiredService{
 				Type:       "meilisearch",
-				Version:    "latest",
+				Version:    serviceDefaults["meilisearch"].Version,
 				Reason:     "Meilisearch client detected",
 				EnvVarName: "MEILISEARCH_URL",
 				Required:   false,
@@ -289,7 +368,7 @@ func (sd *SmartDetector) detectSearch() *RequiredService {
 			strings.Contains(contentStr, "@elastic/elasticsearch") {
 			return &RequiredService{
 				Type:       "elasticsearch",
-				Version:    "8",
+				Version:    serviceDefaults["elasticsearch"].Version,
 				Reason:     "Elasticsearch client detected",
 				EnvVarName: "ELASTICSEARCH_URL",
 				Required:   false,
@@ -0,0 +1,199 @@
+package smart
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// EnvReference is a variable the project's source code reads
+type EnvReference struct {
+	Key      string
+	File     string // a file that reads it, without a fallback if any does, relative to the project
+	Optional bool   // every read has a fallback value
+}
+
+// envPattern is one way a language reads a variable. Call patterns end
+// after the quoted name, so a following comma passes a default.
+type envPattern struct {
+	re     *regexp.Regexp
+	call   bool
+	lookup bool // reports whether the variable is set instead of failing
+}
+
+var envPatterns = []envPattern{
+	// JavaScript and TypeScript
+	{re: regexp.MustCompile(`\bprocess\.env\.([A-Z_][A-Z0-9_]*)`)},
+	{re: regexp.MustCompile(`\bprocess\.env\[\s*["']([A-Z_][A-Z0-9_]*)["']`), call: true},
+	{re: regexp.MustCompile(`\bimport\.meta\.env\.([A-Z_][A-Z0-9_]*)`)},
+	{re: regexp.MustCompile(`\bBun\.env\.([A-Z_][A-Z0-9_]*)`)},
+	{re: regexp.MustCompile(`\bDeno\.env\.get\(\s*["']([A-Z_][A-Z0-9_]*)["']`), call: true},
+	// Python
+	{re: regexp.MustCompile(`\bos\.environ\[\s*["']([A-Z_][A-Z0-9_]*)["']`), call: true},
+	{re: regexp.MustCompile(`\bos\.environ\.get\(\s*["']([A-Z_][A-Z0-9_]*)["']`), call: true},
+	{re: regexp.MustCompile(`\bos\.getenv\(\s*["']([A-Z_][A-Z0-9_]*)["']`), call: true},
+	// Ruby
+	{re: regexp.MustCompile(`\bENV\[\s*["']([A-Z_][A-Z0-9_]*)["']`), call: true},
+	{re: regexp.MustCompile(`\bENV\.fetch\(\s*["']([A-Z_][A-Z0-9_]*)["']`), call: true},
+	// PHP
+	{re: regexp.MustCompile(`\benv\(\s*["']([A-Z_][A-Z0-9_]*)["']`), call: true},
+	{re: regexp.MustCompile(`\bgetenv\(\s*["']([A-Z_][A-Z0-9_]*)["']`), call: true},
+	// Go, Elixir and Rust
+	{re: regexp.MustCompile(`\bos\.Getenv\(\s*"([A-Z_][A-Z0-9_]*)"`), call: true},
+	{re: regexp.MustCompile(`\bos\.LookupEnv\(\s*"([A-Z_][A-Z0-9_]*)"`), call: true, lookup: true},
+	{re: regexp.MustCompile(`\bSystem\.(?:get_env|fetch_env!)\(\s*"([A-Z_][A-Z0-9_]*)"`), call: true},
+	{re: regexp.MustCompile(`\benv::var\(\s*"([A-Z_][A-Z0-9_]*)"`), call: true},
+}
+
+// envFallbacks follow a read that has a default value
+var envFallbacks = []string{"||", "??", "?:", "or ", "{", ".unwrap_or"}
+
+// envSourceExtensions are the files the scan reads
+var envSourceExtensions = map[string]bool{
+	".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true,
+	".vue": true, ".svelte": true, ".astro": true,
+	".py": true, ".rb": true, ".php": true, ".go": true,
+	".ex": true, ".exs": true, ".rs": true,
+}
+
+// envSkipDirs hold dependencies, build output and tests, whose variables
+// the deployed app doesn't need
+var envSkipDirs = map[string]bool{
+	"node_modules": true, "vendor": true, ".git": true, "dist": true, "build": true,
+	".next": true, ".nuxt": true, ".output": true, ".svelte-kit": true, "target": true,
+	".venv": true, "venv": true, "__pycache__": true, "deps": true, "_build": true,
+	"test": true, "tests": true, "__tests__": true, "spec": true, "e2e": true,
+}
+
+// platformEnv are set by Coolify, the build or the runtime itself
+var platformEnv = map[string]bool{
+	"PORT": true, "HOST": true, "HOSTNAME": true, "NODE_ENV": true, "PATH": true,
+	"HOME": true, "PWD": true, "CI": true, "TZ": true, "SOURCE_COMMIT": true,
+	"RAILS_ENV": true, "RACK_ENV": true, "MIX_ENV": true, "PYTHONPATH": true,
+	"NEXT_RUNTIME": true, "NEXT_PHASE": true, "DEV": true, "PROD": true,
+	"MODE": true, "SSR": true, "BASE_URL": true,
+}
+
+// maxEnvScanFiles bounds the scan in very large repositories
+const maxEnvScanFiles = 5000
+
+// ScanEnvReferences returns the variables the project's source code reads,
+// sorted by name, leaving out those the platform sets
+func ScanEnvReferences(dir string) ([]EnvReference, error) {
+	refs := map[string]*EnvReference{}
+	scanned := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && (envSkipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !envSourceExtensions[filepath.Ext(path)] || isTestFile(d.Name()) {
+			return nil
+		}
+		if scanned++; scanned > maxEnvScanFiles {
+			return filepath.SkipAll
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		scanEnvFile(string(data), filepath.ToSlash(rel), refs)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]EnvReference, 0, len(refs))
+	for _, ref := range refs {
+		result = append(result, *ref)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+// scanEnvFile adds the variables content reads to refs. A variable is only
+// optional if every read has a fallback.
+func scanEnvFile(content, file string, refs map[string]*EnvReference) {
+	for _, p := range envPatterns {
+		for _, m := range p.re.FindAllStringSubmatchIndex(content, -1) {
+			key := content[m[2]:m[3]]
+			if platformEnv[key] || strings.HasPrefix(key, "COOLIFY_") || strings.HasPrefix(key, "NIXPACKS_") {
+				continue
+			}
+			optional := p.lookup || hasEnvFallback(content[m[1]:], p.call)
+
+			ref, ok := refs[key]
+			if !ok {
+				refs[key] = &EnvReference{Key: key, File: file, Optional: optional}
+				continue
+			}
+			if ref.Optional && !optional {
+				ref.File, ref.Optional = file, false
+			}
+		}
+	}
+}
+
+func hasEnvFallback(rest string, call bool) bool {
+	rest = strings.TrimLeft(rest, " \t")
+	if call {
+		if strings.HasPrefix(rest, ",") {
+			return true
+		}
+		rest = strings.TrimPrefix(rest, ")")
+		rest = strings.TrimPrefix(rest, "]")
+		rest = strings.TrimLeft(rest, " \t")
+	}
+	for _, fallback := range envFallbacks {
+		if strings.HasPrefix(rest, fallback) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTestFile(name string) bool {
+	return strings.HasSuffix(name, "_test.go") ||
+		strings.HasPrefix(name, "test_") ||
+		strings.Contains(name, ".test.") ||
+		strings.Contains(name, ".spec.")
+}
+
+// MissingEnv returns the required variables the deployment doesn't set:
+// those the code reads without a fallback and those .env.example lists
+// without a value, except the connection variables of provisioned services
+func (dc *DeploymentConfig) MissingEnv(refs []EnvReference) []EnvReference {
+	set := map[string]bool{}
+	for _, s := range dc.Services {
+		for _, key := range s.ConnectionVarNames() {
+			set[key] = true
+		}
+	}
+
+	var missing []EnvReference
+	seen := map[string]bool{}
+	for _, env := range dc.Environment {
+		seen[env.Key] = true
+		if env.Value == "" && !set[env.Key] {
+			missing = append(missing, EnvReference{Key: env.Key, File: ".env.example"})
+		}
+	}
+	for _, ref := range refs {
+		if !ref.Optional && !set[ref.Key] && !seen[ref.Key] {
+			missing = append(missing, ref)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Key < missing[j].Key })
+	return missing
+}
@@ -0,0 +1,62 @@
+package smart
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanEnvReferences(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"src/server.ts": `const port = process.env.PORT || 3000;
+const stripe = new Stripe(process.env.STRIPE_SECRET_KEY);
+const region = process.env.AWS_REGION ?? "eu-west-1";
+const url = process.env["PUBLIC_URL"];`,
+		"src/mail.ts":                   `send(process.env.SMTP_HOST, process.env.AWS_REGION);`,
+		"app/settings.py":               `SECRET_KEY = os.environ["DJANGO_SECRET_KEY"]` + "\n" + `DEBUG = os.environ.get("DJANGO_DEBUG", "0")`,
+		"config/initializers/sentry.rb": `Sentry.init { |c| c.dsn = ENV.fetch("SENTRY_DSN") { nil } }`,
+		"src/server.test.ts":            `process.env.TEST_ONLY`,
+		"node_modules/lib/index.js":     `process.env.LIB_SETTING`,
+	})
+
+	refs, err := ScanEnvReferences(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []EnvReference{
+		// Read with a fallback in server.ts but without one in mail.ts
+		{Key: "AWS_REGION", File: "src/mail.ts"},
+		{Key: "DJANGO_DEBUG", File: "app/settings.py", Optional: true},
+		{Key: "DJANGO_SECRET_KEY", File: "app/settings.py"},
+		{Key: "PUBLIC_URL", File: "src/server.ts"},
+		{Key: "SENTRY_DSN", File: "config/initializers/sentry.rb", Optional: true},
+		{Key: "SMTP_HOST", File: "src/mail.ts"},
+		{Key: "STRIPE_SECRET_KEY", File: "src/server.ts"},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("refs =\n%+v\nwant\n%+v", refs, want)
+	}
+}
+
+func TestMissingEnv(t *testing.T) {
+	dc := &DeploymentConfig{
+		Services: []RequiredService{{Type: "postgresql", EnvVarName: "DATABASE_URL"}},
+		Environment: []EnvironmentVariable{
+			{Key: "APP_NAME", Value: "shop"},
+			{Key: "MAIL_FROM"},
+		},
+	}
+	refs := []EnvReference{
+		{Key: "APP_NAME", File: "app.js"},
+		{Key: "DATABASE_URL", File: "db.js"},
+		{Key: "SENTRY_DSN", File: "app.js", Optional: true},
+		{Key: "STRIPE_KEY", File: "billing.js"},
+	}
+
+	want := []EnvReference{
+		{Key: "MAIL_FROM", File: ".env.example"},
+		{Key: "STRIPE_KEY", File: "billing.js"},
+	}
+	if got := dc.MissingEnv(refs); !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingEnv() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package smart
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// healthPollInterval is how often WaitHealthy checks the services
+var healthPollInterval = 3 * time.Second
+
+// WaitHealthy starts the provisioned services, which Coolify creates
+// stopped, and waits until they are running and pass their health checks.
+// It fails if a required service isn't healthy within timeout; optional
+// ones only warn. report receives progress like "postgres: starting".
+func (sp *ServiceProvisioner) WaitHealthy(services []ProvisionedService, timeout time.Duration, report func(status string)) (warnings []string, err error) {
+	for _, service := range services {
+		if err := sp.start(service); err != nil {
+			if service.Required {
+				return warnings, fmt.Errorf("failed to start %s: %w", service.Name, err)
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to start %s: %v", service.Name, err))
+		}
+	}
+
+	pending := append([]ProvisionedService{}, services...)
+	statuses := map[string]string{}
+	deadline := time.Now().Add(timeout)
+	for {
+		var waiting []ProvisionedService
+		for _, service := range pending {
+			status, err := sp.status(service)
+			if err != nil {
+				status = "unknown"
+			}
+			statuses[service.Name] = status
+			if !serviceHealthy(status) {
+				waiting = append(waiting, service)
+			}
+		}
+		pending = waiting
+		if len(pending) == 0 {
+			return warnings, nil
+		}
+
+		report(describeWaiting(pending, statuses))
+		if time.Now().Add(healthPollInterval).After(deadline) {
+			break
+		}
+		time.Sleep(healthPollInterval)
+	}
+
+	for _, service := range pending {
+		msg := fmt.Sprintf("%s is not healthy after %s (status: %s)", service.Name, timeout, statuses[service.Name])
+		if service.Required {
+			return warnings, fmt.Errorf("%s", msg)
+		}
+		warnings = append(warnings, msg)
+	}
+	return warnings, nil
+}
+
+// start starts a service or database
+func (sp *ServiceProvisioner) start(service ProvisionedService) error {
+	if service.IsService {
+		_, err := sp.client.StartService(service.UUID)
+		return err
+	}
+	_, err := sp.client.StartDatabase(service.UUID)
+	return err
+}
+
+// status returns the Coolify status of a service or database, like
+// "running:healthy" or "exited"
+func (sp *ServiceProvisioner) status(service ProvisionedService) (string, error) {
+	if service.IsService {
+		s, err := sp.client.GetService(service.UUID)
+		if err != nil {
+			return "", err
+		}
+		return s.Status, nil
+	}
+	db, err := sp.client.GetDatabase(service.UUID)
+	if err != nil {
+		return "", err
+	}
+	return db.Status, nil
+}
+
+// serviceHealthy reports whether a status is running and healthy. Coolify
+// reports running:unknown for containers without a health check, which is
+// as healthy as they can be shown to be.
+func serviceHealthy(status string) bool {
+	state, health, _ := strings.Cut(strings.ToLower(strings.TrimSpace(status)), ":")
+	if state != "running" {
+		return false
+	}
+	return health == "" || health == "healthy" || health == "unknown"
+}
+
+// describeWaiting summarizes the services still being waited for
+func describeWaiting(pending []ProvisionedService, statuses map[string]string) string {
+	parts := make([]string, 0, len(pending))
+	for _, service := range pending {
+		status := statuses[service.Name]
+		if status == "" {
+			status = "starting"
+		}
+		parts = append(parts, service.Name+": "+status)
+	}
+	return strings.Join(parts, ", ")
+}
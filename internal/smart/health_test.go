@@ -0,0 +1,86 @@
+package smart
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/api"
+)
+
+func TestServiceHealthy(t *testing.T) {
+	for status, want := range map[string]bool{
+		"running:healthy":   true,
+		"running:unknown":   true,
+		"running":           true,
+		"running:unhealthy": false,
+		"starting":          false,
+		"exited:unhealthy":  false,
+		"":                  false,
+	} {
+		if got := serviceHealthy(status); got != want {
+			t.Errorf("serviceHealthy(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestWaitHealthy(t *testing.T) {
+	interval := healthPollInterval
+	healthPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { healthPollInterval = interval })
+
+	started := map[string]bool{}
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/databases/db-1/start", func(w http.ResponseWriter, r *http.Request) {
+		started["db-1"] = true
+		json.NewEncoder(w).Encode(api.MessageResponse{Message: "started"})
+	})
+	mux.HandleFunc("/api/v1/databases/db-1", func(w http.ResponseWriter, r *http.Request) {
+		// Healthy on the third check
+		polls++
+		status := "starting"
+		if polls >= 3 {
+			status = "running:healthy"
+		}
+		json.NewEncoder(w).Encode(api.Database{UUID: "db-1", Status: status})
+	})
+	mux.HandleFunc("/api/v1/services/svc-1/start", func(w http.ResponseWriter, r *http.Request) {
+		started["svc-1"] = true
+		json.NewEncoder(w).Encode(api.MessageResponse{Message: "started"})
+	})
+	mux.HandleFunc("/api/v1/services/svc-1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(api.Service{UUID: "svc-1", Status: "exited"})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	sp := NewServiceProvisioner(api.NewClient(srv.URL, "token"), "p", "e", "s", "shop")
+	db := ProvisionedService{Name: "shop-postgres", UUID: "db-1", Required: true}
+	search := ProvisionedService{Name: "shop-meilisearch", UUID: "svc-1", IsService: true}
+
+	var reports []string
+	warnings, err := sp.WaitHealthy([]ProvisionedService{db, search}, 200*time.Millisecond, func(s string) {
+		reports = append(reports, s)
+	})
+	if err != nil {
+		t.Fatalf("optional service failed the wait: %v", err)
+	}
+	if !started["db-1"] || !started["svc-1"] {
+		t.Errorf("started = %v, want both", started)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "shop-meilisearch is not healthy") {
+		t.Errorf("warnings = %q", warnings)
+	}
+	if len(reports) == 0 || !strings.Contains(reports[0], "shop-postgres: starting") {
+		t.Errorf("reports = %q", reports)
+	}
+
+	search.Required = true
+	if _, err := sp.WaitHealthy([]ProvisionedService{search}, 50*time.Millisecond, func(string) {}); err == nil {
+		t.Error("required service that never runs didn't fail the wait")
+	}
+}
@@ -0,0 +1,106 @@
+package smart
+
+import (
+	"bufio"
+	"path/filepath"
+	"strings"
+)
+
+// ScheduledTask is a cron job the project needs Coolify to run in the
+// application's container
+type ScheduledTask struct {
+	Name      string
+	Command   string
+	Frequency string // cron expression or a Coolify keyword like "daily"
+	Reason    string
+}
+
+// laravelWorkers detects the processes a Laravel application runs beside
+// its web server: a queue worker unless Horizon runs the queues, and the
+// Reverb WebSocket server
+func (sd *SmartDetector) laravelWorkers() []Worker {
+	composer := sd.readFirst("composer.json")
+	if !strings.Contains(composer, `"laravel/framework"`) {
+		return nil
+	}
+	env := sd.readEnvExample()
+
+	var workers []Worker
+	if !strings.Contains(composer, `"laravel/horizon"`) {
+		if w := sd.laravelQueueWorker(env["QUEUE_CONNECTION"]); w != nil {
+			workers = append(workers, *w)
+		}
+	}
+	if strings.Contains(composer, `"laravel/reverb"`) {
+		workers = append(workers, Worker{
+			Name:         "reverb",
+			Tool:         "Laravel Reverb",
+			StartCommand: "php artisan reverb:start --host=0.0.0.0 --port=8080",
+			Reason:       "laravel/reverb found in composer.json; give it a domain in Coolify for WebSocket clients",
+		})
+	}
+
+	return workers
+}
+
+// detectScheduledTasks detects the cron jobs the project needs: the
+// Laravel scheduler, which runs every minute and starts due commands
+func (sd *SmartDetector) detectScheduledTasks() []ScheduledTask {
+	if !strings.Contains(sd.readFirst("composer.json"), `"laravel/framework"`) {
+		return nil
+	}
+	var tasks []ScheduledTask
+	// Laravel 11 schedules in routes/console.php, earlier versions in the
+	// console kernel
+	if strings.Contains(sd.readFirst("routes/console.php"), "Schedule::") ||
+		strings.Contains(sd.readFirst("app/Console/Kernel.php"), "$schedule->") {
+		tasks = append(tasks, ScheduledTask{
+			Name:      "scheduler",
+			Command:   "php artisan schedule:run",
+			Frequency: "* * * * *",
+			Reason:    "Laravel scheduler has scheduled commands",
+		})
+	}
+	return tasks
+}
+
+// laravelQueueWorker returns a queue:work worker if the application
+// dispatches jobs to a queue a worker has to process
+func (sd *SmartDetector) laravelQueueWorker(connection string) *Worker {
+	if connection == "" || connection == "sync" || connection == "null" {
+		return nil
+	}
+	if !dirExists(filepath.Join(sd.projectPath, "app", "Jobs")) {
+		return nil
+	}
+
+	// Only the Redis queue makes the worker need Redis
+	tool := "Laravel queue"
+	if connection != "redis" {
+		tool = "Laravel " + connection + " queue"
+	}
+	return &Worker{
+		Name:         "queue",
+		Tool:         tool,
+		StartCommand: "php artisan queue:work " + connection + " --tries=3 --max-time=3600",
+		Reason:       "app/Jobs found with QUEUE_CONNECTION=" + connection,
+	}
+}
+
+// readEnvExample returns the values of .env.example, or an empty map
+func (sd *SmartDetector) readEnvExample() map[string]string {
+	env := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(sd.readFirst(".env.example")))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		env[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return env
+}
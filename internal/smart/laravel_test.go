@@ -0,0 +1,57 @@
+package smart
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectLaravel(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"composer.json":            `{"require": {"laravel/framework": "^11.0", "laravel/reverb": "^1.0"}}`,
+		".env.example":             "APP_NAME=Shop\nQUEUE_CONNECTION=redis\n",
+		"app/Jobs/SendInvoice.php": "<?php\n",
+		"routes/console.php":       "<?php\n\nSchedule::command('invoices:send')->daily();\n",
+	})
+	config, err := NewSmartDetector(dir).Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantWorkers := []Worker{
+		{Name: "queue", Tool: "Laravel queue", StartCommand: "php artisan queue:work redis --tries=3 --max-time=3600", Reason: "app/Jobs found with QUEUE_CONNECTION=redis"},
+		{Name: "reverb", Tool: "Laravel Reverb", StartCommand: "php artisan reverb:start --host=0.0.0.0 --port=8080", Reason: "laravel/reverb found in composer.json; give it a domain in Coolify for WebSocket clients"},
+	}
+	if !reflect.DeepEqual(config.Workers, wantWorkers) {
+		t.Errorf("workers =\n%+v\nwant\n%+v", config.Workers, wantWorkers)
+	}
+
+	wantTasks := []ScheduledTask{{Name: "scheduler", Command: "php artisan schedule:run", Frequency: "* * * * *", Reason: "Laravel scheduler has scheduled commands"}}
+	if !reflect.DeepEqual(config.ScheduledTasks, wantTasks) {
+		t.Errorf("scheduled tasks = %+v, want %+v", config.ScheduledTasks, wantTasks)
+	}
+
+	// The redis queue needs Laravel's REDIS_* variables
+	var redis *RequiredService
+	for i := range config.Services {
+		if config.Services[i].Type == "redis" {
+			redis = &config.Services[i]
+		}
+	}
+	if redis == nil || !redis.Required || redis.ConnectionVars["REDIS_HOST"] != "host" {
+		t.Errorf("redis = %+v, want a required service setting REDIS_HOST", redis)
+	}
+}
+
+func TestLaravelDatabaseQueue(t *testing.T) {
+	sd := NewSmartDetector(writeFiles(t, map[string]string{"app/Jobs/Sync.php": "<?php\n"}))
+	w := sd.laravelQueueWorker("database")
+	if w == nil || w.Tool != "Laravel database queue" {
+		t.Fatalf("worker = %+v, want a database queue worker", w)
+	}
+	if services := requireRedis(nil, []Worker{*w}); len(services) != 0 {
+		t.Errorf("services = %+v, want no Redis for a database queue", services)
+	}
+	if w := sd.laravelQueueWorker("sync"); w != nil {
+		t.Errorf("worker = %+v for the sync queue, want none", w)
+	}
+}
@@ -0,0 +1,434 @@
+package smart
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Migration is the command that brings the database schema up to date,
+// run after the application is deployed
+type Migration struct {
+	Tool    string // "prisma", "drizzle", "typeorm", "activerecord", "alembic", "django" or "procfile"
+	Command string
+	Seed    string // fills a new database, run after the first deployment; "" if the project has none
+}
+
+// ormInfo is what an ORM's configuration says about the database
+type ormInfo struct {
+	tool      string
+	engine    string   // "" if the configuration doesn't say
+	envVars   []string // variables the configuration reads
+	migration string   // "" when the project has no migrations to run
+	seed      string   // "" when the project has no seed data
+}
+
+// ormEngines maps the names ORMs use for a database to service types.
+// sqlite needs no service; anything missing can't be provisioned.
+var ormEngines = map[string]string{
+	"postgresql":          "postgresql",
+	"postgres":            "postgresql",
+	"postgis":             "postgresql",
+	"pg":                  "postgresql",
+	"postgresql_psycopg2": "postgresql",
+	"mysql":               "mysql",
+	"mysql2":              "mysql",
+	"mariadb":             "mysql",
+	"trilogy":             "mysql",
+	"mongodb":             "mongodb",
+	"sqlite":              "sqlite",
+	"sqlite3":             "sqlite",
+	"better-sqlite":       "sqlite",
+	"better-sqlite3":      "sqlite",
+	"libsql":              "sqlite",
+	"turso":               "sqlite",
+}
+
+// envReference matches the ways configuration files read a variable:
+// process.env.X, env("X"), ENV["X"], ENV.fetch("X"), os.environ["X"],
+// os.environ.get("X"), os.getenv("X") and config("X")
+var envReference = regexp.MustCompile(`(?:process\.env\.|process\.env\[\s*["']|\benv(?:\.\w+)?\(\s*["']|\bENV(?:\.fetch)?[\[(]\s*["']|environ(?:\.get)?[\[(]\s*["']|getenv\(\s*["']|\bconfig\(\s*["'])([A-Z_][A-Z0-9_]*)`)
+
+var (
+	prismaDatasource = regexp.MustCompile(`(?s)datasource\s+\w+\s*\{(.*?)\}`)
+	prismaProvider   = regexp.MustCompile(`provider\s*=\s*"(\w+)"`)
+	jsDialect        = regexp.MustCompile(`\b(?:dialect|driver|type)\s*:\s*["']([\w-]+)["']`)
+	drizzleOut       = regexp.MustCompile(`\bout\s*:\s*["']([^"']+)["']`)
+	railsAdapter     = regexp.MustCompile(`(?m)^\s*adapter:\s*(\w+)`)
+	djangoEngine     = regexp.MustCompile(`["']ENGINE["']\s*:\s*["'][\w.]+\.(\w+)["']`)
+	alembicURL       = regexp.MustCompile(`(?m)^sqlalchemy\.url\s*=\s*(\w+)`)
+	alembicScripts   = regexp.MustCompile(`(?m)^script_location\s*=\s*(\S+)`)
+	prismaSeed       = regexp.MustCompile(`"prisma"\s*:\s*\{[^}]*"seed"\s*:`)
+	rubyCode         = regexp.MustCompile(`(?m)^\s*[^#\s]`)
+)
+
+// detectORM reads the configuration of the first ORM the project uses
+func (sd *SmartDetector) detectORM() *ormInfo {
+	for _, fn := range []func() *ormInfo{
+		sd.detectPrisma,
+		sd.detectDrizzle,
+		sd.detectTypeORM,
+		sd.detectActiveRecord,
+		sd.detectDjango,
+		sd.detectAlembic,
+	} {
+		if orm := fn(); orm != nil {
+			return orm
+		}
+	}
+	return nil
+}
+
+func (sd *SmartDetector) detectPrisma() *ormInfo {
+	var schema string
+	for _, path := range []string{"prisma/schema.prisma", "schema.prisma"} {
+		if data, err := os.ReadFile(filepath.Join(sd.projectPath, path)); err == nil {
+			schema = string(data)
+			break
+		}
+	}
+	if schema == "" {
+		// Multi-file schemas keep the datasource in one of prisma/schema/*.prisma
+		files, _ := filepath.Glob(filepath.Join(sd.projectPath, "prisma", "schema", "*.prisma"))
+		for _, file := range files {
+			if data, err := os.ReadFile(file); err == nil {
+				schema += string(data) + "\n"
+			}
+		}
+	}
+	m := prismaDatasource.FindStringSubmatch(schema)
+	if m == nil {
+		return nil
+	}
+
+	orm := &ormInfo{tool: "prisma", envVars: envReferences(m[1])}
+	if p := prismaProvider.FindStringSubmatch(m[1]); p != nil {
+		orm.engine = ormEngines[p[1]]
+	}
+	switch {
+	case orm.engine == "mongodb":
+		// MongoDB has no migrations; db push creates the indexes
+		orm.migration = "npx prisma db push --skip-generate"
+	case dirExists(filepath.Join(sd.projectPath, "prisma", "migrations")):
+		orm.migration = "npx prisma migrate deploy"
+	}
+	// prisma db seed runs the script package.json names under "prisma"
+	if prismaSeed.MatchString(sd.readFirst("package.json")) {
+		orm.seed = "npx prisma db seed"
+	}
+	return orm
+}
+
+func (sd *SmartDetector) detectDrizzle() *ormInfo {
+	config := sd.readFirst("drizzle.config.ts", "drizzle.config.js", "drizzle.config.mjs", "drizzle.config.mts", "drizzle.config.cjs")
+	if config == "" {
+		return nil
+	}
+
+	orm := &ormInfo{tool: "drizzle", envVars: envReferences(config)}
+	orm.engine = jsEngine(config)
+	out := "drizzle"
+	if m := drizzleOut.FindStringSubmatch(config); m != nil {
+		out = m[1]
+	}
+	if dirExists(filepath.Join(sd.projectPath, out)) {
+		orm.migration = "npx drizzle-kit migrate"
+	}
+	return orm
+}
+
+func (sd *SmartDetector) detectTypeORM() *ormInfo {
+	if !strings.Contains(sd.readFirst("package.json"), `"typeorm"`) {
+		return nil
+	}
+
+	orm := &ormInfo{tool: "typeorm"}
+	for _, path := range []string{"src/data-source.ts", "data-source.ts", "src/data-source.js", "data-source.js", "ormconfig.json", "ormconfig.js", "ormconfig.ts"} {
+		data, err := os.ReadFile(filepath.Join(sd.projectPath, path))
+		if err != nil {
+			continue
+		}
+		config := string(data)
+		orm.envVars = envReferences(config)
+		orm.engine = jsEngine(config)
+		if strings.Contains(config, "migrations") {
+			switch {
+			case strings.HasPrefix(path, "ormconfig"):
+				orm.migration = "npx typeorm migration:run"
+			case strings.HasSuffix(path, ".ts"):
+				orm.migration = "npx typeorm-ts-node-commonjs migration:run -d " + path
+			default:
+				orm.migration = "npx typeorm migration:run -d " + path
+			}
+		}
+		break
+	}
+	if orm.engine == "" {
+		orm.engine = sd.nodeDriverEngine()
+	}
+	return orm
+}
+
+func (sd *SmartDetector) detectActiveRecord() *ormInfo {
+	config := sd.readFirst("config/database.yml")
+	if config == "" || !strings.Contains(sd.readFirst("Gemfile"), "rails") {
+		return nil
+	}
+
+	orm := &ormInfo{tool: "activerecord", envVars: envReferences(config)}
+	// Sections usually inherit the adapter from a shared default block
+	if m := railsAdapter.FindStringSubmatch(config); m != nil {
+		orm.engine = ormEngines[m[1]]
+	}
+	if dirExists(filepath.Join(sd.projectPath, "db", "migrate")) {
+		orm.migration = "bundle exec rails db:migrate"
+	}
+	// The generated db/seeds.rb only has comments
+	if seeds := sd.readFirst("db/seeds.rb"); rubyCode.MatchString(seeds) {
+		orm.seed = "bundle exec rails db:seed"
+	}
+	return orm
+}
+
+func (sd *SmartDetector) detectDjango() *ormInfo {
+	if _, err := os.Stat(filepath.Join(sd.projectPath, "manage.py")); err != nil {
+		return nil
+	}
+
+	var settings string
+	for _, pattern := range []string{"*/settings.py", "*/settings/*.py"} {
+		files, _ := filepath.Glob(filepath.Join(sd.projectPath, pattern))
+		for _, file := range files {
+			if data, err := os.ReadFile(file); err == nil {
+				settings += string(data) + "\n"
+			}
+		}
+	}
+
+	orm := &ormInfo{tool: "django", envVars: envReferences(settings), migration: "python manage.py migrate --noinput"}
+	if m := djangoEngine.FindStringSubmatch(settings); m != nil {
+		orm.engine = ormEngines[m[1]]
+	}
+	// dj-database-url and django-environ read DATABASE_URL by default
+	if strings.Contains(settings, "dj_database_url") || strings.Contains(settings, "env.db(") {
+		orm.envVars = appendUnique(orm.envVars, "DATABASE_URL")
+	}
+	if orm.engine == "" {
+		orm.engine = sd.pythonDriverEngine()
+	}
+	return orm
+}
+
+func (sd *SmartDetector) detectAlembic() *ormInfo {
+	ini := sd.readFirst("alembic.ini")
+	if ini == "" {
+		return nil
+	}
+
+	scripts := "alembic"
+	if m := alembicScripts.FindStringSubmatch(ini); m != nil {
+		scripts = strings.TrimPrefix(strings.TrimPrefix(m[1], "%(here)s"), "/")
+	}
+	env := sd.readFirst(filepath.Join(scripts, "env.py"))
+
+	orm := &ormInfo{tool: "alembic", envVars: envReferences(env), migration: "alembic upgrade head"}
+	if m := alembicURL.FindStringSubmatch(ini); m != nil {
+		orm.engine = ormEngines[m[1]]
+	}
+	if orm.engine == "" {
+		orm.engine = sd.pythonDriverEngine()
+	}
+	return orm
+}
+
+// jsEngine returns the engine of the first dialect, driver or type option
+// in a JavaScript configuration that names one
+func jsEngine(config string) string {
+	for _, m := range jsDialect.FindAllStringSubmatch(config, -1) {
+		if engine, ok := ormEngines[m[1]]; ok {
+			return engine
+		}
+	}
+	return ""
+}
+
+// nodeDriverEngine infers the database from the driver in package.json
+func (sd *SmartDetector) nodeDriverEngine() string {
+	pkg := sd.readFirst("package.json")
+	for _, driver := range []struct{ name, engine string }{
+		{`"pg"`, "postgresql"},
+		{`"postgres"`, "postgresql"},
+		{`"mysql2"`, "mysql"},
+		{`"mysql"`, "mysql"},
+		{`"mongodb"`, "mongodb"},
+		{`"sqlite3"`, "sqlite"},
+		{`"better-sqlite3"`, "sqlite"},
+	} {
+		if strings.Contains(pkg, driver.name) {
+			return driver.engine
+		}
+	}
+	return ""
+}
+
+// pythonDriverEngine infers the database from the driver in the Python
+// dependency files
+func (sd *SmartDetector) pythonDriverEngine() string {
+	deps := strings.ToLower(sd.readFirst("requirements.txt") + sd.readFirst("pyproject.toml") + sd.readFirst("Pipfile"))
+	for _, driver := range []struct{ name, engine string }{
+		{"psycopg", "postgresql"},
+		{"asyncpg", "postgresql"},
+		{"pg8000", "postgresql"},
+		{"mysqlclient", "mysql"},
+		{"pymysql", "mysql"},
+		{"aiomysql", "mysql"},
+	} {
+		if strings.Contains(deps, driver.name) {
+			return driver.engine
+		}
+	}
+	return ""
+}
+
+// readFirst returns the contents of the first of the given project files
+// that exists, or ""
+func (sd *SmartDetector) readFirst(paths ...string) string {
+	for _, path := range paths {
+		if data, err := os.ReadFile(filepath.Join(sd.projectPath, path)); err == nil {
+			return string(data)
+		}
+	}
+	return ""
+}
+
+// envReferences returns the variables content reads, in order of appearance
+func envReferences(content string) []string {
+	var names []string
+	for _, m := range envReference.FindAllStringSubmatch(content, -1) {
+		names = appendUnique(names, m[1])
+	}
+	return names
+}
+
+// connectionPrefixes start the variables apps use for a database
+// connection or its parts, longest first. DIRECT_URL is Prisma's
+// connection that bypasses a pooler.
+var connectionPrefixes = []string{"POSTGRESQL_", "POSTGRES_", "DATABASE_", "TYPEORM_", "MONGODB_", "DIRECT_", "MYSQL_", "MONGO_", "DB_", "PG_", "PG"}
+
+// connectionParts maps what follows the prefix to a connection component
+var connectionParts = map[string]string{
+	"URL":      "url",
+	"URI":      "url",
+	"HOST":     "host",
+	"HOSTNAME": "host",
+	"PORT":     "port",
+	"USER":     "user",
+	"USERNAME": "user",
+	"PASSWORD": "password",
+	"PASS":     "password",
+	"NAME":     "database",
+	"DATABASE": "database",
+	"DB":       "database",
+}
+
+// connectionVars sorts the variables an ORM reads into the one that should
+// hold the connection URL and the others that hold a connection component,
+// like DB_HOST or PGPASSWORD. urlVar is "" if the app doesn't read a URL.
+func connectionVars(envVars []string) (urlVar string, components map[string]string) {
+	for _, name := range envVars {
+		component := connectionComponent(name)
+		if component == "" {
+			continue
+		}
+		if component == "url" && urlVar == "" {
+			urlVar = name
+			continue
+		}
+		if components == nil {
+			components = map[string]string{}
+		}
+		components[name] = component
+	}
+	return urlVar, components
+}
+
+func connectionComponent(name string) string {
+	for _, prefix := range connectionPrefixes {
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			return connectionParts[rest]
+		}
+	}
+	return ""
+}
+
+// service returns the database the ORM needs, or nil for SQLite and
+// engines Coolify can't provision
+func (orm *ormInfo) service() *RequiredService {
+	defaults, ok := serviceDefaults[orm.engine]
+	if !ok {
+		return nil
+	}
+	service := &RequiredService{
+		Type:     orm.engine,
+		Version:  defaults.Version,
+		Reason:   "Detected " + ormNames[orm.tool] + " configuration",
+		Required: true,
+	}
+	orm.connect(service)
+	return service
+}
+
+// connect points a database service at the variables the ORM reads
+func (orm *ormInfo) connect(service *RequiredService) {
+	urlVar, components := connectionVars(orm.envVars)
+	service.ConnectionVars = components
+	switch {
+	case urlVar != "":
+		service.EnvVarName = urlVar
+	case len(components) > 0:
+		// The app builds its own connection from the parts
+		service.EnvVarName = ""
+	case service.EnvVarName == "":
+		service.EnvVarName = serviceDefaults[service.Type].EnvVarName
+	}
+}
+
+var ormNames = map[string]string{
+	"prisma":       "Prisma",
+	"drizzle":      "Drizzle",
+	"typeorm":      "TypeORM",
+	"activerecord": "ActiveRecord",
+	"alembic":      "Alembic",
+	"django":       "Django",
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// ConnectionVarNames returns every variable the provisioner sets for the
+// service, EnvVarName first
+func (s *RequiredService) ConnectionVarNames() []string {
+	var names []string
+	if s.EnvVarName != "" {
+		names = append(names, s.EnvVarName)
+	}
+	rest := make([]string, 0, len(s.ConnectionVars))
+	for key := range s.ConnectionVars {
+		rest = append(rest, key)
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
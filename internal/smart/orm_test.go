@@ -0,0 +1,201 @@
+package smart
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDetectORM(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		service   *RequiredService
+		migration *Migration
+	}{
+		{
+			name: "prisma mysql",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"next": "14", "@prisma/client": "5"}, "prisma": {"seed": "tsx prisma/seed.ts"}}`,
+				"prisma/schema.prisma": `datasource db {
+  provider  = "mysql"
+  url       = env("MYSQL_URL")
+  directUrl = env("DIRECT_URL")
+}`,
+				"prisma/migrations/0001_init/migration.sql": "",
+			},
+			service: &RequiredService{
+				Type:           "mysql",
+				Version:        "8.4",
+				Reason:         "Detected Prisma configuration",
+				EnvVarName:     "MYSQL_URL",
+				Required:       true,
+				ConnectionVars: map[string]string{"DIRECT_URL": "url"},
+			},
+			migration: &Migration{Tool: "prisma", Command: "npx prisma migrate deploy", Seed: "npx prisma db seed"},
+		},
+		{
+			name: "drizzle without migrations",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"drizzle-orm": "0.30"}}`,
+				"drizzle.config.ts": `export default defineConfig({
+  dialect: "postgresql",
+  dbCredentials: { url: process.env.DATABASE_URL! },
+});`,
+			},
+			service: &RequiredService{
+				Type:       "postgresql",
+				Version:    "17",
+				Reason:     "Detected Drizzle configuration",
+				EnvVarName: "DATABASE_URL",
+				Required:   true,
+			},
+		},
+		{
+			name: "typeorm with DB_ variables",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"typeorm": "0.3", "pg": "8"}}`,
+				"src/data-source.ts": `export const AppDataSource = new DataSource({
+  type: "postgres",
+  host: process.env.DB_HOST,
+  port: Number(process.env.DB_PORT),
+  username: process.env.DB_USER,
+  password: process.env.DB_PASSWORD,
+  database: process.env.DB_NAME,
+  migrations: ["src/migrations/*.ts"],
+});`,
+			},
+			service: &RequiredService{
+				Type:     "postgresql",
+				Version:  "17",
+				Reason:   "Detected TypeORM configuration",
+				Required: true,
+				ConnectionVars: map[string]string{
+					"DB_HOST":     "host",
+					"DB_PORT":     "port",
+					"DB_USER":     "user",
+					"DB_PASSWORD": "password",
+					"DB_NAME":     "database",
+				},
+			},
+			migration: &Migration{Tool: "typeorm", Command: "npx typeorm-ts-node-commonjs migration:run -d src/data-source.ts"},
+		},
+		{
+			name: "rails sqlite",
+			files: map[string]string{
+				"Gemfile": `gem "rails", "~> 7.1"`,
+				"config/database.yml": `default: &default
+  adapter: sqlite3
+production:
+  <<: *default
+  database: storage/production.sqlite3`,
+				"db/migrate/20240101000000_create_users.rb": "",
+				"db/seeds.rb": "# This file should ensure the existence of records required to run the application\n#\n#   Role.find_or_create_by!(name: \"admin\")\n",
+			},
+			migration: &Migration{Tool: "activerecord", Command: "bundle exec rails db:migrate"},
+		},
+		{
+			name: "rails postgres with seeds",
+			files: map[string]string{
+				"Gemfile": `gem "rails", "~> 7.1"`,
+				"config/database.yml": `production:
+  adapter: postgresql
+  url: <%= ENV["DATABASE_URL"] %>`,
+				"db/migrate/20240101000000_create_users.rb": "",
+				"db/seeds.rb": "User.find_or_create_by!(email: \"admin@example.com\")\n",
+			},
+			service: &RequiredService{
+				Type:       "postgresql",
+				Version:    "17",
+				Reason:     "Detected ActiveRecord configuration",
+				EnvVarName: "DATABASE_URL",
+				Required:   true,
+			},
+			migration: &Migration{Tool: "activerecord", Command: "bundle exec rails db:migrate", Seed: "bundle exec rails db:seed"},
+		},
+		{
+			name: "django with dj-database-url",
+			files: map[string]string{
+				"manage.py":        "",
+				"requirements.txt": "Django==5.0\ndj-database-url\npsycopg[binary]\n",
+				"mysite/settings.py": `import dj_database_url
+DATABASES = {"default": dj_database_url.config(conn_max_age=600)}`,
+			},
+			service: &RequiredService{
+				Type:       "postgresql",
+				Version:    "17",
+				Reason:     "Detected Django configuration",
+				EnvVarName: "DATABASE_URL",
+				Required:   true,
+			},
+			migration: &Migration{Tool: "django", Command: "python manage.py migrate --noinput"},
+		},
+		{
+			name: "alembic",
+			files: map[string]string{
+				"requirements.txt": "fastapi\nsqlalchemy\nalembic\n",
+				"alembic.ini": `[alembic]
+script_location = %(here)s/migrations
+sqlalchemy.url = postgresql+psycopg://localhost/app`,
+				"migrations/env.py": `config.set_main_option("sqlalchemy.url", os.environ["PG_URL"])`,
+			},
+			service: &RequiredService{
+				Type:       "postgresql",
+				Version:    "17",
+				Reason:     "Detected Alembic configuration",
+				EnvVarName: "PG_URL",
+				Required:   true,
+			},
+			migration: &Migration{Tool: "alembic", Command: "alembic upgrade head"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := NewSmartDetector(writeFiles(t, tt.files)).Detect()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var database *RequiredService
+			for i, s := range config.Services {
+				if s.Type == "postgresql" || s.Type == "mysql" || s.Type == "mongodb" {
+					database = &config.Services[i]
+				}
+			}
+			if !reflect.DeepEqual(database, tt.service) {
+				t.Errorf("database = %+v, want %+v", database, tt.service)
+			}
+			if !reflect.DeepEqual(config.Migration, tt.migration) {
+				t.Errorf("migration = %+v, want %+v", config.Migration, tt.migration)
+			}
+		})
+	}
+}
+
+func TestConnectionVars(t *testing.T) {
+	urlVar, components := connectionVars([]string{"NODE_ENV", "PGHOST", "PGPASSWORD", "POSTGRES_URL", "PGSSLMODE"})
+	if urlVar != "POSTGRES_URL" {
+		t.Errorf("urlVar = %q, want POSTGRES_URL", urlVar)
+	}
+	want := map[string]string{"PGHOST": "host", "PGPASSWORD": "password"}
+	if !reflect.DeepEqual(components, want) {
+		t.Errorf("components = %v, want %v", components, want)
+	}
+}
@@ -0,0 +1,125 @@
+package smart
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// memoryEstimates are what a small instance of each service type needs, in
+// MiB, for plans
+var memoryEstimates = map[string]int{
+	"postgresql":    256,
+	"timescaledb":   512,
+	"mysql":         512,
+	"mongodb":       512,
+	"clickhouse":    1024,
+	"redis":         64,
+	"meilisearch":   256,
+	"elasticsearch": 2048,
+	"minio":         256,
+	"rabbitmq":      256,
+	"kafka":         1024,
+}
+
+// ProvisioningPlan is what Provision would create and set
+type ProvisioningPlan struct {
+	Services        []PlannedService
+	EnvironmentVars []PlannedVar // sorted by key
+}
+
+// PlannedService is a service Provision would create
+type PlannedService struct {
+	RequiredService
+	Name     string
+	MemoryMB int // 0 if unknown
+}
+
+// PlannedVar is a variable Provision would set on the application
+type PlannedVar struct {
+	Key   string
+	Value string // the value, or what it will hold, like "url of shop-postgres"
+}
+
+// Plan describes what Provision would do with config without calling the
+// Coolify API
+func (sp *ServiceProvisioner) Plan(config *DeploymentConfig) *ProvisioningPlan {
+	plan := &ProvisioningPlan{}
+	env := map[string]string{}
+
+	for _, service := range config.Services {
+		name := sp.generateServiceName(serviceNamePrefix(service.Type))
+		plan.Services = append(plan.Services, PlannedService{
+			RequiredService: service,
+			Name:            name,
+			MemoryMB:        memoryEstimates[service.Type],
+		})
+
+		tmpl := config.ConnectionTemplates[service.Type]
+		url := "url of " + name
+		if tmpl.URL != "" {
+			url = tmpl.URL + " (" + name + ")"
+		}
+		if service.EnvVarName != "" && !tmpl.NoURL {
+			env[service.EnvVarName] = url
+		}
+		for key, component := range service.ConnectionVars {
+			switch {
+			case strings.HasPrefix(component, "="):
+				env[key] = component[1:]
+			case component == "url":
+				env[key] = url
+			default:
+				env[key] = component + " of " + name
+			}
+		}
+		for key, value := range tmpl.Vars {
+			env[key] = value + " (" + name + ")"
+		}
+	}
+
+	for _, envVar := range config.Environment {
+		if _, exists := env[envVar.Key]; exists {
+			continue
+		}
+		value := envVar.Value
+		if envVar.Secret && value != "" {
+			value = "(secret)"
+		}
+		env[envVar.Key] = value
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		plan.EnvironmentVars = append(plan.EnvironmentVars, PlannedVar{Key: key, Value: env[key]})
+	}
+	return plan
+}
+
+// MemoryMB returns the memory the planned services need altogether, and
+// whether every service has an estimate
+func (p *ProvisioningPlan) MemoryMB() (total int, complete bool) {
+	complete = true
+	for _, s := range p.Services {
+		if s.MemoryMB == 0 {
+			complete = false
+		}
+		total += s.MemoryMB
+	}
+	return total, complete
+}
+
+// FormatMemory renders an amount of memory in MiB for plans
+func FormatMemory(mb int) string {
+	if mb >= 1024 && mb%1024 == 0 {
+		return fmt.Sprintf("%d GiB", mb/1024)
+	}
+	if mb >= 1024 {
+		return fmt.Sprintf("%.1f GiB", float64(mb)/1024)
+	}
+	return fmt.Sprintf("%d MiB", mb)
+}
@@ -0,0 +1,86 @@
+package smart
+
+import "testing"
+
+func TestPlan(t *testing.T) {
+	config := &DeploymentConfig{
+		Services: []RequiredService{
+			{Type: "postgresql", Version: "17", EnvVarName: "DATABASE_URL", Required: true},
+			{Type: "redis", Version: "7", EnvVarName: "REDIS_URL", ConnectionVars: map[string]string{
+				"REDIS_HOST":   "host",
+				"CACHE_DRIVER": "=redis",
+			}},
+			{Type: "ftp"},
+		},
+		Environment: []EnvironmentVariable{
+			{Key: "DATABASE_URL", Value: "postgres://localhost/dev"},
+			{Key: "APP_KEY", Value: "base64:abc", Secret: true},
+			{Key: "LOG_LEVEL", Value: "info"},
+		},
+	}
+
+	plan := NewServiceProvisioner(nil, "", "", "", "My Shop").Plan(config)
+
+	if len(plan.Services) != 3 {
+		t.Fatalf("got %d services, want 3", len(plan.Services))
+	}
+	if got := plan.Services[0].Name; got != "my-shop-postgres" {
+		t.Errorf("name = %q, want my-shop-postgres", got)
+	}
+	if got := plan.Services[0].MemoryMB; got != 256 {
+		t.Errorf("postgresql memory = %d, want 256", got)
+	}
+	if total, complete := plan.MemoryMB(); total != 320 || complete {
+		t.Errorf("MemoryMB() = %d, %v, want 320, false", total, complete)
+	}
+
+	want := map[string]string{
+		"APP_KEY":      "(secret)",
+		"CACHE_DRIVER": "redis",
+		"DATABASE_URL": "url of my-shop-postgres",
+		"LOG_LEVEL":    "info",
+		"REDIS_HOST":   "host of my-shop-redis",
+		"REDIS_URL":    "url of my-shop-redis",
+	}
+	if len(plan.EnvironmentVars) != len(want) {
+		t.Fatalf("got %d variables, want %d: %v", len(plan.EnvironmentVars), len(want), plan.EnvironmentVars)
+	}
+	for i, v := range plan.EnvironmentVars {
+		if want[v.Key] != v.Value {
+			t.Errorf("%s = %q, want %q", v.Key, v.Value, want[v.Key])
+		}
+		if i > 0 && plan.EnvironmentVars[i-1].Key > v.Key {
+			t.Errorf("variables aren't sorted: %s before %s", plan.EnvironmentVars[i-1].Key, v.Key)
+		}
+	}
+}
+
+func TestPlanConnectionTemplate(t *testing.T) {
+	config := &DeploymentConfig{
+		Services: []RequiredService{{Type: "postgresql", EnvVarName: "DATABASE_URL"}},
+		ConnectionTemplates: map[string]ConnectionTemplate{
+			"postgresql": {NoURL: true, Vars: map[string]string{"DB_HOST": "{host}"}},
+		},
+	}
+
+	plan := NewServiceProvisioner(nil, "", "", "", "shop").Plan(config)
+
+	if len(plan.EnvironmentVars) != 1 {
+		t.Fatalf("got %v, want only DB_HOST", plan.EnvironmentVars)
+	}
+	if v := plan.EnvironmentVars[0]; v.Key != "DB_HOST" || v.Value != "{host} (shop-postgres)" {
+		t.Errorf("got %s = %q", v.Key, v.Value)
+	}
+}
+
+func TestFormatMemory(t *testing.T) {
+	for mb, want := range map[int]string{
+		64:   "64 MiB",
+		1024: "1 GiB",
+		1536: "1.5 GiB",
+	} {
+		if got := FormatMemory(mb); got != want {
+			t.Errorf("FormatMemory(%d) = %q, want %q", mb, got, want)
+		}
+	}
+}
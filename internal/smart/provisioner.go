@@ -14,6 +14,7 @@ type ProvisioningResult struct {
 	Services        []ProvisionedService
 	EnvironmentVars map[string]string
 	Errors          []error
+	Notes           []string // what the user has to finish by hand
 }
 
 // ProvisionedService represents a service that was created in Coolify
@@ -23,6 +24,39 @@ type ProvisionedService struct {
 	Name          string
 	ConnectionURL string
 	EnvVarName    string
+	Version       string
+	Required      bool
+	IsService     bool // a Coolify service rather than a standalone database
+
+	// The parts of the connection, for ConnectionVars
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Database string
+}
+
+// connectionPart returns a component of the connection named as in
+// RequiredService.ConnectionVars
+func (ps *ProvisionedService) connectionPart(component string) string {
+	if value, ok := strings.CutPrefix(component, "="); ok {
+		return value
+	}
+	switch component {
+	case "url":
+		return ps.ConnectionURL
+	case "host":
+		return ps.Host
+	case "port":
+		return ps.Port
+	case "user":
+		return ps.User
+	case "password":
+		return ps.Password
+	case "database":
+		return ps.Database
+	}
+	return ""
 }
 
 // ServiceProvisioner handles automatic service creation in Coolify
@@ -64,10 +98,31 @@ func (sp *ServiceProvisioner) Provision(config *DeploymentConfig) (*Provisioning
 			continue
 		}
 
+		provisioned.Version = service.Version
+		provisioned.Required = service.Required
+		// The project's template overrides the generated connection
+		tmpl := config.ConnectionTemplates[service.Type]
+		if tmpl.URL != "" {
+			provisioned.ConnectionURL = provisioned.expand(tmpl.URL)
+		}
 		result.Services = append(result.Services, *provisioned)
-		result.EnvironmentVars[provisioned.EnvVarName] = provisioned.ConnectionURL
+		if provisioned.Type == "minio" {
+			// Neither MinIO nor its Coolify template creates buckets
+			result.Notes = append(result.Notes, fmt.Sprintf("Create the bucket %q in the MinIO console of %s", provisioned.Database, provisioned.Name))
+		}
+		if provisioned.EnvVarName != "" && !tmpl.NoURL {
+			result.EnvironmentVars[provisioned.EnvVarName] = provisioned.ConnectionURL
+		}
+		for key, component := range service.ConnectionVars {
+			result.EnvironmentVars[key] = provisioned.connectionPart(component)
+		}
+		for key, value := range tmpl.Vars {
+			result.EnvironmentVars[key] = provisioned.expand(value)
+		}
 	}
 
+	result.Notes = append(result.Notes, sp.versionMismatches(result.Services)...)
+
 	// Add user-defined environment variables from detection
 	for _, envVar := range config.Environment {
 		// Skip auto-generated vars - we've already set them from services
@@ -86,6 +141,10 @@ func (sp *ServiceProvisioner) provisionService(service RequiredService) (*Provis
 	switch service.Type {
 	case "postgresql":
 		return sp.provisionPostgreSQL(service)
+	case "timescaledb":
+		return sp.provisionTimescaleDB(service)
+	case "clickhouse":
+		return sp.provisionClickHouse(service)
 	case "mysql":
 		return sp.provisionMySQL(service)
 	case "mongodb":
@@ -96,6 +155,12 @@ func (sp *ServiceProvisioner) provisionService(service RequiredService) (*Provis
 		return sp.provisionMeilisearch(service)
 	case "elasticsearch":
 		return sp.provisionElasticsearch(service)
+	case "minio":
+		return sp.provisionMinIO(service)
+	case "rabbitmq":
+		return sp.provisionRabbitMQ(service)
+	case "kafka":
+		return sp.provisionKafka(service)
 	default:
 		return nil, fmt.Errorf("unsupported service type: %s", service.Type)
 	}
@@ -103,7 +168,18 @@ func (sp *ServiceProvisioner) provisionService(service RequiredService) (*Provis
 
 // provisionPostgreSQL creates a PostgreSQL database
 func (sp *ServiceProvisioner) provisionPostgreSQL(service RequiredService) (*ProvisionedService, error) {
-	name := sp.generateServiceName("postgres")
+	return sp.provisionPostgres(service, fmt.Sprintf("postgres:%s", service.Version))
+}
+
+// provisionTimescaleDB creates a PostgreSQL database from the TimescaleDB
+// image, whose init script creates the extension in the new database
+func (sp *ServiceProvisioner) provisionTimescaleDB(service RequiredService) (*ProvisionedService, error) {
+	return sp.provisionPostgres(service, fmt.Sprintf("timescale/timescaledb:%s", service.Version))
+}
+
+// provisionPostgres creates a PostgreSQL database running image
+func (sp *ServiceProvisioner) provisionPostgres(service RequiredService, image string) (*ProvisionedService, error) {
+	name := sp.generateServiceName(serviceNamePrefix(service.Type))
 
 	password, err := sp.generatePassword()
 	if err != nil {
@@ -111,45 +187,43 @@ func (sp *ServiceProvisioner) provisionPostgreSQL(service RequiredService) (*Pro
 	}
 
 	// Create PostgreSQL database via Coolify API
-	dbConfig := map[string]interface{}{
-		"name":              name,
-		"description":       fmt.Sprintf("PostgreSQL database for %s (%s)", sp.appName, service.Reason),
-		"project_uuid":      sp.projectUUID,
-		"environment_name":  sp.environmentUUID,
-		"server_uuid":       sp.serverUUID,
-		"type":              "postgresql",
-		"image":             fmt.Sprintf("postgres:%s", service.Version),
-		"postgres_db":       sp.sanitizeDBName(sp.appName),
-		"postgres_user":     sp.sanitizeDBName(sp.appName),
-		"postgres_password": password,
-		"is_public":         false,
-	}
-
-	response, err := sp.client.CreateDatabase(dbConfig)
+	req := &api.CreatePostgreSQLRequest{
+		CreateDatabaseRequest: sp.databaseRequest(name,
+			fmt.Sprintf("PostgreSQL database for %s (%s)", sp.appName, service.Reason),
+			image),
+		PostgresDB:       sp.sanitizeDBName(sp.appName),
+		PostgresUser:     sp.sanitizeDBName(sp.appName),
+		PostgresPassword: password,
+	}
+
+	response, err := sp.client.CreateDatabase(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create PostgreSQL database: %w", err)
 	}
-
-	uuid, ok := response["uuid"].(string)
-	if !ok {
+	if response.UUID == "" {
 		return nil, fmt.Errorf("invalid response from Coolify API: missing uuid")
 	}
 
 	// Generate connection URL
 	connectionURL := fmt.Sprintf(
 		"postgresql://%s:%s@%s:5432/%s",
-		dbConfig["postgres_user"],
-		dbConfig["postgres_password"],
+		req.PostgresUser,
+		req.PostgresPassword,
 		name,
-		dbConfig["postgres_db"],
+		req.PostgresDB,
 	)
 
 	return &ProvisionedService{
-		Type:          "postgresql",
-		UUID:          uuid,
+		Type:          service.Type,
+		UUID:          response.UUID,
 		Name:          name,
 		ConnectionURL: connectionURL,
 		EnvVarName:    service.EnvVarName,
+		Host:          name,
+		Port:          "5432",
+		User:          req.PostgresUser,
+		Password:      req.PostgresPassword,
+		Database:      req.PostgresDB,
 	}, nil
 }
 
@@ -166,45 +240,43 @@ func (sp *ServiceProvisioner) provisionMySQL(service RequiredService) (*Provisio
 		return nil, fmt.Errorf("failed to generate root password: %w", err)
 	}
 
-	dbConfig := map[string]interface{}{
-		"name":                name,
-		"description":         fmt.Sprintf("MySQL database for %s (%s)", sp.appName, service.Reason),
-		"project_uuid":        sp.projectUUID,
-		"environment_name":    sp.environmentUUID,
-		"server_uuid":         sp.serverUUID,
-		"type":                "mysql",
-		"image":               fmt.Sprintf("mysql:%s", service.Version),
-		"mysql_database":      sp.sanitizeDBName(sp.appName),
-		"mysql_user":          sp.sanitizeDBName(sp.appName),
-		"mysql_password":      password,
-		"mysql_root_password": rootPassword,
-		"is_public":           false,
+	req := &api.CreateMySQLRequest{
+		CreateDatabaseRequest: sp.databaseRequest(name,
+			fmt.Sprintf("MySQL database for %s (%s)", sp.appName, service.Reason),
+			fmt.Sprintf("mysql:%s", service.Version)),
+		MySQLDatabase:     sp.sanitizeDBName(sp.appName),
+		MySQLUser:         sp.sanitizeDBName(sp.appName),
+		MySQLPassword:     password,
+		MySQLRootPassword: rootPassword,
 	}
 
-	response, err := sp.client.CreateDatabase(dbConfig)
+	response, err := sp.client.CreateDatabase(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create MySQL database: %w", err)
 	}
-
-	uuid, ok := response["uuid"].(string)
-	if !ok {
+	if response.UUID == "" {
 		return nil, fmt.Errorf("invalid response from Coolify API: missing uuid")
 	}
 
 	connectionURL := fmt.Sprintf(
 		"mysql://%s:%s@%s:3306/%s",
-		dbConfig["mysql_user"],
-		dbConfig["mysql_password"],
+		req.MySQLUser,
+		req.MySQLPassword,
 		name,
-		dbConfig["mysql_database"],
+		req.MySQLDatabase,
 	)
 
 	return &ProvisionedService{
 		Type:          "mysql",
-		UUID:          uuid,
+		UUID:          response.UUID,
 		Name:          name,
 		ConnectionURL: connectionURL,
 		EnvVarName:    service.EnvVarName,
+		Host:          name,
+		Port:          "3306",
+		User:          req.MySQLUser,
+		Password:      req.MySQLPassword,
+		Database:      req.MySQLDatabase,
 	}, nil
 }
 
@@ -217,42 +289,88 @@ func (sp *ServiceProvisioner) provisionMongoDB(service RequiredService) (*Provis
 		return nil, fmt.Errorf("failed to generate password: %w", err)
 	}
 
-	dbConfig := map[string]interface{}{
-		"name":                       name,
-		"description":                fmt.Sprintf("MongoDB database for %s (%s)", sp.appName, service.Reason),
-		"project_uuid":               sp.projectUUID,
-		"environment_name":           sp.environmentUUID,
-		"server_uuid":                sp.serverUUID,
-		"type":                       "mongodb",
-		"image":                      fmt.Sprintf("mongo:%s", service.Version),
-		"mongo_initdb_root_username": sp.sanitizeDBName(sp.appName),
-		"mongo_initdb_root_password": password,
-		"is_public":                  false,
+	req := &api.CreateMongoDBRequest{
+		CreateDatabaseRequest: sp.databaseRequest(name,
+			fmt.Sprintf("MongoDB database for %s (%s)", sp.appName, service.Reason),
+			fmt.Sprintf("mongo:%s", service.Version)),
+		MongoInitdbRootUsername: sp.sanitizeDBName(sp.appName),
+		MongoInitdbRootPassword: password,
 	}
 
-	response, err := sp.client.CreateDatabase(dbConfig)
+	response, err := sp.client.CreateDatabase(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create MongoDB database: %w", err)
 	}
-
-	uuid, ok := response["uuid"].(string)
-	if !ok {
+	if response.UUID == "" {
 		return nil, fmt.Errorf("invalid response from Coolify API: missing uuid")
 	}
 
 	connectionURL := fmt.Sprintf(
 		"mongodb://%s:%s@%s:27017",
-		dbConfig["mongo_initdb_root_username"],
-		dbConfig["mongo_initdb_root_password"],
+		req.MongoInitdbRootUsername,
+		req.MongoInitdbRootPassword,
 		name,
 	)
 
 	return &ProvisionedService{
 		Type:          "mongodb",
-		UUID:          uuid,
+		UUID:          response.UUID,
+		Name:          name,
+		ConnectionURL: connectionURL,
+		EnvVarName:    service.EnvVarName,
+		Host:          name,
+		Port:          "27017",
+		User:          req.MongoInitdbRootUsername,
+		Password:      req.MongoInitdbRootPassword,
+		// MongoDB creates the database on first use
+		Database: sp.sanitizeDBName(sp.appName),
+	}, nil
+}
+
+// provisionClickHouse creates a ClickHouse database
+func (sp *ServiceProvisioner) provisionClickHouse(service RequiredService) (*ProvisionedService, error) {
+	name := sp.generateServiceName("clickhouse")
+
+	password, err := sp.generatePassword()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	req := &api.CreateClickHouseRequest{
+		CreateDatabaseRequest: sp.databaseRequest(name,
+			fmt.Sprintf("ClickHouse database for %s (%s)", sp.appName, service.Reason),
+			fmt.Sprintf("clickhouse/clickhouse-server:%s", service.Version)),
+		ClickHouseAdminUser:     sp.sanitizeDBName(sp.appName),
+		ClickHouseAdminPassword: password,
+	}
+
+	response, err := sp.client.CreateDatabase(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ClickHouse database: %w", err)
+	}
+	if response.UUID == "" {
+		return nil, fmt.Errorf("invalid response from Coolify API: missing uuid")
+	}
+
+	// ClickHouse clients connect over HTTP
+	connectionURL := fmt.Sprintf(
+		"http://%s:%s@%s:8123",
+		req.ClickHouseAdminUser,
+		req.ClickHouseAdminPassword,
+		name,
+	)
+
+	return &ProvisionedService{
+		Type:          "clickhouse",
+		UUID:          response.UUID,
 		Name:          name,
 		ConnectionURL: connectionURL,
 		EnvVarName:    service.EnvVarName,
+		Host:          name,
+		Port:          "8123",
+		User:          req.ClickHouseAdminUser,
+		Password:      req.ClickHouseAdminPassword,
+		Database:      "default",
 	}, nil
 }
 
@@ -265,40 +383,36 @@ func (sp *ServiceProvisioner) provisionRedis(service RequiredService) (*Provisio
 		return nil, fmt.Errorf("failed to generate password: %w", err)
 	}
 
-	dbConfig := map[string]interface{}{
-		"name":             name,
-		"description":      fmt.Sprintf("Redis for %s (%s)", sp.appName, service.Reason),
-		"project_uuid":     sp.projectUUID,
-		"environment_name": sp.environmentUUID,
-		"server_uuid":      sp.serverUUID,
-		"type":             "redis",
-		"image":            fmt.Sprintf("redis:%s", service.Version),
-		"redis_password":   password,
-		"is_public":        false,
+	req := &api.CreateRedisRequest{
+		CreateDatabaseRequest: sp.databaseRequest(name,
+			fmt.Sprintf("Redis for %s (%s)", sp.appName, service.Reason),
+			fmt.Sprintf("redis:%s", service.Version)),
+		RedisPassword: password,
 	}
 
-	response, err := sp.client.CreateDatabase(dbConfig)
+	response, err := sp.client.CreateDatabase(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Redis instance: %w", err)
 	}
-
-	uuid, ok := response["uuid"].(string)
-	if !ok {
+	if response.UUID == "" {
 		return nil, fmt.Errorf("invalid response from Coolify API: missing uuid")
 	}
 
 	connectionURL := fmt.Sprintf(
 		"redis://:%s@%s:6379",
-		dbConfig["redis_password"],
+		req.RedisPassword,
 		name,
 	)
 
 	return &ProvisionedService{
 		Type:          "redis",
-		UUID:          uuid,
+		UUID:          response.UUID,
 		Name:          name,
 		ConnectionURL: connectionURL,
 		EnvVarName:    service.EnvVarName,
+		Host:          name,
+		Port:          "6379",
+		Password:      req.RedisPassword,
 	}, nil
 }
 
@@ -311,35 +425,22 @@ func (sp *ServiceProvisioner) provisionMeilisearch(service RequiredService) (*Pr
 	}
 
 	// Meilisearch is typically deployed as a service, not a database
-	serviceConfig := map[string]interface{}{
-		"name":             name,
-		"description":      fmt.Sprintf("Meilisearch for %s (%s)", sp.appName, service.Reason),
-		"project_uuid":     sp.projectUUID,
-		"environment_name": sp.environmentUUID,
-		"server_uuid":      sp.serverUUID,
-		"type":             "meilisearch",
-		"image":            "getmeili/meilisearch:latest",
-		"environment_variables": map[string]string{
+	uuid, err := sp.createService("meilisearch", name,
+		fmt.Sprintf("Meilisearch for %s (%s)", sp.appName, service.Reason),
+		map[string]string{
 			"MEILI_MASTER_KEY": masterKey,
 			"MEILI_ENV":        "production",
 		},
-		"is_public": false,
-	}
-
-	response, err := sp.client.CreateService(serviceConfig)
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Meilisearch service: %w", err)
 	}
 
-	uuid, ok := response["uuid"].(string)
-	if !ok {
-		return nil, fmt.Errorf("invalid response from Coolify API: missing uuid")
-	}
-
 	connectionURL := fmt.Sprintf("http://%s:7700", name)
 
 	return &ProvisionedService{
 		Type:          "meilisearch",
+		IsService:     true,
 		UUID:          uuid,
 		Name:          name,
 		ConnectionURL: connectionURL,
@@ -355,45 +456,241 @@ func (sp *ServiceProvisioner) provisionElasticsearch(service RequiredService) (*
 		return nil, fmt.Errorf("failed to generate password: %w", err)
 	}
 
-	serviceConfig := map[string]interface{}{
-		"name":             name,
-		"description":      fmt.Sprintf("Elasticsearch for %s (%s)", sp.appName, service.Reason),
-		"project_uuid":     sp.projectUUID,
-		"environment_name": sp.environmentUUID,
-		"server_uuid":      sp.serverUUID,
-		"type":             "elasticsearch",
-		"image":            fmt.Sprintf("elasticsearch:%s", service.Version),
-		"environment_variables": map[string]string{
+	uuid, err := sp.createService("elasticsearch", name,
+		fmt.Sprintf("Elasticsearch for %s (%s)", sp.appName, service.Reason),
+		map[string]string{
 			"discovery.type":         "single-node",
 			"ELASTIC_PASSWORD":       password,
 			"xpack.security.enabled": "true",
 		},
-		"is_public": false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Elasticsearch service: %w", err)
+	}
+
+	connectionURL := fmt.Sprintf("https://elastic:%s@%s:9200", password, name)
+
+	return &ProvisionedService{
+		Type:          "elasticsearch",
+		IsService:     true,
+		UUID:          uuid,
+		Name:          name,
+		ConnectionURL: connectionURL,
+		EnvVarName:    service.EnvVarName,
+	}, nil
+}
+
+// provisionMinIO creates a MinIO instance for S3-compatible object storage
+func (sp *ServiceProvisioner) provisionMinIO(service RequiredService) (*ProvisionedService, error) {
+	name := sp.generateServiceName("minio")
+	secretKey, err := sp.generatePassword()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret key: %w", err)
+	}
+	accessKey := sp.sanitizeDBName(sp.appName)
+	if len(accessKey) < 3 {
+		// MinIO rejects shorter root users
+		accessKey = "minio"
+	}
+
+	// The template reads the root credentials from these variables
+	uuid, err := sp.createService("minio", name,
+		fmt.Sprintf("MinIO for %s (%s)", sp.appName, service.Reason),
+		map[string]string{
+			"SERVICE_USER_MINIO":     accessKey,
+			"SERVICE_PASSWORD_MINIO": secretKey,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MinIO service: %w", err)
 	}
 
-	response, err := sp.client.CreateService(serviceConfig)
+	return &ProvisionedService{
+		Type:          "minio",
+		IsService:     true,
+		UUID:          uuid,
+		Name:          name,
+		ConnectionURL: fmt.Sprintf("http://%s:9000", name),
+		EnvVarName:    service.EnvVarName,
+		Host:          name,
+		Port:          "9000",
+		User:          accessKey,
+		Password:      secretKey,
+		Database:      sp.bucketName(),
+	}, nil
+}
+
+// provisionRabbitMQ creates a RabbitMQ broker
+func (sp *ServiceProvisioner) provisionRabbitMQ(service RequiredService) (*ProvisionedService, error) {
+	name := sp.generateServiceName("rabbitmq")
+	password, err := sp.generatePassword()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Elasticsearch service: %w", err)
+		return nil, fmt.Errorf("failed to generate password: %w", err)
 	}
+	user := sp.sanitizeDBName(sp.appName)
 
-	uuid, ok := response["uuid"].(string)
-	if !ok {
-		return nil, fmt.Errorf("invalid response from Coolify API: missing uuid")
+	// The template reads the default user from these variables
+	uuid, err := sp.createService("rabbitmq", name,
+		fmt.Sprintf("RabbitMQ for %s (%s)", sp.appName, service.Reason),
+		map[string]string{
+			"SERVICE_USER_RABBITMQ":     user,
+			"SERVICE_PASSWORD_RABBITMQ": password,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RabbitMQ service: %w", err)
 	}
 
-	connectionURL := fmt.Sprintf("https://elastic:%s@%s:9200", password, name)
+	// Passwords are base64url, so they need no escaping in the URL
+	connectionURL := fmt.Sprintf("amqp://%s:%s@%s:5672/", user, password, name)
 
 	return &ProvisionedService{
-		Type:          "elasticsearch",
+		Type:          "rabbitmq",
+		IsService:     true,
 		UUID:          uuid,
 		Name:          name,
 		ConnectionURL: connectionURL,
 		EnvVarName:    service.EnvVarName,
+		Host:          name,
+		Port:          "5672",
+		User:          user,
+		Password:      password,
+	}, nil
+}
+
+// kafkaCompose runs a single KRaft node, broker and controller in one, with
+// a SASL/PLAIN listener for the generated user
+const kafkaCompose = `services:
+  kafka:
+    image: apache/kafka:%[1]s
+    hostname: %[2]s
+    environment:
+      KAFKA_NODE_ID: 1
+      KAFKA_PROCESS_ROLES: broker,controller
+      KAFKA_LISTENERS: SASL_PLAINTEXT://:9092,CONTROLLER://:9093
+      KAFKA_ADVERTISED_LISTENERS: SASL_PLAINTEXT://%[2]s:9092
+      KAFKA_CONTROLLER_LISTENER_NAMES: CONTROLLER
+      KAFKA_CONTROLLER_QUORUM_VOTERS: 1@localhost:9093
+      KAFKA_LISTENER_SECURITY_PROTOCOL_MAP: CONTROLLER:PLAINTEXT,SASL_PLAINTEXT:SASL_PLAINTEXT
+      KAFKA_INTER_BROKER_LISTENER_NAME: SASL_PLAINTEXT
+      KAFKA_SASL_ENABLED_MECHANISMS: PLAIN
+      KAFKA_SASL_MECHANISM_INTER_BROKER_PROTOCOL: PLAIN
+      KAFKA_LISTENER_NAME_SASL__PLAINTEXT_PLAIN_SASL_JAAS_CONFIG: 'org.apache.kafka.common.security.plain.PlainLoginModule required username="%[3]s" password="%[4]s" user_%[3]s="%[4]s";'
+      KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR: 1
+      KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR: 1
+      KAFKA_TRANSACTION_STATE_LOG_MIN_ISR: 1
+    volumes:
+      - kafka-data:/var/lib/kafka/data
+volumes:
+  kafka-data:
+`
+
+// provisionKafka creates a Kafka broker. Coolify has no template for it, so
+// the service is created from a compose file.
+func (sp *ServiceProvisioner) provisionKafka(service RequiredService) (*ProvisionedService, error) {
+	name := sp.generateServiceName("kafka")
+	password, err := sp.generatePassword()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	user := sp.sanitizeDBName(sp.appName)
+
+	uuid, err := sp.createComposeService(name,
+		fmt.Sprintf("Kafka for %s (%s)", sp.appName, service.Reason),
+		fmt.Sprintf(kafkaCompose, service.Version, name, user, password),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka service: %w", err)
+	}
+
+	// Kafka clients take a list of brokers rather than a URL
+	return &ProvisionedService{
+		Type:          "kafka",
+		IsService:     true,
+		UUID:          uuid,
+		Name:          name,
+		ConnectionURL: name + ":9092",
+		EnvVarName:    service.EnvVarName,
+		Host:          name,
+		Port:          "9092",
+		User:          user,
+		Password:      password,
 	}, nil
 }
 
 // Helper functions
 
+// createService creates a one-click service from a template and applies its environment
+func (sp *ServiceProvisioner) createService(templateType, name, description string, env map[string]string) (string, error) {
+	response, err := sp.client.CreateService(&api.CreateServiceRequest{
+		Type:            templateType,
+		Name:            name,
+		Description:     description,
+		ProjectUUID:     sp.projectUUID,
+		EnvironmentUUID: sp.environmentUUID,
+		ServerUUID:      sp.serverUUID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if response.UUID == "" {
+		return "", fmt.Errorf("invalid response from Coolify API: missing uuid")
+	}
+
+	if len(env) > 0 {
+		envVars := make([]api.EnvironmentVariable, 0, len(env))
+		for key, value := range env {
+			envVars = append(envVars, api.EnvironmentVariable{Key: key, Value: value})
+		}
+		if err := sp.client.UpdateServiceEnvsBulk(response.UUID, envVars); err != nil {
+			return "", fmt.Errorf("failed to configure service environment: %w", err)
+		}
+	}
+
+	return response.UUID, nil
+}
+
+// createComposeService creates a service from a raw compose file, for
+// software without a one-click template
+func (sp *ServiceProvisioner) createComposeService(name, description, compose string) (string, error) {
+	response, err := sp.client.CreateService(&api.CreateServiceRequest{
+		Name:             name,
+		Description:      description,
+		ProjectUUID:      sp.projectUUID,
+		EnvironmentUUID:  sp.environmentUUID,
+		ServerUUID:       sp.serverUUID,
+		DockerComposeRaw: base64.StdEncoding.EncodeToString([]byte(compose)),
+	})
+	if err != nil {
+		return "", err
+	}
+	if response.UUID == "" {
+		return "", fmt.Errorf("invalid response from Coolify API: missing uuid")
+	}
+	return response.UUID, nil
+}
+
+// databaseRequest returns the common create fields for a private database in the target environment
+func (sp *ServiceProvisioner) databaseRequest(name, description, image string) api.CreateDatabaseRequest {
+	return api.CreateDatabaseRequest{
+		Name:            name,
+		Description:     description,
+		Image:           image,
+		ProjectUUID:     sp.projectUUID,
+		EnvironmentUUID: sp.environmentUUID,
+		ServerUUID:      sp.serverUUID,
+	}
+}
+
+// serviceNamePrefix is what generateServiceName appends to the app name for
+// a service type
+func serviceNamePrefix(serviceType string) string {
+	if serviceType == "postgresql" {
+		return "postgres"
+	}
+	return serviceType
+}
+
 // generateServiceName creates a unique service name
 func (sp *ServiceProvisioner) generateServiceName(serviceType string) string {
 	// Sanitize app name for use in service name
@@ -428,6 +725,19 @@ func (sp *ServiceProvisioner) sanitizeDBName(name string) string {
 	return result
 }
 
+// bucketName derives an S3 bucket name from the app name: 3 to 63 lowercase
+// letters, digits and hyphens
+func (sp *ServiceProvisioner) bucketName() string {
+	name := strings.Trim(strings.ReplaceAll(sp.sanitizeDBName(sp.appName), "_", "-"), "-")
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	if len(name) < 3 {
+		name = "app-" + name
+	}
+	return strings.TrimRight(name, "-")
+}
+
 // generatePassword creates a secure random password using crypto/rand
 func (sp *ServiceProvisioner) generatePassword() (string, error) {
 	// Generate 32 random bytes (256 bits of entropy)
@@ -0,0 +1,57 @@
+package smart
+
+// s3Clients are the S3 client libraries of each language
+var s3Clients = []dependencyHint{
+	{"package.json", []string{`"@aws-sdk/client-s3"`, `"aws-sdk"`, `"minio"`}},
+	{"requirements.txt", []string{"boto3", "minio", "django-storages"}},
+	{"pyproject.toml", []string{"boto3", "minio", "django-storages"}},
+	{"composer.json", []string{`"league/flysystem-aws-s3-v3"`, `"aws/aws-sdk-php"`}},
+	{"Gemfile", []string{"aws-sdk-s3"}},
+	{"go.mod", []string{"github.com/aws/aws-sdk-go-v2/service/s3", "github.com/minio/minio-go"}},
+}
+
+// s3ConnectionVars are the variables an S3 client is configured with: the
+// generic S3_* ones, and the AWS_* ones SDKs read by default
+var s3ConnectionVars = map[string]string{
+	"S3_ACCESS_KEY":         "user",
+	"S3_SECRET_KEY":         "password",
+	"S3_BUCKET":             "database",
+	"S3_REGION":             "=us-east-1",
+	"AWS_ACCESS_KEY_ID":     "user",
+	"AWS_SECRET_ACCESS_KEY": "password",
+	"AWS_DEFAULT_REGION":    "=us-east-1",
+}
+
+// laravelS3Vars are what Laravel's s3 disk reads besides the AWS_* keys.
+// MinIO serves buckets under the path, not as subdomains.
+var laravelS3Vars = map[string]string{
+	"AWS_BUCKET":                  "database",
+	"AWS_ENDPOINT":                "url",
+	"AWS_USE_PATH_STYLE_ENDPOINT": "=true",
+}
+
+// detectStorage detects an S3 client, for which MinIO can stand in. Apps
+// often talk to AWS S3 or another provider, so setup asks first.
+func (sd *SmartDetector) detectStorage() *RequiredService {
+	file, library := sd.findDependency(s3Clients)
+	if file == "" {
+		return nil
+	}
+
+	service := &RequiredService{
+		Type:       "minio",
+		Version:    serviceDefaults["minio"].Version,
+		Reason:     library + " found in " + file,
+		EnvVarName: serviceDefaults["minio"].EnvVarName,
+		Prompt:     "Provision MinIO for S3-compatible storage? (No if the app uses AWS S3 or another provider)",
+	}
+	for key, component := range s3ConnectionVars {
+		service.addConnectionVar(key, component)
+	}
+	if sd.framework.Name == "Laravel" {
+		for key, component := range laravelS3Vars {
+			service.addConnectionVar(key, component)
+		}
+	}
+	return service
+}
@@ -0,0 +1,52 @@
+package smart
+
+import "testing"
+
+func TestDetectStorage(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"composer.json": `{"require": {"laravel/framework": "^11.0", "league/flysystem-aws-s3-v3": "^3.0"}}`,
+	})
+	config, err := NewSmartDetector(dir).Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var minio *RequiredService
+	for i := range config.Services {
+		if config.Services[i].Type == "minio" {
+			minio = &config.Services[i]
+		}
+	}
+	if minio == nil {
+		t.Fatalf("services = %+v, want minio", config.Services)
+	}
+	if minio.Required || minio.Prompt == "" {
+		t.Errorf("minio = %+v, want an optional service setup asks about", minio)
+	}
+	for key, want := range map[string]string{
+		"S3_ACCESS_KEY":               "user",
+		"AWS_SECRET_ACCESS_KEY":       "password",
+		"AWS_BUCKET":                  "database",
+		"AWS_USE_PATH_STYLE_ENDPOINT": "=true",
+	} {
+		if got := minio.ConnectionVars[key]; got != want {
+			t.Errorf("ConnectionVars[%s] = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestMinIOConnection(t *testing.T) {
+	ps := &ProvisionedService{ConnectionURL: "http://shop-minio:9000", User: "shop", Database: "shop"}
+	for component, want := range map[string]string{"url": "http://shop-minio:9000", "user": "shop", "=us-east-1": "us-east-1"} {
+		if got := ps.connectionPart(component); got != want {
+			t.Errorf("connectionPart(%q) = %q, want %q", component, got, want)
+		}
+	}
+
+	for appName, want := range map[string]string{"My_Shop": "my-shop", "x": "app-x", "api.v2": "api-v2"} {
+		sp := &ServiceProvisioner{appName: appName}
+		if got := sp.bucketName(); got != want {
+			t.Errorf("bucketName() for %q = %q, want %q", appName, got, want)
+		}
+	}
+}
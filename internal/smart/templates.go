@@ -0,0 +1,86 @@
+package smart
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// ConnectionTemplatesFile is where a project overrides the connection
+// variables generated for its services, relative to the project directory
+const ConnectionTemplatesFile = ".coolify-deployer/connections.json"
+
+// ConnectionTemplate changes how a service's connection reaches the app.
+// Values may use the placeholders {url}, {host}, {port}, {user},
+// {password} and {database}, filled in once the service is provisioned.
+type ConnectionTemplate struct {
+	// URL replaces the generated connection URL, like
+	// "postgresql://{user}:{password}@{host}:{port}/{database}?sslmode=require"
+	URL string `json:"url,omitempty"`
+	// NoURL doesn't set the URL variable, for apps configured with Vars only
+	NoURL bool `json:"no_url,omitempty"`
+	// Vars are more variables to set, like "DB_HOST": "{host}"
+	Vars map[string]string `json:"vars,omitempty"`
+}
+
+// templatePlaceholder matches a placeholder like {host}
+var templatePlaceholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// templateParts are the placeholders a template can use
+var templateParts = map[string]bool{"url": true, "host": true, "port": true, "user": true, "password": true, "database": true}
+
+// loadConnectionTemplates reads the project's connection templates by
+// service type. A project without the file has none.
+func (sd *SmartDetector) loadConnectionTemplates() (map[string]ConnectionTemplate, error) {
+	path := filepath.Join(sd.projectPath, ConnectionTemplatesFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ConnectionTemplatesFile, err)
+	}
+
+	var templates map[string]ConnectionTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ConnectionTemplatesFile, err)
+	}
+	for serviceType, tmpl := range templates {
+		if _, ok := serviceDefaults[serviceType]; !ok {
+			return nil, fmt.Errorf("%s: unknown service type %q", ConnectionTemplatesFile, serviceType)
+		}
+		values := []string{tmpl.URL}
+		for _, value := range tmpl.Vars {
+			values = append(values, value)
+		}
+		for _, value := range values {
+			for _, m := range templatePlaceholder.FindAllStringSubmatch(value, -1) {
+				if !templateParts[m[1]] {
+					return nil, fmt.Errorf("%s: %s has unknown placeholder {%s}", ConnectionTemplatesFile, serviceType, m[1])
+				}
+			}
+		}
+	}
+	return templates, nil
+}
+
+// expand fills in the template's placeholders from the provisioned service
+func (ps *ProvisionedService) expand(template string) string {
+	return templatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		return ps.connectionPart(placeholder[1 : len(placeholder)-1])
+	})
+}
+
+// VarNames returns the variables the template sets besides the URL
+func (t *ConnectionTemplate) VarNames() []string {
+	names := make([]string, 0, len(t.Vars))
+	for key := range t.Vars {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,65 @@
+package smart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConnectionTemplates(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		ConnectionTemplatesFile: `{
+  "postgresql": {
+    "url": "{url}?sslmode=require",
+    "vars": {"DB_HOST": "{host}", "DB_PORT": "{port}", "DB_DSN": "host={host} user={user} dbname={database}"}
+  },
+  "redis": {"no_url": true, "vars": {"REDIS_HOST": "{host}"}}
+}`,
+	})
+	templates, err := NewSmartDetector(dir).loadConnectionTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ps := &ProvisionedService{
+		ConnectionURL: "postgresql://shop:pw@shop-postgres:5432/shop",
+		Host:          "shop-postgres",
+		Port:          "5432",
+		User:          "shop",
+		Database:      "shop",
+	}
+	pg := templates["postgresql"]
+	if got, want := ps.expand(pg.URL), "postgresql://shop:pw@shop-postgres:5432/shop?sslmode=require"; got != want {
+		t.Errorf("url = %q, want %q", got, want)
+	}
+	if got, want := ps.expand(pg.Vars["DB_DSN"]), "host=shop-postgres user=shop dbname=shop"; got != want {
+		t.Errorf("DB_DSN = %q, want %q", got, want)
+	}
+	if got := strings.Join(pg.VarNames(), ","); got != "DB_DSN,DB_HOST,DB_PORT" {
+		t.Errorf("VarNames() = %q", got)
+	}
+	if !templates["redis"].NoURL {
+		t.Error("redis template lost no_url")
+	}
+}
+
+func TestConnectionTemplatesInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown service":     `{"postgres": {"url": "{url}"}}`,
+		"unknown placeholder": `{"mysql": {"vars": {"DB_NAME": "{db}"}}}`,
+		"not json":            `postgresql: {}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{ConnectionTemplatesFile: content})
+			if _, err := NewSmartDetector(dir).loadConnectionTemplates(); err == nil {
+				t.Error("invalid templates loaded without an error")
+			}
+		})
+	}
+}
+
+func TestNoConnectionTemplates(t *testing.T) {
+	templates, err := NewSmartDetector(t.TempDir()).loadConnectionTemplates()
+	if err != nil || templates != nil {
+		t.Errorf("loadConnectionTemplates() = %v, %v; want nil, nil", templates, err)
+	}
+}
@@ -0,0 +1,106 @@
+package smart
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// supportedMajors are the major versions of each engine with maintained
+// images. Versions are pinned to serviceDefaults, the newest long-term
+// release, when the project doesn't name a supported one. Engines missing
+// here take any version.
+var supportedMajors = map[string][]string{
+	"postgresql":    {"13", "14", "15", "16", "17"},
+	"timescaledb":   {"14", "15", "16", "17"}, // of PostgreSQL
+	"mysql":         {"8", "9"},
+	"mongodb":       {"6", "7", "8"},
+	"redis":         {"6", "7", "8"},
+	"clickhouse":    {"23", "24", "25"},
+	"elasticsearch": {"7", "8"},
+	"rabbitmq":      {"3", "4"},
+	"kafka":         {"3", "4"},
+}
+
+// pinVersion replaces a service version that isn't a supported one, like
+// "" or "latest", with the engine's default. A version set to something
+// unsupported gets a VersionWarning.
+func pinVersion(service *RequiredService) {
+	majors, ok := supportedMajors[service.Type]
+	if !ok {
+		return
+	}
+	defaultVersion := serviceDefaults[service.Type].Version
+	if service.Version == "" || service.Version == "latest" {
+		service.Version = defaultVersion
+		return
+	}
+
+	major := majorVersion(service.Type, service.Version)
+	for _, m := range majors {
+		if major == m {
+			return
+		}
+	}
+	service.VersionWarning = fmt.Sprintf("%s %s isn't supported (use %s); using %s", service.Type, service.Version, strings.Join(majors, ", "), defaultVersion)
+	service.Version = defaultVersion
+}
+
+// majorVersion returns the major version of an image tag: "16" for
+// "16.2-alpine", or the PostgreSQL major of a TimescaleDB tag like
+// "2.14.2-pg16". It returns "" for tags without one, like "alpine".
+func majorVersion(serviceType, tag string) string {
+	if serviceType == "timescaledb" {
+		i := strings.LastIndex(tag, "-pg")
+		if i < 0 {
+			return ""
+		}
+		tag = tag[i+len("-pg"):]
+	}
+	end := strings.IndexFunc(tag, func(r rune) bool { return !unicode.IsDigit(r) })
+	if end < 0 {
+		end = len(tag)
+	}
+	return tag[:end]
+}
+
+// imageTag returns the tag of an image reference, or "" if it has none
+func imageTag(image string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// versionMismatches warns about databases that already existed under the
+// names of the provisioned ones and run another major version than the app
+// expects, like those of an earlier setup whose data may need migrating
+func (sp *ServiceProvisioner) versionMismatches(provisioned []ProvisionedService) []string {
+	if len(provisioned) == 0 {
+		return nil
+	}
+	databases, err := sp.client.ListDatabases()
+	if err != nil {
+		return nil
+	}
+
+	var warnings []string
+	for _, ps := range provisioned {
+		if ps.IsService || ps.Version == "" {
+			continue
+		}
+		want := majorVersion(ps.Type, ps.Version)
+		for _, db := range databases {
+			if db.Name != ps.Name || db.UUID == ps.UUID {
+				continue
+			}
+			have := majorVersion(ps.Type, imageTag(db.Image))
+			if have != "" && want != "" && have != want {
+				warnings = append(warnings, fmt.Sprintf(
+					"%s already exists (%s) and runs %s, but the app expects %s: migrate its data before switching over",
+					db.Name, db.UUID, db.Image, ps.Version))
+			}
+		}
+	}
+	return warnings
+}
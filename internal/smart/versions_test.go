@@ -0,0 +1,50 @@
+package smart
+
+import "testing"
+
+func TestPinVersion(t *testing.T) {
+	tests := []struct {
+		serviceType string
+		version     string
+		want        string
+		warns       bool
+	}{
+		{"postgresql", "", "17", false},
+		{"postgresql", "latest", "17", false},
+		{"postgresql", "15", "15", false},
+		{"postgresql", "16.2-alpine", "16.2-alpine", false},
+		{"postgresql", "9.6", "17", true},
+		{"postgresql", "alpine", "17", true},
+		{"mysql", "8.0", "8.0", false},
+		{"mysql", "5.7", "8.4", true},
+		{"timescaledb", "2.14.2-pg16", "2.14.2-pg16", false},
+		{"timescaledb", "latest-pg12", "latest-pg17", true},
+		{"meilisearch", "v1.6", "v1.6", false},
+	}
+	for _, tt := range tests {
+		service := RequiredService{Type: tt.serviceType, Version: tt.version}
+		pinVersion(&service)
+		if service.Version != tt.want {
+			t.Errorf("pinVersion(%s %q) = %q, want %q", tt.serviceType, tt.version, service.Version, tt.want)
+		}
+		if (service.VersionWarning != "") != tt.warns {
+			t.Errorf("pinVersion(%s %q) warning = %q, want one: %v", tt.serviceType, tt.version, service.VersionWarning, tt.warns)
+		}
+	}
+}
+
+func TestMajorVersion(t *testing.T) {
+	for image, want := range map[string]string{
+		"postgres:15-alpine": "15",
+		"mysql:8.4":          "8",
+		"postgres":           "",
+		"registry.example.com:5000/custom/postgres": "",
+	} {
+		if got := majorVersion("postgresql", imageTag(image)); got != want {
+			t.Errorf("major version of %s = %q, want %q", image, got, want)
+		}
+	}
+	if got := majorVersion("timescaledb", "latest-pg16"); got != "16" {
+		t.Errorf("major version of latest-pg16 = %q, want 16", got)
+	}
+}
@@ -0,0 +1,219 @@
+package smart
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/entro314-labs/cool-kit/internal/detect"
+)
+
+// Worker is a background job processor the project runs beside its web
+// server. It is deployed as another application from the same repository
+// with its own start command.
+type Worker struct {
+	Name         string // appended to the application's name, like "worker"
+	Tool         string // "BullMQ", "Sidekiq", "Celery", "Laravel Horizon", "Laravel queue", "Laravel Reverb" or "Procfile"
+	StartCommand string // "" if it couldn't be found
+	Reason       string
+}
+
+// workerScripts are the package.json scripts that usually start a Node worker
+var workerScripts = []string{"worker", "start:worker", "worker:start", "queue", "jobs"}
+
+// detectWorkers detects the background workers the project defines
+func (sd *SmartDetector) detectWorkers() []Worker {
+	var workers []Worker
+	for _, fn := range []func() *Worker{
+		sd.detectBullMQWorker,
+		sd.detectSidekiqWorker,
+		sd.detectCeleryWorker,
+		sd.detectHorizonWorker,
+	} {
+		if w := fn(); w != nil {
+			workers = append(workers, *w)
+		}
+	}
+	workers = append(workers, sd.laravelWorkers()...)
+	return procfileWorkers(workers, detect.ReadProcfile(sd.projectPath))
+}
+
+// procfileWorkers adds the Procfile's processes besides web and release.
+// A process that runs a detected worker, by name or command, gives it the
+// project's own start command instead of becoming another one.
+func procfileWorkers(workers []Worker, procfile map[string]string) []Worker {
+	names := make([]string, 0, len(procfile))
+	for name := range procfile {
+		if name != "web" && name != "release" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		command := procfile[name]
+		matched := false
+		for i := range workers {
+			w := &workers[i]
+			if w.Name == name || w.StartCommand == command || (w.StartCommand == "" && len(names) == 1) {
+				w.StartCommand = command
+				w.Reason += "; started as in the Procfile"
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			workers = append(workers, Worker{Name: name, Tool: "Procfile", StartCommand: command, Reason: "Defined in the Procfile"})
+		}
+	}
+	return workers
+}
+
+func (sd *SmartDetector) detectBullMQWorker() *Worker {
+	data, err := os.ReadFile(filepath.Join(sd.projectPath, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Dependencies map[string]string `json:"dependencies"`
+		Scripts      map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+	tool := ""
+	if _, ok := pkg.Dependencies["bullmq"]; ok {
+		tool = "BullMQ"
+	} else if _, ok := pkg.Dependencies["bull"]; ok {
+		tool = "Bull"
+	} else {
+		return nil
+	}
+
+	worker := &Worker{Name: "worker", Tool: tool, Reason: tool + " found in dependencies"}
+	for _, script := range workerScripts {
+		if _, ok := pkg.Scripts[script]; ok {
+			worker.StartCommand = "npm run " + script
+			return worker
+		}
+	}
+	for _, path := range []string{"dist/worker.js", "worker.js", "src/worker.js"} {
+		if _, err := os.Stat(filepath.Join(sd.projectPath, path)); err == nil {
+			worker.StartCommand = "node " + path
+			break
+		}
+	}
+	return worker
+}
+
+func (sd *SmartDetector) detectSidekiqWorker() *Worker {
+	if !strings.Contains(sd.readFirst("Gemfile"), "sidekiq") {
+		return nil
+	}
+	command := "bundle exec sidekiq"
+	if _, err := os.Stat(filepath.Join(sd.projectPath, "config", "sidekiq.yml")); err == nil {
+		command += " -C config/sidekiq.yml"
+	}
+	return &Worker{Name: "worker", Tool: "Sidekiq", StartCommand: command, Reason: "Sidekiq found in Gemfile"}
+}
+
+func (sd *SmartDetector) detectCeleryWorker() *Worker {
+	deps := strings.ToLower(sd.readFirst("requirements.txt") + sd.readFirst("pyproject.toml") + sd.readFirst("Pipfile"))
+	if !strings.Contains(deps, "celery") {
+		return nil
+	}
+
+	worker := &Worker{Name: "worker", Tool: "Celery", Reason: "Celery found in dependencies"}
+	// Django projects define the app in <project>/celery.py
+	app := ""
+	if files, _ := filepath.Glob(filepath.Join(sd.projectPath, "*", "celery.py")); len(files) > 0 {
+		app = filepath.Base(filepath.Dir(files[0]))
+	} else {
+		for _, module := range []string{"celery_app", "worker", "tasks"} {
+			if _, err := os.Stat(filepath.Join(sd.projectPath, module+".py")); err == nil {
+				app = module
+				break
+			}
+		}
+	}
+	if app != "" {
+		worker.StartCommand = "celery -A " + app + " worker --loglevel=info"
+	}
+	return worker
+}
+
+func (sd *SmartDetector) detectHorizonWorker() *Worker {
+	if !strings.Contains(sd.readFirst("composer.json"), `"laravel/horizon"`) {
+		return nil
+	}
+	return &Worker{Name: "horizon", Tool: "Laravel Horizon", StartCommand: "php artisan horizon", Reason: "laravel/horizon found in composer.json"}
+}
+
+// workerRedis is the Redis connection each worker reads: the URL variable
+// and, for Laravel, the variables of its default redis config
+var workerRedis = map[string]RequiredService{
+	"BullMQ":          {EnvVarName: "REDIS_URL"},
+	"Bull":            {EnvVarName: "REDIS_URL"},
+	"Sidekiq":         {EnvVarName: "REDIS_URL"},
+	"Celery":          {EnvVarName: "CELERY_BROKER_URL"},
+	"Laravel Horizon": {EnvVarName: "REDIS_URL", ConnectionVars: map[string]string{"REDIS_HOST": "host", "REDIS_PORT": "port", "REDIS_PASSWORD": "password"}},
+	"Laravel queue":   {EnvVarName: "REDIS_URL", ConnectionVars: map[string]string{"REDIS_HOST": "host", "REDIS_PORT": "port", "REDIS_PASSWORD": "password"}},
+}
+
+// requireRedis makes sure services include the Redis the workers need.
+// The web application enqueues jobs too, so it stays even if a worker
+// isn't deployed.
+func requireRedis(services []RequiredService, workers []Worker) []RequiredService {
+	for _, w := range workers {
+		conn, ok := workerRedis[w.Tool]
+		if !ok {
+			// Procfile processes don't say what they connect to, and
+			// Reverb and database queues need no Redis
+			continue
+		}
+
+		redis := -1
+		for i, s := range services {
+			if s.Type == "redis" {
+				redis = i
+				break
+			}
+		}
+		if redis < 0 {
+			services = append(services, RequiredService{
+				Type:       "redis",
+				Version:    serviceDefaults["redis"].Version,
+				Reason:     "Required by the " + w.Tool + " worker",
+				EnvVarName: conn.EnvVarName,
+			})
+			redis = len(services) - 1
+		}
+
+		// Point the worker's variables at the Redis, which may have been
+		// found for caching already
+		s := &services[redis]
+		s.Required = true
+		for key, component := range conn.ConnectionVars {
+			s.addConnectionVar(key, component)
+		}
+		s.addConnectionVar(conn.EnvVarName, "url")
+	}
+	return services
+}
+
+// addConnectionVar sets another variable to part of the connection, unless
+// the service already sets it
+func (s *RequiredService) addConnectionVar(key, component string) {
+	if key == s.EnvVarName {
+		return
+	}
+	if _, ok := s.ConnectionVars[key]; ok {
+		return
+	}
+	if s.ConnectionVars == nil {
+		s.ConnectionVars = map[string]string{}
+	}
+	s.ConnectionVars[key] = component
+}
@@ -0,0 +1,143 @@
+package smart
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectWorkers(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		worker *Worker
+		redis  *RequiredService
+	}{
+		{
+			name: "bullmq with a worker script",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"express": "4", "bullmq": "5"}, "scripts": {"start": "node server.js", "worker": "node worker.js"}}`,
+			},
+			worker: &Worker{Name: "worker", Tool: "BullMQ", StartCommand: "npm run worker", Reason: "BullMQ found in dependencies"},
+			// Found by the queue detection, and the worker reads REDIS_URL
+			redis: &RequiredService{
+				Type:           "redis",
+				Version:        "7",
+				Reason:         "Queue system (BullMQ/Bull) detected",
+				EnvVarName:     "QUEUE_URL",
+				Required:       true,
+				ConnectionVars: map[string]string{"REDIS_URL": "url"},
+			},
+		},
+		{
+			name: "sidekiq",
+			files: map[string]string{
+				"Gemfile":            "gem \"rails\"\ngem \"sidekiq\"\n",
+				"config/sidekiq.yml": "",
+			},
+			worker: &Worker{Name: "worker", Tool: "Sidekiq", StartCommand: "bundle exec sidekiq -C config/sidekiq.yml", Reason: "Sidekiq found in Gemfile"},
+			redis: &RequiredService{
+				Type:       "redis",
+				Version:    "7",
+				Reason:     "Required by the Sidekiq worker",
+				EnvVarName: "REDIS_URL",
+				Required:   true,
+			},
+		},
+		{
+			name: "celery in a django project",
+			files: map[string]string{
+				"manage.py":          "",
+				"requirements.txt":   "django\ncelery[redis]\n",
+				"mysite/celery.py":   "",
+				"mysite/__init__.py": "",
+			},
+			worker: &Worker{Name: "worker", Tool: "Celery", StartCommand: "celery -A mysite worker --loglevel=info", Reason: "Celery found in dependencies"},
+			redis: &RequiredService{
+				Type:       "redis",
+				Version:    "7",
+				Reason:     "Required by the Celery worker",
+				EnvVarName: "CELERY_BROKER_URL",
+				Required:   true,
+			},
+		},
+		{
+			name: "no worker",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"express": "4"}}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := NewSmartDetector(writeFiles(t, tt.files)).Detect()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var worker *Worker
+			if len(config.Workers) > 0 {
+				worker = &config.Workers[0]
+			}
+			if !reflect.DeepEqual(worker, tt.worker) {
+				t.Errorf("worker = %+v, want %+v", worker, tt.worker)
+			}
+
+			var redis *RequiredService
+			for i, s := range config.Services {
+				if s.Type == "redis" {
+					redis = &config.Services[i]
+				}
+			}
+			if !reflect.DeepEqual(redis, tt.redis) {
+				t.Errorf("redis = %+v, want %+v", redis, tt.redis)
+			}
+		})
+	}
+}
+
+func TestRequireRedisHorizon(t *testing.T) {
+	services := requireRedis(nil, []Worker{{Name: "horizon", Tool: "Laravel Horizon"}})
+	want := []RequiredService{{
+		Type:           "redis",
+		Version:        "7",
+		Reason:         "Required by the Laravel Horizon worker",
+		EnvVarName:     "REDIS_URL",
+		Required:       true,
+		ConnectionVars: map[string]string{"REDIS_HOST": "host", "REDIS_PORT": "port", "REDIS_PASSWORD": "password"},
+	}}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("services = %+v, want %+v", services, want)
+	}
+	// The defaults must not be shared with the returned service
+	services[0].ConnectionVars["REDIS_DB"] = "database"
+	if _, ok := workerRedis["Laravel Horizon"].ConnectionVars["REDIS_DB"]; ok {
+		t.Error("requireRedis modified workerRedis")
+	}
+}
+
+func TestProcfileWorkers(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Gemfile":  "gem \"rails\"\ngem \"sidekiq\"\n",
+		"Procfile": "web: bundle exec puma -C config/puma.rb\nworker: bundle exec sidekiq -q default\nclock: bundle exec clockwork clock.rb\nrelease: bundle exec rails db:migrate\n",
+	})
+	config, err := NewSmartDetector(dir).Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Worker{
+		{Name: "worker", Tool: "Sidekiq", StartCommand: "bundle exec sidekiq -q default", Reason: "Sidekiq found in Gemfile; started as in the Procfile"},
+		{Name: "clock", Tool: "Procfile", StartCommand: "bundle exec clockwork clock.rb", Reason: "Defined in the Procfile"},
+	}
+	// Sorted by name, so clock is added after the detected worker
+	if !reflect.DeepEqual(config.Workers, want) {
+		t.Errorf("workers = %+v, want %+v", config.Workers, want)
+	}
+	if m := config.Migration; m == nil || m.Command != "bundle exec rails db:migrate" || m.Tool != "procfile" {
+		t.Errorf("migration = %+v, want the release process", m)
+	}
+	if got := config.Framework.StartCommand; got != "bundle exec puma -C config/puma.rb" {
+		t.Errorf("start command = %q, want the web process", got)
+	}
+}
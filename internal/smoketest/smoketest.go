@@ -0,0 +1,216 @@
+// Package smoketest runs the post-deploy checks configured for a project.
+package smoketest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	defaultRetries = 3
+	maxBodyRead    = 1 << 20
+)
+
+// RetryDelay is the pause between attempts of a failing check
+var RetryDelay = 3 * time.Second
+
+// Result is the outcome of one check
+type Result struct {
+	Name     string
+	Err      error
+	Attempts int
+	Duration time.Duration
+}
+
+// Passed reports whether the check succeeded
+func (r Result) Passed() bool {
+	return r.Err == nil
+}
+
+// Run executes every check against the application at appURL and returns
+// their results in order
+func Run(ctx context.Context, appURL string, checks []config.SmokeCheck) []Result {
+	results := make([]Result, 0, len(checks))
+	for i, check := range checks {
+		results = append(results, runCheck(ctx, appURL, check, i))
+	}
+	return results
+}
+
+// Failed returns the results that did not pass
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if !r.Passed() {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// Name returns the check's name, or a description of what it does
+func Name(check config.SmokeCheck) string {
+	switch {
+	case check.Name != "":
+		return check.Name
+	case check.Command != "":
+		return check.Command
+	default:
+		method := check.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		return fmt.Sprintf("%s %s", strings.ToUpper(method), check.URL)
+	}
+}
+
+func runCheck(ctx context.Context, appURL string, check config.SmokeCheck, index int) Result {
+	result := Result{Name: Name(check)}
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	timeout := defaultTimeout
+	if check.Timeout != "" {
+		d, err := time.ParseDuration(check.Timeout)
+		if err != nil {
+			result.Err = fmt.Errorf("invalid timeout %q: %w", check.Timeout, err)
+			return result
+		}
+		timeout = d
+	}
+
+	retries := check.Retries
+	if retries == 0 {
+		retries = defaultRetries
+	} else if retries < 0 {
+		retries = 0
+	}
+
+	var attempt func(context.Context) error
+	switch {
+	case check.URL != "" && check.Command != "":
+		result.Err = fmt.Errorf("check %d sets both url and command", index+1)
+		return result
+	case check.URL != "":
+		target, err := resolveURL(appURL, check.URL)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		attempt = func(ctx context.Context) error { return checkHTTP(ctx, target, check) }
+	case check.Command != "":
+		attempt = func(ctx context.Context) error { return checkCommand(ctx, appURL, check.Command) }
+	default:
+		result.Err = fmt.Errorf("check %d needs a url or a command", index+1)
+		return result
+	}
+
+	for {
+		result.Attempts++
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		result.Err = attempt(attemptCtx)
+		cancel()
+
+		if result.Err == nil || result.Attempts > retries {
+			return result
+		}
+		select {
+		case <-ctx.Done():
+			return result
+		case <-time.After(RetryDelay):
+		}
+	}
+}
+
+// resolveURL joins a path onto the application's URL; absolute URLs are used as they are
+func resolveURL(appURL, ref string) (string, error) {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return ref, nil
+	}
+	if appURL == "" {
+		return "", fmt.Errorf("application has no URL to check %s against", ref)
+	}
+	if !strings.Contains(appURL, "://") {
+		appURL = "https://" + appURL
+	}
+
+	base, err := url.Parse(appURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid application URL %q: %w", appURL, err)
+	}
+	rel, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid check URL %q: %w", ref, err)
+	}
+	if !strings.HasPrefix(rel.Path, "/") {
+		rel.Path = "/" + rel.Path
+	}
+	return base.ResolveReference(rel).String(), nil
+}
+
+func checkHTTP(ctx context.Context, target string, check config.SmokeCheck) error {
+	method := strings.ToUpper(check.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	want := check.ExpectStatus
+	if want == 0 {
+		want = http.StatusOK
+	}
+	if resp.StatusCode != want {
+		return fmt.Errorf("expected status %d, got %d", want, resp.StatusCode)
+	}
+
+	if check.ExpectBody != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyRead))
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if !strings.Contains(string(body), check.ExpectBody) {
+			return fmt.Errorf("response body does not contain %q", check.ExpectBody)
+		}
+	}
+	return nil
+}
+
+func checkCommand(ctx context.Context, appURL, command string) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), "APP_URL="+appURL)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if out := strings.TrimSpace(string(output)); out != "" {
+			return fmt.Errorf("%w: %s", err, lastLine(out))
+		}
+		return err
+	}
+	return nil
+}
+
+// lastLine returns the last line of command output, which usually holds the error
+func lastLine(s string) string {
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
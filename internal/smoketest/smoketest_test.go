@@ -0,0 +1,98 @@
+package smoketest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/entro314-labs/cool-kit/internal/config"
+)
+
+func TestRun(t *testing.T) {
+	RetryDelay = 0
+
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.Write([]byte(`{"status":"ok"}`))
+		case "/slow-start":
+			hits++
+			if hits < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte("ready"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	checks := []config.SmokeCheck{
+		{URL: "/health", ExpectBody: `"ok"`},
+		{Name: "warms up", URL: "slow-start"},
+		{URL: "/missing", Retries: -1},
+		{URL: "/missing", ExpectStatus: http.StatusNotFound},
+		{URL: srv.URL + "/health", ExpectBody: "healthy", Retries: 1},
+		{Command: `test "$APP_URL" = "` + srv.URL + `"`},
+		{Command: "echo boom; exit 1", Retries: -1},
+		{},
+	}
+
+	results := Run(context.Background(), srv.URL, checks)
+
+	want := []struct {
+		passed   bool
+		attempts int
+	}{
+		{true, 1},
+		{true, 3},
+		{false, 1},
+		{true, 1},
+		{false, 2},
+		{true, 1},
+		{false, 1},
+		{false, 0},
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Passed() != w.passed || r.Attempts != w.attempts {
+			t.Errorf("check %d (%s): passed=%v attempts=%d err=%v, want passed=%v attempts=%d",
+				i, r.Name, r.Passed(), r.Attempts, r.Err, w.passed, w.attempts)
+		}
+	}
+
+	if got := len(Failed(results)); got != 4 {
+		t.Errorf("Failed() returned %d results, want 4", got)
+	}
+	if results[1].Name != "warms up" {
+		t.Errorf("name = %q, want %q", results[1].Name, "warms up")
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	tests := []struct {
+		app, ref, want string
+	}{
+		{"https://app.example.com", "/health", "https://app.example.com/health"},
+		{"https://app.example.com/", "health", "https://app.example.com/health"},
+		{"app.example.com", "/api?x=1", "https://app.example.com/api?x=1"},
+		{"https://app.example.com", "http://other.example.com/up", "http://other.example.com/up"},
+	}
+	for _, tt := range tests {
+		got, err := resolveURL(tt.app, tt.ref)
+		if err != nil {
+			t.Errorf("resolveURL(%q, %q): %v", tt.app, tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveURL(%q, %q) = %q, want %q", tt.app, tt.ref, got, tt.want)
+		}
+	}
+
+	if _, err := resolveURL("", "/health"); err == nil {
+		t.Error("resolveURL without an application URL should fail")
+	}
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// accessible prints plain lines in order for screen readers: no spinners,
+// redrawn lines or full-screen views, and no colors, emoji or box drawing
+var accessible bool
+
+// SetAccessible turns accessible mode on or off
+func SetAccessible(v bool) {
+	accessible = v
+	if v {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// Accessible reports whether output is plain lines for screen readers
+func Accessible() bool {
+	return accessible
+}
+
+// LiveOutput reports whether output can be redrawn in place, like spinners,
+// progress bars and full-screen views
+func LiveOutput() bool {
+	return Interactive() && !accessible
+}
+
+// needsScreen is needsUser for a full-screen view, which accessible mode
+// turns off too
+func needsScreen(what, hint string) error {
+	if accessible {
+		return fmt.Errorf("%s is a full-screen view, which accessible mode turns off: %s", what, hint)
+	}
+	return needsUser(what, hint)
+}
+
+// iconWords spells out the symbols that screen readers read badly
+var iconWords = strings.NewReplacer(
+	IconArrow, "-",
+	IconDot, "-",
+	"…", "...",
+)
+
+// plainText strips emoji and box drawing from s in accessible mode, with the
+// spaces after them, so "✓ Done" reads "Done"
+func plainText(s string) string {
+	if !accessible {
+		return s
+	}
+	var b strings.Builder
+	skipSpace := false
+	for _, r := range iconWords.Replace(s) {
+		switch {
+		case unicode.Is(unicode.So, r), r == '\u200d', unicode.Is(unicode.Variation_Selector, r):
+			skipSpace = true
+			continue
+		case skipSpace && r == ' ':
+			continue
+		}
+		skipSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
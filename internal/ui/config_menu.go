@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/entro314-labs/cool-kit/internal/config"
 )
@@ -232,10 +233,16 @@ func (m ConfigModel) View() string {
 
 // RunConfigMenu runs the config menu and returns the selected key to edit
 func RunConfigMenu() (string, error) {
+	if err := needsUser("Config menu", "use 'config get' and 'config set'"); err != nil {
+		return "", err
+	}
 	model, err := NewConfigModel()
 	if err != nil {
 		return "", err
 	}
+	if accessible {
+		return selectConfigKey(model)
+	}
 
 	p := tea.NewProgram(model)
 	finalModel, err := p.Run()
@@ -249,3 +256,19 @@ func RunConfigMenu() (string, error) {
 	}
 	return "", nil
 }
+
+// selectConfigKey asks for the key of m to edit with a plain select, for
+// accessible mode. It returns no key when done.
+func selectConfigKey(m ConfigModel) (string, error) {
+	opts := make([]huh.Option[string], 0, len(m.keys)+1)
+	for _, key := range m.keys {
+		opts = append(opts, huh.NewOption(fmt.Sprintf("%s: %v", key, m.values[key]), key))
+	}
+	opts = append(opts, huh.NewOption("Done", ""))
+	var key string
+	err := runField(huh.NewSelect[string]().
+		Title("Configuration").
+		Options(opts...).
+		Value(&key))
+	return key, err
+}
@@ -0,0 +1,455 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ResourceKind is a kind of Coolify resource shown by the dashboard
+type ResourceKind int
+
+const (
+	KindApplication ResourceKind = iota
+	KindDatabase
+	KindService
+)
+
+// dashboardTabs are the dashboard's tabs, in order
+var dashboardTabs = []struct {
+	kind  ResourceKind
+	title string
+}{
+	{KindApplication, "Applications"},
+	{KindDatabase, "Databases"},
+	{KindService, "Services"},
+}
+
+// Resource is a row of the dashboard
+type Resource struct {
+	Kind   ResourceKind
+	UUID   string
+	Name   string
+	Status string // as reported by Coolify, like running:healthy
+	Detail string // the domain, database engine or service type
+	URL    string // opened in the browser, if any
+}
+
+// DashboardBackend loads and acts on the resources of a Coolify instance.
+// The actions return a message to show when they succeed.
+type DashboardBackend interface {
+	Resources() ([]Resource, error)
+	Deploy(r Resource) (string, error)
+	Restart(r Resource) (string, error)
+	Stop(r Resource) (string, error)
+	Logs(r Resource) (string, error)
+	Open(r Resource) error
+}
+
+// DashboardModel is a live view of the resources of a Coolify instance
+type DashboardModel struct {
+	backend  DashboardBackend
+	title    string
+	interval time.Duration
+
+	resources []Resource
+	tab       int
+	cursor    []int // per tab
+	loading   bool
+	updated   time.Time
+	err       error
+	status    string
+
+	// confirm is the action waiting for y, if any
+	confirm *dashboardAction
+
+	// logs is shown instead of the list while open
+	logs *LogViewerModel
+
+	width  int
+	height int
+}
+
+// dashboardAction is an action on the selected resource
+type dashboardAction struct {
+	verb   string // shown while it runs, like Stopping
+	prompt string // asked before it runs, like Stop
+	run    func(Resource) (string, error)
+	of     Resource
+}
+
+type resourcesMsg struct {
+	resources []Resource
+	err       error
+}
+
+type dashboardTickMsg time.Time
+
+type actionDoneMsg struct {
+	status string
+	err    error
+}
+
+var (
+	dashboardTabStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#888888")).
+				Padding(0, 1)
+
+	dashboardActiveTabStyle = lipgloss.NewStyle().
+				Foreground(brandAccent).
+				Bold(true).
+				Underline(true).
+				Padding(0, 1)
+
+	dashboardHeaderStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#666666")).
+				Bold(true)
+
+	statusRunningStyle = lipgloss.NewStyle().Foreground(brandAccent)
+	statusStoppedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+	statusOtherStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD75F"))
+)
+
+// NewDashboardModel returns a dashboard for backend, refreshed every interval
+func NewDashboardModel(backend DashboardBackend, title string, interval time.Duration) DashboardModel {
+	return DashboardModel{
+		backend:  backend,
+		title:    title,
+		interval: interval,
+		cursor:   make([]int, len(dashboardTabs)),
+		loading:  true,
+	}
+}
+
+func (m DashboardModel) Init() tea.Cmd {
+	return tea.Batch(m.load(), m.tick())
+}
+
+// load fetches the resources in the background
+func (m DashboardModel) load() tea.Cmd {
+	return func() tea.Msg {
+		resources, err := m.backend.Resources()
+		return resourcesMsg{resources: resources, err: err}
+	}
+}
+
+// tick schedules the next refresh
+func (m DashboardModel) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(t time.Time) tea.Msg {
+		return dashboardTickMsg(t)
+	})
+}
+
+func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		if m.logs != nil {
+			m.logs.SetSize(m.logsSize())
+		}
+		return m, nil
+
+	case resourcesMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.resources = msg.resources
+			m.updated = time.Now()
+			m.clampCursor()
+		}
+		return m, nil
+
+	case dashboardTickMsg:
+		if m.loading {
+			return m, m.tick()
+		}
+		m.loading = true
+		return m, tea.Batch(m.load(), m.tick())
+
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.status = ""
+		} else {
+			m.err = nil
+			m.status = msg.status
+		}
+		if m.loading {
+			return m, nil
+		}
+		m.loading = true
+		return m, m.load()
+
+	case logViewerMsg, logViewerTickMsg:
+		if m.logs == nil {
+			return m, nil
+		}
+		logs, cmd := m.logs.Update(msg)
+		m.logs = &logs
+		return m, cmd
+
+	case tea.KeyMsg:
+		if m.logs != nil {
+			return m.updateLogs(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+// updateLogs handles keys while the logs are open
+func (m DashboardModel) updateLogs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.logs.Editing() {
+		switch msg.String() {
+		case "esc", "q", "l":
+			m.logs = nil
+			return m, nil
+		}
+	}
+	logs, cmd := m.logs.Update(msg)
+	m.logs = &logs
+	return m, cmd
+}
+
+// updateList handles keys on the resource list
+func (m DashboardModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	if m.confirm != nil {
+		action := m.confirm
+		m.confirm = nil
+		if key != "y" && key != "Y" {
+			m.status = "Cancelled"
+			return m, nil
+		}
+		m.status = fmt.Sprintf("%s %s...", action.verb, action.of.Name)
+		return m, runAction(action)
+	}
+
+	rows := m.rows()
+	switch key {
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor[m.tab] > 0 {
+			m.cursor[m.tab]--
+		}
+	case "down", "j":
+		if m.cursor[m.tab] < len(rows)-1 {
+			m.cursor[m.tab]++
+		}
+	case "home", "g":
+		m.cursor[m.tab] = 0
+	case "end", "G":
+		m.cursor[m.tab] = max(len(rows)-1, 0)
+	case "tab", "right":
+		m.tab = (m.tab + 1) % len(dashboardTabs)
+	case "shift+tab", "left":
+		m.tab = (m.tab + len(dashboardTabs) - 1) % len(dashboardTabs)
+	case "1", "2", "3":
+		m.tab = int(key[0] - '1')
+	case "ctrl+r", "f5":
+		if !m.loading {
+			m.loading = true
+			return m, m.load()
+		}
+	}
+
+	selected, ok := m.selected()
+	if !ok {
+		return m, nil
+	}
+	switch key {
+	case "d":
+		m.status = fmt.Sprintf("Deploying %s...", selected.Name)
+		return m, runAction(&dashboardAction{verb: "Deploying", run: m.backend.Deploy, of: selected})
+	case "r":
+		m.confirm = &dashboardAction{verb: "Restarting", prompt: "Restart", run: m.backend.Restart, of: selected}
+	case "s":
+		m.confirm = &dashboardAction{verb: "Stopping", prompt: "Stop", run: m.backend.Stop, of: selected}
+	case "l", "enter":
+		backend := m.backend
+		logs := NewLogViewerModel("Logs of "+selected.Name, func() (string, bool, error) {
+			logs, err := backend.Logs(selected)
+			return logs, false, err
+		}, m.interval)
+		logs.SetSize(m.logsSize())
+		m.logs = &logs
+		m.status = ""
+		return m, logs.Init()
+	case "o":
+		if err := m.backend.Open(selected); err != nil {
+			m.err = err
+		} else {
+			m.err = nil
+			m.status = fmt.Sprintf("Opened %s", selected.Name)
+		}
+	}
+	return m, nil
+}
+
+// runAction runs an action in the background
+func runAction(action *dashboardAction) tea.Cmd {
+	return func() tea.Msg {
+		status, err := action.run(action.of)
+		if err != nil {
+			return actionDoneMsg{err: fmt.Errorf("%s %s failed: %w", strings.ToLower(action.verb), action.of.Name, err)}
+		}
+		return actionDoneMsg{status: status}
+	}
+}
+
+// rows returns the resources of the current tab
+func (m DashboardModel) rows() []Resource {
+	var rows []Resource
+	for _, r := range m.resources {
+		if r.Kind == dashboardTabs[m.tab].kind {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// selected returns the resource under the cursor
+func (m DashboardModel) selected() (Resource, bool) {
+	rows := m.rows()
+	if len(rows) == 0 {
+		return Resource{}, false
+	}
+	return rows[m.cursor[m.tab]], true
+}
+
+// clampCursor keeps the cursors on a row after the resources change
+func (m *DashboardModel) clampCursor() {
+	saved := m.tab
+	for i := range dashboardTabs {
+		m.tab = i
+		if n := len(m.rows()); m.cursor[i] >= n {
+			m.cursor[i] = max(n-1, 0)
+		}
+	}
+	m.tab = saved
+}
+
+// logsSize returns the size of the logs view
+func (m DashboardModel) logsSize() (int, int) {
+	width, height := m.width, m.height-2
+	if width <= 0 {
+		width = 80
+	}
+	if height <= 0 {
+		height = 20
+	}
+	return width, height
+}
+
+func (m DashboardModel) View() string {
+	var s strings.Builder
+
+	header := logoStyle.Render("🧊 COOL KIT") + "  " + taglineStyle.Render(m.title)
+	if !m.updated.IsZero() {
+		header += "  " + footerStyle.Render("updated "+m.updated.Format("15:04:05"))
+	}
+	if m.loading {
+		header += footerStyle.Render(" ↻")
+	}
+	s.WriteString(header + "\n\n")
+
+	if m.logs != nil {
+		s.WriteString(m.logs.View())
+		return s.String()
+	}
+
+	// Tabs
+	var tabs []string
+	for i, tab := range dashboardTabs {
+		count := 0
+		for _, r := range m.resources {
+			if r.Kind == tab.kind {
+				count++
+			}
+		}
+		label := fmt.Sprintf("%d %s (%d)", i+1, tab.title, count)
+		if i == m.tab {
+			tabs = append(tabs, dashboardActiveTabStyle.Render(label))
+		} else {
+			tabs = append(tabs, dashboardTabStyle.Render(label))
+		}
+	}
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...) + "\n\n")
+
+	// Resources
+	rows := m.rows()
+	nameWidth := 24
+	for _, r := range rows {
+		nameWidth = max(nameWidth, lipgloss.Width(r.Name)+2)
+	}
+	const statusWidth = 20
+	s.WriteString(dashboardHeaderStyle.Render(fmt.Sprintf("  %-*s%-*s%s", nameWidth, "NAME", statusWidth, "STATUS", "DETAIL")) + "\n")
+	if len(rows) == 0 && !m.loading {
+		s.WriteString(descTextStyle.Render("  No "+strings.ToLower(dashboardTabs[m.tab].title)) + "\n")
+	}
+	for i, r := range rows {
+		name := fmt.Sprintf("%-*s", nameWidth, r.Name)
+		status := resourceStatusStyle(r.Status).Render(fmt.Sprintf("%-*s", statusWidth, r.Status))
+		if i == m.cursor[m.tab] {
+			s.WriteString(selectedItemStyle.Render("▸ "+name) + status + r.Detail + "\n")
+		} else {
+			s.WriteString(menuItemStyle.Render("  "+name) + status + menuItemStyle.Render(r.Detail) + "\n")
+		}
+	}
+	s.WriteString("\n")
+
+	// Status line
+	switch {
+	case m.confirm != nil:
+		s.WriteString(WarningStyle.Render(fmt.Sprintf("%s %s? (y/N)", m.confirm.prompt, m.confirm.of.Name)))
+	case m.err != nil:
+		s.WriteString(ErrorStyle.Render(m.err.Error()))
+	case m.status != "":
+		s.WriteString(descTextStyle.Render(m.status))
+	}
+	s.WriteString("\n\n")
+
+	s.WriteString(m.footer(
+		footerKeyStyle.Render("↑↓")+" select",
+		footerKeyStyle.Render("tab")+" kind",
+		footerKeyStyle.Render("d")+" deploy",
+		footerKeyStyle.Render("r")+" restart",
+		footerKeyStyle.Render("s")+" stop",
+		footerKeyStyle.Render("l")+" logs",
+		footerKeyStyle.Render("o")+" open",
+		footerKeyStyle.Render("ctrl+r")+" refresh",
+		footerKeyStyle.Render("q")+" quit",
+	))
+	return s.String()
+}
+
+func (m DashboardModel) footer(parts ...string) string {
+	return footerStyle.Render(strings.Join(parts, footerSepStyle.Render(" │ ")))
+}
+
+// resourceStatusStyle colors a Coolify status by its state
+func resourceStatusStyle(status string) lipgloss.Style {
+	state, _, _ := strings.Cut(status, ":")
+	switch {
+	case strings.HasPrefix(state, "running"):
+		return statusRunningStyle
+	case state == "exited", state == "stopped", strings.Contains(status, "unhealthy"):
+		return statusStoppedStyle
+	}
+	return statusOtherStyle
+}
+
+// RunDashboard runs the dashboard until it's quit
+func RunDashboard(backend DashboardBackend, title string, interval time.Duration) error {
+	if err := needsScreen("Dashboard", "use 'ls' or 'apps' for the status of resources"); err != nil {
+		return err
+	}
+	p := tea.NewProgram(NewDashboardModel(backend, title, interval), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
@@ -0,0 +1,551 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/entro314-labs/cool-kit/internal/dotenv"
+)
+
+// maskedValue stands in for a value that isn't revealed
+const maskedValue = "••••••••"
+
+// EnvVar is a variable in the env editor
+type EnvVar struct {
+	UUID      string
+	Key       string
+	Value     string
+	BuildTime bool
+	Preview   bool
+	Literal   bool
+}
+
+// EnvEdit is a change made in the env editor. Old is nil for a variable
+// that was added and New is nil for one that was deleted.
+type EnvEdit struct {
+	Old *EnvVar
+	New *EnvVar
+}
+
+// Key returns the key of the variable the edit changes
+func (e EnvEdit) Key() string {
+	if e.New != nil {
+		return e.New.Key
+	}
+	return e.Old.Key
+}
+
+// Describe says what the edit changes, without the values
+func (e EnvEdit) Describe() string {
+	switch {
+	case e.Old == nil:
+		return fmt.Sprintf("+ %s (%s)", e.New.Key, strings.Join(envFlags(*e.New), ", "))
+	case e.New == nil:
+		return fmt.Sprintf("- %s (%s)", e.Old.Key, envTarget(e.Old.Preview))
+	}
+
+	var changes []string
+	if e.Old.Value != e.New.Value {
+		changes = append(changes, "value")
+	}
+	if e.Old.Preview != e.New.Preview {
+		changes = append(changes, fmt.Sprintf("%s → %s", envTarget(e.Old.Preview), envTarget(e.New.Preview)))
+	}
+	if e.Old.BuildTime != e.New.BuildTime {
+		changes = append(changes, "build-time "+onOff(e.New.BuildTime))
+	}
+	if e.Old.Literal != e.New.Literal {
+		changes = append(changes, "literal "+onOff(e.New.Literal))
+	}
+	return fmt.Sprintf("~ %s: %s", e.New.Key, strings.Join(changes, ", "))
+}
+
+func envTarget(preview bool) string {
+	if preview {
+		return "preview"
+	}
+	return "production"
+}
+
+func envFlags(v EnvVar) []string {
+	flags := []string{envTarget(v.Preview)}
+	if v.BuildTime {
+		flags = append(flags, "build-time")
+	}
+	if v.Literal {
+		flags = append(flags, "literal")
+	}
+	if strings.Contains(v.Value, "\n") {
+		flags = append(flags, "multi-line")
+	}
+	return flags
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// EditEnvVars opens the env editor on vars. It returns the edits once they
+// are reviewed and confirmed, or none when the editor is left without saving.
+func EditEnvVars(title string, vars []EnvVar) ([]EnvEdit, error) {
+	if err := needsScreen("Edit environment variables", "use env add, env rm or env push"); err != nil {
+		return nil, err
+	}
+
+	result, err := tea.NewProgram(newEnvEditorModel(title, vars), tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, err
+	}
+	m := result.(envEditorModel)
+	if !m.saved {
+		return nil, nil
+	}
+	return m.edits(), nil
+}
+
+type envEditorMode int
+
+const (
+	envModeTable  envEditorMode = iota
+	envModeKey                  // typing the key of a new variable
+	envModeValue                // editing a value
+	envModeReview               // the changes, before they are saved
+)
+
+// envRow is a variable as loaded and as edited
+type envRow struct {
+	orig     *EnvVar // nil for a new variable
+	cur      EnvVar
+	deleted  bool
+	revealed bool
+}
+
+func (r *envRow) changed() bool {
+	switch {
+	case r.orig == nil:
+		return !r.deleted
+	case r.deleted:
+		return true
+	}
+	return r.cur != *r.orig
+}
+
+type envEditorModel struct {
+	title  string
+	rows   []envRow
+	cursor int
+	offset int
+	mode   envEditorMode
+
+	key    textinput.Model
+	value  textarea.Model
+	status string
+
+	confirmQuit bool
+	saved       bool
+
+	width  int
+	height int
+}
+
+func newEnvEditorModel(title string, vars []EnvVar) envEditorModel {
+	rows := make([]envRow, len(vars))
+	for i := range vars {
+		orig := vars[i]
+		rows[i] = envRow{orig: &orig, cur: vars[i]}
+	}
+
+	key := textinput.New()
+	key.Prompt = "Key: "
+	key.CharLimit = 256
+
+	value := textarea.New()
+	value.ShowLineNumbers = false
+	value.MaxHeight = 0
+	value.Prompt = "│ "
+
+	return envEditorModel{
+		title:  title,
+		rows:   rows,
+		key:    key,
+		value:  value,
+		width:  80,
+		height: 24,
+	}
+}
+
+func (m envEditorModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m envEditorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.value.SetWidth(max(20, msg.Width-4))
+		m.value.SetHeight(max(3, msg.Height-8))
+		m.scroll()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case envModeKey:
+			return m.updateKey(msg)
+		case envModeValue:
+			return m.updateValue(msg)
+		case envModeReview:
+			return m.updateReview(msg)
+		}
+		return m.updateTable(msg)
+	}
+	return m, nil
+}
+
+func (m envEditorModel) updateTable(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := msg.String()
+	if k != "q" && k != "esc" {
+		m.confirmQuit = false
+	}
+	m.status = ""
+
+	switch k {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "q", "esc":
+		if m.changes() > 0 && !m.confirmQuit {
+			m.confirmQuit = true
+			m.status = "Unsaved changes: press q again to discard them, or s to review and save"
+			return m, nil
+		}
+		return m, tea.Quit
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-m.visibleRows())
+	case "pgdown":
+		m.move(m.visibleRows())
+	case "home", "g":
+		m.move(-len(m.rows))
+	case "end", "G":
+		m.move(len(m.rows))
+	case "a":
+		m.key.SetValue("")
+		m.key.Focus()
+		m.mode = envModeKey
+		return m, textinput.Blink
+	case "V":
+		reveal := !m.allRevealed()
+		for i := range m.rows {
+			m.rows[i].revealed = reveal
+		}
+	case "s":
+		if m.changes() == 0 {
+			m.status = "No changes to save"
+			return m, nil
+		}
+		m.mode = envModeReview
+	}
+
+	row := m.current()
+	if row == nil {
+		return m, nil
+	}
+	switch k {
+	case "enter", "e":
+		if row.deleted {
+			m.status = "Restore the variable with d before editing it"
+			return m, nil
+		}
+		m.value.SetValue(row.cur.Value)
+		m.value.Focus()
+		m.mode = envModeValue
+		return m, textarea.Blink
+	case "v", " ":
+		row.revealed = !row.revealed
+	case "d", "delete":
+		row.deleted = !row.deleted
+	case "b":
+		row.cur.BuildTime = !row.cur.BuildTime
+	case "l":
+		row.cur.Literal = !row.cur.Literal
+	case "p":
+		if m.find(row.cur.Key, !row.cur.Preview) >= 0 {
+			m.status = fmt.Sprintf("%s already has a %s variable", row.cur.Key, envTarget(!row.cur.Preview))
+			return m, nil
+		}
+		row.cur.Preview = !row.cur.Preview
+	case "u":
+		if row.orig == nil {
+			m.rows = append(m.rows[:m.cursor], m.rows[m.cursor+1:]...)
+			m.move(0)
+		} else {
+			*row = envRow{orig: row.orig, cur: *row.orig}
+		}
+	}
+	return m, nil
+}
+
+func (m envEditorModel) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.key.Blur()
+		m.mode = envModeTable
+		return m, nil
+	case "enter":
+		key := strings.TrimSpace(m.key.Value())
+		preview := false
+		if row := m.current(); row != nil {
+			// New variables go with the one they're added next to
+			preview = row.cur.Preview
+		}
+		switch {
+		case !dotenv.ValidKey(key):
+			m.status = fmt.Sprintf("%q isn't a valid key: use letters, digits and underscores", key)
+			return m, nil
+		case m.find(key, preview) >= 0:
+			m.status = fmt.Sprintf("%s already exists: edit it instead", key)
+			return m, nil
+		}
+		m.status = ""
+		m.key.Blur()
+		m.rows = append(m.rows, envRow{cur: EnvVar{Key: key, Preview: preview}, revealed: true})
+		m.cursor = len(m.rows) - 1
+		m.scroll()
+		m.value.SetValue("")
+		m.value.Focus()
+		m.mode = envModeValue
+		return m, textarea.Blink
+	}
+	var cmd tea.Cmd
+	m.key, cmd = m.key.Update(msg)
+	return m, cmd
+}
+
+func (m envEditorModel) updateValue(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.value.Blur()
+		m.mode = envModeTable
+		return m, nil
+	case "ctrl+s":
+		if row := m.current(); row != nil {
+			row.cur.Value = m.value.Value()
+		}
+		m.value.Blur()
+		m.mode = envModeTable
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.value, cmd = m.value.Update(msg)
+	return m, cmd
+}
+
+func (m envEditorModel) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "y", "enter":
+		m.saved = true
+		return m, tea.Quit
+	case "n", "esc", "q":
+		m.mode = envModeTable
+	}
+	return m, nil
+}
+
+func (m *envEditorModel) current() *envRow {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return &m.rows[m.cursor]
+}
+
+// find returns the index of the row with key for preview or production
+func (m *envEditorModel) find(key string, preview bool) int {
+	for i := range m.rows {
+		if m.rows[i].cur.Key == key && m.rows[i].cur.Preview == preview && !(m.rows[i].orig == nil && m.rows[i].deleted) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *envEditorModel) move(n int) {
+	m.cursor = max(0, min(m.cursor+n, len(m.rows)-1))
+	m.scroll()
+}
+
+// visibleRows is how many variables fit between the header and the help
+func (m *envEditorModel) visibleRows() int {
+	return max(1, m.height-8)
+}
+
+func (m *envEditorModel) scroll() {
+	rows := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+	m.offset = max(0, m.offset)
+}
+
+func (m *envEditorModel) allRevealed() bool {
+	for i := range m.rows {
+		if !m.rows[i].revealed {
+			return false
+		}
+	}
+	return len(m.rows) > 0
+}
+
+func (m *envEditorModel) changes() int {
+	n := 0
+	for i := range m.rows {
+		if m.rows[i].changed() {
+			n++
+		}
+	}
+	return n
+}
+
+// edits returns the changes, in the order of the rows
+func (m *envEditorModel) edits() []EnvEdit {
+	var edits []EnvEdit
+	for i := range m.rows {
+		r := &m.rows[i]
+		if !r.changed() {
+			continue
+		}
+		edit := EnvEdit{Old: r.orig}
+		if !r.deleted {
+			cur := r.cur
+			edit.New = &cur
+		}
+		edits = append(edits, edit)
+	}
+	return edits
+}
+
+func (m envEditorModel) View() string {
+	var b strings.Builder
+	b.WriteString(BoldStyle.Render(m.title) + "\n\n")
+
+	switch m.mode {
+	case envModeValue:
+		if row := m.current(); row != nil {
+			b.WriteString(fmt.Sprintf("%s %s\n\n", DimStyle.Render("Editing"), BoldStyle.Render(row.cur.Key)))
+		}
+		b.WriteString(m.value.View() + "\n\n")
+		b.WriteString(DimStyle.Render("enter new line · ctrl+s done · esc cancel"))
+		return b.String()
+
+	case envModeReview:
+		edits := m.edits()
+		b.WriteString(fmt.Sprintf("%d changes to save. Values aren't shown.\n\n", len(edits)))
+		for _, e := range edits {
+			line := "  " + e.Describe()
+			switch {
+			case e.Old == nil:
+				line = SuccessStyle.Render(line)
+			case e.New == nil:
+				line = ErrorStyle.Render(line)
+			default:
+				line = WarningStyle.Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n" + DimStyle.Render("y save · n keep editing"))
+		return b.String()
+	}
+
+	b.WriteString(m.renderTable())
+	if m.mode == envModeKey {
+		b.WriteString("\n" + m.key.View() + "\n")
+	}
+	if m.status != "" {
+		b.WriteString("\n" + WarningStyle.Render(m.status) + "\n")
+	}
+	b.WriteString("\n" + DimStyle.Render(fmt.Sprintf("%d changes · enter edit · a add · d delete · u undo · v reveal · b build-time · p preview · l literal · s save · q quit", m.changes())))
+	return b.String()
+}
+
+// renderTable shows the variables, their masked values and flags
+func (m envEditorModel) renderTable() string {
+	if len(m.rows) == 0 {
+		return DimStyle.Render("  No variables yet: press a to add one") + "\n"
+	}
+
+	keyWidth := len("KEY")
+	for i := range m.rows {
+		keyWidth = max(keyWidth, ansi.StringWidth(m.rows[i].cur.Key))
+	}
+	keyWidth = min(keyWidth, 40)
+	const flagsWidth = 24
+	valueWidth := max(10, m.width-keyWidth-flagsWidth-10)
+
+	var b strings.Builder
+	header := fmt.Sprintf("    %-*s  %-*s  %s", keyWidth, "KEY", valueWidth, "VALUE", "FLAGS")
+	b.WriteString(BoldStyle.Render(header) + "\n")
+
+	end := min(m.offset+m.visibleRows(), len(m.rows))
+	for i := m.offset; i < end; i++ {
+		r := &m.rows[i]
+		mark, style := " ", lipgloss.NewStyle()
+		switch {
+		case r.deleted:
+			mark, style = "-", ErrorStyle
+		case r.orig == nil:
+			mark, style = "+", SuccessStyle
+		case r.changed():
+			mark, style = "~", WarningStyle
+		}
+
+		cursor := "  "
+		if i == m.cursor {
+			cursor = InfoStyle.Render(IconArrow + " ")
+		}
+		key := ansi.Truncate(r.cur.Key, keyWidth, "…")
+		value := ansi.Truncate(displayValue(r), valueWidth, "…")
+		flags := strings.Join(envFlags(r.cur), " ")
+		line := fmt.Sprintf("%s %s  %s  %s", mark, padRight(key, keyWidth), padRight(value, valueWidth), DimStyle.Render(flags))
+		b.WriteString(cursor + style.Render(line) + "\n")
+	}
+	if len(m.rows) > end-m.offset {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("    %d-%d of %d", m.offset+1, end, len(m.rows))) + "\n")
+	}
+	return b.String()
+}
+
+// padRight pads s with spaces to width cells
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-ansi.StringWidth(s)))
+}
+
+// displayValue is the value as the table shows it: masked unless revealed,
+// and a multi-line value on one line with its line count
+func displayValue(r *envRow) string {
+	lines := strings.Count(r.cur.Value, "\n") + 1
+	value := maskedValue
+	if r.revealed {
+		value, _, _ = strings.Cut(r.cur.Value, "\n")
+		if r.cur.Value == "" {
+			value = "(empty)"
+		}
+	}
+	if lines > 1 {
+		value += fmt.Sprintf(" (%d lines)", lines)
+	}
+	return value
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// nonInteractive turns prompts into errors and full-screen views into
+	// plain output, for CI
+	nonInteractive bool
+
+	// assumeYes answers yes to confirmations
+	assumeYes bool
+)
+
+// SetNonInteractive turns prompts and full-screen views off or on
+func SetNonInteractive(v bool) {
+	nonInteractive = v
+}
+
+// SetAssumeYes makes confirmations pass without asking
+func SetAssumeYes(v bool) {
+	assumeYes = v
+}
+
+// Interactive reports whether prompts and full-screen views can be used
+func Interactive() bool {
+	return !nonInteractive
+}
+
+// AssumeYes reports whether confirmations pass without asking
+func AssumeYes() bool {
+	return assumeYes
+}
+
+// NonInteractiveError is returned in non-interactive mode by a prompt or
+// view that would have needed the user
+type NonInteractiveError struct {
+	What string // the prompt, or the view
+	Hint string // how to give the answer instead
+}
+
+func (e *NonInteractiveError) Error() string {
+	what := strings.TrimSuffix(strings.TrimSpace(e.What), ":")
+	hint := e.Hint
+	if hint == "" {
+		hint = "pass it with a flag or environment variable"
+	}
+	return fmt.Sprintf("%q needs an answer, but prompts are disabled in non-interactive mode: %s", what, hint)
+}
+
+// needsUser returns the error of a prompt or view in non-interactive mode,
+// or nil when it can run
+func needsUser(what, hint string) error {
+	if Interactive() {
+		return nil
+	}
+	return &NonInteractiveError{What: what, Hint: hint}
+}
+
+// answered logs the answer a prompt got without asking, so CI logs show it
+func answered(prompt, answer string) {
+	Dim(fmt.Sprintf("%s %s", strings.TrimSpace(prompt), answer))
+}
@@ -0,0 +1,554 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/entro314-labs/cool-kit/internal/logview"
+)
+
+// LogSource returns the logs to show. done reports that they won't change any
+// more, like the logs of a finished deployment, which stops polling.
+type LogSource func() (logs string, done bool, err error)
+
+// viewerInput is what the input line of the log viewer is reading
+type viewerInput int
+
+const (
+	inputNone viewerInput = iota
+	inputSearch
+	inputJump
+)
+
+// viewerLevels are the minimum levels the e key cycles through
+var viewerLevels = []logview.Level{logview.LevelNone, logview.LevelInfo, logview.LevelWarn, logview.LevelError}
+
+// logViewerIDs tells apart the messages of log viewers, so a closed viewer's
+// poll doesn't reach the next one
+var logViewerIDs atomic.Int64
+
+type logViewerMsg struct {
+	id   int64
+	logs string
+	done bool
+	err  error
+}
+
+type logViewerTickMsg struct {
+	id int64
+}
+
+var (
+	logMatchStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("#FFD75F")).
+			Foreground(lipgloss.Color("#000000"))
+
+	logCurrentMatchStyle = lipgloss.NewStyle().
+				Background(brandAccent).
+				Foreground(lipgloss.Color("#000000")).
+				Bold(true)
+)
+
+// LogViewerModel is a scrollable, searchable view of logs that follows new
+// lines as they come in
+type LogViewerModel struct {
+	id       int64
+	title    string
+	source   LogSource
+	interval time.Duration
+	quitKeys bool // q and esc quit, when it isn't part of another model
+
+	lines   []string
+	levels  []logview.Level
+	done    bool
+	loading bool
+	err     error
+
+	viewport viewport.Model
+	follow   bool
+
+	pattern     *regexp.Regexp
+	onlyMatches bool
+	minLevel    int // index into viewerLevels
+
+	shown   []int // indexes of the lines shown
+	matches []int // indexes into shown of the lines pattern matches
+	current int   // index into shown of the current match, or -1
+
+	input     textinput.Model
+	inputMode viewerInput
+	status    string
+
+	width  int
+	height int
+}
+
+// NewLogViewerModel returns a log viewer that polls source every interval
+// until its logs are done. An interval of 0 loads them once.
+func NewLogViewerModel(title string, source LogSource, interval time.Duration) LogViewerModel {
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 256
+
+	vp := viewport.New(80, 20)
+	vp.SetHorizontalStep(8)
+
+	return LogViewerModel{
+		id:       logViewerIDs.Add(1),
+		title:    title,
+		source:   source,
+		interval: interval,
+		loading:  true,
+		viewport: vp,
+		follow:   true,
+		current:  -1,
+		input:    input,
+	}
+}
+
+func (m LogViewerModel) Init() tea.Cmd {
+	return m.load()
+}
+
+// load fetches the logs in the background
+func (m LogViewerModel) load() tea.Cmd {
+	id, source := m.id, m.source
+	return func() tea.Msg {
+		logs, done, err := source()
+		return logViewerMsg{id: id, logs: logs, done: done, err: err}
+	}
+}
+
+// Editing reports the input line is reading a search or a time, so keys
+// are text rather than commands
+func (m LogViewerModel) Editing() bool {
+	return m.inputMode != inputNone
+}
+
+// SetSize fits the viewer in width by height cells
+func (m *LogViewerModel) SetSize(width, height int) {
+	m.width, m.height = width, height
+	// Title, input or status line and footer
+	m.viewport.Width = max(width, 10)
+	m.viewport.Height = max(height-3, 3)
+	m.input.Width = max(width-12, 10)
+	m.render()
+}
+
+func (m LogViewerModel) Update(msg tea.Msg) (LogViewerModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+		return m, nil
+
+	case logViewerMsg:
+		if msg.id != m.id {
+			return m, nil
+		}
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.setLogs(msg.logs)
+			m.done = msg.done
+		}
+		if m.done || m.interval <= 0 {
+			return m, nil
+		}
+		id := m.id
+		return m, tea.Tick(m.interval, func(time.Time) tea.Msg {
+			return logViewerTickMsg{id: id}
+		})
+
+	case logViewerTickMsg:
+		if msg.id != m.id {
+			return m, nil
+		}
+		m.loading = true
+		return m, m.load()
+
+	case tea.KeyMsg:
+		if m.inputMode != inputNone {
+			return m.updateInput(msg)
+		}
+		return m.updateKeys(msg)
+	}
+	return m, nil
+}
+
+// updateInput handles keys while the input line is open
+func (m LogViewerModel) updateInput(msg tea.KeyMsg) (LogViewerModel, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.inputMode = inputNone
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		value := strings.TrimSpace(m.input.Value())
+		mode := m.inputMode
+		m.inputMode = inputNone
+		m.input.Blur()
+		if mode == inputSearch {
+			m.search(value)
+		} else {
+			m.jump(value)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// updateKeys handles keys on the logs
+func (m LogViewerModel) updateKeys(msg tea.KeyMsg) (LogViewerModel, tea.Cmd) {
+	m.status = ""
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "q", "esc":
+		if m.quitKeys {
+			return m, tea.Quit
+		}
+		return m, nil
+	case "/":
+		return m, m.openInput(inputSearch, m.patternString())
+	case "t", ":":
+		return m, m.openInput(inputJump, "")
+	case "n", "N":
+		if len(m.matches) == 0 {
+			m.status = "No matches"
+			return m, nil
+		}
+		from := m.current
+		if from < 0 {
+			from = m.viewport.YOffset - 1
+		}
+		m.current = logview.NextMatch(m.matches, from, msg.String() == "N")
+		m.follow = false
+		m.render()
+		m.scrollTo(m.current)
+		return m, nil
+	case "&":
+		if m.pattern == nil {
+			m.status = "Search with / first"
+			return m, nil
+		}
+		m.onlyMatches = !m.onlyMatches
+		m.current = -1
+		m.render()
+		return m, nil
+	case "e":
+		m.minLevel = (m.minLevel + 1) % len(viewerLevels)
+		m.current = -1
+		m.render()
+		return m, nil
+	case "f":
+		m.follow = !m.follow
+		if m.follow {
+			m.viewport.GotoBottom()
+		}
+		return m, nil
+	case "g", "home":
+		m.follow = false
+		m.viewport.GotoTop()
+		return m, nil
+	case "G", "end":
+		m.follow = true
+		m.viewport.GotoBottom()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	if !m.viewport.AtBottom() {
+		m.follow = false
+	}
+	return m, cmd
+}
+
+// openInput opens the input line for mode, starting with value
+func (m *LogViewerModel) openInput(mode viewerInput, value string) tea.Cmd {
+	m.inputMode = mode
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+// search highlights the lines matching pattern and moves to the first one
+// below the top of the view. An empty pattern clears the search.
+func (m *LogViewerModel) search(pattern string) {
+	re, err := logview.CompilePattern(pattern)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	m.pattern = re
+	m.current = -1
+	if re == nil {
+		m.onlyMatches = false
+	}
+	m.render()
+	if re == nil {
+		return
+	}
+	if len(m.matches) == 0 {
+		m.status = fmt.Sprintf("No lines match %s", pattern)
+		return
+	}
+	m.current = logview.NextMatch(m.matches, m.viewport.YOffset-1, false)
+	m.follow = false
+	m.render()
+	m.scrollTo(m.current)
+}
+
+// jump moves to the first line logged at or after the time in value
+func (m *LogViewerModel) jump(value string) {
+	if value == "" {
+		return
+	}
+	lines := make([]string, len(m.shown))
+	for i, idx := range m.shown {
+		lines[i] = m.lines[idx]
+	}
+	ref, ok := logview.LastTimestamp(lines)
+	if !ok {
+		m.status = "These logs have no timestamps"
+		return
+	}
+	at, err := logview.ParseTime(value, ref)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	i := logview.FirstAt(lines, at)
+	if i < 0 {
+		m.status = fmt.Sprintf("No lines at or after %s", at.Format(time.RFC3339))
+		return
+	}
+	m.follow = false
+	m.scrollTo(i)
+}
+
+// scrollTo puts the shown line i near the top of the view
+func (m *LogViewerModel) scrollTo(i int) {
+	m.viewport.SetYOffset(max(i-2, 0))
+}
+
+// setLogs replaces the lines shown with those of logs
+func (m *LogViewerModel) setLogs(logs string) {
+	logs = strings.TrimRight(logs, "\n")
+	if logs == "" {
+		m.lines = nil
+	} else {
+		m.lines = strings.Split(logs, "\n")
+	}
+	m.levels = logview.Levels(m.lines)
+	m.render()
+}
+
+// render works out the lines shown and redraws them into the viewport
+func (m *LogViewerModel) render() {
+	minLevel := viewerLevels[m.minLevel]
+	m.shown, m.matches = nil, nil
+	for i, line := range m.lines {
+		if m.levels[i] < minLevel {
+			continue
+		}
+		matched := m.pattern != nil && m.pattern.MatchString(line)
+		if m.onlyMatches && !matched {
+			continue
+		}
+		if matched {
+			m.matches = append(m.matches, len(m.shown))
+		}
+		m.shown = append(m.shown, i)
+	}
+	if indexOf(m.matches, m.current) < 0 {
+		m.current = -1
+	}
+
+	var s strings.Builder
+	for i, idx := range m.shown {
+		if i > 0 {
+			s.WriteByte('\n')
+		}
+		s.WriteString(m.renderLine(m.lines[idx], m.levels[idx], i == m.current))
+	}
+	m.viewport.SetContent(s.String())
+	if m.follow {
+		m.viewport.GotoBottom()
+	}
+}
+
+// renderLine colors line by its level and highlights the matches in it
+func (m *LogViewerModel) renderLine(line string, level logview.Level, current bool) string {
+	line = strings.ReplaceAll(line, "\t", "    ")
+	if m.pattern == nil {
+		return RenderLogLine(line, level)
+	}
+	locs := m.pattern.FindAllStringIndex(line, -1)
+	if len(locs) == 0 {
+		return RenderLogLine(line, level)
+	}
+	match := logMatchStyle
+	if current {
+		match = logCurrentMatchStyle
+	}
+	var s strings.Builder
+	last := 0
+	for _, loc := range locs {
+		if loc[0] == loc[1] {
+			continue
+		}
+		s.WriteString(RenderLogLine(line[last:loc[0]], level))
+		s.WriteString(match.Render(line[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	s.WriteString(RenderLogLine(line[last:], level))
+	return s.String()
+}
+
+// patternString returns the current search pattern as typed
+func (m LogViewerModel) patternString() string {
+	if m.pattern == nil {
+		return ""
+	}
+	return strings.TrimPrefix(m.pattern.String(), "(?i)")
+}
+
+func (m LogViewerModel) View() string {
+	var s strings.Builder
+
+	// Title and state
+	header := dashboardHeaderStyle.Render(m.title)
+	var state []string
+	switch {
+	case m.done:
+		state = append(state, "complete")
+	case m.follow:
+		state = append(state, "following")
+	}
+	if level := viewerLevels[m.minLevel]; level != logview.LevelNone {
+		state = append(state, level.String()+" and above")
+	}
+	if m.pattern != nil {
+		count := fmt.Sprintf("%d matches", len(m.matches))
+		if m.current >= 0 {
+			count = fmt.Sprintf("match %d of %d", indexOf(m.matches, m.current)+1, len(m.matches))
+		}
+		if m.onlyMatches {
+			count += ", matching lines only"
+		}
+		state = append(state, fmt.Sprintf("/%s/ %s", m.patternString(), count))
+	}
+	state = append(state, fmt.Sprintf("%d lines", len(m.shown)))
+	header += "  " + footerStyle.Render(strings.Join(state, ", "))
+	if m.loading {
+		header += footerStyle.Render(" ↻")
+	}
+	s.WriteString(header + "\n")
+
+	// Logs
+	if len(m.lines) == 0 && !m.loading && m.err == nil {
+		s.WriteString(descTextStyle.Render("No logs yet"))
+		s.WriteString(strings.Repeat("\n", max(m.viewport.Height-1, 0)) + "\n")
+	} else {
+		s.WriteString(m.viewport.View() + "\n")
+	}
+
+	// Input or status line
+	switch {
+	case m.inputMode == inputSearch:
+		s.WriteString(footerKeyStyle.Render("search /") + " " + m.input.View())
+	case m.inputMode == inputJump:
+		s.WriteString(footerKeyStyle.Render("jump to") + " " + m.input.View())
+	case m.status != "":
+		s.WriteString(WarningStyle.Render(m.status))
+	case m.err != nil:
+		s.WriteString(ErrorStyle.Render(m.err.Error()))
+	}
+	s.WriteString("\n")
+
+	keys := []string{
+		footerKeyStyle.Render("↑↓←→") + " scroll",
+		footerKeyStyle.Render("/") + " search",
+		footerKeyStyle.Render("n/N") + " match",
+		footerKeyStyle.Render("&") + " matching",
+		footerKeyStyle.Render("e") + " level",
+		footerKeyStyle.Render("t") + " time",
+		footerKeyStyle.Render("f") + " follow",
+	}
+	if m.quitKeys {
+		keys = append(keys, footerKeyStyle.Render("q")+" quit")
+	} else {
+		keys = append(keys, footerKeyStyle.Render("esc")+" back")
+	}
+	s.WriteString(footerStyle.Render(strings.Join(keys, footerSepStyle.Render(" │ "))))
+	return s.String()
+}
+
+// indexOf returns the position of v in values, or -1
+func indexOf(values []int, v int) int {
+	for i, value := range values {
+		if value == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// logViewerProgram runs a log viewer as a program of its own
+type logViewerProgram struct {
+	LogViewerModel
+}
+
+func (p logViewerProgram) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := p.LogViewerModel.Update(msg)
+	return logViewerProgram{m}, cmd
+}
+
+// RunLogViewer shows the logs of source full screen until it's quit, polling
+// every interval until they're done
+func RunLogViewer(title string, source LogSource, interval time.Duration) error {
+	m := NewLogViewerModel(title, source, interval)
+	m.quitKeys = true
+	p := tea.NewProgram(logViewerProgram{m}, tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// RenderLogLine colors a log line by its level: errors red, warnings yellow
+// and debug output dim
+func RenderLogLine(line string, level logview.Level) string {
+	if line == "" {
+		return ""
+	}
+	switch level {
+	case logview.LevelError:
+		return ErrorStyle.Render(line)
+	case logview.LevelWarn:
+		return WarningStyle.Render(line)
+	case logview.LevelDebug:
+		return DimStyle.Render(line)
+	}
+	return line
+}
+
+// RenderLogs colors each line of logs by its level, for printing
+func RenderLogs(logs string) string {
+	lines := strings.Split(logs, "\n")
+	levels := logview.Levels(lines)
+	for i, line := range lines {
+		lines[i] = RenderLogLine(line, levels[i])
+	}
+	return strings.Join(lines, "\n")
+}
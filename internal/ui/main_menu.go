@@ -2,10 +2,14 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/entro314-labs/cool-kit/internal/config"
 )
 
 // MainMenuState represents the state of the main menu
@@ -56,10 +60,14 @@ type MainMenuModel struct {
 	sections      []MenuSection
 	cursor        int
 	selected      MainMenuSelection
+	project       *config.RecentProject // the selection runs for it, if set
 	width         int
 	height        int
 	flatChoices   []MenuChoice // flattened for navigation
 	sectionBounds []int        // indices where sections start
+
+	context MenuContext
+	first   int // index of the first section of actions, after the projects
 }
 
 type MenuChoice struct {
@@ -67,6 +75,50 @@ type MenuChoice struct {
 	Description string
 	Selection   MainMenuSelection
 	Icon        string
+	Project     *config.RecentProject // a project row of the menu
+}
+
+// maxMenuProjects is how many recent projects the main menu lists
+const maxMenuProjects = 5
+
+// MenuContext is what the main menu shows about where it runs
+type MenuContext struct {
+	Instance string                // URL of the instance commands use, if logged in
+	Here     *config.RecentProject // the project linked in the working directory
+	Recent   []config.RecentProject
+}
+
+// LoadMenuContext finds the instance, the linked project and the recent
+// projects for the main menu. What can't be loaded is left out.
+func LoadMenuContext() MenuContext {
+	var ctx MenuContext
+	if global, err := config.LoadGlobal(); err == nil {
+		ctx.Instance = global.CoolifyURL
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		if project, err := config.LoadProject(); err == nil && project != nil {
+			ctx.Here = &config.RecentProject{Name: project.Name, Dir: cwd, App: config.SelectedApp()}
+		}
+	}
+	for _, project := range config.RecentProjects() {
+		if ctx.Here != nil && project.Dir == ctx.Here.Dir && project.App == ctx.Here.App {
+			continue
+		}
+		if len(ctx.Recent) == maxMenuProjects {
+			break
+		}
+		ctx.Recent = append(ctx.Recent, project)
+	}
+	return ctx
+}
+
+// projects returns the linked project and the recent ones
+func (c MenuContext) projects() []config.RecentProject {
+	var projects []config.RecentProject
+	if c.Here != nil {
+		projects = append(projects, *c.Here)
+	}
+	return append(projects, c.Recent...)
 }
 
 // Enhanced styles for modern widescreen TUI
@@ -136,7 +188,7 @@ var (
 			Foreground(lipgloss.Color("#333333"))
 )
 
-func NewMainMenuModel() MainMenuModel {
+func NewMainMenuModel(ctx MenuContext) MainMenuModel {
 	sections := []MenuSection{
 		{
 			Title: "🏗️  DEPLOY COOLIFY",
@@ -173,7 +225,7 @@ func NewMainMenuModel() MainMenuModel {
 			Choices: []MenuChoice{
 				{Title: "Backup Instance", Description: "Backup Coolify data and volumes", Selection: SelectionBackup, Icon: "💾"},
 				{Title: "Generate Badge", Description: "Create deployment status badge", Selection: SelectionBadge, Icon: "🏷️"},
-				{Title: "Generate CI", Description: "Create GitHub Actions workflow", Selection: SelectionCI, Icon: "🔄"},
+				{Title: "Generate CI", Description: "Create a CI deployment pipeline", Selection: SelectionCI, Icon: "🔄"},
 			},
 		},
 		{
@@ -185,6 +237,24 @@ func NewMainMenuModel() MainMenuModel {
 		},
 	}
 
+	// The projects come first, to deploy, tail or check them in a keystroke
+	first := 0
+	if projects := ctx.projects(); len(projects) > 0 {
+		var choices []MenuChoice
+		for i := range projects {
+			project := &projects[i]
+			choices = append(choices, MenuChoice{
+				Title:       project.Name,
+				Description: projectPlace(project),
+				Selection:   SelectionDeploy,
+				Icon:        "📁",
+				Project:     project,
+			})
+		}
+		sections = append([]MenuSection{{Title: "📌  PROJECTS", Choices: choices}}, sections...)
+		first = 1
+	}
+
 	// Flatten choices for navigation
 	var flat []MenuChoice
 	var bounds []int
@@ -200,7 +270,43 @@ func NewMainMenuModel() MainMenuModel {
 		sectionBounds: bounds,
 		width:         100,
 		height:        24,
+		context:       ctx,
+		first:         first,
+	}
+}
+
+// projectPlace describes where project is: its directory, with the home
+// directory as ~, its monorepo app, and whether it's linked here
+func projectPlace(project *config.RecentProject) string {
+	dir := project.Dir
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			dir = filepath.Join("~", rel)
+		}
 	}
+	if project.App != "" {
+		dir += " (app " + project.App + ")"
+	}
+	if cwd, err := os.Getwd(); err == nil && cwd == project.Dir {
+		dir += ", linked here"
+	}
+	return dir
+}
+
+// actionProject returns the project the d, l and s keys act on: the one
+// under the cursor, or else the one linked here
+func (m MainMenuModel) actionProject() *config.RecentProject {
+	if p := m.flatChoices[m.cursor].Project; p != nil {
+		return p
+	}
+	return m.context.Here
+}
+
+// runFor quits the menu to run selection for project
+func (m MainMenuModel) runFor(selection MainMenuSelection, project *config.RecentProject) (tea.Model, tea.Cmd) {
+	m.selected = selection
+	m.project = project
+	return m, tea.Quit
 }
 
 func (m MainMenuModel) Init() tea.Cmd {
@@ -226,7 +332,26 @@ func (m MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cursor = 0
 		case "end", "G":
 			m.cursor = len(m.flatChoices) - 1
-		case "tab", "right", "l":
+		case "d", "s":
+			if project := m.actionProject(); project != nil {
+				selection := SelectionDeploy
+				if msg.String() == "s" {
+					selection = SelectionStatus
+				}
+				return m.runFor(selection, project)
+			}
+		case "l":
+			if project := m.actionProject(); project != nil {
+				return m.runFor(SelectionLogs, project)
+			}
+			// Without a project, l moves to the next section like tab
+			for _, bound := range m.sectionBounds {
+				if bound > m.cursor {
+					m.cursor = bound
+					break
+				}
+			}
+		case "tab", "right":
 			// Jump to next section
 			for i, bound := range m.sectionBounds {
 				if bound > m.cursor && i < len(m.sectionBounds) {
@@ -243,8 +368,8 @@ func (m MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		case "enter", " ":
-			m.selected = m.flatChoices[m.cursor].Selection
-			return m, tea.Quit
+			choice := m.flatChoices[m.cursor]
+			return m.runFor(choice.Selection, choice.Project)
 		case "1":
 			m.selected = SelectionInstall
 			return m, tea.Quit
@@ -259,6 +384,23 @@ func (m MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// renderContext renders the line under the header: the instance and the
+// project linked here
+func (m MainMenuModel) renderContext() string {
+	instance := "Not logged in"
+	if m.context.Instance != "" {
+		instance = "Instance " + m.context.Instance
+	}
+	project := "No project linked here"
+	if here := m.context.Here; here != nil {
+		project = "Project " + here.Name
+		if here.App != "" {
+			project += " (app " + here.App + ")"
+		}
+	}
+	return taglineStyle.Render(instance + "  ·  " + project)
+}
+
 // getSectionForCursor returns which section index the cursor is in
 func (m MainMenuModel) getSectionForCursor() int {
 	for i := len(m.sectionBounds) - 1; i >= 0; i-- {
@@ -289,6 +431,9 @@ func (m MainMenuModel) renderSection(sectionIdx int, width int) string {
 		isSelected := m.cursor == choiceIdx
 
 		line := fmt.Sprintf("%s %s", choice.Icon, choice.Title)
+		if choice.Project != nil {
+			line += "  " + choice.Description
+		}
 		if isSelected {
 			line = "▸ " + line
 			content.WriteString(selectedItemStyle.Render(line))
@@ -324,6 +469,8 @@ func (m MainMenuModel) View() string {
 	header := logoStyle.Render("🧊 COOL KIT") + "  " + taglineStyle.Render("The Complete Coolify Toolkit")
 	headerLine := lipgloss.NewStyle().Width(totalWidth).Align(lipgloss.Center).Render(header)
 	s.WriteString(headerLine)
+	s.WriteString("\n")
+	s.WriteString(lipgloss.NewStyle().Width(totalWidth).Align(lipgloss.Center).Render(m.renderContext()))
 	s.WriteString("\n\n")
 
 	// Calculate column widths for 3-column layout (narrower columns)
@@ -336,20 +483,27 @@ func (m MainMenuModel) View() string {
 		colWidth = 28
 	}
 
+	// Projects - spans the three columns
+	if m.first > 0 {
+		projects := m.renderSection(0, colWidth*3+colGap*2)
+		s.WriteString(lipgloss.NewStyle().Width(totalWidth).Align(lipgloss.Center).Render(projects))
+		s.WriteString("\n")
+	}
+
 	// Left column: Deploy Coolify + Deploy Apps
-	leftCol := m.renderSection(0, colWidth)
+	leftCol := m.renderSection(m.first, colWidth)
 	leftCol += "\n"
-	leftCol += m.renderSection(1, colWidth)
+	leftCol += m.renderSection(m.first+1, colWidth)
 
 	// Middle column: Monitor + Settings
-	midCol := m.renderSection(2, colWidth)
+	midCol := m.renderSection(m.first+2, colWidth)
 	midCol += "\n"
-	midCol += m.renderSection(3, colWidth)
+	midCol += m.renderSection(m.first+3, colWidth)
 
 	// Right column: Tools + Help/Exit
-	rightCol := m.renderSection(4, colWidth)
+	rightCol := m.renderSection(m.first+4, colWidth)
 	rightCol += "\n"
-	rightCol += m.renderSection(5, colWidth)
+	rightCol += m.renderSection(m.first+5, colWidth)
 
 	// Join columns horizontally
 	gap := strings.Repeat(" ", colGap)
@@ -359,8 +513,12 @@ func (m MainMenuModel) View() string {
 
 	// Description box - full width at bottom
 	currentChoice := m.flatChoices[m.cursor]
+	description := currentChoice.Description
+	if currentChoice.Project != nil {
+		description = "Deploy, view logs or check the status of this project"
+	}
 	descContent := descTitleStyle.Render(currentChoice.Icon+" "+currentChoice.Title) + "  " +
-		descTextStyle.Render(currentChoice.Description)
+		descTextStyle.Render(description)
 	descWidth := colWidth*3 + colGap*2
 	descBox := descBoxStyle.Width(descWidth).Render(descContent)
 	s.WriteString(lipgloss.NewStyle().Width(totalWidth).Align(lipgloss.Center).Render(descBox))
@@ -371,8 +529,13 @@ func (m MainMenuModel) View() string {
 		footerKeyStyle.Render("↑↓") + " navigate",
 		footerKeyStyle.Render("←→") + " sections",
 		footerKeyStyle.Render("Enter") + " select",
-		footerKeyStyle.Render("q") + " quit",
 	}
+	if project := m.actionProject(); project != nil {
+		footerParts = append(footerParts,
+			footerKeyStyle.Render("d")+"/"+footerKeyStyle.Render("l")+"/"+footerKeyStyle.Render("s")+
+				" deploy/logs/status "+project.Name)
+	}
+	footerParts = append(footerParts, footerKeyStyle.Render("q")+" quit")
 	footer := footerStyle.Render(strings.Join(footerParts, footerSepStyle.Render(" │ ")))
 	footerLine := lipgloss.NewStyle().Width(totalWidth).Align(lipgloss.Center).Render(footer)
 	s.WriteString(footerLine)
@@ -380,15 +543,57 @@ func (m MainMenuModel) View() string {
 	return s.String()
 }
 
-// RunMainMenu runs the main menu and returns the selected action
-func RunMainMenu() (MainMenuSelection, error) {
-	p := tea.NewProgram(NewMainMenuModel(), tea.WithAltScreen())
+// RunMainMenu runs the main menu and returns the selected action, and the
+// project to run it for when one of the projects was picked
+func RunMainMenu() (MainMenuSelection, *config.RecentProject, error) {
+	if err := needsUser("Main menu", "run a command, see --help"); err != nil {
+		return SelectionNone, nil, err
+	}
+	model := NewMainMenuModel(LoadMenuContext())
+	if accessible {
+		return selectMainMenu(model)
+	}
+	p := tea.NewProgram(model, tea.WithAltScreen())
 	m, err := p.Run()
 	if err != nil {
-		return SelectionNone, err
+		return SelectionNone, nil, err
 	}
 	if model, ok := m.(MainMenuModel); ok {
-		return model.selected, nil
+		return model.selected, model.project, nil
+	}
+	return SelectionNone, nil, nil
+}
+
+// selectMainMenu asks for one of m's choices with a plain select, for
+// accessible mode. Each project is listed once per action.
+func selectMainMenu(m MainMenuModel) (MainMenuSelection, *config.RecentProject, error) {
+	type action struct {
+		selection MainMenuSelection
+		project   *config.RecentProject
+	}
+	var actions []action
+	var opts []huh.Option[int]
+	add := func(label string, a action) {
+		opts = append(opts, huh.NewOption(label, len(actions)))
+		actions = append(actions, a)
+	}
+	for _, c := range m.flatChoices {
+		if c.Project == nil {
+			add(c.Title+" - "+c.Description, action{selection: c.Selection})
+			continue
+		}
+		add("Deploy "+c.Title+" - "+c.Description, action{SelectionDeploy, c.Project})
+		add("Logs of "+c.Title, action{SelectionLogs, c.Project})
+		add("Status of "+c.Title, action{SelectionStatus, c.Project})
+	}
+
+	var picked int
+	err := runField(huh.NewSelect[int]().
+		Title("What would you like to do?").
+		Options(opts...).
+		Value(&picked))
+	if err != nil {
+		return SelectionNone, nil, err
 	}
-	return SelectionNone, nil
+	return actions[picked].selection, actions[picked].project, nil
 }
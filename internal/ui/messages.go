@@ -12,3 +12,6 @@ type taskCompleteMsg struct {
 
 // allTasksCompleteMsg indicates all tasks have finished
 type allTasksCompleteMsg struct{}
+
+// taskStatusMsg is a progress report from the running task
+type taskStatusMsg string
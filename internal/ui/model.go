@@ -6,6 +6,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/entro314-labs/cool-kit/internal/config"
 )
@@ -403,3 +404,19 @@ func (m Model) handleNext() (Model, tea.Cmd) {
 	}
 	return m, nil
 }
+
+// SelectProvider asks for the provider to install on with a plain select,
+// for accessible mode
+func SelectProvider() (string, error) {
+	choices := NewModel().choices
+	opts := make([]huh.Option[string], len(choices))
+	for i, c := range choices {
+		opts[i] = huh.NewOption(c.Title+" - "+c.Description, c.Value)
+	}
+	var provider string
+	err := runField(huh.NewSelect[string]().
+		Title("Select provider").
+		Options(opts...).
+		Value(&provider))
+	return provider, err
+}
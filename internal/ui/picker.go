@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/fuzzy"
+)
+
+// pickerRows is how many options the picker shows at once
+const pickerRows = 10
+
+// Kinds of recent picks, so each picker lists what was picked last first
+const (
+	PickServer  = "server"
+	PickProject = "project"
+	PickApp     = "app"
+)
+
+// PickOption is an option of Pick: the key it returns and the label shown
+type PickOption struct {
+	Key   string
+	Label string
+}
+
+// Pick asks for one of options with a fuzzy finder: typing narrows the
+// list to the options that match. The options picked recently of kind come
+// first, then the rest in the order given, and the pick is remembered.
+func Pick(prompt, kind string, options []PickOption) (string, error) {
+	if len(options) == 0 {
+		return "", fmt.Errorf("no options provided")
+	}
+	if err := needsUser(prompt, ""); err != nil {
+		return "", err
+	}
+
+	ordered, recent := recentFirst(options, config.RecentPicks(kind))
+	var key string
+	if accessible {
+		// A list that narrows as you type isn't read out, so ask for a number
+		opts := make([]huh.Option[string], len(ordered))
+		for i, opt := range ordered {
+			opts[i] = huh.NewOption(opt.Label, opt.Key)
+		}
+		if err := runField(huh.NewSelect[string]().Title(prompt).Options(opts...).Value(&key)); err != nil {
+			return "", err
+		}
+	} else {
+		result, err := tea.NewProgram(newPickerModel(prompt, ordered, recent)).Run()
+		if err != nil {
+			return "", err
+		}
+		m := result.(pickerModel)
+		if m.aborted {
+			return "", huh.ErrUserAborted
+		}
+		key = m.options[m.chosen].Key
+	}
+
+	// Losing the order isn't worth failing the pick over
+	_ = config.RecordPick(kind, key)
+	return key, nil
+}
+
+// recentFirst moves the options with a recent key to the front, most
+// recent first, and returns how many it moved
+func recentFirst(options []PickOption, recent []string) ([]PickOption, int) {
+	index := make(map[string]int, len(options))
+	for i, opt := range options {
+		index[opt.Key] = i
+	}
+
+	ordered := make([]PickOption, 0, len(options))
+	moved := make(map[int]bool)
+	for _, key := range recent {
+		if i, ok := index[key]; ok && !moved[i] {
+			ordered = append(ordered, options[i])
+			moved[i] = true
+		}
+	}
+	for i, opt := range options {
+		if !moved[i] {
+			ordered = append(ordered, opt)
+		}
+	}
+	return ordered, len(moved)
+}
+
+type pickerModel struct {
+	prompt  string
+	options []PickOption
+	labels  []string
+	recent  int // the first options were picked recently
+
+	input   textinput.Model
+	matches []fuzzy.Result
+	cursor  int // index into matches
+	offset  int // of the first match shown
+	rows    int
+
+	chosen  int // index into options
+	done    bool
+	aborted bool
+}
+
+func newPickerModel(prompt string, options []PickOption, recent int) pickerModel {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "type to search"
+	input.CharLimit = 128
+	input.Focus()
+
+	labels := make([]string, len(options))
+	for i, opt := range options {
+		labels[i] = opt.Label
+	}
+
+	return pickerModel{
+		prompt:  prompt,
+		options: options,
+		labels:  labels,
+		recent:  recent,
+		input:   input,
+		matches: fuzzy.Filter("", labels),
+		rows:    pickerRows,
+	}
+}
+
+func (m pickerModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// The prompt, the input and the footer take a line each
+		m.rows = max(1, min(pickerRows, msg.Height-4))
+		m.scroll()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.aborted = true
+			return m, tea.Quit
+		case "enter":
+			if len(m.matches) == 0 {
+				return m, nil
+			}
+			m.chosen = m.matches[m.cursor].Index
+			m.done = true
+			return m, tea.Quit
+		case "up", "ctrl+p", "ctrl+k":
+			m.move(-1)
+			return m, nil
+		case "down", "ctrl+n", "ctrl+j", "tab":
+			m.move(1)
+			return m, nil
+		case "pgup":
+			m.move(-m.rows)
+			return m, nil
+		case "pgdown":
+			m.move(m.rows)
+			return m, nil
+		}
+	}
+
+	query := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != query {
+		m.matches = fuzzy.Filter(m.input.Value(), m.labels)
+		m.cursor, m.offset = 0, 0
+	}
+	return m, cmd
+}
+
+// move moves the cursor by n matches, wrapping around at the ends when
+// moving a single row
+func (m *pickerModel) move(n int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.cursor += n
+	switch {
+	case m.cursor < 0 && n == -1:
+		m.cursor = len(m.matches) - 1
+	case m.cursor >= len(m.matches) && n == 1:
+		m.cursor = 0
+	}
+	m.cursor = max(0, min(m.cursor, len(m.matches)-1))
+	m.scroll()
+}
+
+// scroll keeps the cursor within the rows shown
+func (m *pickerModel) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.rows {
+		m.offset = m.cursor - m.rows + 1
+	}
+}
+
+func (m pickerModel) View() string {
+	if m.done {
+		return fmt.Sprintf("%s %s\n", BoldStyle.Render(m.prompt), InfoStyle.Render(m.options[m.chosen].Label))
+	}
+	if m.aborted {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(BoldStyle.Render(m.prompt) + "\n")
+	b.WriteString(m.input.View() + "\n")
+
+	end := min(m.offset+m.rows, len(m.matches))
+	for i := m.offset; i < end; i++ {
+		match := m.matches[i]
+		label := highlightRunes(m.labels[match.Index], match.Positions)
+		if match.Index < m.recent {
+			label += DimStyle.Render("  recent")
+		}
+		if i == m.cursor {
+			b.WriteString(InfoStyle.Render(IconArrow+" ") + label + "\n")
+		} else {
+			b.WriteString("  " + label + "\n")
+		}
+	}
+	if len(m.matches) == 0 {
+		b.WriteString(DimStyle.Render("  No matches") + "\n")
+	}
+
+	b.WriteString(DimStyle.Render(fmt.Sprintf("%d/%d · ↑/↓ move · enter select · esc cancel", len(m.matches), len(m.options))))
+	return b.String()
+}
+
+// highlightRunes colors the runes of s at positions, which are sorted
+func highlightRunes(s string, positions []int) string {
+	if len(positions) == 0 {
+		return s
+	}
+	var b strings.Builder
+	next := 0
+	for i, r := range []rune(s) {
+		if next < len(positions) && positions[next] == i {
+			b.WriteString(WarningStyle.Render(string(r)))
+			next++
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
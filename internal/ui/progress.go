@@ -68,6 +68,21 @@ const (
 	LogDebug
 )
 
+// String returns the level's name, as in run transcripts
+func (l LogLevel) String() string {
+	switch l {
+	case LogSuccess:
+		return "success"
+	case LogWarning:
+		return "warn"
+	case LogError:
+		return "error"
+	case LogDebug:
+		return "debug"
+	}
+	return "info"
+}
+
 // Progress message types
 type (
 	StepStartMsg struct {
@@ -238,22 +253,28 @@ func (m ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case StepStartMsg:
 		if msg.StepIndex < len(m.steps) {
-			m.steps[msg.StepIndex].Status = StepRunning
-			m.steps[msg.StepIndex].StartTime = time.Now()
-			m.currentStep = msg.StepIndex
-			m.addLog(LogInfo, fmt.Sprintf("Starting: %s", msg.StepName))
+			m.beginStep(msg.StepIndex)
 		}
 
 	case StepProgressMsg:
-		if msg.StepIndex < len(m.steps) {
-			m.steps[msg.StepIndex].Progress = msg.Progress
+		// Providers report progress within the step they're running without
+		// its index, and finish each step with its index
+		idx := msg.StepIndex
+		if msg.Progress < 1.0 {
+			idx = m.currentStep
+		}
+		if idx < len(m.steps) {
+			m.steps[idx].Progress = msg.Progress
 			if msg.Message != "" {
 				m.addLog(LogDebug, msg.Message)
 			}
-			// Auto-complete step when progress reaches 100%
-			if msg.Progress >= 1.0 && m.steps[msg.StepIndex].Status == StepRunning {
-				m.steps[msg.StepIndex].Status = StepComplete
-				m.steps[msg.StepIndex].EndTime = time.Now()
+			// Auto-complete step when progress reaches 100%, and start the next
+			if msg.Progress >= 1.0 && m.steps[idx].Status != StepComplete && m.steps[idx].Status != StepFailed {
+				m.steps[idx].Status = StepComplete
+				m.steps[idx].EndTime = time.Now()
+				if idx+1 < len(m.steps) {
+					m.beginStep(idx + 1)
+				}
 			}
 		}
 
@@ -574,6 +595,14 @@ func (m ProgressModel) countCompleted() int {
 	return count
 }
 
+// beginStep marks a step as the running one
+func (m *ProgressModel) beginStep(idx int) {
+	m.steps[idx].Status = StepRunning
+	m.steps[idx].StartTime = time.Now()
+	m.currentStep = idx
+	m.addLog(LogInfo, fmt.Sprintf("Starting: %s", m.steps[idx].Name))
+}
+
 func (m ProgressModel) startNextStep() tea.Cmd {
 	nextIdx := m.currentStep
 	if m.currentStep > 0 {
@@ -1,9 +1,15 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/entro314-labs/cool-kit/internal/config"
+	"github.com/entro314-labs/cool-kit/internal/notify"
+	"github.com/entro314-labs/cool-kit/internal/runs"
 )
 
 // Provider interface that deployment providers must implement
@@ -28,10 +34,15 @@ func NewDeploymentRunner(providerName string, provider Provider) *DeploymentRunn
 	}
 }
 
-// RunWithTUI executes the deployment with the interactive TUI
+// RunWithTUI executes the deployment with the interactive TUI, or as
+// RunSimple in non-interactive and accessible mode
 func (r *DeploymentRunner) RunWithTUI() error {
+	if !LiveOutput() {
+		return r.RunSimple()
+	}
 	steps := r.provider.GetDeploymentSteps()
 	model := NewProgressModel(r.providerName, steps)
+	rec := StartTranscript(runs.KindInstall, r.providerName, steps)
 
 	// Create channels for communicating with the provider
 	progressChan := make(chan StepProgressMsg, 100)
@@ -58,6 +69,7 @@ func (r *DeploymentRunner) RunWithTUI() error {
 					progressChan = nil
 					continue
 				}
+				rec.Progress(progress.StepIndex, progress.Progress, progress.Message)
 				p.Send(progress)
 
 			case log, ok := <-logChan:
@@ -65,9 +77,11 @@ func (r *DeploymentRunner) RunWithTUI() error {
 					logChan = nil
 					continue
 				}
+				rec.Log(log.Level.String(), log.Message)
 				p.Send(log)
 
 			case err := <-errChan:
+				rec.Finish(err)
 				if err != nil {
 					p.Send(DeploymentCompleteMsg{
 						Success: false,
@@ -90,6 +104,8 @@ func (r *DeploymentRunner) RunWithTUI() error {
 
 	// Run the TUI
 	finalModel, err := p.Run()
+	rec.Finish(errors.New("interrupted"))
+	EndTranscript(rec)
 	if err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
@@ -102,9 +118,12 @@ func (r *DeploymentRunner) RunWithTUI() error {
 	return nil
 }
 
-// RunSimple executes the deployment without TUI (for non-interactive mode)
+// RunSimple executes the deployment without TUI (for non-interactive and
+// accessible mode)
 func (r *DeploymentRunner) RunSimple() error {
 	steps := r.provider.GetDeploymentSteps()
+	rec := StartTranscript(runs.KindInstall, r.providerName, steps)
+	defer EndTranscript(rec)
 
 	Section(fmt.Sprintf("Deploying to %s", r.providerName))
 	Spacer()
@@ -132,24 +151,18 @@ func (r *DeploymentRunner) RunSimple() error {
 			if !ok {
 				progressChan = nil
 			} else {
-				Dim(fmt.Sprintf("[Step %d] %.0f%% - %s", progress.StepIndex+1, progress.Progress*100, progress.Message))
+				rec.Progress(progress.StepIndex, progress.Progress, progress.Message)
+				PrintStepProgress(progress)
 			}
 		case log, ok := <-logChan:
 			if !ok {
 				logChan = nil
 			} else {
-				switch log.Level {
-				case LogSuccess:
-					Success(log.Message)
-				case LogError:
-					Error(log.Message)
-				case LogWarning:
-					Warning(log.Message)
-				default:
-					Dim(log.Message)
-				}
+				rec.Log(log.Level.String(), log.Message)
+				PrintLog(log)
 			}
 		case err := <-errChan:
+			rec.Finish(err)
 			if err != nil {
 				Error(fmt.Sprintf("Deployment failed: %v", err))
 				return err
@@ -164,5 +177,75 @@ func (r *DeploymentRunner) RunSimple() error {
 		}
 	}
 
+	rec.Finish(nil)
 	return nil
 }
+
+// StartTranscript starts saving the transcript of a run with steps. When it
+// can't be saved, the run goes on without one.
+func StartTranscript(kind, target string, steps []DeploymentStep) *runs.Recorder {
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		names[i] = step.Name
+	}
+	rec, err := runs.Start(kind, target, names)
+	if err != nil {
+		Warning(fmt.Sprintf("Not saving a transcript of this run: %v", err))
+	}
+	return rec
+}
+
+// EndTranscript tells where a finished run's transcript is, and shows a
+// desktop notification when the run took long enough and they're enabled
+func EndTranscript(rec *runs.Recorder) {
+	if rec == nil {
+		return
+	}
+	Dim(fmt.Sprintf("Transcript saved to %s (run %s)", rec.LogPath(), rec.ID()))
+	notifyFinished(rec.Summary())
+}
+
+// notifyFinished shows a desktop notification for a run that took longer
+// than notifications.after_minutes, if notifications.desktop is on
+func notifyFinished(run runs.Run) {
+	cfg := config.Get()
+	if cfg == nil {
+		if err := config.Initialize(); err != nil {
+			return
+		}
+		cfg = config.Get()
+	}
+	if cfg == nil || !cfg.Notifications.Desktop || run.Duration() < cfg.Notifications.After() {
+		return
+	}
+
+	title := fmt.Sprintf("cool-kit %s %s", run.Kind, run.Status)
+	body := fmt.Sprintf("%s finished in %s", run.Target, run.Duration().Round(time.Second))
+	if run.Error != "" {
+		body = fmt.Sprintf("%s failed after %s: %s", run.Target, run.Duration().Round(time.Second), run.Error)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := notify.Desktop(ctx, title, body); err != nil {
+		Dim(fmt.Sprintf("Desktop notification failed: %v", err))
+	}
+}
+
+// PrintStepProgress prints a deployment step's progress as a line
+func PrintStepProgress(progress StepProgressMsg) {
+	Dim(fmt.Sprintf("[Step %d] %.0f%% - %s", progress.StepIndex+1, progress.Progress*100, progress.Message))
+}
+
+// PrintLog prints a deployment log message as a line
+func PrintLog(log LogMsg) {
+	switch log.Level {
+	case LogSuccess:
+		Success(log.Message)
+	case LogError:
+		Error(log.Message)
+	case LogWarning:
+		Warning(log.Message)
+	default:
+		Dim(log.Message)
+	}
+}
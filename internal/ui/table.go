@@ -0,0 +1,303 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-isatty"
+)
+
+// Column is a column of a ListTable
+type Column struct {
+	Name   string // header, and the name --sort, --columns and --filter use
+	Status bool   // color the cells by the status they name
+	Wide   bool   // hidden on narrow terminals unless asked for
+}
+
+// ListTable is the table of a list command. Cells hold plain text; the
+// table colors status columns itself so it can sort, filter and truncate.
+type ListTable struct {
+	Columns []Column
+	Rows    [][]string
+}
+
+// TableOptions select how a ListTable is shown
+type TableOptions struct {
+	Sort    string   // column to sort by, descending with a leading "-"
+	Columns []string // columns to show, in this order; all by default
+	Filters []string // "column=text", "column!=text" or text in any column
+	Wide    bool     // show every column in full, however wide
+}
+
+// minColumnWidth is how far a column is truncated on narrow terminals
+const minColumnWidth = 8
+
+// PrintTable prints t as selected by opts, fitted to the terminal
+func (t ListTable) PrintTable(opts TableOptions) error {
+	width := 0
+	if !opts.Wide && isatty.IsTerminal(os.Stdout.Fd()) {
+		width = getTerminalWidth()
+	}
+	out, err := t.Render(opts, width)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// Render returns t as selected by opts. With a width, columns marked Wide
+// are dropped and the widest columns truncated until the table fits.
+func (t ListTable) Render(opts TableOptions, width int) (string, error) {
+	rows, err := t.filter(opts.Filters)
+	if err != nil {
+		return "", err
+	}
+	if err := t.sort(rows, opts.Sort); err != nil {
+		return "", err
+	}
+	cols, err := t.selectColumns(opts.Columns)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return DimStyle.Render("No matching rows") + "\n", nil
+	}
+
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		widths[i] = ansi.StringWidth(t.Columns[c].Name)
+		for _, row := range rows {
+			widths[i] = max(widths[i], ansi.StringWidth(cell(row, c)))
+		}
+	}
+	if width > 0 {
+		cols, widths = t.fit(cols, widths, width, len(opts.Columns) > 0)
+	}
+
+	var b strings.Builder
+	for i, c := range cols {
+		if i > 0 {
+			b.WriteString("  ")
+		}
+		b.WriteString(BoldStyle.Render(pad(t.Columns[c].Name, widths[i])))
+	}
+	b.WriteString("\n")
+	total := 2 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	if !accessible {
+		b.WriteString(DimStyle.Render(strings.Repeat("─", total)) + "\n")
+	}
+
+	for _, row := range rows {
+		var line strings.Builder
+		for i, c := range cols {
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			text := ansi.Truncate(cell(row, c), widths[i], "…")
+			if i == len(cols)-1 {
+				// No padding after the last column
+				line.WriteString(styleCell(t.Columns[c], text))
+				continue
+			}
+			line.WriteString(styleCell(t.Columns[c], pad(text, widths[i])))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	return b.String(), nil
+}
+
+// fit drops Wide columns and then shrinks the widest ones until the
+// columns fit in width. Columns the user picked are never dropped.
+func (t ListTable) fit(cols, widths []int, width int, picked bool) ([]int, []int) {
+	total := func() int {
+		n := 2 * (len(widths) - 1)
+		for _, w := range widths {
+			n += w
+		}
+		return n
+	}
+	for i := len(cols) - 1; i >= 0 && total() > width && !picked; i-- {
+		if t.Columns[cols[i]].Wide {
+			cols = append(cols[:i:i], cols[i+1:]...)
+			widths = append(widths[:i:i], widths[i+1:]...)
+		}
+	}
+	for total() > width {
+		widest := -1
+		for i, w := range widths {
+			if w > minColumnWidth && !t.Columns[cols[i]].Status && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest] = max(minColumnWidth, widths[widest]-(total()-width))
+	}
+	return cols, widths
+}
+
+// column returns the index of the column called name, ignoring case
+func (t ListTable) column(name string) (int, error) {
+	for i, c := range t.Columns {
+		if strings.EqualFold(c.Name, strings.TrimSpace(name)) {
+			return i, nil
+		}
+	}
+	names := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		names[i] = strings.ToLower(c.Name)
+	}
+	return 0, fmt.Errorf("unknown column %q: use %s", name, strings.Join(names, ", "))
+}
+
+// selectColumns returns the indexes of the columns to show
+func (t ListTable) selectColumns(names []string) ([]int, error) {
+	var cols []int
+	for _, name := range names {
+		c, err := t.column(name)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, c)
+	}
+	if len(cols) > 0 {
+		return cols, nil
+	}
+	for i := range t.Columns {
+		cols = append(cols, i)
+	}
+	return cols, nil
+}
+
+// filter returns the rows that match every filter
+func (t ListTable) filter(filters []string) ([][]string, error) {
+	type match struct {
+		col    int // -1 for any column
+		text   string
+		negate bool
+	}
+	matches := make([]match, 0, len(filters))
+	for _, f := range filters {
+		m := match{col: -1, text: f}
+		if name, text, ok := strings.Cut(f, "="); ok {
+			m.text = text
+			if strings.HasSuffix(name, "!") {
+				name, m.negate = strings.TrimSuffix(name, "!"), true
+			}
+			c, err := t.column(name)
+			if err != nil {
+				return nil, err
+			}
+			m.col = c
+		}
+		m.text = strings.ToLower(m.text)
+		matches = append(matches, m)
+	}
+
+	rows := make([][]string, 0, len(t.Rows))
+	for _, row := range t.Rows {
+		keep := true
+		for _, m := range matches {
+			found := false
+			if m.col >= 0 {
+				found = strings.Contains(strings.ToLower(cell(row, m.col)), m.text)
+			} else {
+				for c := range t.Columns {
+					if strings.Contains(strings.ToLower(cell(row, c)), m.text) {
+						found = true
+						break
+					}
+				}
+			}
+			if found == m.negate {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// sort orders rows by a column, numbers by value and text ignoring case
+func (t ListTable) sort(rows [][]string, by string) error {
+	if by == "" {
+		return nil
+	}
+	desc := strings.HasPrefix(by, "-")
+	c, err := t.column(strings.TrimPrefix(by, "-"))
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := cell(rows[i], c), cell(rows[j], c)
+		if desc {
+			a, b = b, a
+		}
+		// Numbers like 12 or #12 sort by value
+		x, errA := strconv.ParseFloat(strings.TrimPrefix(a, "#"), 64)
+		y, errB := strconv.ParseFloat(strings.TrimPrefix(b, "#"), 64)
+		if errA == nil && errB == nil {
+			return x < y
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+	return nil
+}
+
+func cell(row []string, c int) string {
+	if c < len(row) {
+		return row[c]
+	}
+	return ""
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-ansi.StringWidth(s)))
+}
+
+func styleCell(c Column, text string) string {
+	if !c.Status {
+		return text
+	}
+	return StatusStyle(strings.TrimSpace(text)).Render(text)
+}
+
+// StatusStyle colors a status of Coolify or of the CLI: green when running
+// or done, red when failed or stopped, and blue while it changes
+func StatusStyle(status string) lipgloss.Style {
+	s := strings.ToLower(status)
+	if state, _, ok := strings.Cut(s, ":"); ok && !strings.Contains(s, "unhealthy") {
+		s = state
+	}
+	switch {
+	case strings.Contains(s, "unhealthy"), strings.Contains(s, "unreachable"),
+		strings.HasPrefix(s, "fail"), strings.HasPrefix(s, "error"),
+		strings.HasPrefix(s, "exited"), strings.HasPrefix(s, "degraded"):
+		return ErrorStyle
+	case strings.HasPrefix(s, "running"), strings.HasPrefix(s, "healthy"),
+		strings.HasPrefix(s, "finished"), strings.HasPrefix(s, "ready"),
+		strings.HasPrefix(s, "success"), strings.HasPrefix(s, "succeeded"), s == "enabled":
+		return SuccessStyle
+	case strings.HasPrefix(s, "in_progress"), strings.HasPrefix(s, "queued"),
+		strings.HasPrefix(s, "starting"), strings.HasPrefix(s, "restarting"),
+		strings.HasPrefix(s, "reachable"), strings.HasPrefix(s, "pending"):
+		return InfoStyle
+	case strings.HasPrefix(s, "stopped"), strings.HasPrefix(s, "cancelled"),
+		s == "disabled", s == "unknown", s == "-":
+		return DimStyle
+	}
+	return lipgloss.NewStyle()
+}
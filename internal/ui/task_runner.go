@@ -14,6 +14,18 @@ type Task struct {
 	ActiveName   string       // Message shown while task is running (e.g., "Loading servers...")
 	CompleteName string       // Message shown when task completes (e.g., "✓ Loaded servers")
 	Action       func() error // Function to execute
+
+	// Run is used instead of Action by tasks that report progress, shown
+	// after ActiveName while the task runs
+	Run func(report func(status string)) error
+}
+
+// run executes the task, passing its progress to report
+func (t Task) run(report func(status string)) error {
+	if t.Run != nil {
+		return t.Run(report)
+	}
+	return t.Action()
 }
 
 // TaskRunnerModel runs sequential tasks with spinner feedback
@@ -26,6 +38,8 @@ type TaskRunnerModel struct {
 	done       bool
 	quitting   bool
 	verbose    bool // If true, skip spinner and show completion messages immediately
+	status     string
+	statusCh   chan string
 }
 
 // NewTaskRunner creates a new task runner model
@@ -39,6 +53,7 @@ func NewTaskRunner(tasks []Task, verbose bool) TaskRunnerModel {
 		spinner:   s,
 		completed: []string{},
 		verbose:   verbose,
+		statusCh:  make(chan string, 1),
 	}
 }
 
@@ -51,9 +66,17 @@ func (m TaskRunnerModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
 		m.runNextTask(),
+		m.waitForStatus(),
 	)
 }
 
+// waitForStatus delivers the next progress report of the running task
+func (m TaskRunnerModel) waitForStatus() tea.Cmd {
+	return func() tea.Msg {
+		return taskStatusMsg(<-m.statusCh)
+	}
+}
+
 // runNextTask executes the next task in the queue
 func (m TaskRunnerModel) runNextTask() tea.Cmd {
 	if m.currentIdx >= len(m.tasks) {
@@ -63,8 +86,16 @@ func (m TaskRunnerModel) runNextTask() tea.Cmd {
 	}
 
 	task := m.tasks[m.currentIdx]
+	statusCh := m.statusCh
 	return func() tea.Msg {
-		err := task.Action()
+		err := task.run(func(status string) {
+			// Drop the report rather than block the task if the last one
+			// hasn't been shown yet
+			select {
+			case statusCh <- status:
+			default:
+			}
+		})
 		return taskCompleteMsg{err: err}
 	}
 }
@@ -90,6 +121,7 @@ func (m TaskRunnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		task := m.tasks[m.currentIdx]
 		m.completed = append(m.completed, task.CompleteName)
 		m.currentIdx++
+		m.status = ""
 
 		// Check if all tasks are done
 		if m.currentIdx >= len(m.tasks) {
@@ -108,6 +140,10 @@ func (m TaskRunnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+	case taskStatusMsg:
+		m.status = string(msg)
+		return m, m.waitForStatus()
+
 	case allTasksCompleteMsg:
 		m.done = true
 		return m, tea.Quit
@@ -139,6 +175,9 @@ func (m TaskRunnerModel) View() string {
 			// In normal mode, show spinner
 			buf.WriteString(m.spinner.View() + " " + task.ActiveName)
 		}
+		if m.status != "" {
+			buf.WriteString(" " + DimStyle.Render(m.status))
+		}
 	}
 
 	return buf.String()
@@ -157,11 +196,19 @@ func RunTasksVerbose(tasks []Task, verbose bool) error {
 		return nil
 	}
 
-	// In verbose mode, skip BubbleTea entirely and run tasks directly
-	if verbose {
+	// In verbose, non-interactive or accessible mode, skip BubbleTea
+	// entirely and run tasks directly
+	if verbose || !LiveOutput() {
 		for _, task := range tasks {
 			Info(task.ActiveName)
-			if err := task.Action(); err != nil {
+			last := ""
+			err := task.run(func(status string) {
+				if status != last {
+					Dim("  " + status)
+					last = status
+				}
+			})
+			if err != nil {
 				return err
 			}
 			Success(strings.TrimPrefix(task.CompleteName, "✓ "))
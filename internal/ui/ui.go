@@ -79,37 +79,54 @@ func init() {
 
 func Print(msg string) {
 	trace("Print")
-	fmt.Println(msg)
+	fmt.Println(plainText(msg))
 }
 
 func Success(msg string) {
 	trace("Success")
+	if accessible {
+		fmt.Println("Done: " + plainText(msg))
+		return
+	}
 	fmt.Println(SuccessStyle.Render(IconSuccess + " " + msg))
 }
 
 func Error(msg string) {
 	trace("Error")
+	if accessible {
+		fmt.Fprintln(os.Stderr, "Error: "+plainText(msg))
+		return
+	}
 	logger.Error(msg)
 }
 
 func Warning(msg string) {
 	trace("Warning")
+	if accessible {
+		fmt.Fprintln(os.Stderr, "Warning: "+plainText(msg))
+		return
+	}
 	logger.Warn(msg)
 }
 
 func Info(msg string) {
 	trace("Info")
+	if accessible {
+		// A prefix on every status line would only be noise
+		fmt.Fprintln(os.Stderr, plainText(msg))
+		return
+	}
 	logger.Info(msg)
 }
 
 func Dim(msg string) {
 	trace("Dim")
-	fmt.Println(DimStyle.Render(msg))
+	fmt.Println(DimStyle.Render(plainText(msg)))
 }
 
 func Bold(msg string) {
 	trace("Bold")
-	fmt.Println(BoldStyle.Render(msg))
+	fmt.Println(BoldStyle.Render(plainText(msg)))
 }
 
 func Spacer() {
@@ -118,6 +135,10 @@ func Spacer() {
 }
 
 func Divider() {
+	if accessible {
+		fmt.Println()
+		return
+	}
 	width := getTerminalWidth()
 	if width > 80 {
 		width = 80 // Cap at 80 for readability
@@ -152,22 +173,22 @@ func getTerminalSize() (int, int, error) {
 }
 
 func Code(msg string) {
-	fmt.Println(CodeStyle.Render(msg))
+	fmt.Println(CodeStyle.Render(plainText(msg)))
 }
 
 func Section(title string) {
 	fmt.Println()
-	fmt.Println(BoldStyle.Render(title))
+	fmt.Println(BoldStyle.Render(plainText(title)))
 	fmt.Println()
 }
 
 func KeyValue(key, value string) {
-	fmt.Printf("%s %s\n", DimStyle.Render(key+":"), value)
+	fmt.Printf("%s %s\n", DimStyle.Render(plainText(key)+":"), plainText(value))
 }
 
 func List(items []string) {
 	for _, item := range items {
-		fmt.Println(DimStyle.Render("  " + IconDot + " " + item))
+		fmt.Println(DimStyle.Render(plainText("  " + IconDot + " " + item)))
 	}
 }
 
@@ -211,7 +232,9 @@ func Table(headers []string, rows [][]string) {
 		}
 	}
 	sepLine = strings.Repeat("─", totalWidth)
-	fmt.Println(DimStyle.Render(sepLine))
+	if !accessible {
+		fmt.Println(DimStyle.Render(sepLine))
+	}
 
 	// Print rows
 	for _, row := range rows {
@@ -230,23 +253,33 @@ func Table(headers []string, rows [][]string) {
 
 // Prompt functions using huh
 
+// runField runs a prompt, as numbered plain-text questions in accessible mode
+func runField(field huh.Field) error {
+	return huh.NewForm(huh.NewGroup(field)).WithShowHelp(false).WithAccessible(accessible).Run()
+}
+
 func Input(prompt, placeholder string) (string, error) {
+	if err := needsUser(prompt, ""); err != nil {
+		return "", err
+	}
 	var value string
-	err := huh.NewInput().
+	err := runField(huh.NewInput().
 		Title(prompt).
 		Placeholder(placeholder).
-		Value(&value).
-		Run()
+		Value(&value))
 	return value, err
 }
 
 func InputWithDefault(prompt, defaultValue string) (string, error) {
+	if !Interactive() {
+		answered(prompt, defaultValue)
+		return defaultValue, nil
+	}
 	var value string
-	err := huh.NewInput().
+	err := runField(huh.NewInput().
 		Title(prompt).
 		Placeholder(defaultValue).
-		Value(&value).
-		Run()
+		Value(&value))
 	if err != nil {
 		return "", err
 	}
@@ -257,23 +290,33 @@ func InputWithDefault(prompt, defaultValue string) (string, error) {
 }
 
 func Password(prompt string) (string, error) {
+	if err := needsUser(prompt, ""); err != nil {
+		return "", err
+	}
 	var value string
-	err := huh.NewInput().
+	err := runField(huh.NewInput().
 		Title(prompt).
 		EchoMode(huh.EchoModePassword).
-		Value(&value).
-		Run()
+		Value(&value))
 	return value, err
 }
 
+// Confirm asks to go ahead with an action. With --yes it passes without
+// asking; in non-interactive mode it fails without it.
 func Confirm(prompt string) (bool, error) {
+	if AssumeYes() {
+		answered(prompt, "yes")
+		return true, nil
+	}
+	if err := needsUser(prompt, "pass --yes to confirm"); err != nil {
+		return false, err
+	}
 	var value bool
-	err := huh.NewConfirm().
+	err := runField(huh.NewConfirm().
 		Title(prompt).
 		Affirmative("Yes").
 		Negative("No").
-		Value(&value).
-		Run()
+		Value(&value))
 	return value, err
 }
 
@@ -282,36 +325,52 @@ func Select(prompt string, options []string) (string, error) {
 		return "", fmt.Errorf("no options provided")
 	}
 
+	if err := needsUser(prompt, ""); err != nil {
+		return "", err
+	}
+
 	var value string
 	opts := make([]huh.Option[string], len(options))
 	for i, opt := range options {
 		opts[i] = huh.NewOption(opt, opt)
 	}
 
-	err := huh.NewSelect[string]().
+	err := runField(huh.NewSelect[string]().
 		Title(prompt).
 		Options(opts...).
-		Value(&value).
-		Run()
+		Value(&value))
 	return value, err
 }
 
+// SelectDefault is Select for a choice that has a safe default, which is
+// taken in non-interactive mode
+func SelectDefault(prompt string, options []string, fallback string) (string, error) {
+	if !Interactive() {
+		answered(prompt, fallback)
+		return fallback, nil
+	}
+	return Select(prompt, options)
+}
+
 func SelectWithKeys(prompt string, options map[string]string) (string, error) {
 	if len(options) == 0 {
 		return "", fmt.Errorf("no options provided")
 	}
 
+	if err := needsUser(prompt, ""); err != nil {
+		return "", err
+	}
+
 	var value string
 	opts := make([]huh.Option[string], 0, len(options))
 	for key, display := range options {
 		opts = append(opts, huh.NewOption(display, key))
 	}
 
-	err := huh.NewSelect[string]().
+	err := runField(huh.NewSelect[string]().
 		Title(prompt).
 		Options(opts...).
-		Value(&value).
-		Run()
+		Value(&value))
 	return value, err
 }
 
@@ -320,22 +379,67 @@ func MultiSelect(prompt string, options []string) ([]string, error) {
 		return nil, fmt.Errorf("no options provided")
 	}
 
+	if err := needsUser(prompt, ""); err != nil {
+		return nil, err
+	}
+
 	var values []string
 	opts := make([]huh.Option[string], len(options))
 	for i, opt := range options {
 		opts[i] = huh.NewOption(opt, opt)
 	}
 
-	err := huh.NewMultiSelect[string]().
+	err := runField(huh.NewMultiSelect[string]().
 		Title(prompt).
 		Options(opts...).
-		Value(&values).
-		Run()
+		Value(&values))
 	return values, err
 }
 
 func Form(groups ...*huh.Group) error {
-	return huh.NewForm(groups...).Run()
+	if err := needsUser("Form", ""); err != nil {
+		return err
+	}
+	return huh.NewForm(groups...).WithAccessible(accessible).Run()
+}
+
+// Ask asks whether to take an optional step. In non-interactive mode it
+// returns fallback instead, and --yes doesn't answer it.
+func Ask(prompt string, fallback bool) (bool, error) {
+	if !Interactive() {
+		answer := "no"
+		if fallback {
+			answer = "yes"
+		}
+		answered(prompt, answer)
+		return fallback, nil
+	}
+	value := fallback
+	err := runField(huh.NewConfirm().
+		Title(prompt).
+		Affirmative("Yes").
+		Negative("No").
+		Value(&value))
+	return value, err
+}
+
+// TypeToConfirm asks to type word to go ahead with a destructive action.
+// Like Confirm, it passes with --yes and fails without it in non-interactive
+// mode.
+func TypeToConfirm(prompt, word string) (bool, error) {
+	if AssumeYes() {
+		answered(prompt, word)
+		return true, nil
+	}
+	if err := needsUser(prompt, "pass --yes to confirm"); err != nil {
+		return false, err
+	}
+	var value string
+	err := runField(huh.NewInput().
+		Title(prompt).
+		Description(fmt.Sprintf("Type '%s' to confirm", word)).
+		Value(&value))
+	return strings.TrimSpace(value) == word, err
 }
 
 func ConfirmAction(action, resource string) (bool, error) {
@@ -390,12 +494,19 @@ func NewStatus(message string) *Status {
 }
 
 func (s *Status) Update(message string) {
-	s.message = message
+	s.message = plainText(message)
+	if !LiveOutput() {
+		// Without a terminal \r doesn't rewrite the line, so log each one
+		fmt.Println(DimStyle.Render(s.message))
+		return
+	}
 	fmt.Printf("\r%s", DimStyle.Render(s.message))
 }
 
 func (s *Status) Done() {
-	fmt.Println()
+	if LiveOutput() {
+		fmt.Println()
+	}
 }
 
 // Helper functions
@@ -404,7 +515,7 @@ func NextSteps(steps []string) {
 	trace("NextSteps")
 	Dim("Next steps:")
 	for _, step := range steps {
-		fmt.Println(DimStyle.Render("  " + IconArrow + " " + step))
+		fmt.Println(DimStyle.Render(plainText("  " + IconArrow + " " + step)))
 	}
 }
 
@@ -418,5 +529,5 @@ func ErrorWithSuggestion(err error, suggestion string) {
 
 func StepProgress(current, total int, stepName string) {
 	progress := DimStyle.Render(fmt.Sprintf("[%d/%d]", current, total))
-	fmt.Printf("%s %s\n", progress, stepName)
+	fmt.Printf("%s %s\n", progress, plainText(stepName))
 }
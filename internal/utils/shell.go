@@ -0,0 +1,8 @@
+package utils
+
+import "strings"
+
+// ShellQuote quotes s as a single argument of a POSIX shell command
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
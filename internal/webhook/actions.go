@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/entro314-labs/cool-kit/internal/notify"
+)
+
+// Action is run for every accepted event
+type Action interface {
+	Name() string
+	Run(ctx context.Context, e *Event) error
+}
+
+// ShellHook runs a shell command with event details in COOLKIT_* environment
+// variables and the raw payload on stdin.
+type ShellHook struct {
+	Command string
+}
+
+// Name returns the action name
+func (h *ShellHook) Name() string { return "hook" }
+
+// Run executes the hook
+func (h *ShellHook) Run(ctx context.Context, e *Event) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	cmd := exec.CommandContext(ctx, shell, flag, h.Command)
+	cmd.Stdin = bytes.NewReader(e.Raw)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"COOLKIT_EVENT="+e.Name,
+		"COOLKIT_EVENT_TYPE="+string(e.Type),
+		"COOLKIT_MESSAGE="+e.Message,
+		"COOLKIT_APP="+e.Application,
+		"COOLKIT_APP_UUID="+e.ApplicationUUID,
+		"COOLKIT_DEPLOYMENT_UUID="+e.DeploymentUUID,
+		"COOLKIT_DEPLOYMENT_URL="+e.DeploymentURL,
+		"COOLKIT_PROJECT="+e.Project,
+		"COOLKIT_ENVIRONMENT="+e.Environment,
+	)
+	return cmd.Run()
+}
+
+// DesktopNotifier shows a desktop notification using the platform's notifier
+type DesktopNotifier struct{}
+
+// Name returns the action name
+func (DesktopNotifier) Name() string { return "notify" }
+
+// Run shows the notification
+func (DesktopNotifier) Run(ctx context.Context, e *Event) error {
+	return notify.Desktop(ctx, "Coolify", e.Summary())
+}
+
+// SlackNotifier posts events to a Slack incoming webhook
+type SlackNotifier struct {
+	WebhookURL string
+	client     *http.Client
+}
+
+// NewSlackNotifier returns a notifier posting to webhookURL
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		WebhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the action name
+func (s *SlackNotifier) Name() string { return "slack" }
+
+// Run posts the event
+func (s *SlackNotifier) Run(ctx context.Context, e *Event) error {
+	icon := ":information_source:"
+	switch e.Type {
+	case EventFinished:
+		icon = ":white_check_mark:"
+	case EventFailed:
+		icon = ":x:"
+	case EventStarted:
+		icon = ":rocket:"
+	}
+
+	text := icon + " " + e.Summary()
+	if e.Message != "" {
+		text += "\n" + e.Message
+	}
+	if e.DeploymentURL != "" {
+		text += fmt.Sprintf("\n<%s|View deployment>", e.DeploymentURL)
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack returned status %d", resp.StatusCode)
+	}
+	return nil
+}
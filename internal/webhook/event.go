@@ -0,0 +1,98 @@
+// Package webhook receives Coolify webhook notifications and runs local actions for them.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EventType is the normalised kind of a deployment notification
+type EventType string
+
+const (
+	EventStarted  EventType = "started"
+	EventFinished EventType = "finished"
+	EventFailed   EventType = "failed"
+	EventOther    EventType = "other"
+)
+
+// EventTypes lists the event types that can be subscribed to
+var EventTypes = []EventType{EventStarted, EventFinished, EventFailed, EventOther}
+
+// Event is a Coolify webhook notification
+type Event struct {
+	Type            EventType `json:"type"`
+	Name            string    `json:"event"`
+	Message         string    `json:"message,omitempty"`
+	Application     string    `json:"application_name,omitempty"`
+	ApplicationUUID string    `json:"application_uuid,omitempty"`
+	DeploymentUUID  string    `json:"deployment_uuid,omitempty"`
+	DeploymentURL   string    `json:"deployment_url,omitempty"`
+	Project         string    `json:"project,omitempty"`
+	Environment     string    `json:"environment,omitempty"`
+	FQDN            string    `json:"fqdn,omitempty"`
+
+	// Raw is the payload as received
+	Raw json.RawMessage `json:"-"`
+}
+
+// ParseEvent decodes a webhook payload
+func ParseEvent(body []byte) (*Event, error) {
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if e.Name == "" {
+		return nil, fmt.Errorf("invalid webhook payload: missing event")
+	}
+
+	e.Type = classify(e.Name)
+	e.Raw = append(json.RawMessage(nil), body...)
+	return &e, nil
+}
+
+// classify maps Coolify event names (deployment_success, deployment_failed, ...)
+// to an EventType
+func classify(name string) EventType {
+	name = strings.ToLower(name)
+	if !strings.HasPrefix(name, "deployment") {
+		return EventOther
+	}
+
+	switch {
+	case strings.Contains(name, "fail"), strings.Contains(name, "error"):
+		return EventFailed
+	case strings.Contains(name, "success"), strings.Contains(name, "finish"):
+		return EventFinished
+	case strings.Contains(name, "start"), strings.Contains(name, "queued"):
+		return EventStarted
+	default:
+		return EventOther
+	}
+}
+
+// Summary returns a one-line description of the event
+func (e *Event) Summary() string {
+	subject := e.Application
+	if subject == "" {
+		subject = e.ApplicationUUID
+	}
+
+	var verb string
+	switch e.Type {
+	case EventStarted:
+		verb = "deployment started"
+	case EventFinished:
+		verb = "deployed successfully"
+	case EventFailed:
+		verb = "deployment failed"
+	default:
+		verb = e.Name
+	}
+
+	if subject == "" {
+		return verb
+	}
+	return subject + ": " + verb
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxPayloadSize bounds the webhook body read into memory
+const maxPayloadSize = 1 << 20
+
+// actionTimeout bounds how long a single action may run
+const actionTimeout = 2 * time.Minute
+
+// Result reports the outcome of running one action for an event
+type Result struct {
+	Event  *Event
+	Action string
+	Err    error
+}
+
+// Handler accepts Coolify webhook POSTs and runs actions for subscribed events
+type Handler struct {
+	// Secret must be presented as a bearer token or "token" query parameter.
+	// Empty disables authentication.
+	Secret string
+
+	// Events limits which event types trigger actions. Empty means all.
+	Events []EventType
+
+	Actions []Action
+
+	// OnEvent is called when an event is received, before actions run
+	OnEvent func(e *Event, accepted bool)
+
+	// OnResult is called after each action completes
+	OnResult func(r Result)
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	event, err := ParseEvent(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	accepted := h.subscribed(event.Type)
+	if h.OnEvent != nil {
+		h.OnEvent(event, accepted)
+	}
+
+	// Acknowledge immediately: Coolify does not wait for slow hooks
+	w.WriteHeader(http.StatusAccepted)
+
+	if accepted {
+		go h.run(event)
+	}
+}
+
+// run executes all actions for an event
+func (h *Handler) run(e *Event) {
+	for _, action := range h.Actions {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		err := action.Run(ctx, e)
+		cancel()
+
+		if h.OnResult != nil {
+			h.OnResult(Result{Event: e, Action: action.Name(), Err: err})
+		}
+	}
+}
+
+func (h *Handler) authorized(r *http.Request) bool {
+	if h.Secret == "" {
+		return true
+	}
+
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.Secret)) == 1
+}
+
+func (h *Handler) subscribed(t EventType) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, e := range h.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
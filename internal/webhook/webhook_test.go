@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Payloads as Coolify's webhook notification channel sends them
+const (
+	successPayload = `{"success":true,"message":"New version successfully deployed to https://shop.example.com","event":"deployment_success","application_name":"shop","application_uuid":"r4nd0mapp","deployment_uuid":"d3pl0y","deployment_url":"https://coolify.example.com/project/p1/environment/e1/application/r4nd0mapp/deployment/d3pl0y","project":"Shop","environment":"production","fqdn":"https://shop.example.com"}`
+	failedPayload  = `{"success":false,"message":"Deployment failed: shop","event":"deployment_failed","application_name":"shop","application_uuid":"r4nd0mapp","deployment_uuid":"d3pl0y","deployment_url":"https://coolify.example.com/project/p1/environment/e1/application/r4nd0mapp/deployment/d3pl0y","project":"Shop","environment":"production"}`
+	backupPayload  = `{"success":false,"message":"Database backup for db failed","event":"backup_failed","database_name":"db","frequency":"0 0 * * *"}`
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    EventType
+		summary string
+		wantErr bool
+	}{
+		{"success", successPayload, EventFinished, "shop: deployed successfully", false},
+		{"failed", failedPayload, EventFailed, "shop: deployment failed", false},
+		{"backup", backupPayload, EventOther, "backup_failed", false},
+		{"uuid only", `{"event":"deployment_started","application_uuid":"r4nd0mapp"}`, EventStarted, "r4nd0mapp: deployment started", false},
+		{"no event", `{"success":true,"message":"hi"}`, "", "", true},
+		{"not json", `event=deployment_success`, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := ParseEvent([]byte(tt.body))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseEvent = %+v, want an error", e)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Type != tt.want || e.Summary() != tt.summary {
+				t.Errorf("got %s %q, want %s %q", e.Type, e.Summary(), tt.want, tt.summary)
+			}
+			if string(e.Raw) != tt.body {
+				t.Errorf("Raw = %s", e.Raw)
+			}
+		})
+	}
+
+	e, _ := ParseEvent([]byte(successPayload))
+	if e.DeploymentUUID != "d3pl0y" || e.Project != "Shop" || e.Environment != "production" || e.FQDN != "https://shop.example.com" {
+		t.Errorf("fields not decoded: %+v", e)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		want EventType
+	}{
+		{"deployment_success", EventFinished},
+		{"deployment_finished", EventFinished},
+		{"deployment_failed", EventFailed},
+		{"Deployment_Error", EventFailed},
+		{"deployment_started", EventStarted},
+		{"deployment_queued", EventStarted},
+		{"deployment_cancelled", EventOther},
+		{"backup_failed", EventOther},
+		{"status_changed", EventOther},
+	}
+
+	for _, tt := range tests {
+		if got := classify(tt.name); got != tt.want {
+			t.Errorf("classify(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorized(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		url    string
+		header string
+		want   bool
+	}{
+		{"no secret", "", "/", "", true},
+		{"bearer", "s3cret", "/", "Bearer s3cret", true},
+		{"query", "s3cret", "/?token=s3cret", "", true},
+		{"bearer wins over query", "s3cret", "/?token=s3cret", "Bearer wrong", false},
+		{"wrong bearer", "s3cret", "/", "Bearer wrong", false},
+		{"wrong query", "s3cret", "/?token=wrong", "", false},
+		{"not bearer", "s3cret", "/", "Basic s3cret", false},
+		{"missing", "s3cret", "/", "", false},
+		{"prefix of secret", "s3cret", "/?token=s3c", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tt.url, nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			h := &Handler{Secret: tt.secret}
+			if got := h.authorized(r); got != tt.want {
+				t.Errorf("authorized = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscribed(t *testing.T) {
+	all := &Handler{}
+	some := &Handler{Events: []EventType{EventFailed, EventFinished}}
+
+	for _, tt := range []struct {
+		h    *Handler
+		t    EventType
+		want bool
+	}{
+		{all, EventStarted, true},
+		{all, EventOther, true},
+		{some, EventFailed, true},
+		{some, EventFinished, true},
+		{some, EventStarted, false},
+		{some, EventOther, false},
+	} {
+		if got := tt.h.subscribed(tt.t); got != tt.want {
+			t.Errorf("subscribed(%s) with %v = %v, want %v", tt.t, tt.h.Events, got, tt.want)
+		}
+	}
+}
+
+// recordAction reports the events it runs for
+type recordAction struct {
+	events chan *Event
+}
+
+func (a *recordAction) Name() string { return "record" }
+
+func (a *recordAction) Run(ctx context.Context, e *Event) error {
+	a.events <- e
+	return nil
+}
+
+// readRecorder records whether the body was read
+type readRecorder struct {
+	io.Reader
+	read bool
+}
+
+func (r *readRecorder) Read(p []byte) (int, error) {
+	r.read = true
+	return r.Reader.Read(p)
+}
+
+func TestServeHTTP(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		status   int
+		accepted bool
+	}{
+		{"success", http.MethodPost, "/?token=s3cret", successPayload, http.StatusAccepted, true},
+		{"unsubscribed", http.MethodPost, "/?token=s3cret", backupPayload, http.StatusAccepted, false},
+		{"get", http.MethodGet, "/?token=s3cret", "", http.StatusMethodNotAllowed, false},
+		{"unauthorized", http.MethodPost, "/?token=wrong", successPayload, http.StatusUnauthorized, false},
+		{"bad payload", http.MethodPost, "/?token=s3cret", `{"message":"hi"}`, http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := &recordAction{events: make(chan *Event, 1)}
+			var received *Event
+			var accepted bool
+			results := make(chan Result, 1)
+			h := &Handler{
+				Secret:   "s3cret",
+				Events:   []EventType{EventFinished, EventFailed},
+				Actions:  []Action{action},
+				OnEvent:  func(e *Event, ok bool) { received, accepted = e, ok },
+				OnResult: func(r Result) { results <- r },
+			}
+
+			body := &readRecorder{Reader: strings.NewReader(tt.body)}
+			r := httptest.NewRequest(tt.method, tt.url, body)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			switch tt.status {
+			case http.StatusMethodNotAllowed:
+				if allow := w.Header().Get("Allow"); allow != http.MethodPost {
+					t.Errorf("Allow = %q", allow)
+				}
+			case http.StatusUnauthorized:
+				if body.read {
+					t.Error("body read before authenticating")
+				}
+			case http.StatusAccepted:
+				if received == nil || accepted != tt.accepted {
+					t.Errorf("OnEvent got %+v, accepted %v", received, accepted)
+				}
+			}
+
+			if !tt.accepted {
+				select {
+				case e := <-action.events:
+					t.Errorf("action ran for %s", e.Name)
+				case <-time.After(50 * time.Millisecond):
+				}
+				return
+			}
+			select {
+			case e := <-action.events:
+				if e.Type != EventFinished {
+					t.Errorf("action got %s", e.Type)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("action didn't run")
+			}
+			if r := <-results; r.Action != "record" || r.Err != nil {
+				t.Errorf("OnResult = %+v", r)
+			}
+		})
+	}
+}